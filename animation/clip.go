@@ -0,0 +1,33 @@
+package animation
+
+// Clip is a named group of tracks played together, eg. the walk cycle of a
+// character.
+type Clip struct {
+	Name   string
+	Tracks []*Track
+	// Duration is the length of the clip, in seconds. It defaults to the
+	// time of the last keyframe of the tracks.
+	Duration float32
+}
+
+// NewClip creates a new clip.
+func NewClip(name string, tracks ...*Track) *Clip {
+	c := &Clip{
+		Name:   name,
+		Tracks: tracks,
+	}
+	for _, t := range tracks {
+		if d := t.Duration(); d > c.Duration {
+			c.Duration = d
+		}
+	}
+	return c
+}
+
+// Apply sets the properties animated by the clip to their value at time, in
+// seconds.
+func (c *Clip) Apply(time float32) {
+	for _, t := range c.Tracks {
+		t.Apply(time)
+	}
+}
@@ -0,0 +1,135 @@
+package animation
+
+import (
+	"sort"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// GradientStop is a color of a Gradient, at a position between 0 and 1.
+type GradientStop struct {
+	Position float32
+	Color    dax.Color
+}
+
+// Gradient is a sequence of colors, blended linearly from one stop to the
+// next, eg. the colors of the sky during a day.
+type Gradient struct {
+	stops []GradientStop
+}
+
+// NewGradient creates a gradient going through stops. The stops don't need to
+// be sorted.
+func NewGradient(stops ...GradientStop) *Gradient {
+	g := &Gradient{
+		stops: append([]GradientStop(nil), stops...),
+	}
+	sort.SliceStable(g.stops, func(i, j int) bool {
+		return g.stops[i].Position < g.stops[j].Position
+	})
+	return g
+}
+
+// At returns the color of the gradient at position t. Positions before the
+// first stop have its color, and positions after the last stop have its
+// color. Empty gradients are transparent black.
+func (g *Gradient) At(t float32) dax.Color {
+	n := len(g.stops)
+	if n == 0 {
+		return dax.Color{}
+	}
+	if t <= g.stops[0].Position {
+		return g.stops[0].Color
+	}
+	if t >= g.stops[n-1].Position {
+		return g.stops[n-1].Color
+	}
+
+	i := sort.Search(n, func(i int) bool {
+		return g.stops[i].Position > t
+	})
+	s0, s1 := &g.stops[i-1], &g.stops[i]
+	amount := math.Clamp((t-s0.Position)/(s1.Position-s0.Position), 0, 1)
+	return s0.Color.Lerp(&s1.Color, amount)
+}
+
+// ColorTween is a component changing a color over some time, going straight
+// from a color to another or sweeping through a Gradient. It's driven by a
+// Tween and advanced the same way.
+type ColorTween struct {
+	tween    *Tween
+	set      func(c *dax.Color)
+	gradient *Gradient
+}
+
+var _ dax.Updater = &ColorTween{}
+
+// NewColorTween creates a new ColorTween calling set with the color while
+// playing. Colors take duration seconds to change, with EaseInOut. The tween
+// is idle until Start or StartGradient is called.
+func NewColorTween(duration float32, set func(c *dax.Color)) *ColorTween {
+	t := &ColorTween{
+		set: set,
+	}
+	t.tween = NewTween(duration, func(v float32) {
+		c := t.gradient.At(v)
+		t.set(&c)
+	})
+	return t
+}
+
+// NewBackgroundTween creates a ColorTween changing the background color of a
+// scene, eg. to fade to a menu color or for day/night cycles:
+//
+//	fade := animation.NewBackgroundTween(scene, 2)
+//	fade.Start(scene.BackgroundColor(), &dax.Color{R: 0, G: 0, B: 0, A: 1})
+//	node.AddComponent(fade)
+func NewBackgroundTween(s dax.Scener, duration float32) *ColorTween {
+	return NewColorTween(duration, func(c *dax.Color) {
+		*s.BackgroundColor() = *c
+	})
+}
+
+// SetEasing sets how the color goes from its start to its end.
+func (t *ColorTween) SetEasing(easing Easing) {
+	t.tween.SetEasing(easing)
+}
+
+// SetDuration sets the time, in seconds, the color takes to change. It's used
+// from the next start.
+func (t *ColorTween) SetDuration(duration float32) {
+	t.tween.SetDuration(duration)
+}
+
+// Start starts changing the color from from to to, interrupting the current
+// change if any. The color is set to from straight away.
+func (t *ColorTween) Start(from, to *dax.Color) {
+	t.StartGradient(NewGradient(
+		GradientStop{Position: 0, Color: *from},
+		GradientStop{Position: 1, Color: *to},
+	))
+}
+
+// StartGradient starts sweeping the color through the stops of g, from
+// position 0 to 1, interrupting the current change if any.
+func (t *ColorTween) StartGradient(g *Gradient) {
+	t.gradient = g
+	t.tween.Start(0, 1)
+}
+
+// Stop stops changing the color, leaving it where it is.
+func (t *ColorTween) Stop() {
+	t.tween.Stop()
+}
+
+// IsPlaying returns true if the color is changing.
+func (t *ColorTween) IsPlaying() bool {
+	return t.tween.IsPlaying()
+}
+
+// Update implements dax.Updater. It advances the tween by dt seconds and sets
+// the color.
+func (t *ColorTween) Update(dt float64) {
+	t.tween.Update(dt)
+}
@@ -0,0 +1,48 @@
+package animation
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradient(t *testing.T) {
+	g := NewGradient(
+		GradientStop{Position: 1, Color: dax.Color{R: 0, G: 0, B: 1, A: 1}},
+		GradientStop{Position: 0, Color: dax.Color{R: 1, G: 0, B: 0, A: 1}},
+		GradientStop{Position: .5, Color: dax.Color{R: 0, G: 1, B: 0, A: 1}},
+	)
+
+	assert.Equal(t, dax.Color{R: 1, G: 0, B: 0, A: 1}, g.At(-1))
+	assert.Equal(t, dax.Color{R: .5, G: .5, B: 0, A: 1}, g.At(.25))
+	assert.Equal(t, dax.Color{R: 0, G: 1, B: 0, A: 1}, g.At(.5))
+	assert.Equal(t, dax.Color{R: 0, G: .5, B: .5, A: 1}, g.At(.75))
+	assert.Equal(t, dax.Color{R: 0, G: 0, B: 1, A: 1}, g.At(2))
+
+	assert.Equal(t, dax.Color{}, NewGradient().At(.5))
+}
+
+func TestColorTween(t *testing.T) {
+	var color dax.Color
+	tween := NewColorTween(2, func(c *dax.Color) { color = *c })
+	tween.SetEasing(EaseLinear)
+
+	tween.Start(&dax.Color{R: 0, G: 0, B: 0, A: 1}, &dax.Color{R: 1, G: 1, B: 1, A: 1})
+	assert.Equal(t, dax.Color{R: 0, G: 0, B: 0, A: 1}, color)
+	tween.Update(1)
+	assert.Equal(t, dax.Color{R: .5, G: .5, B: .5, A: 1}, color)
+	tween.Update(1)
+	assert.Equal(t, dax.Color{R: 1, G: 1, B: 1, A: 1}, color)
+	assert.False(t, tween.IsPlaying())
+
+	// Sweeping a gradient goes through all its stops.
+	tween.StartGradient(NewGradient(
+		GradientStop{Position: 0, Color: dax.Color{R: 0, G: 0, B: 0, A: 1}},
+		GradientStop{Position: .5, Color: dax.Color{R: 1, G: 0, B: 0, A: 1}},
+		GradientStop{Position: 1, Color: dax.Color{R: 0, G: 0, B: 0, A: 1}},
+	))
+	tween.Update(1)
+	assert.Equal(t, dax.Color{R: 1, G: 0, B: 0, A: 1}, color)
+	assert.True(t, tween.IsPlaying())
+}
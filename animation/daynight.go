@@ -0,0 +1,183 @@
+package animation
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// DayNight is a lighting rig animating a day: the sun goes around the scene,
+// changing color and intensity, while the ambient light and the sky color
+// follow. It's a component of the node holding the sun light, returned by Sun:
+//
+//	rig := animation.NewDayNight(120)
+//	rig.OnSky(func(c *dax.Color) { *scene.BackgroundColor() = *c })
+//	rig.OnAmbient(phong.SetAmbient)
+//	sg.AddChild(rig.Sun())
+//
+// The time of day goes from 0 to 1: the sun rises at .25 along the +X axis, is
+// at the zenith at noon, .5, and sets at .75 along the -X axis. The colors of
+// the sun, the ambient light and the sky are gradients over the time of day.
+type DayNight struct {
+	sun       *dax.Node
+	light     *dax.Light
+	dayLength float32
+	time      float32
+	intensity float32
+
+	sunColors     *Gradient
+	ambientColors *Gradient
+	skyColors     *Gradient
+	onAmbient     func(c *dax.Color)
+	onSky         func(c *dax.Color)
+}
+
+var _ dax.Updater = &DayNight{}
+
+// NewDayNight creates a day/night rig where a day lasts dayLength seconds. The
+// rig starts at noon, with a sun intensity of 1 and default colors going from
+// a dark blue night to a warm sunrise and a clear day.
+func NewDayNight(dayLength float32) *DayNight {
+	d := &DayNight{
+		light:     dax.NewDirectionalLight(&dax.Color{R: 1, G: 1, B: 1, A: 1}, 1),
+		dayLength: dayLength,
+		time:      .5,
+		intensity: 1,
+		sunColors: NewGradient(
+			GradientStop{Position: .2, Color: dax.Color{R: 1, G: .3, B: .1, A: 1}},
+			GradientStop{Position: .3, Color: dax.Color{R: 1, G: .8, B: .6, A: 1}},
+			GradientStop{Position: .5, Color: dax.Color{R: 1, G: .98, B: .95, A: 1}},
+			GradientStop{Position: .7, Color: dax.Color{R: 1, G: .8, B: .6, A: 1}},
+			GradientStop{Position: .8, Color: dax.Color{R: 1, G: .3, B: .1, A: 1}},
+		),
+		ambientColors: NewGradient(
+			GradientStop{Position: .2, Color: dax.Color{R: .02, G: .02, B: .05, A: 1}},
+			GradientStop{Position: .3, Color: dax.Color{R: .15, G: .14, B: .15, A: 1}},
+			GradientStop{Position: .5, Color: dax.Color{R: .25, G: .27, B: .3, A: 1}},
+			GradientStop{Position: .7, Color: dax.Color{R: .15, G: .14, B: .15, A: 1}},
+			GradientStop{Position: .8, Color: dax.Color{R: .02, G: .02, B: .05, A: 1}},
+		),
+		skyColors: NewGradient(
+			GradientStop{Position: .2, Color: dax.Color{R: .01, G: .01, B: .03, A: 1}},
+			GradientStop{Position: .25, Color: dax.Color{R: .5, G: .3, B: .3, A: 1}},
+			GradientStop{Position: .3, Color: dax.Color{R: .45, G: .65, B: .9, A: 1}},
+			GradientStop{Position: .7, Color: dax.Color{R: .45, G: .65, B: .9, A: 1}},
+			GradientStop{Position: .75, Color: dax.Color{R: .5, G: .3, B: .3, A: 1}},
+			GradientStop{Position: .8, Color: dax.Color{R: .01, G: .01, B: .03, A: 1}},
+		),
+	}
+
+	d.sun = dax.NewNode()
+	d.sun.AddComponent(d.light)
+	d.sun.AddComponent(d)
+	d.apply()
+
+	return d
+}
+
+// Sun returns the node holding the sun, a directional light. Add it to a
+// scene graph to light it and advance the rig with SceneGraph.Update.
+func (d *DayNight) Sun() *dax.Node {
+	return d.sun
+}
+
+// Light returns the sun light.
+func (d *DayNight) Light() *dax.Light {
+	return d.light
+}
+
+// SetDayLength sets the duration of a day, in seconds. 0 stops the time.
+func (d *DayNight) SetDayLength(seconds float32) {
+	d.dayLength = seconds
+}
+
+// DayLength returns the duration of a day, in seconds.
+func (d *DayNight) DayLength() float32 {
+	return d.dayLength
+}
+
+// SetTimeOfDay sets the time of day, from 0 to 1, wrapping around outside of
+// this range.
+func (d *DayNight) SetTimeOfDay(t float32) {
+	d.time = t - math.Floor(t)
+	d.apply()
+}
+
+// TimeOfDay returns the time of day, from 0 to 1.
+func (d *DayNight) TimeOfDay() float32 {
+	return d.time
+}
+
+// SetIntensity sets the intensity of the sun at the zenith.
+func (d *DayNight) SetIntensity(intensity float32) {
+	d.intensity = intensity
+	d.apply()
+}
+
+// SetSunColors sets the color of the sun over the time of day.
+func (d *DayNight) SetSunColors(g *Gradient) {
+	d.sunColors = g
+	d.apply()
+}
+
+// SetAmbientColors sets the ambient light over the time of day.
+func (d *DayNight) SetAmbientColors(g *Gradient) {
+	d.ambientColors = g
+	d.apply()
+}
+
+// SetSkyColors sets the color of the sky over the time of day.
+func (d *DayNight) SetSkyColors(g *Gradient) {
+	d.skyColors = g
+	d.apply()
+}
+
+// OnAmbient registers f to be called with the ambient light when it changes,
+// eg. to set the ambient term of the lit materials.
+func (d *DayNight) OnAmbient(f func(c *dax.Color)) {
+	d.onAmbient = f
+	d.apply()
+}
+
+// OnSky registers f to be called with the sky color when it changes, eg. to
+// set the background color of the scene.
+func (d *DayNight) OnSky(f func(c *dax.Color)) {
+	d.onSky = f
+	d.apply()
+}
+
+// sunDirection returns the direction from the scene to the sun at the time of
+// day t.
+func sunDirection(t float32) math.Vec3 {
+	sin, cos := math.Sincos((t - .25) * 2 * math.Pi)
+	return math.Vec3{cos, sin, 0}
+}
+
+// apply sets the sun, ambient light and sky for the current time of day.
+func (d *DayNight) apply() {
+	toSun := sunDirection(d.time)
+	direction := math.Vec3{-toSun[0], -toSun[1], -toSun[2]}
+	q := math.QuatBetweenVectors(&math.Vec3{0, 0, -1}, &direction)
+	d.sun.SetRotation(&q)
+
+	// The sun light decreases with its angle to the zenith and is off at
+	// night.
+	d.light.Color = d.sunColors.At(d.time)
+	d.light.Intensity = d.intensity * math.Max(toSun[1], 0)
+
+	if d.onAmbient != nil {
+		c := d.ambientColors.At(d.time)
+		d.onAmbient(&c)
+	}
+	if d.onSky != nil {
+		c := d.skyColors.At(d.time)
+		d.onSky(&c)
+	}
+}
+
+// Update implements dax.Updater. It advances the time of day by dt seconds.
+func (d *DayNight) Update(dt float64) {
+	if d.dayLength <= 0 {
+		return
+	}
+	d.SetTimeOfDay(d.time + float32(dt)/d.dayLength)
+}
@@ -0,0 +1,38 @@
+package animation
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDayNight(t *testing.T) {
+	var sky, ambient dax.Color
+	rig := NewDayNight(100)
+	rig.OnSky(func(c *dax.Color) { sky = *c })
+	rig.OnAmbient(func(c *dax.Color) { ambient = *c })
+
+	// At noon, the sun shines straight down.
+	assert.Equal(t, float32(.5), rig.TimeOfDay())
+	direction := rig.Sun().GetRotation().Rotate(&math.Vec3{0, 0, -1})
+	assertVec3(t, &math.Vec3{0, -1, 0}, &direction)
+	assert.InDelta(t, 1, rig.Light().Intensity, 1e-5)
+	assert.Equal(t, dax.Color{R: .45, G: .65, B: .9, A: 1}, sky)
+	assert.Equal(t, dax.Color{R: .25, G: .27, B: .3, A: 1}, ambient)
+
+	// A quarter of a day later, the sun sets.
+	rig.Update(25)
+	assert.InDelta(t, .75, rig.TimeOfDay(), 1e-5)
+	direction = rig.Sun().GetRotation().Rotate(&math.Vec3{0, 0, -1})
+	assertVec3(t, &math.Vec3{1, 0, 0}, &direction)
+
+	// The time of day wraps around, the sun is off at night.
+	rig.Update(50)
+	assert.InDelta(t, .25, rig.TimeOfDay(), 1e-5)
+	rig.SetTimeOfDay(1.1)
+	assert.InDelta(t, .1, rig.TimeOfDay(), 1e-5)
+	assert.Equal(t, float32(0), rig.Light().Intensity)
+	assert.Equal(t, dax.Color{R: .01, G: .01, B: .03, A: 1}, sky)
+}
@@ -0,0 +1,116 @@
+package animation
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Player is a component playing a Clip. It's advanced by SceneGraph.Update
+// when added to a node of the scene graph, or by calling Update directly.
+type Player struct {
+	clip    *Clip
+	time    float32
+	speed   float32
+	loop    bool
+	playing bool
+}
+
+var _ dax.Updater = &Player{}
+
+// NewPlayer creates a new Player, playing at normal speed and without
+// looping.
+func NewPlayer() *Player {
+	return &Player{
+		speed: 1,
+	}
+}
+
+// Play starts playing clip from its beginning, or from its end when the speed
+// is negative.
+func (p *Player) Play(clip *Clip) {
+	p.clip = clip
+	p.time = 0
+	if p.speed < 0 {
+		p.time = clip.Duration
+	}
+	p.playing = true
+	clip.Apply(p.time)
+}
+
+// Pause stops advancing the clip. Resume starts again from the same time.
+func (p *Player) Pause() {
+	p.playing = false
+}
+
+// Resume resumes playing a paused clip.
+func (p *Player) Resume() {
+	p.playing = p.clip != nil
+}
+
+// Seek jumps to time, in seconds, and applies the clip at that time.
+func (p *Player) Seek(time float32) {
+	if p.clip == nil {
+		return
+	}
+	p.time = p.wrap(time)
+	p.clip.Apply(p.time)
+}
+
+// Clip returns the clip being played, nil if Play hasn't been called.
+func (p *Player) Clip() *Clip {
+	return p.clip
+}
+
+// Time returns the current time in the clip, in seconds.
+func (p *Player) Time() float32 {
+	return p.time
+}
+
+// IsPlaying returns true if the clip is advancing. Clips that don't loop stop
+// playing when reaching their end.
+func (p *Player) IsPlaying() bool {
+	return p.playing
+}
+
+// SetSpeed sets the playback speed: 1 is normal speed, 2 twice as fast and
+// negative speeds play the clip backwards.
+func (p *Player) SetSpeed(speed float32) {
+	p.speed = speed
+}
+
+// SetLoop makes the clip start again once it reaches its end.
+func (p *Player) SetLoop(loop bool) {
+	p.loop = loop
+}
+
+// wrap brings time back in the clip, looping or clamping it.
+func (p *Player) wrap(time float32) float32 {
+	duration := p.clip.Duration
+	if duration <= 0 {
+		return 0
+	}
+	if p.loop {
+		time = math.Mod(time, duration)
+		if time < 0 {
+			time += duration
+		}
+		return time
+	}
+	return math.Clamp(time, 0, duration)
+}
+
+// Update implements dax.Updater. It advances the clip by dt seconds, scaled by
+// the speed, and applies it.
+func (p *Player) Update(dt float64) {
+	if !p.playing {
+		return
+	}
+
+	time := p.time + float32(dt)*p.speed
+	p.time = p.wrap(time)
+	if !p.loop && p.time != time {
+		// Reached an end of the clip.
+		p.playing = false
+	}
+	p.clip.Apply(p.time)
+}
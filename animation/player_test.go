@@ -0,0 +1,77 @@
+package animation
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClip(value *float32) *Clip {
+	track := NewFloatTrack(func(v float32) { *value = v },
+		[]float32{0, 2}, []float32{0, 20})
+	return NewClip("test", track)
+}
+
+func TestPlayer(t *testing.T) {
+	var value float32
+	clip := newTestClip(&value)
+	assert.Equal(t, float32(2), clip.Duration)
+
+	p := NewPlayer()
+	p.Play(clip)
+	assert.True(t, p.IsPlaying())
+
+	p.Update(.5)
+	assert.InDelta(t, 5, value, 1e-5)
+
+	p.Pause()
+	p.Update(.5)
+	assert.InDelta(t, 5, value, 1e-5)
+	p.Resume()
+
+	p.SetSpeed(2)
+	p.Update(.5)
+	assert.InDelta(t, 15, value, 1e-5)
+
+	// Stops at the end of the clip.
+	p.Update(1)
+	assert.InDelta(t, 20, value, 1e-5)
+	assert.Equal(t, float32(2), p.Time())
+	assert.False(t, p.IsPlaying())
+
+	p.Seek(1)
+	assert.InDelta(t, 10, value, 1e-5)
+}
+
+func TestPlayerLoop(t *testing.T) {
+	var value float32
+	p := NewPlayer()
+	p.SetLoop(true)
+	p.Play(newTestClip(&value))
+
+	p.Update(2.5)
+	assert.InDelta(t, .5, p.Time(), 1e-5)
+	assert.InDelta(t, 5, value, 1e-5)
+	assert.True(t, p.IsPlaying())
+
+	// Backwards.
+	p.SetSpeed(-1)
+	p.Update(1)
+	assert.InDelta(t, 1.5, p.Time(), 1e-5)
+	assert.InDelta(t, 15, value, 1e-5)
+}
+
+func TestPlayerSceneGraph(t *testing.T) {
+	var value float32
+	p := NewPlayer()
+	p.Play(newTestClip(&value))
+
+	node := dax.NewNode()
+	node.AddComponent(p)
+	sg := dax.NewSceneGraph()
+	sg.AddChild(node)
+
+	sg.Update(1)
+	assert.InDelta(t, 10, value, 1e-5)
+}
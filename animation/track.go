@@ -0,0 +1,242 @@
+// Package animation animates nodes with keyframes.
+//
+// A Track interpolates the keyframes of a single property, eg. the position
+// of a node. Tracks are grouped in a Clip and a Player component plays clips:
+//
+//	track := animation.NewPositionTrack(node, []float32{0, 1, 2}, []math.Vec3{
+//		{0, 0, 0}, {0, 10, 0}, {0, 0, 0},
+//	})
+//	player := animation.NewPlayer()
+//	player.SetLoop(true)
+//	player.Play(animation.NewClip("bounce", track))
+//	node.AddComponent(player)
+//
+// Players are updated with the other node components by SceneGraph.Update,
+// usually called from the Update method of a Scene.
+package animation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Interpolation is how values are computed between two keyframes.
+type Interpolation int
+
+const (
+	// InterpolationLinear interpolates linearly between keyframes.
+	// Rotations are spherically interpolated.
+	InterpolationLinear Interpolation = iota
+	// InterpolationStep keeps the value of a keyframe until the next one.
+	InterpolationStep
+	// InterpolationCubic goes smoothly through the keyframes with a
+	// Catmull-Rom spline.
+	InterpolationCubic
+)
+
+// Target is the property animated by a Track.
+type Target int
+
+const (
+	// TargetPosition animates the position of a node.
+	TargetPosition Target = iota
+	// TargetRotation animates the rotation of a node.
+	TargetRotation
+	// TargetScale animates the scale of a node.
+	TargetScale
+	// TargetFloat animates a float value, given to a function.
+	TargetFloat
+)
+
+// numComponents returns the number of floats in a keyframe value.
+func (t Target) numComponents() int {
+	switch t {
+	case TargetPosition, TargetScale:
+		return 3
+	case TargetRotation:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// Track animates one property with keyframes.
+type Track struct {
+	Target        Target
+	Interpolation Interpolation
+
+	node *dax.Node
+	set  func(v float32)
+
+	// times of the keyframes, in seconds and increasing.
+	times []float32
+	// values of the keyframes, numComponents floats per keyframe.
+	// Rotations are stored as x, y, z, w.
+	values []float32
+	// sampled is the value at the time given to Apply.
+	sampled [4]float32
+}
+
+func newTrack(target Target, times []float32, numKeyframes int) *Track {
+	if len(times) != numKeyframes {
+		panic(fmt.Sprintf("animation: %d times for %d values", len(times), numKeyframes))
+	}
+	if !sort.SliceIsSorted(times, func(i, j int) bool { return times[i] < times[j] }) {
+		panic("animation: keyframe times aren't increasing")
+	}
+
+	return &Track{
+		Target: target,
+		times:  append([]float32(nil), times...),
+		values: make([]float32, 0, numKeyframes*target.numComponents()),
+	}
+}
+
+// NewPositionTrack creates a track animating the position of node.
+func NewPositionTrack(node *dax.Node, times []float32, positions []math.Vec3) *Track {
+	t := newTrack(TargetPosition, times, len(positions))
+	t.node = node
+	for i := range positions {
+		t.values = append(t.values, positions[i][:]...)
+	}
+	return t
+}
+
+// NewRotationTrack creates a track animating the rotation of node.
+func NewRotationTrack(node *dax.Node, times []float32, rotations []math.Quaternion) *Track {
+	t := newTrack(TargetRotation, times, len(rotations))
+	t.node = node
+	for i := range rotations {
+		q := rotations[i].Normalized()
+		// Take the shortest path from the previous keyframe.
+		if i > 0 {
+			prev := t.quaternion(t.values[len(t.values)-4:])
+			if prev.Dot(&q) < 0 {
+				q = q.Scale(-1)
+			}
+		}
+		t.values = append(t.values, q.V[0], q.V[1], q.V[2], q.W)
+	}
+	return t
+}
+
+// NewScaleTrack creates a track animating the scale of node.
+func NewScaleTrack(node *dax.Node, times []float32, scales []math.Vec3) *Track {
+	t := newTrack(TargetScale, times, len(scales))
+	t.node = node
+	for i := range scales {
+		t.values = append(t.values, scales[i][:]...)
+	}
+	return t
+}
+
+// NewFloatTrack creates a track animating a float value, eg. a material
+// uniform. set is called with the animated value.
+func NewFloatTrack(set func(v float32), times []float32, values []float32) *Track {
+	t := newTrack(TargetFloat, times, len(values))
+	t.set = set
+	t.values = append(t.values, values...)
+	return t
+}
+
+// Duration returns the time of the last keyframe.
+func (t *Track) Duration() float32 {
+	if len(t.times) == 0 {
+		return 0
+	}
+	return t.times[len(t.times)-1]
+}
+
+func (t *Track) quaternion(v []float32) math.Quaternion {
+	return math.Quaternion{W: v[3], V: math.Vec3{v[0], v[1], v[2]}}
+}
+
+// keyframe returns the value of the keyframe i, clamped to the existing
+// keyframes.
+func (t *Track) keyframe(i int) []float32 {
+	if i < 0 {
+		i = 0
+	}
+	if last := len(t.times) - 1; i > last {
+		i = last
+	}
+	n := t.Target.numComponents()
+	return t.values[i*n : (i+1)*n]
+}
+
+// sample computes, in t.sampled, the value of the track at time.
+func (t *Track) sample(time float32) []float32 {
+	n := t.Target.numComponents()
+	out := t.sampled[:n]
+	if len(t.times) == 0 {
+		return nil
+	}
+
+	// i is the keyframe starting the segment time is in.
+	i := sort.Search(len(t.times), func(i int) bool { return t.times[i] > time }) - 1
+	if i < 0 || i == len(t.times)-1 || t.Interpolation == InterpolationStep {
+		copy(out, t.keyframe(i))
+		return out
+	}
+
+	u := (time - t.times[i]) / (t.times[i+1] - t.times[i])
+	v1, v2 := t.keyframe(i), t.keyframe(i+1)
+
+	switch {
+	case t.Interpolation == InterpolationLinear && t.Target == TargetRotation:
+		q1, q2 := t.quaternion(v1), t.quaternion(v2)
+		q := math.QuatSlerp(&q1, &q2, u)
+		out[0], out[1], out[2], out[3] = q.V[0], q.V[1], q.V[2], q.W
+	case t.Interpolation == InterpolationLinear:
+		for c := range out {
+			out[c] = v1[c] + (v2[c]-v1[c])*u
+		}
+	case t.Interpolation == InterpolationCubic:
+		v0, v3 := t.keyframe(i-1), t.keyframe(i+2)
+		for c := range out {
+			out[c] = catmullRom(v0[c], v1[c], v2[c], v3[c], u)
+		}
+		if t.Target == TargetRotation {
+			q := t.quaternion(out)
+			q.Normalize()
+			out[0], out[1], out[2], out[3] = q.V[0], q.V[1], q.V[2], q.W
+		}
+	}
+
+	return out
+}
+
+// catmullRom interpolates between p1, at u = 0, and p2, at u = 1.
+func catmullRom(p0, p1, p2, p3, u float32) float32 {
+	u2 := u * u
+	u3 := u2 * u
+	return .5 * (2*p1 +
+		(p2-p0)*u +
+		(2*p0-5*p1+4*p2-p3)*u2 +
+		(3*p1-p0-3*p2+p3)*u3)
+}
+
+// Apply sets the animated property to its value at time, in seconds. Before
+// the first keyframe and after the last one, the value is the one of the
+// first and last keyframe.
+func (t *Track) Apply(time float32) {
+	v := t.sample(time)
+	if v == nil {
+		return
+	}
+
+	switch t.Target {
+	case TargetPosition:
+		t.node.SetPosition(v[0], v[1], v[2])
+	case TargetRotation:
+		q := t.quaternion(v)
+		t.node.SetRotation(&q)
+	case TargetScale:
+		t.node.SetScale(v[0], v[1], v[2])
+	case TargetFloat:
+		t.set(v[0])
+	}
+}
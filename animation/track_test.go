@@ -0,0 +1,95 @@
+package animation
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertVec3(t *testing.T, expected, v *math.Vec3) {
+	for i := range expected {
+		assert.InDelta(t, expected[i], v[i], 1e-5)
+	}
+}
+
+func TestTrackInterpolation(t *testing.T) {
+	tests := []struct {
+		interpolation Interpolation
+		time          float32
+		expected      float32
+	}{
+		// Clamped outside the keyframes.
+		{InterpolationLinear, -1, 0},
+		{InterpolationLinear, 3, 0},
+		// On keyframes.
+		{InterpolationLinear, 0, 0},
+		{InterpolationLinear, 1, 10},
+		{InterpolationStep, 1, 10},
+		{InterpolationCubic, 1, 10},
+		// Between keyframes.
+		{InterpolationLinear, .5, 5},
+		{InterpolationLinear, 1.25, 7.5},
+		{InterpolationStep, .5, 0},
+		{InterpolationStep, 1.99, 10},
+		{InterpolationCubic, .5, 5.625},
+	}
+
+	for _, test := range tests {
+		var value float32
+		track := NewFloatTrack(func(v float32) { value = v },
+			[]float32{0, 1, 2}, []float32{0, 10, 0})
+		track.Interpolation = test.interpolation
+		track.Apply(test.time)
+		assert.InDelta(t, test.expected, value, 1e-5,
+			"interpolation %d, time %f", test.interpolation, test.time)
+	}
+}
+
+func TestTrackTargets(t *testing.T) {
+	node := dax.NewNode()
+	times := []float32{0, 2}
+
+	position := NewPositionTrack(node, times, []math.Vec3{{0, 0, 0}, {2, 4, 6}})
+	position.Apply(1)
+	assertVec3(t, &math.Vec3{1, 2, 3}, node.GetPosition())
+
+	scale := NewScaleTrack(node, times, []math.Vec3{{1, 1, 1}, {3, 3, 3}})
+	scale.Apply(1)
+	assertVec3(t, &math.Vec3{2, 2, 2}, node.GetScale())
+
+	q1 := math.QuatIdent()
+	q2 := math.QuatFromAxisAngle(&math.Vec3{0, 1, 0}, math.Pi/2)
+	rotation := NewRotationTrack(node, times, []math.Quaternion{q1, q2})
+	rotation.Apply(1)
+	assert.InDelta(t, math.Pi/4, node.GetRotation().Angle(), 1e-5)
+	axis := node.GetRotation().Axis()
+	assertVec3(t, &math.Vec3{0, 1, 0}, &axis)
+}
+
+func TestRotationTrackShortestPath(t *testing.T) {
+	node := dax.NewNode()
+	q1 := math.QuatIdent()
+	// -q2 is the same rotation as q2, but interpolating to it naively would
+	// go the long way around.
+	q2 := math.QuatFromAxisAngle(&math.Vec3{0, 1, 0}, math.Pi/2)
+	q2 = q2.Scale(-1)
+
+	for _, interpolation := range []Interpolation{InterpolationLinear, InterpolationCubic} {
+		track := NewRotationTrack(node, []float32{0, 1}, []math.Quaternion{q1, q2})
+		track.Interpolation = interpolation
+		track.Apply(.5)
+		assert.InDelta(t, math.Pi/4, node.GetRotation().Angle(), 1e-5)
+	}
+}
+
+func TestNewTrackPanics(t *testing.T) {
+	set := func(float32) {}
+	assert.Panics(t, func() {
+		NewFloatTrack(set, []float32{0, 1}, []float32{0})
+	})
+	assert.Panics(t, func() {
+		NewFloatTrack(set, []float32{1, 0}, []float32{0, 1})
+	})
+}
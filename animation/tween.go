@@ -0,0 +1,105 @@
+package animation
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Easing maps the progress of a Tween, from 0 to 1, to how far the value has
+// gone from its start to its end, 0 and 1 respectively.
+type Easing func(t float32) float32
+
+// EaseLinear changes the value at a constant speed.
+func EaseLinear(t float32) float32 {
+	return t
+}
+
+// EaseIn starts slowly and speeds up.
+func EaseIn(t float32) float32 {
+	return t * t
+}
+
+// EaseOut starts fast and slows down.
+func EaseOut(t float32) float32 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOut starts and ends slowly, eg. for camera moves and focus pulls.
+func EaseInOut(t float32) float32 {
+	return t * t * (3 - 2*t)
+}
+
+// Tween is a component changing a value from a start to an end over some
+// time, eg. the focus distance of a camera. It's advanced by SceneGraph.Update
+// when added to a node of the scene graph, or by calling Update directly.
+type Tween struct {
+	duration float32
+	set      func(v float32)
+	easing   Easing
+
+	from, to float32
+	time     float32
+	playing  bool
+}
+
+var _ dax.Updater = &Tween{}
+
+// NewTween creates a new Tween calling set with the value while playing. The
+// value takes duration seconds to go from its start to its end, with
+// EaseInOut. The tween is idle until Start is called.
+func NewTween(duration float32, set func(v float32)) *Tween {
+	return &Tween{
+		duration: duration,
+		set:      set,
+		easing:   EaseInOut,
+	}
+}
+
+// SetEasing sets how the value goes from its start to its end.
+func (t *Tween) SetEasing(easing Easing) {
+	t.easing = easing
+}
+
+// SetDuration sets the time, in seconds, the value takes to go from its start
+// to its end. It's used from the next Start.
+func (t *Tween) SetDuration(duration float32) {
+	t.duration = duration
+}
+
+// Start starts changing the value from from to to, interrupting the current
+// change if any. The value is set to from straight away.
+func (t *Tween) Start(from, to float32) {
+	t.from = from
+	t.to = to
+	t.time = 0
+	t.playing = true
+	t.set(from)
+}
+
+// Stop stops changing the value, leaving it where it is.
+func (t *Tween) Stop() {
+	t.playing = false
+}
+
+// IsPlaying returns true if the value is changing.
+func (t *Tween) IsPlaying() bool {
+	return t.playing
+}
+
+// Update implements dax.Updater. It advances the tween by dt seconds and sets
+// the value.
+func (t *Tween) Update(dt float64) {
+	if !t.playing {
+		return
+	}
+
+	t.time += float32(dt)
+	if t.duration <= 0 || t.time >= t.duration {
+		t.playing = false
+		t.set(t.to)
+		return
+	}
+
+	amount := t.easing(math.Clamp(t.time/t.duration, 0, 1))
+	t.set(t.from + (t.to-t.from)*amount)
+}
@@ -0,0 +1,52 @@
+package animation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTween(t *testing.T) {
+	var value float32
+	tween := NewTween(2, func(v float32) { value = v })
+	tween.SetEasing(EaseLinear)
+
+	// Idle until started.
+	tween.Update(1)
+	assert.Equal(t, float32(0), value)
+	assert.False(t, tween.IsPlaying())
+
+	tween.Start(10, 20)
+	assert.Equal(t, float32(10), value)
+	assert.True(t, tween.IsPlaying())
+
+	tween.Update(.5)
+	assert.InDelta(t, 12.5, value, 1e-4)
+
+	// Stops on the end value.
+	tween.Update(5)
+	assert.Equal(t, float32(20), value)
+	assert.False(t, tween.IsPlaying())
+
+	// Restarting interrupts the current change.
+	tween.Start(20, 0)
+	tween.Update(1)
+	tween.Start(value, 30)
+	assert.InDelta(t, 10, value, 1e-4)
+	tween.Update(1)
+	assert.InDelta(t, 20, value, 1e-4)
+
+	tween.Stop()
+	tween.Update(1)
+	assert.InDelta(t, 20, value, 1e-4)
+}
+
+func TestEasing(t *testing.T) {
+	for _, easing := range []Easing{EaseLinear, EaseIn, EaseOut, EaseInOut} {
+		assert.Equal(t, float32(0), easing(0))
+		assert.Equal(t, float32(1), easing(1))
+	}
+	assert.Equal(t, float32(.5), EaseInOut(.5))
+	assert.True(t, EaseIn(.5) < .5)
+	assert.True(t, EaseOut(.5) > .5)
+}
@@ -1,32 +1,93 @@
 package dax
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"runtime"
 	"sync"
+	"unsafe"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.1/glfw"
 )
 
+// initErr is the error initializing glfw or GL, reported when creating the
+// application.
+var initErr error
+
 func init() {
 	runtime.LockOSThread()
 
 	if err := glfw.Init(); err != nil {
-		log.Fatalln("failed to initialize glfw:", err)
+		initErr = fmt.Errorf("failed to initialize glfw: %v", err)
+		return
 	}
 
 	if err := gl.Init(); err != nil {
-		log.Fatalln("failed to initialize gl:", err)
+		initErr = fmt.Errorf("failed to initialize gl: %v", err)
 	}
 }
 
+// InitError returns the error initializing the windowing system and GL, nil
+// when windows can be created, eg. to skip tests needing a GL context.
+// NewApplication exits with this error.
+func InitError() error {
+	return initErr
+}
+
 // Application object is the top level object from which everything else in DaX
 // is derived.
+//
+// DaX objects (nodes, scene graphs, meshes, materials, ...) aren't safe for
+// concurrent use. They must only be modified from the goroutine running the
+// application main loop, eg. from Scene Update or event handlers. Other
+// goroutines can use Do to modify them.
+//
+// An application can have several windows, see NewWindow. Windows and the main
+// loop must be created and run from the main goroutine.
 type Application struct {
 	Name string
 
 	windows map[*glfw.Window]*Window
+	// ordered are the windows in the order they were created.
+	ordered   []*Window
+	mutations MutationQueue
+	clock     clock
+	options   EngineOptions
+	// frames is the number of frames drawn so far.
+	frames int
+
+	// quitting is set by Quit, terminated once GLFW has been terminated.
+	quitting   bool
+	terminated bool
+	quitHooks  hookList
+}
+
+// WindowOptions are optional parameters of NewWindow.
+type WindowOptions struct {
+	// Isolated gives the window a GL context of its own. By default, GL
+	// objects (textures, buffers, shaders, ...) are shared with the other
+	// windows of the application. Framebuffers are never shared: an
+	// OffScreen framebuffer can only be drawn into by the window it was
+	// created for.
+	Isolated bool
+	// Hidden creates the window without showing it, eg. to render scenes
+	// offscreen in tests with Window.RenderScene.
+	Hidden bool
+	// Samples is the number of samples per pixel of the window
+	// framebuffer, eg. 4 for 4x MSAA. 0 disables multisampling.
+	Samples int
+	// SRGB creates an sRGB window framebuffer: the colors drawn, in linear
+	// space, are converted to sRGB.
+	SRGB bool
+	// DepthBits and StencilBits are the precision of the depth and stencil
+	// buffers of the window framebuffer. 0 keeps the defaults, 24 bits of
+	// depth and 8 bits of stencil. Use NoStencil for a framebuffer without
+	// stencil.
+	DepthBits, StencilBits int
+	// NoStencil creates a window framebuffer without stencil buffer.
+	NoStencil bool
 }
 
 var appInstance *Application
@@ -34,40 +95,321 @@ var appOnce sync.Once
 
 // NewApplication creates a new Application. This is a singleton.
 func NewApplication(name string) *Application {
+	if initErr != nil {
+		log.Fatalln(initErr)
+	}
+
 	appOnce.Do(func() {
 		app := new(Application)
 		app.Name = name
 		app.windows = make(map[*glfw.Window]*Window)
+		app.clock = newClock()
+		app.options = DefaultEngineOptions()
 		appInstance = app
 	})
 	return appInstance
 }
 
 func (app *Application) addWindow(window *Window) {
-	// TODO: support multiple windows
 	app.windows[window.glfwWindow] = window
+	app.ordered = append(app.ordered, window)
+	app.updateSwapIntervals()
+}
+
+func (app *Application) removeWindow(window *Window) {
+	delete(app.windows, window.glfwWindow)
+	for i := range app.ordered {
+		if app.ordered[i] == window {
+			app.ordered = append(app.ordered[:i], app.ordered[i+1:]...)
+			break
+		}
+	}
+	app.updateSwapIntervals()
+}
+
+// updateSwapIntervals only syncs the first window to the vertical refresh:
+// each window waiting for it would divide the frame rate by the number of
+// windows.
+func (app *Application) updateSwapIntervals() {
+	for i, window := range app.ordered {
+		window.makeCurrent()
+		if i == 0 && app.options.VSync {
+			glfw.SwapInterval(1)
+		} else {
+			glfw.SwapInterval(0)
+		}
+	}
+}
+
+// sharedWindow returns the window new windows share their GL objects with,
+// nil if there's none.
+func (app *Application) sharedWindow() *glfw.Window {
+	for _, window := range app.ordered {
+		if !window.isolated {
+			return window.glfwWindow
+		}
+	}
+	return nil
+}
+
+// ParseFlags sets the engine options from the environment and the engine flags
+// of args, usually os.Args[1:], and returns the other arguments. It's called
+// before creating windows:
+//
+//	app := dax.NewApplication("viewer")
+//	args, err := app.ParseFlags(os.Args[1:])
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// See EngineOptions.
+func (app *Application) ParseFlags(args []string) ([]string, error) {
+	options, others, err := parseEngineOptions(args, os.Getenv)
+	if err != nil {
+		return args, err
+	}
+	app.options = options
+	return others, nil
 }
 
-// Run enters the application main loop.
+// Options returns the engine options given on the command line and in the
+// environment, see EngineOptions.
+func (app *Application) Options() EngineOptions {
+	return app.options
+}
+
+// Quit closes all the windows at the end of the current frame, Run then
+// returning once they are released. It can be called from event handlers,
+// Update or functions given to Do.
+func (app *Application) Quit() {
+	app.quitting = true
+}
+
+// OnQuit registers f to be called by Run once all windows have been closed and
+// their GL objects deleted, before GLFW is terminated. It's the place to
+// release resources that aren't tied to a window.
+func (app *Application) OnQuit(f func()) HookID {
+	return app.quitHooks.add(f)
+}
+
+// RemoveHook removes a function registered with OnQuit.
+func (app *Application) RemoveHook(id HookID) {
+	app.quitHooks.remove(id)
+}
+
+// Do queues f to be run from the main loop at the start of the next frame,
+// before the scenes are updated. Functions are run in the order they are
+// queued. Do can be called from any goroutine.
+func (app *Application) Do(f func()) {
+	app.mutations.Push(f)
+}
+
+// Time returns the simulation time, in seconds: the sum of the delta times of
+// the frames since the application main loop started, up to the current one.
+// Delta times being capped, it falls behind the wall-clock time after long
+// frames or pauses.
+func (app *Application) Time() float64 {
+	return app.clock.total
+}
+
+// DeltaTime returns the number of seconds elapsed between the previous frame
+// and the current one, capped to a quarter of a second so animations don't
+// jump after a pause.
+func (app *Application) DeltaTime() float64 {
+	return app.clock.dt
+}
+
+// Run enters the application main loop. Each frame, the windows are updated
+// and drawn in the order they were created. Run returns once all windows have
+// been closed, by the user, Window.Close or Quit.
+//
+// Closed windows are released at the end of the frame, see Window.OnClose.
+// Once the last one is, the OnQuit functions are called and GLFW is
+// terminated: no windows can be created after Run returns.
 func (app *Application) Run() {
-	for _, window := range app.windows {
-		for !window.glfwWindow.ShouldClose() {
-			window.Update()
+	if app.options.Capture != "" {
+		if err := os.MkdirAll(app.options.Capture, 0755); err != nil {
+			log.Fatalln("failed to create the capture directory:", err)
+		}
+	}
+
+	app.clock.start()
+	for len(app.ordered) > 0 {
+		dt := app.clock.tick()
+		app.mutations.Flush()
+
+		// Windows can be created from the main loop, don't range over
+		// app.ordered.
+		windows := append([]*Window(nil), app.ordered...)
+		for _, window := range windows {
+			window.makeCurrent()
+			window.Update(dt)
 			window.Draw()
-			window.glfwWindow.SwapBuffers()
-			glfw.PollEvents()
+			if app.options.Capture != "" {
+				if err := window.capture(app.options.Capture, app.frames); err != nil {
+					log.Fatalln("failed to capture frame:", err)
+				}
+			}
+			window.swap()
+		}
+		Debug.Clear()
+		glfw.PollEvents()
+
+		app.frames++
+		if app.options.Frames > 0 && app.frames >= app.options.Frames {
+			app.Quit()
+		}
+		if app.quitting {
+			for _, window := range app.ordered {
+				window.Close()
+			}
+		}
+
+		for _, window := range app.closedWindows() {
+			window.destroy()
+			app.removeWindow(window)
 		}
 	}
+
+	app.shutdown()
 }
 
-// CreateWindow creates a window on which scene will be drawn.
-func (app *Application) CreateWindow(name string, width, height int) *Window {
-	window := newWindow(app, name, width, height)
+// closedWindows returns the windows to destroy, the last created first so
+// windows sharing their GL objects with others are destroyed last.
+func (app *Application) closedWindows() []*Window {
+	var closed []*Window
+	for i := len(app.ordered) - 1; i >= 0; i-- {
+		if window := app.ordered[i]; window.glfwWindow.ShouldClose() {
+			closed = append(closed, window)
+		}
+	}
+	return closed
+}
+
+// shutdown ends the application once all windows have been destroyed.
+func (app *Application) shutdown() {
+	app.quitHooks.call()
+	glfw.Terminate()
+	app.terminated = true
+}
+
+// NewWindow creates a window on which a scene will be drawn. Each window has
+// its own scene, see Window.SetScene. Unless options say otherwise, windows
+// share their GL objects so meshes and textures can be drawn in any of them.
+func (app *Application) NewWindow(name string, width, height int, options ...WindowOptions) *Window {
+	if app.terminated {
+		panic("application: can't create a window after Run returned")
+	}
+
+	var opts WindowOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	var share *glfw.Window
+	if !opts.Isolated {
+		share = app.sharedWindow()
+	}
+
+	if app.options.Width > 0 {
+		width = app.options.Width
+	}
+	if app.options.Height > 0 {
+		height = app.options.Height
+	}
+	var monitor *glfw.Monitor
+	if app.options.Fullscreen && len(app.ordered) == 0 {
+		monitor = glfw.GetPrimaryMonitor()
+		mode := monitor.GetVideoMode()
+		if app.options.Width == 0 || app.options.Height == 0 {
+			width, height = mode.Width, mode.Height
+		}
+	}
+
+	visible := glfw.True
+	if opts.Hidden {
+		visible = glfw.False
+	}
+	glfw.WindowHint(glfw.Visible, visible)
+	opts.hintFramebuffer()
+	debug := glfw.False
+	if app.options.GLDebug {
+		debug = glfw.True
+	}
+	glfw.WindowHint(glfw.OpenGLDebugContext, debug)
+
+	window := newWindow(app, name, width, height, monitor, share)
+	if app.options.GLDebug {
+		enableGLDebug()
+	}
+	window.isolated = opts.Isolated
+	window.samples = opts.Samples
+	window.srgb = opts.SRGB
+	if opts.Samples > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+	}
+	if opts.SRGB {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
 	app.addWindow(window)
 
 	return window
 }
 
+// hintFramebuffer sets the GLFW hints describing the window framebuffer.
+func (opts *WindowOptions) hintFramebuffer() {
+	depthBits, stencilBits := 24, 8
+	if opts.DepthBits > 0 {
+		depthBits = opts.DepthBits
+	}
+	if opts.StencilBits > 0 {
+		stencilBits = opts.StencilBits
+	}
+	if opts.NoStencil {
+		stencilBits = 0
+	}
+	srgb := glfw.False
+	if opts.SRGB {
+		srgb = glfw.True
+	}
+
+	glfw.WindowHint(glfw.Samples, opts.Samples)
+	glfw.WindowHint(glfw.SRGBCapable, srgb)
+	glfw.WindowHint(glfw.DepthBits, depthBits)
+	glfw.WindowHint(glfw.StencilBits, stencilBits)
+}
+
+// enableGLDebug prints the debug messages of the GL driver for the current
+// context.
+func enableGLDebug() {
+	callback := func(source, gltype, id, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+		fmt.Fprintf(os.Stderr, "gl: %s\n", message)
+	}
+
+	switch {
+	case glHasExtension("GL_KHR_debug"):
+		gl.DebugMessageCallback(callback, nil)
+	case glHasExtension("GL_ARB_debug_output"):
+		gl.DebugMessageCallbackARB(callback, nil)
+	default:
+		fmt.Fprintln(os.Stderr, "gl: debug output isn't supported")
+		return
+	}
+	gl.Enable(gl.DEBUG_OUTPUT)
+	gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+}
+
+// CreateWindow creates a window on which scene will be drawn.
+//
+// Deprecated: use NewWindow.
+func (app *Application) CreateWindow(name string, width, height int) *Window {
+	return app.NewWindow(name, width, height)
+}
+
+// getWindow returns the Window of w, making its GL context current so event
+// handlers can draw or read back pixels.
 func getWindow(w *glfw.Window) *Window {
-	return appInstance.windows[w]
+	window := appInstance.windows[w]
+	window.makeCurrent()
+	return window
 }
@@ -16,6 +16,9 @@ type Camera interface {
 type BaseCamera struct {
 	Node
 	projection math.Mat4
+	modifiers  []CameraModifier
+	// offset is the composition of the modifiers transforms.
+	offset math.Mat4
 }
 
 // Init initializes the BaseCamera. Call this function first before anything
@@ -83,10 +86,44 @@ func (c *screenSpaceCamera) UpdateFBSize(width, height int) {
 type perspectiveCamera struct {
 	BaseCamera
 	fovy, aspect, near, far float32
+
+	// infiniteFar pushes the far plane to infinity.
+	infiniteFar bool
+	// reversedZ maps the near plane to a depth of 1 and the far plane to 0.
+	reversedZ bool
+	// autoNearFar fits near and far to the scene each frame.
+	autoNearFar bool
+	// fixedAspect stops the aspect ratio from following the framebuffer
+	// size.
+	fixedAspect bool
+
+	// Focus settings, for depth of field effects.
+	focusDistance, aperture float32
 }
 
 func (c *perspectiveCamera) updateProjection() {
-	c.projection = math.Perspective(c.fovy, c.aspect, c.near, c.far)
+	c.projection = c.perspective(c.reversedZ)
+}
+
+// perspective returns the projection of the camera, with a reversed depth
+// range if asked to.
+func (c *perspectiveCamera) perspective(reversed bool) math.Mat4 {
+	switch {
+	case reversed && c.infiniteFar:
+		return math.InfiniteReversedPerspective(c.fovy, c.aspect, c.near)
+	case reversed:
+		return math.ReversedPerspective(c.fovy, c.aspect, c.near, c.far)
+	case c.infiniteFar:
+		return math.InfinitePerspective(c.fovy, c.aspect, c.near)
+	default:
+		return math.Perspective(c.fovy, c.aspect, c.near, c.far)
+	}
+}
+
+// reversedProjection returns the projection of the camera with a reversed
+// depth range, whether the camera uses one or not.
+func (c *perspectiveCamera) reversedProjection() math.Mat4 {
+	return c.perspective(true)
 }
 
 func NewPerspectiveCamera(fovy, aspect, near, far float32) *perspectiveCamera {
@@ -96,13 +133,308 @@ func NewPerspectiveCamera(fovy, aspect, near, far float32) *perspectiveCamera {
 	c.aspect = aspect
 	c.near = near
 	c.far = far
+	c.focusDistance = 10
 
 	c.updateProjection()
 
 	return c
 }
 
+// UpdateFBSize implements Camera. The aspect ratio of the camera follows the
+// size of the framebuffer, unless it has been set with SetAspect.
 func (c *perspectiveCamera) UpdateFBSize(width, height int) {
+	if c.fixedAspect || width <= 0 || height <= 0 {
+		return
+	}
 	c.aspect = float32(width) / float32(height)
 	c.updateProjection()
 }
+
+// SetAspect overrides the aspect ratio of the camera, which otherwise follows
+// the size of the framebuffer drawn into. An aspect of 0 makes the camera
+// follow the framebuffer size again from the next resize.
+func (c *perspectiveCamera) SetAspect(aspect float32) {
+	c.fixedAspect = aspect != 0
+	if aspect == 0 {
+		return
+	}
+	c.aspect = aspect
+	c.updateProjection()
+}
+
+// GetAspect returns the aspect ratio of the camera.
+func (c *perspectiveCamera) GetAspect() float32 {
+	return c.aspect
+}
+
+// SetFocusDistance sets the distance, along the view direction, of the plane
+// in focus. It's 10 by default. The camera doesn't blur anything itself: the
+// focus settings are read by NewDepthOfFieldEffect. They can be animated, eg.
+// to pull focus to a picked object with an animation.Tween:
+//
+//	pull := animation.NewTween(.5, c.SetFocusDistance)
+//	sg.AddComponent(pull)
+//	...
+//	if distance, ok := sg.FocusDistance(x, y, fb); ok {
+//		pull.Start(c.FocusDistance(), distance)
+//	}
+func (c *perspectiveCamera) SetFocusDistance(distance float32) {
+	c.focusDistance = distance
+}
+
+// FocusDistance returns the distance of the plane in focus.
+func (c *perspectiveCamera) FocusDistance() float32 {
+	return c.focusDistance
+}
+
+// SetAperture sets the diameter of the lens, in world units: the wider, the
+// blurrier what's out of the focus plane. The default, 0, is a pinhole camera
+// with everything in focus.
+func (c *perspectiveCamera) SetAperture(aperture float32) {
+	c.aperture = aperture
+}
+
+// Aperture returns the diameter of the lens.
+func (c *perspectiveCamera) Aperture() float32 {
+	return c.aperture
+}
+
+// SetNearFar sets the distance of the near and far planes.
+func (c *perspectiveCamera) SetNearFar(near, far float32) {
+	c.near = near
+	c.far = far
+	c.updateProjection()
+}
+
+// GetNearFar returns the distance of the near and far planes.
+func (c *perspectiveCamera) GetNearFar() (near, far float32) {
+	return c.near, c.far
+}
+
+// SetInfiniteFar pushes the far plane to infinity. Nothing is clipped by the
+// far plane anymore, at the cost of some depth precision.
+func (c *perspectiveCamera) SetInfiniteFar(infinite bool) {
+	c.infiniteFar = infinite
+	c.updateProjection()
+}
+
+// SetReversedZ maps the near plane to a depth of 1 and the far plane to a depth
+// of 0. The renderer switches to a GREATER depth test and, when the driver
+// supports glClipControl, to a [0, 1] clip space depth range. With a floating
+// point depth buffer, this gives an almost uniform depth precision over the
+// whole view distance, removing most z-fighting in large scenes.
+func (c *perspectiveCamera) SetReversedZ(reversed bool) {
+	c.reversedZ = reversed
+	c.updateProjection()
+}
+
+func (c *perspectiveCamera) isReversedZ() bool {
+	return c.reversedZ
+}
+
+// SetAutoNearFar makes the camera compute its near and far planes from the
+// bounds of the visible scene before each frame, keeping the depth range as
+// tight as possible. With an infinite far plane, only near is adjusted.
+func (c *perspectiveCamera) SetAutoNearFar(auto bool) {
+	c.autoNearFar = auto
+}
+
+// Framer is a camera able to move to fit bounds in its view.
+type Framer interface {
+	// Frame positions the camera so the box bounds, in world space, is in
+	// view. margin is the extra space to leave around the bounds, as a
+	// ratio of their size: 0.1 gives 10% of room.
+	Frame(bounds *math.AABB, margin float32)
+}
+
+// Frame implements Framer. The camera keeps its orientation and is moved back
+// along its view direction until the bounding sphere of bounds fits in both
+// the horizontal and vertical fields of view. Unless the camera fits its near
+// and far planes automatically, they are widened if they would clip bounds.
+// The camera is expected not to have a parent.
+func (c *perspectiveCamera) Frame(bounds *math.AABB, margin float32) {
+	if bounds.IsEmpty() {
+		return
+	}
+
+	sphere := bounds.BoundingSphere()
+	radius := sphere.Radius * (1 + margin)
+	halfFov := c.fovy / 2
+	if c.aspect < 1 {
+		// The horizontal field of view is the narrowest.
+		halfFov = math.Atan(math.Tan(halfFov) * c.aspect)
+	}
+	distance := radius / math.Sin(halfFov)
+
+	forward := c.rotation.Forward()
+	position := sphere.Center
+	position.AddScaledVec(-distance, &forward)
+	c.SetPositionV(&position)
+
+	if c.autoNearFar {
+		return
+	}
+	near, far := c.near, c.far
+	if d := distance - radius; d < near {
+		near = math.Max(d, distance*autoNearFarMinRatio)
+	}
+	if d := distance + radius; d > far && !c.infiniteFar {
+		far = d
+	}
+	c.SetNearFar(near, far)
+}
+
+// Margins used when fitting the near and far planes to the scene.
+const (
+	// The planes are pushed away from the scene bounds by this ratio.
+	autoNearFarMargin = 0.01
+	// Minimum near/far ratio, to keep some depth precision when the scene
+	// extends right up to the camera.
+	autoNearFarMinRatio = 1e-4
+)
+
+func (c *perspectiveCamera) fitNearFar(sg *SceneGraph, s *sceneState) {
+	if !c.autoNearFar {
+		return
+	}
+
+	view := cameraView(c)
+	near, far, ok := sceneDepthRange(sg, s, &view)
+	if !ok {
+		return
+	}
+
+	far *= 1 + autoNearFarMargin
+	near *= 1 - autoNearFarMargin
+	if min := far * autoNearFarMinRatio; near < min {
+		near = min
+	}
+
+	if c.infiniteFar {
+		far = c.far
+	}
+	c.SetNearFar(near, far)
+}
+
+// fitNearFar fits the near and far planes of c to the meshes of sg, or of its
+// snapshot s when not nil, if c does so, see SetAutoNearFar.
+func fitNearFar(c Camera, sg *SceneGraph, s *sceneState) {
+	if f, ok := c.(interface {
+		fitNearFar(sg *SceneGraph, s *sceneState)
+	}); ok {
+		f.fitNearFar(sg, s)
+	}
+}
+
+// Far plane distance used for logarithmic depth with cameras that don't have
+// one.
+const defaultLogDepthFar = 1e7
+
+// cameraFar returns the distance of the far plane of c.
+func cameraFar(c Camera) float32 {
+	p, ok := c.(interface{ GetNearFar() (near, far float32) })
+	if !ok {
+		return defaultLogDepthFar
+	}
+	_, far := p.GetNearFar()
+	return far
+}
+
+// cameraProjection returns the projection of c. Cameras drawing into a
+// reversed-Z framebuffer, reversed, get a reversed projection when they support
+// one, without being changed: the same camera can draw into framebuffers with
+// different depth ranges.
+func cameraProjection(c Camera, reversed bool) math.Mat4 {
+	if reversed && !isReversedZ(c) {
+		if r, ok := c.(interface{ reversedProjection() math.Mat4 }); ok {
+			return r.reversedProjection()
+		}
+	}
+	return *c.GetProjection()
+}
+
+// isReversedZ returns true if c uses a reversed depth range.
+func isReversedZ(c Camera) bool {
+	r, ok := c.(interface{ isReversedZ() bool })
+	return ok && r.isReversedZ()
+}
+
+// cameraView returns the view matrix of c: the inverse of the camera world
+// transform, with the camera modifiers applied.
+func cameraView(c Camera) math.Mat4 {
+	// The camera may either be part of the scene (part of the scene graph) or not.
+	// XXX: we don't check that the root of the tree the Camera is part of is
+	// indeed the scenegraph we are drawing.
+	cameraNode := c.AsNode()
+	var cameraWorld math.Mat4
+	if cameraNode.parent == nil {
+		cameraWorld = *cameraNode.GetTransform()
+	} else {
+		cameraWorld = *cameraNode.worldTransform.AsMat4()
+	}
+
+	// Camera modifiers are applied on top of the camera transform.
+	if m, ok := c.(interface{ getOffset() *math.Mat4 }); ok {
+		if offset := m.getOffset(); offset != nil {
+			cameraWorld.Mul4With(offset)
+		}
+	}
+
+	return cameraWorld.Inverse()
+}
+
+// sceneDepthRange returns the distances, along the view direction, of the
+// closest and farthest points of the meshes in sg in front of the camera,
+// using their bounding boxes. ok is false when there's nothing in front of the
+// camera. World transforms are
+// expected to be up to date. The nodes of the snapshot s are used instead of
+// the ones of sg when s isn't nil.
+func sceneDepthRange(sg *SceneGraph, s *sceneState, view *math.Mat4) (near, far float32, ok bool) {
+	near = math.MaxFloat32
+
+	add := func(bounds math.AABB, world *math.Mat4) {
+		if bounds.IsEmpty() {
+			return
+		}
+
+		modelView := view.Mul4(world)
+		nodeNear, nodeFar := float32(math.MaxFloat32), float32(-math.MaxFloat32)
+		for i := 0; i < 8; i++ {
+			c := bounds.Corner(i)
+			corner := math.Vec4{c[0], c[1], c[2], 1}
+
+			// The camera looks down -z.
+			d := -modelView.Mul4x1(&corner).Z()
+			nodeNear = math.Min(nodeNear, d)
+			nodeFar = math.Max(nodeFar, d)
+		}
+
+		// Skip nodes entirely behind the camera.
+		if nodeFar <= 0 {
+			return
+		}
+		near = math.Min(near, math.Max(nodeNear, 0))
+		far = math.Max(far, nodeFar)
+	}
+
+	if s != nil {
+		for i := range s.nodes {
+			add(s.nodes[i].bounds, &s.nodes[i].world)
+		}
+	} else {
+		for g := range sg.Traverse() {
+			node, isNode := g.(*Node)
+			if !isNode {
+				continue
+			}
+			if mr := getMeshRenderer(node); mr != nil {
+				add(mr.Bounds(), node.worldTransform.AsMat4())
+			}
+		}
+	}
+
+	if far <= 0 {
+		return 0, 0, false
+	}
+	return near, far, true
+}
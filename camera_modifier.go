@@ -0,0 +1,208 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// CameraModifier procedurally offsets a camera, eg. to make it shake. The
+// offset is applied on top of the camera transform when rendering and doesn't
+// change the camera node itself, so modifiers don't interfere with whatever
+// code is moving the camera around.
+type CameraModifier interface {
+	// Update advances the modifier by dt seconds and returns the offset to
+	// apply, in camera space: a translation and a rotation given as pitch,
+	// yaw and roll angles in radians.
+	Update(dt float32) (translation, rotation math.Vec3)
+}
+
+// AddModifier adds a modifier to the camera. Modifiers are composed in the
+// order they are added.
+func (c *BaseCamera) AddModifier(m CameraModifier) {
+	if len(c.modifiers) == 0 {
+		c.offset.Ident()
+	}
+	c.modifiers = append(c.modifiers, m)
+}
+
+// RemoveModifier removes a modifier from the camera.
+func (c *BaseCamera) RemoveModifier(m CameraModifier) {
+	for i := range c.modifiers {
+		if c.modifiers[i] == m {
+			c.modifiers = append(c.modifiers[:i], c.modifiers[i+1:]...)
+			return
+		}
+	}
+}
+
+// UpdateModifiers advances all the camera modifiers by dt seconds. It should
+// be called once per frame, after the camera has been moved. Windows do it for
+// the camera of their scene, after Scene.Update.
+func (c *BaseCamera) UpdateModifiers(dt float32) {
+	c.offset.Ident()
+
+	for _, m := range c.modifiers {
+		translation, rotation := m.Update(dt)
+
+		offset := math.Translate3D(translation[0], translation[1], translation[2])
+		// yaw, then pitch, then roll.
+		q := math.AnglesToQuat(rotation[1], rotation[0], rotation[2], math.YXZ)
+		r := q.Mat4()
+		offset.Mul4With(&r)
+		c.offset.Mul4With(&offset)
+	}
+}
+
+// updateCameraModifiers advances the modifiers of c by dt seconds, if c can
+// have modifiers.
+func updateCameraModifiers(c Camera, dt float64) {
+	if m, ok := c.(interface{ UpdateModifiers(dt float32) }); ok {
+		m.UpdateModifiers(float32(dt))
+	}
+}
+
+// getOffset returns the transform computed by the modifiers or nil if the
+// camera has no modifier.
+func (c *BaseCamera) getOffset() *math.Mat4 {
+	if len(c.modifiers) == 0 {
+		return nil
+	}
+	return &c.offset
+}
+
+// noise3 returns 3 independent noise values at time t.
+func noise3(t float32) math.Vec3 {
+	// Sample the noise far apart for each channel so they're not correlated.
+	return math.Vec3{
+		math.Noise1(t),
+		math.Noise1(t + 71.3),
+		math.Noise1(t + 143.7),
+	}
+}
+
+// CameraShake shakes the camera with a strength driven by a trauma value
+// between 0 and 1. Events like explosions add trauma which then decays over
+// time. The shake intensity is trauma squared, making small trauma values
+// barely noticeable and large ones violent.
+type CameraShake struct {
+	// MaxTranslation is the maximum translation offset along each axis.
+	MaxTranslation math.Vec3
+	// MaxRotation is the maximum pitch, yaw and roll offsets in radians.
+	MaxRotation math.Vec3
+	// Frequency is the speed at which the noise is sampled.
+	Frequency float32
+	// Decay is the amount of trauma removed each second.
+	Decay float32
+
+	trauma float32
+	time   float32
+}
+
+// NewCameraShake creates a CameraShake with sensible defaults: rotation only,
+// up to a few degrees, and a trauma fully decaying in one second.
+func NewCameraShake() *CameraShake {
+	return &CameraShake{
+		MaxRotation: math.Vec3{math.DegToRad(4), math.DegToRad(4), math.DegToRad(2)},
+		Frequency:   15,
+		Decay:       1,
+	}
+}
+
+// AddTrauma adds trauma to the shake. The result is clamped between 0 and 1.
+func (s *CameraShake) AddTrauma(trauma float32) {
+	s.trauma = math.Clamp(s.trauma+trauma, 0, 1)
+}
+
+// Trauma returns the current trauma value.
+func (s *CameraShake) Trauma() float32 {
+	return s.trauma
+}
+
+// Update implements CameraModifier.
+func (s *CameraShake) Update(dt float32) (translation, rotation math.Vec3) {
+	s.time += dt
+	s.trauma = math.Clamp(s.trauma-s.Decay*dt, 0, 1)
+
+	shake := s.trauma * s.trauma
+	if shake == 0 {
+		return
+	}
+
+	n := noise3(s.time * s.Frequency)
+	translation = math.Vec3{
+		shake * s.MaxTranslation[0] * n[0],
+		shake * s.MaxTranslation[1] * n[1],
+		shake * s.MaxTranslation[2] * n[2],
+	}
+	n = noise3(s.time*s.Frequency + 521.9)
+	rotation = math.Vec3{
+		shake * s.MaxRotation[0] * n[0],
+		shake * s.MaxRotation[1] * n[1],
+		shake * s.MaxRotation[2] * n[2],
+	}
+	return
+}
+
+// HandheldWobble is a slow and continuous camera sway simulating a camera
+// held by hand.
+type HandheldWobble struct {
+	// Amplitude is the maximum pitch, yaw and roll offsets in radians.
+	Amplitude math.Vec3
+	// Frequency is the speed at which the noise is sampled.
+	Frequency float32
+
+	time float32
+}
+
+// NewHandheldWobble creates a HandheldWobble with a subtle default sway.
+func NewHandheldWobble() *HandheldWobble {
+	return &HandheldWobble{
+		Amplitude: math.Vec3{math.DegToRad(.5), math.DegToRad(.5), math.DegToRad(.25)},
+		Frequency: .5,
+	}
+}
+
+// Update implements CameraModifier.
+func (w *HandheldWobble) Update(dt float32) (translation, rotation math.Vec3) {
+	w.time += dt
+
+	n := noise3(w.time * w.Frequency)
+	rotation = math.Vec3{
+		w.Amplitude[0] * n[0],
+		w.Amplitude[1] * n[1],
+		w.Amplitude[2] * n[2],
+	}
+	return
+}
+
+// CameraRecoil kicks the camera, eg. when firing a weapon, and then smoothly
+// brings it back to its rest position.
+type CameraRecoil struct {
+	// Recovery is the speed at which the camera goes back to its rest
+	// position. The kick is halved every 1/Recovery seconds.
+	Recovery float32
+
+	translation math.Vec3
+	rotation    math.Vec3
+}
+
+// NewCameraRecoil creates a CameraRecoil.
+func NewCameraRecoil() *CameraRecoil {
+	return &CameraRecoil{
+		Recovery: 10,
+	}
+}
+
+// Kick adds a translation and a rotation (pitch, yaw, roll in radians) to the
+// current recoil.
+func (r *CameraRecoil) Kick(translation, rotation *math.Vec3) {
+	r.translation.AddWith(translation)
+	r.rotation.AddWith(rotation)
+}
+
+// Update implements CameraModifier.
+func (r *CameraRecoil) Update(dt float32) (translation, rotation math.Vec3) {
+	attenuation := math.Pow(.5, r.Recovery*dt)
+	r.translation.MulWith(attenuation)
+	r.rotation.MulWith(attenuation)
+	return r.translation, r.rotation
+}
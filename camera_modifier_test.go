@@ -0,0 +1,74 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraShake(t *testing.T) {
+	s := NewCameraShake()
+	s.MaxTranslation = math.Vec3{1, 1, 1}
+
+	// No trauma, no shake.
+	translation, rotation := s.Update(.1)
+	assert.Equal(t, math.Vec3{}, translation)
+	assert.Equal(t, math.Vec3{}, rotation)
+
+	s.AddTrauma(.8)
+	s.AddTrauma(.8)
+	assert.Equal(t, float32(1), s.Trauma())
+
+	translation, rotation = s.Update(.25)
+	assert.InDelta(t, .75, s.Trauma(), 1e-6)
+	assert.NotEqual(t, math.Vec3{}, translation)
+	for i := 0; i < 3; i++ {
+		assert.True(t, math.Abs(translation[i]) <= s.MaxTranslation[i])
+		assert.True(t, math.Abs(rotation[i]) <= s.MaxRotation[i])
+	}
+
+	// The trauma decays back to 0.
+	s.Update(1)
+	assert.Equal(t, float32(0), s.Trauma())
+}
+
+func TestCameraRecoil(t *testing.T) {
+	r := NewCameraRecoil()
+	r.Kick(&math.Vec3{0, 0, 1}, &math.Vec3{.2, 0, 0})
+
+	translation, rotation := r.Update(1 / r.Recovery)
+	assertVec3(t, &math.Vec3{0, 0, .5}, &translation, 1e-6)
+	assertVec3(t, &math.Vec3{.1, 0, 0}, &rotation, 1e-6)
+}
+
+func TestCameraModifiers(t *testing.T) {
+	camera := NewPerspectiveCamera(45, 1, 1, 100)
+	assert.Nil(t, camera.getOffset())
+
+	r := NewCameraRecoil()
+	camera.AddModifier(r)
+	assert.Equal(t, math.Ident4(), *camera.getOffset())
+
+	r.Kick(&math.Vec3{1, 0, 0}, &math.Vec3{})
+	camera.UpdateModifiers(0)
+	offset := camera.getOffset()
+	assertVec3(t, &math.Vec3{1, 0, 0}, &math.Vec3{offset[12], offset[13], offset[14]}, 1e-6)
+
+	camera.RemoveModifier(r)
+	assert.Nil(t, camera.getOffset())
+}
+
+func TestUpdateCameraModifiers(t *testing.T) {
+	camera := NewPerspectiveCamera(45, 1, 1, 100)
+	r := NewCameraRecoil()
+	camera.AddModifier(r)
+	r.Kick(&math.Vec3{1, 0, 0}, &math.Vec3{})
+
+	updateCameraModifiers(camera, 0)
+	offset := camera.getOffset()
+	assertVec3(t, &math.Vec3{1, 0, 0}, &math.Vec3{offset[12], offset[13], offset[14]}, 1e-6)
+
+	// Cameras without modifiers.
+	updateCameraModifiers(nil, 0)
+}
@@ -0,0 +1,76 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// CameraPath describes the movement of a camera over time with two splines:
+// one for the position of the camera and one for the point it looks at. It can
+// be used for cinematics or to capture the same sequence of frames again and
+// again.
+type CameraPath struct {
+	position math.Spline3
+	target   math.Spline3
+	// Loop makes the path start over once its end is reached.
+	Loop bool
+}
+
+// NewCameraPath creates an empty CameraPath.
+func NewCameraPath() *CameraPath {
+	return new(CameraPath)
+}
+
+// AddKey adds a key frame to the path: at time t, the camera will be at
+// position and looking at target. Keys have to be added in increasing time
+// order.
+func (p *CameraPath) AddKey(t float32, position, target *math.Vec3) {
+	p.position.AddPoint(t, position)
+	p.target.AddPoint(t, target)
+}
+
+// Duration returns the time between the first and last key of the path.
+func (p *CameraPath) Duration() float32 {
+	return p.position.End() - p.position.Start()
+}
+
+func (p *CameraPath) localTime(t float32) float32 {
+	start, duration := p.position.Start(), p.Duration()
+	if p.Loop && duration > 0 && t > start {
+		t = start + math.Mod(t-start, duration)
+	}
+	return t
+}
+
+// Evaluate returns the position of the camera and the point it looks at at
+// time t.
+func (p *CameraPath) Evaluate(t float32) (position, target math.Vec3) {
+	t = p.localTime(t)
+	return p.position.Evaluate(t), p.target.Evaluate(t)
+}
+
+// Apply moves and orients camera c to where it should be at time t.
+func (p *CameraPath) Apply(c Camera, t float32) {
+	position, target := p.Evaluate(t)
+
+	node := c.AsNode()
+	node.SetPositionV(&position)
+	q := math.QuatLookAtV(&position, &target, up)
+	node.SetRotation(&q)
+}
+
+// Polyline returns a Polyline following the camera position with n segments.
+// Drawing it is a handy way to preview the path.
+func (p *CameraPath) Polyline(n int) *Polyline {
+	if n < 1 {
+		n = 1
+	}
+	line := NewPolylineWithSize(n + 1)
+	start, duration := p.position.Start(), p.Duration()
+
+	for i := 0; i <= n; i++ {
+		v := p.position.Evaluate(start + duration*float32(i)/float32(n))
+		line.AddVertex(&v)
+	}
+
+	return line
+}
@@ -0,0 +1,32 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraPath(t *testing.T) {
+	path := NewCameraPath()
+	path.AddKey(0, &math.Vec3{0, 0, 10}, &math.Vec3{0, 0, 0})
+	path.AddKey(2, &math.Vec3{10, 0, 0}, &math.Vec3{0, 0, 0})
+	path.AddKey(4, &math.Vec3{0, 0, -10}, &math.Vec3{0, 0, 0})
+
+	assert.Equal(t, float32(4), path.Duration())
+
+	position, target := path.Evaluate(2)
+	assertVec3(t, &math.Vec3{10, 0, 0}, &position, 1e-3)
+	assertVec3(t, &math.Vec3{}, &target, 1e-3)
+
+	path.Loop = true
+	position, _ = path.Evaluate(6)
+	assertVec3(t, &math.Vec3{10, 0, 0}, &position, 1e-3)
+
+	camera := NewPerspectiveCamera(45, 1, 1, 100)
+	path.Apply(camera, 2)
+	assertVec3(t, &math.Vec3{10, 0, 0}, camera.GetPosition(), 1e-3)
+
+	line := path.Polyline(8)
+	assert.Equal(t, 9, line.Size())
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestLookAt(t *testing.T) {
@@ -34,3 +35,211 @@ func TestLookAt(t *testing.T) {
 		assertVec3(t, &test.result, &result, 1e-3)
 	}
 }
+
+// cubeMesh is a unit cube centered on the origin. Only its corners are
+// given, as points: the tests only look at its bounds.
+type cubeMesh struct{}
+
+func (c *cubeMesh) GetMesh() *Mesh {
+	m := NewMesh()
+	m.SetVertexMode(VertexModePoints)
+	positions := m.getNewAttribute("position")
+	positions.Init("position", 8, 3)
+	for i := 0; i < 8; i++ {
+		positions.SetXYZ(i, float32(i&1)-.5, float32(i>>1&1)-.5, float32(i>>2&1)-.5)
+	}
+	return m
+}
+
+func buildDepthTestScene() *SceneGraph {
+	sg := NewSceneGraph()
+	for _, z := range []float32{-10, -50, 20} {
+		n := NewNode().AddComponent(NewMeshRenderer(&cubeMesh{}, &dummyOpaqueMaterial{}))
+		n.SetPosition(0, 0, z)
+		sg.AddChild(n)
+	}
+	sg.updateWorldTransform()
+	return sg
+}
+
+func TestSceneDepthRange(t *testing.T) {
+	sg := buildDepthTestScene()
+
+	// Camera at the origin, looking down -z.
+	view := math.Ident4()
+	near, far, ok := sceneDepthRange(sg, nil, &view)
+	assert.True(t, ok)
+	// The cube behind the camera is ignored.
+	assertFloat(t, 9.5, near, 1e-3)
+	assertFloat(t, 50.5, far, 1e-3)
+
+	// Looking down +z now, only the cube at z = 20 is in front.
+	view = math.HomogRotate3DY(math.Pi)
+	near, far, ok = sceneDepthRange(sg, nil, &view)
+	assert.True(t, ok)
+	assertFloat(t, 19.5, near, 1e-3)
+	assertFloat(t, 20.5, far, 1e-3)
+
+	// A cube around the camera.
+	view = math.Translate3D(0, 0, 10)
+	near, far, ok = sceneDepthRange(sg, nil, &view)
+	assert.True(t, ok)
+	assertFloat(t, 0, near, 1e-3)
+	assertFloat(t, 40.5, far, 1e-3)
+
+	// Nothing in front.
+	view = math.Translate3D(0, 0, 100)
+	_, _, ok = sceneDepthRange(sg, nil, &view)
+	assert.False(t, ok)
+}
+
+func TestAutoNearFar(t *testing.T) {
+	sg := buildDepthTestScene()
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, .1, 1000)
+
+	// Disabled by default.
+	c.fitNearFar(sg, nil)
+	near, far := c.GetNearFar()
+	assertFloat(t, .1, near, 1e-5)
+	assertFloat(t, 1000, far, 1e-5)
+
+	c.SetAutoNearFar(true)
+	c.fitNearFar(sg, nil)
+	near, far = c.GetNearFar()
+	assert.True(t, near <= 9.5 && near > 9)
+	assert.True(t, far >= 50.5 && far < 52)
+
+	// Only near is fitted with an infinite far plane.
+	c.SetNearFar(.1, 1000)
+	c.SetInfiniteFar(true)
+	c.fitNearFar(sg, nil)
+	near, far = c.GetNearFar()
+	assert.True(t, near <= 9.5 && near > 9)
+	assertFloat(t, 1000, far, 1e-5)
+}
+
+func TestFrame(t *testing.T) {
+	sg := buildDepthTestScene()
+	bounds := sg.Bounds()
+	assert.Equal(t, math.Vec3{-.5, -.5, -50.5}, bounds.Min)
+	assert.Equal(t, math.Vec3{.5, .5, 20.5}, bounds.Max)
+
+	c := NewPerspectiveCamera(math.DegToRad(60), 2, 1, 10)
+	c.Frame(&bounds, 0)
+
+	// The camera looks down -z at the center of the scene, from far enough
+	// to see the whole bounding sphere.
+	sphere := bounds.BoundingSphere()
+	position := c.AsNode().position
+	assertFloat(t, 0, position[0], 1e-5)
+	assertFloat(t, 0, position[1], 1e-5)
+	assertFloat(t, sphere.Center[2]+sphere.Radius/math.Sin(math.DegToRad(30)), position[2], 1e-3)
+
+	// Near and far are widened to not clip the scene.
+	near, far := c.GetNearFar()
+	assert.True(t, near <= position[2]-20.5)
+	assert.True(t, far >= position[2]+50.5)
+
+	// The whole scene is in the view frustum.
+	view := cameraView(c)
+	viewProjection := c.GetProjection().Mul4(&view)
+	for i := 0; i < 8; i++ {
+		corner := bounds.Corner(i)
+		p := viewProjection.Mul4x1(&math.Vec4{corner[0], corner[1], corner[2], 1})
+		for j := 0; j < 3; j++ {
+			assert.True(t, math.Abs(p[j]) <= p[3], "corner %v is out of view", corner)
+		}
+	}
+
+	// A margin moves the camera further away.
+	c.Frame(&bounds, .5)
+	assert.True(t, c.AsNode().position[2] > position[2])
+
+	// Empty bounds don't move the camera.
+	position = c.AsNode().position
+	empty := math.EmptyAABB()
+	c.Frame(&empty, 0)
+	assert.Equal(t, position, c.AsNode().position)
+}
+
+func TestReversedZ(t *testing.T) {
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, 1, 100)
+	assert.False(t, isReversedZ(c))
+
+	depth := func(d float32) float32 {
+		v := c.GetProjection().Mul4x1(&math.Vec4{0, 0, -d, 1})
+		return v[2] / v[3]
+	}
+
+	assertFloat(t, -1, depth(1), 1e-5)
+	assertFloat(t, 1, depth(100), 1e-5)
+
+	c.SetReversedZ(true)
+	assert.True(t, isReversedZ(c))
+	assertFloat(t, 1, depth(1), 1e-5)
+	assertFloat(t, 0, depth(100), 1e-3)
+
+	c.SetInfiniteFar(true)
+	assertFloat(t, 1, depth(1), 1e-5)
+	assert.True(t, depth(1e6) < 1e-5)
+
+	assert.False(t, isReversedZ(NewOrthographicCamera(-1, 1, -1, 1, 1, -1)))
+}
+
+func TestCameraProjectionReversed(t *testing.T) {
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, 1, 100)
+	depth := func(projection math.Mat4, d float32) float32 {
+		v := projection.Mul4x1(&math.Vec4{0, 0, -d, 1})
+		return v[2] / v[3]
+	}
+
+	// Drawing into a reversed-Z framebuffer reverses the projection without
+	// changing the camera.
+	projection := cameraProjection(c, true)
+	assertFloat(t, 1, depth(projection, 1), 1e-5)
+	assertFloat(t, 0, depth(projection, 100), 1e-3)
+	assert.False(t, isReversedZ(c))
+	assertFloat(t, -1, depth(*c.GetProjection(), 1), 1e-5)
+	assert.Equal(t, *c.GetProjection(), cameraProjection(c, false))
+
+	// Cameras already reversed stay reversed.
+	c.SetReversedZ(true)
+	assert.Equal(t, *c.GetProjection(), cameraProjection(c, false))
+
+	// Letterboxing is kept.
+	perspective := NewPerspectiveCamera(math.DegToRad(60), 1, 1, 100)
+	l := NewLetterboxCamera(perspective, 16./9.)
+	l.UpdateFBSize(800, 600)
+	projection = cameraProjection(l, true)
+	assertFloat(t, 1, depth(projection, 1), 1e-5)
+	assertFloat(t, l.GetProjection()[5], projection[5], 1e-6)
+	assert.False(t, isReversedZ(perspective))
+
+	// Orthographic cameras don't have a reversed projection.
+	o := NewOrthographicCamera(-1, 1, -1, 1, 1, -1)
+	assert.Equal(t, *o.GetProjection(), cameraProjection(o, true))
+}
+
+func TestPerspectiveAspect(t *testing.T) {
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, 1, 100)
+
+	// The aspect ratio follows the framebuffer.
+	c.UpdateFBSize(800, 400)
+	assertFloat(t, 2, c.GetAspect(), 1e-5)
+	projection := math.Perspective(c.fovy, 2, 1, 100)
+	assert.Equal(t, projection, *c.GetProjection())
+
+	// Unless overridden.
+	c.SetAspect(.5)
+	c.UpdateFBSize(800, 400)
+	assertFloat(t, .5, c.GetAspect(), 1e-5)
+
+	c.SetAspect(0)
+	assertFloat(t, .5, c.GetAspect(), 1e-5)
+	c.UpdateFBSize(300, 100)
+	assertFloat(t, 3, c.GetAspect(), 1e-5)
+
+	// Minimized windows have a 0x0 framebuffer.
+	c.UpdateFBSize(0, 0)
+	assertFloat(t, 3, c.GetAspect(), 1e-5)
+}
@@ -0,0 +1,68 @@
+// Package canvas lays out 2D nodes in pixel space, for user interfaces and
+// other 2D scenes.
+//
+// A Canvas is the root of a tree of Nodes. Nodes are anchored in the rectangle
+// of their parent or placed by a container Layout (HBox, VBox, Grid), and drawn
+// in z-index order. The canvas drives dax nodes, to be drawn with a camera
+// mapping pixels to the canvas, eg. the screen space camera:
+//
+//	c := canvas.NewCanvas(800, 600)
+//	button := canvas.NewNode()
+//	button.SetAnchor(canvas.AnchorBottomRight)
+//	button.SetOffset(-10, -10)
+//	button.SetSize(100, 30)
+//	button.Content().AddComponent(dax.NewMeshRenderer(quad, material))
+//	c.AddChild(button)
+//	sg.AddChild(c.AsNode())
+//
+// The canvas is a component of its dax node: SceneGraph.Update lays it out
+// each frame.
+package canvas
+
+import (
+	"github.com/dlespiau/dax"
+)
+
+// Canvas is the root of a tree of 2D nodes. It covers a rectangle of its own
+// size, at the origin.
+type Canvas struct {
+	Node
+}
+
+var _ dax.Updater = &Canvas{}
+
+// NewCanvas creates a new Canvas of size width x height pixels.
+func NewCanvas(width, height float32) *Canvas {
+	c := new(Canvas)
+	c.Init()
+	c.SetSize(width, height)
+	c.frame.AddComponent(c)
+	return c
+}
+
+// Layout computes the rectangles of the nodes and updates the transforms of
+// their dax nodes.
+func (c *Canvas) Layout() {
+	c.rect = Rect{0, 0, c.size[0], c.size[1]}
+	c.rank = 0
+	rank := 0
+	c.arrange(&rank)
+
+	// Keep depths in [0, 1[, in front of the canvas plane.
+	c.updateTransforms(1 / float32(c.count()))
+}
+
+// Update implements dax.Updater. It lays out the canvas.
+func (c *Canvas) Update(dt float64) {
+	c.Layout()
+}
+
+// HitTest returns the node drawn on top at the point (x, y), in canvas space,
+// nil if there's none. Rotations are ignored.
+func (c *Canvas) HitTest(x, y float32) *Node {
+	hit := c.hitTest(x, y)
+	if hit == &c.Node {
+		return nil
+	}
+	return hit
+}
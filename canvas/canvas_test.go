@@ -0,0 +1,98 @@
+package canvas
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanvasLayout(t *testing.T) {
+	c := NewCanvas(800, 600)
+
+	panel := NewNode()
+	panel.SetAnchor(AnchorBottomRight)
+	panel.SetOffset(-10, -10)
+	panel.SetSize(200, 100)
+	panel.SetLayout(&HBox{Spacing: 10, Padding: 10})
+	c.AddChild(panel)
+
+	a, b := NewNode(), NewNode()
+	a.SetSize(50, 20)
+	a.SetAnchor(AnchorLeft)
+	b.SetSize(50, 20)
+	b.SetAnchor(AnchorStretch)
+	panel.AddChildren(a, b)
+
+	c.Layout()
+	assert.Equal(t, Rect{590, 490, 200, 100}, panel.Rect())
+	assert.Equal(t, Rect{600, 530, 50, 20}, a.Rect())
+	assert.Equal(t, Rect{660, 500, 50, 80}, b.Rect())
+
+	// The dax nodes follow the layout.
+	assert.Equal(t, &math.Vec3{10, 40, .25}, a.AsNode().GetPosition())
+	assert.Equal(t, &math.Vec3{50, 20, 1}, a.Content().GetScale())
+
+	// The canvas is laid out when the scene graph is updated.
+	c.SetSize(400, 300)
+	sg := dax.NewSceneGraph()
+	sg.AddChild(c.AsNode())
+	sg.Update(0)
+	assert.Equal(t, Rect{190, 190, 200, 100}, panel.Rect())
+}
+
+func TestCanvasZIndex(t *testing.T) {
+	c := NewCanvas(100, 100)
+	back, front := NewNode(), NewNode()
+	back.SetSize(50, 50)
+	front.SetSize(50, 50)
+	front.SetOffset(25, 25)
+	child := NewNode()
+	child.SetAnchor(AnchorStretch)
+	back.AddChild(child)
+
+	// Added first but drawn last.
+	front.SetZIndex(1)
+	c.AddChildren(front, back)
+	c.Layout()
+
+	// back, its child then front.
+	assert.True(t, back.rank < child.rank)
+	assert.True(t, child.rank < front.rank)
+	assert.Equal(t, float32(.75), front.AsNode().GetPosition()[2])
+
+	assert.Equal(t, front, c.HitTest(30, 30))
+	assert.Equal(t, child, c.HitTest(10, 10))
+	assert.Equal(t, front, c.HitTest(60, 60))
+	assert.Nil(t, c.HitTest(90, 10))
+}
+
+func TestCanvasRotation(t *testing.T) {
+	c := NewCanvas(100, 100)
+	n := NewNode()
+	n.SetSize(20, 10)
+	n.SetOffset(10, 10)
+	n.SetRotation(math.Pi)
+	c.AddChild(n)
+	c.Layout()
+
+	// Rotating by half a turn around the center moves the origin to the
+	// opposite corner.
+	assert.InDelta(t, 30, n.AsNode().GetPosition()[0], 1e-5)
+	assert.InDelta(t, 20, n.AsNode().GetPosition()[1], 1e-5)
+}
+
+func TestCanvasReparent(t *testing.T) {
+	c := NewCanvas(100, 100)
+	a, b := NewNode(), NewNode()
+	c.AddChildren(a, b)
+	b.AddChild(a)
+	assert.Equal(t, []*Node{b}, c.Children())
+	assert.Equal(t, b, a.Parent())
+	assert.Panics(t, func() { a.AddChild(b) })
+
+	b.RemoveChild(a)
+	assert.Nil(t, a.Parent())
+	assert.Equal(t, 0, len(b.Children()))
+}
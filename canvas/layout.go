@@ -0,0 +1,93 @@
+package canvas
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// Layout places the children of a container node. Arrange returns a slot, in
+// canvas space, for each child. Children are then anchored in their slot: in a
+// HBox, AnchorTop, AnchorLeft and AnchorBottom align a child vertically while
+// AnchorStretch makes it as high as the box.
+type Layout interface {
+	Arrange(bounds *Rect, children []*Node) []Rect
+}
+
+// HBox lines up children from left to right, each slot being as wide as the
+// child and as high as the box.
+type HBox struct {
+	// Spacing is the space between two children.
+	Spacing float32
+	// Padding is the space between the box edges and the children.
+	Padding float32
+}
+
+// Arrange implements Layout.
+func (b *HBox) Arrange(bounds *Rect, children []*Node) []Rect {
+	inner := bounds.Inset(b.Padding)
+	slots := make([]Rect, len(children))
+	x := inner.X
+	for i, child := range children {
+		slots[i] = Rect{x, inner.Y, child.size[0], inner.Height}
+		x += child.size[0] + b.Spacing
+	}
+	return slots
+}
+
+// VBox lines up children from top to bottom, each slot being as high as the
+// child and as wide as the box.
+type VBox struct {
+	// Spacing is the space between two children.
+	Spacing float32
+	// Padding is the space between the box edges and the children.
+	Padding float32
+}
+
+// Arrange implements Layout.
+func (b *VBox) Arrange(bounds *Rect, children []*Node) []Rect {
+	inner := bounds.Inset(b.Padding)
+	slots := make([]Rect, len(children))
+	y := inner.Y
+	for i, child := range children {
+		slots[i] = Rect{inner.X, y, inner.Width, child.size[1]}
+		y += child.size[1] + b.Spacing
+	}
+	return slots
+}
+
+// Grid splits the container in cells of the same size, filled row by row.
+type Grid struct {
+	// Columns is the number of cells per row, 1 if less than 1.
+	Columns int
+	// Spacing is the space between two cells.
+	Spacing float32
+	// Padding is the space between the grid edges and the cells.
+	Padding float32
+}
+
+// Arrange implements Layout.
+func (g *Grid) Arrange(bounds *Rect, children []*Node) []Rect {
+	columns := g.Columns
+	if columns < 1 {
+		columns = 1
+	}
+	rows := (len(children) + columns - 1) / columns
+	if rows == 0 {
+		return nil
+	}
+
+	inner := bounds.Inset(g.Padding)
+	width := math.Max(inner.Width-float32(columns-1)*g.Spacing, 0) / float32(columns)
+	height := math.Max(inner.Height-float32(rows-1)*g.Spacing, 0) / float32(rows)
+
+	slots := make([]Rect, len(children))
+	for i := range children {
+		column, row := i%columns, i/columns
+		slots[i] = Rect{
+			X:      inner.X + float32(column)*(width+g.Spacing),
+			Y:      inner.Y + float32(row)*(height+g.Spacing),
+			Width:  width,
+			Height: height,
+		}
+	}
+	return slots
+}
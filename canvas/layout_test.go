@@ -0,0 +1,43 @@
+package canvas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sizedNodes(sizes ...[2]float32) []*Node {
+	var nodes []*Node
+	for _, size := range sizes {
+		n := NewNode()
+		n.SetSize(size[0], size[1])
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func TestHBox(t *testing.T) {
+	box := &HBox{Spacing: 5, Padding: 10}
+	children := sizedNodes([2]float32{20, 10}, [2]float32{30, 10})
+	slots := box.Arrange(&Rect{0, 0, 200, 100}, children)
+	assert.Equal(t, []Rect{{10, 10, 20, 80}, {35, 10, 30, 80}}, slots)
+}
+
+func TestVBox(t *testing.T) {
+	box := &VBox{Spacing: 5, Padding: 10}
+	children := sizedNodes([2]float32{20, 10}, [2]float32{30, 20})
+	slots := box.Arrange(&Rect{0, 0, 200, 100}, children)
+	assert.Equal(t, []Rect{{10, 10, 180, 10}, {10, 25, 180, 20}}, slots)
+}
+
+func TestGrid(t *testing.T) {
+	grid := &Grid{Columns: 2, Spacing: 10, Padding: 5}
+	children := sizedNodes([2]float32{}, [2]float32{}, [2]float32{})
+	slots := grid.Arrange(&Rect{0, 0, 110, 110}, children)
+	assert.Equal(t, []Rect{
+		{5, 5, 45, 45}, {60, 5, 45, 45},
+		{5, 60, 45, 45},
+	}, slots)
+
+	assert.Nil(t, grid.Arrange(&Rect{0, 0, 110, 110}, nil))
+}
@@ -0,0 +1,257 @@
+package canvas
+
+import (
+	"sort"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Node is a 2D node of a Canvas. A node covers a rectangle, computed by
+// Canvas.Layout from the rectangle of its parent, its anchor, offset and size.
+// Children are drawn on top of their parent, in increasing z-index order.
+//
+// Each node drives two dax nodes: a frame, carrying the node position and
+// rotation and parenting the frames of its children, and its content, where
+// components drawing the node, eg. a MeshRenderer, are added. The content is
+// scaled to the node size: meshes are expected to fill the unit square from
+// (0, 0) to (1, 1).
+type Node struct {
+	parent   *Node
+	children []*Node
+	// ordered are the children sorted by z-index.
+	ordered []*Node
+
+	anchor   Anchor
+	offset   math.Vec2
+	size     math.Vec2
+	zIndex   int
+	rotation float32
+	layout   Layout
+
+	// rect is the node rectangle in canvas space, computed by Layout.
+	rect Rect
+	// rank is the position of the node in the drawing order.
+	rank int
+
+	frame   *dax.Node
+	content *dax.Node
+}
+
+// NewNode creates a new Node, anchored at the top left corner of its parent.
+func NewNode() *Node {
+	n := new(Node)
+	n.Init()
+	return n
+}
+
+// Init initializes the Node. Call this function first before anything else.
+func (n *Node) Init() {
+	n.frame = dax.NewNode()
+	n.content = dax.NewNode()
+	n.frame.AddChild(n.content)
+}
+
+// AsNode returns the dax node carrying the node transform, the one to add to a
+// scene graph.
+func (n *Node) AsNode() *dax.Node {
+	return n.frame
+}
+
+// Content returns the dax node to add the components drawing the node to.
+func (n *Node) Content() *dax.Node {
+	return n.content
+}
+
+// SetAnchor sets the point of the parent rectangle the node is attached to.
+func (n *Node) SetAnchor(anchor Anchor) {
+	n.anchor = anchor
+}
+
+// Anchor returns the anchor of the node.
+func (n *Node) Anchor() Anchor {
+	return n.anchor
+}
+
+// SetOffset sets the offset, in pixels, of the node from its anchor.
+func (n *Node) SetOffset(x, y float32) {
+	n.offset = math.Vec2{x, y}
+}
+
+// Offset returns the offset of the node from its anchor.
+func (n *Node) Offset() math.Vec2 {
+	return n.offset
+}
+
+// SetSize sets the size of the node, in pixels.
+func (n *Node) SetSize(width, height float32) {
+	n.size = math.Vec2{width, height}
+}
+
+// Size returns the size of the node.
+func (n *Node) Size() math.Vec2 {
+	return n.size
+}
+
+// SetZIndex sets the drawing order of the node among its siblings: nodes with
+// a greater z-index are drawn on top. Siblings with the same z-index are drawn
+// in the order they were added.
+func (n *Node) SetZIndex(z int) {
+	n.zIndex = z
+}
+
+// ZIndex returns the z-index of the node.
+func (n *Node) ZIndex() int {
+	return n.zIndex
+}
+
+// SetRotation rotates the node, and its children, by angle radians around its
+// center. Rotations don't change the layout.
+func (n *Node) SetRotation(angle float32) {
+	n.rotation = angle
+}
+
+// Rotation returns the rotation of the node, in radians.
+func (n *Node) Rotation() float32 {
+	return n.rotation
+}
+
+// SetLayout makes the node a container placing its children with layout. A
+// nil layout anchors children in the node rectangle.
+func (n *Node) SetLayout(layout Layout) {
+	n.layout = layout
+}
+
+// Rect returns the rectangle of the node in canvas space, as computed by the
+// last Canvas.Layout.
+func (n *Node) Rect() Rect {
+	return n.rect
+}
+
+// Parent returns the parent of the node, nil if it has none.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the children of the node, in the order they were added.
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// AddChild adds a child to the node. A child already parented to another node
+// is removed from its previous parent first.
+func (n *Node) AddChild(child *Node) {
+	for a := n; a != nil; a = a.parent {
+		if a == child {
+			panic("canvas: node cycle")
+		}
+	}
+
+	if child.parent != nil {
+		child.parent.RemoveChild(child)
+	}
+	child.parent = n
+	n.children = append(n.children, child)
+	n.frame.AddChild(child.frame)
+}
+
+// AddChildren adds a number of children to the node.
+func (n *Node) AddChildren(children ...*Node) {
+	for _, child := range children {
+		n.AddChild(child)
+	}
+}
+
+// RemoveChild removes child from the children of the node.
+func (n *Node) RemoveChild(child *Node) {
+	for i := range n.children {
+		if n.children[i] != child {
+			continue
+		}
+
+		children := make([]*Node, 0, len(n.children)-1)
+		children = append(children, n.children[:i]...)
+		n.children = append(children, n.children[i+1:]...)
+		child.parent = nil
+		n.frame.RemoveChild(child.frame)
+		return
+	}
+}
+
+// count returns the number of nodes in the subtree rooted at n.
+func (n *Node) count() int {
+	c := 1
+	for _, child := range n.children {
+		c += child.count()
+	}
+	return c
+}
+
+// arrange computes the rectangles of the children of n and ranks them in
+// drawing order, starting after *rank.
+func (n *Node) arrange(rank *int) {
+	var slots []Rect
+	if n.layout != nil {
+		slots = n.layout.Arrange(&n.rect, n.children)
+	}
+	for i, child := range n.children {
+		slot := &n.rect
+		if slots != nil {
+			slot = &slots[i]
+		}
+		child.rect = child.anchor.place(slot, &child.offset, &child.size)
+	}
+
+	n.ordered = append(n.ordered[:0], n.children...)
+	sort.SliceStable(n.ordered, func(i, j int) bool {
+		return n.ordered[i].zIndex < n.ordered[j].zIndex
+	})
+	for _, child := range n.ordered {
+		*rank++
+		child.rank = *rank
+		child.arrange(rank)
+	}
+}
+
+// updateTransforms sets the transforms of the dax nodes from the layout. The
+// drawing order is given by the depth of the nodes: depthStep apart for two
+// consecutive ranks.
+func (n *Node) updateTransforms(depthStep float32) {
+	var position math.Vec3
+	if n.parent != nil {
+		position = math.Vec3{
+			n.rect.X - n.parent.rect.X,
+			n.rect.Y - n.parent.rect.Y,
+			float32(n.rank-n.parent.rank) * depthStep,
+		}
+	}
+
+	// Rotate around the center of the node.
+	rotation := math.QuatRotate(n.rotation, &math.Vec3{0, 0, 1})
+	center := math.Vec3{n.rect.Width / 2, n.rect.Height / 2, 0}
+	rotated := rotation.Rotate(&center)
+	position.AddWith(&center)
+	position.SubWith(&rotated)
+
+	n.frame.SetPositionV(&position)
+	n.frame.SetRotation(&rotation)
+	n.content.SetScale(n.rect.Width, n.rect.Height, 1)
+
+	for _, child := range n.children {
+		child.updateTransforms(depthStep)
+	}
+}
+
+// hitTest returns the top most node of the subtree rooted at n containing the
+// point (x, y).
+func (n *Node) hitTest(x, y float32) *Node {
+	for i := len(n.ordered) - 1; i >= 0; i-- {
+		if hit := n.ordered[i].hitTest(x, y); hit != nil {
+			return hit
+		}
+	}
+	if n.rect.Contains(x, y) {
+		return n
+	}
+	return nil
+}
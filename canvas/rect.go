@@ -0,0 +1,96 @@
+package canvas
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Rect is a rectangle, in pixels. Canvas space has its origin at the top left
+// corner, with Y going down.
+type Rect struct {
+	X, Y, Width, Height float32
+}
+
+// Contains returns true if the point (x, y) is inside the rectangle.
+func (r *Rect) Contains(x, y float32) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Inset returns the rectangle shrunk by d on all sides. The size of the result
+// doesn't go below 0.
+func (r *Rect) Inset(d float32) Rect {
+	return Rect{
+		X:      r.X + d,
+		Y:      r.Y + d,
+		Width:  math.Max(r.Width-2*d, 0),
+		Height: math.Max(r.Height-2*d, 0),
+	}
+}
+
+// RectFromViewport converts v, a rectangle of a framebuffer of the given
+// height with its origin at the bottom left corner, to canvas space. It's
+// used to lay out user interfaces in part of the framebuffer, eg. the safe
+// area of a letterboxing camera:
+//
+//	safe := camera.SafeArea(.05)
+//	rect := canvas.RectFromViewport(&safe, height)
+func RectFromViewport(v *dax.Viewport, height int) Rect {
+	return Rect{
+		X:      float32(v.X),
+		Y:      float32(height - v.Y - v.Height),
+		Width:  float32(v.Width),
+		Height: float32(v.Height),
+	}
+}
+
+// Anchor is the point of its parent rectangle a node is attached to.
+type Anchor int
+
+const (
+	// AnchorTopLeft attaches the top left corner of the node to the top
+	// left corner of its parent.
+	AnchorTopLeft Anchor = iota
+	// AnchorTop centers the node horizontally at the top of its parent.
+	AnchorTop
+	// AnchorTopRight attaches the node to the top right corner of its
+	// parent.
+	AnchorTopRight
+	// AnchorLeft centers the node vertically on the left of its parent.
+	AnchorLeft
+	// AnchorCenter centers the node in its parent.
+	AnchorCenter
+	// AnchorRight centers the node vertically on the right of its parent.
+	AnchorRight
+	// AnchorBottomLeft attaches the node to the bottom left corner of its
+	// parent.
+	AnchorBottomLeft
+	// AnchorBottom centers the node horizontally at the bottom of its
+	// parent.
+	AnchorBottom
+	// AnchorBottomRight attaches the node to the bottom right corner of its
+	// parent.
+	AnchorBottomRight
+	// AnchorStretch makes the node fill its parent, ignoring its size and
+	// offset.
+	AnchorStretch
+)
+
+// place returns the rectangle of a node of size anchored in parent and moved
+// by offset.
+func (a Anchor) place(parent *Rect, offset, size *math.Vec2) Rect {
+	if a == AnchorStretch {
+		return *parent
+	}
+
+	// Where the anchor is in the parent, from 0 (left, top) to 1 (right,
+	// bottom).
+	fx := float32(int(a)%3) / 2
+	fy := float32(int(a)/3) / 2
+
+	return Rect{
+		X:      parent.X + fx*(parent.Width-size[0]) + offset[0],
+		Y:      parent.Y + fy*(parent.Height-size[1]) + offset[1],
+		Width:  size[0],
+		Height: size[1],
+	}
+}
@@ -0,0 +1,55 @@
+package canvas
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnchorPlace(t *testing.T) {
+	parent := Rect{10, 20, 100, 50}
+	size := math.Vec2{20, 10}
+	offset := math.Vec2{1, 2}
+
+	tests := []struct {
+		anchor   Anchor
+		expected Rect
+	}{
+		{AnchorTopLeft, Rect{11, 22, 20, 10}},
+		{AnchorTop, Rect{51, 22, 20, 10}},
+		{AnchorTopRight, Rect{91, 22, 20, 10}},
+		{AnchorLeft, Rect{11, 42, 20, 10}},
+		{AnchorCenter, Rect{51, 42, 20, 10}},
+		{AnchorRight, Rect{91, 42, 20, 10}},
+		{AnchorBottomLeft, Rect{11, 62, 20, 10}},
+		{AnchorBottom, Rect{51, 62, 20, 10}},
+		{AnchorBottomRight, Rect{91, 62, 20, 10}},
+		{AnchorStretch, parent},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.anchor.place(&parent, &offset, &size),
+			"anchor %d", test.anchor)
+	}
+}
+
+func TestRect(t *testing.T) {
+	r := Rect{10, 20, 100, 50}
+	assert.True(t, r.Contains(10, 20))
+	assert.True(t, r.Contains(109, 69))
+	assert.False(t, r.Contains(110, 30))
+	assert.False(t, r.Contains(50, 19))
+
+	assert.Equal(t, Rect{15, 25, 90, 40}, r.Inset(5))
+	assert.Equal(t, Rect{40, 50, 40, 0}, r.Inset(30))
+}
+
+func TestRectFromViewport(t *testing.T) {
+	v := dax.Viewport{X: 40, Y: 98, Width: 720, Height: 404}
+	assert.Equal(t, Rect{40, 98, 720, 404}, RectFromViewport(&v, 600))
+
+	v = dax.Viewport{X: 0, Y: 0, Width: 100, Height: 50}
+	assert.Equal(t, Rect{0, 550, 100, 50}, RectFromViewport(&v, 600))
+}
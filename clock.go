@@ -0,0 +1,51 @@
+package dax
+
+import (
+	"time"
+)
+
+// maxFrameTime caps the time elapsed between two frames, so a long pause, eg.
+// when the process was stopped in a debugger, doesn't make animations jump.
+const maxFrameTime = 0.25
+
+// clock measures the time elapsed between frames.
+type clock struct {
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+
+	last  time.Time
+	dt    float64
+	total float64
+}
+
+func newClock() clock {
+	return clock{
+		now: time.Now,
+	}
+}
+
+// start starts the clock.
+func (c *clock) start() {
+	c.last = c.now()
+	c.dt = 0
+	c.total = 0
+}
+
+// tick is called at the start of each frame and returns the number of seconds
+// elapsed since the previous frame.
+func (c *clock) tick() float64 {
+	now := c.now()
+	dt := now.Sub(c.last).Seconds()
+	c.last = now
+
+	if dt > maxFrameTime {
+		dt = maxFrameTime
+	}
+	if dt < 0 {
+		dt = 0
+	}
+
+	c.dt = dt
+	c.total += dt
+	return dt
+}
@@ -0,0 +1,35 @@
+package dax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := newClock()
+	c.now = func() time.Time { return now }
+
+	c.start()
+	assert.Equal(t, 0., c.total)
+
+	now = now.Add(16 * time.Millisecond)
+	assert.InDelta(t, .016, c.tick(), 1e-9)
+	now = now.Add(20 * time.Millisecond)
+	assert.InDelta(t, .020, c.tick(), 1e-9)
+	assert.InDelta(t, .020, c.dt, 1e-9)
+	assert.InDelta(t, .036, c.total, 1e-9)
+
+	// Long pauses are capped.
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, maxFrameTime, c.tick())
+	assert.InDelta(t, .036+maxFrameTime, c.total, 1e-9)
+
+	// Restarting resets the total time.
+	c.start()
+	assert.Equal(t, 0., c.total)
+	now = now.Add(10 * time.Millisecond)
+	assert.InDelta(t, .010, c.tick(), 1e-9)
+}
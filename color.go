@@ -137,3 +137,14 @@ func (color *Color) ToHSL() (h, s, l float32) {
 func (color *Color) Vec4() math.Vec4 {
 	return math.Vec4{color.R, color.G, color.B, color.A}
 }
+
+// Lerp interpolates between color, at amount 0, and c2, at amount 1,
+// component by component.
+func (color *Color) Lerp(c2 *Color, amount float32) Color {
+	return Color{
+		R: color.R + (c2.R-color.R)*amount,
+		G: color.G + (c2.G-color.G)*amount,
+		B: color.B + (c2.B-color.B)*amount,
+		A: color.A + (c2.A-color.A)*amount,
+	}
+}
@@ -70,3 +70,12 @@ func TestColorVec4(t *testing.T) {
 	assert.Equal(t, float32(3.0), v[2])
 	assert.Equal(t, float32(4.0), v[3])
 }
+
+func TestColorLerp(t *testing.T) {
+	c1 := Color{0, 1, .5, 1}
+	c2 := Color{1, 0, .5, 0}
+
+	assert.Equal(t, c1, c1.Lerp(&c2, 0))
+	assert.Equal(t, c2, c1.Lerp(&c2, 1))
+	assert.Equal(t, Color{.25, .75, .5, .75}, c1.Lerp(&c2, .25))
+}
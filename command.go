@@ -0,0 +1,214 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// Command is an operation that can be undone.
+type Command interface {
+	Do()
+	Undo()
+}
+
+// CommandMerger is implemented by commands that can be coalesced with the
+// command that follows them, eg. the many small moves making up the drag of
+// an object, so they can be undone in one go.
+type CommandMerger interface {
+	// Merge tries to fold next, which has already been executed, into the
+	// receiver. It returns false if the commands can't be merged.
+	Merge(next Command) bool
+}
+
+// CommandStack records executed commands to undo and redo them.
+type CommandStack struct {
+	done   []Command
+	undone []Command
+	// mergeable is false when the last command shouldn't be merged with
+	// the next one.
+	mergeable bool
+	// Limit is the maximum number of commands kept in the undo history. 0
+	// means no limit.
+	Limit int
+}
+
+// NewCommandStack creates an empty CommandStack.
+func NewCommandStack() *CommandStack {
+	return new(CommandStack)
+}
+
+// Do executes c and pushes it on the undo stack. Executing a command clears
+// the redo history.
+func (s *CommandStack) Do(c Command) {
+	c.Do()
+	s.undone = nil
+
+	if n := len(s.done); n > 0 && s.mergeable {
+		if m, ok := s.done[n-1].(CommandMerger); ok && m.Merge(c) {
+			return
+		}
+	}
+
+	s.done = append(s.done, c)
+	s.mergeable = true
+
+	if s.Limit > 0 && len(s.done) > s.Limit {
+		s.done = s.done[len(s.done)-s.Limit:]
+	}
+}
+
+// EndMerge prevents the last executed command from being merged with the next
+// one. Call it at the end of an interaction, eg. when the mouse button is
+// released after dragging an object.
+func (s *CommandStack) EndMerge() {
+	s.mergeable = false
+}
+
+// CanUndo returns true if there's a command to undo.
+func (s *CommandStack) CanUndo() bool {
+	return len(s.done) > 0
+}
+
+// CanRedo returns true if there's a command to redo.
+func (s *CommandStack) CanRedo() bool {
+	return len(s.undone) > 0
+}
+
+// Undo undoes the last executed command. It returns false if there was
+// nothing to undo.
+func (s *CommandStack) Undo() bool {
+	n := len(s.done)
+	if n == 0 {
+		return false
+	}
+
+	c := s.done[n-1]
+	s.done = s.done[:n-1]
+	c.Undo()
+	s.undone = append(s.undone, c)
+	s.mergeable = false
+
+	return true
+}
+
+// Redo executes again the last undone command. It returns false if there was
+// nothing to redo.
+func (s *CommandStack) Redo() bool {
+	n := len(s.undone)
+	if n == 0 {
+		return false
+	}
+
+	c := s.undone[n-1]
+	s.undone = s.undone[:n-1]
+	c.Do()
+	s.done = append(s.done, c)
+	s.mergeable = false
+
+	return true
+}
+
+// Clear empties the undo and redo histories.
+func (s *CommandStack) Clear() {
+	s.done = nil
+	s.undone = nil
+	s.mergeable = false
+}
+
+type nodeTRS struct {
+	position math.Vec3
+	rotation math.Quaternion
+	scale    math.Vec3
+}
+
+func (t *nodeTRS) apply(n *Node) {
+	n.SetPositionV(&t.position)
+	n.SetRotation(&t.rotation)
+	n.SetScaleV(&t.scale)
+}
+
+// TransformCommand changes the position, rotation and scale of a node.
+// Consecutive TransformCommands on the same node are merged.
+type TransformCommand struct {
+	node     *Node
+	from, to nodeTRS
+}
+
+// NewTransformCommand creates a command setting the position, rotation and
+// scale of n.
+func NewTransformCommand(n *Node, position *math.Vec3, rotation *math.Quaternion, scale *math.Vec3) *TransformCommand {
+	return &TransformCommand{
+		node: n,
+		to: nodeTRS{
+			position: *position,
+			rotation: *rotation,
+			scale:    *scale,
+		},
+	}
+}
+
+// Do implements Command.
+func (c *TransformCommand) Do() {
+	c.from = nodeTRS{
+		position: c.node.position,
+		rotation: c.node.rotation,
+		scale:    c.node.scale,
+	}
+	c.to.apply(c.node)
+}
+
+// Undo implements Command.
+func (c *TransformCommand) Undo() {
+	c.from.apply(c.node)
+}
+
+// Merge implements CommandMerger.
+func (c *TransformCommand) Merge(next Command) bool {
+	n, ok := next.(*TransformCommand)
+	if !ok || n.node != c.node {
+		return false
+	}
+	c.to = n.to
+	return true
+}
+
+// AddChildCommand adds a child to a node. Undoing it gives the child back to
+// its previous parent, at its previous place among its siblings.
+type AddChildCommand struct {
+	parent, child *Node
+
+	// previous and index are the parent of child before Do and the index of
+	// child in its children.
+	previous *Node
+	index    int
+}
+
+// NewAddChildCommand creates a command adding child to parent.
+func NewAddChildCommand(parent, child *Node) *AddChildCommand {
+	return &AddChildCommand{
+		parent: parent,
+		child:  child,
+	}
+}
+
+// Do implements Command.
+func (c *AddChildCommand) Do() {
+	c.previous = c.child.parentNode()
+	c.index = 0
+	if c.previous != nil {
+		for i, child := range c.previous.children {
+			if child == Grapher(c.child) {
+				c.index = i
+				break
+			}
+		}
+	}
+	c.parent.AddChild(c.child)
+}
+
+// Undo implements Command.
+func (c *AddChildCommand) Undo() {
+	c.parent.RemoveChild(c.child)
+	if c.previous != nil {
+		c.previous.insertChild(c.child, c.index)
+	}
+}
@@ -0,0 +1,129 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCommand appends to a slice to keep track of what's been done.
+type testCommand struct {
+	values *[]int
+	value  int
+}
+
+func (c *testCommand) Do() {
+	*c.values = append(*c.values, c.value)
+}
+
+func (c *testCommand) Undo() {
+	*c.values = (*c.values)[:len(*c.values)-1]
+}
+
+func TestCommandStack(t *testing.T) {
+	var values []int
+	s := NewCommandStack()
+
+	assert.False(t, s.CanUndo())
+	assert.False(t, s.Undo())
+	assert.False(t, s.Redo())
+
+	s.Do(&testCommand{&values, 1})
+	s.Do(&testCommand{&values, 2})
+	assert.Equal(t, []int{1, 2}, values)
+
+	assert.True(t, s.Undo())
+	assert.Equal(t, []int{1}, values)
+	assert.True(t, s.CanRedo())
+
+	assert.True(t, s.Redo())
+	assert.Equal(t, []int{1, 2}, values)
+
+	// Doing a new command clears the redo history.
+	s.Undo()
+	s.Do(&testCommand{&values, 3})
+	assert.Equal(t, []int{1, 3}, values)
+	assert.False(t, s.CanRedo())
+
+	s.Clear()
+	assert.False(t, s.CanUndo())
+}
+
+func TestCommandStackLimit(t *testing.T) {
+	var values []int
+	s := NewCommandStack()
+	s.Limit = 2
+
+	for i := 0; i < 4; i++ {
+		s.Do(&testCommand{&values, i})
+	}
+
+	assert.True(t, s.Undo())
+	assert.True(t, s.Undo())
+	assert.False(t, s.Undo())
+	assert.Equal(t, []int{0, 1}, values)
+}
+
+func TestTransformCommand(t *testing.T) {
+	n := NewNode()
+	s := NewCommandStack()
+	rotation := n.rotation
+	scale := n.scale
+
+	// Consecutive moves are merged into a single undo step.
+	for i := 1; i <= 3; i++ {
+		s.Do(NewTransformCommand(n, &math.Vec3{float32(i), 0, 0}, &rotation, &scale))
+	}
+	s.EndMerge()
+	s.Do(NewTransformCommand(n, &math.Vec3{5, 0, 0}, &rotation, &scale))
+	assertVec3(t, &math.Vec3{5, 0, 0}, n.GetPosition(), 1e-6)
+
+	s.Undo()
+	assertVec3(t, &math.Vec3{3, 0, 0}, n.GetPosition(), 1e-6)
+	s.Undo()
+	assertVec3(t, &math.Vec3{0, 0, 0}, n.GetPosition(), 1e-6)
+	assert.False(t, s.CanUndo())
+
+	s.Redo()
+	assertVec3(t, &math.Vec3{3, 0, 0}, n.GetPosition(), 1e-6)
+}
+
+func TestAddChildCommand(t *testing.T) {
+	parent, child := NewNode(), NewNode()
+	s := NewCommandStack()
+
+	s.Do(NewAddChildCommand(parent, child))
+	assert.Equal(t, []Grapher{child}, parent.GetChildren())
+	assert.Equal(t, parent, child.GetParent())
+
+	s.Undo()
+	assert.Empty(t, parent.GetChildren())
+	assert.Nil(t, child.GetParent())
+}
+
+func TestAddChildCommandReparent(t *testing.T) {
+	previous, parent := NewNode(), NewNode()
+	first, child, last := NewNode(), NewNode(), NewNode()
+	previous.AddChildren(first, child, last)
+	s := NewCommandStack()
+
+	// Undoing gives the child back to its previous parent, in its place.
+	s.Do(NewAddChildCommand(parent, child))
+	assert.Equal(t, []Grapher{first, last}, previous.GetChildren())
+	s.Undo()
+	assert.Empty(t, parent.GetChildren())
+	assert.Equal(t, []Grapher{first, child, last}, previous.GetChildren())
+	assert.Equal(t, previous, child.GetParent())
+
+	s.Redo()
+	assert.Equal(t, []Grapher{child}, parent.GetChildren())
+	assert.Equal(t, []Grapher{first, last}, previous.GetChildren())
+
+	// Moving a child among its siblings.
+	s.Undo()
+	s.Do(NewAddChildCommand(previous, first))
+	assert.Equal(t, []Grapher{child, last, first}, previous.GetChildren())
+	s.Undo()
+	assert.Equal(t, []Grapher{first, child, last}, previous.GetChildren())
+}
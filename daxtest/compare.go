@@ -0,0 +1,112 @@
+package daxtest
+
+import (
+	"image"
+	"image/color"
+)
+
+// Tolerance is how different two images can be while still being considered
+// the same.
+type Tolerance struct {
+	// Threshold is the largest perceived color difference, in [0, 1],
+	// between two pixels considered the same. Differences are measured in
+	// the YIQ color space, weighting the luma more than the chroma like
+	// the human eye does.
+	Threshold float64
+	// MaxDiffRatio is the ratio of differing pixels, in [0, 1], allowed
+	// before two images are considered different, eg. to absorb the
+	// rasterization differences of GPU drivers.
+	MaxDiffRatio float64
+}
+
+// DefaultTolerance ignores slight color changes, such as rounding
+// differences, and a few differing pixels.
+var DefaultTolerance = Tolerance{
+	Threshold:    .1,
+	MaxDiffRatio: .001,
+}
+
+// Comparison is the result of Compare.
+type Comparison struct {
+	// DiffPixels is the number of pixels differing by more than the
+	// threshold.
+	DiffPixels int
+	// DiffRatio is DiffPixels over the number of pixels.
+	DiffRatio float64
+	// Diff is a faded version of the expected image with the differing
+	// pixels in red.
+	Diff *image.RGBA
+	// SizeMismatch is true when the images don't have the same size.
+	// Nothing else is compared then.
+	SizeMismatch bool
+}
+
+// Equal returns true if the images compared are the same within tolerance.
+func (c *Comparison) Equal(tolerance Tolerance) bool {
+	return !c.SizeMismatch && c.DiffRatio <= tolerance.MaxDiffRatio
+}
+
+// maxYIQDelta is the largest yiqDelta, between black and white.
+const maxYIQDelta = 35215
+
+// Compare compares got to want, pixel by pixel, ignoring differences under
+// tolerance.Threshold.
+func Compare(got, want image.Image, tolerance Tolerance) *Comparison {
+	bounds := want.Bounds()
+	if got.Bounds().Dx() != bounds.Dx() || got.Bounds().Dy() != bounds.Dy() {
+		return &Comparison{SizeMismatch: true}
+	}
+
+	c := &Comparison{
+		Diff: image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy())),
+	}
+	maxDelta := maxYIQDelta * tolerance.Threshold * tolerance.Threshold
+	offset := got.Bounds().Min.Sub(bounds.Min)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			w := toRGB(want.At(x, y))
+			g := toRGB(got.At(x+offset.X, y+offset.Y))
+
+			var diff color.RGBA
+			if yiqDelta(g, w) > maxDelta {
+				c.DiffPixels++
+				diff = color.RGBA{255, 0, 0, 255}
+			} else {
+				// Faded luma of the expected pixel, for context.
+				l := uint8(255 - (255-luma(w))*.1)
+				diff = color.RGBA{l, l, l, 255}
+			}
+			c.Diff.SetRGBA(x-bounds.Min.X, y-bounds.Min.Y, diff)
+		}
+	}
+
+	c.DiffRatio = float64(c.DiffPixels) / float64(bounds.Dx()*bounds.Dy())
+	return c
+}
+
+// toRGB returns the color c blended over white, with channels in [0, 255].
+func toRGB(c color.Color) [3]float64 {
+	r, g, b, a := c.RGBA()
+	// RGBA returns alpha premultiplied channels in [0, 0xffff].
+	white := float64(0xffff - a)
+	return [3]float64{
+		(float64(r) + white) / 0x101,
+		(float64(g) + white) / 0x101,
+		(float64(b) + white) / 0x101,
+	}
+}
+
+func luma(c [3]float64) float64 {
+	return c[0]*.29889531 + c[1]*.58662247 + c[2]*.11448223
+}
+
+// yiqDelta returns the squared perceived distance between two colors, from
+// "Measuring perceived color difference using YIQ NTSC transmission color
+// space in mobile applications" by Kotsarenko and Ramos.
+func yiqDelta(c1, c2 [3]float64) float64 {
+	y := luma(c1) - luma(c2)
+	i := (c1[0]-c2[0])*.59597799 - (c1[1]-c2[1])*.27417610 - (c1[2]-c2[2])*.32180189
+	q := (c1[0]-c2[0])*.21147017 - (c1[1]-c2[1])*.52261711 + (c1[2]-c2[2])*.31114694
+	return .5053*y*y + .299*i*i + .1957*q*q
+}
@@ -0,0 +1,57 @@
+package daxtest
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uniformImage(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	want := uniformImage(10, 10, color.RGBA{100, 150, 200, 255})
+
+	// Slight differences are ignored.
+	got := uniformImage(10, 10, color.RGBA{101, 150, 199, 255})
+	c := Compare(got, want, DefaultTolerance)
+	assert.Equal(t, 0, c.DiffPixels)
+	assert.True(t, c.Equal(DefaultTolerance))
+
+	// A differing pixel.
+	got.SetRGBA(3, 4, color.RGBA{255, 0, 0, 255})
+	c = Compare(got, want, DefaultTolerance)
+	assert.Equal(t, 1, c.DiffPixels)
+	assert.Equal(t, .01, c.DiffRatio)
+	assert.False(t, c.Equal(DefaultTolerance))
+	assert.True(t, c.Equal(Tolerance{Threshold: .1, MaxDiffRatio: .01}))
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, c.Diff.RGBAAt(3, 4))
+	assert.NotEqual(t, color.RGBA{255, 0, 0, 255}, c.Diff.RGBAAt(4, 4))
+
+	// A threshold of 1 accepts anything.
+	black := uniformImage(10, 10, color.RGBA{0, 0, 0, 255})
+	white := uniformImage(10, 10, color.RGBA{255, 255, 255, 255})
+	assert.Equal(t, 0, Compare(black, white, Tolerance{Threshold: 1}).DiffPixels)
+	assert.Equal(t, 100, Compare(black, white, Tolerance{Threshold: .9}).DiffPixels)
+
+	// Images of different sizes.
+	c = Compare(uniformImage(5, 10, color.RGBA{}), want, DefaultTolerance)
+	assert.True(t, c.SizeMismatch)
+	assert.False(t, c.Equal(Tolerance{Threshold: 1, MaxDiffRatio: 1}))
+}
+
+func TestCompareTransparent(t *testing.T) {
+	// Transparent pixels are compared blended over white.
+	transparent := uniformImage(1, 1, color.RGBA{})
+	white := uniformImage(1, 1, color.RGBA{255, 255, 255, 255})
+	assert.Equal(t, 0, Compare(transparent, white, Tolerance{}).DiffPixels)
+}
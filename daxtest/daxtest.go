@@ -0,0 +1,60 @@
+// Package daxtest helps testing what dax scenes draw by comparing frames to
+// golden images, reference PNG files stored with the tests:
+//
+//	func TestMyScene(t *testing.T) {
+//		daxtest.AssertScene(t, &MyScene{}, 320, 240, "testdata/my_scene.png",
+//			daxtest.DefaultTolerance)
+//	}
+//
+// Golden images are written, instead of compared, when the tests are run with
+// the -daxtest.update flag. On a mismatch, the frame and an image
+// highlighting the differences are written next to the golden image, with
+// .got.png and .diff.png extensions.
+//
+// Rendering needs a GL context: scenes are drawn with a hidden window, created
+// on first use, and AssertScene skips the tests without one. As for any dax
+// program, tests must run on the main thread, which dax locks in its init
+// function.
+package daxtest
+
+import (
+	"image"
+	"os"
+	"testing"
+
+	"github.com/dlespiau/dax"
+)
+
+var window *dax.Window
+
+// Render draws one frame of s in an offscreen framebuffer of size
+// width x height, after setting it up and updating it once, and returns it.
+// The scene is torn down before returning.
+func Render(s dax.Scener, width, height int) *image.RGBA {
+	if window == nil {
+		app := dax.NewApplication("daxtest")
+		window = app.NewWindow("daxtest", width, height, dax.WindowOptions{
+			Hidden: true,
+		})
+	}
+	return window.RenderScene(s, width, height)
+}
+
+// AssertScene renders s with Render and compares the frame to the golden
+// image at path with AssertGolden. A missing golden image is an error, unless
+// updating, and the test is skipped when there's no GL context.
+func AssertScene(t testing.TB, s dax.Scener, width, height int, path string, tolerance Tolerance) bool {
+	t.Helper()
+
+	if !*update {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Errorf("daxtest: no golden image %s, run the test with -daxtest.update to create it", path)
+			return false
+		}
+	}
+	if err := dax.InitError(); err != nil {
+		t.Skipf("daxtest: no GL context: %v", err)
+	}
+
+	return AssertGolden(t, Render(s, width, height), path, tolerance)
+}
@@ -0,0 +1,88 @@
+package daxtest
+
+import (
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("daxtest.update", false, "write golden images instead of comparing them")
+
+// AssertGolden compares img to the golden image at path, a PNG file, and
+// reports a test error if they differ by more than tolerance. The frame and
+// the differences are then written next to the golden image, with .got.png
+// and .diff.png extensions. With the -daxtest.update flag, img is written to
+// path instead.
+func AssertGolden(t testing.TB, img image.Image, path string, tolerance Tolerance) bool {
+	t.Helper()
+
+	if *update {
+		if err := writePNG(path, img); err != nil {
+			t.Fatalf("daxtest: %v", err)
+		}
+		return true
+	}
+
+	want, err := readPNG(path)
+	if os.IsNotExist(err) {
+		t.Errorf("daxtest: no golden image %s, run the test with -daxtest.update to create it", path)
+		return false
+	}
+	if err != nil {
+		t.Fatalf("daxtest: %v", err)
+	}
+
+	c := Compare(img, want, tolerance)
+	if c.Equal(tolerance) {
+		return true
+	}
+
+	base := strings.TrimSuffix(path, ".png")
+	if err := writePNG(base+".got.png", img); err != nil {
+		t.Errorf("daxtest: %v", err)
+	}
+	if c.SizeMismatch {
+		t.Errorf("daxtest: %s: size is %v, expected %v (got %s.got.png)",
+			path, img.Bounds().Size(), want.Bounds().Size(), base)
+		return false
+	}
+	if err := writePNG(base+".diff.png", c.Diff); err != nil {
+		t.Errorf("daxtest: %v", err)
+	}
+	t.Errorf("daxtest: %s: %d pixels (%.2f%%) differ (got %s.got.png, diff %s.diff.png)",
+		path, c.DiffPixels, c.DiffRatio*100, base, base)
+	return false
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return png.Decode(f)
+}
+
+// writePNG writes img to path, creating the directory of path if needed, eg.
+// when writing the first golden image of a testdata directory.
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
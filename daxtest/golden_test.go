@@ -0,0 +1,61 @@
+package daxtest
+
+import (
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the errors of AssertGolden.
+type recorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors++
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "daxtest")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	golden := filepath.Join(dir, "scene.png")
+	img := uniformImage(4, 4, color.RGBA{10, 20, 30, 255})
+
+	// No golden image yet.
+	r := &recorder{TB: t}
+	assert.False(t, AssertGolden(r, img, golden, DefaultTolerance))
+	assert.Equal(t, 1, r.errors)
+
+	*update = true
+	assert.True(t, AssertGolden(t, img, golden, DefaultTolerance))
+	*update = false
+
+	r = &recorder{TB: t}
+	assert.True(t, AssertGolden(r, img, golden, DefaultTolerance))
+	assert.Equal(t, 0, r.errors)
+
+	// A mismatch writes the frame and the differences.
+	img.SetRGBA(1, 1, color.RGBA{255, 255, 255, 255})
+	assert.False(t, AssertGolden(r, img, golden, DefaultTolerance))
+	assert.Equal(t, 1, r.errors)
+	_, err = os.Stat(filepath.Join(dir, "scene.got.png"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "scene.diff.png"))
+	assert.Nil(t, err)
+
+	// Updating creates the directory of the golden image.
+	*update = true
+	assert.True(t, AssertGolden(t, img, filepath.Join(dir, "testdata", "scene.png"), DefaultTolerance))
+	*update = false
+	_, err = os.Stat(filepath.Join(dir, "testdata", "scene.png"))
+	assert.Nil(t, err)
+}
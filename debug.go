@@ -0,0 +1,118 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// DebugDraw buffers lines drawn on top of the next frame, eg. to visualize
+// transforms, bounds or physics while debugging. Lines can be added from
+// anywhere, usually the Update methods, and only last one frame: they have to
+// be added again every frame to stay visible.
+//
+//	bounds := node.WorldBounds()
+//	dax.Debug.DrawAABB(&bounds, &dax.Color{R: 1, G: 1, B: 0, A: 1})
+//	dax.Debug.DrawAxis(node.GetTransform(), 1)
+//
+// Lines are in world space and seen through the camera of the scene of each
+// window. They are drawn after the scene and its post-processing, before the
+// overlays, without depth test.
+type DebugDraw struct {
+	positions []float32
+	colors    []float32
+
+	// cache is the mesh drawn, kept between frames.
+	cache *Mesh
+}
+
+// Debug is the buffer of debug lines drawn by the windows at the end of each
+// frame.
+var Debug = &DebugDraw{}
+
+// DrawLine draws a line between a and b.
+func (d *DebugDraw) DrawLine(a, b *math.Vec3, color *Color) {
+	d.positions = append(d.positions, a[0], a[1], a[2], b[0], b[1], b[2])
+	d.colors = append(d.colors,
+		color.R, color.G, color.B, color.A,
+		color.R, color.G, color.B, color.A)
+}
+
+// DrawAABB draws the edges of box.
+func (d *DebugDraw) DrawAABB(box *math.AABB, color *Color) {
+	min, max := &box.Min, &box.Max
+	corners := [8]math.Vec3{
+		{min[0], min[1], min[2]},
+		{max[0], min[1], min[2]},
+		{max[0], max[1], min[2]},
+		{min[0], max[1], min[2]},
+		{min[0], min[1], max[2]},
+		{max[0], min[1], max[2]},
+		{max[0], max[1], max[2]},
+		{min[0], max[1], max[2]},
+	}
+	for i := 0; i < 4; i++ {
+		j := (i + 1) % 4
+		// Edges of the back face, of the front face, and between them.
+		d.DrawLine(&corners[i], &corners[j], color)
+		d.DrawLine(&corners[i+4], &corners[j+4], color)
+		d.DrawLine(&corners[i], &corners[i+4], color)
+	}
+}
+
+// DrawAxis draws the X, Y and Z axes of transform, in red, green and blue, size
+// units long.
+func (d *DebugDraw) DrawAxis(transform *math.Mat4, size float32) {
+	o := transform.Mul4x1(&math.Vec4{0, 0, 0, 1})
+	origin := o.Vec3()
+	axes := [3]struct {
+		tip   math.Vec4
+		color Color
+	}{
+		{math.Vec4{size, 0, 0, 1}, Color{1, 0, 0, 1}},
+		{math.Vec4{0, size, 0, 1}, Color{0, 1, 0, 1}},
+		{math.Vec4{0, 0, size, 1}, Color{0, 0, 1, 1}},
+	}
+	for i := range axes {
+		e := transform.Mul4x1(&axes[i].tip)
+		end := e.Vec3()
+		d.DrawLine(&origin, &end, &axes[i].color)
+	}
+}
+
+// DrawGrid draws a grid on the XZ plane, centered on the origin, size units
+// wide with divisions cells per side.
+func (d *DebugDraw) DrawGrid(size float32, divisions int, color *Color) {
+	if divisions <= 0 {
+		return
+	}
+
+	half := size / 2
+	step := size / float32(divisions)
+	for i := 0; i <= divisions; i++ {
+		p := -half + float32(i)*step
+		d.DrawLine(&math.Vec3{p, 0, -half}, &math.Vec3{p, 0, half}, color)
+		d.DrawLine(&math.Vec3{-half, 0, p}, &math.Vec3{half, 0, p}, color)
+	}
+}
+
+// Len returns the number of lines buffered.
+func (d *DebugDraw) Len() int {
+	return len(d.positions) / 6
+}
+
+// Clear removes the buffered lines. The application clears Debug once all the
+// windows have drawn a frame.
+func (d *DebugDraw) Clear() {
+	d.positions = d.positions[:0]
+	d.colors = d.colors[:0]
+}
+
+func (d *DebugDraw) mesh() *Mesh {
+	if d.cache == nil {
+		d.cache = NewMesh()
+		d.cache.SetVertexMode(VertexModeLines)
+	}
+	m := d.cache
+	m.AddAttribute("position", d.positions, 3)
+	m.AddAttribute("color", d.colors, 4)
+	return m
+}
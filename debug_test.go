@@ -0,0 +1,38 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugDraw(t *testing.T) {
+	d := &DebugDraw{}
+	red := &Color{1, 0, 0, 1}
+
+	d.DrawLine(&math.Vec3{0, 0, 0}, &math.Vec3{1, 2, 3}, red)
+	assert.Equal(t, 1, d.Len())
+	assert.Equal(t, []float32{0, 0, 0, 1, 2, 3}, d.positions)
+	assert.Equal(t, []float32{1, 0, 0, 1, 1, 0, 0, 1}, d.colors)
+
+	box := math.AABB{Min: math.Vec3{-1, -1, -1}, Max: math.Vec3{1, 1, 1}}
+	d.DrawAABB(&box, red)
+	assert.Equal(t, 13, d.Len())
+
+	// 2 lines per division, plus the ones of the last edge.
+	d.DrawGrid(10, 4, red)
+	assert.Equal(t, 23, d.Len())
+
+	d.Clear()
+	transform := math.Translate3D(10, 0, 0)
+	d.DrawAxis(&transform, 2)
+	assert.Equal(t, 3, d.Len())
+	assert.Equal(t, []float32{10, 0, 0, 12, 0, 0}, d.positions[:6])
+	assert.Equal(t, []float32{10, 0, 0, 10, 0, 2}, d.positions[12:])
+	assert.Equal(t, []float32{0, 0, 1, 1}, d.colors[16:20])
+
+	mesh := d.mesh()
+	assert.Equal(t, VertexModeLines, mesh.GetVertexMode())
+	assert.Equal(t, 6, mesh.GetAttribute("position").Len())
+}
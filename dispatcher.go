@@ -0,0 +1,99 @@
+package dax
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	// EventNodeAdded is sent by a scene graph when a node is added to it.
+	// Node is the root of the added subtree and Parent its new parent.
+	EventNodeAdded EventKind = iota
+	// EventNodeRemoved is sent by a scene graph when a node is removed from
+	// it. Node is the root of the removed subtree and Parent its previous
+	// parent.
+	EventNodeRemoved
+	// EventSceneSetup is sent by a scene once it's been set up.
+	EventSceneSetup
+	// EventSceneTearDown is sent by a scene before it's torn down.
+	EventSceneTearDown
+	// EventResize is sent by a scene when the window it's drawn into is
+	// resized, after the scene and its camera have handled it.
+	EventResize
+)
+
+// Event describes something that happened to a scene or a scene graph.
+type Event struct {
+	Kind EventKind
+	// Node and Parent are set for node events.
+	Node, Parent *Node
+	// Width and Height are the new size of the framebuffer for
+	// EventResize.
+	Width, Height int
+}
+
+type eventHandler struct {
+	id   HookID
+	kind EventKind
+	f    func(e Event)
+}
+
+// EventDispatcher calls functions when events are sent. Scenes and scene
+// graphs have one.
+type EventDispatcher struct {
+	handlers []eventHandler
+	forward  []*EventDispatcher
+}
+
+// On registers f to be called when an event of the given kind is sent. The
+// returned ID can be given to Off to unregister f.
+func (d *EventDispatcher) On(kind EventKind, f func(e Event)) HookID {
+	lastHookID++
+	d.handlers = append(d.handlers, eventHandler{
+		id:   lastHookID,
+		kind: kind,
+		f:    f,
+	})
+	return lastHookID
+}
+
+// Off unregisters a function registered with On.
+func (d *EventDispatcher) Off(id HookID) {
+	for i := range d.handlers {
+		if d.handlers[i].id != id {
+			continue
+		}
+		// Don't modify the slice in place: the handlers may be being
+		// called.
+		handlers := make([]eventHandler, 0, len(d.handlers)-1)
+		handlers = append(handlers, d.handlers[:i]...)
+		d.handlers = append(handlers, d.handlers[i+1:]...)
+		return
+	}
+}
+
+// Forward sends the events of d to another dispatcher too, eg. the node events
+// of a scene graph to the scene drawing it:
+//
+//	sg.Events().Forward(s.Events())
+//
+// Forwarding several times to the same dispatcher sends the events once.
+func (d *EventDispatcher) Forward(to *EventDispatcher) {
+	for _, f := range d.forward {
+		if f == to {
+			return
+		}
+	}
+	d.forward = append(d.forward, to)
+}
+
+// emit calls the functions registered for the kind of e, in the order they
+// were registered.
+func (d *EventDispatcher) emit(e Event) {
+	for _, h := range d.handlers {
+		if h.kind == e.Kind {
+			h.f(e)
+		}
+	}
+	for _, to := range d.forward {
+		to.emit(e)
+	}
+}
@@ -0,0 +1,93 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventDispatcher(t *testing.T) {
+	var d, forwarded EventDispatcher
+	var calls []string
+
+	id := d.On(EventResize, func(e Event) {
+		calls = append(calls, "first")
+		assert.Equal(t, 800, e.Width)
+	})
+	d.On(EventResize, func(e Event) { calls = append(calls, "second") })
+	d.On(EventSceneSetup, func(e Event) { calls = append(calls, "setup") })
+	forwarded.On(EventResize, func(e Event) { calls = append(calls, "forwarded") })
+	d.Forward(&forwarded)
+
+	d.emit(Event{Kind: EventResize, Width: 800, Height: 600})
+	assert.Equal(t, []string{"first", "second", "forwarded"}, calls)
+
+	calls = nil
+	d.Off(id)
+	d.emit(Event{Kind: EventResize, Width: 800, Height: 600})
+	assert.Equal(t, []string{"second", "forwarded"}, calls)
+}
+
+func TestSceneGraphNodeEvents(t *testing.T) {
+	sg := NewSceneGraph()
+	var events []Event
+	record := func(e Event) { events = append(events, e) }
+	sg.On(EventNodeAdded, record)
+	sg.On(EventNodeRemoved, record)
+
+	parent, child := NewNode(), NewNode()
+	sg.AddChild(parent)
+	parent.AddChild(child)
+	parent.RemoveChild(child)
+
+	assert.Equal(t, []Event{
+		{Kind: EventNodeAdded, Node: parent, Parent: &sg.Node},
+		{Kind: EventNodeAdded, Node: child, Parent: parent},
+		{Kind: EventNodeRemoved, Node: child, Parent: parent},
+	}, events)
+
+	// Nodes outside of the scene graph don't send events.
+	events = nil
+	other := NewNode()
+	other.AddChild(child)
+	assert.Equal(t, 0, len(events))
+}
+
+func TestSceneEvents(t *testing.T) {
+	s := new(Scene)
+	torn := false
+	s.On(EventSceneTearDown, func(e Event) { torn = true })
+	sceneTearDown(s)
+	assert.True(t, torn)
+}
+
+type graphScene struct {
+	Scene
+	sg      *SceneGraph
+	overlay *SceneGraph
+}
+
+func (s *graphScene) Setup() {
+	s.SetCamera(NewPerspectiveCamera(60, 1, 1, 100))
+	s.sg = NewSceneGraph()
+	s.AddSceneGraph(s.sg)
+	s.overlay = NewSceneGraph()
+}
+
+func TestSceneGraphForwarding(t *testing.T) {
+	s := &graphScene{}
+	added := 0
+	s.On(EventNodeAdded, func(e Event) { added++ })
+
+	// Only the scene graphs added to the scene send their events to it.
+	sceneSetup(s, nil)
+	s.sg.AddChild(NewNode())
+	s.overlay.AddChild(NewNode())
+	assert.Equal(t, 1, added)
+
+	// Adding a scene graph several times sends its events once.
+	s.AddSceneGraph(s.overlay)
+	s.AddSceneGraph(s.overlay)
+	s.overlay.AddChild(NewNode())
+	assert.Equal(t, 2, added)
+}
@@ -0,0 +1,133 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// DisplayAdjustment is a final correction of the image shown in a window, eg.
+// to quickly calibrate the screens or projectors of an installation. It's
+// applied to everything drawn in the window, after the conversion to the
+// output color space, in this order: contrast, brightness, saturation and
+// gamma.
+type DisplayAdjustment struct {
+	// Gamma raises colors to the power 1 / Gamma: above 1 brightens the
+	// mid-tones, below 1 darkens them. 1 leaves colors untouched.
+	Gamma float32
+	// Brightness is added to colors. 0 leaves colors untouched.
+	Brightness float32
+	// Contrast scales colors around the mid-grey. 1 leaves colors untouched.
+	Contrast float32
+	// Saturation scales the distance between colors and their grey: 0 gives
+	// a greyscale image. 1 leaves colors untouched.
+	Saturation float32
+}
+
+// DefaultDisplayAdjustment returns the adjustment leaving colors untouched.
+func DefaultDisplayAdjustment() DisplayAdjustment {
+	return DisplayAdjustment{
+		Gamma:      1,
+		Brightness: 0,
+		Contrast:   1,
+		Saturation: 1,
+	}
+}
+
+// isIdentity returns true when a doesn't change colors and the final pass can
+// be skipped.
+func (a *DisplayAdjustment) isIdentity() bool {
+	return *a == DefaultDisplayAdjustment()
+}
+
+// apply returns c adjusted by a. It's the reference of the display fragment
+// shader.
+func (a *DisplayAdjustment) apply(c Color) Color {
+	rgb := [3]float32{c.R, c.G, c.B}
+
+	for i := range rgb {
+		rgb[i] = (rgb[i]-.5)*a.Contrast + .5 + a.Brightness
+	}
+
+	luma := rgb[0]*.2126 + rgb[1]*.7152 + rgb[2]*.0722
+	for i := range rgb {
+		v := luma + (rgb[i]-luma)*a.Saturation
+		v = math.Clamp(v, 0, 1)
+		if a.Gamma > 0 {
+			v = math.Pow(v, 1/a.Gamma)
+		}
+		rgb[i] = v
+	}
+
+	return Color{rgb[0], rgb[1], rgb[2], c.A}
+}
+
+// ColorSpace is the color space of the colors sent to a display.
+type ColorSpace int
+
+const (
+	// ColorSpaceSRGB is the color space of most displays and the one
+	// colors are given in. Frames are sent as is to sRGB displays.
+	ColorSpaceSRGB ColorSpace = iota
+	// ColorSpaceDisplayP3 is the wide gamut color space of recent Apple
+	// displays and of many wide gamut monitors: the sRGB transfer function with
+	// DCI-P3 primaries. Frames are converted from sRGB so colors look the
+	// same as on sRGB displays.
+	ColorSpaceDisplayP3
+)
+
+// fromSRGB returns the matrix converting linear sRGB colors to linear colors
+// of cs.
+func (cs ColorSpace) fromSRGB() math.Mat3 {
+	switch cs {
+	case ColorSpaceDisplayP3:
+		return math.Mat3{
+			0.822462, 0.033194, 0.017083,
+			0.177538, 0.966806, 0.072397,
+			0, 0, 0.910520,
+		}
+	default:
+		return math.Ident3()
+	}
+}
+
+// srgbToLinear decodes a color channel encoded with the sRGB transfer
+// function.
+func srgbToLinear(v float32) float32 {
+	if v <= .04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+.055)/1.055, 2.4)
+}
+
+// linearToSRGB encodes a linear color channel with the sRGB transfer
+// function.
+func linearToSRGB(v float32) float32 {
+	if v <= .0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - .055
+}
+
+// convert returns the sRGB color c in the color space cs. encoded is true
+// when c is encoded with the sRGB transfer function, false when it's linear,
+// eg. when drawn into an sRGB framebuffer. It's the reference of the display
+// fragment shader.
+func (cs ColorSpace) convert(c Color, encoded bool) Color {
+	rgb := math.Vec3{c.R, c.G, c.B}
+	if encoded {
+		for i := range rgb {
+			rgb[i] = srgbToLinear(rgb[i])
+		}
+	}
+
+	m := cs.fromSRGB()
+	rgb = m.Mul3x1(&rgb)
+
+	for i := range rgb {
+		rgb[i] = math.Clamp(rgb[i], 0, 1)
+		if encoded {
+			rgb[i] = linearToSRGB(rgb[i])
+		}
+	}
+
+	return Color{rgb[0], rgb[1], rgb[2], c.A}
+}
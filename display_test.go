@@ -0,0 +1,83 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayAdjustmentIdentity(t *testing.T) {
+	a := DefaultDisplayAdjustment()
+	assert.True(t, a.isIdentity())
+
+	c := Color{.2, .5, .9, .7}
+	got := a.apply(c)
+	assert.InDelta(t, c.R, got.R, 1e-6)
+	assert.InDelta(t, c.G, got.G, 1e-6)
+	assert.InDelta(t, c.B, got.B, 1e-6)
+	assert.Equal(t, c.A, got.A)
+
+	a.Gamma = 2.2
+	assert.False(t, a.isIdentity())
+}
+
+func TestDisplayAdjustment(t *testing.T) {
+	tests := []struct {
+		name       string
+		adjustment DisplayAdjustment
+		in, out    Color
+	}{
+		{"brightness", DisplayAdjustment{Gamma: 1, Brightness: .1, Contrast: 1, Saturation: 1},
+			Color{.2, .5, .95, 1}, Color{.3, .6, 1, 1}},
+		{"contrast", DisplayAdjustment{Gamma: 1, Contrast: 2, Saturation: 1},
+			Color{.25, .5, .6, 1}, Color{0, .5, .7, 1}},
+		{"greyscale", DisplayAdjustment{Gamma: 1, Contrast: 1, Saturation: 0},
+			Color{1, 0, 0, 1}, Color{.2126, .2126, .2126, 1}},
+		{"gamma", DisplayAdjustment{Gamma: 2, Contrast: 1, Saturation: 1},
+			Color{.25, 1, 0, .5}, Color{.5, 1, 0, .5}},
+	}
+
+	for _, test := range tests {
+		got := test.adjustment.apply(test.in)
+		assert.InDelta(t, test.out.R, got.R, 1e-5, test.name)
+		assert.InDelta(t, test.out.G, got.G, 1e-5, test.name)
+		assert.InDelta(t, test.out.B, got.B, 1e-5, test.name)
+		assert.Equal(t, test.out.A, got.A, test.name)
+	}
+}
+
+func TestColorSpaceConvert(t *testing.T) {
+	red := Color{1, 0, 0, 1}
+
+	// sRGB colors are left untouched.
+	got := ColorSpaceSRGB.convert(red, true)
+	assert.InDelta(t, 1, got.R, 1e-5)
+	assert.InDelta(t, 0, got.G, 1e-5)
+	assert.InDelta(t, 0, got.B, 1e-5)
+
+	// The sRGB red is inside the Display P3 gamut.
+	got = ColorSpaceDisplayP3.convert(red, true)
+	assert.InDelta(t, .9175, got.R, 1e-3)
+	assert.InDelta(t, .2003, got.G, 1e-3)
+	assert.InDelta(t, .1386, got.B, 1e-3)
+	assert.Equal(t, float32(1), got.A)
+
+	got = ColorSpaceDisplayP3.convert(red, false)
+	assert.InDelta(t, .8225, got.R, 1e-3)
+	assert.InDelta(t, .0332, got.G, 1e-3)
+	assert.InDelta(t, .0171, got.B, 1e-3)
+
+	// Greys are the same in both color spaces.
+	grey := Color{.5, .5, .5, 1}
+	got = ColorSpaceDisplayP3.convert(grey, true)
+	assert.InDelta(t, .5, got.R, 1e-4)
+	assert.InDelta(t, .5, got.G, 1e-4)
+	assert.InDelta(t, .5, got.B, 1e-4)
+}
+
+func TestSRGBTransfer(t *testing.T) {
+	for _, v := range []float32{0, .002, .04, .2, .5, 1} {
+		assert.InDelta(t, v, srgbToLinear(linearToSRGB(v)), 1e-5)
+	}
+	assert.InDelta(t, .2140, srgbToLinear(.5), 1e-4)
+}
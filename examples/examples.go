@@ -46,7 +46,8 @@ func run(ctx *cli.Context) error {
 	}
 
 	app := dax.NewApplication(example.Name)
-	window := app.CreateWindow(app.Name+" Example", 800, 600)
+	app.Name = example.Name
+	window := app.NewWindow(app.Name+" Example", 800, 600)
 	window.SetScene(example.Scene)
 	app.Run()
 
@@ -55,13 +56,23 @@ func run(ctx *cli.Context) error {
 
 var daxExamples = &examples{
 	list: []*Example{
+		&gfxGridExample,
+		&gfxPickingExample,
 		&gfxPolylineExample,
+		&gfxPrimitivesExample,
 		&gfxScenegraphExample,
 		&winsysEventsExample,
 	},
 }
 
 func main() {
+	// Engine flags can be given anywhere on the command line.
+	args, err := dax.NewApplication("dax-examples").ParseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	app := cli.NewApp()
 	app.Name = "dax-examples"
 	app.Usage = "Show off what DaX can do"
@@ -82,5 +93,5 @@ func main() {
 	app.Metadata = map[string]interface{}{
 		"examples": daxExamples,
 	}
-	app.Run(os.Args)
+	app.Run(append(os.Args[:1], args...))
 }
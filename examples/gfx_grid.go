@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+type gridBasic struct {
+	dax.Scene
+
+	grid *dax.Grid
+}
+
+func (s *gridBasic) Setup() {
+	s.SetBackgroundColor(.1, .1, .1, 1)
+
+	camera := dax.NewPerspectiveCamera(70, 800./600., .1, 1000)
+	camera.SetPosition(20, 8, 20)
+	camera.LookAt(&math.Vec3{0, 0, 0})
+	s.SetCamera(camera)
+
+	s.grid = dax.NewGrid()
+}
+
+func (s *gridBasic) Draw(fb dax.Framebuffer) {
+	fb.Draw(s.grid)
+}
+
+var gfxGridExample = Example{
+	Category:    CategoryGraphics,
+	Name:        "Grid",
+	Description: "Display a ground grid, as found in editors",
+	Scene:       &gridBasic{},
+}
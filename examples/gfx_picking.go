@@ -0,0 +1,401 @@
+package main
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/geometry"
+	"github.com/dlespiau/dax/material"
+	"github.com/dlespiau/dax/math"
+)
+
+var (
+	pickingHoverColor   = dax.Color{R: .6, G: .8, B: 1, A: 1}
+	pickingOutlineColor = dax.Color{R: 1, G: .8, B: .2, A: 1}
+)
+
+// pickingAxes are the axes of the gizmo, with their color.
+var pickingAxes = []struct {
+	axis  math.Vec3
+	color dax.Color
+}{
+	{math.Vec3{1, 0, 0}, dax.Color{R: .9, G: .2, B: .2, A: 1}},
+	{math.Vec3{0, 1, 0}, dax.Color{R: .2, G: .9, B: .2, A: 1}},
+	{math.Vec3{0, 0, 1}, dax.Color{R: .2, G: .4, B: .9, A: 1}},
+}
+
+const (
+	// Size of the gizmo axes: their length, the width of their shaft and
+	// the size of the cube at their end.
+	pickingGizmoLength = 150
+	pickingGizmoWidth  = 6
+	pickingGizmoTip    = 20
+	// pickingOutlineOffset moves the outline towards the camera so it isn't
+	// hidden by the faces of the selected object it borders.
+	pickingOutlineOffset = 2
+)
+
+// pickingObject is an object that can be picked.
+type pickingObject struct {
+	node     *dax.Node
+	renderer *dax.MeshRenderer
+	mesh     *dax.Mesh
+}
+
+// pickingDrag is the state of the selected node being dragged along an axis
+// of the gizmo.
+type pickingDrag struct {
+	axis math.Vec3
+	// start is the node position when the drag started and grab the
+	// position, along the axis, of the point that was clicked.
+	start math.Vec3
+	grab  float32
+}
+
+// picking hovers and selects objects with the mouse. The selected object is
+// outlined and has a gizmo: dragging one of its axes moves the object along
+// that axis.
+type picking struct {
+	dax.Scene
+
+	// The objects, the outline and the gizmo are in different scene
+	// graphs: only the objects and the gizmo are picked, separately.
+	sg      *dax.SceneGraph
+	outline *dax.SceneGraph
+	gizmo   *dax.SceneGraph
+	camera  dax.Camera
+	fb      dax.Framebuffer
+
+	objects []pickingObject
+
+	// outlineMesh has the lines of the silhouette of the selected object,
+	// in world space.
+	outlineMesh  *dax.Mesh
+	outlineLines []float32
+
+	gizmoRoot *dax.Node
+	// axes maps the nodes of the gizmo to the axis they're part of.
+	axes map[*dax.Node]math.Vec3
+
+	hovered  *dax.Node
+	selected *dax.Node
+	dragging bool
+	drag     pickingDrag
+}
+
+func (s *picking) Setup() {
+	camera := dax.NewPerspectiveCamera(math.DegToRad(60), 800./600., 1, 1000)
+	camera.SetPosition(0, 200, 600)
+	camera.LookAt(&math.Vec3{0, 0, 0})
+	s.SetCamera(camera)
+	s.camera = camera
+
+	s.sg = dax.NewSceneGraph()
+	s.objects = s.objects[:0]
+
+	// The meshes are created once, the outline being computed from them.
+	box := geometry.NewBox(100, 100, 100).GetMesh()
+	sphere := geometry.NewSphere(60, 32, 16).GetMesh()
+	grey := material.NewColor(&dax.Color{R: .8, G: .8, B: .8, A: 1})
+
+	for i, mesh := range []*dax.Mesh{box, sphere, box, sphere, box} {
+		mr := dax.NewMeshRenderer(mesh, grey)
+		node := dax.NewNode().AddComponent(mr)
+		node.SetPosition(float32(i-2)*200, 0, 0)
+		s.sg.AddChild(node)
+		s.objects = append(s.objects, pickingObject{node, mr, mesh})
+	}
+
+	s.outline = dax.NewSceneGraph()
+	s.outlineMesh = dax.NewMesh()
+	s.outlineMesh.SetVertexMode(dax.VertexModeLines)
+	outline := material.NewColor(&pickingOutlineColor)
+	s.outline.AddChild(dax.NewNode().AddComponent(dax.NewMeshRenderer(s.outlineMesh, outline)))
+
+	s.setupGizmo()
+
+	s.hovered = nil
+	s.selected = nil
+	s.dragging = false
+}
+
+// setupGizmo creates the gizmo: an arrow per axis, made of a thin box ending
+// with a cube.
+func (s *picking) setupGizmo() {
+	s.gizmo = dax.NewSceneGraph()
+	s.gizmoRoot = dax.NewNode()
+	s.gizmo.AddChild(s.gizmoRoot)
+	s.axes = make(map[*dax.Node]math.Vec3)
+
+	tip := geometry.NewBox(pickingGizmoTip, pickingGizmoTip, pickingGizmoTip).GetMesh()
+	for _, a := range pickingAxes {
+		m := material.NewColor(&a.color)
+
+		length := a.axis.Mul(pickingGizmoLength - pickingGizmoWidth)
+		size := math.Vec3{pickingGizmoWidth, pickingGizmoWidth, pickingGizmoWidth}
+		size = size.Add(&length)
+		shaft := dax.NewNode().AddComponent(dax.NewMeshRenderer(
+			geometry.NewBox(size[0], size[1], size[2]).GetMesh(), m))
+		center := a.axis.Mul(pickingGizmoLength / 2)
+		shaft.SetPositionV(&center)
+
+		end := dax.NewNode().AddComponent(dax.NewMeshRenderer(tip, m))
+		position := a.axis.Mul(pickingGizmoLength)
+		end.SetPositionV(&position)
+
+		s.gizmoRoot.AddChildren(shaft, end)
+		s.axes[shaft] = a.axis
+		s.axes[end] = a.axis
+	}
+}
+
+// OnResize remembers the framebuffer, needed to pick from mouse coordinates.
+func (s *picking) OnResize(fb dax.Framebuffer, width, height int) {
+	s.Scene.OnResize(fb, width, height)
+	s.fb = fb
+}
+
+// object returns the object of node, nil if node isn't one.
+func (s *picking) object(node *dax.Node) *pickingObject {
+	for i := range s.objects {
+		if s.objects[i].node == node {
+			return &s.objects[i]
+		}
+	}
+	return nil
+}
+
+// pick returns the closest node of sg under the mouse.
+func (s *picking) pick(sg *dax.SceneGraph, x, y float32) *dax.PickResult {
+	if s.fb == nil {
+		return nil
+	}
+	results := sg.Pick(x, y, s.fb)
+	if len(results) == 0 {
+		return nil
+	}
+	return &results[0]
+}
+
+// highlight tints the node under the mouse.
+func (s *picking) highlight() {
+	for i := range s.objects {
+		o := &s.objects[i]
+		if o.node != s.hovered {
+			o.renderer.SetPropertyBlock(nil)
+			continue
+		}
+		properties := dax.NewPropertyBlock()
+		properties.Set("color", pickingHoverColor)
+		o.renderer.SetPropertyBlock(properties)
+	}
+}
+
+// selectNode selects node, nil clearing the selection.
+func (s *picking) selectNode(node *dax.Node) {
+	s.selected = node
+	if node != nil {
+		s.updateSelection()
+	}
+}
+
+// updateSelection moves the gizmo to the selected node and computes its
+// outline, as seen from the camera.
+func (s *picking) updateSelection() {
+	o := s.object(s.selected)
+	world := s.selected.GetTransform()
+	inverse := world.Inverse()
+	eye := *s.camera.AsNode().GetPosition()
+	localEye := inverse.Mul4x1(&math.Vec4{eye[0], eye[1], eye[2], 1})
+	local := localEye.Vec3()
+
+	lines := silhouette(s.outlineLines[:0], o.mesh, &local)
+	for i := 0; i < len(lines); i += 3 {
+		v := world.Mul4x1(&math.Vec4{lines[i], lines[i+1], lines[i+2], 1})
+		p := v.Vec3()
+		toEye := eye.Sub(&p)
+		toEye.Normalize()
+		offset := toEye.Mul(pickingOutlineOffset)
+		p = p.Add(&offset)
+		copy(lines[i:i+3], p[:])
+	}
+	s.outlineLines = lines
+	s.outlineMesh.AddAttribute("position", lines, 3)
+
+	s.gizmoRoot.SetPositionV(s.selected.GetPosition())
+}
+
+// pickingEdge is an edge of a mesh, with the number of triangles sharing it
+// facing the eye and facing away.
+type pickingEdge struct {
+	a, b        math.Vec3
+	front, back int
+}
+
+// pickingEdgeKey identifies an edge by the position of its vertices, rounded
+// to a hundredth of unit and sorted.
+type pickingEdgeKey [2][3]int32
+
+func newPickingEdgeKey(a, b *math.Vec3) pickingEdgeKey {
+	var k pickingEdgeKey
+	for i := 0; i < 3; i++ {
+		k[0][i] = int32(math.Floor(a[i]*100 + .5))
+		k[1][i] = int32(math.Floor(b[i]*100 + .5))
+	}
+	if k[1][0] < k[0][0] ||
+		(k[1][0] == k[0][0] && (k[1][1] < k[0][1] ||
+			(k[1][1] == k[0][1] && k[1][2] < k[0][2]))) {
+		k[0], k[1] = k[1], k[0]
+	}
+	return k
+}
+
+// silhouette appends to lines the pairs of vertices of the edges of the
+// silhouette of mesh, as seen from eye: the edges between a triangle facing
+// eye and a triangle facing away, and the borders of triangles facing eye.
+// eye is in the mesh space, as are the vertices. mesh has to be made of
+// triangles.
+func silhouette(lines []float32, mesh *dax.Mesh, eye *math.Vec3) []float32 {
+	positions := mesh.GetAttribute("position")
+	indices := mesh.GetIndices()
+	index, count := indices.Get, indices.Len()
+	if count == 0 {
+		index = func(n int) uint { return uint(n) }
+		count = positions.Len()
+	}
+	vertex := func(i uint) math.Vec3 {
+		x, y, z := positions.GetXYZ(int(i))
+		return math.Vec3{x, y, z}
+	}
+
+	// Meshes duplicate vertices along their seams and sharp edges: edges
+	// are identified by the position of their vertices.
+	edges := make(map[pickingEdgeKey]*pickingEdge)
+	for i := 0; i+2 < count; i += 3 {
+		a, b, c := vertex(index(i)), vertex(index(i+1)), vertex(index(i+2))
+		ab, ac := b.Sub(&a), c.Sub(&a)
+		normal := ab.Cross(&ac)
+		if normal.Len2() == 0 {
+			// Degenerate triangles, eg. at the poles of spheres.
+			continue
+		}
+		toEye := eye.Sub(&a)
+		front := normal.Dot(&toEye) > 0
+
+		for _, e := range [3][2]*math.Vec3{{&a, &b}, {&b, &c}, {&c, &a}} {
+			key := newPickingEdgeKey(e[0], e[1])
+			edge := edges[key]
+			if edge == nil {
+				edge = &pickingEdge{a: *e[0], b: *e[1]}
+				edges[key] = edge
+			}
+			if front {
+				edge.front++
+			} else {
+				edge.back++
+			}
+		}
+	}
+
+	for _, e := range edges {
+		if e.front == 0 || (e.back == 0 && e.front > 1) {
+			continue
+		}
+		lines = append(lines, e.a[0], e.a[1], e.a[2], e.b[0], e.b[1], e.b[2])
+	}
+	return lines
+}
+
+// axisParameter returns the position, along the line going through origin in
+// the direction axis, of the point of the line the closest to ray. axis and
+// the ray direction are unit vectors. ok is false when they're parallel.
+func axisParameter(ray *math.Ray, origin, axis *math.Vec3) (t float32, ok bool) {
+	// Minimize |w + t.axis - u.direction|, w going from the ray origin to
+	// origin.
+	w := origin.Sub(&ray.Origin)
+	b := axis.Dot(&ray.Direction)
+	d := 1 - b*b
+	if d < 1e-6 {
+		return 0, false
+	}
+	return (b*ray.Direction.Dot(&w) - axis.Dot(&w)) / d, true
+}
+
+func (s *picking) OnMouseMoved(x, y float32) {
+	if !s.dragging {
+		s.hovered = nil
+		if hit := s.pick(s.sg, x, y); hit != nil {
+			s.hovered = hit.Node
+		}
+		s.highlight()
+		return
+	}
+
+	ray := dax.ScreenRay(x, y, s.fb)
+	t, ok := axisParameter(&ray, &s.drag.start, &s.drag.axis)
+	if !ok {
+		return
+	}
+	offset := s.drag.axis.Mul(t - s.drag.grab)
+	position := s.drag.start.Add(&offset)
+	s.selected.SetPositionV(&position)
+	s.updateSelection()
+}
+
+func (s *picking) OnMouseButtonPressed(b dax.MouseButton, x, y float32) {
+	if b != dax.MouseButtonLeft {
+		return
+	}
+
+	// The gizmo sticks out of the selected object, it's picked first.
+	if s.selected != nil {
+		if hit := s.pick(s.gizmo, x, y); hit != nil {
+			s.startDrag(s.axes[hit.Node], x, y)
+			return
+		}
+	}
+
+	// Clicking in the void clears the selection.
+	var node *dax.Node
+	if hit := s.pick(s.sg, x, y); hit != nil {
+		node = hit.Node
+	}
+	s.selectNode(node)
+}
+
+// startDrag starts dragging the selected node along axis, from the point
+// (x, y) of the framebuffer.
+func (s *picking) startDrag(axis math.Vec3, x, y float32) {
+	ray := dax.ScreenRay(x, y, s.fb)
+	start := *s.selected.GetPosition()
+	grab, ok := axisParameter(&ray, &start, &axis)
+	if !ok {
+		// The axis points at the camera.
+		return
+	}
+	s.dragging = true
+	s.drag = pickingDrag{
+		axis:  axis,
+		start: start,
+		grab:  grab,
+	}
+}
+
+func (s *picking) OnMouseButtonReleased(b dax.MouseButton, x, y float32) {
+	if b == dax.MouseButtonLeft {
+		s.dragging = false
+	}
+}
+
+func (s *picking) Draw(fb dax.Framebuffer) {
+	fb.Draw(s.sg)
+	if s.selected != nil {
+		fb.Draw(s.outline)
+		fb.Draw(s.gizmo)
+	}
+}
+
+var gfxPickingExample = Example{
+	Category:    CategoryGraphics,
+	Name:        "Picking",
+	Description: "Hover and select objects with the mouse, move them with the gizmo",
+	Scene:       &picking{},
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/daxtest"
+	"github.com/dlespiau/dax/geometry"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// pickingSelected is the picking example with its middle object selected.
+type pickingSelected struct {
+	picking
+}
+
+func (s *pickingSelected) Setup() {
+	s.picking.Setup()
+	s.selectNode(s.objects[2].node)
+}
+
+// TestPicking is the rendering regression test of the selection outline and
+// the gizmo. Update the golden image with:
+//
+//	go test ./examples -run Picking -daxtest.update
+func TestPicking(t *testing.T) {
+	daxtest.AssertScene(t, &pickingSelected{}, 640, 480, "testdata/gfx-picking.png",
+		daxtest.DefaultTolerance)
+}
+
+func TestAxisParameter(t *testing.T) {
+	// A ray going down the Z axis, crossing the X axis at x = 3.
+	ray := math.Ray{
+		Origin:    math.Vec3{3, 0, 10},
+		Direction: math.Vec3{0, 0, -1},
+	}
+	origin := math.Vec3{1, 0, 0}
+	axis := math.Vec3{1, 0, 0}
+	param, ok := axisParameter(&ray, &origin, &axis)
+	assert.True(t, ok)
+	assert.InDelta(t, 2, param, 1e-5)
+
+	// Parallel to the axis.
+	axis = math.Vec3{0, 0, 1}
+	_, ok = axisParameter(&ray, &origin, &axis)
+	assert.False(t, ok)
+}
+
+func TestSilhouette(t *testing.T) {
+	box := geometry.NewBox(2, 2, 2).GetMesh()
+
+	// Looking at a face of a box, the silhouette is that face.
+	eye := math.Vec3{0, 0, 10}
+	lines := silhouette(nil, box, &eye)
+	assert.Equal(t, 4*2*3, len(lines))
+	for i := 2; i < len(lines); i += 3 {
+		assert.Equal(t, float32(1), lines[i])
+	}
+
+	// Looking at a corner, the silhouette is an hexagon.
+	eye = math.Vec3{10, 10, 10}
+	lines = silhouette(nil, box, &eye)
+	assert.Equal(t, 6*2*3, len(lines))
+}
@@ -41,7 +41,7 @@ func (s *shapePolyline) OnMouseButtonReleased(b dax.MouseButton, x, y float32) {
 	}
 }
 
-func (s *shapePolyline) Update(time float64) {
+func (s *shapePolyline) Update(dt float64) {
 	p := s.poly.Positions()
 	for i := 0; i < len(p); i += 3 {
 		p[i] += dax.Rand(-.5, .5)
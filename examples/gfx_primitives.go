@@ -0,0 +1,120 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/geometry"
+	"github.com/dlespiau/dax/material"
+	"github.com/dlespiau/dax/math"
+)
+
+// primitivesViewer draws every geometry primitive, one per row, with every
+// built-in material, one per column, under a fixed camera and light. The first
+// frame is compared to a golden image by the tests, making it the rendering
+// regression test of the primitives and materials.
+type primitivesViewer struct {
+	dax.Scene
+
+	sg     *dax.SceneGraph
+	shapes []*dax.Node
+}
+
+// primitives returns the meshes drawn by the viewer.
+func primitives() []dax.Mesher {
+	return []dax.Mesher{
+		geometry.NewBox(1, 1, 1),
+		geometry.NewSphere(.6, 32, 16),
+		geometry.NewCylinder(.5, .5, 1),
+		geometry.NewCone(.5, 1),
+		geometry.NewTorus(.4, .15),
+		geometry.NewPlane(1, 1, 1),
+	}
+}
+
+// checkerboard returns an 8x8 checkerboard image, to texture materials.
+func checkerboard(c1, c2 color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if (x/8+y/8)%2 == 0 {
+				img.Set(x, y, c1)
+			} else {
+				img.Set(x, y, c2)
+			}
+		}
+	}
+	return img
+}
+
+// materials returns the materials used by the viewer.
+func materials() []dax.Material {
+	white := &dax.Color{R: 1, G: 1, B: 1, A: 1}
+	orange := &dax.Color{R: 1, G: .5, B: .1, A: 1}
+	grey := &dax.Color{R: .2, G: .2, B: .2, A: 1}
+	texture := dax.NewTextureFromImage(checkerboard(color.White, color.Gray{Y: 64}))
+
+	return []dax.Material{
+		material.NewColor(orange),
+		material.NewChecker(white, grey, .25),
+		material.NewDebug(material.DebugNormals),
+		material.NewGrid(grey, white, .25),
+		material.NewMatcap(orange),
+		material.NewNoise(white, grey, 4),
+		material.NewPBR(orange, 0, .5),
+		material.NewPhong(orange),
+		material.NewTriplanar(texture, 1),
+	}
+}
+
+func (s *primitivesViewer) Setup() {
+	s.SetBackgroundColor(.1, .1, .1, 1)
+
+	camera := dax.NewPerspectiveCamera(40, 800./600., .1, 100)
+	camera.SetPosition(0, 4, 16)
+	camera.LookAt(&math.Vec3{0, 0, 0})
+	s.SetCamera(camera)
+
+	s.sg = dax.NewSceneGraph()
+	s.shapes = s.shapes[:0]
+
+	sun := dax.NewNode()
+	sun.AddComponent(dax.NewDirectionalLight(&dax.Color{R: 1, G: 1, B: 1, A: 1}, 2))
+	sun.RotateX(-math.Pi / 4)
+	sun.RotateY(-math.Pi / 6)
+	s.sg.AddChild(sun)
+
+	const spacing = 1.5
+	meshes, looks := primitives(), materials()
+	for row, mesh := range meshes {
+		for column, look := range looks {
+			node := s.CreateActor(mesh, look)
+			node.SetPosition(
+				(float32(column)-float32(len(looks)-1)/2)*spacing,
+				(float32(len(meshes)-1)/2-float32(row))*spacing,
+				0)
+			node.RotateX(.5)
+			node.RotateY(.6)
+			s.sg.AddChild(node)
+			s.shapes = append(s.shapes, node)
+		}
+	}
+}
+
+func (s *primitivesViewer) Update(dt float64) {
+	for _, node := range s.shapes {
+		node.RotateY(.5 * float32(dt))
+	}
+}
+
+func (s *primitivesViewer) Draw(fb dax.Framebuffer) {
+	fb.Draw(s.sg)
+}
+
+var gfxPrimitivesExample = Example{
+	Category:    CategoryGraphics,
+	Name:        "Primitives",
+	Description: "Display every geometry primitive with every built-in material",
+	Scene:       &primitivesViewer{},
+}
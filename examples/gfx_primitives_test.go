@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/daxtest"
+)
+
+// TestPrimitives is the rendering regression test of the primitives and
+// materials. Update the golden image with:
+//
+//	go test ./examples -run Primitives -daxtest.update
+func TestPrimitives(t *testing.T) {
+	daxtest.AssertScene(t, &primitivesViewer{}, 640, 480, "testdata/gfx-primitives.png",
+		daxtest.DefaultTolerance)
+}
@@ -33,11 +33,11 @@ func (s *sceneGraphBasic) Setup() {
 	s.sg.AddChildren(node1, node2, node3)
 }
 
-func (s *sceneGraphBasic) Update(time float64) {
+func (s *sceneGraphBasic) Update(dt float64) {
 	for i, child := range s.sg.GetChildren() {
 		node := child.(*dax.Node)
-		node.RotateX(0.02 * float32(i+1))
-		node.RotateY(0.04 * float32(i+1))
+		node.RotateX(1.2 * float32(dt) * float32(i+1))
+		node.RotateY(2.4 * float32(dt) * float32(i+1))
 	}
 }
 
@@ -42,7 +42,7 @@ func (s *miscProperties) OnMouseButtonReleased(b dax.MouseButton, x, y float32)
 	}
 }
 
-func (s *miscProperties) Update(time float64) {
+func (s *miscProperties) Update(dt float64) {
 	p := s.poly.Positions()
 	for i := 0; i < len(p); i += 3 {
 		p[i] += dax.Rand(-s.dx, s.dx)
@@ -22,6 +22,14 @@ func (s *eventsBasic) OnMouseButtonReleased(b dax.MouseButton, x, y float32) {
 	fmt.Printf("Button %v released at (%.0f, %.0f)\n", b, x, y)
 }
 
+func (s *eventsBasic) OnKeyPressed(key dax.Key, scancode int, mods dax.ModifierKey) {
+	fmt.Printf("Key %d (scancode %d, modifiers %#x) pressed\n", key, scancode, mods)
+}
+
+func (s *eventsBasic) OnKeyReleased(key dax.Key, scancode int, mods dax.ModifierKey) {
+	fmt.Printf("Key %d (scancode %d, modifiers %#x) released\n", key, scancode, mods)
+}
+
 func (s *eventsBasic) OnRuneEntered(r rune) {
 	fmt.Printf("Rune entered '%c'\n", r)
 }
@@ -0,0 +1,134 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// FloatingOrigin keeps the camera close to the origin in very large worlds.
+//
+// Node positions are single precision floats: far away from the origin, their
+// precision isn't good enough anymore and objects jitter. FloatingOrigin
+// stores the position of the render origin in double precision and, when the
+// camera strays too far from it, moves the origin to the camera, shifting the
+// whole scene so the camera ends up close to (0, 0, 0) again. Node positions
+// are then relative to the origin: use ToWorld and FromWorld to convert from
+// and to absolute world coordinates.
+//
+// The absolute positions of the moved nodes are kept in double precision:
+// nodes that don't move between two rebases are placed from their absolute
+// position, so rounding errors don't accumulate over many rebases.
+//
+// Scenes can have their windows rebase a scene graph every frame, see
+// Scene.SetFloatingOrigin.
+type FloatingOrigin struct {
+	// Threshold is the distance from the origin the camera can move away
+	// before the scene is rebased.
+	Threshold float32
+
+	origin [3]float64
+	// anchors are the absolute positions of the nodes moved by the last
+	// rebase.
+	anchors map[*Node]*anchor
+}
+
+// anchor is the absolute position of a node moved by a rebase, world, and the
+// position it was given, to notice when the node moves.
+type anchor struct {
+	world    [3]float64
+	position math.Vec3
+}
+
+// NewFloatingOrigin creates a FloatingOrigin rebasing the scene when the
+// camera is further than threshold from the origin.
+func NewFloatingOrigin(threshold float32) *FloatingOrigin {
+	return &FloatingOrigin{
+		Threshold: threshold,
+	}
+}
+
+// Origin returns the world position of the render origin.
+func (o *FloatingOrigin) Origin() (x, y, z float64) {
+	return o.origin[0], o.origin[1], o.origin[2]
+}
+
+// ToWorld converts a position relative to the render origin into absolute world
+// coordinates.
+func (o *FloatingOrigin) ToWorld(v *math.Vec3) (x, y, z float64) {
+	return o.origin[0] + float64(v[0]),
+		o.origin[1] + float64(v[1]),
+		o.origin[2] + float64(v[2])
+}
+
+// FromWorld converts absolute world coordinates into a position relative to
+// the render origin.
+func (o *FloatingOrigin) FromWorld(x, y, z float64) math.Vec3 {
+	return math.Vec3{
+		float32(x - o.origin[0]),
+		float32(y - o.origin[1]),
+		float32(z - o.origin[2]),
+	}
+}
+
+// Update rebases sg when the camera is too far from the origin. It should be
+// called once per frame, after the camera has moved and before drawing, which
+// windows do for scenes given a floating origin with Scene.SetFloatingOrigin.
+// It returns true if the scene has been rebased.
+//
+// The top level nodes of sg are moved, as well as the camera when it isn't part
+// of the scene graph. Previous frame transforms are left untouched: they are
+// consistent with the previous camera transform, so velocities stay correct.
+func (o *FloatingOrigin) Update(sg *SceneGraph, c Camera) bool {
+	cameraNode := c.AsNode()
+	var position math.Vec3
+	if cameraNode.parent == nil {
+		position = cameraNode.position
+	} else {
+		sg.updateWorldTransform()
+		translation := cameraNode.worldTransform.AsMat4().Col(3)
+		position = translation.Vec3()
+	}
+
+	if position.Len() < o.Threshold {
+		return false
+	}
+
+	o.Rebase(sg, c, &position)
+	return true
+}
+
+// Rebase moves the render origin by offset, shifting the scene the other way.
+func (o *FloatingOrigin) Rebase(sg *SceneGraph, c Camera, offset *math.Vec3) {
+	previous := o.origin
+	for i := range offset {
+		o.origin[i] += float64(offset[i])
+	}
+
+	anchors := make(map[*Node]*anchor, len(o.anchors))
+	for _, child := range sg.GetChildren() {
+		if n, ok := child.(*Node); ok {
+			o.move(n, &previous, anchors)
+		}
+	}
+	if cameraNode := c.AsNode(); cameraNode.parent == nil {
+		o.move(cameraNode, &previous, anchors)
+	}
+	o.anchors = anchors
+}
+
+// move places n relative to the new origin. previous is the origin n is
+// currently relative to. The anchor of n is then added to anchors.
+func (o *FloatingOrigin) move(n *Node, previous *[3]float64, anchors map[*Node]*anchor) {
+	a := o.anchors[n]
+	if a == nil || a.position != n.position {
+		// New or moved node.
+		a = &anchor{}
+		for i := range a.world {
+			a.world[i] = previous[i] + float64(n.position[i])
+		}
+	}
+
+	position := o.FromWorld(a.world[0], a.world[1], a.world[2])
+	n.SetPositionV(&position)
+	a.position = position
+	anchors[n] = a
+}
@@ -0,0 +1,118 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloatingOrigin(t *testing.T) {
+	sg := NewSceneGraph()
+	a := NewNode()
+	a.SetPosition(100, 0, 0)
+	b := NewNode()
+	b.SetPosition(10, 0, 0)
+	a.AddChild(b)
+	sg.AddChild(a)
+
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, .1, 1000)
+	o := NewFloatingOrigin(1000)
+
+	// Close enough to the origin.
+	c.SetPosition(500, 0, 0)
+	assert.False(t, o.Update(sg, c))
+	x, y, z := o.Origin()
+	assert.Equal(t, [3]float64{0, 0, 0}, [3]float64{x, y, z})
+
+	c.SetPosition(1500, 0, 200)
+	assert.True(t, o.Update(sg, c))
+	x, y, z = o.Origin()
+	assert.Equal(t, [3]float64{1500, 0, 200}, [3]float64{x, y, z})
+
+	// The camera is back to the origin, top level nodes have moved, their
+	// children follow.
+	assertVec3(t, &math.Vec3{0, 0, 0}, c.GetPosition(), 1e-3)
+	assertVec3(t, &math.Vec3{-1400, 0, -200}, a.GetPosition(), 1e-3)
+	assertVec3(t, &math.Vec3{10, 0, 0}, b.GetPosition(), 1e-3)
+
+	// World positions don't change.
+	x, y, z = o.ToWorld(a.GetPosition())
+	assert.Equal(t, [3]float64{100, 0, 0}, [3]float64{x, y, z})
+	v := o.FromWorld(100, 0, 0)
+	assertVec3(t, a.GetPosition(), &v, 1e-3)
+}
+
+func TestFloatingOriginCameraInSceneGraph(t *testing.T) {
+	sg := NewSceneGraph()
+	rig := NewNode()
+	rig.SetPosition(2000, 0, 0)
+	sg.AddChild(rig)
+
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, .1, 1000)
+	c.SetPosition(0, 100, 0)
+	rig.AddChild(c.AsNode())
+
+	o := NewFloatingOrigin(1000)
+	assert.True(t, o.Update(sg, c))
+	x, y, z := o.Origin()
+	assert.Equal(t, [3]float64{2000, 100, 0}, [3]float64{x, y, z})
+
+	// The camera moves with its parent.
+	assertVec3(t, &math.Vec3{0, -100, 0}, rig.GetPosition(), 1e-3)
+	assertVec3(t, &math.Vec3{0, 100, 0}, c.GetPosition(), 1e-3)
+}
+
+func TestFloatingOriginPrecision(t *testing.T) {
+	sg := NewSceneGraph()
+	static := NewNode()
+	static.SetPosition(.1, 0, 0)
+	moving := NewNode()
+	sg.AddChild(static)
+	sg.AddChild(moving)
+
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, .1, 1000)
+	o := NewFloatingOrigin(1000)
+
+	// Going far away and back doesn't move static nodes: rounding errors
+	// don't accumulate.
+	offset := math.Vec3{1234.567, 0, 0}
+	back := offset.Mul(-1)
+	for i := 0; i < 1000; i++ {
+		o.Rebase(sg, c, &offset)
+	}
+	for i := 0; i < 1000; i++ {
+		o.Rebase(sg, c, &back)
+	}
+	x, _, _ := o.Origin()
+	assert.InDelta(t, 0, x, 1e-6)
+	assert.Equal(t, float32(.1), static.GetPosition()[0])
+
+	// Nodes moved between rebases keep their new position.
+	moving.SetPosition(5, 0, 0)
+	o.Rebase(sg, c, &offset)
+	o.Rebase(sg, c, &back)
+	assert.Equal(t, float32(5), moving.GetPosition()[0])
+}
+
+func TestSceneFloatingOrigin(t *testing.T) {
+	sg := NewSceneGraph()
+	node := NewNode()
+	sg.AddChild(node)
+
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, .1, 1000)
+	var s Scene
+	s.updateFloatingOrigin(c)
+
+	o := NewFloatingOrigin(100)
+	s.SetFloatingOrigin(o, sg)
+	c.SetPosition(50, 0, 0)
+	s.updateFloatingOrigin(c)
+	assertVec3(t, &math.Vec3{0, 0, 0}, node.GetPosition(), 1e-6)
+
+	// Rebased once the camera is too far.
+	c.SetPosition(150, 0, 0)
+	s.updateFloatingOrigin(c)
+	assertVec3(t, &math.Vec3{-150, 0, 0}, node.GetPosition(), 1e-6)
+	assertVec3(t, &math.Vec3{0, 0, 0}, c.GetPosition(), 1e-6)
+}
@@ -1,6 +1,7 @@
 package dax
 
 import (
+	"fmt"
 	"image"
 	"unsafe"
 
@@ -13,20 +14,152 @@ type Framebuffer interface {
 
 	GetCamera() Camera
 	SetCamera(camera Camera)
+
+	// SetViewport sets the rectangle of the framebuffer drawn into.
 	SetViewport(x, y, width, height int)
+	// Viewport returns the current viewport.
+	Viewport() Viewport
+	// SetScissor discards the pixels outside of a rectangle of the
+	// framebuffer, until DisableScissor is called.
+	SetScissor(x, y, width, height int)
+	DisableScissor()
+	// PushViewport saves the current viewport and scissor before setting a
+	// new viewport. PopViewport restores them, so nested passes can change
+	// the viewport without disturbing the pass around them.
+	PushViewport(x, y, width, height int)
+	PopViewport()
 
 	Draw(d Drawer)
 
 	Screenshot() *image.RGBA
+	// ReadPixels returns the color of the pixels of rect, in framebuffer
+	// coordinates, as RGBA bytes. Rows are tightly packed, from the bottom
+	// of rect to its top. rect is clamped to the framebuffer first, see
+	// Viewport.Clamp, and nil is returned when nothing is left.
+	ReadPixels(rect Viewport) []byte
+	// ReadDepth returns the depth of the pixels of rect, in [0, 1], laid
+	// out as ReadPixels does. Windows drawing their scene offscreen, for
+	// post-processing or display adjustments, return the depth of the
+	// scene.
+	ReadDepth(rect Viewport) []float32
+	// ReadPixelsAsync is ReadPixels without waiting for the GPU: the pixels
+	// are copied into a GPU buffer and done is called with them, from the
+	// rendering loop, at the end of the first frame the copy is over,
+	// usually a frame or two later.
+	ReadPixelsAsync(rect Viewport, done func(pixels []byte))
+	// ReadDepthAsync is ReadDepth without waiting for the GPU, see
+	// ReadPixelsAsync.
+	ReadDepthAsync(rect Viewport, done func(depth []float32))
+
+	// SetReversedZ switches the framebuffer to a reversed depth range: the
+	// near plane is mapped to a depth of 1 and the far plane to 0, which,
+	// with a floating point depth buffer, gives a much better depth
+	// precision. Perspective cameras drawing into the framebuffer use a
+	// reversed projection, without being changed.
+	SetReversedZ(reversed bool)
+	IsReversedZ() bool
+
+	// ColorTexture returns the texture the i-th color attachment is
+	// rendered into or nil if the framebuffer doesn't have such a texture.
+	ColorTexture(i int) *Texture
+	// DepthTexture returns the texture the depth is rendered into or nil if
+	// the framebuffer doesn't have one.
+	DepthTexture() *Texture
+
+	// BlitTo copies the color of the framebuffer into dst, scaling it to
+	// the size of dst with filter.
+	BlitTo(dst Framebuffer, filter BlitFilter)
 
 	// private
 	render() *renderer
+	glID() uint32
+}
+
+// BlitFilter is the interpolation used when a blit scales an image.
+type BlitFilter int
+
+const (
+	// BlitFilterNearest picks the nearest pixel.
+	BlitFilterNearest BlitFilter = iota
+	// BlitFilterLinear linearly interpolates between the 4 nearest
+	// pixels.
+	BlitFilterLinear
+)
+
+func (f BlitFilter) glFilter() uint32 {
+	if f == BlitFilterLinear {
+		return gl.LINEAR
+	}
+	return gl.NEAREST
+}
+
+// applyViewport sets the GL viewport and scissor for the framebuffer currently
+// bound.
+func applyViewport(s *viewportState) {
+	v := &s.viewport
+	gl.Viewport(int32(v.X), int32(v.Y), int32(v.Width), int32(v.Height))
+	if s.scissorEnabled {
+		v = &s.scissor
+		gl.Enable(gl.SCISSOR_TEST)
+		gl.Scissor(int32(v.X), int32(v.Y), int32(v.Width), int32(v.Height))
+	} else {
+		gl.Disable(gl.SCISSOR_TEST)
+	}
+}
+
+// clampRead clamps rect, given to the ReadPixels family, to fb. ok is false
+// when there's nothing left to read.
+func clampRead(fb Framebuffer, rect Viewport) (clamped Viewport, ok bool) {
+	width, height := fb.Size()
+	clamped = rect.Clamp(width, height)
+	return clamped, clamped.Width > 0
+}
+
+// readPixels reads rect from the framebuffer currently bound into pixels,
+// with tightly packed rows whatever their size. rect must have been clamped.
+func readPixels(fb Framebuffer, rect *Viewport, format, xtype uint32, pixels unsafe.Pointer) {
+	width, height := fb.Size()
+	if !rect.inside(width, height) {
+		panic(fmt.Sprintf("framebuffer: can't read %dx%d pixels at (%d, %d) of a %dx%d framebuffer",
+			rect.Width, rect.Height, rect.X, rect.Y, width, height))
+	}
+
+	var alignment int32
+	gl.GetIntegerv(gl.PACK_ALIGNMENT, &alignment)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(int32(rect.X), int32(rect.Y), int32(rect.Width), int32(rect.Height),
+		format, xtype, pixels)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, alignment)
+}
+
+// blit copies the color of the src framebuffer object, of size sw x sh, into
+// dst.
+func blit(src uint32, sw, sh int, dst Framebuffer, filter BlitFilter) {
+	var previous int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &previous)
+
+	dw, dh := dst.Size()
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, src)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, dst.glID())
+	gl.BlitFramebuffer(0, 0, int32(sw), int32(sh), 0, 0, int32(dw), int32(dh),
+		gl.COLOR_BUFFER_BIT, filter.glFilter())
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(previous))
 }
 
 type onScreen struct {
 	renderer      *renderer
 	width, height int
 	camera        Camera
+	reversedZ     bool
+	viewport      viewportState
+	// target is the framebuffer object standing for the window while a
+	// display pass draws the frame offscreen, 0 otherwise.
+	target uint32
+	// depth is the offscreen target the scene was last drawn into, eg. for
+	// post-processing, nil when it was drawn into the window. Depth is read
+	// from it.
+	depth *OffScreen
 }
 
 func newOnScreen(width, height int) *onScreen {
@@ -47,10 +180,42 @@ func (fb *onScreen) SetCamera(camera Camera) {
 	fb.camera = camera
 }
 
+// SetReversedZ implements Framebuffer. The window depth buffer is a fixed
+// point buffer: to get the full precision benefits of reversed-Z, draw into an
+// OffScreen framebuffer instead.
+func (fb *onScreen) SetReversedZ(reversed bool) {
+	fb.reversedZ = reversed
+}
+
+func (fb *onScreen) IsReversedZ() bool {
+	return fb.reversedZ
+}
+
 func (fb *onScreen) render() *renderer {
 	return fb.renderer
 }
 
+// The window is the default framebuffer.
+func (fb *onScreen) glID() uint32 {
+	return fb.target
+}
+
+// ColorTexture implements Framebuffer. The window doesn't render into
+// textures: it always returns nil.
+func (fb *onScreen) ColorTexture(i int) *Texture {
+	return nil
+}
+
+// DepthTexture implements Framebuffer. The window doesn't render into
+// textures: it always returns nil.
+func (fb *onScreen) DepthTexture() *Texture {
+	return nil
+}
+
+func (fb *onScreen) BlitTo(dst Framebuffer, filter BlitFilter) {
+	blit(0, fb.width, fb.height, dst, filter)
+}
+
 func (fb *onScreen) Draw(d Drawer) {
 	d.Draw(fb)
 }
@@ -61,10 +226,41 @@ func (fb *onScreen) SetSize(width, height int) {
 }
 
 func (fb *onScreen) SetViewport(x, y, width, height int) {
-	gl.Viewport(int32(x), int32(y), int32(width), int32(height))
+	fb.viewport.setViewport(x, y, width, height)
+	applyViewport(&fb.viewport)
+}
+
+func (fb *onScreen) Viewport() Viewport {
+	return fb.viewport.viewport
+}
+
+func (fb *onScreen) SetScissor(x, y, width, height int) {
+	fb.viewport.setScissor(x, y, width, height)
+	applyViewport(&fb.viewport)
+}
+
+func (fb *onScreen) DisableScissor() {
+	fb.viewport.disableScissor()
+	applyViewport(&fb.viewport)
+}
+
+func (fb *onScreen) PushViewport(x, y, width, height int) {
+	fb.viewport.push()
+	fb.SetViewport(x, y, width, height)
 }
 
+func (fb *onScreen) PopViewport() {
+	fb.viewport.pop()
+	applyViewport(&fb.viewport)
+}
+
+// Screenshot implements Framebuffer. Minimized windows, with a 0x0
+// framebuffer, give an empty image.
 func (fb *onScreen) Screenshot() *image.RGBA {
+	if fb.width == 0 || fb.height == 0 {
+		return &image.RGBA{}
+	}
+
 	pixels := make([]byte, fb.width*fb.height*4)
 
 	gl.ReadPixels(0, 0, int32(fb.width), int32(fb.height), gl.RGBA,
@@ -76,3 +272,572 @@ func (fb *onScreen) Screenshot() *image.RGBA {
 		Rect:   image.Rect(0, 0, fb.width, fb.height),
 	}
 }
+
+// ReadPixels implements Framebuffer.
+func (fb *onScreen) ReadPixels(rect Viewport) []byte {
+	rect, ok := clampRead(fb, rect)
+	if !ok {
+		return nil
+	}
+
+	pixels := make([]byte, rect.Width*rect.Height*4)
+	readPixels(fb, &rect, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	return pixels
+}
+
+// depthTarget returns the offscreen target holding the depth of the last frame,
+// nil when it's the window itself. Destroyed targets are ignored.
+func (fb *onScreen) depthTarget() *OffScreen {
+	if fb.depth == nil || fb.depth.depth == nil {
+		return nil
+	}
+	return fb.depth
+}
+
+// ReadDepth implements Framebuffer.
+func (fb *onScreen) ReadDepth(rect Viewport) []float32 {
+	if fb.depthTarget() != nil {
+		return fb.depth.ReadDepth(rect)
+	}
+
+	rect, ok := clampRead(fb, rect)
+	if !ok {
+		return nil
+	}
+
+	depth := make([]float32, rect.Width*rect.Height)
+	readPixels(fb, &rect, gl.DEPTH_COMPONENT, gl.FLOAT, gl.Ptr(depth))
+	return depth
+}
+
+// ReadPixelsAsync implements Framebuffer.
+func (fb *onScreen) ReadPixelsAsync(rect Viewport, done func(pixels []byte)) {
+	rect, ok := clampRead(fb, rect)
+	if !ok {
+		done(nil)
+		return
+	}
+
+	fb.render().readPixelsAsync(fb, &rect, gl.RGBA, gl.UNSIGNED_BYTE, 4, done)
+}
+
+// ReadDepthAsync implements Framebuffer.
+func (fb *onScreen) ReadDepthAsync(rect Viewport, done func(depth []float32)) {
+	if fb.depthTarget() != nil {
+		fb.depth.ReadDepthAsync(rect, done)
+		return
+	}
+
+	rect, ok := clampRead(fb, rect)
+	if !ok {
+		done(nil)
+		return
+	}
+
+	fb.render().readPixelsAsync(fb, &rect, gl.DEPTH_COMPONENT, gl.FLOAT, 4, func(data []byte) {
+		done(bytesToFloat32s(data))
+	})
+}
+
+// OffScreenOptions configure the attachments of an OffScreen framebuffer.
+type OffScreenOptions struct {
+	// ColorFormat is the format of the color attachment. It defaults to
+	// TextureFormatRGBA8.
+	ColorFormat TextureFormat
+	// DepthFormat is the format of the depth attachment. It defaults to
+	// TextureFormatDepth24. Formats with a stencil, eg.
+	// TextureFormatDepth24Stencil8, give the framebuffer a stencil buffer.
+	DepthFormat TextureFormat
+	// Samples is the number of samples per pixel of a multisampled
+	// framebuffer. Multisampled framebuffers draw into renderbuffers that
+	// are resolved into the color and depth textures after each Draw and
+	// Clear, unless ManualResolve is set. 0 and 1 disable multisampling.
+	Samples int
+	// ManualResolve leaves resolving a multisampled framebuffer to
+	// Resolve, eg. to draw several passes into the multisampled buffers
+	// and resolve them once, before the textures are used.
+	ManualResolve bool
+	// NoColor creates a depth only framebuffer, eg. for shadow maps.
+	NoColor bool
+	// ReversedZ creates a reversed-Z framebuffer, see
+	// Framebuffer.SetReversedZ. The depth format then defaults to
+	// TextureFormatDepth32F.
+	ReversedZ bool
+}
+
+// OffScreen is a framebuffer rendering into textures rather than on screen.
+// The textures can then be used in a later pass, eg. for post-processing,
+// shadow maps or picking buffers.
+type OffScreen struct {
+	renderer      *renderer
+	options       OffScreenOptions
+	width, height int
+	viewport      viewportState
+	camera        Camera
+	id            uint32
+	color, depth  *Texture
+	// msaa is the multisampled framebuffer drawn into, resolved into id,
+	// when options.Samples > 1.
+	msaa          uint32
+	renderbuffers []uint32
+	// defaultDepth is true when the user didn't choose a depth format.
+	defaultDepth bool
+	// bound is non-zero while the framebuffer is bound.
+	bound int
+}
+
+var _ Framebuffer = &OffScreen{}
+
+// NewOffScreen creates an OffScreen framebuffer of the given size. It shares
+// the renderer of fb, usually the framebuffer given to Scene.Setup, so GL
+// programs are only compiled once. A GL context must be current.
+func NewOffScreen(fb Framebuffer, width, height int, options ...OffScreenOptions) *OffScreen {
+	o := &OffScreen{
+		renderer: fb.render(),
+	}
+	if len(options) > 0 {
+		o.options = options[0]
+	}
+	if o.options.ColorFormat == textureFormatDefault {
+		o.options.ColorFormat = TextureFormatRGBA8
+	}
+	if o.options.DepthFormat == textureFormatDefault {
+		o.defaultDepth = true
+		o.options.DepthFormat = defaultDepthFormat(o.options.ReversedZ)
+	}
+	if o.options.ColorFormat.IsDepth() {
+		panic("offscreen: invalid color format")
+	}
+	if !o.options.DepthFormat.IsDepth() {
+		panic("offscreen: invalid depth format")
+	}
+
+	o.SetSize(width, height)
+
+	return o
+}
+
+func defaultDepthFormat(reversedZ bool) TextureFormat {
+	if reversedZ {
+		return TextureFormatDepth32F
+	}
+	return TextureFormatDepth24
+}
+
+// depthAttachment returns the attachment point of a depth texture of format f.
+func depthAttachment(f TextureFormat) uint32 {
+	if f.HasStencil() {
+		return gl.DEPTH_STENCIL_ATTACHMENT
+	}
+	return gl.DEPTH_ATTACHMENT
+}
+
+// checkFramebuffer panics if the framebuffer currently bound is incomplete.
+func checkFramebuffer() {
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		panic(fmt.Sprintf("offscreen: incomplete framebuffer (0x%x)", status))
+	}
+}
+
+func (o *OffScreen) allocate() {
+	gl.GenFramebuffers(1, &o.id)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, o.id)
+
+	if !o.options.NoColor {
+		o.color = newTexture(o.width, o.height, o.options.ColorFormat)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0,
+			gl.TEXTURE_2D, o.color.id, 0)
+	} else {
+		gl.DrawBuffer(gl.NONE)
+		gl.ReadBuffer(gl.NONE)
+	}
+
+	o.depth = newTexture(o.width, o.height, o.options.DepthFormat)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, depthAttachment(o.options.DepthFormat),
+		gl.TEXTURE_2D, o.depth.id, 0)
+	checkFramebuffer()
+
+	if o.options.Samples > 1 {
+		o.allocateMultisampled()
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// allocateMultisampled allocates the multisampled framebuffer, with
+// renderbuffers of the formats of the textures.
+func (o *OffScreen) allocateMultisampled() {
+	gl.GenFramebuffers(1, &o.msaa)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, o.msaa)
+
+	addRenderbuffer := func(format TextureFormat, attachment uint32) {
+		var id uint32
+		internalFormat, _, _ := format.glFormat()
+		gl.GenRenderbuffers(1, &id)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, id)
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(o.options.Samples),
+			uint32(internalFormat), int32(o.width), int32(o.height))
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, attachment, gl.RENDERBUFFER, id)
+		o.renderbuffers = append(o.renderbuffers, id)
+	}
+
+	if !o.options.NoColor {
+		addRenderbuffer(o.options.ColorFormat, gl.COLOR_ATTACHMENT0)
+	} else {
+		gl.DrawBuffer(gl.NONE)
+		gl.ReadBuffer(gl.NONE)
+	}
+	addRenderbuffer(o.options.DepthFormat, depthAttachment(o.options.DepthFormat))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	checkFramebuffer()
+}
+
+// resolve copies the multisampled framebuffer into the textures.
+func (o *OffScreen) resolve() {
+	if o.msaa == 0 {
+		return
+	}
+
+	var previous int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &previous)
+
+	mask := uint32(gl.DEPTH_BUFFER_BIT)
+	if o.color != nil {
+		mask |= gl.COLOR_BUFFER_BIT
+	}
+	if o.options.DepthFormat.HasStencil() {
+		mask |= gl.STENCIL_BUFFER_BIT
+	}
+
+	// The scissor test applies to blits.
+	scissorEnabled := gl.IsEnabled(gl.SCISSOR_TEST)
+	gl.Disable(gl.SCISSOR_TEST)
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, o.msaa)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, o.id)
+	gl.BlitFramebuffer(0, 0, int32(o.width), int32(o.height),
+		0, 0, int32(o.width), int32(o.height), mask, gl.NEAREST)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(previous))
+	if scissorEnabled {
+		gl.Enable(gl.SCISSOR_TEST)
+	}
+}
+
+func (o *OffScreen) release() {
+	gc := &o.renderer.garbage
+	if o.color != nil {
+		o.color.release(gc)
+		o.color = nil
+	}
+	if o.depth != nil {
+		o.depth.release(gc)
+		o.depth = nil
+	}
+	for _, id := range o.renderbuffers {
+		gc.release(glObjectRenderbuffer, id)
+	}
+	o.renderbuffers = nil
+	gc.release(glObjectFramebuffer, o.msaa)
+	o.msaa = 0
+	gc.release(glObjectFramebuffer, o.id)
+	o.id = 0
+}
+
+// Destroy releases the GL resources of the framebuffer.
+func (o *OffScreen) Destroy() {
+	o.release()
+}
+
+// ColorTexture returns the texture the color is rendered into or nil for a
+// depth only framebuffer. An OffScreen framebuffer has a single color
+// attachment, i must be 0. The texture changes when the framebuffer is
+// resized.
+func (o *OffScreen) ColorTexture(i int) *Texture {
+	if i != 0 {
+		return nil
+	}
+	return o.color
+}
+
+// DepthTexture returns the texture the depth is rendered into. The texture
+// changes when the framebuffer is resized.
+func (o *OffScreen) DepthTexture() *Texture {
+	return o.depth
+}
+
+// BlitTo implements Framebuffer. A depth only framebuffer has no color to
+// copy: nothing is done.
+func (o *OffScreen) BlitTo(dst Framebuffer, filter BlitFilter) {
+	if o.color == nil {
+		return
+	}
+	blit(o.id, o.width, o.height, dst, filter)
+}
+
+func (o *OffScreen) Size() (width, height int) {
+	return o.width, o.height
+}
+
+// SetSize resizes the framebuffer, reallocating its textures, and resets the
+// viewport to the whole framebuffer.
+func (o *OffScreen) SetSize(width, height int) {
+	if o.id != 0 && width == o.width && height == o.height {
+		return
+	}
+
+	o.release()
+	o.width = width
+	o.height = height
+	o.viewport.setViewport(0, 0, width, height)
+	o.allocate()
+}
+
+func (o *OffScreen) GetCamera() Camera {
+	return o.camera
+}
+
+func (o *OffScreen) SetCamera(camera Camera) {
+	o.camera = camera
+}
+
+func (o *OffScreen) SetViewport(x, y, width, height int) {
+	o.viewport.setViewport(x, y, width, height)
+	o.applyViewport()
+}
+
+func (o *OffScreen) Viewport() Viewport {
+	return o.viewport.viewport
+}
+
+func (o *OffScreen) SetScissor(x, y, width, height int) {
+	o.viewport.setScissor(x, y, width, height)
+	o.applyViewport()
+}
+
+func (o *OffScreen) DisableScissor() {
+	o.viewport.disableScissor()
+	o.applyViewport()
+}
+
+func (o *OffScreen) PushViewport(x, y, width, height int) {
+	o.viewport.push()
+	o.SetViewport(x, y, width, height)
+}
+
+func (o *OffScreen) PopViewport() {
+	o.viewport.pop()
+	o.applyViewport()
+}
+
+// applyViewport updates the GL state when o is bound. Otherwise, the state is
+// applied the next time o is bound.
+func (o *OffScreen) applyViewport() {
+	if o.bound > 0 {
+		applyViewport(&o.viewport)
+	}
+}
+
+// SetReversedZ implements Framebuffer. Unless a depth format was given at
+// creation, the depth texture is reallocated with a floating point format when
+// switching to reversed-Z.
+func (o *OffScreen) SetReversedZ(reversed bool) {
+	if reversed == o.options.ReversedZ {
+		return
+	}
+	o.options.ReversedZ = reversed
+
+	if o.defaultDepth {
+		o.options.DepthFormat = defaultDepthFormat(reversed)
+		o.release()
+		o.allocate()
+	}
+}
+
+func (o *OffScreen) IsReversedZ() bool {
+	return o.options.ReversedZ
+}
+
+func (o *OffScreen) render() *renderer {
+	return o.renderer
+}
+
+// Samples returns the number of samples per pixel of the framebuffer, 1 when
+// it isn't multisampled.
+func (o *OffScreen) Samples() int {
+	if o.msaa == 0 {
+		return 1
+	}
+	return o.options.Samples
+}
+
+// Resolve resolves a multisampled framebuffer into its color and depth
+// textures. It's only needed for framebuffers created with ManualResolve, the
+// other ones are resolved after each Draw and Clear. It does nothing for
+// framebuffers which aren't multisampled.
+func (o *OffScreen) Resolve() {
+	o.resolve()
+}
+
+// autoResolve resolves o after drawing into it, unless it's resolved manually.
+func (o *OffScreen) autoResolve() {
+	if !o.options.ManualResolve {
+		o.resolve()
+	}
+}
+
+// glID returns the framebuffer object drawn into.
+func (o *OffScreen) glID() uint32 {
+	if o.msaa != 0 {
+		return o.msaa
+	}
+	return o.id
+}
+
+// bind makes the framebuffer object id of o, o.glID() to draw or o.id to read
+// back the textures, the current framebuffer and returns a function restoring
+// the previous one.
+func (o *OffScreen) bind(id uint32) func() {
+	var previous int32
+	var viewport, scissor [4]int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &previous)
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	gl.GetIntegerv(gl.SCISSOR_BOX, &scissor[0])
+	scissorEnabled := gl.IsEnabled(gl.SCISSOR_TEST)
+	// Colors are only converted to sRGB when drawing into sRGB textures
+	// with FRAMEBUFFER_SRGB enabled.
+	srgbEnabled := gl.IsEnabled(gl.FRAMEBUFFER_SRGB)
+	srgb := o.color != nil && o.options.ColorFormat.IsSRGB()
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, id)
+	o.bound++
+	applyViewport(&o.viewport)
+	if srgb && !srgbEnabled {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+
+	return func() {
+		o.bound--
+		if srgb && !srgbEnabled {
+			gl.Disable(gl.FRAMEBUFFER_SRGB)
+		}
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(previous))
+		gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+		gl.Scissor(scissor[0], scissor[1], scissor[2], scissor[3])
+		if scissorEnabled {
+			gl.Enable(gl.SCISSOR_TEST)
+		} else {
+			gl.Disable(gl.SCISSOR_TEST)
+		}
+	}
+}
+
+// Clear clears the color attachment to c, the depth attachment to the far
+// plane of the framebuffer camera and the stencil, if any, to 0.
+func (o *OffScreen) Clear(c *Color) {
+	defer o.autoResolve()
+	defer o.bind(o.glID())()
+
+	mask := uint32(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	if o.options.DepthFormat.HasStencil() {
+		gl.ClearStencil(0)
+		mask |= gl.STENCIL_BUFFER_BIT
+	}
+
+	o.renderer.setDepthState(o)
+	gl.ClearColor(c.R, c.G, c.B, c.A)
+	gl.Clear(mask)
+}
+
+// Draw implements Framebuffer. Multisampled framebuffers are resolved into
+// their textures once d is drawn, unless the framebuffer was created with
+// ManualResolve.
+func (o *OffScreen) Draw(d Drawer) {
+	defer o.autoResolve()
+	defer o.bind(o.glID())()
+
+	d.Draw(o)
+}
+
+// Screenshot implements Framebuffer. It returns nil for depth only
+// framebuffers and an empty image for 0x0 ones.
+func (o *OffScreen) Screenshot() *image.RGBA {
+	if o.color == nil {
+		return nil
+	}
+	if o.width == 0 || o.height == 0 {
+		return &image.RGBA{}
+	}
+
+	defer o.bind(o.id)()
+
+	pixels := make([]byte, o.width*o.height*4)
+	gl.ReadPixels(0, 0, int32(o.width), int32(o.height), gl.RGBA,
+		gl.UNSIGNED_BYTE, unsafe.Pointer(&pixels[0]))
+
+	return &image.RGBA{
+		Pix:    pixels,
+		Stride: o.width * 4,
+		Rect:   image.Rect(0, 0, o.width, o.height),
+	}
+}
+
+// ReadPixels implements Framebuffer. It returns nil for depth only
+// framebuffers.
+func (o *OffScreen) ReadPixels(rect Viewport) []byte {
+	rect, ok := clampRead(o, rect)
+	if o.color == nil || !ok {
+		return nil
+	}
+
+	defer o.bind(o.id)()
+
+	pixels := make([]byte, rect.Width*rect.Height*4)
+	readPixels(o, &rect, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	return pixels
+}
+
+// ReadDepth implements Framebuffer.
+func (o *OffScreen) ReadDepth(rect Viewport) []float32 {
+	rect, ok := clampRead(o, rect)
+	if !ok {
+		return nil
+	}
+
+	defer o.bind(o.id)()
+
+	depth := make([]float32, rect.Width*rect.Height)
+	readPixels(o, &rect, gl.DEPTH_COMPONENT, gl.FLOAT, gl.Ptr(depth))
+	return depth
+}
+
+// ReadPixelsAsync implements Framebuffer. done is called with nil right away
+// for depth only framebuffers.
+func (o *OffScreen) ReadPixelsAsync(rect Viewport, done func(pixels []byte)) {
+	rect, ok := clampRead(o, rect)
+	if o.color == nil || !ok {
+		done(nil)
+		return
+	}
+
+	defer o.bind(o.id)()
+
+	o.renderer.readPixelsAsync(o, &rect, gl.RGBA, gl.UNSIGNED_BYTE, 4, done)
+}
+
+// ReadDepthAsync implements Framebuffer.
+func (o *OffScreen) ReadDepthAsync(rect Viewport, done func(depth []float32)) {
+	rect, ok := clampRead(o, rect)
+	if !ok {
+		done(nil)
+		return
+	}
+
+	defer o.bind(o.id)()
+
+	o.renderer.readPixelsAsync(o, &rect, gl.DEPTH_COMPONENT, gl.FLOAT, 4, func(data []byte) {
+		done(bytesToFloat32s(data))
+	})
+}
@@ -0,0 +1,86 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffScreenScreenshot(t *testing.T) {
+	// 0x0 framebuffers give an empty image.
+	o := &OffScreen{color: &Texture{}}
+	img := o.Screenshot()
+	assert.NotNil(t, img)
+	assert.True(t, img.Bounds().Empty())
+
+	o.width = 16
+	img = o.Screenshot()
+	assert.NotNil(t, img)
+	assert.True(t, img.Bounds().Empty())
+
+	// Depth only framebuffers don't have a color to read.
+	o = &OffScreen{depth: &Texture{}, width: 16, height: 16}
+	assert.Nil(t, o.Screenshot())
+}
+
+func TestOffScreenTextures(t *testing.T) {
+	color, depth := &Texture{}, &Texture{}
+	o := &OffScreen{color: color, depth: depth}
+	assert.Equal(t, color, o.ColorTexture(0))
+	assert.Nil(t, o.ColorTexture(1))
+	assert.Equal(t, depth, o.DepthTexture())
+
+	o = &OffScreen{depth: depth}
+	assert.Nil(t, o.ColorTexture(0))
+	// Nothing to blit.
+	o.BlitTo(nil, BlitFilterNearest)
+}
+
+func TestOffScreenSamples(t *testing.T) {
+	// Framebuffers without a multisampled framebuffer have one sample,
+	// whatever they asked for.
+	o := &OffScreen{}
+	assert.Equal(t, 1, o.Samples())
+	o.options.Samples = 4
+	assert.Equal(t, 1, o.Samples())
+	o.msaa = 2
+	assert.Equal(t, 4, o.Samples())
+}
+
+func TestOffScreenViewport(t *testing.T) {
+	// The viewport of unbound framebuffers is applied when they are bound.
+	o := &OffScreen{width: 64, height: 32}
+	o.viewport.setViewport(0, 0, 64, 32)
+
+	o.PushViewport(8, 8, 16, 16)
+	assert.Equal(t, Viewport{8, 8, 16, 16}, o.Viewport())
+	o.SetViewport(0, 0, 32, 16)
+	assert.Equal(t, Viewport{0, 0, 32, 16}, o.Viewport())
+	o.PopViewport()
+	assert.Equal(t, Viewport{0, 0, 64, 32}, o.Viewport())
+}
+
+func TestOffScreenRead(t *testing.T) {
+	// Depth only framebuffers don't have pixels.
+	o := &OffScreen{width: 64, height: 32, depth: &Texture{}}
+	rect := Viewport{0, 0, 4, 4}
+	assert.Nil(t, o.ReadPixels(rect))
+	called := false
+	o.ReadPixelsAsync(rect, func(pixels []byte) {
+		assert.Nil(t, pixels)
+		called = true
+	})
+	assert.True(t, called)
+
+	// Nothing is read outside of the framebuffer.
+	o.color = &Texture{}
+	rect = Viewport{64, 0, 4, 4}
+	assert.Nil(t, o.ReadPixels(rect))
+	assert.Nil(t, o.ReadDepth(rect))
+	called = false
+	o.ReadDepthAsync(Viewport{-8, -8, 8, 8}, func(depth []float32) {
+		assert.Nil(t, depth)
+		called = true
+	})
+	assert.True(t, called)
+}
@@ -139,6 +139,21 @@ func buildPlane(ctx *boxContext,
 	ctx.nVertices += vertexCounter
 }
 
+// Bounds implements dax.Bounder.
+func (b *Box) Bounds() math.AABB {
+	half := math.Vec3{b.Width / 2, b.Height / 2, b.Depth / 2}
+	return math.AABB{
+		Min: half.Mul(-1),
+		Max: half,
+	}
+}
+
+// BoundingSphere implements dax.SphereBounder.
+func (b *Box) BoundingSphere() math.BoundingSphere {
+	half := math.Vec3{b.Width / 2, b.Height / 2, b.Depth / 2}
+	return math.BoundingSphere{Radius: half.Len()}
+}
+
 // GetMesh is part of the dax.Mesher interface.
 func (b *Box) GetMesh() *dax.Mesh {
 
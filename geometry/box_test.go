@@ -3,6 +3,7 @@ package geometry
 import (
 	"testing"
 
+	"github.com/dlespiau/dax/math"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,3 +51,17 @@ func TestBoxMesh(t *testing.T) {
 	// 9 vertices, 6 faces, 2 components per vertex
 	assert.Equal(t, 9*6*2, len(uvs.Data))
 }
+
+func TestBoxBounds(t *testing.T) {
+	box := NewBox(10, 20, 30)
+
+	bounds := box.Bounds()
+	assert.Equal(t, math.Vec3{-5, -10, -15}, bounds.Min)
+	assert.Equal(t, math.Vec3{5, 10, 15}, bounds.Max)
+
+	// The analytic box is the one of the generated mesh.
+	assert.Equal(t, bounds, box.GetMesh().Bounds())
+
+	sphere := box.BoundingSphere()
+	assert.Equal(t, bounds.BoundingSphere(), sphere)
+}
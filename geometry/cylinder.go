@@ -0,0 +1,191 @@
+package geometry
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Cylinder is a cylinder of height Height along the Y axis, centered around
+// (0, 0, 0). The top and bottom radii can differ, giving a truncated cone, or
+// a cone when one of them is 0.
+type Cylinder struct {
+	RadiusTop, RadiusBottom, Height      float32
+	NumRadialSegments, NumHeightSegments int
+	// OpenEnded cylinders don't have caps.
+	OpenEnded bool
+}
+
+// CylinderOptions contains optional parameters for the Cylinder and Cone
+// constructors.
+type CylinderOptions struct {
+	NumRadialSegments, NumHeightSegments int
+	OpenEnded                            bool
+}
+
+var defaultCylinder = Cylinder{
+	RadiusTop:         1.0,
+	RadiusBottom:      1.0,
+	Height:            1.0,
+	NumRadialSegments: 32,
+	NumHeightSegments: 1,
+}
+
+// NewCylinder creates a new cylinder.
+func NewCylinder(radiusTop, radiusBottom, height float32, options ...CylinderOptions) *Cylinder {
+	c := defaultCylinder
+	c.RadiusTop = radiusTop
+	c.RadiusBottom = radiusBottom
+	c.Height = height
+
+	if len(options) == 0 {
+		return &c
+	}
+
+	if options[0].NumRadialSegments > 0 {
+		c.NumRadialSegments = options[0].NumRadialSegments
+	}
+	if options[0].NumHeightSegments > 0 {
+		c.NumHeightSegments = options[0].NumHeightSegments
+	}
+	c.OpenEnded = options[0].OpenEnded
+
+	return &c
+}
+
+// NewCone creates a new cone, pointing up.
+func NewCone(radius, height float32, options ...CylinderOptions) *Cylinder {
+	return NewCylinder(0, radius, height, options...)
+}
+
+// Bounds implements dax.Bounder.
+func (c *Cylinder) Bounds() math.AABB {
+	r := math.Max(c.RadiusTop, c.RadiusBottom)
+	return math.AABB{
+		Min: math.Vec3{-r, -c.Height / 2, -r},
+		Max: math.Vec3{r, c.Height / 2, r},
+	}
+}
+
+// BoundingSphere implements dax.SphereBounder.
+func (c *Cylinder) BoundingSphere() math.BoundingSphere {
+	r := math.Max(c.RadiusTop, c.RadiusBottom)
+	return math.BoundingSphere{Radius: math.Sqrt(r*r + c.Height*c.Height/4)}
+}
+
+type cylinderContext struct {
+	nVertices int
+	positions []float32
+	normals   []float32
+	uvs       []float32
+	indices   []uint
+}
+
+func (c *Cylinder) buildTorso(ctx *cylinderContext) {
+	halfHeight := c.Height / 2
+	// The normals are scaled by the height, rather than using the slope of
+	// the torso, to stay finite for flat cylinders.
+	height := math.Abs(c.Height)
+	gridX1 := c.NumRadialSegments + 1
+
+	normal := math.Vec3{}
+
+	for y := 0; y <= c.NumHeightSegments; y++ {
+		v := float32(y) / float32(c.NumHeightSegments)
+		radius := v*(c.RadiusBottom-c.RadiusTop) + c.RadiusTop
+
+		for x := 0; x <= c.NumRadialSegments; x++ {
+			u := float32(x) / float32(c.NumRadialSegments)
+			sin, cos := math.Sincos(u * 2 * math.Pi)
+
+			ctx.positions = append(ctx.positions, radius*sin, -v*c.Height+halfHeight, radius*cos)
+
+			normal.Set(sin*height, c.RadiusBottom-c.RadiusTop, cos*height)
+			if normal.Len2() == 0 {
+				// Flat cylinder with equal radii: the torso has no area.
+				normal.Set(sin, 0, cos)
+			}
+			normal.Normalize()
+			ctx.normals = append(ctx.normals, normal[0], normal[1], normal[2])
+
+			ctx.uvs = append(ctx.uvs, u, 1-v)
+		}
+	}
+
+	for y := 0; y < c.NumHeightSegments; y++ {
+		for x := 0; x < c.NumRadialSegments; x++ {
+			v1 := uint(ctx.nVertices + x + gridX1*y)
+			v2 := uint(ctx.nVertices + x + gridX1*(y+1))
+			v3 := uint(ctx.nVertices + (x + 1) + gridX1*(y+1))
+			v4 := uint(ctx.nVertices + (x + 1) + gridX1*y)
+
+			ctx.indices = append(ctx.indices, v1, v2, v4, v2, v3, v4)
+		}
+	}
+
+	ctx.nVertices += gridX1 * (c.NumHeightSegments + 1)
+}
+
+func (c *Cylinder) buildCap(ctx *cylinderContext, top bool) {
+	radius, sign := c.RadiusBottom, float32(-1)
+	if top {
+		radius, sign = c.RadiusTop, 1
+	}
+	if radius == 0 {
+		// The tip of a cone.
+		return
+	}
+
+	y := sign * c.Height / 2
+
+	// One center vertex per segment, so each triangle has its own texture
+	// coordinates.
+	centerStart := ctx.nVertices
+	for x := 0; x < c.NumRadialSegments; x++ {
+		ctx.positions = append(ctx.positions, 0, y, 0)
+		ctx.normals = append(ctx.normals, 0, sign, 0)
+		ctx.uvs = append(ctx.uvs, .5, .5)
+	}
+
+	rimStart := centerStart + c.NumRadialSegments
+	for x := 0; x <= c.NumRadialSegments; x++ {
+		u := float32(x) / float32(c.NumRadialSegments)
+		sin, cos := math.Sincos(u * 2 * math.Pi)
+
+		ctx.positions = append(ctx.positions, radius*sin, y, radius*cos)
+		ctx.normals = append(ctx.normals, 0, sign, 0)
+		ctx.uvs = append(ctx.uvs, cos*.5+.5, sin*.5*sign+.5)
+	}
+
+	for x := 0; x < c.NumRadialSegments; x++ {
+		center := uint(centerStart + x)
+		i := uint(rimStart + x)
+
+		if top {
+			ctx.indices = append(ctx.indices, i, i+1, center)
+		} else {
+			ctx.indices = append(ctx.indices, i+1, i, center)
+		}
+	}
+
+	ctx.nVertices = rimStart + c.NumRadialSegments + 1
+}
+
+// GetMesh is part of the dax.Mesher interface.
+func (c *Cylinder) GetMesh() *dax.Mesh {
+	m := dax.NewMesh()
+
+	ctx := &cylinderContext{}
+
+	c.buildTorso(ctx)
+	if !c.OpenEnded {
+		c.buildCap(ctx, true)
+		c.buildCap(ctx, false)
+	}
+
+	m.AddAttribute("position", ctx.positions, 3)
+	m.AddAttribute("normal", ctx.normals, 3)
+	m.AddAttribute("uv", ctx.uvs, 2)
+	m.AddIndices(ctx.indices)
+
+	return m
+}
@@ -0,0 +1,65 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCylinderNoOptions(t *testing.T) {
+	c := NewCylinder(1, 2, 3)
+	assert.Equal(t, float32(1), c.RadiusTop)
+	assert.Equal(t, float32(2), c.RadiusBottom)
+	assert.Equal(t, float32(3), c.Height)
+	assert.Equal(t, 32, c.NumRadialSegments)
+	assert.Equal(t, 1, c.NumHeightSegments)
+	assert.False(t, c.OpenEnded)
+}
+
+func TestCylinderMesh(t *testing.T) {
+	c := NewCylinder(1, 1, 2, CylinderOptions{
+		NumRadialSegments: 8,
+		NumHeightSegments: 2,
+	})
+	checkMesh(t, c)
+
+	m := c.GetMesh()
+	// 9x3 vertices for the torso, 8 centers and 9 rim vertices per cap.
+	assert.Equal(t, 9*3+2*(8+9), m.GetAttribute("position").Len())
+	assert.Equal(t, (8*2*2+2*8)*3, m.GetIndices().Len())
+
+	c.OpenEnded = true
+	m = c.GetMesh()
+	assert.Equal(t, 9*3, m.GetAttribute("position").Len())
+}
+
+func TestConeMesh(t *testing.T) {
+	c := NewCone(1, 2, CylinderOptions{NumRadialSegments: 8})
+	assert.Equal(t, float32(0), c.RadiusTop)
+	checkMesh(t, c)
+
+	// No cap at the tip.
+	m := c.GetMesh()
+	assert.Equal(t, 9*2+8+9, m.GetAttribute("position").Len())
+}
+
+func TestFlatCylinderNormals(t *testing.T) {
+	for _, c := range []*Cylinder{
+		NewCylinder(1, 1, 0, CylinderOptions{NumRadialSegments: 8}),
+		NewCylinder(.5, 1, 0, CylinderOptions{NumRadialSegments: 8}),
+		NewCone(1, 0, CylinderOptions{NumRadialSegments: 8}),
+	} {
+		normals := c.GetMesh().GetAttribute("normal")
+		for i := 0; i < normals.Len(); i++ {
+			x, y, z := normals.GetXYZ(i)
+			n := math.Vec3{x, y, z}
+			assert.InDelta(t, 1, n.Len(), 1e-5, "normal %d of %v: %v", i, c, n)
+		}
+	}
+
+	// The torso of a flat truncated cone faces up, like its slope.
+	normals := NewCylinder(.5, 1, 0).GetMesh().GetAttribute("normal")
+	_, y, _ := normals.GetXYZ(0)
+	assert.Equal(t, float32(1), y)
+}
@@ -0,0 +1,272 @@
+package geometry
+
+import (
+	"errors"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Mesh is a triangle mesh built from arbitrary vertex data, eg. an imported
+// model or a mesh generated by the application. Positions are mandatory, the
+// other vertex attributes are optional: when not empty, they must have as many
+// elements as Positions.
+//
+// Indices list the vertices of each triangle, counter-clockwise. Without
+// indices, each group of 3 consecutive vertices is a triangle.
+type Mesh struct {
+	Positions []math.Vec3
+	Normals   []math.Vec3
+	UVs       []math.Vec2
+	Colors    []dax.Color
+	// Tangents have the handedness of the bitangent in their 4th component:
+	// bitangent = w * cross(normal, tangent).
+	Tangents []math.Vec4
+	Indices  []uint
+
+	bounds boundsCache
+}
+
+// boundsCache caches the bounding volumes of positions.
+type boundsCache struct {
+	positions []math.Vec3
+	valid     bool
+	box       math.AABB
+	sphere    math.BoundingSphere
+}
+
+// sameSlice returns true if a and b are the same slice of the same array.
+func sameSlice(a, b []math.Vec3) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// cachedBounds returns the bounding volumes of the positions, computed again
+// when Positions is replaced or after InvalidateBounds.
+func (m *Mesh) cachedBounds() *boundsCache {
+	c := &m.bounds
+	if c.valid && sameSlice(c.positions, m.Positions) {
+		return c
+	}
+
+	c.positions = m.Positions
+	c.valid = true
+	c.box = math.EmptyAABB()
+	for i := range m.Positions {
+		c.box.ExtendPoint(&m.Positions[i])
+	}
+
+	if len(m.Positions) == 0 {
+		c.sphere = math.BoundingSphere{Radius: -1}
+		return c
+	}
+	center := c.box.Center()
+	var radius2 float32
+	for i := range m.Positions {
+		d := m.Positions[i].Sub(&center)
+		radius2 = math.Max(radius2, d.Len2())
+	}
+	c.sphere = math.BoundingSphere{Center: center, Radius: math.Sqrt(radius2)}
+	return c
+}
+
+// NewMesh creates a mesh from positions and, optionally, indices.
+func NewMesh(positions []math.Vec3, indices []uint) *Mesh {
+	return &Mesh{
+		Positions: positions,
+		Indices:   indices,
+	}
+}
+
+// numTriangles returns the number of triangles of the mesh.
+func (m *Mesh) numTriangles() int {
+	if len(m.Indices) > 0 {
+		return len(m.Indices) / 3
+	}
+	return len(m.Positions) / 3
+}
+
+// triangle returns the indices of the vertices of the i-th triangle.
+func (m *Mesh) triangle(i int) (a, b, c uint) {
+	if len(m.Indices) > 0 {
+		return m.Indices[3*i], m.Indices[3*i+1], m.Indices[3*i+2]
+	}
+	return uint(3 * i), uint(3*i + 1), uint(3*i + 2)
+}
+
+// ComputeNormals computes smooth vertex normals, replacing Normals. The
+// normal of a vertex is the average of the normals of the triangles sharing
+// it, weighted by their area.
+func (m *Mesh) ComputeNormals() {
+	normals := make([]math.Vec3, len(m.Positions))
+
+	for i := 0; i < m.numTriangles(); i++ {
+		a, b, c := m.triangle(i)
+		e1 := m.Positions[b].Sub(&m.Positions[a])
+		e2 := m.Positions[c].Sub(&m.Positions[a])
+		// The length of the cross product is twice the area of the
+		// triangle.
+		n := e1.Cross(&e2)
+		normals[a].AddWith(&n)
+		normals[b].AddWith(&n)
+		normals[c].AddWith(&n)
+	}
+
+	for i := range normals {
+		if normals[i].Len2() > 0 {
+			normals[i].Normalize()
+		}
+	}
+
+	m.Normals = normals
+}
+
+// ComputeTangents computes vertex tangents from the normals and texture
+// coordinates, replacing Tangents. Normals are computed first if the mesh
+// doesn't have any. An error is returned when the mesh doesn't have texture
+// coordinates.
+func (m *Mesh) ComputeTangents() error {
+	if len(m.UVs) != len(m.Positions) {
+		return errors.New("geometry: computing tangents needs texture coordinates")
+	}
+	if len(m.Normals) != len(m.Positions) {
+		m.ComputeNormals()
+	}
+
+	// Lengyel, "Computing Tangent Space Basis Vectors for an Arbitrary
+	// Mesh".
+	tangents := make([]math.Vec3, len(m.Positions))
+	bitangents := make([]math.Vec3, len(m.Positions))
+
+	for i := 0; i < m.numTriangles(); i++ {
+		a, b, c := m.triangle(i)
+		e1 := m.Positions[b].Sub(&m.Positions[a])
+		e2 := m.Positions[c].Sub(&m.Positions[a])
+		d1 := m.UVs[b].Sub(&m.UVs[a])
+		d2 := m.UVs[c].Sub(&m.UVs[a])
+
+		det := d1.Cross(&d2)
+		if math.Abs(det) < 1e-12 {
+			// Degenerated texture coordinates.
+			continue
+		}
+		r := 1 / det
+
+		var t, bt math.Vec3
+		t.AddScaledVec(d2[1]*r, &e1)
+		t.AddScaledVec(-d1[1]*r, &e2)
+		bt.AddScaledVec(-d2[0]*r, &e1)
+		bt.AddScaledVec(d1[0]*r, &e2)
+
+		for _, v := range []uint{a, b, c} {
+			tangents[v].AddWith(&t)
+			bitangents[v].AddWith(&bt)
+		}
+	}
+
+	m.Tangents = make([]math.Vec4, len(m.Positions))
+	for i := range tangents {
+		n := &m.Normals[i]
+
+		// Gram-Schmidt orthogonalization.
+		t := tangents[i]
+		t.AddScaledVec(-n.Dot(&t), n)
+		if t.Len2() == 0 {
+			// No usable texture coordinates: pick any tangent.
+			t = anyPerpendicular(n)
+		}
+		t.Normalize()
+
+		w := float32(1)
+		nxt := n.Cross(&t)
+		if nxt.Dot(&bitangents[i]) < 0 {
+			w = -1
+		}
+
+		m.Tangents[i] = t.Vec4(w)
+	}
+
+	return nil
+}
+
+// anyPerpendicular returns a vector perpendicular to n.
+func anyPerpendicular(n *math.Vec3) math.Vec3 {
+	axis := math.Vec3{1, 0, 0}
+	if math.Abs(n[0]) > .9 {
+		axis = math.Vec3{0, 1, 0}
+	}
+	return n.Cross(&axis)
+}
+
+// Bounds implements dax.Bounder. The box is cached until Positions is
+// replaced: call InvalidateBounds after modifying positions in place.
+func (m *Mesh) Bounds() math.AABB {
+	return m.cachedBounds().box
+}
+
+// BoundingSphere implements dax.SphereBounder. The sphere is centered on the
+// bounding box and cached like the box, see Bounds.
+func (m *Mesh) BoundingSphere() math.BoundingSphere {
+	return m.cachedBounds().sphere
+}
+
+// InvalidateBounds discards the cached bounding volumes of the mesh.
+func (m *Mesh) InvalidateBounds() {
+	m.bounds.valid = false
+}
+
+// GetMesh is part of the dax.Mesher interface.
+func (m *Mesh) GetMesh() *dax.Mesh {
+	mesh := dax.NewMesh()
+
+	n := len(m.Positions)
+	data := make([]float32, 0, 3*n)
+	for i := range m.Positions {
+		data = append(data, m.Positions[i][:]...)
+	}
+	mesh.AddAttribute("position", data, 3)
+
+	if len(m.Normals) == n && n > 0 {
+		data = make([]float32, 0, 3*n)
+		for i := range m.Normals {
+			data = append(data, m.Normals[i][:]...)
+		}
+		mesh.AddAttribute("normal", data, 3)
+	}
+
+	if len(m.UVs) == n && n > 0 {
+		data = make([]float32, 0, 2*n)
+		for i := range m.UVs {
+			data = append(data, m.UVs[i][:]...)
+		}
+		mesh.AddAttribute("uv", data, 2)
+	}
+
+	if len(m.Colors) == n && n > 0 {
+		data = make([]float32, 0, 4*n)
+		for i := range m.Colors {
+			c := &m.Colors[i]
+			data = append(data, c.R, c.G, c.B, c.A)
+		}
+		mesh.AddAttribute("color", data, 4)
+	}
+
+	if len(m.Tangents) == n && n > 0 {
+		data = make([]float32, 0, 4*n)
+		for i := range m.Tangents {
+			data = append(data, m.Tangents[i][:]...)
+		}
+		mesh.AddAttribute("tangent", data, 4)
+	}
+
+	// The renderer draws indexed meshes.
+	indices := m.Indices
+	if len(indices) == 0 {
+		indices = make([]uint, n)
+		for i := range indices {
+			indices[i] = uint(i)
+		}
+	}
+	mesh.AddIndices(indices)
+
+	return mesh
+}
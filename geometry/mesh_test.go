@@ -0,0 +1,177 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// A unit quad in the z = 0 plane, facing +Z, made of 2 triangles.
+func newQuad() *Mesh {
+	m := NewMesh([]math.Vec3{
+		{0, 0, 0},
+		{1, 0, 0},
+		{1, 1, 0},
+		{0, 1, 0},
+	}, []uint{0, 1, 2, 0, 2, 3})
+	m.UVs = []math.Vec2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	return m
+}
+
+func TestMeshComputeNormals(t *testing.T) {
+	m := newQuad()
+	m.ComputeNormals()
+
+	assert.Equal(t, 4, len(m.Normals))
+	for _, n := range m.Normals {
+		assert.True(t, n.EqualThreshold(&math.Vec3{0, 0, 1}, 1e-3), "normal %v", n)
+	}
+
+	// Without indices.
+	m = NewMesh([]math.Vec3{{0, 0, 0}, {0, 0, 1}, {1, 0, 0}}, nil)
+	m.ComputeNormals()
+	for _, n := range m.Normals {
+		assert.True(t, n.EqualThreshold(&math.Vec3{0, 1, 0}, 1e-3), "normal %v", n)
+	}
+}
+
+func TestMeshComputeTangents(t *testing.T) {
+	m := newQuad()
+	assert.Nil(t, m.ComputeTangents())
+
+	// Normals are computed along the way.
+	assert.Equal(t, 4, len(m.Normals))
+	assert.Equal(t, 4, len(m.Tangents))
+	for _, tangent := range m.Tangents {
+		assert.True(t, tangent.EqualThreshold(&math.Vec4{1, 0, 0, 1}, 1e-3), "tangent %v", tangent)
+	}
+
+	// Mirrored texture coordinates flip the bitangent.
+	m = newQuad()
+	m.UVs = []math.Vec2{{0, 1}, {1, 1}, {1, 0}, {0, 0}}
+	assert.Nil(t, m.ComputeTangents())
+	for _, tangent := range m.Tangents {
+		assert.True(t, tangent.EqualThreshold(&math.Vec4{1, 0, 0, -1}, 1e-3), "tangent %v", tangent)
+	}
+
+	m = newQuad()
+	m.UVs = nil
+	assert.NotNil(t, m.ComputeTangents())
+}
+
+func TestMeshGetMesh(t *testing.T) {
+	m := newQuad()
+	m.ComputeNormals()
+
+	mesh := m.GetMesh()
+	positions := mesh.GetAttribute("position")
+	assert.NotNil(t, positions)
+	assert.Equal(t, 4, positions.Len())
+	assert.NotNil(t, mesh.GetAttribute("normal"))
+	uvs := mesh.GetAttribute("uv")
+	assert.NotNil(t, uvs)
+	assert.Equal(t, 2, uvs.NumComponents)
+	assert.Nil(t, mesh.GetAttribute("color"))
+	assert.Nil(t, mesh.GetAttribute("tangent"))
+	assert.True(t, mesh.HasIndices())
+
+	// Non-indexed meshes get indices.
+	m = NewMesh([]math.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, nil)
+	assert.True(t, m.GetMesh().HasIndices())
+}
+
+func TestMeshBounds(t *testing.T) {
+	m := newQuad()
+
+	bounds := m.Bounds()
+	assert.Equal(t, math.Vec3{0, 0, 0}, bounds.Min)
+	assert.Equal(t, math.Vec3{1, 1, 0}, bounds.Max)
+	assert.Equal(t, bounds, m.GetMesh().Bounds())
+
+	sphere := m.BoundingSphere()
+	assert.Equal(t, math.Vec3{.5, .5, 0}, sphere.Center)
+	assert.InDelta(t, math.Sqrt(.5), sphere.Radius, 1e-6)
+	assert.Equal(t, sphere, m.GetMesh().BoundingSphere())
+}
+
+func TestMeshBoundsCache(t *testing.T) {
+	m := newQuad()
+	bounds := m.Bounds()
+
+	// Modifying positions in place needs an explicit invalidation.
+	m.Positions[0] = math.Vec3{-1, 0, 0}
+	assert.Equal(t, bounds, m.Bounds())
+	m.InvalidateBounds()
+	assert.Equal(t, math.Vec3{-1, 0, 0}, m.Bounds().Min)
+
+	// Replacing the positions doesn't.
+	m.Positions = []math.Vec3{{0, 0, 0}, {2, 0, 0}, {0, 2, 0}}
+	assert.Equal(t, math.Vec3{2, 2, 0}, m.Bounds().Max)
+	assert.Equal(t, math.Vec3{1, 1, 0}, m.BoundingSphere().Center)
+	m.Positions = m.Positions[:0]
+	assert.Equal(t, float32(-1), m.BoundingSphere().Radius)
+}
+
+// checkMesh verifies the generated meshes have unit normals, triangles
+// wound counter-clockwise when seen from the side the normals point to, and
+// are contained in the analytic bounding volumes.
+func checkMesh(t *testing.T, mesher interface {
+	GetMesh() *dax.Mesh
+	Bounds() math.AABB
+	BoundingSphere() math.BoundingSphere
+}) {
+	m := mesher.GetMesh()
+
+	positions := m.GetAttribute("position")
+	normals := m.GetAttribute("normal")
+	uvs := m.GetAttribute("uv")
+	if !assert.NotNil(t, positions) || !assert.NotNil(t, normals) || !assert.NotNil(t, uvs) {
+		return
+	}
+	assert.Equal(t, positions.Len(), normals.Len())
+	assert.Equal(t, positions.Len(), uvs.Len())
+
+	vec3 := func(ab *dax.AttributeBuffer, i int) math.Vec3 {
+		x, y, z := ab.GetXYZ(i)
+		return math.Vec3{x, y, z}
+	}
+
+	for i := 0; i < normals.Len(); i++ {
+		n := vec3(normals, i)
+		assert.InDelta(t, 1, n.Len(), 1e-3, "normal %d: %v", i, n)
+	}
+
+	indices := m.GetIndices()
+	assert.Equal(t, 0, indices.Len()%3)
+	for i := 0; i < indices.Len(); i += 3 {
+		i0, i1, i2 := int(indices.Get(i)), int(indices.Get(i+1)), int(indices.Get(i+2))
+		a, b, c := vec3(positions, i0), vec3(positions, i1), vec3(positions, i2)
+		e1, e2 := b.Sub(&a), c.Sub(&a)
+		face := e1.Cross(&e2)
+		if face.Len() < 1e-5 {
+			continue
+		}
+		n := vec3(normals, i0)
+		n2 := vec3(normals, i1)
+		n3 := vec3(normals, i2)
+		n.AddWith(&n2)
+		n.AddWith(&n3)
+		assert.True(t, face.Dot(&n) > 0, "triangle %d is back facing", i/3)
+	}
+
+	bounds := mesher.Bounds()
+	meshBounds := m.Bounds()
+	assert.True(t, bounds.Min.EqualThreshold(&meshBounds.Min, 1e-3) || bounds.ContainsPoint(&meshBounds.Min),
+		"%v isn't in %v", meshBounds, bounds)
+	assert.True(t, bounds.Max.EqualThreshold(&meshBounds.Max, 1e-3) || bounds.ContainsPoint(&meshBounds.Max),
+		"%v isn't in %v", meshBounds, bounds)
+
+	sphere := mesher.BoundingSphere()
+	sphere.Radius += 1e-3
+	for i := 0; i < positions.Len(); i++ {
+		p := vec3(positions, i)
+		assert.True(t, sphere.ContainsPoint(&p), "%v isn't in %v", p, sphere)
+	}
+}
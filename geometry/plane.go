@@ -0,0 +1,82 @@
+package geometry
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Plane is a rectangle centered around (0, 0, 0) in the XY plane, facing +Z,
+// with sizes Width and Height on the X and Y axis respectively. Each side is
+// divided in NumSubdivisions segments.
+type Plane struct {
+	Width, Height   float32
+	NumSubdivisions int
+}
+
+// NewPlane creates a new plane. subdivisions is clamped to at least 1.
+func NewPlane(width, height float32, subdivisions int) *Plane {
+	if subdivisions < 1 {
+		subdivisions = 1
+	}
+	return &Plane{
+		Width:           width,
+		Height:          height,
+		NumSubdivisions: subdivisions,
+	}
+}
+
+// Bounds implements dax.Bounder.
+func (p *Plane) Bounds() math.AABB {
+	return math.AABB{
+		Min: math.Vec3{-p.Width / 2, -p.Height / 2, 0},
+		Max: math.Vec3{p.Width / 2, p.Height / 2, 0},
+	}
+}
+
+// BoundingSphere implements dax.SphereBounder.
+func (p *Plane) BoundingSphere() math.BoundingSphere {
+	return math.BoundingSphere{Radius: math.Sqrt(p.Width*p.Width+p.Height*p.Height) / 2}
+}
+
+// GetMesh is part of the dax.Mesher interface.
+func (p *Plane) GetMesh() *dax.Mesh {
+	m := dax.NewMesh()
+
+	grid := p.NumSubdivisions
+	grid1 := grid + 1
+	segmentWidth := p.Width / float32(grid)
+	segmentHeight := p.Height / float32(grid)
+
+	var positions, normals, uvs []float32
+	var indices []uint
+
+	for iy := 0; iy < grid1; iy++ {
+		y := float32(iy)*segmentHeight - p.Height/2
+
+		for ix := 0; ix < grid1; ix++ {
+			x := float32(ix)*segmentWidth - p.Width/2
+
+			positions = append(positions, x, -y, 0)
+			normals = append(normals, 0, 0, 1)
+			uvs = append(uvs, float32(ix)/float32(grid), 1-float32(iy)/float32(grid))
+		}
+	}
+
+	for iy := 0; iy < grid; iy++ {
+		for ix := 0; ix < grid; ix++ {
+			a := uint(ix + grid1*iy)
+			b := uint(ix + grid1*(iy+1))
+			c := uint((ix + 1) + grid1*(iy+1))
+			d := uint((ix + 1) + grid1*iy)
+
+			indices = append(indices, a, b, d, b, c, d)
+		}
+	}
+
+	m.AddAttribute("position", positions, 3)
+	m.AddAttribute("normal", normals, 3)
+	m.AddAttribute("uv", uvs, 2)
+	m.AddIndices(indices)
+
+	return m
+}
@@ -0,0 +1,19 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaneMesh(t *testing.T) {
+	p := NewPlane(4, 2, 3)
+	checkMesh(t, p)
+
+	m := p.GetMesh()
+	assert.Equal(t, 4*4, m.GetAttribute("position").Len())
+	assert.Equal(t, 3*3*6, m.GetIndices().Len())
+	assert.Equal(t, p.Bounds(), m.Bounds())
+
+	assert.Equal(t, 1, NewPlane(1, 1, 0).NumSubdivisions)
+}
@@ -5,6 +5,10 @@ import (
 	"github.com/dlespiau/dax/math"
 )
 
+// Sphere is a UV sphere centered around (0, 0, 0). nVSegments is the number of
+// segments around the Y axis and nHSegments the number of rings from pole to
+// pole. A slice of the sphere can be generated with InitFull: phi is the
+// angle around the Y axis and theta the angle from the north pole.
 type Sphere struct {
 	radius                  float32
 	nVSegments, nHSegments  int
@@ -12,6 +16,7 @@ type Sphere struct {
 	thetaStart, thetaLength float32
 }
 
+// NewSphere creates a new sphere with the given number of segments and rings.
 func NewSphere(radius float32, nVSegments, nHSegments int) *Sphere {
 	s := new(Sphere)
 	s.Init(radius, nVSegments, nHSegments)
@@ -30,11 +35,24 @@ func (s *Sphere) InitFull(radius float32, nVSegments, nHSegments int,
 }
 
 func (s *Sphere) Init(radius float32, nVSegments, nHSegments int) {
-	const angle float32 = 2 * float32(math.Pi)
+	s.InitFull(radius, nVSegments, nHSegments, 0, 2*math.Pi, 0, math.Pi)
+}
+
+// Bounds implements dax.Bounder. The box is the one of the full sphere, even
+// when only a slice of the sphere is generated.
+func (s *Sphere) Bounds() math.AABB {
+	return math.AABB{
+		Min: math.Vec3{-s.radius, -s.radius, -s.radius},
+		Max: math.Vec3{s.radius, s.radius, s.radius},
+	}
+}
 
-	s.InitFull(radius, nVSegments, nHSegments, 0, angle, 0, angle)
+// BoundingSphere implements dax.SphereBounder.
+func (s *Sphere) BoundingSphere() math.BoundingSphere {
+	return math.BoundingSphere{Radius: s.radius}
 }
 
+// GetMesh is part of the dax.Mesher interface.
 func (s *Sphere) GetMesh() *dax.Mesh {
 	m := dax.NewMesh()
 	var positions, normals, uvs dax.AttributeBuffer
@@ -44,7 +62,7 @@ func (s *Sphere) GetMesh() *dax.Mesh {
 
 	positions.Init("position", vertexCount, 3)
 	normals.Init("normal", vertexCount, 3)
-	uvs.Init("uvs", vertexCount, 2)
+	uvs.Init("uv", vertexCount, 2)
 
 	index := 0
 	vertices := make([][]uint, s.nHSegments+1, s.nHSegments+1)
@@ -80,8 +98,8 @@ func (s *Sphere) GetMesh() *dax.Mesh {
 
 	}
 
-	indices := make([]uint, vertexCount, vertexCount)
-	i := 0
+	// Each quad is made of 2 triangles, except at the poles.
+	indices := make([]uint, 0, 6*s.nVSegments*s.nHSegments)
 
 	for y := 0; y < s.nHSegments; y++ {
 
@@ -93,20 +111,10 @@ func (s *Sphere) GetMesh() *dax.Mesh {
 			v4 := vertices[y+1][x+1]
 
 			if y != 0 || s.thetaStart > 0 {
-				indices[i] = v1
-				i++
-				indices[i] = v2
-				i++
-				indices[i] = v4
-				i++
+				indices = append(indices, v1, v2, v4)
 			}
 			if y != s.nHSegments-1 || thetaEnd < math.Pi {
-				indices[i] = v2
-				i++
-				indices[i] = v3
-				i++
-				indices[i] = v4
-				i++
+				indices = append(indices, v2, v3, v4)
 			}
 		}
 	}
@@ -0,0 +1,20 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSphereMesh(t *testing.T) {
+	s := NewSphere(2, 16, 8)
+	checkMesh(t, s)
+
+	m := s.GetMesh()
+	assert.Equal(t, 17*9, m.GetAttribute("position").Len())
+	// The triangles touching the poles are dropped.
+	assert.Equal(t, (16*8*2-2*16)*3, m.GetIndices().Len())
+
+	// The mesh goes from pole to pole.
+	assert.Equal(t, s.Bounds(), m.Bounds())
+}
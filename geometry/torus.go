@@ -0,0 +1,110 @@
+package geometry
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Torus is a ring centered around (0, 0, 0) in the XY plane. Radius is the
+// distance from the center of the torus to the center of the tube and Tube
+// the radius of the tube.
+type Torus struct {
+	Radius, Tube                          float32
+	NumRadialSegments, NumTubularSegments int
+}
+
+// TorusOptions contains optional parameters for the Torus constructor.
+type TorusOptions struct {
+	// NumRadialSegments is the number of segments around the tube and
+	// NumTubularSegments the number of segments along the tube.
+	NumRadialSegments, NumTubularSegments int
+}
+
+var defaultTorus = Torus{
+	Radius:             1.0,
+	Tube:               .4,
+	NumRadialSegments:  16,
+	NumTubularSegments: 48,
+}
+
+// NewTorus creates a new torus.
+func NewTorus(radius, tube float32, options ...TorusOptions) *Torus {
+	t := defaultTorus
+	t.Radius = radius
+	t.Tube = tube
+
+	if len(options) == 0 {
+		return &t
+	}
+
+	if options[0].NumRadialSegments > 0 {
+		t.NumRadialSegments = options[0].NumRadialSegments
+	}
+	if options[0].NumTubularSegments > 0 {
+		t.NumTubularSegments = options[0].NumTubularSegments
+	}
+
+	return &t
+}
+
+// Bounds implements dax.Bounder.
+func (t *Torus) Bounds() math.AABB {
+	r := t.Radius + t.Tube
+	return math.AABB{
+		Min: math.Vec3{-r, -r, -t.Tube},
+		Max: math.Vec3{r, r, t.Tube},
+	}
+}
+
+// BoundingSphere implements dax.SphereBounder.
+func (t *Torus) BoundingSphere() math.BoundingSphere {
+	return math.BoundingSphere{Radius: t.Radius + t.Tube}
+}
+
+// GetMesh is part of the dax.Mesher interface.
+func (t *Torus) GetMesh() *dax.Mesh {
+	m := dax.NewMesh()
+
+	var positions, normals, uvs []float32
+	var indices []uint
+
+	normal := math.Vec3{}
+
+	for j := 0; j <= t.NumRadialSegments; j++ {
+		v := float32(j) / float32(t.NumRadialSegments)
+		sinV, cosV := math.Sincos(v * 2 * math.Pi)
+
+		for i := 0; i <= t.NumTubularSegments; i++ {
+			u := float32(i) / float32(t.NumTubularSegments)
+			sinU, cosU := math.Sincos(u * 2 * math.Pi)
+
+			r := t.Radius + t.Tube*cosV
+			positions = append(positions, r*cosU, r*sinU, t.Tube*sinV)
+
+			// The normal points away from the center of the tube.
+			normal.Set(cosV*cosU, cosV*sinU, sinV)
+			normals = append(normals, normal[0], normal[1], normal[2])
+
+			uvs = append(uvs, u, v)
+		}
+	}
+
+	gridX1 := uint(t.NumTubularSegments + 1)
+	for j := uint(1); j <= uint(t.NumRadialSegments); j++ {
+		for i := uint(1); i <= uint(t.NumTubularSegments); i++ {
+			v1 := gridX1*j + i - 1
+			v2 := gridX1*(j-1) + i - 1
+			v3 := gridX1*(j-1) + i
+			v4 := gridX1*j + i
+
+			indices = append(indices, v1, v2, v4, v2, v3, v4)
+		}
+	}
+
+	m.AddAttribute("position", positions, 3)
+	m.AddAttribute("normal", normals, 3)
+	m.AddAttribute("uv", uvs, 2)
+	m.AddIndices(indices)
+
+	return m
+}
@@ -0,0 +1,19 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTorusMesh(t *testing.T) {
+	torus := NewTorus(2, .5, TorusOptions{
+		NumRadialSegments:  8,
+		NumTubularSegments: 16,
+	})
+	checkMesh(t, torus)
+
+	m := torus.GetMesh()
+	assert.Equal(t, 9*17, m.GetAttribute("position").Len())
+	assert.Equal(t, 8*16*6, m.GetIndices().Len())
+}
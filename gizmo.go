@@ -0,0 +1,294 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// Gizmo is a component drawing a wireframe representation of a camera or a
+// light, eg. in editors: the frustum of cameras, an arrow for directional
+// lights, the sphere of their range for point lights and their cone for spot
+// lights.
+//
+// Gizmos follow their target. Their node is meant to be added to the root of
+// the scene graph in editor or debug modes:
+//
+//	gizmo := dax.NewLightGizmo(light)
+//	sg.AddChild(gizmo.AsNode())
+//
+// Gizmos are picked like any other mesh, by their bounds. GetGizmo returns
+// the gizmo of a picked node and Target the camera or light node it stands
+// for.
+type Gizmo struct {
+	node     *Node
+	camera   Camera
+	light    *Node
+	size     float32
+	mesh     *Mesh
+	material *gizmoMaterial
+
+	// Lines being built, as pairs of vertices.
+	positions []float32
+	indices   []uint
+}
+
+var _ Updater = &Gizmo{}
+
+func newGizmo() *Gizmo {
+	g := &Gizmo{
+		node:     NewNode(),
+		size:     1,
+		mesh:     NewMesh(),
+		material: newGizmoMaterial(),
+	}
+	g.mesh.SetVertexMode(VertexModeLines)
+	g.node.AddComponent(g)
+	g.node.AddComponent(NewMeshRenderer(g.mesh, g.material))
+	return g
+}
+
+// NewCameraGizmo creates a gizmo drawing the frustum of c, in light grey.
+func NewCameraGizmo(c Camera) *Gizmo {
+	g := newGizmo()
+	g.camera = c
+	g.SetColor(&Color{.8, .8, .8, 1})
+	g.Update(0)
+	return g
+}
+
+// NewLightGizmo creates a gizmo for the Light component of node, of the color
+// of the light. It panics if node has no light.
+func NewLightGizmo(node *Node) *Gizmo {
+	l := getLight(node)
+	if l == nil {
+		panic("gizmo: node has no light")
+	}
+
+	g := newGizmo()
+	g.light = node
+	color := l.Color
+	color.A = 1
+	g.SetColor(&color)
+	g.Update(0)
+	return g
+}
+
+// GetGizmo returns the gizmo drawn by node, nil if node isn't the node of a
+// gizmo.
+func GetGizmo(node *Node) *Gizmo {
+	for i := range node.components {
+		if g, ok := node.components[i].(*Gizmo); ok {
+			return g
+		}
+	}
+	return nil
+}
+
+// AsNode returns the node drawing the gizmo, the one to add to a scene graph.
+func (g *Gizmo) AsNode() *Node {
+	return g.node
+}
+
+// Target returns the node of the camera or light the gizmo stands for.
+func (g *Gizmo) Target() *Node {
+	if g.camera != nil {
+		return g.camera.AsNode()
+	}
+	return g.light
+}
+
+// SetColor sets the color of the gizmo lines.
+func (g *Gizmo) SetColor(c *Color) {
+	g.material.SetUniform("color", *c)
+}
+
+// SetSize sets the length, in world units, of the directional light arrows and
+// of lights without range, and the depth up to which camera frustums are
+// drawn. It's 1 by default.
+func (g *Gizmo) SetSize(size float32) {
+	g.size = size
+}
+
+// Update implements Updater. It moves the gizmo to its target and rebuilds its
+// lines from the target parameters.
+func (g *Gizmo) Update(dt float64) {
+	g.positions = g.positions[:0]
+	g.indices = g.indices[:0]
+
+	var world math.Mat4
+	if g.camera != nil {
+		view := cameraView(g.camera)
+		world = view.Inverse()
+		g.addFrustum(g.camera)
+	} else {
+		world = g.light.computeWorldTransform()
+		if l := getLight(g.light); l != nil {
+			g.addLight(l)
+		}
+	}
+
+	position, rotation, scale := world.Decompose()
+	g.node.SetPositionV(&position)
+	g.node.SetRotation(&rotation)
+	g.node.SetScaleV(&scale)
+
+	g.mesh.AddAttribute("position", g.positions, 3)
+	g.mesh.AddIndices(g.indices)
+}
+
+func (g *Gizmo) addLine(a, b *math.Vec3) {
+	n := uint(len(g.positions) / 3)
+	g.positions = append(g.positions, a[0], a[1], a[2], b[0], b[1], b[2])
+	g.indices = append(g.indices, n, n+1)
+}
+
+// addCircle adds a circle around center, in the plane of the axes u and v,
+// whose length is the radius.
+func (g *Gizmo) addCircle(center, u, v *math.Vec3) {
+	const segments = 32
+
+	point := func(i int) math.Vec3 {
+		angle := float32(i) * 2 * math.Pi / segments
+		a := u.Mul(math.Cos(angle))
+		b := v.Mul(math.Sin(angle))
+		p := center.Add(&a)
+		return p.Add(&b)
+	}
+
+	previous := point(0)
+	for i := 1; i <= segments; i++ {
+		p := point(i)
+		g.addLine(&previous, &p)
+		previous = p
+	}
+}
+
+// addFrustum adds the frustum of c, in camera space.
+func (g *Gizmo) addFrustum(c Camera) {
+	projection := c.GetProjection()
+	inverse := projection.Inverse()
+	unproject := func(x, y, z float32) math.Vec3 {
+		p := inverse.Mul4x1(&math.Vec4{x, y, z, 1})
+		return math.Vec3{p[0] / p[3], p[1] / p[3], p[2] / p[3]}
+	}
+
+	corners := [4][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}}
+	var near, far [4]math.Vec3
+
+	if projection[15] == 0 {
+		// Perspective projection: the frustum is a pyramid. Its far plane
+		// may be at infinity, draw it up to the gizmo size.
+		nearDepth, farDepth := float32(0), g.size
+		if nf, ok := c.(interface{ GetNearFar() (near, far float32) }); ok {
+			nearDepth, farDepth = nf.GetNearFar()
+		}
+		farDepth = math.Min(farDepth, g.size)
+		nearDepth = math.Min(nearDepth, farDepth)
+
+		for i, corner := range corners {
+			// A point inside the frustum for all depth ranges.
+			d := unproject(corner[0], corner[1], .5)
+			d = d.Mul(-1 / d[2])
+			near[i] = d.Mul(nearDepth)
+			far[i] = d.Mul(farDepth)
+		}
+
+		var apex math.Vec3
+		for i := range far {
+			g.addLine(&apex, &far[i])
+		}
+	} else {
+		for i, corner := range corners {
+			near[i] = unproject(corner[0], corner[1], -1)
+			far[i] = unproject(corner[0], corner[1], 1)
+			g.addLine(&near[i], &far[i])
+		}
+	}
+
+	for i := range corners {
+		j := (i + 1) % len(corners)
+		g.addLine(&near[i], &near[j])
+		g.addLine(&far[i], &far[j])
+	}
+}
+
+// addLight adds the representation of l, in light space. Lights point
+// towards -Z.
+func (g *Gizmo) addLight(l *Light) {
+	x := math.Vec3{1, 0, 0}
+	y := math.Vec3{0, 1, 0}
+	z := math.Vec3{0, 0, 1}
+	var origin math.Vec3
+
+	length := l.Range
+	if length <= 0 {
+		length = g.size
+	}
+
+	switch l.Type {
+	case LightDirectional:
+		tip := math.Vec3{0, 0, -g.size}
+		g.addLine(&origin, &tip)
+		head := g.size * .1
+		for _, side := range []math.Vec3{x, y, x.Mul(-1), y.Mul(-1)} {
+			p := math.Vec3{side[0] * head, side[1] * head, -g.size + 2*head}
+			g.addLine(&tip, &p)
+		}
+		u, v := x.Mul(g.size*.2), y.Mul(g.size*.2)
+		g.addCircle(&origin, &u, &v)
+	case LightPoint:
+		u, v, w := x.Mul(length), y.Mul(length), z.Mul(length)
+		g.addCircle(&origin, &u, &v)
+		g.addCircle(&origin, &u, &w)
+		g.addCircle(&origin, &v, &w)
+	case LightSpot:
+		base := math.Vec3{0, 0, -length}
+		outer := length * math.Tan(l.OuterCone)
+		inner := length * math.Tan(l.InnerCone)
+		u, v := x.Mul(outer), y.Mul(outer)
+		g.addCircle(&base, &u, &v)
+		if inner > 0 && inner < outer {
+			u, v := x.Mul(inner), y.Mul(inner)
+			g.addCircle(&base, &u, &v)
+		}
+		for _, side := range []math.Vec3{x, y, x.Mul(-1), y.Mul(-1)} {
+			p := math.Vec3{side[0] * outer, side[1] * outer, -length}
+			g.addLine(&origin, &p)
+		}
+	}
+}
+
+// gizmoMaterial draws unlit lines of a color.
+type gizmoMaterial struct {
+	BaseMaterial
+	shader *FragmentShader
+}
+
+const gizmoFragmentShader = `
+#version 330
+uniform vec4 color;
+uniform float logDepthCoef;
+in float logDepthW;
+out vec4 outputColor;
+void main() {
+    outputColor = color;
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
+}`
+
+func newGizmoMaterial() *gizmoMaterial {
+	m := &gizmoMaterial{}
+	m.shader = NewFragmentShader(gizmoFragmentShader)
+	m.shader.AddUniform(VariableKindVec4, "color")
+	return m
+}
+
+// ID is part of the Material interface.
+func (m *gizmoMaterial) ID() string {
+	return "-dax-material-gizmo"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *gizmoMaterial) GetFragmentShader() *FragmentShader {
+	return m.shader
+}
@@ -0,0 +1,57 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraGizmo(t *testing.T) {
+	camera := NewPerspectiveCamera(math.Pi/2, 1, 1, 100)
+	camera.SetPosition(1, 2, 3)
+	gizmo := NewCameraGizmo(camera)
+	gizmo.SetSize(10)
+	gizmo.Update(0)
+
+	assert.Equal(t, gizmo, GetGizmo(gizmo.AsNode()))
+	assert.Equal(t, camera.AsNode(), gizmo.Target())
+	assertVec3(t, &math.Vec3{1, 2, 3}, gizmo.AsNode().GetPosition(), 1e-4)
+
+	// With a fov of 90 degrees, the frustum is as wide as deep, drawn up to
+	// the gizmo size.
+	bounds := gizmo.mesh.Bounds()
+	assertVec3(t, &math.Vec3{-10, -10, -10}, &bounds.Min, 1e-3)
+	assert.InDelta(t, 10, bounds.Max[0], 1e-3)
+	assert.InDelta(t, 10, bounds.Max[1], 1e-3)
+	assert.InDelta(t, 0, bounds.Max[2], 1e-3)
+
+	// Apex to far corners, near and far rectangles.
+	assert.Equal(t, 12*2, gizmo.mesh.GetAttribute("position").Len())
+}
+
+func TestLightGizmo(t *testing.T) {
+	assert.Panics(t, func() { NewLightGizmo(NewNode()) })
+
+	node := NewNode()
+	spot := NewSpotLight(&Color{1, 1, 0, 1}, 1, 4, math.Pi/8, math.Pi/4)
+	node.AddComponent(spot)
+	node.SetPosition(0, 5, 0)
+	gizmo := NewLightGizmo(node)
+
+	assert.Equal(t, node, gizmo.Target())
+	assertVec3(t, &math.Vec3{0, 5, 0}, gizmo.AsNode().GetPosition(), 1e-4)
+
+	// A 45 degrees cone, as long as the light range.
+	bounds := gizmo.mesh.Bounds()
+	assertVec3(t, &math.Vec3{-4, -4, -4}, &bounds.Min, 1e-3)
+	assert.InDelta(t, 4, bounds.Max[0], 1e-3)
+	assert.InDelta(t, 0, bounds.Max[2], 1e-3)
+
+	// The gizmo follows the light changes.
+	spot.Type = LightPoint
+	gizmo.Update(0)
+	bounds = gizmo.mesh.Bounds()
+	assertVec3(t, &math.Vec3{-4, -4, -4}, &bounds.Min, 1e-3)
+	assertVec3(t, &math.Vec3{4, 4, 4}, &bounds.Max, 1e-3)
+}
@@ -0,0 +1,137 @@
+package dax
+
+import (
+	"unsafe"
+)
+
+// glObjectKind is the type of a GL object.
+type glObjectKind int
+
+const (
+	glObjectBuffer glObjectKind = iota
+	glObjectVertexArray
+	glObjectProgram
+	glObjectTexture
+	glObjectFramebuffer
+	glObjectRenderbuffer
+)
+
+// glObject identifies a GL object.
+type glObject struct {
+	kind glObjectKind
+	id   uint32
+}
+
+// glGarbageBatch is a list of objects released during the same frame. They
+// can be deleted once the GPU is done with that frame, ie. when the fence
+// inserted at the end of the frame is signaled.
+type glGarbageBatch struct {
+	fence   unsafe.Pointer
+	objects []glObject
+}
+
+// defaultGarbageBudget is the default maximum number of GL objects deleted per
+// frame, on top of the number of objects released per frame.
+const defaultGarbageBudget = 64
+
+// garbageRateSmoothing is the number of frames the release rate is averaged
+// over, roughly.
+const garbageRateSmoothing = 16
+
+// glGarbageCollector defers the deletion of GL objects: objects aren't
+// deleted while the GPU may still be using them and deletions are spread over
+// several frames to avoid hitches when releasing a lot of objects at once.
+type glGarbageCollector struct {
+	// budget is the maximum number of objects deleted per frame, on top of
+	// the average number of objects released per frame, rate: objects
+	// released every frame are deleted at the same pace and only bursts are
+	// spread over several frames. 0 means no limit.
+	budget int
+	rate   float64
+
+	// released during the current frame.
+	pending []glObject
+	// waiting for the GPU to finish the frame they were released in.
+	batches []glGarbageBatch
+	// ready to be deleted.
+	ready []glObject
+
+	// GL operations, replaced in tests.
+	fenceSync    func() unsafe.Pointer
+	isSignaled   func(fence unsafe.Pointer) bool
+	deleteFence  func(fence unsafe.Pointer)
+	deleteObject func(o glObject)
+}
+
+// release queues o for deletion.
+func (gc *glGarbageCollector) release(kind glObjectKind, id uint32) {
+	if id == 0 {
+		return
+	}
+	gc.pending = append(gc.pending, glObject{kind, id})
+}
+
+// endFrame is called at the end of each frame. It deletes objects the GPU is
+// done with, within the frame budget, and fences the objects released during
+// the frame.
+func (gc *glGarbageCollector) endFrame() {
+	gc.rate += (float64(len(gc.pending)) - gc.rate) / garbageRateSmoothing
+	gc.collect()
+
+	if len(gc.pending) == 0 {
+		return
+	}
+	gc.batches = append(gc.batches, glGarbageBatch{
+		fence:   gc.fenceSync(),
+		objects: gc.pending,
+	})
+	gc.pending = nil
+}
+
+func (gc *glGarbageCollector) collect() {
+	// Fences are signaled in order, stop at the first unsignaled one.
+	n := 0
+	for ; n < len(gc.batches); n++ {
+		b := &gc.batches[n]
+		if !gc.isSignaled(b.fence) {
+			break
+		}
+		gc.deleteFence(b.fence)
+		gc.ready = append(gc.ready, b.objects...)
+	}
+	gc.batches = append(gc.batches[:0], gc.batches[n:]...)
+
+	n = len(gc.ready)
+	if limit := gc.budget + int(gc.rate); gc.budget > 0 && n > limit {
+		n = limit
+	}
+	for _, o := range gc.ready[:n] {
+		gc.deleteObject(o)
+	}
+	gc.ready = append(gc.ready[:0], gc.ready[n:]...)
+}
+
+// flush deletes all released objects right away.
+func (gc *glGarbageCollector) flush() {
+	for _, b := range gc.batches {
+		gc.deleteFence(b.fence)
+		gc.ready = append(gc.ready, b.objects...)
+	}
+	gc.batches = nil
+	gc.ready = append(gc.ready, gc.pending...)
+	gc.pending = nil
+
+	for _, o := range gc.ready {
+		gc.deleteObject(o)
+	}
+	gc.ready = nil
+}
+
+// len returns the number of objects waiting to be deleted.
+func (gc *glGarbageCollector) len() int {
+	n := len(gc.pending) + len(gc.ready)
+	for i := range gc.batches {
+		n += len(gc.batches[i].objects)
+	}
+	return n
+}
@@ -0,0 +1,118 @@
+package dax
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGPU pretends to be the GPU, signaling fences when told to.
+type fakeGPU struct {
+	fences  []*bool
+	deleted []glObject
+}
+
+func (gpu *fakeGPU) collector(budget int) *glGarbageCollector {
+	return &glGarbageCollector{
+		budget: budget,
+		fenceSync: func() unsafe.Pointer {
+			fence := new(bool)
+			gpu.fences = append(gpu.fences, fence)
+			return unsafe.Pointer(fence)
+		},
+		isSignaled: func(fence unsafe.Pointer) bool {
+			return *(*bool)(fence)
+		},
+		deleteFence: func(fence unsafe.Pointer) {},
+		deleteObject: func(o glObject) {
+			gpu.deleted = append(gpu.deleted, o)
+		},
+	}
+}
+
+// signal signals all the fences inserted so far.
+func (gpu *fakeGPU) signal() {
+	for _, fence := range gpu.fences {
+		*fence = true
+	}
+}
+
+func TestGLGarbageCollector(t *testing.T) {
+	gpu := &fakeGPU{}
+	gc := gpu.collector(0)
+
+	gc.release(glObjectBuffer, 1)
+	gc.release(glObjectVertexArray, 2)
+	gc.release(glObjectBuffer, 0)
+	assert.Equal(t, 2, gc.len())
+
+	// The GPU may still be using the objects.
+	gc.endFrame()
+	assert.Empty(t, gpu.deleted)
+	gc.endFrame()
+	assert.Empty(t, gpu.deleted)
+
+	gpu.signal()
+	gc.endFrame()
+	assert.Equal(t, []glObject{{glObjectBuffer, 1}, {glObjectVertexArray, 2}}, gpu.deleted)
+	assert.Equal(t, 0, gc.len())
+}
+
+func TestGLGarbageCollectorBudget(t *testing.T) {
+	gpu := &fakeGPU{}
+	gc := gpu.collector(2)
+
+	for i := 1; i <= 5; i++ {
+		gc.release(glObjectBuffer, uint32(i))
+	}
+	gc.endFrame()
+	gpu.signal()
+
+	// Deletions are spread over several frames.
+	gc.endFrame()
+	assert.Equal(t, 2, len(gpu.deleted))
+	gc.endFrame()
+	assert.Equal(t, 4, len(gpu.deleted))
+	gc.endFrame()
+	assert.Equal(t, 5, len(gpu.deleted))
+	assert.Equal(t, 0, gc.len())
+}
+
+func TestGLGarbageCollectorSteadyRelease(t *testing.T) {
+	gpu := &fakeGPU{}
+	gc := gpu.collector(2)
+
+	// Releasing more objects per frame than the budget doesn't make the
+	// queue grow unbounded.
+	id := uint32(1)
+	for frame := 0; frame < 1000; frame++ {
+		for i := 0; i < 10; i++ {
+			gc.release(glObjectBuffer, id)
+			id++
+		}
+		gc.endFrame()
+		gpu.signal()
+	}
+	assert.True(t, gc.len() < 100, "%d objects queued", gc.len())
+
+	// And the queue drains once objects aren't released anymore.
+	for frame := 0; frame < 100; frame++ {
+		gc.endFrame()
+	}
+	assert.Equal(t, 0, gc.len())
+	assert.Equal(t, int(id-1), len(gpu.deleted))
+}
+
+func TestGLGarbageCollectorFlush(t *testing.T) {
+	gpu := &fakeGPU{}
+	gc := gpu.collector(1)
+
+	gc.release(glObjectBuffer, 1)
+	gc.endFrame()
+	gc.release(glObjectProgram, 2)
+
+	gc.flush()
+	assert.Equal(t, []glObject{{glObjectBuffer, 1}, {glObjectProgram, 2}}, gpu.deleted)
+	assert.Equal(t, 0, gc.len())
+}
@@ -0,0 +1,56 @@
+package dax
+
+// Grid is an infinite-looking grid drawn on the XZ plane, centered around the
+// world origin. It's typically used as a ground reference in editors and demos.
+//
+// The grid is a single quad whose lines are computed in the fragment shader:
+// minor lines every MinorSpacing units, major lines every MajorSpacing units
+// and the X and Z axis drawn with their own colors. The grid fades out with
+// the distance to the camera, reaching full transparency at FadeDistance.
+type Grid struct {
+	// Extent is half the size of the quad the grid is drawn on.
+	Extent       float32
+	MinorSpacing float32
+	MajorSpacing float32
+	FadeDistance float32
+
+	MinorColor Color
+	MajorColor Color
+	XAxisColor Color
+	ZAxisColor Color
+}
+
+// NewGrid creates a new Grid with sensible defaults: a minor line every unit,
+// a major line every 10 units.
+func NewGrid() *Grid {
+	return &Grid{
+		Extent:       1000,
+		MinorSpacing: 1,
+		MajorSpacing: 10,
+		FadeDistance: 100,
+		MinorColor:   Color{.5, .5, .5, .3},
+		MajorColor:   Color{.6, .6, .6, .6},
+		XAxisColor:   Color{.9, .2, .2, 1},
+		ZAxisColor:   Color{.2, .2, .9, 1},
+	}
+}
+
+// GetMesh is part of the Mesher interface.
+func (g *Grid) GetMesh() *Mesh {
+	e := g.Extent
+	m := NewMesh()
+	m.AddAttribute("position", []float32{
+		-e, 0, -e,
+		-e, 0, e,
+		e, 0, e,
+		e, 0, -e,
+	}, 3)
+	m.AddIndices([]uint{0, 1, 2, 0, 2, 3})
+
+	return m
+}
+
+// Draw implements Drawer for Grid.
+func (g *Grid) Draw(fb Framebuffer) {
+	fb.render().drawGrid(fb, g)
+}
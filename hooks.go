@@ -0,0 +1,61 @@
+package dax
+
+// HookID identifies a function registered with one of the Window On* hook
+// methods or with EventDispatcher.On. It's used to remove the function.
+type HookID int
+
+type hook struct {
+	id HookID
+	f  func()
+}
+
+// hookList is a list of functions called at a given point of the frame.
+type hookList struct {
+	hooks []hook
+}
+
+// lastHookID is the last ID given to a hook. IDs are unique across all hook
+// lists so RemoveHook doesn't need to know which list a hook is in.
+var lastHookID HookID
+
+func (l *hookList) add(f func()) HookID {
+	lastHookID++
+	l.hooks = append(l.hooks, hook{id: lastHookID, f: f})
+	return lastHookID
+}
+
+func (l *hookList) remove(id HookID) bool {
+	for i := range l.hooks {
+		if l.hooks[i].id != id {
+			continue
+		}
+		// Don't modify the slice in place: the list may be being
+		// called.
+		hooks := make([]hook, 0, len(l.hooks)-1)
+		hooks = append(hooks, l.hooks[:i]...)
+		l.hooks = append(hooks, l.hooks[i+1:]...)
+		return true
+	}
+	return false
+}
+
+// call calls the hooks in the order they were added. Hooks added or removed
+// by a hook take effect the next time the list is called.
+func (l *hookList) call() {
+	for _, h := range l.hooks {
+		h.f()
+	}
+}
+
+// frameHooks are the hooks of a Window.
+type frameHooks struct {
+	beforeUpdate hookList
+	afterDraw    hookList
+	swap         hookList
+	close        hookList
+}
+
+func (h *frameHooks) remove(id HookID) {
+	_ = h.beforeUpdate.remove(id) || h.afterDraw.remove(id) || h.swap.remove(id) ||
+		h.close.remove(id)
+}
@@ -0,0 +1,58 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHookList(t *testing.T) {
+	var l hookList
+	var calls []int
+
+	l.add(func() { calls = append(calls, 1) })
+	var id HookID
+	id = l.add(func() {
+		calls = append(calls, 2)
+		// Removing a hook while calling the list takes effect at the
+		// next call.
+		l.remove(id)
+	})
+	l.add(func() { calls = append(calls, 3) })
+
+	l.call()
+	assert.Equal(t, []int{1, 2, 3}, calls)
+
+	calls = nil
+	l.call()
+	assert.Equal(t, []int{1, 3}, calls)
+
+	assert.False(t, l.remove(id))
+}
+
+func TestFrameHooksRemove(t *testing.T) {
+	var h frameHooks
+	called := false
+
+	h.beforeUpdate.add(func() {})
+	id := h.swap.add(func() { called = true })
+	assert.NotEqual(t, h.beforeUpdate.hooks[0].id, id)
+
+	h.remove(id)
+	h.swap.call()
+	assert.False(t, called)
+	assert.Equal(t, 1, len(h.beforeUpdate.hooks))
+}
+
+func TestFrameHooksRemoveClose(t *testing.T) {
+	var h frameHooks
+	var calls []string
+
+	h.close.add(func() { calls = append(calls, "first") })
+	id := h.close.add(func() { calls = append(calls, "second") })
+	h.close.add(func() { calls = append(calls, "third") })
+
+	h.remove(id)
+	h.close.call()
+	assert.Equal(t, []string{"first", "third"}, calls)
+}
@@ -0,0 +1,108 @@
+package dax
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dlespiau/dax/math"
+)
+
+// inputState is the input of a window at a point in time: the keys and mouse
+// buttons held down, in the order they were pressed, and the position of the
+// mouse.
+type inputState struct {
+	cursor  math.Vec2
+	keys    []Key
+	buttons []MouseButton
+}
+
+// reset releases all the keys and buttons.
+func (s *inputState) reset() {
+	s.keys = s.keys[:0]
+	s.buttons = s.buttons[:0]
+}
+
+// track updates the input state with e.
+func (s *inputState) track(e *InputEvent) {
+	switch e.Kind {
+	case InputKeyPressed:
+		s.releaseKey(e.Key)
+		s.keys = append(s.keys, e.Key)
+	case InputKeyReleased:
+		s.releaseKey(e.Key)
+	case InputMouseMoved:
+		s.cursor = math.Vec2{e.X, e.Y}
+	case InputMouseButtonPressed:
+		s.cursor = math.Vec2{e.X, e.Y}
+		s.releaseButton(e.Button)
+		s.buttons = append(s.buttons, e.Button)
+	case InputMouseButtonReleased:
+		s.cursor = math.Vec2{e.X, e.Y}
+		s.releaseButton(e.Button)
+	}
+}
+
+func (s *inputState) releaseKey(key Key) {
+	for i, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *inputState) releaseButton(button MouseButton) {
+	for i, b := range s.buttons {
+		if b == button {
+			s.buttons = append(s.buttons[:i], s.buttons[i+1:]...)
+			return
+		}
+	}
+}
+
+// buttonName returns the name of b shown in the overlays, which only have
+// upper case letters.
+func buttonName(b MouseButton) string {
+	switch b {
+	case MouseButtonLeft:
+		return "LMB"
+	case MouseButtonRight:
+		return "RMB"
+	case MouseButtonMiddle:
+		return "MMB"
+	}
+	return fmt.Sprintf("MB%d", b+1)
+}
+
+// label returns the keys and buttons held down, eg. "CTRL+A" or "SHIFT+LMB".
+func (s *inputState) label() string {
+	names := make([]string, 0, len(s.keys)+len(s.buttons))
+	for _, k := range s.keys {
+		names = append(names, k.String())
+	}
+	for _, b := range s.buttons {
+		names = append(names, buttonName(b))
+	}
+	return strings.Join(names, "+")
+}
+
+// drawHeld draws the keys and buttons held down in a box at the bottom left
+// corner of a window of the given height. Nothing is drawn when nothing is
+// held down.
+func (s *inputState) drawHeld(b *overlayBuilder, height int) {
+	label := s.label()
+	if label == "" {
+		return
+	}
+	lines := []string{label}
+	_, h := textBoxSize(lines)
+	b.textBox(statsMargin, float32(height)-h-statsMargin, lines)
+}
+
+// inputOverlay builds the overlay of Window.SetInputOverlayVisible for a
+// window of the given height.
+func inputOverlay(s *inputState, height int) *Mesh {
+	var b overlayBuilder
+	s.drawHeld(&b, height)
+	return b.mesh()
+}
@@ -0,0 +1,49 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputStateLabel(t *testing.T) {
+	var s inputState
+	assert.Equal(t, "", s.label())
+
+	s.track(&InputEvent{Kind: InputKeyPressed, Key: KeyLeftControl})
+	s.track(&InputEvent{Kind: InputKeyPressed, Key: KeyA})
+	assert.Equal(t, "CTRL+A", s.label())
+
+	// Buttons come after the keys and repeated presses aren't duplicated.
+	s.track(&InputEvent{Kind: InputMouseButtonPressed, Button: MouseButtonLeft, X: 1, Y: 2})
+	s.track(&InputEvent{Kind: InputMouseButtonPressed, Button: MouseButtonLeft, X: 1, Y: 2})
+	s.track(&InputEvent{Kind: InputKeyReleased, Key: KeyA})
+	assert.Equal(t, "CTRL+LMB", s.label())
+	assert.Equal(t, float32(2), s.cursor[1])
+
+	s.track(&InputEvent{Kind: InputMouseButtonReleased, Button: MouseButtonLeft})
+	s.track(&InputEvent{Kind: InputMouseButtonPressed, Button: MouseButton5})
+	assert.Equal(t, "CTRL+MB5", s.label())
+
+	s.reset()
+	assert.Equal(t, "", s.label())
+}
+
+func TestInputOverlay(t *testing.T) {
+	var s inputState
+
+	// Nothing is drawn when nothing is held down.
+	m := inputOverlay(&s, 480)
+	assert.Equal(t, 0, m.GetAttribute("position").Len())
+
+	s.track(&InputEvent{Kind: InputKeyPressed, Key: KeyLeftShift})
+	m = inputOverlay(&s, 480)
+	positions := m.GetAttribute("position")
+	assert.True(t, positions.Len() > 6)
+
+	// The box is at the bottom left corner.
+	_, h := textBoxSize([]string{"SHIFT"})
+	x, y := positions.GetXY(0)
+	assertFloat(t, statsMargin, x, 1e-3)
+	assertFloat(t, 480-statsMargin-h, y, 1e-3)
+}
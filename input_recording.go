@@ -0,0 +1,270 @@
+package dax
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dlespiau/dax/math"
+)
+
+// InputEventKind is the kind of an InputEvent.
+type InputEventKind int
+
+const (
+	// InputKeyPressed is a key press, see Scener.OnKeyPressed.
+	InputKeyPressed InputEventKind = iota
+	// InputKeyReleased is a key release, see Scener.OnKeyReleased.
+	InputKeyReleased
+	// InputMouseMoved is a mouse move, see Scener.OnMouseMoved.
+	InputMouseMoved
+	// InputMouseButtonPressed is a mouse button press, see
+	// Scener.OnMouseButtonPressed.
+	InputMouseButtonPressed
+	// InputMouseButtonReleased is a mouse button release, see
+	// Scener.OnMouseButtonReleased.
+	InputMouseButtonReleased
+	// InputRuneEntered is a character typed, see Scener.OnRuneEntered.
+	InputRuneEntered
+)
+
+// InputEvent is an input event given by a window to its scene.
+type InputEvent struct {
+	// Time is when the event happened, in seconds since the start of the
+	// recording.
+	Time float64
+	Kind InputEventKind
+	// Key, Scancode and Mods are set for key events.
+	Key      Key         `json:",omitempty"`
+	Scancode int         `json:",omitempty"`
+	Mods     ModifierKey `json:",omitempty"`
+	// Button is set for mouse button events.
+	Button MouseButton `json:",omitempty"`
+	// X and Y are the position of the mouse, in framebuffer pixels, for
+	// mouse events.
+	X float32 `json:",omitempty"`
+	Y float32 `json:",omitempty"`
+	// Rune is set for InputRuneEntered events.
+	Rune rune `json:",omitempty"`
+}
+
+// dispatch calls the event handler of s for e.
+func (e *InputEvent) dispatch(s Scener) {
+	switch e.Kind {
+	case InputKeyPressed:
+		s.OnKeyPressed(e.Key, e.Scancode, e.Mods)
+	case InputKeyReleased:
+		s.OnKeyReleased(e.Key, e.Scancode, e.Mods)
+	case InputMouseMoved:
+		s.OnMouseMoved(e.X, e.Y)
+	case InputMouseButtonPressed:
+		s.OnMouseButtonPressed(e.Button, e.X, e.Y)
+	case InputMouseButtonReleased:
+		s.OnMouseButtonReleased(e.Button, e.X, e.Y)
+	case InputRuneEntered:
+		s.OnRuneEntered(e.Rune)
+	}
+}
+
+// InputRecording is a sequence of input events, in chronological order. It's
+// saved and loaded as JSON, so recordings can be shipped with applications,
+// eg. as tutorials, or kept as automated demos.
+type InputRecording struct {
+	Events []InputEvent
+}
+
+// Duration returns the time of the last event of the recording.
+func (r *InputRecording) Duration() float64 {
+	if len(r.Events) == 0 {
+		return 0
+	}
+	return r.Events[len(r.Events)-1].Time
+}
+
+// Save writes the recording to w, as JSON.
+func (r *InputRecording) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// LoadInputRecording reads a recording written by InputRecording.Save.
+func LoadInputRecording(r io.Reader) (*InputRecording, error) {
+	recording := &InputRecording{}
+	if err := json.NewDecoder(r).Decode(recording); err != nil {
+		return nil, err
+	}
+	return recording, nil
+}
+
+// InputRecorder records the input events of a window, see Window.RecordInput.
+// Events are timestamped with the time of the frame they are received in.
+type InputRecorder struct {
+	recording InputRecording
+	time      float64
+	active    bool
+}
+
+// NewInputRecorder creates a new InputRecorder. It doesn't record until Start
+// is called.
+func NewInputRecorder() *InputRecorder {
+	return &InputRecorder{}
+}
+
+// Start starts a new recording.
+func (r *InputRecorder) Start() {
+	r.recording = InputRecording{}
+	r.time = 0
+	r.active = true
+}
+
+// Stop stops recording and returns the events recorded since Start.
+func (r *InputRecorder) Stop() *InputRecording {
+	r.active = false
+	recording := r.recording
+	return &recording
+}
+
+// IsRecording returns true between Start and Stop.
+func (r *InputRecorder) IsRecording() bool {
+	return r.active
+}
+
+// advance moves the recording time forward by dt seconds.
+func (r *InputRecorder) advance(dt float64) {
+	if r.active {
+		r.time += dt
+	}
+}
+
+func (r *InputRecorder) record(e InputEvent) {
+	if !r.active {
+		return
+	}
+	e.Time = r.time
+	r.recording.Events = append(r.recording.Events, e)
+}
+
+// InputPlayer replays an InputRecording in a window, see Window.PlayInput. The
+// events are given to the scene as if they were coming from the user, and
+// shown on top of the window: a ghost cursor follows the recorded mouse and
+// the keys and buttons held down are listed at the bottom left corner.
+type InputPlayer struct {
+	recording *InputRecording
+	time      float64
+	next      int
+	playing   bool
+	visible   bool
+
+	// Input state at the current time of the replay.
+	inputState
+}
+
+// NewInputPlayer creates a player for recording. It doesn't play until Play
+// is called.
+func NewInputPlayer(recording *InputRecording) *InputPlayer {
+	return &InputPlayer{
+		recording: recording,
+		visible:   true,
+	}
+}
+
+// Play starts replaying the recording from its beginning.
+func (p *InputPlayer) Play() {
+	p.time = 0
+	p.next = 0
+	p.reset()
+	p.playing = true
+}
+
+// Stop stops the replay.
+func (p *InputPlayer) Stop() {
+	p.playing = false
+}
+
+// IsPlaying returns true from Play until all the events have been replayed
+// or Stop is called.
+func (p *InputPlayer) IsPlaying() bool {
+	return p.playing
+}
+
+// SetVisible shows or hides the ghost cursor and the keys overlay. They are
+// visible by default.
+func (p *InputPlayer) SetVisible(visible bool) {
+	p.visible = visible
+}
+
+// Cursor returns the position of the ghost cursor, in framebuffer pixels.
+func (p *InputPlayer) Cursor() math.Vec2 {
+	return p.cursor
+}
+
+// HeldKeys returns the keys held down at the current time of the replay, in
+// the order they were pressed.
+func (p *InputPlayer) HeldKeys() []Key {
+	return p.keys
+}
+
+// update gives s the events up to the current time then moves the time
+// forward by dt seconds. Recorded events are timestamped before the frame time
+// is advanced, replaying them in the same order keeps them in the frame they
+// were received in.
+func (p *InputPlayer) update(dt float64, s Scener) {
+	if !p.playing {
+		return
+	}
+
+	events := p.recording.Events
+	for ; p.next < len(events) && events[p.next].Time <= p.time; p.next++ {
+		e := &events[p.next]
+		p.track(e)
+		e.dispatch(s)
+	}
+	if p.next == len(events) {
+		p.playing = false
+	}
+	p.time += dt
+}
+
+// ghostCursor is the arrow drawn at the position of the recorded mouse: '#'
+// pixels are the outline and 'o' ones the inside.
+var ghostCursor = []string{
+	"#.........",
+	"##........",
+	"#o#.......",
+	"#oo#......",
+	"#ooo#.....",
+	"#oooo#....",
+	"#ooooo#...",
+	"#oooooo#..",
+	"#ooooooo#.",
+	"#oooo#####",
+	"#oo#o#....",
+	"#o#.#o#...",
+	"##..#o#...",
+	"#....#o#..",
+	".....#o#..",
+	"......##..",
+}
+
+var (
+	ghostCursorOutline = Color{0, 0, 0, 1}
+	ghostCursorFill    = Color{1, 1, 1, 1}
+	// The inside of the cursor while a mouse button is held down.
+	ghostCursorPressed = Color{1, .8, 0, 1}
+)
+
+// overlay builds the ghost cursor and keys overlay of a window of the given
+// height.
+func (p *InputPlayer) overlay(height int) *Mesh {
+	var b overlayBuilder
+
+	p.drawHeld(&b, height)
+
+	const pixel = statsPixelSize
+	fill := &ghostCursorFill
+	if len(p.buttons) > 0 {
+		fill = &ghostCursorPressed
+	}
+	b.bitmap(p.cursor[0], p.cursor[1], pixel, ghostCursor, '#', &ghostCursorOutline)
+	b.bitmap(p.cursor[0], p.cursor[1], pixel, ghostCursor, 'o', fill)
+
+	return b.mesh()
+}
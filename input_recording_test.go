@@ -0,0 +1,156 @@
+package dax
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// inputScene records the input events it receives.
+type inputScene struct {
+	Scene
+	events []InputEvent
+}
+
+func (s *inputScene) OnKeyPressed(key Key, scancode int, mods ModifierKey) {
+	s.events = append(s.events, InputEvent{Kind: InputKeyPressed, Key: key, Scancode: scancode, Mods: mods})
+}
+
+func (s *inputScene) OnKeyReleased(key Key, scancode int, mods ModifierKey) {
+	s.events = append(s.events, InputEvent{Kind: InputKeyReleased, Key: key, Scancode: scancode, Mods: mods})
+}
+
+func (s *inputScene) OnMouseMoved(x, y float32) {
+	s.events = append(s.events, InputEvent{Kind: InputMouseMoved, X: x, Y: y})
+}
+
+func (s *inputScene) OnMouseButtonPressed(button MouseButton, x, y float32) {
+	s.events = append(s.events, InputEvent{Kind: InputMouseButtonPressed, Button: button, X: x, Y: y})
+}
+
+func (s *inputScene) OnMouseButtonReleased(button MouseButton, x, y float32) {
+	s.events = append(s.events, InputEvent{Kind: InputMouseButtonReleased, Button: button, X: x, Y: y})
+}
+
+func (s *inputScene) OnRuneEntered(r rune) {
+	s.events = append(s.events, InputEvent{Kind: InputRuneEntered, Rune: r})
+}
+
+func recordTestInput() *InputRecording {
+	r := NewInputRecorder()
+
+	// Events aren't recorded before Start.
+	r.record(InputEvent{Kind: InputRuneEntered, Rune: 'x'})
+	r.advance(1)
+
+	r.Start()
+	r.record(InputEvent{Kind: InputMouseMoved, X: 10, Y: 20})
+	r.advance(.5)
+	r.record(InputEvent{Kind: InputKeyPressed, Key: 1, Mods: 2})
+	r.record(InputEvent{Kind: InputRuneEntered, Rune: 'a'})
+	r.advance(.5)
+	r.record(InputEvent{Kind: InputMouseButtonPressed, Button: 1, X: 30, Y: 40})
+	r.record(InputEvent{Kind: InputKeyReleased, Key: 1})
+	return r.Stop()
+}
+
+func TestInputRecorder(t *testing.T) {
+	recording := recordTestInput()
+
+	assert.Equal(t, 5, len(recording.Events))
+	times := []float64{0, .5, .5, 1, 1}
+	for i, e := range recording.Events {
+		assert.Equal(t, times[i], e.Time)
+	}
+	assert.Equal(t, 1.0, recording.Duration())
+}
+
+func TestInputRecordingSaveLoad(t *testing.T) {
+	recording := recordTestInput()
+
+	var buf bytes.Buffer
+	assert.Nil(t, recording.Save(&buf))
+	loaded, err := LoadInputRecording(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, recording, loaded)
+
+	_, err = LoadInputRecording(bytes.NewBufferString("{"))
+	assert.NotNil(t, err)
+}
+
+func TestInputPlayer(t *testing.T) {
+	recording := recordTestInput()
+	s := &inputScene{}
+	p := NewInputPlayer(recording)
+
+	// Nothing is replayed before Play.
+	p.update(.5, s)
+	assert.Equal(t, 0, len(s.events))
+
+	p.Play()
+	assert.True(t, p.IsPlaying())
+
+	// Events are replayed in the frame they were recorded in.
+	p.update(.5, s)
+	assert.Equal(t, 1, len(s.events))
+	assert.Equal(t, float32(10), p.Cursor()[0])
+	assert.Equal(t, float32(20), p.Cursor()[1])
+
+	p.update(.5, s)
+	assert.Equal(t, 3, len(s.events))
+	assert.Equal(t, []Key{1}, p.HeldKeys())
+	assert.Equal(t, 'a', s.events[2].Rune)
+
+	p.update(.5, s)
+	assert.Equal(t, 5, len(s.events))
+	assert.Equal(t, 0, len(p.HeldKeys()))
+	assert.Equal(t, []MouseButton{1}, p.buttons)
+	assert.Equal(t, float32(30), p.Cursor()[0])
+	assert.False(t, p.IsPlaying())
+
+	for i := range s.events {
+		e := recording.Events[i]
+		e.Time = 0
+		assert.Equal(t, e, s.events[i])
+	}
+
+	// Play restarts from the beginning.
+	p.Play()
+	p.update(.5, s)
+	assert.Equal(t, 6, len(s.events))
+	assert.Equal(t, 0, len(p.buttons))
+}
+
+func TestInputPlayerOverlay(t *testing.T) {
+	p := NewInputPlayer(&InputRecording{})
+	p.track(&InputEvent{Kind: InputMouseMoved, X: 100, Y: 50})
+
+	// Only the cursor is drawn, at the mouse position.
+	m := p.overlay(480)
+	positions := m.GetAttribute("position")
+	outline, inside := 0, 0
+	for _, row := range ghostCursor {
+		for _, pixel := range row {
+			switch pixel {
+			case '#':
+				outline++
+			case 'o':
+				inside++
+			}
+		}
+	}
+	assert.Equal(t, (outline+inside)*6, positions.Len())
+	x, y := positions.GetXY(0)
+	assertFloat(t, 100, x, 1e-3)
+	assertFloat(t, 50, y, 1e-3)
+
+	// Held keys are drawn in a box at the bottom left corner.
+	p.track(&InputEvent{Kind: InputKeyPressed, Key: 1})
+	m = p.overlay(480)
+	positions = m.GetAttribute("position")
+	assert.True(t, positions.Len() > (outline+inside)*6)
+	x, y = positions.GetXY(2)
+	assertFloat(t, 480-statsMargin, y, 1e-3)
+	assert.True(t, x > statsMargin)
+}
@@ -0,0 +1,152 @@
+package dax
+
+import "sync/atomic"
+
+// Instances draw a shared subtree, the prototype, at several places of the
+// scene graph without duplicating it: changes to the prototype, its transforms,
+// components or children, are seen by all the instances.
+//
+// Each instance keeps a mirror of the prototype subtree, parented to the
+// instance. The mirror is made of internal nodes sharing the components of
+// the prototype nodes but with their own world transforms, the ones of the
+// copy drawn by this instance. It's synchronized with the prototype when world
+// transforms are updated after a change of the prototype subtree, so the rest
+// of dax, drawing, picking or lighting, handles instanced nodes like any other
+// node.
+
+// NewInstance creates a node drawing the subtree rooted at prototype. The
+// transform of the instance places the copy of the subtree, replacing the
+// transform of the prototype root. prototype doesn't need to be part of a
+// scene graph.
+func NewInstance(prototype *Node) *Node {
+	n := NewNode()
+	n.prototype = prototype
+	return n
+}
+
+// Prototype returns the root of the subtree instanced by n, nil if n isn't an
+// instance.
+func (n *Node) Prototype() *Node {
+	return n.prototype
+}
+
+// isInstanced returns true if n is part of the mirror of an instance.
+func (n *Node) isInstanced() bool {
+	return n.instanced != nil
+}
+
+// source returns the node of the scene graph n stands for: for nodes of a
+// mirror, the prototype node they mirror, n itself otherwise.
+func (n *Node) source() *Node {
+	if n.instanced != nil {
+		return n.instanced
+	}
+	return n
+}
+
+// outerInstance returns, for nodes of a mirror, the instance of the scene
+// graph they are drawn through. With nested instances, it's the outermost one.
+func (n *Node) outerInstance() *Node {
+	a := n
+	for a != nil && a.isInstanced() {
+		a = a.parentNode()
+	}
+	return a
+}
+
+// parentNode returns the parent of n, nil if it has none.
+func (n *Node) parentNode() *Node {
+	parent, _ := n.parent.(*Node)
+	return parent
+}
+
+// nodeVersion counts the changes made to nodes, see touch.
+var nodeVersion uint64
+
+// touch records a change of n, its transform, components or children, in the
+// version of n and of its ancestors: instances of any of them need to update
+// their mirror.
+func (n *Node) touch() {
+	version := atomic.AddUint64(&nodeVersion, 1)
+	for a := n; a != nil; a = a.parentNode() {
+		a.version = version
+	}
+}
+
+// instanceDirty returns true if the mirror of the instance n needs to be
+// synchronized with the prototype. Nested instances may change without
+// touching the prototype, mirrors with nested instances are always
+// synchronized.
+func (n *Node) instanceDirty() bool {
+	return n.instanceRoot == nil || n.nested || n.synced != n.prototype.version
+}
+
+// syncInstance updates the mirror of the prototype subtree drawn by the
+// instance n.
+func (n *Node) syncInstance() {
+	// An instance can't draw a subtree it is part of, directly or through
+	// other instances.
+	child := n
+	for a, _ := n.parent.(*Node); a != nil; child, a = a, a.parentNode() {
+		if a.source() == n.prototype || (a.instanceRoot == child && a.prototype == n.prototype) {
+			panic("scenegraph: instance cycle")
+		}
+	}
+
+	if n.instanceRoot == nil {
+		n.instanceRoot = NewNode()
+		n.instanceRoot.parent = n
+	}
+
+	// The instance transform replaces the one of the prototype root.
+	root := n.instanceRoot
+	root.instanced = n.prototype
+	root.components = n.prototype.components
+	n.nested = root.syncChildren(n.prototype)
+	n.synced = n.prototype.version
+}
+
+// syncMirror makes the mirror node n reflect the prototype node p. It returns
+// true if the subtree of p has instances.
+func (n *Node) syncMirror(p *Node) bool {
+	p.updateTransform()
+	n.position = p.position
+	n.rotation = p.rotation
+	n.scale = p.scale
+	n.transform = p.transform
+	n.transformValid = true
+
+	n.instanced = p
+	n.components = p.components
+	if n.prototype != p.prototype {
+		n.prototype = p.prototype
+		n.instanceRoot = nil
+	}
+
+	return n.syncChildren(p) || p.prototype != nil
+}
+
+// syncChildren makes the children of the mirror node n reflect the ones of
+// the prototype node p, reusing the existing mirror nodes. It returns true if
+// the children of p have instances in their subtree.
+func (n *Node) syncChildren(p *Node) bool {
+	nested := false
+	for i := len(p.children); i < len(n.children); i++ {
+		n.children[i] = nil
+	}
+	if len(n.children) > len(p.children) {
+		n.children = n.children[:len(p.children)]
+	}
+
+	for i, child := range p.children {
+		if i == len(n.children) {
+			mirror := NewNode()
+			mirror.parent = n
+			n.children = append(n.children, mirror)
+		}
+		if n.children[i].(*Node).syncMirror(child.(*Node)) {
+			nested = true
+		}
+	}
+	return nested
+}
@@ -0,0 +1,188 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// instancedWorldPosition returns the world position of the mirror of prototype drawn
+// by instance.
+func instancedWorldPosition(t *testing.T, sg *SceneGraph, instance, prototype *Node) math.Vec3 {
+	var position math.Vec3
+	found := false
+
+	// The traversal is drained: its goroutine reads the children of the nodes.
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok || found || node.source() != prototype || node.outerInstance() != instance {
+			continue
+		}
+		position = math.Vec3{
+			node.worldTransform[12],
+			node.worldTransform[13],
+			node.worldTransform[14],
+		}
+		found = true
+	}
+	if !found {
+		t.Fatalf("node not instanced")
+	}
+	return position
+}
+
+func TestInstance(t *testing.T) {
+	// A two node prototype, not part of the scene graph.
+	arm := NewNode()
+	arm.SetPosition(100, 100, 100)
+	hand := NewNode()
+	hand.SetPosition(0, 1, 0)
+	arm.AddChild(hand)
+
+	sg := NewSceneGraph()
+	left := NewInstance(arm)
+	left.SetPosition(-10, 0, 0)
+	right := NewInstance(arm)
+	right.SetPosition(10, 0, 0)
+	sg.AddChildren(left, right)
+	assert.Equal(t, arm, left.Prototype())
+
+	sg.updateWorldTransform()
+	p := instancedWorldPosition(t, sg, left, hand)
+	assertVec3(t, &math.Vec3{-10, 1, 0}, &p, 1e-6)
+	p = instancedWorldPosition(t, sg, right, hand)
+	assertVec3(t, &math.Vec3{10, 1, 0}, &p, 1e-6)
+
+	// Changes to the prototype are seen by all instances.
+	hand.SetPosition(0, 2, 0)
+	finger := NewNode()
+	finger.SetPosition(0, 0, 1)
+	hand.AddChild(finger)
+	sg.updateWorldTransform()
+	p = instancedWorldPosition(t, sg, left, hand)
+	assertVec3(t, &math.Vec3{-10, 2, 0}, &p, 1e-6)
+	p = instancedWorldPosition(t, sg, right, finger)
+	assertVec3(t, &math.Vec3{10, 2, 1}, &p, 1e-6)
+
+	// Nested instances.
+	body := NewNode()
+	shoulder := NewInstance(arm)
+	shoulder.SetPosition(0, 5, 0)
+	body.AddChild(shoulder)
+	character := NewInstance(body)
+	character.SetPosition(0, 0, 20)
+	sg.AddChild(character)
+	sg.updateWorldTransform()
+	p = instancedWorldPosition(t, sg, character, finger)
+	assertVec3(t, &math.Vec3{0, 7, 21}, &p, 1e-6)
+}
+
+func TestInstanceSync(t *testing.T) {
+	arm := NewNode()
+	hand := NewNode()
+	hand.SetPosition(0, 1, 0)
+	arm.AddChild(hand)
+
+	sg := NewSceneGraph()
+	instance := NewInstance(arm)
+	sg.AddChild(instance)
+	sg.updateWorldTransform()
+	assert.False(t, instance.instanceDirty())
+
+	// Moving the instance doesn't need a sync of the mirror.
+	instance.SetPosition(10, 0, 0)
+	assert.False(t, instance.instanceDirty())
+	sg.updateWorldTransform()
+	p := instancedWorldPosition(t, sg, instance, hand)
+	assertVec3(t, &math.Vec3{10, 1, 0}, &p, 1e-6)
+
+	// Changes of the prototype subtree do.
+	hand.SetPosition(0, 2, 0)
+	assert.True(t, instance.instanceDirty())
+	sg.updateWorldTransform()
+	assert.False(t, instance.instanceDirty())
+	p = instancedWorldPosition(t, sg, instance, hand)
+	assertVec3(t, &math.Vec3{10, 2, 0}, &p, 1e-6)
+
+	hand.AddComponent(&countingUpdater{})
+	assert.True(t, instance.instanceDirty())
+	sg.updateWorldTransform()
+	hand.AddChild(NewNode())
+	assert.True(t, instance.instanceDirty())
+	sg.updateWorldTransform()
+
+	// Prototypes with nested instances are always synchronized.
+	body := NewNode()
+	body.AddChild(NewInstance(arm))
+	character := NewInstance(body)
+	sg.AddChild(character)
+	sg.updateWorldTransform()
+	assert.True(t, character.instanceDirty())
+	assert.False(t, instance.instanceDirty())
+}
+
+func TestInstanceCycle(t *testing.T) {
+	sg := NewSceneGraph()
+	a := NewNode()
+	a.AddChild(NewInstance(a))
+	sg.AddChild(NewInstance(a))
+	assert.Panics(t, func() { sg.updateWorldTransform() })
+
+	// Indirect cycle.
+	sg = NewSceneGraph()
+	b, c := NewNode(), NewNode()
+	b.AddChild(NewInstance(c))
+	c.AddChild(NewInstance(b))
+	sg.AddChild(NewInstance(b))
+	assert.Panics(t, func() { sg.updateWorldTransform() })
+
+	// Instancing a prototype under one of its instances isn't a cycle.
+	sg = NewSceneGraph()
+	d := NewNode()
+	outer := NewInstance(d)
+	outer.AddChild(NewInstance(d))
+	sg.AddChild(outer)
+	assert.NotPanics(t, func() { sg.updateWorldTransform() })
+}
+
+func TestPickInstance(t *testing.T) {
+	cube := NewNode().AddComponent(NewMeshRenderer(&cubeMesh{}, &dummyOpaqueMaterial{}))
+	instance := NewInstance(cube)
+	instance.SetPosition(0, 0, -10)
+	sg := NewSceneGraph()
+	sg.AddChild(instance)
+	sg.updateWorldTransform()
+
+	ray := math.Ray{Origin: math.Vec3{0, 0, 0}, Direction: math.Vec3{0, 0, -1}}
+	results := sg.PickRay(&ray)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, cube, results[0].Node)
+	assert.Equal(t, instance, results[0].Instance)
+	assertFloat(t, 9.5, results[0].Distance, 1e-3)
+}
+
+type countingUpdater struct {
+	n int
+}
+
+func (u *countingUpdater) Update(dt float64) {
+	u.n++
+}
+
+func TestUpdateInstance(t *testing.T) {
+	u := &countingUpdater{}
+	prototype := NewNode()
+	prototype.AddChild(NewNode().AddComponent(u))
+
+	sg := NewSceneGraph()
+	sg.AddChildren(NewInstance(prototype), NewInstance(prototype))
+	sg.Update(0)
+	sg.Update(0)
+	assert.Equal(t, 2, u.n)
+
+	// Prototypes part of the scene graph aren't updated twice either.
+	sg.AddChild(prototype)
+	sg.Update(0)
+	assert.Equal(t, 3, u.n)
+}
@@ -0,0 +1,106 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// InstancedMesh draws a mesh many times, one copy per transform, with a single
+// instanced draw call. It's much cheaper than a node per copy when drawing
+// thousands of identical objects, eg. particles, vegetation or voxels:
+//
+//	boxes := dax.NewInstancedMesh(box, material)
+//	for i := range positions {
+//		t := math.Translate3D(positions[i][0], positions[i][1], positions[i][2])
+//		boxes.Add(&t)
+//	}
+//	fb.Draw(boxes)
+//
+// The transforms are world transforms and are uploaded to the GPU at each
+// draw. Copies are lit by the lights of the last scene graph drawn into the
+// framebuffer and aren't culled individually. Transforms are expected to keep
+// the orientation of the faces: mirroring ones are drawn with inverted faces.
+type InstancedMesh struct {
+	mesher     Mesher
+	material   Material
+	properties *PropertyBlock
+	transforms []math.Mat4
+}
+
+// NewInstancedMesh creates an InstancedMesh drawing mesher with material,
+// without any copy.
+func NewInstancedMesh(mesher Mesher, material Material) *InstancedMesh {
+	return &InstancedMesh{
+		mesher:   mesher,
+		material: material,
+	}
+}
+
+// SetPropertyBlock sets uniform values overriding the ones of the material,
+// for all the copies. nil removes the overrides.
+func (m *InstancedMesh) SetPropertyBlock(properties *PropertyBlock) {
+	m.properties = properties
+}
+
+// PropertyBlock returns the property block set with SetPropertyBlock.
+func (m *InstancedMesh) PropertyBlock() *PropertyBlock {
+	return m.properties
+}
+
+// Add adds a copy of the mesh drawn with the transform t.
+func (m *InstancedMesh) Add(t *math.Mat4) {
+	m.transforms = append(m.transforms, *t)
+}
+
+// SetTransforms replaces the copies of the mesh by one per transform.
+func (m *InstancedMesh) SetTransforms(transforms []math.Mat4) {
+	m.transforms = append(m.transforms[:0], transforms...)
+}
+
+// Transforms returns the transforms of the copies. They can be modified in
+// place, eg. to animate the copies.
+func (m *InstancedMesh) Transforms() []math.Mat4 {
+	return m.transforms
+}
+
+// Len returns the number of copies.
+func (m *InstancedMesh) Len() int {
+	return len(m.transforms)
+}
+
+// Clear removes all the copies.
+func (m *InstancedMesh) Clear() {
+	m.transforms = m.transforms[:0]
+}
+
+// Bounds implements Bounder. The box contains all the copies, in world space.
+// It's empty when there are no copies or the mesher doesn't have bounds.
+func (m *InstancedMesh) Bounds() math.AABB {
+	bounds := math.EmptyAABB()
+	b, ok := m.mesher.(Bounder)
+	if !ok {
+		return bounds
+	}
+
+	local := b.Bounds()
+	for i := range m.transforms {
+		world := local.Transform(&m.transforms[i])
+		bounds = bounds.Merge(&world)
+	}
+	return bounds
+}
+
+// appendInstances appends the per-instance data of the copies, the model and
+// previous model matrices, to instances. Copies don't move from the point of
+// view of motion vectors.
+func (m *InstancedMesh) appendInstances(instances []float32) []float32 {
+	for i := range m.transforms {
+		instances = append(instances, m.transforms[i][:]...)
+		instances = append(instances, m.transforms[i][:]...)
+	}
+	return instances
+}
+
+// Draw implements Drawer.
+func (m *InstancedMesh) Draw(fb Framebuffer) {
+	fb.render().drawInstancedMesh(fb, m)
+}
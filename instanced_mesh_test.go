@@ -0,0 +1,49 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstancedMesh(t *testing.T) {
+	mesh := NewMesh()
+	mesh.AddAttribute("position", []float32{
+		-1, -1, -1,
+		1, 1, 1,
+	}, 3)
+	m := NewInstancedMesh(mesh, nil)
+	assert.Equal(t, 0, m.Len())
+	bounds := m.Bounds()
+	assert.True(t, bounds.IsEmpty())
+
+	t1 := math.Translate3D(10, 0, 0)
+	m.Add(&t1)
+	t2 := math.Translate3D(0, -10, 0)
+	m.Add(&t2)
+	assert.Equal(t, 2, m.Len())
+
+	// Bounds cover all the copies.
+	bounds = m.Bounds()
+	assert.Equal(t, math.Vec3{-1, -11, -1}, bounds.Min)
+	assert.Equal(t, math.Vec3{11, 1, 1}, bounds.Max)
+
+	// Each copy has its model matrix twice, as the previous model matrix
+	// too.
+	instances := m.appendInstances(nil)
+	assert.Equal(t, 2*instanceStride/4, len(instances))
+	assert.Equal(t, t1[:], instances[:16])
+	assert.Equal(t, t1[:], instances[16:32])
+	assert.Equal(t, t2[:], instances[32:48])
+
+	// Transforms can be modified in place.
+	m.Transforms()[1] = math.Ident4()
+	bounds = m.Bounds()
+	assert.Equal(t, math.Vec3{-1, -1, -1}, bounds.Min)
+
+	m.SetTransforms([]math.Mat4{math.Ident4()})
+	assert.Equal(t, 1, m.Len())
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+}
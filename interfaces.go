@@ -1,13 +1,31 @@
 package dax
 
+import (
+	"github.com/dlespiau/dax/math"
+)
+
 // Mesher is an object that can produce a Mesh.
 type Mesher interface {
 	GetMesh() *Mesh
 }
 
-// Updater is an object that would like to be updated at very frame
+// Bounder is an object with a bounding box, in its local space.
+type Bounder interface {
+	Bounds() math.AABB
+}
+
+// SphereBounder is an object with a bounding sphere, in its local space. The
+// sphere is usually tighter than the one circumscribing the bounding box.
+type SphereBounder interface {
+	BoundingSphere() math.BoundingSphere
+}
+
+// Updater is an object that would like to be updated at very frame. dt is the
+// number of seconds elapsed since the previous frame: animations should scale
+// their changes by dt to move at the same speed whatever the frame rate.
+// Application.Time gives the total time elapsed.
 type Updater interface {
-	Update(time float64)
+	Update(dt float64)
 }
 
 // Drawer is an object that can draw on a Framebuffer.
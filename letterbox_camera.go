@@ -0,0 +1,182 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// LetterboxViewport returns the largest viewport with an aspect ratio, width
+// over height, of aspect centered in a width x height framebuffer. Taller
+// framebuffers get bars above and below the viewport (letterboxing), wider
+// ones get bars on its sides (pillarboxing).
+func LetterboxViewport(width, height int, aspect float32) Viewport {
+	if width <= 0 || height <= 0 || aspect <= 0 {
+		return Viewport{0, 0, width, height}
+	}
+
+	w, h := width, height
+	if float32(width) > float32(height)*aspect {
+		w = int(math.Floor(float32(height)*aspect + .5))
+	} else {
+		h = int(math.Floor(float32(width)/aspect + .5))
+	}
+
+	return Viewport{
+		X:      (width - w) / 2,
+		Y:      (height - h) / 2,
+		Width:  w,
+		Height: h,
+	}
+}
+
+// SafeArea returns the viewport shrunk by margin, a ratio of its size, on all
+// sides. User interfaces laid out in the safe area stay visible on displays
+// cropping the edges of the image, eg. TVs: 0.05 gives the usual 90% "action
+// safe" area.
+func (v *Viewport) SafeArea(margin float32) Viewport {
+	dx := int(math.Floor(float32(v.Width)*margin + .5))
+	dy := int(math.Floor(float32(v.Height)*margin + .5))
+	// Margins larger than half the viewport collapse it to its center.
+	if 2*dx > v.Width {
+		dx = v.Width / 2
+	}
+	if 2*dy > v.Height {
+		dy = v.Height / 2
+	}
+	return Viewport{v.X + dx, v.Y + dy, v.Width - 2*dx, v.Height - 2*dy}
+}
+
+// letterboxCamera shows the view of another camera at a fixed aspect ratio.
+type letterboxCamera struct {
+	Camera
+	aspect float32
+
+	// Color of the bars, if they have their own.
+	barColor    Color
+	hasBarColor bool
+
+	// Size of the framebuffer and where the view is in it.
+	width, height int
+	box           Viewport
+
+	projection math.Mat4
+}
+
+// NewLetterboxCamera wraps camera to show its view at a fixed aspect ratio,
+// width over height, centered in the framebuffer, whatever the framebuffer
+// size, eg. for games designed for a single aspect ratio or video output.
+// camera is given the size of the view rather than the one of the
+// framebuffer. The rest of the framebuffer, the bars, is left to the
+// background color of the scene, see SetBarColor.
+//
+//	perspective := dax.NewPerspectiveCamera(math.DegToRad(60), 16./9., .1, 100)
+//	scene.SetCamera(dax.NewLetterboxCamera(perspective, 16./9.))
+func NewLetterboxCamera(camera Camera, aspect float32) *letterboxCamera {
+	c := &letterboxCamera{
+		Camera: camera,
+		aspect: aspect,
+	}
+	c.projection = *camera.GetProjection()
+	return c
+}
+
+// SetBarColor sets the color of the bars, instead of the background color of
+// the scene.
+func (c *letterboxCamera) SetBarColor(color Color) {
+	c.barColor = color
+	c.hasBarColor = true
+}
+
+// Aspect returns the aspect ratio of the view.
+func (c *letterboxCamera) Aspect() float32 {
+	return c.aspect
+}
+
+// Viewport returns the rectangle of the framebuffer the view is drawn into.
+func (c *letterboxCamera) Viewport() Viewport {
+	return c.box
+}
+
+// SafeArea returns the safe area of the view, see Viewport.SafeArea.
+func (c *letterboxCamera) SafeArea(margin float32) Viewport {
+	return c.box.SafeArea(margin)
+}
+
+// UpdateFBSize implements Camera.
+func (c *letterboxCamera) UpdateFBSize(width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	c.width, c.height = width, height
+	c.box = LetterboxViewport(width, height, c.aspect)
+	c.Camera.UpdateFBSize(c.box.Width, c.box.Height)
+}
+
+// GetProjection implements Camera. The projection of the wrapped camera is
+// scaled and moved to the view rectangle, so the camera still covers the whole
+// framebuffer and the bars are cut by the scissor test.
+func (c *letterboxCamera) GetProjection() *math.Mat4 {
+	c.projection = c.crop(c.Camera.GetProjection())
+	return &c.projection
+}
+
+// reversedProjection is GetProjection with the reversed projection of the
+// wrapped camera, see cameraProjection.
+func (c *letterboxCamera) reversedProjection() math.Mat4 {
+	projection := cameraProjection(c.Camera, true)
+	return c.crop(&projection)
+}
+
+// crop scales and moves projection to the view rectangle.
+func (c *letterboxCamera) crop(projection *math.Mat4) math.Mat4 {
+	if c.width == 0 || c.height == 0 {
+		return *projection
+	}
+
+	sx := float32(c.box.Width) / float32(c.width)
+	sy := float32(c.box.Height) / float32(c.height)
+	tx := float32(2*c.box.X+c.box.Width)/float32(c.width) - 1
+	ty := float32(2*c.box.Y+c.box.Height)/float32(c.height) - 1
+	crop := math.Mat4{
+		sx, 0, 0, 0,
+		0, sy, 0, 0,
+		0, 0, 1, 0,
+		tx, ty, 0, 1,
+	}
+	return crop.Mul4(projection)
+}
+
+// letterbox implements letterboxer.
+func (c *letterboxCamera) letterbox() Viewport {
+	return c.box
+}
+
+// bars implements letterboxBars.
+func (c *letterboxCamera) bars() (Color, bool) {
+	return c.barColor, c.hasBarColor
+}
+
+// Frame implements Framer when the wrapped camera does.
+func (c *letterboxCamera) Frame(bounds *math.AABB, margin float32) {
+	if f, ok := c.Camera.(Framer); ok {
+		f.Frame(bounds, margin)
+	}
+}
+
+// SetReversedZ forwards to the wrapped camera, if it supports reversed-Z.
+func (c *letterboxCamera) SetReversedZ(reversed bool) {
+	if rc, ok := c.Camera.(interface{ SetReversedZ(reversed bool) }); ok {
+		rc.SetReversedZ(reversed)
+	}
+}
+
+func (c *letterboxCamera) isReversedZ() bool {
+	return isReversedZ(c.Camera)
+}
+
+// letterboxBars is a letterboxer drawing its bars with their own color rather
+// than the background color of the scene.
+type letterboxBars interface {
+	letterboxer
+	bars() (color Color, ok bool)
+}
@@ -0,0 +1,69 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLetterboxViewport(t *testing.T) {
+	tests := []struct {
+		width, height int
+		aspect        float32
+		expected      Viewport
+	}{
+		// Letterboxing: bars above and below.
+		{800, 600, 16. / 9., Viewport{0, 75, 800, 450}},
+		// Pillarboxing: bars on the sides.
+		{1920, 1080, 4. / 3., Viewport{240, 0, 1440, 1080}},
+		// Same aspect ratio: no bars.
+		{1280, 720, 16. / 9., Viewport{0, 0, 1280, 720}},
+		// Invalid aspect ratios keep the whole framebuffer.
+		{640, 480, 0, Viewport{0, 0, 640, 480}},
+	}
+
+	for _, test := range tests {
+		got := LetterboxViewport(test.width, test.height, test.aspect)
+		assert.Equal(t, test.expected, got)
+	}
+}
+
+func TestViewportSafeArea(t *testing.T) {
+	v := Viewport{0, 75, 800, 450}
+	assert.Equal(t, Viewport{40, 98, 720, 404}, v.SafeArea(.05))
+	assert.Equal(t, v, v.SafeArea(0))
+	assert.Equal(t, Viewport{400, 300, 0, 0}, v.SafeArea(.6))
+}
+
+func TestLetterboxCamera(t *testing.T) {
+	perspective := NewPerspectiveCamera(math.DegToRad(60), 1, 1, 100)
+	c := NewLetterboxCamera(perspective, 16./9.)
+	c.SetBarColor(Color{1, 0, 0, 1})
+	c.UpdateFBSize(800, 600)
+
+	box := c.Viewport()
+	assert.Equal(t, Viewport{0, 75, 800, 450}, box)
+	assert.Equal(t, box, c.letterbox())
+	color, ok := c.bars()
+	assert.True(t, ok)
+	assert.Equal(t, Color{1, 0, 0, 1}, color)
+
+	// The wrapped camera follows the aspect ratio of the letterbox.
+	assertFloat(t, 16./9., perspective.aspect, 1e-5)
+
+	// The corners of the view of the wrapped camera are projected on the
+	// corners of the letterbox.
+	inner := *perspective.GetProjection()
+	inverse := inner.Inverse()
+	projection := c.GetProjection()
+	for _, corner := range [][2]float32{{-1, -1}, {1, 1}} {
+		ndc := math.Vec4{corner[0], corner[1], 0, 1}
+		view := inverse.Mul4x1(&ndc)
+		clip := projection.Mul4x1(&view)
+		x := (clip[0]/clip[3] + 1) / 2 * 800
+		y := (clip[1]/clip[3] + 1) / 2 * 600
+		assertFloat(t, float32(box.X)+(corner[0]+1)/2*float32(box.Width), x, 1e-2)
+		assertFloat(t, float32(box.Y)+(corner[1]+1)/2*float32(box.Height), y, 1e-2)
+	}
+}
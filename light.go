@@ -0,0 +1,188 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// LightType is the kind of a Light.
+type LightType int
+
+const (
+	// LightDirectional lights the whole scene from a direction, like the
+	// sun. Only the rotation of the node matters.
+	LightDirectional LightType = iota
+	// LightPoint emits light in all directions from the position of the
+	// node.
+	LightPoint
+	// LightSpot emits a cone of light from the position of the node.
+	LightSpot
+)
+
+// MaxLights is the maximum number of lights taken into account when drawing a
+// scene. Extra lights are ignored.
+const MaxLights = 8
+
+// Light is a component lighting the scene graph it's part of. Like cameras,
+// lights point towards the -Z axis of their node:
+//
+//	light := dax.NewNode()
+//	light.AddComponent(dax.NewPointLight(&dax.Color{R: 1, G: 1, B: 1, A: 1}, 100, 0))
+//	light.SetPosition(0, 10, 0)
+//	sg.AddChild(light)
+//
+// Lights are used by the lit materials of the material package.
+type Light struct {
+	Type LightType
+	// Color is the color of the light, multiplied by Intensity.
+	Color     Color
+	Intensity float32
+	// Range is the distance at which point and spot lights fade out. 0
+	// means the light intensity only decreases with the inverse square of
+	// the distance.
+	Range float32
+	// InnerCone and OuterCone are the angles, in radians, between the spot
+	// direction and the edges of the cone. The light starts to fade at
+	// InnerCone and is off past OuterCone.
+	InnerCone, OuterCone float32
+}
+
+// NewDirectionalLight creates a new directional light.
+func NewDirectionalLight(color *Color, intensity float32) *Light {
+	return &Light{
+		Type:      LightDirectional,
+		Color:     *color,
+		Intensity: intensity,
+	}
+}
+
+// NewPointLight creates a new point light.
+func NewPointLight(color *Color, intensity, lightRange float32) *Light {
+	return &Light{
+		Type:      LightPoint,
+		Color:     *color,
+		Intensity: intensity,
+		Range:     lightRange,
+	}
+}
+
+// NewSpotLight creates a new spot light.
+func NewSpotLight(color *Color, intensity, lightRange, innerCone, outerCone float32) *Light {
+	return &Light{
+		Type:      LightSpot,
+		Color:     *color,
+		Intensity: intensity,
+		Range:     lightRange,
+		InnerCone: innerCone,
+		OuterCone: outerCone,
+	}
+}
+
+func getLight(node *Node) *Light {
+	for i := range node.components {
+		if l, ok := node.components[i].(*Light); ok {
+			return l
+		}
+	}
+	return nil
+}
+
+// lightData is a light as seen by the shaders, in world space.
+type lightData struct {
+	kind       int32
+	color      math.Vec3
+	position   math.Vec3
+	direction  math.Vec3
+	lightRange float32
+	// Cosines of the spot cone angles.
+	cosInner, cosOuter float32
+}
+
+// appendLights collects, in lights, up to MaxLights lights of the scene graph.
+// World transforms are expected to be up to date.
+func appendLights(lights []lightData, sg *SceneGraph) []lightData {
+	lights = lights[:0]
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok {
+			continue
+		}
+		l := getLight(node)
+		if l == nil || len(lights) == MaxLights {
+			continue
+		}
+
+		world := node.worldTransform.AsMat4()
+		position := world.Mul4x1(&math.Vec4{0, 0, 0, 1})
+		direction := world.Mul4x1(&math.Vec4{0, 0, -1, 0})
+		d := direction.Vec3()
+		if d.Len2() > 0 {
+			d.Normalize()
+		}
+
+		lights = append(lights, lightData{
+			kind:       int32(l.Type),
+			color:      math.Vec3{l.Color.R * l.Intensity, l.Color.G * l.Intensity, l.Color.B * l.Intensity},
+			position:   position.Vec3(),
+			direction:  d,
+			lightRange: l.Range,
+			cosInner:   math.Cos(l.InnerCone),
+			cosOuter:   math.Cos(l.OuterCone),
+		})
+	}
+	return lights
+}
+
+// LightsShaderChunk declares the lights uniforms and a daxLight helper. Lit
+// fragment shaders insert it after their #version line:
+//
+//	vec3 l;
+//	float attenuation = daxLight(lights[i], worldPosition, l);
+//
+// l is then the normalized direction from the fragment to the light and the
+// light reaching the fragment is lights[i].color * attenuation.
+const LightsShaderChunk = `
+#define DAX_MAX_LIGHTS 8
+#define DAX_LIGHT_DIRECTIONAL 0
+#define DAX_LIGHT_POINT 1
+#define DAX_LIGHT_SPOT 2
+
+struct Light {
+	int kind;
+	vec3 color;
+	vec3 position;
+	vec3 direction;
+	float range;
+	float cosInner;
+	float cosOuter;
+};
+
+uniform Light lights[DAX_MAX_LIGHTS];
+uniform int lightCount;
+uniform vec3 cameraPosition;
+
+in vec3 worldPosition;
+in vec3 worldNormal;
+
+float daxLight(Light light, vec3 p, out vec3 l) {
+	if (light.kind == DAX_LIGHT_DIRECTIONAL) {
+		l = -light.direction;
+		return 1.0;
+	}
+
+	vec3 d = light.position - p;
+	float distance2 = max(dot(d, d), 1e-4);
+	l = d * inversesqrt(distance2);
+
+	float attenuation = 1.0 / distance2;
+	if (light.range > 0.0) {
+		float r = sqrt(distance2) / light.range;
+		float window = clamp(1.0 - r * r * r * r, 0.0, 1.0);
+		attenuation *= window * window;
+	}
+	if (light.kind == DAX_LIGHT_SPOT) {
+		float cosAngle = dot(-l, light.direction);
+		attenuation *= smoothstep(light.cosOuter, light.cosInner, cosAngle);
+	}
+	return attenuation;
+}
+`
@@ -0,0 +1,64 @@
+package dax
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendLights(t *testing.T) {
+	sg := NewSceneGraph()
+
+	white := &Color{1, 1, 1, 1}
+	sun := NewNode()
+	sun.AddComponent(NewDirectionalLight(white, 2))
+	// Point the sun down.
+	sun.RotateX(-math.Pi / 2)
+
+	parent := NewNode()
+	parent.SetPosition(10, 0, 0)
+	bulb := NewNode()
+	bulb.AddComponent(NewPointLight(&Color{1, .5, 0, 1}, 4, 20))
+	bulb.SetPosition(0, 5, 0)
+	parent.AddChild(bulb)
+
+	sg.AddChildren(sun, parent, NewNode())
+	sg.updateWorldTransform()
+
+	lights := appendLights(nil, sg)
+	if !assert.Equal(t, 2, len(lights)) {
+		return
+	}
+
+	assert.Equal(t, int32(LightDirectional), lights[0].kind)
+	assert.Equal(t, math.Vec3{2, 2, 2}, lights[0].color)
+	assertVec3(t, &math.Vec3{0, -1, 0}, &lights[0].direction, 1e-3)
+
+	assert.Equal(t, int32(LightPoint), lights[1].kind)
+	assert.Equal(t, math.Vec3{4, 2, 0}, lights[1].color)
+	assert.Equal(t, math.Vec3{10, 5, 0}, lights[1].position)
+	assert.Equal(t, float32(20), lights[1].lightRange)
+}
+
+func TestAppendLightsMax(t *testing.T) {
+	sg := NewSceneGraph()
+	for i := 0; i < MaxLights+2; i++ {
+		n := NewNode()
+		n.AddComponent(NewSpotLight(&Color{1, 1, 1, 1}, 1, 10, .2, .4))
+		sg.AddChild(n)
+	}
+	sg.updateWorldTransform()
+
+	lights := appendLights(nil, sg)
+	assert.Equal(t, MaxLights, len(lights))
+	assertFloat(t, math.Cos(.2), lights[0].cosInner, 1e-3)
+	assertFloat(t, math.Cos(.4), lights[0].cosOuter, 1e-3)
+}
+
+func TestLightsShaderChunk(t *testing.T) {
+	// The shaders and the renderer agree on the maximum number of lights.
+	assert.True(t, strings.Contains(LightsShaderChunk, fmt.Sprintf("#define DAX_MAX_LIGHTS %d\n", MaxLights)))
+}
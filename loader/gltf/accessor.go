@@ -0,0 +1,258 @@
+package gltf
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	gomath "math"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	componentByte          = 5120
+	componentUnsignedByte  = 5121
+	componentShort         = 5122
+	componentUnsignedShort = 5123
+	componentUnsignedInt   = 5125
+	componentFloat         = 5126
+)
+
+func componentSize(componentType int) int {
+	switch componentType {
+	case componentByte, componentUnsignedByte:
+		return 1
+	case componentShort, componentUnsignedShort:
+		return 2
+	case componentUnsignedInt, componentFloat:
+		return 4
+	}
+	return 0
+}
+
+func numComponents(accessorType string) int {
+	switch accessorType {
+	case "SCALAR":
+		return 1
+	case "VEC2":
+		return 2
+	case "VEC3":
+		return 3
+	case "VEC4", "MAT2":
+		return 4
+	case "MAT3":
+		return 9
+	case "MAT4":
+		return 16
+	}
+	return 0
+}
+
+// loadBuffers returns the content of the document buffers. Buffers are either
+// embedded as data URIs, stored in external files relative to dir or, for the
+// first buffer of a GLB file, the binary chunk.
+func loadBuffers(doc *document, dir string, bin []byte) ([][]byte, error) {
+	buffers := make([][]byte, len(doc.Buffers))
+
+	for i := range doc.Buffers {
+		b := &doc.Buffers[i]
+		var data []byte
+		var err error
+
+		switch {
+		case b.URI == "":
+			if i != 0 || bin == nil {
+				return nil, fmt.Errorf("gltf: buffer %d has no data", i)
+			}
+			data = bin
+		case strings.HasPrefix(b.URI, "data:"):
+			comma := strings.IndexByte(b.URI, ',')
+			if comma < 0 || !strings.HasSuffix(b.URI[:comma], ";base64") {
+				return nil, fmt.Errorf("gltf: buffer %d: unsupported data URI", i)
+			}
+			data, err = base64.StdEncoding.DecodeString(b.URI[comma+1:])
+		default:
+			var path string
+			path, err = url.PathUnescape(b.URI)
+			if err == nil {
+				data, err = ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gltf: buffer %d: %v", i, err)
+		}
+
+		if len(data) < b.ByteLength {
+			return nil, fmt.Errorf("gltf: buffer %d is too short: %d bytes, expected %d",
+				i, len(data), b.ByteLength)
+		}
+		buffers[i] = data
+	}
+
+	return buffers, nil
+}
+
+// accessorData returns the bytes backing accessor a and the stride between
+// two elements.
+func accessorData(doc *document, buffers [][]byte, a *accessor) (data []byte, stride int, err error) {
+	if a.Sparse != nil {
+		return nil, 0, fmt.Errorf("gltf: sparse accessors aren't supported")
+	}
+	if a.Count < 0 || a.ByteOffset < 0 {
+		return nil, 0, fmt.Errorf("gltf: invalid accessor count %d or offset %d", a.Count, a.ByteOffset)
+	}
+
+	elementSize := componentSize(a.ComponentType) * numComponents(a.Type)
+	if elementSize == 0 {
+		return nil, 0, fmt.Errorf("gltf: unsupported accessor %s of type %d", a.Type, a.ComponentType)
+	}
+
+	if a.BufferView == nil {
+		// No buffer view: the accessor is all zeros.
+		if a.Count > gomath.MaxInt32/elementSize {
+			return nil, 0, fmt.Errorf("gltf: accessor count %d too large", a.Count)
+		}
+		return make([]byte, a.Count*elementSize), elementSize, nil
+	}
+
+	if *a.BufferView < 0 || *a.BufferView >= len(doc.BufferViews) {
+		return nil, 0, fmt.Errorf("gltf: invalid buffer view %d", *a.BufferView)
+	}
+	view := &doc.BufferViews[*a.BufferView]
+	if view.Buffer < 0 || view.Buffer >= len(buffers) {
+		return nil, 0, fmt.Errorf("gltf: invalid buffer %d", view.Buffer)
+	}
+	if view.ByteOffset < 0 || view.ByteLength < 0 {
+		return nil, 0, fmt.Errorf("gltf: invalid buffer view offset %d or length %d",
+			view.ByteOffset, view.ByteLength)
+	}
+
+	stride = view.ByteStride
+	if stride == 0 {
+		stride = elementSize
+	}
+	if stride < elementSize {
+		return nil, 0, fmt.Errorf("gltf: invalid stride %d for elements of %d bytes", stride, elementSize)
+	}
+
+	// Bounds are checked against the lengths before computing the end of
+	// the data, which could overflow otherwise.
+	viewEnd := view.ByteOffset + view.ByteLength
+	if viewEnd < 0 || viewEnd > len(buffers[view.Buffer]) || a.ByteOffset > view.ByteLength {
+		return nil, 0, fmt.Errorf("gltf: accessor out of its buffer view bounds")
+	}
+	start := view.ByteOffset + a.ByteOffset
+	end := start
+	if a.Count > 0 {
+		if a.Count-1 > (viewEnd-start)/stride {
+			return nil, 0, fmt.Errorf("gltf: accessor out of its buffer view bounds")
+		}
+		end += (a.Count-1)*stride + elementSize
+	}
+	if end > viewEnd {
+		return nil, 0, fmt.Errorf("gltf: accessor out of its buffer view bounds")
+	}
+
+	return buffers[view.Buffer][start:end], stride, nil
+}
+
+// readComponent reads the nth component of type componentType from data as a
+// float, normalizing integers if asked to.
+func readComponent(data []byte, componentType int, normalized bool) float32 {
+	switch componentType {
+	case componentFloat:
+		return gomath.Float32frombits(binary.LittleEndian.Uint32(data))
+	case componentByte:
+		v := float32(int8(data[0]))
+		if normalized {
+			return float32(gomath.Max(float64(v/127), -1))
+		}
+		return v
+	case componentUnsignedByte:
+		v := float32(data[0])
+		if normalized {
+			return v / 255
+		}
+		return v
+	case componentShort:
+		v := float32(int16(binary.LittleEndian.Uint16(data)))
+		if normalized {
+			return float32(gomath.Max(float64(v/32767), -1))
+		}
+		return v
+	case componentUnsignedShort:
+		v := float32(binary.LittleEndian.Uint16(data))
+		if normalized {
+			return v / 65535
+		}
+		return v
+	case componentUnsignedInt:
+		return float32(binary.LittleEndian.Uint32(data))
+	}
+	return 0
+}
+
+// readFloats returns the content of accessor index as floats, along with the
+// number of components per element.
+func readFloats(doc *document, buffers [][]byte, index int) ([]float32, int, error) {
+	if index < 0 || index >= len(doc.Accessors) {
+		return nil, 0, fmt.Errorf("gltf: invalid accessor %d", index)
+	}
+	a := &doc.Accessors[index]
+
+	data, stride, err := accessorData(doc, buffers, a)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	n := numComponents(a.Type)
+	size := componentSize(a.ComponentType)
+	floats := make([]float32, 0, a.Count*n)
+	for i := 0; i < a.Count; i++ {
+		element := data[i*stride:]
+		for c := 0; c < n; c++ {
+			floats = append(floats, readComponent(element[c*size:], a.ComponentType, a.Normalized))
+		}
+	}
+
+	return floats, n, nil
+}
+
+// readIndices returns the content of the scalar accessor index as indices of
+// vertices, out of range if they aren't lower than vertexCount.
+func readIndices(doc *document, buffers [][]byte, index, vertexCount int) ([]uint, error) {
+	if index < 0 || index >= len(doc.Accessors) {
+		return nil, fmt.Errorf("gltf: invalid accessor %d", index)
+	}
+	a := &doc.Accessors[index]
+	if a.Type != "SCALAR" {
+		return nil, fmt.Errorf("gltf: indices accessor of type %s", a.Type)
+	}
+
+	data, stride, err := accessorData(doc, buffers, a)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]uint, a.Count)
+	for i := range indices {
+		element := data[i*stride:]
+		switch a.ComponentType {
+		case componentUnsignedByte:
+			indices[i] = uint(element[0])
+		case componentUnsignedShort:
+			indices[i] = uint(binary.LittleEndian.Uint16(element))
+		case componentUnsignedInt:
+			indices[i] = uint(binary.LittleEndian.Uint32(element))
+		default:
+			return nil, fmt.Errorf("gltf: invalid indices component type %d", a.ComponentType)
+		}
+		if indices[i] >= uint(vertexCount) {
+			return nil, fmt.Errorf("gltf: index %d out of range, %d vertices", indices[i], vertexCount)
+		}
+	}
+
+	return indices, nil
+}
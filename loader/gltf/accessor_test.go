@@ -0,0 +1,233 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intp(i int) *int { return &i }
+
+// buildBuffer returns the little endian encoding of values.
+func buildBuffer(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadBuffers(t *testing.T) {
+	data := []byte{1, 2, 3, 4}
+	doc := &document{
+		Buffers: []buffer{
+			{ByteLength: 4},
+			{URI: "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(data), ByteLength: 4},
+		},
+	}
+
+	buffers, err := loadBuffers(doc, "", []byte{5, 6, 7, 8})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{{5, 6, 7, 8}, data}, buffers)
+
+	// Buffer 0 without uri needs a GLB binary chunk.
+	_, err = loadBuffers(doc, "", nil)
+	assert.NotNil(t, err)
+
+	// Too short.
+	doc.Buffers[1].ByteLength = 5
+	_, err = loadBuffers(doc, "", []byte{5, 6, 7, 8})
+	assert.NotNil(t, err)
+}
+
+func TestReadFloats(t *testing.T) {
+	// Interleaved VEC3 float positions and VEC2 normalized ubyte uvs, with 2
+	// bytes of padding.
+	data := buildBuffer(
+		[3]float32{1, 2, 3}, [2]uint8{0, 255}, [2]uint8{},
+		[3]float32{4, 5, 6}, [2]uint8{255, 0}, [2]uint8{},
+	)
+	doc := &document{
+		BufferViews: []bufferView{
+			{Buffer: 0, ByteLength: len(data), ByteStride: 16},
+		},
+		Accessors: []accessor{
+			{BufferView: intp(0), ComponentType: componentFloat, Count: 2, Type: "VEC3"},
+			{BufferView: intp(0), ByteOffset: 12, ComponentType: componentUnsignedByte,
+				Normalized: true, Count: 2, Type: "VEC2"},
+			{ComponentType: componentFloat, Count: 2, Type: "SCALAR"},
+			{BufferView: intp(0), ComponentType: componentFloat, Count: 3, Type: "VEC3"},
+		},
+	}
+	buffers := [][]byte{data}
+
+	positions, n, err := readFloats(doc, buffers, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []float32{1, 2, 3, 4, 5, 6}, positions)
+
+	uvs, n, err := readFloats(doc, buffers, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []float32{0, 1, 1, 0}, uvs)
+
+	// No buffer view: zeros.
+	zeros, _, err := readFloats(doc, buffers, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []float32{0, 0}, zeros)
+
+	// Out of bounds.
+	_, _, err = readFloats(doc, buffers, 3)
+	assert.NotNil(t, err)
+	_, _, err = readFloats(doc, buffers, 4)
+	assert.NotNil(t, err)
+}
+
+func TestReadIndices(t *testing.T) {
+	data := buildBuffer([]uint16{0, 1, 2, 2}, []uint32{3, 4})
+	doc := &document{
+		BufferViews: []bufferView{
+			{Buffer: 0, ByteLength: 8},
+			{Buffer: 0, ByteOffset: 8, ByteLength: 8},
+		},
+		Accessors: []accessor{
+			{BufferView: intp(0), ComponentType: componentUnsignedShort, Count: 3, Type: "SCALAR"},
+			{BufferView: intp(1), ComponentType: componentUnsignedInt, Count: 2, Type: "SCALAR"},
+			{BufferView: intp(0), ComponentType: componentUnsignedShort, Count: 2, Type: "VEC2"},
+		},
+	}
+	buffers := [][]byte{data}
+
+	indices, err := readIndices(doc, buffers, 0, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint{0, 1, 2}, indices)
+
+	indices, err = readIndices(doc, buffers, 1, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint{3, 4}, indices)
+
+	_, err = readIndices(doc, buffers, 2, 5)
+	assert.NotNil(t, err)
+
+	// Indices out of range.
+	_, err = readIndices(doc, buffers, 1, 4)
+	assert.NotNil(t, err)
+}
+
+func TestMalformedAccessors(t *testing.T) {
+	data := buildBuffer([]float32{0, 1, 2, 3, 4, 5, 6, 7})
+	valid := func() *document {
+		return &document{
+			BufferViews: []bufferView{{Buffer: 0, ByteLength: len(data)}},
+			Accessors: []accessor{
+				{BufferView: intp(0), ComponentType: componentFloat, Count: 2, Type: "VEC3"},
+			},
+		}
+	}
+	buffers := [][]byte{data}
+
+	_, _, err := readFloats(valid(), buffers, 0)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(doc *document)
+	}{
+		{"negative count", func(doc *document) { doc.Accessors[0].Count = -1 }},
+		{"huge count", func(doc *document) { doc.Accessors[0].Count = 1 << 62 }},
+		{"negative offset", func(doc *document) { doc.Accessors[0].ByteOffset = -4 }},
+		{"offset after view", func(doc *document) { doc.Accessors[0].ByteOffset = 64 }},
+		{"negative view offset", func(doc *document) { doc.BufferViews[0].ByteOffset = -4 }},
+		{"huge view offset", func(doc *document) { doc.BufferViews[0].ByteOffset = 1<<63 - 1 }},
+		{"negative view length", func(doc *document) { doc.BufferViews[0].ByteLength = -4 }},
+		{"view longer than buffer", func(doc *document) { doc.BufferViews[0].ByteLength = 64 }},
+		{"negative stride", func(doc *document) { doc.BufferViews[0].ByteStride = -12 }},
+		{"stride too small", func(doc *document) { doc.BufferViews[0].ByteStride = 4 }},
+		{"huge stride", func(doc *document) { doc.BufferViews[0].ByteStride = 1 << 62 }},
+		{"invalid view", func(doc *document) { doc.Accessors[0].BufferView = intp(-1) }},
+		{"huge count without view", func(doc *document) {
+			doc.Accessors[0].BufferView = nil
+			doc.Accessors[0].Count = 1 << 62
+		}},
+		{"invalid buffer", func(doc *document) { doc.BufferViews[0].Buffer = 1 }},
+		{"invalid type", func(doc *document) { doc.Accessors[0].Type = "VEC5" }},
+	}
+	for _, test := range tests {
+		doc := valid()
+		test.modify(doc)
+		assert.NotPanics(t, func() {
+			_, _, err = readFloats(doc, buffers, 0)
+			assert.NotNil(t, err, test.name)
+			doc.Accessors[0].Type = "SCALAR"
+			_, err = readIndices(doc, buffers, 0, 8)
+			assert.NotNil(t, err, test.name)
+		}, test.name)
+	}
+
+	// Random accessors never make the readers panic.
+	r := rand.New(rand.NewSource(1))
+	values := []int{-1 << 62, -100, -1, 0, 1, 2, 3, 4, 12, 31, 32, 33, 1 << 62}
+	pick := func() int { return values[r.Intn(len(values))] }
+	for i := 0; i < 10000; i++ {
+		doc := &document{
+			BufferViews: []bufferView{{
+				Buffer: r.Intn(2), ByteOffset: pick(), ByteLength: pick(), ByteStride: pick(),
+			}},
+			Accessors: []accessor{{
+				BufferView: intp(r.Intn(2)), ByteOffset: pick(),
+				ComponentType: componentUnsignedShort, Count: pick(), Type: "SCALAR",
+			}},
+		}
+		assert.NotPanics(t, func() {
+			readFloats(doc, buffers, 0)
+			readIndices(doc, buffers, 0, 8)
+		})
+	}
+}
+
+// buildGLB creates a GLB file from its chunks.
+func buildGLB(json, bin []byte) []byte {
+	pad := func(b []byte, c byte) []byte {
+		for len(b)%4 != 0 {
+			b = append(b, c)
+		}
+		return b
+	}
+	json = pad(json, ' ')
+	bin = pad(bin, 0)
+
+	length := 12 + 8 + len(json)
+	if bin != nil {
+		length += 8 + len(bin)
+	}
+
+	data := buildBuffer(uint32(glbMagic), uint32(2), uint32(length),
+		uint32(len(json)), uint32(glbChunkJSON), json)
+	if bin != nil {
+		data = append(data, buildBuffer(uint32(len(bin)), uint32(glbChunkBIN), bin)...)
+	}
+	return data
+}
+
+func TestParseGLB(t *testing.T) {
+	glb := buildGLB([]byte(`{}`), []byte{1, 2, 3, 4})
+	assert.True(t, isGLB(glb))
+	assert.False(t, isGLB([]byte(`{}`)))
+
+	json, bin, err := parseGLB(glb)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte(`{}  `), json)
+	assert.Equal(t, []byte{1, 2, 3, 4}, bin)
+
+	json, bin, err = parseGLB(buildGLB([]byte(`{}`), nil))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte(`{}  `), json)
+	assert.Nil(t, bin)
+
+	_, _, err = parseGLB(glb[:len(glb)-1])
+	assert.NotNil(t, err)
+}
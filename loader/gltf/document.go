@@ -0,0 +1,102 @@
+package gltf
+
+// The subset of the glTF 2.0 JSON schema the loader understands.
+// https://github.com/KhronosGroup/glTF/tree/master/specification/2.0
+
+type document struct {
+	Asset       asset          `json:"asset"`
+	Scene       *int           `json:"scene"`
+	Scenes      []scene        `json:"scenes"`
+	Nodes       []node         `json:"nodes"`
+	Meshes      []mesh         `json:"meshes"`
+	Materials   []gltfMaterial `json:"materials"`
+	Cameras     []camera       `json:"cameras"`
+	Accessors   []accessor     `json:"accessors"`
+	BufferViews []bufferView   `json:"bufferViews"`
+	Buffers     []buffer       `json:"buffers"`
+}
+
+type asset struct {
+	Version    string `json:"version"`
+	MinVersion string `json:"minVersion"`
+}
+
+type scene struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type node struct {
+	Name        string       `json:"name"`
+	Children    []int        `json:"children"`
+	Mesh        *int         `json:"mesh"`
+	Camera      *int         `json:"camera"`
+	Matrix      *[16]float32 `json:"matrix"`
+	Translation *[3]float32  `json:"translation"`
+	Rotation    *[4]float32  `json:"rotation"`
+	Scale       *[3]float32  `json:"scale"`
+}
+
+type mesh struct {
+	Name       string      `json:"name"`
+	Primitives []primitive `json:"primitives"`
+}
+
+type primitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *int           `json:"mode"`
+}
+
+type gltfMaterial struct {
+	Name                 string                `json:"name"`
+	PBRMetallicRoughness *pbrMetallicRoughness `json:"pbrMetallicRoughness"`
+}
+
+type pbrMetallicRoughness struct {
+	BaseColorFactor *[4]float32 `json:"baseColorFactor"`
+}
+
+type camera struct {
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	Perspective  *perspective  `json:"perspective"`
+	Orthographic *orthographic `json:"orthographic"`
+}
+
+type perspective struct {
+	AspectRatio float32 `json:"aspectRatio"`
+	YFov        float32 `json:"yfov"`
+	ZNear       float32 `json:"znear"`
+	ZFar        float32 `json:"zfar"`
+}
+
+type orthographic struct {
+	XMag  float32 `json:"xmag"`
+	YMag  float32 `json:"ymag"`
+	ZNear float32 `json:"znear"`
+	ZFar  float32 `json:"zfar"`
+}
+
+type accessor struct {
+	BufferView    *int        `json:"bufferView"`
+	ByteOffset    int         `json:"byteOffset"`
+	ComponentType int         `json:"componentType"`
+	Normalized    bool        `json:"normalized"`
+	Count         int         `json:"count"`
+	Type          string      `json:"type"`
+	Sparse        interface{} `json:"sparse"`
+}
+
+type bufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type buffer struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
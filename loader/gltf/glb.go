@@ -0,0 +1,61 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	glbMagic     = 0x46546c67 // "glTF"
+	glbChunkJSON = 0x4e4f534a // "JSON"
+	glbChunkBIN  = 0x004e4942 // "BIN\0"
+)
+
+func isGLB(data []byte) bool {
+	return len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic
+}
+
+// parseGLB splits a binary glTF file into its JSON and binary chunks. The
+// binary chunk is optional.
+func parseGLB(data []byte) (json, bin []byte, err error) {
+	if len(data) < 12 {
+		return nil, nil, errors.New("gltf: truncated GLB header")
+	}
+	if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("gltf: unsupported GLB version %d", version)
+	}
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, nil, errors.New("gltf: truncated GLB file")
+	}
+
+	for offset := 12; offset < length; {
+		if offset+8 > length {
+			return nil, nil, errors.New("gltf: truncated GLB chunk header")
+		}
+		chunkLength := int(binary.LittleEndian.Uint32(data[offset:]))
+		chunkType := binary.LittleEndian.Uint32(data[offset+4:])
+		offset += 8
+		if offset+chunkLength > length {
+			return nil, nil, errors.New("gltf: truncated GLB chunk")
+		}
+
+		chunk := data[offset : offset+chunkLength]
+		switch {
+		case chunkType == glbChunkJSON && json == nil:
+			json = chunk
+		case chunkType == glbChunkBIN && bin == nil:
+			bin = chunk
+		}
+		// Unknown chunks are ignored.
+
+		offset += chunkLength
+	}
+
+	if json == nil {
+		return nil, nil, errors.New("gltf: GLB file without JSON chunk")
+	}
+
+	return json, bin, nil
+}
@@ -0,0 +1,446 @@
+// Package gltf loads glTF 2.0 files into dax scene graphs.
+//
+// Both the JSON (.gltf) and binary (.glb) flavours are supported. Meshes are
+// loaded with their positions, normals, texture coordinates, tangents and
+// colors, materials are turned into material.Color using their base color and
+// cameras into dax cameras. Textures, skins and animations are ignored.
+//
+// Loaded meshes are optimized for the GPU, see Options.NoOptimize.
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/material"
+	"github.com/dlespiau/dax/math"
+)
+
+// Scene is a glTF scene loaded as a dax node hierarchy.
+type Scene struct {
+	// Name is the name of the glTF scene.
+	Name string
+	// Root parents the root nodes of the glTF scene. Add it to a
+	// SceneGraph to display the scene.
+	Root *dax.Node
+	// Cameras are the cameras of the scene, in the order they appear in
+	// the node hierarchy. They are part of the Root hierarchy.
+	Cameras []dax.Camera
+}
+
+// Options configure how a file is loaded.
+type Options struct {
+	// Convention is the coordinate system convention the file has been
+	// authored in. glTF files are Y-up and right handed, like DaX, but
+	// some exporters don't follow the specification. Meshes and
+	// transforms are converted from Convention to the DaX convention.
+	Convention math.Convention
+	// NoOptimize disables the optimization of the loaded meshes. Meshes
+	// are optimized for the GPU vertex cache, overdraw and vertex fetches
+	// by default, see dax.Mesh.Optimize.
+	NoOptimize bool
+}
+
+// Load loads the default scene of the .gltf or .glb file filename. External
+// buffers are looked for relatively to the file directory.
+func Load(filename string, options ...Options) (*Scene, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data, filepath.Dir(filename), options)
+}
+
+// Decode loads the default scene of a .gltf or .glb file read from r. External
+// buffers are looked for relatively to dir.
+func Decode(r io.Reader, dir string, options ...Options) (*Scene, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data, dir, options)
+}
+
+type loader struct {
+	doc       *document
+	options   Options
+	buffers   [][]byte
+	meshes    [][]*dax.Mesh
+	materials []dax.Material
+	scene     *Scene
+}
+
+func decode(data []byte, dir string, options []Options) (*Scene, error) {
+	var bin []byte
+	if isGLB(data) {
+		var err error
+		if data, bin, err = parseGLB(data); err != nil {
+			return nil, err
+		}
+	}
+
+	doc := new(document)
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(doc); err != nil {
+		return nil, fmt.Errorf("gltf: %v", err)
+	}
+	if !strings.HasPrefix(doc.Asset.Version, "2.") {
+		return nil, fmt.Errorf("gltf: unsupported version %q", doc.Asset.Version)
+	}
+
+	buffers, err := loadBuffers(doc, dir, bin)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &loader{
+		doc:     doc,
+		buffers: buffers,
+		meshes:  make([][]*dax.Mesh, len(doc.Meshes)),
+		scene: &Scene{
+			Root: dax.NewNode(),
+		},
+	}
+	if len(options) > 0 {
+		l.options = options[0]
+	}
+
+	l.loadMaterials()
+	if err := l.loadScene(); err != nil {
+		return nil, err
+	}
+
+	return l.scene, nil
+}
+
+func (l *loader) loadMaterials() {
+	l.materials = make([]dax.Material, len(l.doc.Materials))
+	for i := range l.doc.Materials {
+		color := dax.Color{R: 1, G: 1, B: 1, A: 1}
+		if pbr := l.doc.Materials[i].PBRMetallicRoughness; pbr != nil && pbr.BaseColorFactor != nil {
+			f := pbr.BaseColorFactor
+			color = dax.Color{R: f[0], G: f[1], B: f[2], A: f[3]}
+		}
+		l.materials[i] = material.NewColor(&color)
+	}
+}
+
+// defaultMaterial is used by primitives without material.
+var defaultMaterial = material.NewColor(&dax.Color{R: 1, G: 1, B: 1, A: 1})
+
+func (l *loader) material(index *int) dax.Material {
+	if index == nil || *index < 0 || *index >= len(l.materials) {
+		return defaultMaterial
+	}
+	return l.materials[*index]
+}
+
+func (l *loader) loadScene() error {
+	doc := l.doc
+
+	var roots []int
+	switch {
+	case len(doc.Scenes) == 0:
+		// No scene: every node without parent is a root.
+		isChild := make([]bool, len(doc.Nodes))
+		for i := range doc.Nodes {
+			for _, child := range doc.Nodes[i].Children {
+				if child >= 0 && child < len(isChild) {
+					isChild[child] = true
+				}
+			}
+		}
+		for i := range isChild {
+			if !isChild[i] {
+				roots = append(roots, i)
+			}
+		}
+	default:
+		index := 0
+		if doc.Scene != nil {
+			index = *doc.Scene
+		}
+		if index < 0 || index >= len(doc.Scenes) {
+			return fmt.Errorf("gltf: invalid scene %d", index)
+		}
+		l.scene.Name = doc.Scenes[index].Name
+		roots = doc.Scenes[index].Nodes
+	}
+
+	visited := make([]bool, len(doc.Nodes))
+	for _, root := range roots {
+		if err := l.loadNode(l.scene.Root, root, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var modes = []dax.VertexMode{
+	dax.VertexModePoints,
+	dax.VertexModeLines,
+	dax.VertexModeLineLoop,
+	dax.VertexModeLineStrip,
+	dax.VertexModeTriangles,
+	dax.VertexModeTriangleStrip,
+	dax.VertexModeTriangleFan,
+}
+
+// glTF attribute name -> dax attribute name, sorted by glTF name so meshes
+// always have their attributes in the same order.
+var attributes = []struct {
+	gltf, dax string
+}{
+	{"COLOR_0", "color"},
+	{"NORMAL", "normal"},
+	{"POSITION", "position"},
+	{"TANGENT", "tangent"},
+	{"TEXCOORD_0", "uv"},
+}
+
+func (l *loader) loadPrimitive(p *primitive) (*dax.Mesh, error) {
+	m := dax.NewMesh()
+
+	mode := 4
+	if p.Mode != nil {
+		mode = *p.Mode
+	}
+	if mode < 0 || mode >= len(modes) {
+		return nil, fmt.Errorf("gltf: invalid primitive mode %d", mode)
+	}
+	m.SetVertexMode(modes[mode])
+
+	if _, ok := p.Attributes["POSITION"]; !ok {
+		return nil, fmt.Errorf("gltf: primitive without positions")
+	}
+
+	// All attributes have the same number of elements, the vertex count.
+	vertexCount := -1
+	for _, a := range attributes {
+		index, ok := p.Attributes[a.gltf]
+		if !ok {
+			continue
+		}
+		data, n, err := readFloats(l.doc, l.buffers, index)
+		if err != nil {
+			return nil, err
+		}
+		if vertexCount == -1 {
+			vertexCount = len(data) / n
+		} else if len(data)/n != vertexCount {
+			return nil, fmt.Errorf("gltf: attribute %s has %d elements, expected %d",
+				a.gltf, len(data)/n, vertexCount)
+		}
+		m.AddAttribute(a.dax, data, n)
+	}
+
+	var indices []uint
+	if p.Indices != nil {
+		var err error
+		if indices, err = readIndices(l.doc, l.buffers, *p.Indices, vertexCount); err != nil {
+			return nil, err
+		}
+	} else {
+		// The renderer always draws indexed geometry.
+		indices = make([]uint, vertexCount)
+		for i := range indices {
+			indices[i] = uint(i)
+		}
+	}
+	m.AddIndices(indices)
+
+	return m, nil
+}
+
+func (l *loader) mesh(index int) ([]*dax.Mesh, error) {
+	if index < 0 || index >= len(l.doc.Meshes) {
+		return nil, fmt.Errorf("gltf: invalid mesh %d", index)
+	}
+
+	// Meshes are shared between the nodes using them.
+	if l.meshes[index] != nil {
+		return l.meshes[index], nil
+	}
+
+	primitives := l.doc.Meshes[index].Primitives
+	meshes := make([]*dax.Mesh, len(primitives))
+	for i := range primitives {
+		m, err := l.loadPrimitive(&primitives[i])
+		if err != nil {
+			return nil, fmt.Errorf("gltf: mesh %d: %v", index, err)
+		}
+		m.ConvertConvention(l.options.Convention, math.YUpRightHanded)
+		if !l.options.NoOptimize {
+			m.Optimize()
+		}
+		meshes[i] = m
+	}
+	l.meshes[index] = meshes
+
+	return meshes, nil
+}
+
+func (l *loader) camera(index int) (dax.Camera, error) {
+	if index < 0 || index >= len(l.doc.Cameras) {
+		return nil, fmt.Errorf("gltf: invalid camera %d", index)
+	}
+
+	c := &l.doc.Cameras[index]
+	switch {
+	case c.Type == "perspective" && c.Perspective != nil:
+		p := c.Perspective
+		far := p.ZFar
+		if far == 0 {
+			// Infinite far plane, use a distant one instead.
+			far = p.ZNear * 1e5
+		}
+		camera := dax.NewPerspectiveCamera(p.YFov, 1, p.ZNear, far)
+		// Unspecified, the aspect ratio is the one of the viewport.
+		camera.SetAspect(p.AspectRatio)
+		return camera, nil
+	case c.Type == "orthographic" && c.Orthographic != nil:
+		o := c.Orthographic
+		return dax.NewOrthographicCamera(-o.XMag, o.XMag, -o.YMag, o.YMag, o.ZNear, o.ZFar), nil
+	}
+
+	return nil, fmt.Errorf("gltf: invalid camera %d of type %q", index, c.Type)
+}
+
+func setMatrix(n *dax.Node, m *math.Mat4) {
+	translation, rotation, scale := m.Decompose()
+	n.SetPositionV(&translation)
+	n.SetRotation(&rotation)
+	n.SetScaleV(&scale)
+}
+
+func (l *loader) setTransform(n *dax.Node, gn *node) {
+	if l.options.Convention != math.YUpRightHanded {
+		local := localMatrix(gn)
+		m := math.ConvertTransform(&local, l.options.Convention, math.YUpRightHanded)
+		setMatrix(n, &m)
+		return
+	}
+
+	if gn.Matrix != nil {
+		m := math.Mat4(*gn.Matrix)
+		setMatrix(n, &m)
+		return
+	}
+
+	if t := gn.Translation; t != nil {
+		n.SetPosition(t[0], t[1], t[2])
+	}
+	if r := gn.Rotation; r != nil {
+		n.SetRotation(&math.Quaternion{W: r[3], V: math.Vec3{r[0], r[1], r[2]}})
+	}
+	if s := gn.Scale; s != nil {
+		n.SetScale(s[0], s[1], s[2])
+	}
+}
+
+// localMatrix returns the local transform of gn.
+func localMatrix(gn *node) math.Mat4 {
+	if gn.Matrix != nil {
+		return math.Mat4(*gn.Matrix)
+	}
+
+	m := math.Ident4()
+	if t := gn.Translation; t != nil {
+		m = math.Translate3D(t[0], t[1], t[2])
+	}
+	if r := gn.Rotation; r != nil {
+		q := math.Quaternion{W: r[3], V: math.Vec3{r[0], r[1], r[2]}}
+		rotation := q.Mat4()
+		m.Mul4With(&rotation)
+	}
+	if s := gn.Scale; s != nil {
+		scale := math.Scale3D(s[0], s[1], s[2])
+		m.Mul4With(&scale)
+	}
+	return m
+}
+
+// cameraFrame returns the rotation keeping converted cameras looking down their
+// -Z axis, with Y up. Converting a camera transform also converts its local
+// axes: the camera would otherwise end up looking along the converted -Z axis
+// of the original convention.
+func (l *loader) cameraFrame() math.Quaternion {
+	c := math.ConventionMatrix(l.options.Convention, math.YUpRightHanded)
+	if math.ConventionMirrors(l.options.Convention, math.YUpRightHanded) {
+		// Keep forward and up, flip right to get a rotation.
+		flip := math.Mat3{-1, 0, 0, 0, 1, 0, 0, 0, 1}
+		c = c.Mul3(&flip)
+	}
+	m := c.Mat4()
+	return math.Mat4ToQuat(&m)
+}
+
+func (l *loader) loadNode(parent *dax.Node, index int, visited []bool) error {
+	if index < 0 || index >= len(l.doc.Nodes) {
+		return fmt.Errorf("gltf: invalid node %d", index)
+	}
+	if visited[index] {
+		return fmt.Errorf("gltf: node %d has several parents", index)
+	}
+	visited[index] = true
+	gn := &l.doc.Nodes[index]
+
+	// Cameras are nodes themselves.
+	var n *dax.Node
+	if gn.Camera != nil {
+		c, err := l.camera(*gn.Camera)
+		if err != nil {
+			return err
+		}
+		l.scene.Cameras = append(l.scene.Cameras, c)
+		n = c.AsNode()
+
+		if l.options.Convention != math.YUpRightHanded {
+			// The camera needs its own frame, under a node carrying
+			// the converted transform and the children.
+			frame := l.cameraFrame()
+			n.SetRotation(&frame)
+			holder := dax.NewNode()
+			holder.AddChild(n)
+			n = holder
+		}
+	} else {
+		n = dax.NewNode()
+	}
+	n.SetName(gn.Name)
+	l.setTransform(n, gn)
+	parent.AddChild(n)
+
+	if gn.Mesh != nil {
+		meshes, err := l.mesh(*gn.Mesh)
+		if err != nil {
+			return err
+		}
+
+		// A node can only have one MeshRenderer, each primitive past the
+		// first one gets its own child node.
+		primitives := l.doc.Meshes[*gn.Mesh].Primitives
+		for i, m := range meshes {
+			mr := dax.NewMeshRenderer(m, l.material(primitives[i].Material))
+			if i == 0 {
+				n.AddComponent(mr)
+				continue
+			}
+			n.AddChild(dax.NewNode().AddComponent(mr))
+		}
+	}
+
+	for _, child := range gn.Children {
+		if err := l.loadNode(n, child, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
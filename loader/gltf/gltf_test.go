@@ -0,0 +1,215 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// A triangle, used twice, and a perspective camera:
+//
+//	parent (translated by 1 on X)
+//	  ├ child (scaled by 2, matrix)
+//	  └ camera (rotated)
+var triangle = buildBuffer(
+	[]float32{0, 0, 0, 1, 0, 0, 0, 1, 0},
+	[]uint16{0, 1, 2, 0},
+)
+
+const triangleDocument = `{
+	"asset": { "version": "2.0" },
+	"scene": 0,
+	"scenes": [ { "name": "test", "nodes": [ 0 ] } ],
+	"nodes": [
+		{ "name": "parent", "mesh": 0, "translation": [ 1, 0, 0 ], "children": [ 1, 2 ] },
+		{ "name": "child", "mesh": 0, "matrix": [ 2,0,0,0, 0,2,0,0, 0,0,2,0, 0,0,3,1 ] },
+		{ "name": "camera", "camera": 0, "rotation": [ 0, 0.7071068, 0, 0.7071068 ] }
+	],
+	"meshes": [ { "primitives": [ { "attributes": { "POSITION": 0 }, "indices": 1, "material": 0 } ] } ],
+	"materials": [ { "pbrMetallicRoughness": { "baseColorFactor": [ 1, 0, 0, 1 ] } } ],
+	"cameras": [ { "type": "perspective", "perspective": { "yfov": 1, "znear": 0.1, "zfar": 100 } } ],
+	"accessors": [
+		{ "bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3" },
+		{ "bufferView": 1, "componentType": 5123, "count": 3, "type": "SCALAR" }
+	],
+	"bufferViews": [
+		{ "buffer": 0, "byteLength": 36 },
+		{ "buffer": 0, "byteOffset": 36, "byteLength": 6 }
+	],
+	"buffers": [ { %s"byteLength": 42 } ]
+}`
+
+func checkTriangleScene(t *testing.T, scene *Scene) {
+	assert.Equal(t, "test", scene.Name)
+
+	roots := scene.Root.GetChildren()
+	assert.Equal(t, 1, len(roots))
+	parent := roots[0].(*dax.Node)
+	assert.Equal(t, &math.Vec3{1, 0, 0}, parent.GetPosition())
+
+	children := parent.GetChildren()
+	assert.Equal(t, 2, len(children))
+
+	child := children[0].(*dax.Node)
+	assert.True(t, child.GetPosition().EqualThreshold(&math.Vec3{0, 0, 3}, 1e-6))
+	assert.True(t, child.GetScale().EqualThreshold(&math.Vec3{2, 2, 2}, 1e-6))
+
+	assert.Equal(t, "child", child.Name())
+	assert.Equal(t, child, scene.Root.FindByPath("parent/child"))
+
+	assert.Equal(t, 1, len(scene.Cameras))
+	camera := scene.Cameras[0].AsNode()
+	assert.Equal(t, children[1], camera)
+	assert.Equal(t, camera, scene.Root.Find("camera"))
+	expected := math.QuatRotate(math.Pi/2, &math.Vec3{0, 1, 0})
+	assert.True(t, camera.GetRotation().OrientationEqualThreshold(&expected, 1e-4))
+}
+
+func TestDecode(t *testing.T) {
+	uri := fmt.Sprintf(`"uri": "data:application/octet-stream;base64,%s", `,
+		base64.StdEncoding.EncodeToString(triangle))
+	doc := fmt.Sprintf(triangleDocument, uri)
+
+	scene, err := Decode(bytes.NewReader([]byte(doc)), "")
+	assert.Nil(t, err)
+	checkTriangleScene(t, scene)
+}
+
+func TestDecodeGLB(t *testing.T) {
+	glb := buildGLB([]byte(fmt.Sprintf(triangleDocument, "")), triangle)
+
+	scene, err := Decode(bytes.NewReader(glb), "")
+	assert.Nil(t, err)
+	checkTriangleScene(t, scene)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`{ "asset": { "version": "1.0" } }`,
+		`{ "asset": { "version": "2.0" }, "scene": 1, "scenes": [ { "nodes": [] } ] }`,
+		`{ "asset": { "version": "2.0" }, "scenes": [ { "nodes": [ 1 ] } ], "nodes": [ {} ] }`,
+		`{ "asset": { "version": "2.0" }, "nodes": [ { "mesh": 0 } ] }`,
+		`{ "asset": { "version": "2.0" }, "nodes": [ { "camera": 0 } ] }`,
+	}
+
+	for _, test := range tests {
+		_, err := Decode(bytes.NewReader([]byte(test)), "")
+		assert.NotNil(t, err, test)
+	}
+}
+
+func TestDecodeConvention(t *testing.T) {
+	glb := buildGLB([]byte(fmt.Sprintf(triangleDocument, "")), triangle)
+
+	scene, err := Decode(bytes.NewReader(glb), "", Options{Convention: math.ZUpRightHanded})
+	assert.Nil(t, err)
+
+	parent := scene.Root.GetChildren()[0].(*dax.Node)
+	assert.True(t, parent.GetPosition().EqualThreshold(&math.Vec3{1, 0, 0}, 1e-3))
+
+	// Up is now Y.
+	children := parent.GetChildren()
+	child := children[0].(*dax.Node)
+	assert.True(t, child.GetPosition().EqualThreshold(&math.Vec3{0, 3, 0}, 1e-3))
+	assert.True(t, child.GetScale().EqualThreshold(&math.Vec3{2, 2, 2}, 1e-3))
+
+	// The camera still looks down its -Z axis, with its Y axis up, both
+	// converted to the new convention.
+	camera := scene.Cameras[0].AsNode()
+	holder := children[1].(*dax.Node)
+	assert.Equal(t, holder, camera.GetParent())
+	world := holder.GetTransform().Mul4(camera.GetTransform())
+
+	q := math.QuatRotate(math.Pi/2, &math.Vec3{0, 1, 0})
+	r := q.Mat4()
+	c := math.ConventionMatrix(math.ZUpRightHanded, math.YUpRightHanded)
+	for _, axis := range []math.Vec3{{0, 0, -1}, {0, 1, 0}} {
+		original := r.Mul4x1(&math.Vec4{axis[0], axis[1], axis[2], 0})
+		original3 := original.Vec3()
+		expected := c.Mul3x1(&original3)
+		got := world.Mul4x1(&math.Vec4{axis[0], axis[1], axis[2], 0})
+		got3 := got.Vec3()
+		assert.True(t, got3.EqualThreshold(&expected, 1e-3), "axis %v: expected %v, got %v", axis, expected, got3)
+	}
+}
+
+func TestLoadOptimize(t *testing.T) {
+	// 4 vertices, the first one unused.
+	data := buildBuffer(
+		[]float32{9, 9, 9, 0, 0, 0, 1, 0, 0, 0, 1, 0},
+		[]uint16{1, 2, 3, 0},
+	)
+	doc := &document{
+		Meshes: []mesh{
+			{Primitives: []primitive{{Attributes: map[string]int{"POSITION": 0}, Indices: intp(1)}}},
+		},
+		Accessors: []accessor{
+			{BufferView: intp(0), ComponentType: componentFloat, Count: 4, Type: "VEC3"},
+			{BufferView: intp(1), ComponentType: componentUnsignedShort, Count: 3, Type: "SCALAR"},
+		},
+		BufferViews: []bufferView{
+			{Buffer: 0, ByteLength: 48},
+			{Buffer: 0, ByteOffset: 48, ByteLength: 6},
+		},
+	}
+
+	load := func(options Options) *dax.Mesh {
+		l := &loader{
+			doc:     doc,
+			options: options,
+			buffers: [][]byte{data},
+			meshes:  make([][]*dax.Mesh, 1),
+		}
+		meshes, err := l.mesh(0)
+		assert.Nil(t, err)
+		return meshes[0]
+	}
+
+	// Optimizing removes the unused vertex.
+	m := load(Options{})
+	assert.Equal(t, 3, m.GetAttribute("position").Len())
+	assert.Equal(t, []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}, m.GetAttribute("position").Data)
+
+	m = load(Options{NoOptimize: true})
+	assert.Equal(t, 4, m.GetAttribute("position").Len())
+}
+
+func TestLoadPrimitiveVertexCount(t *testing.T) {
+	data := buildBuffer(
+		[]float32{0, 0, 0, 1, 0, 0, 0, 1, 0},
+		[]float32{0, 0, 1, 0},
+		[]uint16{0, 1, 3},
+	)
+	doc := &document{
+		Accessors: []accessor{
+			{BufferView: intp(0), ComponentType: componentFloat, Count: 3, Type: "VEC3"},
+			{BufferView: intp(1), ComponentType: componentFloat, Count: 2, Type: "VEC2"},
+			{BufferView: intp(2), ComponentType: componentUnsignedShort, Count: 3, Type: "SCALAR"},
+		},
+		BufferViews: []bufferView{
+			{Buffer: 0, ByteLength: 36},
+			{Buffer: 0, ByteOffset: 36, ByteLength: 16},
+			{Buffer: 0, ByteOffset: 52, ByteLength: 6},
+		},
+	}
+	l := &loader{doc: doc, buffers: [][]byte{data}}
+
+	// Attributes with different vertex counts.
+	_, err := l.loadPrimitive(&primitive{Attributes: map[string]int{"POSITION": 0, "TEXCOORD_0": 1}})
+	assert.NotNil(t, err)
+
+	// Index 3 is out of range.
+	_, err = l.loadPrimitive(&primitive{Attributes: map[string]int{"POSITION": 0}, Indices: intp(2)})
+	assert.NotNil(t, err)
+
+	// Without indices, one per vertex.
+	m, err := l.loadPrimitive(&primitive{Attributes: map[string]int{"POSITION": 0}})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, m.GetAttribute("position").Len())
+}
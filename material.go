@@ -10,6 +10,8 @@ type Material interface {
 	GetBlending() *Blending
 	// GetDepthTest returns the depth test state of the Material.
 	GetDepthTest() *DepthTest
+	// SetUniform sets the value of a uniform of the fragment shader.
+	SetUniform(name string, value interface{})
 }
 
 // BlendingMode is the blending mode of a Material.
@@ -77,6 +79,18 @@ type DepthTest struct {
 	Enabled bool
 	Write   bool
 	Func    DepthTestFunc
+	// Logarithmic makes the material write a logarithmic depth, for scenes
+	// with extreme depth ranges when reversed-Z isn't available. The
+	// fragment shader is compiled with DAX_LOG_DEPTH defined and is
+	// expected to write gl_FragDepth, as the built-in materials do:
+	//
+	//	uniform float logDepthCoef;
+	//	in float logDepthW;
+	//	...
+	//	#ifdef DAX_LOG_DEPTH
+	//	gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+	//	#endif
+	Logarithmic bool
 }
 
 // BaseMaterial holds the common material state and can be used to implement
@@ -84,6 +98,8 @@ type DepthTest struct {
 type BaseMaterial struct {
 	Blending  Blending
 	DepthTest DepthTest
+
+	uniforms uniformValues
 }
 
 // ID is part of the Material interface.
@@ -95,9 +111,14 @@ func (m *BaseMaterial) ID() string {
 func (m *BaseMaterial) GetFragmentShader() *FragmentShader {
 	return NewFragmentShader(`
 #version 330
+uniform float logDepthCoef;
+in float logDepthW;
 out vec4 outputColor;
 void main() {
     outputColor = vec4(1,1,1,1);
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
 }`)
 }
 
@@ -111,4 +132,35 @@ func (m *BaseMaterial) GetDepthTest() *DepthTest {
 	return &m.DepthTest
 }
 
+// SetUniform is part of the Material interface. value can be a float32, an
+// int, a math.Vec2, math.Vec3, math.Vec4, math.Mat3, math.Mat4, a Color or a
+// *Texture. The value is uploaded before drawing with the material, only when
+// it has changed since the last draw. SetUniform panics if the type of value
+// isn't supported.
+func (m *BaseMaterial) SetUniform(name string, value interface{}) {
+	m.uniforms.set(name, value)
+}
+
+// GetUniform returns the value of a uniform set with SetUniform, or nil if it
+// hasn't been set. Colors are returned as math.Vec4 and ints as int32.
+func (m *BaseMaterial) GetUniform(name string) interface{} {
+	return m.uniforms.get(name)
+}
+
+func (m *BaseMaterial) uniformValues() *uniformValues {
+	return &m.uniforms
+}
+
+// materialUniforms returns the uniform values of m, nil for materials not
+// embedding BaseMaterial.
+func materialUniforms(m Material) *uniformValues {
+	u, ok := m.(interface {
+		uniformValues() *uniformValues
+	})
+	if !ok {
+		return nil
+	}
+	return u.uniformValues()
+}
+
 var _ Material = &BaseMaterial{}
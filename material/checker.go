@@ -0,0 +1,50 @@
+package material
+
+import "github.com/dlespiau/dax"
+
+// Checker is a 3D checkerboard of two colors, in world space. Surfaces show
+// the slice of the cells they cut through, so meshes don't need texture
+// coordinates. Meshes drawn with a Checker material need normals.
+type Checker struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &Checker{}
+
+// NewChecker creates a new Checker material alternating color1 and color2 in
+// cubic cells of size world units.
+func NewChecker(color1, color2 *dax.Color, size float32) *Checker {
+	m := &Checker{}
+
+	m.shader = newProceduralShader("DAX_PROCEDURAL_CHECKER")
+	m.shader.AddUniform(dax.VariableKindVec4, "color1")
+	m.shader.AddUniform(dax.VariableKindVec4, "color2")
+	m.shader.AddUniform(dax.VariableKindFloat, "scale")
+
+	m.SetColors(color1, color2)
+	m.SetSize(size)
+
+	return m
+}
+
+// SetColors sets the colors of the cells.
+func (m *Checker) SetColors(color1, color2 *dax.Color) {
+	m.SetUniform("color1", *color1)
+	m.SetUniform("color2", *color2)
+}
+
+// SetSize sets the size of the cells, in world units.
+func (m *Checker) SetSize(size float32) {
+	m.SetUniform("scale", size)
+}
+
+// ID is part of the Material interface.
+func (m *Checker) ID() string {
+	return "-dax-material-checker"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Checker) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
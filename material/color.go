@@ -5,24 +5,36 @@ import "github.com/dlespiau/dax"
 // Color is the simplest material possible, just a color.
 type Color struct {
 	dax.BaseMaterial
-	color dax.Color
+	color  dax.Color
+	shader *dax.FragmentShader
 }
 
 var _ dax.Material = &Color{}
 
 // NewColor creates a new Color material.
 func NewColor(color *dax.Color) *Color {
-	return &Color{
+	m := &Color{
 		color: *color,
 	}
+
+	m.shader = dax.NewFragmentShader(colorFragmentShader)
+	m.shader.AddUniform(dax.VariableKindVec4, "color")
+	m.SetUniform("color", m.color)
+
+	return m
 }
 
 const colorFragmentShader = `
 #version 330
 uniform vec4 color;
+uniform float logDepthCoef;
+in float logDepthW;
 out vec4 outputColor;
 void main() {
     outputColor = color;
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
 }`
 
 // ID is part of the Material interface.
@@ -32,8 +44,5 @@ func (m *Color) ID() string {
 
 // GetFragmentShader is part of the Material interface.
 func (m *Color) GetFragmentShader() *dax.FragmentShader {
-	s := dax.NewFragmentShader(colorFragmentShader)
-	s.AddUniform(dax.VariableKindVec4, "color")
-
-	return s
+	return m.shader
 }
@@ -0,0 +1,92 @@
+package material
+
+import "github.com/dlespiau/dax"
+
+// DebugMode is what a Debug material shows.
+type DebugMode int
+
+const (
+	// DebugNormals shows the world space normals, the x, y and z
+	// components mapped from [-1, 1] to red, green and blue.
+	DebugNormals DebugMode = iota
+	// DebugViewNormals shows the view space normals: surfaces facing the
+	// camera are blue.
+	DebugViewNormals
+	// DebugUV shows the texture coordinates, u in red and v in green, with
+	// a checkerboard of 8x8 cells to reveal stretching and seams.
+	DebugUV
+)
+
+// Debug visualizes mesh attributes, eg. to check the normals or texture
+// coordinates of a model. Back faces are darkened.
+type Debug struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &Debug{}
+
+const debugFragmentShader = `
+#version 330
+uniform mat4 view;
+uniform int mode;
+uniform float logDepthCoef;
+in vec3 worldNormal;
+in vec2 texCoord;
+in float logDepthW;
+out vec4 outputColor;
+
+#define DAX_DEBUG_NORMALS 0
+#define DAX_DEBUG_VIEW_NORMALS 1
+#define DAX_DEBUG_UV 2
+
+void main() {
+    vec3 n = normalize(worldNormal);
+    vec3 color;
+
+    if (mode == DAX_DEBUG_VIEW_NORMALS) {
+        color = normalize(mat3(view) * n) * 0.5 + 0.5;
+    } else if (mode == DAX_DEBUG_UV) {
+        vec2 cell = floor(texCoord * 8.0);
+        float checker = mod(cell.x + cell.y, 2.0);
+        color = vec3(fract(texCoord), 0.0) * (0.75 + 0.25 * checker);
+    } else {
+        color = n * 0.5 + 0.5;
+    }
+
+    if (!gl_FrontFacing) {
+        color *= 0.3;
+    }
+
+    outputColor = vec4(color, 1.0);
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
+}`
+
+// NewDebug creates a new Debug material showing mode.
+func NewDebug(mode DebugMode) *Debug {
+	m := &Debug{}
+
+	m.shader = dax.NewFragmentShader(debugFragmentShader)
+	m.shader.AddUniform(dax.VariableKindInt, "mode")
+
+	m.SetMode(mode)
+
+	return m
+}
+
+// SetMode sets what the material shows.
+func (m *Debug) SetMode(mode DebugMode) {
+	m.SetUniform("mode", int(mode))
+}
+
+// ID is part of the Material interface.
+func (m *Debug) ID() string {
+	return "-dax-material-debug"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Debug) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
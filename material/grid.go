@@ -0,0 +1,63 @@
+package material
+
+import "github.com/dlespiau/dax"
+
+// Grid draws anti-aliased lines of constant width, in pixels, at regular
+// intervals in world space, eg. to show the scale of a model. Surfaces are
+// projected on the plane of the axes they face the most. Meshes drawn with a
+// Grid material need normals.
+type Grid struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &Grid{}
+
+// NewGrid creates a new Grid material with lines every spacing world units,
+// one pixel wide.
+func NewGrid(background, line *dax.Color, spacing float32) *Grid {
+	m := &Grid{}
+
+	m.shader = newProceduralShader("DAX_PROCEDURAL_GRID")
+	m.shader.AddUniform(dax.VariableKindVec4, "background")
+	m.shader.AddUniform(dax.VariableKindVec4, "lineColor")
+	m.shader.AddUniform(dax.VariableKindFloat, "lineWidth")
+	m.shader.AddUniform(dax.VariableKindFloat, "scale")
+
+	m.SetBackground(background)
+	m.SetLineColor(line)
+	m.SetLineWidth(1)
+	m.SetSpacing(spacing)
+
+	return m
+}
+
+// SetBackground sets the color between the lines.
+func (m *Grid) SetBackground(c *dax.Color) {
+	m.SetUniform("background", *c)
+}
+
+// SetLineColor sets the color of the lines.
+func (m *Grid) SetLineColor(c *dax.Color) {
+	m.SetUniform("lineColor", *c)
+}
+
+// SetLineWidth sets the width of the lines, in pixels.
+func (m *Grid) SetLineWidth(width float32) {
+	m.SetUniform("lineWidth", width)
+}
+
+// SetSpacing sets the distance between two lines, in world units.
+func (m *Grid) SetSpacing(spacing float32) {
+	m.SetUniform("scale", spacing)
+}
+
+// ID is part of the Material interface.
+func (m *Grid) ID() string {
+	return "-dax-material-grid"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Grid) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
@@ -0,0 +1,88 @@
+package material
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Matcap (material capture) shades surfaces from their orientation relative
+// to the camera only. It ignores the scene lights, making it a good default to
+// inspect the shape of models. Meshes drawn with a Matcap material need
+// normals.
+//
+// A matcap either samples a sphere map, an image of a lit sphere, with the
+// view space normal, or computes a studio setup moving with the camera: a
+// soft key light, a highlight and a rim light.
+type Matcap struct {
+	dax.BaseMaterial
+	shader   *dax.FragmentShader
+	textured bool
+}
+
+var _ dax.Material = &Matcap{}
+
+// NewMatcap creates a new Matcap material of the given color, lit by a studio
+// setup with a faint white rim light.
+func NewMatcap(color *dax.Color) *Matcap {
+	m := &Matcap{}
+
+	m.shader = newProceduralShader("DAX_PROCEDURAL_MATCAP")
+	m.shader.AddUniform(dax.VariableKindVec4, "color")
+	m.shader.AddUniform(dax.VariableKindVec3, "rimColor")
+
+	m.SetColor(color)
+	m.SetUniform("rimColor", math.Vec3{.2, .2, .2})
+
+	return m
+}
+
+// NewMatcapTexture creates a new Matcap material sampling texture, a sphere
+// map: the surfaces facing the camera get the center of the texture and the
+// silhouettes its edges.
+func NewMatcapTexture(texture *dax.Texture) *Matcap {
+	m := &Matcap{
+		textured: true,
+	}
+
+	m.shader = newProceduralShader("DAX_PROCEDURAL_MATCAP", "DAX_MATCAP_TEXTURE")
+	m.shader.AddUniform(dax.VariableKindVec4, "color")
+	m.shader.AddUniform(dax.VariableKindTexture, "matcap")
+
+	m.SetColor(&dax.Color{R: 1, G: 1, B: 1, A: 1})
+	m.SetTexture(texture)
+
+	return m
+}
+
+// SetColor sets the color of the material. Textured matcaps are multiplied by
+// it.
+func (m *Matcap) SetColor(c *dax.Color) {
+	m.SetUniform("color", *c)
+}
+
+// SetRimColor sets the color of the light grazing the silhouettes. It has no
+// effect on textured matcaps.
+func (m *Matcap) SetRimColor(c *dax.Color) {
+	m.SetUniform("rimColor", math.Vec3{c.R, c.G, c.B})
+}
+
+// SetTexture sets the sphere map of a matcap created with NewMatcapTexture.
+func (m *Matcap) SetTexture(texture *dax.Texture) {
+	if !m.textured {
+		panic("matcap: not a textured matcap")
+	}
+	m.SetUniform("matcap", texture)
+}
+
+// ID is part of the Material interface.
+func (m *Matcap) ID() string {
+	if m.textured {
+		return "-dax-material-matcap-texture"
+	}
+	return "-dax-material-matcap"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Matcap) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
@@ -0,0 +1,62 @@
+package material
+
+import "github.com/dlespiau/dax"
+
+// Noise blends two colors with 3D fractal noise in world space, eg. for
+// terrain or marble-like placeholders. Meshes drawn with a Noise material
+// need normals.
+type Noise struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &Noise{}
+
+// NewNoise creates a new Noise material blending color1 and color2 with 4
+// octaves of noise, the largest features being scale world units wide.
+func NewNoise(color1, color2 *dax.Color, scale float32) *Noise {
+	m := &Noise{}
+
+	m.shader = newProceduralShader("DAX_PROCEDURAL_NOISE")
+	m.shader.AddUniform(dax.VariableKindVec4, "color1")
+	m.shader.AddUniform(dax.VariableKindVec4, "color2")
+	m.shader.AddUniform(dax.VariableKindFloat, "scale")
+	m.shader.AddUniform(dax.VariableKindInt, "octaves")
+
+	m.SetColors(color1, color2)
+	m.SetScale(scale)
+	m.SetOctaves(4)
+
+	return m
+}
+
+// SetColors sets the colors blended by the noise, color1 where the noise is
+// 0 and color2 where it's 1.
+func (m *Noise) SetColors(color1, color2 *dax.Color) {
+	m.SetUniform("color1", *color1)
+	m.SetUniform("color2", *color2)
+}
+
+// SetScale sets the size of the largest features, in world units.
+func (m *Noise) SetScale(scale float32) {
+	m.SetUniform("scale", scale)
+}
+
+// SetOctaves sets the number of layers of noise summed, each adding details
+// half the size of the previous one. It's at least 1.
+func (m *Noise) SetOctaves(octaves int) {
+	if octaves < 1 {
+		octaves = 1
+	}
+	m.SetUniform("octaves", octaves)
+}
+
+// ID is part of the Material interface.
+func (m *Noise) ID() string {
+	return "-dax-material-noise"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Noise) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
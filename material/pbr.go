@@ -0,0 +1,138 @@
+package material
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// PBR is a physically based material lit by the scene lights, following the
+// metallic-roughness model of glTF: a GGX microfacet specular term and a
+// Lambertian diffuse term. Meshes drawn with a PBR material need normals.
+type PBR struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &PBR{}
+
+const pbrFragmentShader = `
+#version 330
+` + dax.LightsShaderChunk + `
+uniform vec4 baseColor;
+uniform float metallic;
+uniform float roughness;
+uniform vec3 ambient;
+uniform float logDepthCoef;
+in float logDepthW;
+out vec4 outputColor;
+
+const float PI = 3.14159265359;
+
+float distributionGGX(float nDotH, float alpha) {
+    float a2 = alpha * alpha;
+    float d = nDotH * nDotH * (a2 - 1.0) + 1.0;
+    return a2 / (PI * d * d);
+}
+
+float visibilitySmithGGX(float nDotL, float nDotV, float alpha) {
+    float a2 = alpha * alpha;
+    float ggxL = nDotV * sqrt(nDotL * nDotL * (1.0 - a2) + a2);
+    float ggxV = nDotL * sqrt(nDotV * nDotV * (1.0 - a2) + a2);
+    return 0.5 / max(ggxL + ggxV, 1e-5);
+}
+
+vec3 fresnelSchlick(float vDotH, vec3 f0) {
+    return f0 + (1.0 - f0) * pow(1.0 - vDotH, 5.0);
+}
+
+void main() {
+    vec3 n = normalize(worldNormal);
+    if (!gl_FrontFacing) {
+        n = -n;
+    }
+    vec3 v = normalize(cameraPosition - worldPosition);
+    float nDotV = max(dot(n, v), 1e-4);
+
+    float alpha = roughness * roughness;
+    vec3 f0 = mix(vec3(0.04), baseColor.rgb, metallic);
+    vec3 diffuseColor = baseColor.rgb * (1.0 - metallic);
+
+    vec3 color = ambient * baseColor.rgb;
+    for (int i = 0; i < lightCount; i++) {
+        vec3 l;
+        float attenuation = daxLight(lights[i], worldPosition, l);
+        float nDotL = max(dot(n, l), 0.0);
+        if (nDotL <= 0.0) {
+            continue;
+        }
+
+        vec3 h = normalize(l + v);
+        float nDotH = max(dot(n, h), 0.0);
+        float vDotH = max(dot(v, h), 0.0);
+
+        vec3 f = fresnelSchlick(vDotH, f0);
+        vec3 specular = f * distributionGGX(nDotH, alpha) * visibilitySmithGGX(nDotL, nDotV, alpha);
+        vec3 diffuse = (1.0 - f) * diffuseColor / PI;
+
+        color += lights[i].color * attenuation * nDotL * (diffuse + specular);
+    }
+
+    outputColor = vec4(color, baseColor.a);
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
+}`
+
+// NewPBR creates a new PBR material. metallic and roughness are in [0, 1].
+func NewPBR(baseColor *dax.Color, metallic, roughness float32) *PBR {
+	m := &PBR{}
+
+	m.shader = dax.NewFragmentShader(pbrFragmentShader)
+	m.shader.AddUniform(dax.VariableKindVec4, "baseColor")
+	m.shader.AddUniform(dax.VariableKindFloat, "metallic")
+	m.shader.AddUniform(dax.VariableKindFloat, "roughness")
+	m.shader.AddUniform(dax.VariableKindVec3, "ambient")
+
+	m.SetUniform("baseColor", *baseColor)
+	m.SetUniform("metallic", math.Clamp(metallic, 0, 1))
+	m.SetUniform("roughness", clampRoughness(roughness))
+	m.SetUniform("ambient", math.Vec3{.03, .03, .03})
+
+	return m
+}
+
+// A roughness of 0 gives an infinitely thin highlight.
+func clampRoughness(roughness float32) float32 {
+	return math.Clamp(roughness, .03, 1)
+}
+
+// SetBaseColor sets the albedo of dielectrics or the specular color of
+// metals.
+func (m *PBR) SetBaseColor(c *dax.Color) {
+	m.SetUniform("baseColor", *c)
+}
+
+// SetMetallic sets how metallic the material is, in [0, 1].
+func (m *PBR) SetMetallic(metallic float32) {
+	m.SetUniform("metallic", math.Clamp(metallic, 0, 1))
+}
+
+// SetRoughness sets the roughness of the surface, in [0, 1].
+func (m *PBR) SetRoughness(roughness float32) {
+	m.SetUniform("roughness", clampRoughness(roughness))
+}
+
+// SetAmbient sets the light reaching the material from all directions.
+func (m *PBR) SetAmbient(c *dax.Color) {
+	m.SetUniform("ambient", math.Vec3{c.R, c.G, c.B})
+}
+
+// ID is part of the Material interface.
+func (m *PBR) ID() string {
+	return "-dax-material-pbr"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *PBR) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
@@ -0,0 +1,98 @@
+package material
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Phong is a material lit by the scene lights with the Blinn-Phong reflection
+// model. Meshes drawn with a Phong material need normals.
+type Phong struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &Phong{}
+
+const phongFragmentShader = `
+#version 330
+` + dax.LightsShaderChunk + `
+uniform vec4 diffuse;
+uniform vec3 specular;
+uniform float shininess;
+uniform vec3 ambient;
+uniform float logDepthCoef;
+in float logDepthW;
+out vec4 outputColor;
+void main() {
+    vec3 n = normalize(worldNormal);
+    if (!gl_FrontFacing) {
+        n = -n;
+    }
+    vec3 v = normalize(cameraPosition - worldPosition);
+
+    vec3 color = ambient * diffuse.rgb;
+    for (int i = 0; i < lightCount; i++) {
+        vec3 l;
+        float attenuation = daxLight(lights[i], worldPosition, l);
+        float nDotL = max(dot(n, l), 0.0);
+        vec3 h = normalize(l + v);
+        float s = nDotL > 0.0 ? pow(max(dot(n, h), 0.0), shininess) : 0.0;
+        color += lights[i].color * attenuation * (diffuse.rgb * nDotL + specular * s);
+    }
+
+    outputColor = vec4(color, diffuse.a);
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
+}`
+
+// NewPhong creates a new Phong material with a diffuse color, a white specular
+// color, a shininess of 32 and a faint ambient light.
+func NewPhong(diffuse *dax.Color) *Phong {
+	m := &Phong{}
+
+	m.shader = dax.NewFragmentShader(phongFragmentShader)
+	m.shader.AddUniform(dax.VariableKindVec4, "diffuse")
+	m.shader.AddUniform(dax.VariableKindVec3, "specular")
+	m.shader.AddUniform(dax.VariableKindFloat, "shininess")
+	m.shader.AddUniform(dax.VariableKindVec3, "ambient")
+
+	m.SetUniform("diffuse", *diffuse)
+	m.SetUniform("specular", math.Vec3{1, 1, 1})
+	m.SetUniform("shininess", float32(32))
+	m.SetUniform("ambient", math.Vec3{.05, .05, .05})
+
+	return m
+}
+
+// SetDiffuse sets the color of the material.
+func (m *Phong) SetDiffuse(c *dax.Color) {
+	m.SetUniform("diffuse", *c)
+}
+
+// SetSpecular sets the color of the highlights.
+func (m *Phong) SetSpecular(c *dax.Color) {
+	m.SetUniform("specular", math.Vec3{c.R, c.G, c.B})
+}
+
+// SetShininess sets the Phong exponent: the higher, the smaller and sharper
+// the highlights.
+func (m *Phong) SetShininess(shininess float32) {
+	m.SetUniform("shininess", shininess)
+}
+
+// SetAmbient sets the light reaching the material from all directions.
+func (m *Phong) SetAmbient(c *dax.Color) {
+	m.SetUniform("ambient", math.Vec3{c.R, c.G, c.B})
+}
+
+// ID is part of the Material interface.
+func (m *Phong) ID() string {
+	return "-dax-material-phong"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Phong) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
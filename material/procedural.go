@@ -0,0 +1,176 @@
+package material
+
+import (
+	"github.com/dlespiau/dax"
+)
+
+// proceduralFragmentShader is shared by the procedural materials (Checker,
+// Grid, Noise, Matcap and Triplanar), each compiling it with the macro
+// selecting its pattern defined. It's also meant as a starting point for
+// custom shaders: patterns are computed in world space from the position and
+// normal given by the dax vertex shader, so meshes don't need texture
+// coordinates. The vertex shader also gives texCoord, the texture coordinates
+// of the mesh, and the renderer sets the view matrix of the camera, view.
+//
+// Except for Matcap, patterns are shaded by a light at the camera position so
+// shapes stay readable in scenes without lights.
+const proceduralFragmentShader = `
+#version 330
+uniform vec3 cameraPosition;
+uniform float logDepthCoef;
+in vec3 worldPosition;
+in vec3 worldNormal;
+in float logDepthW;
+out vec4 outputColor;
+
+#if defined(DAX_PROCEDURAL_CHECKER) || defined(DAX_PROCEDURAL_NOISE)
+uniform vec4 color1;
+uniform vec4 color2;
+#endif
+
+#if defined(DAX_PROCEDURAL_CHECKER) || defined(DAX_PROCEDURAL_GRID) || defined(DAX_PROCEDURAL_NOISE) || defined(DAX_PROCEDURAL_TRIPLANAR)
+// scale is the size, in world units, of a checker cell, a grid cell, a noise
+// feature or a texture repeat.
+uniform float scale;
+#endif
+
+#ifdef DAX_PROCEDURAL_GRID
+uniform vec4 background;
+uniform vec4 lineColor;
+// lineWidth is in pixels.
+uniform float lineWidth;
+#endif
+
+#ifdef DAX_PROCEDURAL_NOISE
+uniform int octaves;
+#endif
+
+#ifdef DAX_PROCEDURAL_MATCAP
+uniform mat4 view;
+uniform vec4 color;
+#ifdef DAX_MATCAP_TEXTURE
+uniform sampler2D matcap;
+#else
+uniform vec3 rimColor;
+#endif
+#endif
+
+#ifdef DAX_PROCEDURAL_TRIPLANAR
+uniform sampler2D map;
+uniform vec4 tint;
+uniform float sharpness;
+#endif
+
+// headlight returns the light reaching a surface of normal n from a light at
+// the camera, v being the direction to the camera.
+float headlight(vec3 n, vec3 v) {
+    return 0.3 + 0.7 * max(dot(n, v), 0.0);
+}
+
+// triplanarWeights returns the contribution of the projections along the x,
+// y and z axes to a surface of normal n. The higher sharpness, the narrower
+// the transitions between projections.
+vec3 triplanarWeights(vec3 n, float sharpness) {
+    vec3 w = pow(abs(n), vec3(sharpness));
+    return w / (w.x + w.y + w.z);
+}
+
+#ifdef DAX_PROCEDURAL_GRID
+// gridLines returns the coverage of the lines at integer coordinates of p,
+// anti-aliased over a pixel.
+float gridLines(vec2 p) {
+    vec2 d = abs(fract(p - 0.5) - 0.5) / fwidth(p);
+    return clamp(lineWidth * 0.5 - min(d.x, d.y) + 0.5, 0.0, 1.0);
+}
+#endif
+
+#ifdef DAX_PROCEDURAL_NOISE
+float hash(vec3 p) {
+    return fract(sin(dot(p, vec3(127.1, 311.7, 74.7))) * 43758.5453);
+}
+
+// valueNoise interpolates random values at integer coordinates, in [0, 1].
+float valueNoise(vec3 p) {
+    vec3 i = floor(p);
+    vec3 f = fract(p);
+    f = f * f * (3.0 - 2.0 * f);
+
+    return mix(
+        mix(mix(hash(i + vec3(0, 0, 0)), hash(i + vec3(1, 0, 0)), f.x),
+            mix(hash(i + vec3(0, 1, 0)), hash(i + vec3(1, 1, 0)), f.x), f.y),
+        mix(mix(hash(i + vec3(0, 0, 1)), hash(i + vec3(1, 0, 1)), f.x),
+            mix(hash(i + vec3(0, 1, 1)), hash(i + vec3(1, 1, 1)), f.x), f.y),
+        f.z);
+}
+
+// fbm sums octaves of noise, each with twice the frequency and half the
+// amplitude of the previous one, in [0, 1].
+float fbm(vec3 p) {
+    float sum = 0.0;
+    float amplitude = 0.5;
+    float total = 0.0;
+    for (int i = 0; i < octaves; i++) {
+        sum += valueNoise(p) * amplitude;
+        total += amplitude;
+        p *= 2.0;
+        amplitude *= 0.5;
+    }
+    return sum / max(total, 1e-4);
+}
+#endif
+
+void main() {
+    vec3 n = normalize(worldNormal);
+    if (!gl_FrontFacing) {
+        n = -n;
+    }
+    vec3 v = normalize(cameraPosition - worldPosition);
+
+#if defined(DAX_PROCEDURAL_CHECKER)
+    // Move inside the surface so faces lying on cell boundaries don't
+    // flicker between cells.
+    vec3 cell = floor((worldPosition - n * 1e-3 * scale) / scale);
+    vec4 c = mod(cell.x + cell.y + cell.z, 2.0) < 0.5 ? color1 : color2;
+    outputColor = vec4(c.rgb * headlight(n, v), c.a);
+#elif defined(DAX_PROCEDURAL_GRID)
+    vec3 p = worldPosition / scale;
+    vec3 w = triplanarWeights(n, 8.0);
+    float line = gridLines(p.yz) * w.x + gridLines(p.xz) * w.y + gridLines(p.xy) * w.z;
+    vec4 c = mix(background, lineColor, line);
+    outputColor = vec4(c.rgb * headlight(n, v), c.a);
+#elif defined(DAX_PROCEDURAL_NOISE)
+    vec4 c = mix(color1, color2, fbm(worldPosition / scale));
+    outputColor = vec4(c.rgb * headlight(n, v), c.a);
+#elif defined(DAX_PROCEDURAL_MATCAP)
+    // Matcaps are looked up with the view space normal: x and y address a
+    // sphere map and z is how much the surface faces the camera.
+    vec3 vn = normalize(mat3(view) * n);
+#ifdef DAX_MATCAP_TEXTURE
+    outputColor = texture(matcap, vn.xy * 0.5 + 0.5) * color;
+#else
+    float facing = max(vn.z, 0.0);
+    float diffuse = 0.25 + 0.75 * smoothstep(-1.0, 1.0, 0.4 * vn.y + 0.8 * facing);
+    float highlight = pow(max(1.0 - length(vn.xy - vec2(-0.35, 0.45)), 0.0), 8.0);
+    float rim = pow(1.0 - facing, 3.0);
+    outputColor = vec4(color.rgb * diffuse + vec3(highlight) + rimColor * rim, color.a);
+#endif
+#elif defined(DAX_PROCEDURAL_TRIPLANAR)
+    vec3 p = worldPosition / scale;
+    vec3 w = triplanarWeights(n, sharpness);
+    vec4 c = texture(map, p.yz) * w.x + texture(map, p.xz) * w.y + texture(map, p.xy) * w.z;
+    c *= tint;
+    outputColor = vec4(c.rgb * headlight(n, v), c.a);
+#else
+    outputColor = vec4(1.0, 0.0, 1.0, 1.0);
+#endif
+
+#ifdef DAX_LOG_DEPTH
+    gl_FragDepth = log2(logDepthW) * logDepthCoef * 0.5;
+#endif
+}`
+
+// newProceduralShader returns the procedural fragment shader with the pattern,
+// and its options, selected by defines.
+func newProceduralShader(defines ...string) *dax.FragmentShader {
+	return dax.NewFragmentShaderVariant(proceduralFragmentShader, defines...)
+}
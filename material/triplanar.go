@@ -0,0 +1,66 @@
+package material
+
+import "github.com/dlespiau/dax"
+
+// Triplanar maps a texture on surfaces by projecting it along the x, y and z
+// axes, in world space, and blending the projections according to the
+// surface normal. Meshes don't need texture coordinates, which makes it handy
+// for terrains and level blockouts. Meshes drawn with a Triplanar material
+// need normals.
+type Triplanar struct {
+	dax.BaseMaterial
+	shader *dax.FragmentShader
+}
+
+var _ dax.Material = &Triplanar{}
+
+// NewTriplanar creates a new Triplanar material repeating texture every
+// scale world units.
+func NewTriplanar(texture *dax.Texture, scale float32) *Triplanar {
+	m := &Triplanar{}
+
+	m.shader = newProceduralShader("DAX_PROCEDURAL_TRIPLANAR")
+	m.shader.AddUniform(dax.VariableKindTexture, "map")
+	m.shader.AddUniform(dax.VariableKindFloat, "scale")
+	m.shader.AddUniform(dax.VariableKindFloat, "sharpness")
+	m.shader.AddUniform(dax.VariableKindVec4, "tint")
+
+	m.SetTexture(texture)
+	m.SetScale(scale)
+	m.SetSharpness(4)
+	m.SetTint(&dax.Color{R: 1, G: 1, B: 1, A: 1})
+
+	return m
+}
+
+// SetTexture sets the projected texture.
+func (m *Triplanar) SetTexture(texture *dax.Texture) {
+	m.SetUniform("map", texture)
+}
+
+// SetScale sets the size of a repeat of the texture, in world units.
+func (m *Triplanar) SetScale(scale float32) {
+	m.SetUniform("scale", scale)
+}
+
+// SetSharpness sets how narrow the transitions between projections are. 1
+// blends them linearly with the normal, higher values favor the projection
+// the surface faces the most.
+func (m *Triplanar) SetSharpness(sharpness float32) {
+	m.SetUniform("sharpness", sharpness)
+}
+
+// SetTint sets the color the texture is multiplied by.
+func (m *Triplanar) SetTint(c *dax.Color) {
+	m.SetUniform("tint", *c)
+}
+
+// ID is part of the Material interface.
+func (m *Triplanar) ID() string {
+	return "-dax-material-triplanar"
+}
+
+// GetFragmentShader is part of the Material interface.
+func (m *Triplanar) GetFragmentShader() *dax.FragmentShader {
+	return m.shader
+}
@@ -0,0 +1,104 @@
+package math
+
+// Batch versions of the matrix operations, for the hot loops of scene graphs
+// and mesh processing. They work on contiguous slices and keep the constant
+// operand in locals rather than reloading it for each element.
+
+// Mul4Batch computes dst[i] = a[i] * b for every matrix of a. dst must be at
+// least as long as a. It may be a itself.
+func Mul4Batch(dst, a []Mat4, b *Mat4) {
+	dst = dst[:len(a)]
+
+	b0, b1, b2, b3 := b[0], b[1], b[2], b[3]
+	b4, b5, b6, b7 := b[4], b[5], b[6], b[7]
+	b8, b9, b10, b11 := b[8], b[9], b[10], b[11]
+	b12, b13, b14, b15 := b[12], b[13], b[14], b[15]
+
+	for i := range a {
+		m := a[i]
+		d := &dst[i]
+		d[0] = m[0]*b0 + m[4]*b1 + m[8]*b2 + m[12]*b3
+		d[1] = m[1]*b0 + m[5]*b1 + m[9]*b2 + m[13]*b3
+		d[2] = m[2]*b0 + m[6]*b1 + m[10]*b2 + m[14]*b3
+		d[3] = m[3]*b0 + m[7]*b1 + m[11]*b2 + m[15]*b3
+		d[4] = m[0]*b4 + m[4]*b5 + m[8]*b6 + m[12]*b7
+		d[5] = m[1]*b4 + m[5]*b5 + m[9]*b6 + m[13]*b7
+		d[6] = m[2]*b4 + m[6]*b5 + m[10]*b6 + m[14]*b7
+		d[7] = m[3]*b4 + m[7]*b5 + m[11]*b6 + m[15]*b7
+		d[8] = m[0]*b8 + m[4]*b9 + m[8]*b10 + m[12]*b11
+		d[9] = m[1]*b8 + m[5]*b9 + m[9]*b10 + m[13]*b11
+		d[10] = m[2]*b8 + m[6]*b9 + m[10]*b10 + m[14]*b11
+		d[11] = m[3]*b8 + m[7]*b9 + m[11]*b10 + m[15]*b11
+		d[12] = m[0]*b12 + m[4]*b13 + m[8]*b14 + m[12]*b15
+		d[13] = m[1]*b12 + m[5]*b13 + m[9]*b14 + m[13]*b15
+		d[14] = m[2]*b12 + m[6]*b13 + m[10]*b14 + m[14]*b15
+		d[15] = m[3]*b12 + m[7]*b13 + m[11]*b14 + m[15]*b15
+	}
+}
+
+// PreMul4Batch computes dst[i] = a * b[i] for every matrix of b, eg. the world
+// transforms of the children of a node, a being the world transform of the
+// node and b the local transforms of its children. dst must be at least as
+// long as b. It may be b itself.
+func PreMul4Batch(dst []Mat4, a *Mat4, b []Mat4) {
+	dst = dst[:len(b)]
+
+	a0, a1, a2, a3 := a[0], a[1], a[2], a[3]
+	a4, a5, a6, a7 := a[4], a[5], a[6], a[7]
+	a8, a9, a10, a11 := a[8], a[9], a[10], a[11]
+	a12, a13, a14, a15 := a[12], a[13], a[14], a[15]
+
+	for i := range b {
+		m := b[i]
+		d := &dst[i]
+		for c := 0; c < 16; c += 4 {
+			x, y, z, w := m[c], m[c+1], m[c+2], m[c+3]
+			d[c] = a0*x + a4*y + a8*z + a12*w
+			d[c+1] = a1*x + a5*y + a9*z + a13*w
+			d[c+2] = a2*x + a6*y + a10*z + a14*w
+			d[c+3] = a3*x + a7*y + a11*z + a15*w
+		}
+	}
+}
+
+// TransformPoints transforms the points of src by m into dst. m is expected to
+// be an affine transformation, eg. a world transform: the points are
+// multiplied as (x, y, z, 1) and the w coordinate of the result is dropped.
+// dst must be at least as long as src. It may be src itself.
+func TransformPoints(dst, src []Vec3, m *Mat4) {
+	dst = dst[:len(src)]
+
+	m0, m1, m2 := m[0], m[1], m[2]
+	m4, m5, m6 := m[4], m[5], m[6]
+	m8, m9, m10 := m[8], m[9], m[10]
+	m12, m13, m14 := m[12], m[13], m[14]
+
+	for i := range src {
+		x, y, z := src[i][0], src[i][1], src[i][2]
+		dst[i] = Vec3{
+			m0*x + m4*y + m8*z + m12,
+			m1*x + m5*y + m9*z + m13,
+			m2*x + m6*y + m10*z + m14,
+		}
+	}
+}
+
+// TransformDirections transforms the directions of src by m into dst,
+// ignoring the translation of m. dst must be at least as long as src. It may
+// be src itself.
+func TransformDirections(dst, src []Vec3, m *Mat4) {
+	dst = dst[:len(src)]
+
+	m0, m1, m2 := m[0], m[1], m[2]
+	m4, m5, m6 := m[4], m[5], m[6]
+	m8, m9, m10 := m[8], m[9], m[10]
+
+	for i := range src {
+		x, y, z := src[i][0], src[i][1], src[i][2]
+		dst[i] = Vec3{
+			m0*x + m4*y + m8*z,
+			m1*x + m5*y + m9*z,
+			m2*x + m6*y + m10*z,
+		}
+	}
+}
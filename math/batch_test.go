@@ -0,0 +1,145 @@
+package math
+
+import (
+	"testing"
+)
+
+func testMatrices(n int) []Mat4 {
+	matrices := make([]Mat4, n)
+	for i := range matrices {
+		f := float32(i)
+		translation := Translate3D(f, 2*f, -f)
+		matrices[i] = translation.Mul4(&Mat4{
+			1, f, 0, 0,
+			0, 1, 0, 0,
+			f, 0, 2, 0,
+			0, 0, 0, 1,
+		})
+	}
+	return matrices
+}
+
+func vec3Near(v1, v2 *Vec3) bool {
+	for i := range v1 {
+		if Abs(v1[i]-v2[i]) > 1e-5 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMul4Batch(t *testing.T) {
+	t.Parallel()
+	a := testMatrices(5)
+	rotation := HomogRotate3DY(.3)
+	b := rotation.Mul4(&Mat4{2, 0, 0, 0, 0, 3, 0, 0, 0, 0, 4, 0, 1, 2, 3, 1})
+
+	dst := make([]Mat4, len(a))
+	Mul4Batch(dst, a, &b)
+	for i := range a {
+		if expected := a[i].Mul4(&b); !dst[i].EqualThreshold(&expected, 1e-5) {
+			t.Errorf("Mul4Batch[%d]: expected %v, got %v", i, expected, dst[i])
+		}
+	}
+
+	// In place.
+	Mul4Batch(a, a, &b)
+	for i := range a {
+		if a[i] != dst[i] {
+			t.Errorf("in place Mul4Batch[%d]: expected %v, got %v", i, dst[i], a[i])
+		}
+	}
+}
+
+func TestPreMul4Batch(t *testing.T) {
+	t.Parallel()
+	rotation := HomogRotate3DX(-.7)
+	a := rotation.Mul4(&Mat4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 5, 6, 7, 1})
+	b := testMatrices(5)
+
+	dst := make([]Mat4, len(b))
+	PreMul4Batch(dst, &a, b)
+	for i := range b {
+		if expected := a.Mul4(&b[i]); !dst[i].EqualThreshold(&expected, 1e-5) {
+			t.Errorf("PreMul4Batch[%d]: expected %v, got %v", i, expected, dst[i])
+		}
+	}
+}
+
+func TestTransformPoints(t *testing.T) {
+	t.Parallel()
+	translation := Translate3D(1, 2, 3)
+	m := translation.Mul4(&Mat4{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 1})
+	src := []Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 2, 3}}
+
+	points := make([]Vec3, len(src))
+	directions := make([]Vec3, len(src))
+	TransformPoints(points, src, &m)
+	TransformDirections(directions, src, &m)
+
+	for i, p := range src {
+		p4 := m.Mul4x1(&Vec4{p[0], p[1], p[2], 1})
+		if expected := p4.Vec3(); !vec3Near(&points[i], &expected) {
+			t.Errorf("TransformPoints[%d]: expected %v, got %v", i, expected, points[i])
+		}
+		d4 := m.Mul4x1(&Vec4{p[0], p[1], p[2], 0})
+		if expected := d4.Vec3(); !vec3Near(&directions[i], &expected) {
+			t.Errorf("TransformDirections[%d]: expected %v, got %v", i, expected, directions[i])
+		}
+	}
+
+	// In place.
+	TransformPoints(src, src, &m)
+	for i := range src {
+		if src[i] != points[i] {
+			t.Errorf("in place TransformPoints[%d]: expected %v, got %v", i, points[i], src[i])
+		}
+	}
+}
+
+func TestMul4BatchShortDst(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Error("Mul4Batch didn't panic with a short dst")
+		}
+	}()
+	a := testMatrices(3)
+	id := Ident4()
+	Mul4Batch(make([]Mat4, 2), a, &id)
+}
+
+func BenchmarkMul4Batch(b *testing.B) {
+	a := testMatrices(1024)
+	dst := make([]Mat4, len(a))
+	m := HomogRotate3DY(.3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Mul4Batch(dst, a, &m)
+	}
+}
+
+func BenchmarkMul4Loop(b *testing.B) {
+	a := testMatrices(1024)
+	dst := make([]Mat4, len(a))
+	m := HomogRotate3DY(.3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range a {
+			dst[j] = a[j].Mul4(&m)
+		}
+	}
+}
+
+func BenchmarkTransformPoints(b *testing.B) {
+	src := make([]Vec3, 4096)
+	for i := range src {
+		src[i] = Vec3{float32(i), 1, -float32(i)}
+	}
+	dst := make([]Vec3, len(src))
+	m := HomogRotate3DY(.3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		TransformPoints(dst, src, &m)
+	}
+}
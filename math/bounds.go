@@ -0,0 +1,226 @@
+package math
+
+// AABB is an axis aligned bounding box. A box with Min greater than Max on any
+// axis is empty: use EmptyAABB to start growing a box from points.
+type AABB struct {
+	Min Vec3
+	Max Vec3
+}
+
+// EmptyAABB returns an empty box, the neutral element of Merge and
+// ExtendPoint.
+func EmptyAABB() AABB {
+	return AABB{
+		Min: Vec3{MaxFloat32, MaxFloat32, MaxFloat32},
+		Max: Vec3{-MaxFloat32, -MaxFloat32, -MaxFloat32},
+	}
+}
+
+// IsEmpty returns true if the box doesn't contain any point.
+func (b *AABB) IsEmpty() bool {
+	return b.Min[0] > b.Max[0] || b.Min[1] > b.Max[1] || b.Min[2] > b.Max[2]
+}
+
+// Center returns the center of the box.
+func (b *AABB) Center() Vec3 {
+	return Vec3{
+		(b.Min[0] + b.Max[0]) / 2,
+		(b.Min[1] + b.Max[1]) / 2,
+		(b.Min[2] + b.Max[2]) / 2,
+	}
+}
+
+// Size returns the size of the box along each axis.
+func (b *AABB) Size() Vec3 {
+	return b.Max.Sub(&b.Min)
+}
+
+// Corner returns one of the 8 corners of the box. Bits 0, 1 and 2 of i select
+// Max rather than Min on the X, Y and Z axis respectively.
+func (b *AABB) Corner(i int) Vec3 {
+	c := b.Min
+	if i&1 != 0 {
+		c[0] = b.Max[0]
+	}
+	if i&2 != 0 {
+		c[1] = b.Max[1]
+	}
+	if i&4 != 0 {
+		c[2] = b.Max[2]
+	}
+	return c
+}
+
+// ExtendPoint grows the box to include p.
+func (b *AABB) ExtendPoint(p *Vec3) {
+	for i := 0; i < 3; i++ {
+		b.Min[i] = Min(b.Min[i], p[i])
+		b.Max[i] = Max(b.Max[i], p[i])
+	}
+}
+
+// ContainsPoint returns true if p is inside the box, boundaries included.
+func (b *AABB) ContainsPoint(p *Vec3) bool {
+	return p[0] >= b.Min[0] && p[0] <= b.Max[0] &&
+		p[1] >= b.Min[1] && p[1] <= b.Max[1] &&
+		p[2] >= b.Min[2] && p[2] <= b.Max[2]
+}
+
+// IntersectsAABB returns true if the two boxes overlap. Touching boxes
+// intersect.
+func (b *AABB) IntersectsAABB(b2 *AABB) bool {
+	return b.Min[0] <= b2.Max[0] && b.Max[0] >= b2.Min[0] &&
+		b.Min[1] <= b2.Max[1] && b.Max[1] >= b2.Min[1] &&
+		b.Min[2] <= b2.Max[2] && b.Max[2] >= b2.Min[2]
+}
+
+// Merge returns the smallest box containing both boxes.
+func (b *AABB) Merge(b2 *AABB) AABB {
+	return AABB{
+		Min: Vec3{Min(b.Min[0], b2.Min[0]), Min(b.Min[1], b2.Min[1]), Min(b.Min[2], b2.Min[2])},
+		Max: Vec3{Max(b.Max[0], b2.Max[0]), Max(b.Max[1], b2.Max[1]), Max(b.Max[2], b2.Max[2])},
+	}
+}
+
+// Transform returns the axis aligned box containing the box transformed by m.
+// m is expected to be an affine transformation.
+func (b *AABB) Transform(m *Mat4) AABB {
+	if b.IsEmpty() {
+		return *b
+	}
+
+	// Arvo's method: start from the translation and, for each element of
+	// the 3x3 part of m, add the smallest and largest contributions of the
+	// box.
+	r := AABB{
+		Min: Vec3{m[12], m[13], m[14]},
+		Max: Vec3{m[12], m[13], m[14]},
+	}
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			e := m[col*4+row]
+			a, c := e*b.Min[col], e*b.Max[col]
+			if a > c {
+				a, c = c, a
+			}
+			r.Min[row] += a
+			r.Max[row] += c
+		}
+	}
+
+	return r
+}
+
+// BoundingSphere returns the sphere circumscribing the box.
+func (b *AABB) BoundingSphere() BoundingSphere {
+	if b.IsEmpty() {
+		return BoundingSphere{Radius: -1}
+	}
+	size := b.Size()
+	return BoundingSphere{
+		Center: b.Center(),
+		Radius: size.Len() / 2,
+	}
+}
+
+// BoundingSphere is a sphere enclosing an object. A sphere with a negative
+// radius is empty.
+type BoundingSphere struct {
+	Center Vec3
+	Radius float32
+}
+
+// IsEmpty returns true if the sphere doesn't contain any point.
+func (s *BoundingSphere) IsEmpty() bool {
+	return s.Radius < 0
+}
+
+// ContainsPoint returns true if p is inside the sphere, boundary included.
+func (s *BoundingSphere) ContainsPoint(p *Vec3) bool {
+	d := p.Sub(&s.Center)
+	return d.Len2() <= s.Radius*s.Radius
+}
+
+// IntersectsAABB returns true if the sphere and the box overlap.
+func (s *BoundingSphere) IntersectsAABB(b *AABB) bool {
+	if s.IsEmpty() || b.IsEmpty() {
+		return false
+	}
+
+	// Distance from the center to the closest point of the box.
+	var d2 float32
+	for i := 0; i < 3; i++ {
+		c := Clamp(s.Center[i], b.Min[i], b.Max[i])
+		d := s.Center[i] - c
+		d2 += d * d
+	}
+	return d2 <= s.Radius*s.Radius
+}
+
+// IntersectsSphere returns true if the two spheres overlap.
+func (s *BoundingSphere) IntersectsSphere(s2 *BoundingSphere) bool {
+	if s.IsEmpty() || s2.IsEmpty() {
+		return false
+	}
+	d := s2.Center.Sub(&s.Center)
+	r := s.Radius + s2.Radius
+	return d.Len2() <= r*r
+}
+
+// Merge returns the smallest sphere containing both spheres.
+func (s *BoundingSphere) Merge(s2 *BoundingSphere) BoundingSphere {
+	if s2.IsEmpty() {
+		return *s
+	}
+	if s.IsEmpty() {
+		return *s2
+	}
+
+	d := s2.Center.Sub(&s.Center)
+	dist := d.Len()
+
+	// One sphere contains the other.
+	if dist+s2.Radius <= s.Radius {
+		return *s
+	}
+	if dist+s.Radius <= s2.Radius {
+		return *s2
+	}
+
+	radius := (dist + s.Radius + s2.Radius) / 2
+	center := s.Center
+	center.AddScaledVec((radius-s.Radius)/dist, &d)
+	return BoundingSphere{Center: center, Radius: radius}
+}
+
+// Transform returns a sphere containing the sphere transformed by m. m is
+// expected to be an affine transformation. With a non-uniform scale, the
+// radius is scaled by the largest scale factor.
+func (s *BoundingSphere) Transform(m *Mat4) BoundingSphere {
+	if s.IsEmpty() {
+		return *s
+	}
+
+	center := m.Mul4x1(&Vec4{s.Center[0], s.Center[1], s.Center[2], 1})
+	var scale2 float32
+	for col := 0; col < 3; col++ {
+		axis := Vec3{m[col*4], m[col*4+1], m[col*4+2]}
+		scale2 = Max(scale2, axis.Len2())
+	}
+	return BoundingSphere{
+		Center: center.Vec3(),
+		Radius: s.Radius * Sqrt(scale2),
+	}
+}
+
+// AABB returns the box circumscribing the sphere.
+func (s *BoundingSphere) AABB() AABB {
+	if s.IsEmpty() {
+		return EmptyAABB()
+	}
+	r := Vec3{s.Radius, s.Radius, s.Radius}
+	return AABB{
+		Min: s.Center.Sub(&r),
+		Max: s.Center.Add(&r),
+	}
+}
@@ -0,0 +1,194 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestAABBEmpty(t *testing.T) {
+	t.Parallel()
+
+	b := EmptyAABB()
+	if !b.IsEmpty() {
+		t.Errorf("EmptyAABB() isn't empty")
+	}
+	if b.ContainsPoint(&Vec3{}) {
+		t.Errorf("empty box contains the origin")
+	}
+
+	b.ExtendPoint(&Vec3{1, 2, 3})
+	if b.IsEmpty() {
+		t.Errorf("box with a point is empty")
+	}
+	if b.Min != (Vec3{1, 2, 3}) || b.Max != (Vec3{1, 2, 3}) {
+		t.Errorf("expected [(1, 2, 3), (1, 2, 3)], got %v", b)
+	}
+}
+
+func TestAABBContainsPoint(t *testing.T) {
+	t.Parallel()
+
+	b := AABB{Min: Vec3{-1, -2, -3}, Max: Vec3{1, 2, 3}}
+	tests := []struct {
+		p        Vec3
+		expected bool
+	}{
+		{Vec3{0, 0, 0}, true},
+		{Vec3{1, 2, 3}, true},
+		{Vec3{-1, 0, 3}, true},
+		{Vec3{1.1, 0, 0}, false},
+		{Vec3{0, -2.1, 0}, false},
+		{Vec3{0, 0, 4}, false},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		if got := b.ContainsPoint(&test.p); got != test.expected {
+			t.Errorf("ContainsPoint(%v): expected %v, got %v", test.p, test.expected, got)
+		}
+	}
+}
+
+func TestAABBIntersectsAABB(t *testing.T) {
+	t.Parallel()
+
+	b := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{1, 1, 1}}
+	tests := []struct {
+		b        AABB
+		expected bool
+	}{
+		{AABB{Vec3{.5, .5, .5}, Vec3{2, 2, 2}}, true},
+		{AABB{Vec3{.2, .2, .2}, Vec3{.8, .8, .8}}, true},
+		{AABB{Vec3{1, 0, 0}, Vec3{2, 1, 1}}, true},
+		{AABB{Vec3{1.1, 0, 0}, Vec3{2, 1, 1}}, false},
+		{AABB{Vec3{0, -2, 0}, Vec3{1, -.1, 1}}, false},
+		{EmptyAABB(), false},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		if got := b.IntersectsAABB(&test.b); got != test.expected {
+			t.Errorf("IntersectsAABB(%v): expected %v, got %v", test.b, test.expected, got)
+		}
+	}
+}
+
+func TestAABBMerge(t *testing.T) {
+	t.Parallel()
+
+	b1 := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{1, 1, 1}}
+	b2 := AABB{Min: Vec3{-1, .5, 2}, Max: Vec3{0, 3, 4}}
+	merged := b1.Merge(&b2)
+	expected := AABB{Min: Vec3{-1, 0, 0}, Max: Vec3{1, 3, 4}}
+	if merged != expected {
+		t.Errorf("expected %v, got %v", expected, merged)
+	}
+
+	empty := EmptyAABB()
+	if merged = b1.Merge(&empty); merged != b1 {
+		t.Errorf("merging an empty box: expected %v, got %v", b1, merged)
+	}
+}
+
+func TestAABBTransform(t *testing.T) {
+	t.Parallel()
+
+	b := AABB{Min: Vec3{-1, -1, -1}, Max: Vec3{1, 1, 1}}
+
+	// Translation and scale.
+	m := Translate3D(10, 0, 0)
+	s := Scale3D(2, 1, 1)
+	m.Mul4With(&s)
+	got := b.Transform(&m)
+	expected := AABB{Min: Vec3{8, -1, -1}, Max: Vec3{12, 1, 1}}
+	if !got.Min.EqualThreshold(&expected.Min, 1e-3) || !got.Max.EqualThreshold(&expected.Max, 1e-3) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// A 45° rotation around Y grows the box by sqrt(2) on X and Z.
+	m = HomogRotate3DY(DegToRad(45))
+	got = b.Transform(&m)
+	expected = AABB{Min: Vec3{-Sqrt(2), -1, -Sqrt(2)}, Max: Vec3{Sqrt(2), 1, Sqrt(2)}}
+	if !got.Min.EqualThreshold(&expected.Min, 1e-3) || !got.Max.EqualThreshold(&expected.Max, 1e-3) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// The transformed box contains all the transformed corners.
+	for i := 0; i < 8; i++ {
+		c := b.Corner(i)
+		p := m.Mul4x1(&Vec4{c[0], c[1], c[2], 1})
+		p3 := p.Vec3()
+		grown := AABB{Min: got.Min.Sub(&Vec3{1e-4, 1e-4, 1e-4}), Max: got.Max.Add(&Vec3{1e-4, 1e-4, 1e-4})}
+		if !grown.ContainsPoint(&p3) {
+			t.Errorf("corner %d: %v isn't in %v", i, p3, got)
+		}
+	}
+}
+
+func TestBoundingSphereContainsPoint(t *testing.T) {
+	t.Parallel()
+
+	s := BoundingSphere{Center: Vec3{1, 0, 0}, Radius: 2}
+	if !s.ContainsPoint(&Vec3{3, 0, 0}) {
+		t.Errorf("point on the boundary isn't contained")
+	}
+	if s.ContainsPoint(&Vec3{2.5, 1.5, 0}) {
+		t.Errorf("point outside is contained")
+	}
+}
+
+func TestBoundingSphereIntersectsAABB(t *testing.T) {
+	t.Parallel()
+
+	b := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{1, 1, 1}}
+	tests := []struct {
+		s        BoundingSphere
+		expected bool
+	}{
+		{BoundingSphere{Vec3{.5, .5, .5}, .1}, true},
+		{BoundingSphere{Vec3{2, .5, .5}, 1}, true},
+		{BoundingSphere{Vec3{2, .5, .5}, .9}, false},
+		// Close to the corner on each axis but too far diagonally.
+		{BoundingSphere{Vec3{1.5, 1.5, 1.5}, .6}, false},
+		{BoundingSphere{Vec3{1.5, 1.5, 1.5}, .9}, true},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		if got := test.s.IntersectsAABB(&b); got != test.expected {
+			t.Errorf("%v.IntersectsAABB(): expected %v, got %v", test.s, test.expected, got)
+		}
+	}
+}
+
+func TestBoundingSphereMerge(t *testing.T) {
+	t.Parallel()
+
+	s1 := BoundingSphere{Center: Vec3{0, 0, 0}, Radius: 1}
+	s2 := BoundingSphere{Center: Vec3{4, 0, 0}, Radius: 1}
+	merged := s1.Merge(&s2)
+	if !merged.Center.EqualThreshold(&Vec3{2, 0, 0}, 1e-3) || !FloatEqualThreshold(merged.Radius, 3, 1e-3) {
+		t.Errorf("expected center (2, 0, 0) radius 3, got %v", merged)
+	}
+
+	// s3 is inside s1.
+	s3 := BoundingSphere{Center: Vec3{.5, 0, 0}, Radius: .2}
+	if merged = s1.Merge(&s3); merged != s1 {
+		t.Errorf("expected %v, got %v", s1, merged)
+	}
+	if merged = s3.Merge(&s1); merged != s1 {
+		t.Errorf("expected %v, got %v", s1, merged)
+	}
+}
+
+func TestBoundingSphereTransform(t *testing.T) {
+	t.Parallel()
+
+	s := BoundingSphere{Center: Vec3{1, 0, 0}, Radius: 1}
+	m := Translate3D(0, 5, 0)
+	scale := Scale3D(1, 3, 2)
+	m.Mul4With(&scale)
+	got := s.Transform(&m)
+	if !got.Center.EqualThreshold(&Vec3{1, 5, 0}, 1e-3) || !FloatEqualThreshold(got.Radius, 3, 1e-3) {
+		t.Errorf("expected center (1, 5, 0) radius 3, got %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package math
+
+// Convention is a coordinate system convention: which axis points up and
+// whether the coordinate system is right or left handed. DaX, like OpenGL and
+// glTF, is Y-up and right handed.
+type Convention int
+
+// Common conventions.
+const (
+	// YUpRightHanded is used by DaX, OpenGL, glTF and Maya.
+	YUpRightHanded Convention = iota
+	// ZUpRightHanded is used by Blender, 3ds Max and most CAD tools.
+	ZUpRightHanded
+	// YUpLeftHanded is used by Unity and Direct3D.
+	YUpLeftHanded
+	// ZUpLeftHanded is used by Unreal Engine.
+	ZUpLeftHanded
+)
+
+// IsRightHanded returns true if c is a right handed coordinate system.
+func (c Convention) IsRightHanded() bool {
+	return c == YUpRightHanded || c == ZUpRightHanded
+}
+
+// toYUpRightHanded returns the matrix converting from c to YUpRightHanded.
+func (c Convention) toYUpRightHanded() Mat3 {
+	switch c {
+	case ZUpRightHanded:
+		// (x, y, z) -> (x, z, -y)
+		return Mat3{1, 0, 0, 0, 0, -1, 0, 1, 0}
+	case YUpLeftHanded:
+		// (x, y, z) -> (x, y, -z)
+		return Mat3{1, 0, 0, 0, 1, 0, 0, 0, -1}
+	case ZUpLeftHanded:
+		// (x, y, z) -> (x, z, y)
+		return Mat3{1, 0, 0, 0, 0, 1, 0, 1, 0}
+	default:
+		return Ident3()
+	}
+}
+
+// ConventionMatrix returns the matrix converting positions and directions from
+// the from convention to the to convention. Conversion matrices are
+// orthonormal: normals are transformed with the same matrix.
+func ConventionMatrix(from, to Convention) Mat3 {
+	// Go through YUpRightHanded. The matrices are orthonormal, their inverse
+	// is their transpose.
+	t := to.toYUpRightHanded()
+	m := t.Transposed()
+	f := from.toYUpRightHanded()
+	return m.Mul3(&f)
+}
+
+// ConventionMirrors returns true if converting from the from convention to the
+// to convention is a reflection, in which case the winding of triangles has to
+// be reversed to keep front faces front facing.
+func ConventionMirrors(from, to Convention) bool {
+	return from.IsRightHanded() != to.IsRightHanded()
+}
+
+// ConvertTransform converts a transform from the from convention to the to
+// convention: the result applies the same transformation, expressed in the
+// to convention. The result of converting a rotation is a rotation, even when
+// the conventions have different handedness.
+func ConvertTransform(m *Mat4, from, to Convention) Mat4 {
+	c := ConventionMatrix(from, to)
+	c4 := c.Mat4()
+	ct := c.Transposed()
+	ct4 := ct.Mat4()
+
+	r := c4.Mul4(m)
+	return r.Mul4(&ct4)
+}
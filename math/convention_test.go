@@ -0,0 +1,82 @@
+package math
+
+import (
+	"testing"
+)
+
+var conventions = []Convention{YUpRightHanded, ZUpRightHanded, YUpLeftHanded, ZUpLeftHanded}
+
+func TestConventionMatrix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from, to Convention
+		v        Vec3
+		expected Vec3
+	}{
+		// Up stays up.
+		{ZUpRightHanded, YUpRightHanded, Vec3{0, 0, 1}, Vec3{0, 1, 0}},
+		{YUpRightHanded, ZUpRightHanded, Vec3{0, 1, 0}, Vec3{0, 0, 1}},
+		{ZUpLeftHanded, YUpRightHanded, Vec3{0, 0, 1}, Vec3{0, 1, 0}},
+		{YUpLeftHanded, YUpRightHanded, Vec3{1, 2, 3}, Vec3{1, 2, -3}},
+		{ZUpRightHanded, YUpRightHanded, Vec3{1, 2, 3}, Vec3{1, 3, -2}},
+		{ZUpLeftHanded, ZUpRightHanded, Vec3{1, 2, 3}, Vec3{1, -2, 3}},
+	}
+
+	for _, test := range tests {
+		m := ConventionMatrix(test.from, test.to)
+		if r := m.Mul3x1(&test.v); !r.EqualThreshold(&test.expected, 1e-3) {
+			t.Errorf("ConventionMatrix(%v, %v) * %v: expected %v, got %v",
+				test.from, test.to, test.v, test.expected, r)
+		}
+	}
+
+	for _, from := range conventions {
+		for _, to := range conventions {
+			m := ConventionMatrix(from, to)
+			back := ConventionMatrix(to, from)
+			id := back.Mul3(&m)
+			if ident := Ident3(); !id.EqualThreshold(&ident, 1e-3) {
+				t.Errorf("converting from %v to %v and back isn't the identity (%v)", from, to, id)
+			}
+
+			det := m.Det()
+			if mirrors := ConventionMirrors(from, to); mirrors != (det < 0) {
+				t.Errorf("ConventionMirrors(%v, %v) = %v but det is %v", from, to, mirrors, det)
+			}
+		}
+	}
+}
+
+func TestConvertTransform(t *testing.T) {
+	t.Parallel()
+
+	// A rotation around the up axis, followed by a translation along it.
+	q := QuatRotate(DegToRad(30), &Vec3{0, 0, 1})
+	m := Translate3D(0, 0, 5)
+	r := q.Mat4()
+	m = m.Mul4(&r)
+
+	converted := ConvertTransform(&m, ZUpRightHanded, YUpRightHanded)
+	qy := QuatRotate(DegToRad(30), &Vec3{0, 1, 0})
+	expected := Translate3D(0, 5, 0)
+	ry := qy.Mat4()
+	expected = expected.Mul4(&ry)
+	if !converted.EqualThreshold(&expected, 1e-3) {
+		t.Errorf("expected %v, got %v", expected, converted)
+	}
+
+	// Converting points then transforming them is the same as transforming
+	// then converting.
+	p := Vec4{1, 2, 3, 1}
+	c := ConventionMatrix(ZUpLeftHanded, YUpRightHanded)
+	c4 := c.Mat4()
+	converted = ConvertTransform(&m, ZUpLeftHanded, YUpRightHanded)
+	cp := c4.Mul4x1(&p)
+	a := converted.Mul4x1(&cp)
+	mp := m.Mul4x1(&p)
+	b := c4.Mul4x1(&mp)
+	if !a.EqualThreshold(&b, 1e-3) {
+		t.Errorf("expected %v, got %v", b, a)
+	}
+}
@@ -0,0 +1,228 @@
+package math
+
+// Hermite3 evaluates the cubic Hermite curve going from p0 to p1 with tangents
+// m0 and m1 at t, t being between 0 and 1.
+func Hermite3(p0, m0, p1, m1 *Vec3, t float32) Vec3 {
+	t2 := t * t
+	t3 := t2 * t
+
+	h00 := 2*t3 - 3*t2 + 1
+	h10 := t3 - 2*t2 + t
+	h01 := -2*t3 + 3*t2
+	h11 := t3 - t2
+
+	return Vec3{
+		h00*p0[0] + h10*m0[0] + h01*p1[0] + h11*m1[0],
+		h00*p0[1] + h10*m0[1] + h01*p1[1] + h11*m1[1],
+		h00*p0[2] + h10*m0[2] + h01*p1[2] + h11*m1[2],
+	}
+}
+
+// CatmullRom3 evaluates the uniform Catmull-Rom spline segment between p1 and
+// p2 at t, t being between 0 and 1. p0 and p3 are the points before and after
+// the segment, used to compute the tangents. The curve goes through all the
+// control points.
+func CatmullRom3(p0, p1, p2, p3 *Vec3, t float32) Vec3 {
+	m1 := p2.Sub(p0)
+	m1.MulWith(0.5)
+	m2 := p3.Sub(p1)
+	m2.MulWith(0.5)
+	return Hermite3(p1, &m1, p2, &m2, t)
+}
+
+// Spline3 is a Catmull-Rom spline going through a list of points, each point
+// being associated with a time. Times don't need to be evenly spaced.
+type Spline3 struct {
+	times  []float32
+	points []Vec3
+}
+
+// AddPoint adds a point at time t to the spline. Points have to be added in
+// increasing time order.
+func (s *Spline3) AddPoint(t float32, p *Vec3) {
+	s.times = append(s.times, t)
+	s.points = append(s.points, *p)
+}
+
+// Len returns the number of points in the spline.
+func (s *Spline3) Len() int {
+	return len(s.points)
+}
+
+// Point returns the nth point of the spline and its time.
+func (s *Spline3) Point(nth int) (t float32, p Vec3) {
+	return s.times[nth], s.points[nth]
+}
+
+// Start returns the time of the first point of the spline.
+func (s *Spline3) Start() float32 {
+	if len(s.times) == 0 {
+		return 0
+	}
+	return s.times[0]
+}
+
+// End returns the time of the last point of the spline.
+func (s *Spline3) End() float32 {
+	if len(s.times) == 0 {
+		return 0
+	}
+	return s.times[len(s.times)-1]
+}
+
+// tangent returns the tangent at point i, scaled to the [0, 1] parametrization
+// of a segment of duration dt.
+func (s *Spline3) tangent(i int, dt float32) Vec3 {
+	prev, next := i-1, i+1
+	if prev < 0 {
+		prev = 0
+	}
+	if next >= len(s.points) {
+		next = len(s.points) - 1
+	}
+
+	span := s.times[next] - s.times[prev]
+	if span <= 0 {
+		return Vec3{}
+	}
+	m := s.points[next].Sub(&s.points[prev])
+	m.MulWith(dt / span)
+	return m
+}
+
+// Evaluate returns the position on the spline at time t. Times before the
+// first point or after the last one are clamped.
+func (s *Spline3) Evaluate(t float32) Vec3 {
+	n := len(s.points)
+	switch {
+	case n == 0:
+		return Vec3{}
+	case t <= s.times[0]:
+		return s.points[0]
+	case t >= s.times[n-1]:
+		return s.points[n-1]
+	}
+
+	i := 0
+	for i < n-2 && t >= s.times[i+1] {
+		i++
+	}
+
+	dt := s.times[i+1] - s.times[i]
+	if dt <= 0 {
+		return s.points[i+1]
+	}
+	m0 := s.tangent(i, dt)
+	m1 := s.tangent(i+1, dt)
+	return Hermite3(&s.points[i], &m0, &s.points[i+1], &m1, (t-s.times[i])/dt)
+}
+
+// QuatSquadControl computes the inner control point of the squad interpolation
+// at q1, from the previous and next rotations q0 and q2.
+func QuatSquadControl(q0, q1, q2 *Quaternion) Quaternion {
+	inv := q1.Inverse()
+	next := inv.Mul(q2)
+	prev := inv.Mul(q0)
+	lnext, lprev := next.Log(), prev.Log()
+
+	e := Quaternion{0, lnext.V.Add(&lprev.V)}
+	e.V.MulWith(-.25)
+	e = e.Exp()
+	return q1.Mul(&e)
+}
+
+// QuatSquad is the spherical quadrangle interpolation between q1 and q2, with
+// the inner control points s1 and s2 given by QuatSquadControl. Chaining squad
+// segments gives a smooth, C1 continuous, rotation path where chaining slerps
+// has visible changes of angular velocity at each rotation.
+func QuatSquad(q1, q2, s1, s2 *Quaternion, amount float32) Quaternion {
+	slerpQ := QuatSlerp(q1, q2, amount)
+	slerpS := QuatSlerp(s1, s2, amount)
+	return QuatSlerp(&slerpQ, &slerpS, 2*amount*(1-amount))
+}
+
+// QuatSpline is a squad interpolated path going through a list of rotations,
+// each rotation being associated with a time. Times don't need to be evenly
+// spaced but the path is smoother when they are.
+type QuatSpline struct {
+	times     []float32
+	rotations []Quaternion
+}
+
+// AddRotation adds a rotation at time t to the spline. Rotations have to be
+// added in increasing time order.
+func (s *QuatSpline) AddRotation(t float32, q *Quaternion) {
+	r := q.Normalized()
+	// q and -q are the same rotation: pick the closest to the previous
+	// rotation so the path doesn't take the long way around.
+	if n := len(s.rotations); n > 0 && s.rotations[n-1].Dot(&r) < 0 {
+		r.ScaleWith(-1)
+	}
+	s.times = append(s.times, t)
+	s.rotations = append(s.rotations, r)
+}
+
+// Len returns the number of rotations in the spline.
+func (s *QuatSpline) Len() int {
+	return len(s.rotations)
+}
+
+// Rotation returns the nth rotation of the spline and its time.
+func (s *QuatSpline) Rotation(nth int) (t float32, q Quaternion) {
+	return s.times[nth], s.rotations[nth]
+}
+
+// Start returns the time of the first rotation of the spline.
+func (s *QuatSpline) Start() float32 {
+	if len(s.times) == 0 {
+		return 0
+	}
+	return s.times[0]
+}
+
+// End returns the time of the last rotation of the spline.
+func (s *QuatSpline) End() float32 {
+	if len(s.times) == 0 {
+		return 0
+	}
+	return s.times[len(s.times)-1]
+}
+
+// control returns the squad control point at rotation i.
+func (s *QuatSpline) control(i int) Quaternion {
+	prev, next := i-1, i+1
+	if prev < 0 {
+		prev = 0
+	}
+	if next >= len(s.rotations) {
+		next = len(s.rotations) - 1
+	}
+	return QuatSquadControl(&s.rotations[prev], &s.rotations[i], &s.rotations[next])
+}
+
+// Evaluate returns the rotation on the spline at time t. Times before the
+// first rotation or after the last one are clamped.
+func (s *QuatSpline) Evaluate(t float32) Quaternion {
+	n := len(s.rotations)
+	switch {
+	case n == 0:
+		return QuatIdent()
+	case t <= s.times[0]:
+		return s.rotations[0]
+	case t >= s.times[n-1]:
+		return s.rotations[n-1]
+	}
+
+	i := 0
+	for i < n-2 && t >= s.times[i+1] {
+		i++
+	}
+
+	dt := s.times[i+1] - s.times[i]
+	if dt <= 0 {
+		return s.rotations[i+1]
+	}
+	s1 := s.control(i)
+	s2 := s.control(i + 1)
+	return QuatSquad(&s.rotations[i], &s.rotations[i+1], &s1, &s2, (t-s.times[i])/dt)
+}
@@ -0,0 +1,146 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestHermite3(t *testing.T) {
+	t.Parallel()
+	p0, p1 := Vec3{0, 0, 0}, Vec3{1, 2, 3}
+	m0, m1 := Vec3{1, 0, 0}, Vec3{0, 1, 0}
+
+	if v := Hermite3(&p0, &m0, &p1, &m1, 0); !v.EqualThreshold(&p0, 1e-4) {
+		t.Errorf("Hermite3(0) = %v, expected %v", v, p0)
+	}
+	if v := Hermite3(&p0, &m0, &p1, &m1, 1); !v.EqualThreshold(&p1, 1e-4) {
+		t.Errorf("Hermite3(1) = %v, expected %v", v, p1)
+	}
+
+	// A straight line with constant tangents is a linear interpolation.
+	m := Vec3{1, 2, 3}
+	expected := Vec3{.25, .5, .75}
+	if v := Hermite3(&p0, &m, &p1, &m, .25); !v.EqualThreshold(&expected, 1e-4) {
+		t.Errorf("Hermite3(.25) = %v, expected %v", v, expected)
+	}
+}
+
+func TestCatmullRom3(t *testing.T) {
+	t.Parallel()
+	p := []Vec3{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}, {3, 0, 0}}
+
+	for _, test := range []struct{ t, x float32 }{{0, 1}, {.5, 1.5}, {1, 2}} {
+		v := CatmullRom3(&p[0], &p[1], &p[2], &p[3], test.t)
+		expected := Vec3{test.x, 0, 0}
+		if !v.EqualThreshold(&expected, 1e-4) {
+			t.Errorf("CatmullRom3(%f) = %v, expected %v", test.t, v, expected)
+		}
+	}
+}
+
+func TestSpline3(t *testing.T) {
+	t.Parallel()
+	var s Spline3
+
+	if v := s.Evaluate(1); v != (Vec3{}) {
+		t.Errorf("empty spline evaluated to %v", v)
+	}
+
+	points := []struct {
+		t float32
+		p Vec3
+	}{
+		{1, Vec3{0, 0, 0}},
+		{2, Vec3{1, 1, 0}},
+		{4, Vec3{2, 0, 0}},
+		{5, Vec3{3, 1, 1}},
+	}
+	for i := range points {
+		s.AddPoint(points[i].t, &points[i].p)
+	}
+
+	if s.Len() != 4 || s.Start() != 1 || s.End() != 5 {
+		t.Errorf("wrong spline length %d, start %f or end %f", s.Len(), s.Start(), s.End())
+	}
+
+	// The spline goes through all its points.
+	for _, point := range points {
+		if v := s.Evaluate(point.t); !v.EqualThreshold(&point.p, 1e-3) {
+			t.Errorf("Evaluate(%f) = %v, expected %v", point.t, v, point.p)
+		}
+	}
+
+	// Clamping.
+	if v := s.Evaluate(0); v != points[0].p {
+		t.Errorf("Evaluate(0) = %v, expected %v", v, points[0].p)
+	}
+	if v := s.Evaluate(10); v != points[3].p {
+		t.Errorf("Evaluate(10) = %v, expected %v", v, points[3].p)
+	}
+
+	// Continuity at a point: evaluating on both sides gives close results.
+	a := s.Evaluate(2 - 1e-3)
+	b := s.Evaluate(2 + 1e-3)
+	if d := a.Sub(&b); d.Len() > 1e-2 {
+		t.Errorf("spline isn't continuous at t=2: %v, %v", a, b)
+	}
+}
+
+func TestQuatSpline(t *testing.T) {
+	t.Parallel()
+	var s QuatSpline
+
+	if q := s.Evaluate(1); q != QuatIdent() {
+		t.Errorf("empty spline evaluated to %v", q)
+	}
+
+	negated := QuatRotate(DegToRad(-30), &Vec3{0, 0, 1})
+	negated.ScaleWith(-1)
+	rotations := []struct {
+		t float32
+		q Quaternion
+	}{
+		{0, QuatIdent()},
+		{1, QuatRotate(DegToRad(60), &Vec3{0, 1, 0})},
+		{2, QuatRotate(DegToRad(90), &Vec3{1, 0, 0})},
+		// Negated, the spline should still take the short path.
+		{3, negated},
+	}
+	for i := range rotations {
+		s.AddRotation(rotations[i].t, &rotations[i].q)
+	}
+
+	if s.Len() != 4 || s.Start() != 0 || s.End() != 3 {
+		t.Errorf("wrong spline length %d, start %f or end %f", s.Len(), s.Start(), s.End())
+	}
+
+	// The spline goes through all its rotations.
+	for _, r := range rotations {
+		if q := s.Evaluate(r.t); !q.OrientationEqualThreshold(&r.q, 1e-3) {
+			t.Errorf("Evaluate(%f) = %v, expected %v", r.t, q, r.q)
+		}
+	}
+
+	// Angular velocity is continuous at the inner rotations, ie. the
+	// rotation over a small step is the same on each side of the key.
+	const h = 1e-2
+	for _, key := range []float32{1, 2} {
+		before, at, after := s.Evaluate(key-h), s.Evaluate(key), s.Evaluate(key+h)
+		inv := before.Inverse()
+		left := inv.Mul(&at)
+		inv = at.Inverse()
+		right := inv.Mul(&after)
+		if !left.OrientationEqualThreshold(&right, 1e-3) {
+			t.Errorf("t = %f: angular velocity discontinuity, %v vs %v", key, left, right)
+		}
+	}
+
+	// The short path: the angle between consecutive samples stays small.
+	prev := s.Evaluate(2)
+	for i := 1; i <= 10; i++ {
+		q := s.Evaluate(2 + float32(i)/10)
+		if prev.Dot(&q) < .9 {
+			t.Errorf("t = %f: rotation jumped from %v to %v", 2+float32(i)/10, prev, q)
+		}
+		prev = q
+	}
+}
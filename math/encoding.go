@@ -0,0 +1,184 @@
+package math
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// Vectors, matrices and quaternions implement encoding.BinaryMarshaler,
+// encoding.BinaryUnmarshaler, json.Marshaler and json.Unmarshaler.
+//
+// The binary encoding is the sequence of float32 components in little-endian
+// order, in the same order as they are stored in memory: column major for
+// matrices. The JSON encoding is an array of numbers in that same order.
+//
+// Quaternions are encoded as (x, y, z, w), the convention used by most file
+// formats, glTF included.
+
+func marshalFloats(f []float32) []byte {
+	data := make([]byte, 4*len(f))
+	for i := range f {
+		binary.LittleEndian.PutUint32(data[4*i:], Float32bits(f[i]))
+	}
+	return data
+}
+
+func unmarshalFloats(data []byte, f []float32) error {
+	if len(data) != 4*len(f) {
+		return fmt.Errorf("math: expected %d bytes, got %d", 4*len(f), len(data))
+	}
+	for i := range f {
+		f[i] = Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return nil
+}
+
+func unmarshalJSONFloats(data []byte, f []float32) error {
+	var values []float32
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if len(values) != len(f) {
+		return fmt.Errorf("math: expected %d values, got %d", len(f), len(values))
+	}
+	copy(f, values)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v1 Vec2) MarshalBinary() ([]byte, error) { return marshalFloats(v1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v1 *Vec2) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, v1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (v1 Vec2) MarshalJSON() ([]byte, error) { return json.Marshal(v1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v1 *Vec2) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, v1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v1 Vec3) MarshalBinary() ([]byte, error) { return marshalFloats(v1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v1 *Vec3) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, v1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (v1 Vec3) MarshalJSON() ([]byte, error) { return json.Marshal(v1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v1 *Vec3) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, v1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v1 Vec4) MarshalBinary() ([]byte, error) { return marshalFloats(v1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v1 *Vec4) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, v1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (v1 Vec4) MarshalJSON() ([]byte, error) { return json.Marshal(v1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v1 *Vec4) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, v1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m1 Mat2) MarshalBinary() ([]byte, error) { return marshalFloats(m1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m1 *Mat2) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, m1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (m1 Mat2) MarshalJSON() ([]byte, error) { return json.Marshal(m1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m1 *Mat2) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, m1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m1 Mat3) MarshalBinary() ([]byte, error) { return marshalFloats(m1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m1 *Mat3) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, m1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (m1 Mat3) MarshalJSON() ([]byte, error) { return json.Marshal(m1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m1 *Mat3) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, m1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m1 Mat4) MarshalBinary() ([]byte, error) { return marshalFloats(m1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m1 *Mat4) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, m1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (m1 Mat4) MarshalJSON() ([]byte, error) { return json.Marshal(m1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m1 *Mat4) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, m1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m1 Mat3x4) MarshalBinary() ([]byte, error) { return marshalFloats(m1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m1 *Mat3x4) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, m1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (m1 Mat3x4) MarshalJSON() ([]byte, error) { return json.Marshal(m1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m1 *Mat3x4) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, m1[:]) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m1 Mat2x3) MarshalBinary() ([]byte, error) { return marshalFloats(m1[:]), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m1 *Mat2x3) UnmarshalBinary(data []byte) error { return unmarshalFloats(data, m1[:]) }
+
+// MarshalJSON implements json.Marshaler.
+func (m1 Mat2x3) MarshalJSON() ([]byte, error) { return json.Marshal(m1[:]) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m1 *Mat2x3) UnmarshalJSON(data []byte) error { return unmarshalJSONFloats(data, m1[:]) }
+
+func (q1 *Quaternion) xyzw() [4]float32 {
+	return [4]float32{q1.V[0], q1.V[1], q1.V[2], q1.W}
+}
+
+func (q1 *Quaternion) setXYZW(f *[4]float32) {
+	q1.V = Vec3{f[0], f[1], f[2]}
+	q1.W = f[3]
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (q1 Quaternion) MarshalBinary() ([]byte, error) {
+	f := q1.xyzw()
+	return marshalFloats(f[:]), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (q1 *Quaternion) UnmarshalBinary(data []byte) error {
+	var f [4]float32
+	if err := unmarshalFloats(data, f[:]); err != nil {
+		return err
+	}
+	q1.setXYZW(&f)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (q1 Quaternion) MarshalJSON() ([]byte, error) {
+	f := q1.xyzw()
+	return json.Marshal(f[:])
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q1 *Quaternion) UnmarshalJSON(data []byte) error {
+	var f [4]float32
+	if err := unmarshalJSONFloats(data, f[:]); err != nil {
+		return err
+	}
+	q1.setXYZW(&f)
+	return nil
+}
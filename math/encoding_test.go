@@ -0,0 +1,119 @@
+package math
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value interface{}
+		empty interface{}
+		size  int
+	}{
+		{&Vec2{1, 2}, &Vec2{}, 8},
+		{&Vec3{1, 2, 3}, &Vec3{}, 12},
+		{&Vec4{1, 2, 3, 4}, &Vec4{}, 16},
+		{&Mat2{1, 2, 3, 4}, &Mat2{}, 16},
+		{&Mat3{1, 2, 3, 4, 5, 6, 7, 8, 9}, &Mat3{}, 36},
+		{&Mat4{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, &Mat4{}, 64},
+		{&Mat3x4{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, &Mat3x4{}, 48},
+		{&Mat2x3{1, 2, 3, 4, 5, 6}, &Mat2x3{}, 24},
+		{&Quaternion{1, Vec3{2, 3, 4}}, &Quaternion{}, 16},
+	}
+
+	for _, test := range tests {
+		data, err := test.value.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("%T: %v", test.value, err)
+		}
+		if len(data) != test.size {
+			t.Errorf("%T: expected %d bytes, got %d", test.value, test.size, len(data))
+		}
+		if err := test.empty.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+			t.Fatalf("%T: %v", test.value, err)
+		}
+		if !reflect.DeepEqual(test.value, test.empty) {
+			t.Errorf("%T: round trip gave %v, expected %v", test.value, test.empty, test.value)
+		}
+		if err := test.empty.(encoding.BinaryUnmarshaler).UnmarshalBinary(data[1:]); err == nil {
+			t.Errorf("%T: expected an error with truncated data", test.value)
+		}
+	}
+}
+
+func TestMarshalBinaryLittleEndian(t *testing.T) {
+	t.Parallel()
+	v := Vec2{1, -2}
+	data, _ := v.MarshalBinary()
+	expected := []byte{0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x00, 0xc0}
+	if !reflect.DeepEqual(expected, data) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value    interface{}
+		empty    interface{}
+		expected string
+	}{
+		{&Vec2{1, 2}, &Vec2{}, "[1,2]"},
+		{&Vec3{1, 2, 3}, &Vec3{}, "[1,2,3]"},
+		{&Vec4{1, 2, 3, 4}, &Vec4{}, "[1,2,3,4]"},
+		{&Mat2{1, 2, 3, 4}, &Mat2{}, "[1,2,3,4]"},
+		{&Mat3{1, 2, 3, 4, 5, 6, 7, 8, 9}, &Mat3{}, "[1,2,3,4,5,6,7,8,9]"},
+		{&Mat3x4{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, &Mat3x4{}, "[1,2,3,4,5,6,7,8,9,10,11,12]"},
+		{&Mat2x3{1, 2, 3, 4, 5, 6}, &Mat2x3{}, "[1,2,3,4,5,6]"},
+		{&Quaternion{1, Vec3{2, 3, 4}}, &Quaternion{}, "[2,3,4,1]"},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(test.value)
+		if err != nil {
+			t.Fatalf("%T: %v", test.value, err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("%T: expected %s, got %s", test.value, test.expected, string(data))
+		}
+		if err := json.Unmarshal(data, test.empty); err != nil {
+			t.Fatalf("%T: %v", test.value, err)
+		}
+		if !reflect.DeepEqual(test.value, test.empty) {
+			t.Errorf("%T: round trip gave %v, expected %v", test.value, test.empty, test.value)
+		}
+		if err := json.Unmarshal([]byte("[1]"), test.empty); err == nil {
+			t.Errorf("%T: expected an error with the wrong number of values", test.value)
+		}
+	}
+}
+
+func TestMarshalJSONStruct(t *testing.T) {
+	t.Parallel()
+	type node struct {
+		Position Vec3
+		Rotation Quaternion
+	}
+
+	n := node{Vec3{1, 2, 3}, QuatIdent()}
+	data, err := json.Marshal(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Position":[1,2,3],"Rotation":[0,0,0,1]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, string(data))
+	}
+
+	var decoded node
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != n {
+		t.Errorf("round trip gave %v, expected %v", decoded, n)
+	}
+}
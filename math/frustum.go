@@ -0,0 +1,148 @@
+package math
+
+// ViewFrustum is the volume seen by a camera, bounded by 6 planes. A plane (a,
+// b, c, d) keeps the points with a*x + b*y + c*z + d >= 0. Not to be confused
+// with Frustum, building a projection matrix.
+type ViewFrustum struct {
+	Planes [6]Vec4
+}
+
+// Indices of the frustum planes.
+const (
+	FrustumLeft = iota
+	FrustumRight
+	FrustumBottom
+	FrustumTop
+	FrustumNear
+	FrustumFar
+)
+
+// Planes with a normal shorter than this are degenerated.
+const frustumEpsilon = 1e-6
+
+// ViewFrustumFromMat4 extracts the frustum planes from a projection . view
+// matrix, giving a frustum in world space. With a projection alone, the
+// frustum is in view space.
+//
+// The planes assume a [-1, 1] depth range. The frustum of projections using a
+// [0, 1] depth range is then a bit larger than the actual view volume, which
+// is fine for culling. Infinite projections don't have a far plane: it's
+// replaced by a plane keeping all points.
+func ViewFrustumFromMat4(m *Mat4) ViewFrustum {
+	// Gribb and Hartmann, "Fast Extraction of Viewing Frustum Planes from the
+	// World-View-Projection Matrix".
+	row := func(i int) Vec4 {
+		return Vec4{m[i], m[4+i], m[8+i], m[12+i]}
+	}
+	r0, r1, r2, r3 := row(0), row(1), row(2), row(3)
+
+	var f ViewFrustum
+	f.Planes[FrustumLeft] = r3.Add(&r0)
+	f.Planes[FrustumRight] = r3.Sub(&r0)
+	f.Planes[FrustumBottom] = r3.Add(&r1)
+	f.Planes[FrustumTop] = r3.Sub(&r1)
+	f.Planes[FrustumNear] = r3.Add(&r2)
+	f.Planes[FrustumFar] = r3.Sub(&r2)
+
+	for i := range f.Planes {
+		p := &f.Planes[i]
+		n := Vec3{p[0], p[1], p[2]}
+		l := n.Len()
+		if l < frustumEpsilon {
+			// Degenerated plane, eg. the far plane of an infinite
+			// projection.
+			*p = Vec4{0, 0, 0, 1}
+			continue
+		}
+		p.MulWith(1 / l)
+	}
+
+	return f
+}
+
+func planeDistance(p *Vec4, x, y, z float32) float32 {
+	return p[0]*x + p[1]*y + p[2]*z + p[3]
+}
+
+// FrustumCorners returns the corners of the view volume of a projection . view
+// matrix, m1 being the inverse of that matrix. The corners are in world space,
+// or in view space when m1 is the inverse of a projection alone. The first 4
+// corners are on the near plane and the last 4 on the far plane, each group in
+// the order bottom left, bottom right, top right and top left.
+//
+// Like ViewFrustumFromMat4, FrustumCorners assumes a [-1, 1] depth range, see
+// FrustumCornersDepth for other projections.
+func (m1 *Mat4) FrustumCorners() [8]Vec3 {
+	return m1.FrustumCornersDepth(-1, 1)
+}
+
+// FrustumCornersDepth is FrustumCorners with the near and far planes at the
+// clip space depths near and far, eg. 1 and 0 for ReversedPerspective.
+// Depths between the ones of the projection planes give the corners of a slice
+// of the view volume, eg. the cascades of shadow maps. Infinite projections
+// need a far depth strictly inside the depth range to get finite corners.
+func (m1 *Mat4) FrustumCornersDepth(near, far float32) [8]Vec3 {
+	var corners [8]Vec3
+	ndc := [4][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}}
+	for i, z := range [2]float32{near, far} {
+		for j, xy := range ndc {
+			p := Vec4{xy[0], xy[1], z, 1}
+			p = m1.Mul4x1(&p)
+			corners[i*4+j] = Vec3{p[0] / p[3], p[1] / p[3], p[2] / p[3]}
+		}
+	}
+	return corners
+}
+
+// ContainsPoint returns true if p is inside the frustum.
+func (f *ViewFrustum) ContainsPoint(p *Vec3) bool {
+	for i := range f.Planes {
+		if planeDistance(&f.Planes[i], p[0], p[1], p[2]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsAABB returns true if the box is, at least partly, inside the
+// frustum. The test is conservative: some boxes near the corners of the
+// frustum are reported as intersecting while they're outside.
+func (f *ViewFrustum) IntersectsAABB(b *AABB) bool {
+	if b.IsEmpty() {
+		return false
+	}
+
+	for i := range f.Planes {
+		p := &f.Planes[i]
+		// The corner of the box the furthest along the plane normal.
+		x, y, z := b.Min[0], b.Min[1], b.Min[2]
+		if p[0] >= 0 {
+			x = b.Max[0]
+		}
+		if p[1] >= 0 {
+			y = b.Max[1]
+		}
+		if p[2] >= 0 {
+			z = b.Max[2]
+		}
+		if planeDistance(p, x, y, z) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere returns true if the sphere is, at least partly, inside the
+// frustum. Like IntersectsAABB, the test is conservative.
+func (f *ViewFrustum) IntersectsSphere(s *BoundingSphere) bool {
+	if s.IsEmpty() {
+		return false
+	}
+
+	for i := range f.Planes {
+		if planeDistance(&f.Planes[i], s.Center[0], s.Center[1], s.Center[2]) < -s.Radius {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestViewFrustumContainsPoint(t *testing.T) {
+	t.Parallel()
+
+	// Camera at (0, 0, 10), looking at the origin.
+	view := Translate3D(0, 0, -10)
+	projections := []Mat4{
+		Perspective(DegToRad(90), 1, 1, 100),
+		InfinitePerspective(DegToRad(90), 1, 1),
+		ReversedPerspective(DegToRad(90), 1, 1, 100),
+		InfiniteReversedPerspective(DegToRad(90), 1, 1),
+	}
+	tests := []struct {
+		p        Vec3
+		expected bool
+	}{
+		{Vec3{0, 0, 0}, true},
+		{Vec3{9, 0, 0}, true},
+		{Vec3{11, 0, 0}, false},
+		{Vec3{0, -11, 0}, false},
+		// Behind the camera.
+		{Vec3{0, 0, 11}, false},
+		// Between the camera and the near plane.
+		{Vec3{0, 0, 9.5}, false},
+	}
+
+	for i := range projections {
+		m := projections[i].Mul4(&view)
+		f := ViewFrustumFromMat4(&m)
+		for j := range tests {
+			test := &tests[j]
+			if got := f.ContainsPoint(&test.p); got != test.expected {
+				t.Errorf("projection %d: ContainsPoint(%v): expected %v, got %v", i, test.p, test.expected, got)
+			}
+		}
+	}
+
+	// Only the finite, [-1, 1] depth range, projection has a far plane.
+	far := Vec3{0, 0, -200}
+	for i := range projections {
+		m := projections[i].Mul4(&view)
+		f := ViewFrustumFromMat4(&m)
+		expected := i != 0
+		if got := f.ContainsPoint(&far); got != expected {
+			t.Errorf("projection %d: ContainsPoint(%v): expected %v, got %v", i, far, expected, got)
+		}
+	}
+}
+
+func TestViewFrustumIntersects(t *testing.T) {
+	t.Parallel()
+
+	view := Translate3D(0, 0, -10)
+	m := Perspective(DegToRad(90), 1, 1, 100)
+	m.Mul4With(&view)
+	f := ViewFrustumFromMat4(&m)
+
+	tests := []struct {
+		b        AABB
+		expected bool
+	}{
+		{AABB{Vec3{-1, -1, -1}, Vec3{1, 1, 1}}, true},
+		// Straddling the right plane.
+		{AABB{Vec3{9, -1, -1}, Vec3{12, 1, 1}}, true},
+		{AABB{Vec3{12, -1, -1}, Vec3{13, 1, 1}}, false},
+		{AABB{Vec3{-1, -1, 20}, Vec3{1, 1, 21}}, false},
+		{EmptyAABB(), false},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		if got := f.IntersectsAABB(&test.b); got != test.expected {
+			t.Errorf("IntersectsAABB(%v): expected %v, got %v", test.b, test.expected, got)
+		}
+		s := test.b.BoundingSphere()
+		if got := f.IntersectsSphere(&s); got != test.expected {
+			t.Errorf("IntersectsSphere(%v): expected %v, got %v", s, test.expected, got)
+		}
+	}
+}
+
+func TestFrustumCorners(t *testing.T) {
+	t.Parallel()
+
+	// Camera at (0, 0, 10), looking at the origin, with a 90 degrees field of
+	// view: the half width of the view volume is its distance to the camera.
+	view := Translate3D(0, 0, -10)
+	tests := []struct {
+		name      string
+		m         Mat4
+		near, far float32
+	}{
+		{"Perspective", Perspective(DegToRad(90), 1, 1, 100), -1, 1},
+		{"ReversedPerspective", ReversedPerspective(DegToRad(90), 1, 1, 100), 1, 0},
+	}
+
+	for _, c := range tests {
+		vp := c.m.Mul4(&view)
+		inverse := vp.Inverse()
+		corners := inverse.FrustumCornersDepth(c.near, c.far)
+
+		expected := [8]Vec3{
+			{-1, -1, 9}, {1, -1, 9}, {1, 1, 9}, {-1, 1, 9},
+			{-100, -100, -90}, {100, -100, -90}, {100, 100, -90}, {-100, 100, -90},
+		}
+		for i := range corners {
+			if !corners[i].EqualThreshold(&expected[i], 1e-2) {
+				t.Errorf("%s: corner %d: expected %v, got %v", c.name, i, expected[i], corners[i])
+			}
+		}
+	}
+
+	// FrustumCorners assumes a [-1, 1] depth range.
+	vp := Perspective(DegToRad(90), 1, 1, 100)
+	inverse := vp.Inverse()
+	if inverse.FrustumCorners() != inverse.FrustumCornersDepth(-1, 1) {
+		t.Errorf("FrustumCorners should use a [-1, 1] depth range")
+	}
+}
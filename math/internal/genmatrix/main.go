@@ -0,0 +1,381 @@
+// genmatrix generates the method sets shared by all the matrix types of the
+// math package: element access, element-wise operations, rows and columns,
+// transposition and printing. The operations specific to a size, products,
+// determinants, inverses, conversions, are written by hand in matrix.go.
+//
+// Adding a matrix size is a matter of adding it to the matrices list below and
+// running go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+	"text/template"
+)
+
+// matrix is a matrix type with rows rows and cols columns, stored in column
+// major order.
+type matrix struct {
+	Name string
+	Rows int
+	Cols int
+}
+
+var matrices = []matrix{
+	{"Mat2", 2, 2},
+	{"Mat3", 3, 3},
+	{"Mat4", 4, 4},
+	{"Mat2x3", 2, 3},
+	{"Mat3x4", 3, 4},
+	{"Mat4x3", 4, 3},
+	{"Mat2x4", 2, 4},
+}
+
+func (m matrix) Len() int       { return m.Rows * m.Cols }
+func (m matrix) Square() bool   { return m.Rows == m.Cols }
+func (m matrix) RowVec() string { return fmt.Sprintf("Vec%d", m.Cols) }
+func (m matrix) ColVec() string { return fmt.Sprintf("Vec%d", m.Rows) }
+
+// Transpose returns the name of the transpose type of m, or the empty string
+// when that type isn't generated.
+func (m matrix) Transpose() string {
+	for _, t := range matrices {
+		if t.Rows == m.Cols && t.Cols == m.Rows {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// Elems formats each element index of m with format, "%[1]d" being the index,
+// and joins the results with sep.
+func (m matrix) Elems(format, sep string) string {
+	s := make([]string, m.Len())
+	for i := range s {
+		s[i] = fmt.Sprintf(format, i)
+	}
+	return strings.Join(s, sep)
+}
+
+// RowElems returns the elements of the row row of m.
+func (m matrix) RowElems(v string) string {
+	s := make([]string, m.Cols)
+	for c := range s {
+		s[c] = fmt.Sprintf("%s[row+%d]", v, c*m.Rows)
+	}
+	return strings.Join(s, ", ")
+}
+
+// ColElems returns the elements of the column col of m.
+func (m matrix) ColElems(v string) string {
+	s := make([]string, m.Rows)
+	for r := range s {
+		s[r] = fmt.Sprintf("%s[col*%d+%d]", v, m.Rows, r)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Vars returns the names prefix0 to prefixN-1.
+func vars(prefix string, n int) []string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return s
+}
+
+func (m matrix) RowVars() string { return strings.Join(vars("row", m.Rows), ", ") }
+func (m matrix) ColVars() string { return strings.Join(vars("col", m.Cols), ", ") }
+
+// Vecs returns the elements of v, a vector of size n.
+func (m matrix) Vecs(v string, n int) string {
+	return strings.Join(vars(v+"[", n), "], ") + "]"
+}
+
+func (m matrix) RowCalls() string {
+	s := make([]string, m.Rows)
+	for r := range s {
+		s[r] = fmt.Sprintf("m1.Row(%d)", r)
+	}
+	return strings.Join(s, ", ")
+}
+
+func (m matrix) ColCalls() string {
+	s := make([]string, m.Cols)
+	for c := range s {
+		s[c] = fmt.Sprintf("m1.Col(%d)", c)
+	}
+	return strings.Join(s, ", ")
+}
+
+// FromRows returns the elements of the matrix built from the rows row0 to
+// rowN-1, in column major order.
+func (m matrix) FromRows() string {
+	var s []string
+	for c := 0; c < m.Cols; c++ {
+		for r := 0; r < m.Rows; r++ {
+			s = append(s, fmt.Sprintf("row%d[%d]", r, c))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// FromCols returns the elements of the matrix built from the columns col0 to
+// colN-1, in column major order.
+func (m matrix) FromCols() string {
+	var s []string
+	for c := 0; c < m.Cols; c++ {
+		for r := 0; r < m.Rows; r++ {
+			s = append(s, fmt.Sprintf("col%d[%d]", c, r))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// Transposed returns the elements of the transpose of v, in column major
+// order.
+func (m matrix) Transposed(v string) string {
+	var s []string
+	for r := 0; r < m.Rows; r++ {
+		for c := 0; c < m.Cols; c++ {
+			s = append(s, fmt.Sprintf("%s[%d]", v, c*m.Rows+r))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// Swaps returns the swaps transposing a square matrix in place.
+func (m matrix) Swaps() string {
+	var lhs, rhs []string
+	for c := 0; c < m.Cols; c++ {
+		for r := c + 1; r < m.Rows; r++ {
+			a, b := c*m.Rows+r, r*m.Rows+c
+			lhs = append(lhs, fmt.Sprintf("m1[%d], m1[%d]", a, b))
+			rhs = append(rhs, fmt.Sprintf("m1[%d], m1[%d]", b, a))
+		}
+	}
+	return strings.Join(lhs, ", ") + " = " + strings.Join(rhs, ", ")
+}
+
+const header = `// Code generated by genmatrix. DO NOT EDIT.
+
+package math
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+)
+`
+
+var methods = template.Must(template.New("methods").Parse(`
+{{- if .Square}}
+// {{.Name}} represents a column major {{.Rows}}x{{.Cols}} matrix.
+{{- else}}
+// {{.Name}} represents a column major {{.Rows}} row {{.Cols}} column matrix.
+{{- end}}
+type {{.Name}} [{{.Len}}]float32
+
+// RowLen returns the length of a row for this matrix type.
+func ({{.Name}}) RowLen() int { return {{.Cols}} }
+
+// ColLen returns the length of a column for this matrix type.
+func ({{.Name}}) ColLen() int { return {{.Rows}} }
+
+// String pretty prints the matrix.
+func (m1 *{{.Name}}) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *{{.Name}}) At(row, col int) float32 { return m1[col*{{.Rows}}+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *{{.Name}}) Set(row, col int, value float32) { m1[col*{{.Rows}}+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func ({{.Name}}) Index(row, col int) int { return col*{{.Rows}} + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *{{.Name}}) Equal(m2 *{{.Name}}) bool {
+	return {{.Elems "FloatEqual(m1[%[1]d], m2[%[1]d])" " && "}}
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *{{.Name}}) EqualThreshold(m2 *{{.Name}}, threshold float32) bool {
+	return {{.Elems "FloatEqualThreshold(m1[%[1]d], m2[%[1]d], threshold)" " && "}}
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *{{.Name}}) SetCol(col int, v *{{.ColVec}}) {
+	{{.ColElems "m1"}} = {{.Vecs "v" .Rows}}
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *{{.Name}}) SetRow(row int, v *{{.RowVec}}) {
+	{{.RowElems "m1"}} = {{.Vecs "v" .Cols}}
+}
+
+// {{.Name}}FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func {{.Name}}FromRows({{.RowVars}} *{{.RowVec}}) {{.Name}} {
+	return {{.Name}}{ {{- .FromRows -}} }
+}
+
+// {{.Name}}FromCols builds a new matrix from column vectors.
+func {{.Name}}FromCols({{.ColVars}} *{{.ColVec}}) {{.Name}} {
+	return {{.Name}}{ {{- .FromCols -}} }
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *{{.Name}}) Add(m2 *{{.Name}}) {{.Name}} {
+	return {{.Name}}{ {{- .Elems "m1[%[1]d] + m2[%[1]d]" ", " -}} }
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *{{.Name}}) AddOf(m2, m3 *{{.Name}}) {
+	{{.Elems "m1[%[1]d] = m2[%[1]d] + m3[%[1]d]" "\n"}}
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *{{.Name}}) AddWith(m2 *{{.Name}}) {
+	{{.Elems "m1[%[1]d] += m2[%[1]d]" "\n"}}
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *{{.Name}}) Sub(m2 *{{.Name}}) {{.Name}} {
+	return {{.Name}}{ {{- .Elems "m1[%[1]d] - m2[%[1]d]" ", " -}} }
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *{{.Name}}) SubOf(m2, m3 *{{.Name}}) {
+	{{.Elems "m1[%[1]d] = m2[%[1]d] - m3[%[1]d]" "\n"}}
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *{{.Name}}) SubWith(m2 *{{.Name}}) {
+	{{.Elems "m1[%[1]d] -= m2[%[1]d]" "\n"}}
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *{{.Name}}) Mul(c float32) {{.Name}} {
+	return {{.Name}}{ {{- .Elems "m1[%[1]d] * c" ", " -}} }
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *{{.Name}}) MulOf(m2 *{{.Name}}, c float32) {
+	{{.Elems "m1[%[1]d] = m2[%[1]d] * c" "\n"}}
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *{{.Name}}) MulWith(c float32) {
+	{{.Elems "m1[%[1]d] *= c" "\n"}}
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *{{.Name}}) Row(row int) {{.RowVec}} {
+	return {{.RowVec}}{ {{- .RowElems "m1" -}} }
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *{{.Name}}) Rows() ({{.RowVars}} {{.RowVec}}) {
+	return {{.RowCalls}}
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *{{.Name}}) Col(col int) {{.ColVec}} {
+	return {{.ColVec}}{ {{- .ColElems "m1" -}} }
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *{{.Name}}) Cols() ({{.ColVars}} {{.ColVec}}) {
+	return {{.ColCalls}}
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *{{.Name}}) Abs() {{.Name}} {
+	return {{.Name}}{ {{- .Elems "Abs(m1[%[1]d])" ", " -}} }
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *{{.Name}}) AbsSelf() {
+	{{.Elems "m1[%[1]d] = Abs(m1[%[1]d])" "\n"}}
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *{{.Name}}) AbsOf(m2 *{{.Name}}) {
+	{{.Elems "m1[%[1]d] = Abs(m2[%[1]d])" "\n"}}
+}
+{{- with .Transpose}}
+
+// Transposed produces the transpose of this matrix. For any MxN matrix the
+// transpose is an NxM matrix with the rows swapped with the columns.
+func (m1 *{{$.Name}}) Transposed() {{.}} {
+	return {{.}}{ {{- $.Transposed "m1" -}} }
+}
+
+// TransposeOf is a memory friendly version of Transposed.
+func (m1 *{{$.Name}}) TransposeOf(m2 *{{.}}) {
+	*m1 = m2.Transposed()
+}
+{{- end}}
+{{- if .Square}}
+
+// Transpose transposes this matrix in place.
+func (m1 *{{.Name}}) Transpose() {
+	{{.Swaps}}
+}
+{{- end}}
+`))
+
+func main() {
+	output := flag.String("o", "matrix_gen.go", "output file")
+	flag.Parse()
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	for _, m := range matrices {
+		if err := methods.Execute(&buf, m); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, buf.Bytes())
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,134 @@
+package math
+
+// Layout is the memory layout of an interface block in a shader, ie. the rules
+// deciding where each member of a uniform or shader storage block lives in the
+// buffer backing it.
+type Layout int
+
+const (
+	// Std140 is the standard layout for uniform blocks. Vectors of 3 and 4
+	// components, matrix columns and array elements are all aligned on 16
+	// bytes.
+	Std140 Layout = iota
+	// Std430 is the standard layout for shader storage blocks. It relaxes
+	// Std140: matrix columns and array elements of 2-component vectors or
+	// scalars are tightly packed.
+	Std430
+)
+
+// BlockWriter writes values into a buffer following the alignment rules of a
+// Layout. The resulting buffer can be uploaded as is to a uniform or shader
+// storage buffer.
+//
+// Members have to be written in the order they are declared in the shader.
+type BlockWriter struct {
+	layout Layout
+	data   []float32
+}
+
+// NewBlockWriter creates a BlockWriter for layout.
+func NewBlockWriter(layout Layout) *BlockWriter {
+	return &BlockWriter{
+		layout: layout,
+	}
+}
+
+// align pads the buffer with zeros so the next member starts at a multiple of
+// n floats.
+func (w *BlockWriter) align(n int) {
+	for len(w.data)%n != 0 {
+		w.data = append(w.data, 0)
+	}
+}
+
+// columnAlign returns the alignment, in floats, of a matrix column or an array
+// element made of n floats.
+func (w *BlockWriter) columnAlign(n int) int {
+	if w.layout == Std140 || n > 2 {
+		return 4
+	}
+	return n
+}
+
+func (w *BlockWriter) columns(m []float32, rows int) {
+	stride := w.columnAlign(rows)
+	for col := 0; col < len(m)/rows; col++ {
+		w.align(stride)
+		w.data = append(w.data, m[col*rows:(col+1)*rows]...)
+	}
+	w.align(stride)
+}
+
+// Float writes a float.
+func (w *BlockWriter) Float(f float32) {
+	w.data = append(w.data, f)
+}
+
+// Vec2 writes a vec2.
+func (w *BlockWriter) Vec2(v *Vec2) {
+	w.align(2)
+	w.data = append(w.data, v[:]...)
+}
+
+// Vec3 writes a vec3. A vec3 has the same alignment as a vec4 but a following
+// float can be packed right after it.
+func (w *BlockWriter) Vec3(v *Vec3) {
+	w.align(4)
+	w.data = append(w.data, v[:]...)
+}
+
+// Vec4 writes a vec4.
+func (w *BlockWriter) Vec4(v *Vec4) {
+	w.align(4)
+	w.data = append(w.data, v[:]...)
+}
+
+// Mat2 writes a mat2.
+func (w *BlockWriter) Mat2(m *Mat2) {
+	w.columns(m[:], 2)
+}
+
+// Mat3 writes a mat3.
+func (w *BlockWriter) Mat3(m *Mat3) {
+	w.columns(m[:], 3)
+}
+
+// Mat4 writes a mat4.
+func (w *BlockWriter) Mat4(m *Mat4) {
+	w.columns(m[:], 4)
+}
+
+// Mat3x4 writes a Mat3x4, a mat4x3 in GLSL (4 columns of 3 rows).
+func (w *BlockWriter) Mat3x4(m *Mat3x4) {
+	w.columns(m[:], 3)
+}
+
+// Mat2x3 writes a Mat2x3, a mat3x2 in GLSL (3 columns of 2 rows).
+func (w *BlockWriter) Mat2x3(m *Mat2x3) {
+	w.columns(m[:], 2)
+}
+
+// FloatArray writes an array of floats.
+func (w *BlockWriter) FloatArray(f []float32) {
+	w.columns(f, 1)
+}
+
+// Len returns the size of the block, in bytes.
+func (w *BlockWriter) Len() int {
+	return 4 * len(w.data)
+}
+
+// Floats returns the block as a slice of float32.
+func (w *BlockWriter) Floats() []float32 {
+	return w.data
+}
+
+// Bytes returns the block as a slice of bytes, in little-endian order.
+func (w *BlockWriter) Bytes() []byte {
+	return marshalFloats(w.data)
+}
+
+// Reset empties the block, keeping the underlying storage.
+func (w *BlockWriter) Reset() {
+	w.data = w.data[:0]
+}
@@ -0,0 +1,119 @@
+package math
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlockWriter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		description string
+		layout      Layout
+		write       func(w *BlockWriter)
+		expected    []float32
+	}{
+		{
+			"float packed after vec3", Std140,
+			func(w *BlockWriter) {
+				w.Vec3(&Vec3{1, 2, 3})
+				w.Float(4)
+			},
+			[]float32{1, 2, 3, 4},
+		},
+		{
+			"vec3 aligned on 16 bytes", Std140,
+			func(w *BlockWriter) {
+				w.Float(1)
+				w.Vec3(&Vec3{2, 3, 4})
+			},
+			[]float32{1, 0, 0, 0, 2, 3, 4},
+		},
+		{
+			"vec2 aligned on 8 bytes", Std430,
+			func(w *BlockWriter) {
+				w.Float(1)
+				w.Vec2(&Vec2{2, 3})
+				w.Vec4(&Vec4{4, 5, 6, 7})
+			},
+			[]float32{1, 0, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			"std140 mat2", Std140,
+			func(w *BlockWriter) {
+				w.Mat2(&Mat2{1, 2, 3, 4})
+				w.Float(5)
+			},
+			[]float32{1, 2, 0, 0, 3, 4, 0, 0, 5},
+		},
+		{
+			"std430 mat2", Std430,
+			func(w *BlockWriter) {
+				w.Mat2(&Mat2{1, 2, 3, 4})
+				w.Float(5)
+			},
+			[]float32{1, 2, 3, 4, 5},
+		},
+		{
+			"mat3", Std430,
+			func(w *BlockWriter) {
+				w.Mat3(&Mat3{1, 2, 3, 4, 5, 6, 7, 8, 9})
+			},
+			[]float32{1, 2, 3, 0, 4, 5, 6, 0, 7, 8, 9, 0},
+		},
+		{
+			"mat4", Std140,
+			func(w *BlockWriter) {
+				w.Float(1)
+				w.Mat4(&Mat4{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+			},
+			[]float32{1, 0, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		},
+		{
+			"mat3x4", Std140,
+			func(w *BlockWriter) {
+				w.Mat3x4(&Mat3x4{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+			},
+			[]float32{1, 2, 3, 0, 4, 5, 6, 0, 7, 8, 9, 0, 10, 11, 12, 0},
+		},
+		{
+			"std430 mat2x3", Std430,
+			func(w *BlockWriter) {
+				w.Mat2x3(&Mat2x3{1, 2, 3, 4, 5, 6})
+			},
+			[]float32{1, 2, 3, 4, 5, 6},
+		},
+		{
+			"std140 float array", Std140,
+			func(w *BlockWriter) {
+				w.FloatArray([]float32{1, 2})
+				w.Float(3)
+			},
+			[]float32{1, 0, 0, 0, 2, 0, 0, 0, 3},
+		},
+		{
+			"std430 float array", Std430,
+			func(w *BlockWriter) {
+				w.FloatArray([]float32{1, 2})
+				w.Float(3)
+			},
+			[]float32{1, 2, 3},
+		},
+	}
+
+	for _, test := range tests {
+		w := NewBlockWriter(test.layout)
+		test.write(w)
+		if !reflect.DeepEqual(test.expected, w.Floats()) {
+			t.Errorf("%s: expected %v, got %v", test.description, test.expected, w.Floats())
+		}
+		if w.Len() != 4*len(test.expected) || len(w.Bytes()) != w.Len() {
+			t.Errorf("%s: wrong length %d", test.description, w.Len())
+		}
+
+		w.Reset()
+		if w.Len() != 0 {
+			t.Errorf("%s: Reset() didn't empty the block", test.description)
+		}
+	}
+}
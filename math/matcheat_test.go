@@ -46,3 +46,116 @@ func TestMat3x4_Inv(t *testing.T) {
 		return
 	}
 }
+
+func TestMat3x4_InPlace(t *testing.T) {
+	t.Parallel()
+	var m1, m2 Mat3x4
+	for i := range m1 {
+		m1[i] = rand.Float32()*20 - 10
+		m2[i] = rand.Float32()*20 - 10
+	}
+
+	var got Mat3x4
+	got.AddOf(&m1, &m2)
+	if got != m1.Add(&m2) {
+		t.Errorf("AddOf() = %v, want %v", got, m1.Add(&m2))
+	}
+	got = m1
+	got.AddWith(&m2)
+	if got != m1.Add(&m2) {
+		t.Errorf("AddWith() = %v, want %v", got, m1.Add(&m2))
+	}
+	got.SubOf(&m1, &m2)
+	if got != m1.Sub(&m2) {
+		t.Errorf("SubOf() = %v, want %v", got, m1.Sub(&m2))
+	}
+	got = m1
+	got.SubWith(&m2)
+	if got != m1.Sub(&m2) {
+		t.Errorf("SubWith() = %v, want %v", got, m1.Sub(&m2))
+	}
+	got.MulOf(&m1, 3)
+	if got != m1.Mul(3) {
+		t.Errorf("MulOf() = %v, want %v", got, m1.Mul(3))
+	}
+	got = m1
+	got.MulWith(3)
+	if got != m1.Mul(3) {
+		t.Errorf("MulWith() = %v, want %v", got, m1.Mul(3))
+	}
+	got.AbsOf(&m1)
+	if got != m1.Abs() {
+		t.Errorf("AbsOf() = %v, want %v", got, m1.Abs())
+	}
+	got = m1
+	got.AbsSelf()
+	if got != m1.Abs() {
+		t.Errorf("AbsSelf() = %v, want %v", got, m1.Abs())
+	}
+	got.InverseOf(&m1)
+	if got != m1.Inverse() {
+		t.Errorf("InverseOf() = %v, want %v", got, m1.Inverse())
+	}
+	m4 := m1.Mat4()
+	if d, want := m1.Diag(), m4.Diag(); d != want.Vec3() {
+		t.Errorf("Diag() = %v, want %v", d, want.Vec3())
+	}
+	if tr, want := m1.Trace(), m4.Trace()-1; !FloatEqualThreshold(tr, want, 1e-4) {
+		t.Errorf("Trace() = %v, want %v", tr, want)
+	}
+}
+
+func TestMat2x3_InPlace(t *testing.T) {
+	t.Parallel()
+	var m1, m2 Mat2x3
+	for i := range m1 {
+		m1[i] = rand.Float32()*20 - 10
+		m2[i] = rand.Float32()*20 - 10
+	}
+
+	var got Mat2x3
+	got.AddOf(&m1, &m2)
+	if got != m1.Add(&m2) {
+		t.Errorf("AddOf() = %v, want %v", got, m1.Add(&m2))
+	}
+	got = m1
+	got.AddWith(&m2)
+	if got != m1.Add(&m2) {
+		t.Errorf("AddWith() = %v, want %v", got, m1.Add(&m2))
+	}
+	got.SubOf(&m1, &m2)
+	if got != m1.Sub(&m2) {
+		t.Errorf("SubOf() = %v, want %v", got, m1.Sub(&m2))
+	}
+	got = m1
+	got.SubWith(&m2)
+	if got != m1.Sub(&m2) {
+		t.Errorf("SubWith() = %v, want %v", got, m1.Sub(&m2))
+	}
+	got.MulOf(&m1, 3)
+	if got != m1.Mul(3) {
+		t.Errorf("MulOf() = %v, want %v", got, m1.Mul(3))
+	}
+	got = m1
+	got.MulWith(3)
+	if got != m1.Mul(3) {
+		t.Errorf("MulWith() = %v, want %v", got, m1.Mul(3))
+	}
+	got.AbsOf(&m1)
+	if got != m1.Abs() {
+		t.Errorf("AbsOf() = %v, want %v", got, m1.Abs())
+	}
+	got = m1
+	got.AbsSelf()
+	if got != m1.Abs() {
+		t.Errorf("AbsSelf() = %v, want %v", got, m1.Abs())
+	}
+	got = m1
+	got.Invert()
+	if got != m1.Inverse() {
+		t.Errorf("Invert() = %v, want %v", got, m1.Inverse())
+	}
+	if d := m1.Diag(); d != (Vec2{m1[0], m1[3]}) {
+		t.Errorf("Diag() = %v, want %v", d, Vec2{m1[0], m1[3]})
+	}
+}
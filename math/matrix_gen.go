@@ -0,0 +1,1679 @@
+// Code generated by genmatrix. DO NOT EDIT.
+
+package math
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+)
+
+// Mat2 represents a column major 2x2 matrix.
+type Mat2 [4]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat2) RowLen() int { return 2 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat2) ColLen() int { return 2 }
+
+// String pretty prints the matrix.
+func (m1 *Mat2) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat2) At(row, col int) float32 { return m1[col*2+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat2) Set(row, col int, value float32) { m1[col*2+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat2) Index(row, col int) int { return col*2 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat2) Equal(m2 *Mat2) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat2) EqualThreshold(m2 *Mat2, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat2) SetCol(col int, v *Vec2) {
+	m1[col*2+0], m1[col*2+1] = v[0], v[1]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat2) SetRow(row int, v *Vec2) {
+	m1[row+0], m1[row+2] = v[0], v[1]
+}
+
+// Mat2FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat2FromRows(row0, row1 *Vec2) Mat2 {
+	return Mat2{row0[0], row1[0], row0[1], row1[1]}
+}
+
+// Mat2FromCols builds a new matrix from column vectors.
+func Mat2FromCols(col0, col1 *Vec2) Mat2 {
+	return Mat2{col0[0], col0[1], col1[0], col1[1]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat2) Add(m2 *Mat2) Mat2 {
+	return Mat2{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat2) AddOf(m2, m3 *Mat2) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat2) AddWith(m2 *Mat2) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat2) Sub(m2 *Mat2) Mat2 {
+	return Mat2{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat2) SubOf(m2, m3 *Mat2) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat2) SubWith(m2 *Mat2) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat2) Mul(c float32) Mat2 {
+	return Mat2{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat2) MulOf(m2 *Mat2, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat2) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat2) Row(row int) Vec2 {
+	return Vec2{m1[row+0], m1[row+2]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat2) Rows() (row0, row1 Vec2) {
+	return m1.Row(0), m1.Row(1)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat2) Col(col int) Vec2 {
+	return Vec2{m1[col*2+0], m1[col*2+1]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat2) Cols() (col0, col1 Vec2) {
+	return m1.Col(0), m1.Col(1)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat2) Abs() Mat2 {
+	return Mat2{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat2) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat2) AbsOf(m2 *Mat2) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+}
+
+// Transposed produces the transpose of this matrix. For any MxN matrix the
+// transpose is an NxM matrix with the rows swapped with the columns.
+func (m1 *Mat2) Transposed() Mat2 {
+	return Mat2{m1[0], m1[2], m1[1], m1[3]}
+}
+
+// TransposeOf is a memory friendly version of Transposed.
+func (m1 *Mat2) TransposeOf(m2 *Mat2) {
+	*m1 = m2.Transposed()
+}
+
+// Transpose transposes this matrix in place.
+func (m1 *Mat2) Transpose() {
+	m1[1], m1[2] = m1[2], m1[1]
+}
+
+// Mat3 represents a column major 3x3 matrix.
+type Mat3 [9]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat3) RowLen() int { return 3 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat3) ColLen() int { return 3 }
+
+// String pretty prints the matrix.
+func (m1 *Mat3) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat3) At(row, col int) float32 { return m1[col*3+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat3) Set(row, col int, value float32) { m1[col*3+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat3) Index(row, col int) int { return col*3 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat3) Equal(m2 *Mat3) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3]) && FloatEqual(m1[4], m2[4]) && FloatEqual(m1[5], m2[5]) && FloatEqual(m1[6], m2[6]) && FloatEqual(m1[7], m2[7]) && FloatEqual(m1[8], m2[8])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat3) EqualThreshold(m2 *Mat3, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold) && FloatEqualThreshold(m1[4], m2[4], threshold) && FloatEqualThreshold(m1[5], m2[5], threshold) && FloatEqualThreshold(m1[6], m2[6], threshold) && FloatEqualThreshold(m1[7], m2[7], threshold) && FloatEqualThreshold(m1[8], m2[8], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat3) SetCol(col int, v *Vec3) {
+	m1[col*3+0], m1[col*3+1], m1[col*3+2] = v[0], v[1], v[2]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat3) SetRow(row int, v *Vec3) {
+	m1[row+0], m1[row+3], m1[row+6] = v[0], v[1], v[2]
+}
+
+// Mat3FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat3FromRows(row0, row1, row2 *Vec3) Mat3 {
+	return Mat3{row0[0], row1[0], row2[0], row0[1], row1[1], row2[1], row0[2], row1[2], row2[2]}
+}
+
+// Mat3FromCols builds a new matrix from column vectors.
+func Mat3FromCols(col0, col1, col2 *Vec3) Mat3 {
+	return Mat3{col0[0], col0[1], col0[2], col1[0], col1[1], col1[2], col2[0], col2[1], col2[2]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat3) Add(m2 *Mat3) Mat3 {
+	return Mat3{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3], m1[4] + m2[4], m1[5] + m2[5], m1[6] + m2[6], m1[7] + m2[7], m1[8] + m2[8]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat3) AddOf(m2, m3 *Mat3) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+	m1[4] = m2[4] + m3[4]
+	m1[5] = m2[5] + m3[5]
+	m1[6] = m2[6] + m3[6]
+	m1[7] = m2[7] + m3[7]
+	m1[8] = m2[8] + m3[8]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat3) AddWith(m2 *Mat3) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+	m1[4] += m2[4]
+	m1[5] += m2[5]
+	m1[6] += m2[6]
+	m1[7] += m2[7]
+	m1[8] += m2[8]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat3) Sub(m2 *Mat3) Mat3 {
+	return Mat3{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3], m1[4] - m2[4], m1[5] - m2[5], m1[6] - m2[6], m1[7] - m2[7], m1[8] - m2[8]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat3) SubOf(m2, m3 *Mat3) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+	m1[4] = m2[4] - m3[4]
+	m1[5] = m2[5] - m3[5]
+	m1[6] = m2[6] - m3[6]
+	m1[7] = m2[7] - m3[7]
+	m1[8] = m2[8] - m3[8]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat3) SubWith(m2 *Mat3) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+	m1[4] -= m2[4]
+	m1[5] -= m2[5]
+	m1[6] -= m2[6]
+	m1[7] -= m2[7]
+	m1[8] -= m2[8]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat3) Mul(c float32) Mat3 {
+	return Mat3{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c, m1[4] * c, m1[5] * c, m1[6] * c, m1[7] * c, m1[8] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat3) MulOf(m2 *Mat3, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+	m1[4] = m2[4] * c
+	m1[5] = m2[5] * c
+	m1[6] = m2[6] * c
+	m1[7] = m2[7] * c
+	m1[8] = m2[8] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat3) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+	m1[4] *= c
+	m1[5] *= c
+	m1[6] *= c
+	m1[7] *= c
+	m1[8] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat3) Row(row int) Vec3 {
+	return Vec3{m1[row+0], m1[row+3], m1[row+6]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat3) Rows() (row0, row1, row2 Vec3) {
+	return m1.Row(0), m1.Row(1), m1.Row(2)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat3) Col(col int) Vec3 {
+	return Vec3{m1[col*3+0], m1[col*3+1], m1[col*3+2]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat3) Cols() (col0, col1, col2 Vec3) {
+	return m1.Col(0), m1.Col(1), m1.Col(2)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat3) Abs() Mat3 {
+	return Mat3{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3]), Abs(m1[4]), Abs(m1[5]), Abs(m1[6]), Abs(m1[7]), Abs(m1[8])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat3) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+	m1[4] = Abs(m1[4])
+	m1[5] = Abs(m1[5])
+	m1[6] = Abs(m1[6])
+	m1[7] = Abs(m1[7])
+	m1[8] = Abs(m1[8])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat3) AbsOf(m2 *Mat3) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+	m1[4] = Abs(m2[4])
+	m1[5] = Abs(m2[5])
+	m1[6] = Abs(m2[6])
+	m1[7] = Abs(m2[7])
+	m1[8] = Abs(m2[8])
+}
+
+// Transposed produces the transpose of this matrix. For any MxN matrix the
+// transpose is an NxM matrix with the rows swapped with the columns.
+func (m1 *Mat3) Transposed() Mat3 {
+	return Mat3{m1[0], m1[3], m1[6], m1[1], m1[4], m1[7], m1[2], m1[5], m1[8]}
+}
+
+// TransposeOf is a memory friendly version of Transposed.
+func (m1 *Mat3) TransposeOf(m2 *Mat3) {
+	*m1 = m2.Transposed()
+}
+
+// Transpose transposes this matrix in place.
+func (m1 *Mat3) Transpose() {
+	m1[1], m1[3], m1[2], m1[6], m1[5], m1[7] = m1[3], m1[1], m1[6], m1[2], m1[7], m1[5]
+}
+
+// Mat4 represents a column major 4x4 matrix.
+type Mat4 [16]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat4) RowLen() int { return 4 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat4) ColLen() int { return 4 }
+
+// String pretty prints the matrix.
+func (m1 *Mat4) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat4) At(row, col int) float32 { return m1[col*4+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat4) Set(row, col int, value float32) { m1[col*4+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat4) Index(row, col int) int { return col*4 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat4) Equal(m2 *Mat4) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3]) && FloatEqual(m1[4], m2[4]) && FloatEqual(m1[5], m2[5]) && FloatEqual(m1[6], m2[6]) && FloatEqual(m1[7], m2[7]) && FloatEqual(m1[8], m2[8]) && FloatEqual(m1[9], m2[9]) && FloatEqual(m1[10], m2[10]) && FloatEqual(m1[11], m2[11]) && FloatEqual(m1[12], m2[12]) && FloatEqual(m1[13], m2[13]) && FloatEqual(m1[14], m2[14]) && FloatEqual(m1[15], m2[15])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat4) EqualThreshold(m2 *Mat4, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold) && FloatEqualThreshold(m1[4], m2[4], threshold) && FloatEqualThreshold(m1[5], m2[5], threshold) && FloatEqualThreshold(m1[6], m2[6], threshold) && FloatEqualThreshold(m1[7], m2[7], threshold) && FloatEqualThreshold(m1[8], m2[8], threshold) && FloatEqualThreshold(m1[9], m2[9], threshold) && FloatEqualThreshold(m1[10], m2[10], threshold) && FloatEqualThreshold(m1[11], m2[11], threshold) && FloatEqualThreshold(m1[12], m2[12], threshold) && FloatEqualThreshold(m1[13], m2[13], threshold) && FloatEqualThreshold(m1[14], m2[14], threshold) && FloatEqualThreshold(m1[15], m2[15], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat4) SetCol(col int, v *Vec4) {
+	m1[col*4+0], m1[col*4+1], m1[col*4+2], m1[col*4+3] = v[0], v[1], v[2], v[3]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat4) SetRow(row int, v *Vec4) {
+	m1[row+0], m1[row+4], m1[row+8], m1[row+12] = v[0], v[1], v[2], v[3]
+}
+
+// Mat4FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat4FromRows(row0, row1, row2, row3 *Vec4) Mat4 {
+	return Mat4{row0[0], row1[0], row2[0], row3[0], row0[1], row1[1], row2[1], row3[1], row0[2], row1[2], row2[2], row3[2], row0[3], row1[3], row2[3], row3[3]}
+}
+
+// Mat4FromCols builds a new matrix from column vectors.
+func Mat4FromCols(col0, col1, col2, col3 *Vec4) Mat4 {
+	return Mat4{col0[0], col0[1], col0[2], col0[3], col1[0], col1[1], col1[2], col1[3], col2[0], col2[1], col2[2], col2[3], col3[0], col3[1], col3[2], col3[3]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat4) Add(m2 *Mat4) Mat4 {
+	return Mat4{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3], m1[4] + m2[4], m1[5] + m2[5], m1[6] + m2[6], m1[7] + m2[7], m1[8] + m2[8], m1[9] + m2[9], m1[10] + m2[10], m1[11] + m2[11], m1[12] + m2[12], m1[13] + m2[13], m1[14] + m2[14], m1[15] + m2[15]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat4) AddOf(m2, m3 *Mat4) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+	m1[4] = m2[4] + m3[4]
+	m1[5] = m2[5] + m3[5]
+	m1[6] = m2[6] + m3[6]
+	m1[7] = m2[7] + m3[7]
+	m1[8] = m2[8] + m3[8]
+	m1[9] = m2[9] + m3[9]
+	m1[10] = m2[10] + m3[10]
+	m1[11] = m2[11] + m3[11]
+	m1[12] = m2[12] + m3[12]
+	m1[13] = m2[13] + m3[13]
+	m1[14] = m2[14] + m3[14]
+	m1[15] = m2[15] + m3[15]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat4) AddWith(m2 *Mat4) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+	m1[4] += m2[4]
+	m1[5] += m2[5]
+	m1[6] += m2[6]
+	m1[7] += m2[7]
+	m1[8] += m2[8]
+	m1[9] += m2[9]
+	m1[10] += m2[10]
+	m1[11] += m2[11]
+	m1[12] += m2[12]
+	m1[13] += m2[13]
+	m1[14] += m2[14]
+	m1[15] += m2[15]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat4) Sub(m2 *Mat4) Mat4 {
+	return Mat4{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3], m1[4] - m2[4], m1[5] - m2[5], m1[6] - m2[6], m1[7] - m2[7], m1[8] - m2[8], m1[9] - m2[9], m1[10] - m2[10], m1[11] - m2[11], m1[12] - m2[12], m1[13] - m2[13], m1[14] - m2[14], m1[15] - m2[15]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat4) SubOf(m2, m3 *Mat4) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+	m1[4] = m2[4] - m3[4]
+	m1[5] = m2[5] - m3[5]
+	m1[6] = m2[6] - m3[6]
+	m1[7] = m2[7] - m3[7]
+	m1[8] = m2[8] - m3[8]
+	m1[9] = m2[9] - m3[9]
+	m1[10] = m2[10] - m3[10]
+	m1[11] = m2[11] - m3[11]
+	m1[12] = m2[12] - m3[12]
+	m1[13] = m2[13] - m3[13]
+	m1[14] = m2[14] - m3[14]
+	m1[15] = m2[15] - m3[15]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat4) SubWith(m2 *Mat4) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+	m1[4] -= m2[4]
+	m1[5] -= m2[5]
+	m1[6] -= m2[6]
+	m1[7] -= m2[7]
+	m1[8] -= m2[8]
+	m1[9] -= m2[9]
+	m1[10] -= m2[10]
+	m1[11] -= m2[11]
+	m1[12] -= m2[12]
+	m1[13] -= m2[13]
+	m1[14] -= m2[14]
+	m1[15] -= m2[15]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat4) Mul(c float32) Mat4 {
+	return Mat4{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c, m1[4] * c, m1[5] * c, m1[6] * c, m1[7] * c, m1[8] * c, m1[9] * c, m1[10] * c, m1[11] * c, m1[12] * c, m1[13] * c, m1[14] * c, m1[15] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat4) MulOf(m2 *Mat4, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+	m1[4] = m2[4] * c
+	m1[5] = m2[5] * c
+	m1[6] = m2[6] * c
+	m1[7] = m2[7] * c
+	m1[8] = m2[8] * c
+	m1[9] = m2[9] * c
+	m1[10] = m2[10] * c
+	m1[11] = m2[11] * c
+	m1[12] = m2[12] * c
+	m1[13] = m2[13] * c
+	m1[14] = m2[14] * c
+	m1[15] = m2[15] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat4) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+	m1[4] *= c
+	m1[5] *= c
+	m1[6] *= c
+	m1[7] *= c
+	m1[8] *= c
+	m1[9] *= c
+	m1[10] *= c
+	m1[11] *= c
+	m1[12] *= c
+	m1[13] *= c
+	m1[14] *= c
+	m1[15] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat4) Row(row int) Vec4 {
+	return Vec4{m1[row+0], m1[row+4], m1[row+8], m1[row+12]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat4) Rows() (row0, row1, row2, row3 Vec4) {
+	return m1.Row(0), m1.Row(1), m1.Row(2), m1.Row(3)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat4) Col(col int) Vec4 {
+	return Vec4{m1[col*4+0], m1[col*4+1], m1[col*4+2], m1[col*4+3]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat4) Cols() (col0, col1, col2, col3 Vec4) {
+	return m1.Col(0), m1.Col(1), m1.Col(2), m1.Col(3)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat4) Abs() Mat4 {
+	return Mat4{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3]), Abs(m1[4]), Abs(m1[5]), Abs(m1[6]), Abs(m1[7]), Abs(m1[8]), Abs(m1[9]), Abs(m1[10]), Abs(m1[11]), Abs(m1[12]), Abs(m1[13]), Abs(m1[14]), Abs(m1[15])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat4) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+	m1[4] = Abs(m1[4])
+	m1[5] = Abs(m1[5])
+	m1[6] = Abs(m1[6])
+	m1[7] = Abs(m1[7])
+	m1[8] = Abs(m1[8])
+	m1[9] = Abs(m1[9])
+	m1[10] = Abs(m1[10])
+	m1[11] = Abs(m1[11])
+	m1[12] = Abs(m1[12])
+	m1[13] = Abs(m1[13])
+	m1[14] = Abs(m1[14])
+	m1[15] = Abs(m1[15])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat4) AbsOf(m2 *Mat4) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+	m1[4] = Abs(m2[4])
+	m1[5] = Abs(m2[5])
+	m1[6] = Abs(m2[6])
+	m1[7] = Abs(m2[7])
+	m1[8] = Abs(m2[8])
+	m1[9] = Abs(m2[9])
+	m1[10] = Abs(m2[10])
+	m1[11] = Abs(m2[11])
+	m1[12] = Abs(m2[12])
+	m1[13] = Abs(m2[13])
+	m1[14] = Abs(m2[14])
+	m1[15] = Abs(m2[15])
+}
+
+// Transposed produces the transpose of this matrix. For any MxN matrix the
+// transpose is an NxM matrix with the rows swapped with the columns.
+func (m1 *Mat4) Transposed() Mat4 {
+	return Mat4{m1[0], m1[4], m1[8], m1[12], m1[1], m1[5], m1[9], m1[13], m1[2], m1[6], m1[10], m1[14], m1[3], m1[7], m1[11], m1[15]}
+}
+
+// TransposeOf is a memory friendly version of Transposed.
+func (m1 *Mat4) TransposeOf(m2 *Mat4) {
+	*m1 = m2.Transposed()
+}
+
+// Transpose transposes this matrix in place.
+func (m1 *Mat4) Transpose() {
+	m1[1], m1[4], m1[2], m1[8], m1[3], m1[12], m1[6], m1[9], m1[7], m1[13], m1[11], m1[14] = m1[4], m1[1], m1[8], m1[2], m1[12], m1[3], m1[9], m1[6], m1[13], m1[7], m1[14], m1[11]
+}
+
+// Mat2x3 represents a column major 2 row 3 column matrix.
+type Mat2x3 [6]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat2x3) RowLen() int { return 3 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat2x3) ColLen() int { return 2 }
+
+// String pretty prints the matrix.
+func (m1 *Mat2x3) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat2x3) At(row, col int) float32 { return m1[col*2+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat2x3) Set(row, col int, value float32) { m1[col*2+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat2x3) Index(row, col int) int { return col*2 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat2x3) Equal(m2 *Mat2x3) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3]) && FloatEqual(m1[4], m2[4]) && FloatEqual(m1[5], m2[5])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat2x3) EqualThreshold(m2 *Mat2x3, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold) && FloatEqualThreshold(m1[4], m2[4], threshold) && FloatEqualThreshold(m1[5], m2[5], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat2x3) SetCol(col int, v *Vec2) {
+	m1[col*2+0], m1[col*2+1] = v[0], v[1]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat2x3) SetRow(row int, v *Vec3) {
+	m1[row+0], m1[row+2], m1[row+4] = v[0], v[1], v[2]
+}
+
+// Mat2x3FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat2x3FromRows(row0, row1 *Vec3) Mat2x3 {
+	return Mat2x3{row0[0], row1[0], row0[1], row1[1], row0[2], row1[2]}
+}
+
+// Mat2x3FromCols builds a new matrix from column vectors.
+func Mat2x3FromCols(col0, col1, col2 *Vec2) Mat2x3 {
+	return Mat2x3{col0[0], col0[1], col1[0], col1[1], col2[0], col2[1]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat2x3) Add(m2 *Mat2x3) Mat2x3 {
+	return Mat2x3{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3], m1[4] + m2[4], m1[5] + m2[5]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat2x3) AddOf(m2, m3 *Mat2x3) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+	m1[4] = m2[4] + m3[4]
+	m1[5] = m2[5] + m3[5]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat2x3) AddWith(m2 *Mat2x3) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+	m1[4] += m2[4]
+	m1[5] += m2[5]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat2x3) Sub(m2 *Mat2x3) Mat2x3 {
+	return Mat2x3{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3], m1[4] - m2[4], m1[5] - m2[5]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat2x3) SubOf(m2, m3 *Mat2x3) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+	m1[4] = m2[4] - m3[4]
+	m1[5] = m2[5] - m3[5]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat2x3) SubWith(m2 *Mat2x3) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+	m1[4] -= m2[4]
+	m1[5] -= m2[5]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat2x3) Mul(c float32) Mat2x3 {
+	return Mat2x3{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c, m1[4] * c, m1[5] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat2x3) MulOf(m2 *Mat2x3, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+	m1[4] = m2[4] * c
+	m1[5] = m2[5] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat2x3) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+	m1[4] *= c
+	m1[5] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat2x3) Row(row int) Vec3 {
+	return Vec3{m1[row+0], m1[row+2], m1[row+4]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat2x3) Rows() (row0, row1 Vec3) {
+	return m1.Row(0), m1.Row(1)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat2x3) Col(col int) Vec2 {
+	return Vec2{m1[col*2+0], m1[col*2+1]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat2x3) Cols() (col0, col1, col2 Vec2) {
+	return m1.Col(0), m1.Col(1), m1.Col(2)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat2x3) Abs() Mat2x3 {
+	return Mat2x3{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3]), Abs(m1[4]), Abs(m1[5])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat2x3) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+	m1[4] = Abs(m1[4])
+	m1[5] = Abs(m1[5])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat2x3) AbsOf(m2 *Mat2x3) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+	m1[4] = Abs(m2[4])
+	m1[5] = Abs(m2[5])
+}
+
+// Mat3x4 represents a column major 3 row 4 column matrix.
+type Mat3x4 [12]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat3x4) RowLen() int { return 4 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat3x4) ColLen() int { return 3 }
+
+// String pretty prints the matrix.
+func (m1 *Mat3x4) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat3x4) At(row, col int) float32 { return m1[col*3+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat3x4) Set(row, col int, value float32) { m1[col*3+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat3x4) Index(row, col int) int { return col*3 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat3x4) Equal(m2 *Mat3x4) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3]) && FloatEqual(m1[4], m2[4]) && FloatEqual(m1[5], m2[5]) && FloatEqual(m1[6], m2[6]) && FloatEqual(m1[7], m2[7]) && FloatEqual(m1[8], m2[8]) && FloatEqual(m1[9], m2[9]) && FloatEqual(m1[10], m2[10]) && FloatEqual(m1[11], m2[11])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat3x4) EqualThreshold(m2 *Mat3x4, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold) && FloatEqualThreshold(m1[4], m2[4], threshold) && FloatEqualThreshold(m1[5], m2[5], threshold) && FloatEqualThreshold(m1[6], m2[6], threshold) && FloatEqualThreshold(m1[7], m2[7], threshold) && FloatEqualThreshold(m1[8], m2[8], threshold) && FloatEqualThreshold(m1[9], m2[9], threshold) && FloatEqualThreshold(m1[10], m2[10], threshold) && FloatEqualThreshold(m1[11], m2[11], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat3x4) SetCol(col int, v *Vec3) {
+	m1[col*3+0], m1[col*3+1], m1[col*3+2] = v[0], v[1], v[2]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat3x4) SetRow(row int, v *Vec4) {
+	m1[row+0], m1[row+3], m1[row+6], m1[row+9] = v[0], v[1], v[2], v[3]
+}
+
+// Mat3x4FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat3x4FromRows(row0, row1, row2 *Vec4) Mat3x4 {
+	return Mat3x4{row0[0], row1[0], row2[0], row0[1], row1[1], row2[1], row0[2], row1[2], row2[2], row0[3], row1[3], row2[3]}
+}
+
+// Mat3x4FromCols builds a new matrix from column vectors.
+func Mat3x4FromCols(col0, col1, col2, col3 *Vec3) Mat3x4 {
+	return Mat3x4{col0[0], col0[1], col0[2], col1[0], col1[1], col1[2], col2[0], col2[1], col2[2], col3[0], col3[1], col3[2]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat3x4) Add(m2 *Mat3x4) Mat3x4 {
+	return Mat3x4{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3], m1[4] + m2[4], m1[5] + m2[5], m1[6] + m2[6], m1[7] + m2[7], m1[8] + m2[8], m1[9] + m2[9], m1[10] + m2[10], m1[11] + m2[11]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat3x4) AddOf(m2, m3 *Mat3x4) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+	m1[4] = m2[4] + m3[4]
+	m1[5] = m2[5] + m3[5]
+	m1[6] = m2[6] + m3[6]
+	m1[7] = m2[7] + m3[7]
+	m1[8] = m2[8] + m3[8]
+	m1[9] = m2[9] + m3[9]
+	m1[10] = m2[10] + m3[10]
+	m1[11] = m2[11] + m3[11]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat3x4) AddWith(m2 *Mat3x4) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+	m1[4] += m2[4]
+	m1[5] += m2[5]
+	m1[6] += m2[6]
+	m1[7] += m2[7]
+	m1[8] += m2[8]
+	m1[9] += m2[9]
+	m1[10] += m2[10]
+	m1[11] += m2[11]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat3x4) Sub(m2 *Mat3x4) Mat3x4 {
+	return Mat3x4{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3], m1[4] - m2[4], m1[5] - m2[5], m1[6] - m2[6], m1[7] - m2[7], m1[8] - m2[8], m1[9] - m2[9], m1[10] - m2[10], m1[11] - m2[11]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat3x4) SubOf(m2, m3 *Mat3x4) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+	m1[4] = m2[4] - m3[4]
+	m1[5] = m2[5] - m3[5]
+	m1[6] = m2[6] - m3[6]
+	m1[7] = m2[7] - m3[7]
+	m1[8] = m2[8] - m3[8]
+	m1[9] = m2[9] - m3[9]
+	m1[10] = m2[10] - m3[10]
+	m1[11] = m2[11] - m3[11]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat3x4) SubWith(m2 *Mat3x4) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+	m1[4] -= m2[4]
+	m1[5] -= m2[5]
+	m1[6] -= m2[6]
+	m1[7] -= m2[7]
+	m1[8] -= m2[8]
+	m1[9] -= m2[9]
+	m1[10] -= m2[10]
+	m1[11] -= m2[11]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat3x4) Mul(c float32) Mat3x4 {
+	return Mat3x4{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c, m1[4] * c, m1[5] * c, m1[6] * c, m1[7] * c, m1[8] * c, m1[9] * c, m1[10] * c, m1[11] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat3x4) MulOf(m2 *Mat3x4, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+	m1[4] = m2[4] * c
+	m1[5] = m2[5] * c
+	m1[6] = m2[6] * c
+	m1[7] = m2[7] * c
+	m1[8] = m2[8] * c
+	m1[9] = m2[9] * c
+	m1[10] = m2[10] * c
+	m1[11] = m2[11] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat3x4) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+	m1[4] *= c
+	m1[5] *= c
+	m1[6] *= c
+	m1[7] *= c
+	m1[8] *= c
+	m1[9] *= c
+	m1[10] *= c
+	m1[11] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat3x4) Row(row int) Vec4 {
+	return Vec4{m1[row+0], m1[row+3], m1[row+6], m1[row+9]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat3x4) Rows() (row0, row1, row2 Vec4) {
+	return m1.Row(0), m1.Row(1), m1.Row(2)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat3x4) Col(col int) Vec3 {
+	return Vec3{m1[col*3+0], m1[col*3+1], m1[col*3+2]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat3x4) Cols() (col0, col1, col2, col3 Vec3) {
+	return m1.Col(0), m1.Col(1), m1.Col(2), m1.Col(3)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat3x4) Abs() Mat3x4 {
+	return Mat3x4{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3]), Abs(m1[4]), Abs(m1[5]), Abs(m1[6]), Abs(m1[7]), Abs(m1[8]), Abs(m1[9]), Abs(m1[10]), Abs(m1[11])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat3x4) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+	m1[4] = Abs(m1[4])
+	m1[5] = Abs(m1[5])
+	m1[6] = Abs(m1[6])
+	m1[7] = Abs(m1[7])
+	m1[8] = Abs(m1[8])
+	m1[9] = Abs(m1[9])
+	m1[10] = Abs(m1[10])
+	m1[11] = Abs(m1[11])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat3x4) AbsOf(m2 *Mat3x4) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+	m1[4] = Abs(m2[4])
+	m1[5] = Abs(m2[5])
+	m1[6] = Abs(m2[6])
+	m1[7] = Abs(m2[7])
+	m1[8] = Abs(m2[8])
+	m1[9] = Abs(m2[9])
+	m1[10] = Abs(m2[10])
+	m1[11] = Abs(m2[11])
+}
+
+// Transposed produces the transpose of this matrix. For any MxN matrix the
+// transpose is an NxM matrix with the rows swapped with the columns.
+func (m1 *Mat3x4) Transposed() Mat4x3 {
+	return Mat4x3{m1[0], m1[3], m1[6], m1[9], m1[1], m1[4], m1[7], m1[10], m1[2], m1[5], m1[8], m1[11]}
+}
+
+// TransposeOf is a memory friendly version of Transposed.
+func (m1 *Mat3x4) TransposeOf(m2 *Mat4x3) {
+	*m1 = m2.Transposed()
+}
+
+// Mat4x3 represents a column major 4 row 3 column matrix.
+type Mat4x3 [12]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat4x3) RowLen() int { return 3 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat4x3) ColLen() int { return 4 }
+
+// String pretty prints the matrix.
+func (m1 *Mat4x3) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat4x3) At(row, col int) float32 { return m1[col*4+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat4x3) Set(row, col int, value float32) { m1[col*4+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat4x3) Index(row, col int) int { return col*4 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat4x3) Equal(m2 *Mat4x3) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3]) && FloatEqual(m1[4], m2[4]) && FloatEqual(m1[5], m2[5]) && FloatEqual(m1[6], m2[6]) && FloatEqual(m1[7], m2[7]) && FloatEqual(m1[8], m2[8]) && FloatEqual(m1[9], m2[9]) && FloatEqual(m1[10], m2[10]) && FloatEqual(m1[11], m2[11])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat4x3) EqualThreshold(m2 *Mat4x3, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold) && FloatEqualThreshold(m1[4], m2[4], threshold) && FloatEqualThreshold(m1[5], m2[5], threshold) && FloatEqualThreshold(m1[6], m2[6], threshold) && FloatEqualThreshold(m1[7], m2[7], threshold) && FloatEqualThreshold(m1[8], m2[8], threshold) && FloatEqualThreshold(m1[9], m2[9], threshold) && FloatEqualThreshold(m1[10], m2[10], threshold) && FloatEqualThreshold(m1[11], m2[11], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat4x3) SetCol(col int, v *Vec4) {
+	m1[col*4+0], m1[col*4+1], m1[col*4+2], m1[col*4+3] = v[0], v[1], v[2], v[3]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat4x3) SetRow(row int, v *Vec3) {
+	m1[row+0], m1[row+4], m1[row+8] = v[0], v[1], v[2]
+}
+
+// Mat4x3FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat4x3FromRows(row0, row1, row2, row3 *Vec3) Mat4x3 {
+	return Mat4x3{row0[0], row1[0], row2[0], row3[0], row0[1], row1[1], row2[1], row3[1], row0[2], row1[2], row2[2], row3[2]}
+}
+
+// Mat4x3FromCols builds a new matrix from column vectors.
+func Mat4x3FromCols(col0, col1, col2 *Vec4) Mat4x3 {
+	return Mat4x3{col0[0], col0[1], col0[2], col0[3], col1[0], col1[1], col1[2], col1[3], col2[0], col2[1], col2[2], col2[3]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat4x3) Add(m2 *Mat4x3) Mat4x3 {
+	return Mat4x3{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3], m1[4] + m2[4], m1[5] + m2[5], m1[6] + m2[6], m1[7] + m2[7], m1[8] + m2[8], m1[9] + m2[9], m1[10] + m2[10], m1[11] + m2[11]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat4x3) AddOf(m2, m3 *Mat4x3) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+	m1[4] = m2[4] + m3[4]
+	m1[5] = m2[5] + m3[5]
+	m1[6] = m2[6] + m3[6]
+	m1[7] = m2[7] + m3[7]
+	m1[8] = m2[8] + m3[8]
+	m1[9] = m2[9] + m3[9]
+	m1[10] = m2[10] + m3[10]
+	m1[11] = m2[11] + m3[11]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat4x3) AddWith(m2 *Mat4x3) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+	m1[4] += m2[4]
+	m1[5] += m2[5]
+	m1[6] += m2[6]
+	m1[7] += m2[7]
+	m1[8] += m2[8]
+	m1[9] += m2[9]
+	m1[10] += m2[10]
+	m1[11] += m2[11]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat4x3) Sub(m2 *Mat4x3) Mat4x3 {
+	return Mat4x3{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3], m1[4] - m2[4], m1[5] - m2[5], m1[6] - m2[6], m1[7] - m2[7], m1[8] - m2[8], m1[9] - m2[9], m1[10] - m2[10], m1[11] - m2[11]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat4x3) SubOf(m2, m3 *Mat4x3) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+	m1[4] = m2[4] - m3[4]
+	m1[5] = m2[5] - m3[5]
+	m1[6] = m2[6] - m3[6]
+	m1[7] = m2[7] - m3[7]
+	m1[8] = m2[8] - m3[8]
+	m1[9] = m2[9] - m3[9]
+	m1[10] = m2[10] - m3[10]
+	m1[11] = m2[11] - m3[11]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat4x3) SubWith(m2 *Mat4x3) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+	m1[4] -= m2[4]
+	m1[5] -= m2[5]
+	m1[6] -= m2[6]
+	m1[7] -= m2[7]
+	m1[8] -= m2[8]
+	m1[9] -= m2[9]
+	m1[10] -= m2[10]
+	m1[11] -= m2[11]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat4x3) Mul(c float32) Mat4x3 {
+	return Mat4x3{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c, m1[4] * c, m1[5] * c, m1[6] * c, m1[7] * c, m1[8] * c, m1[9] * c, m1[10] * c, m1[11] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat4x3) MulOf(m2 *Mat4x3, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+	m1[4] = m2[4] * c
+	m1[5] = m2[5] * c
+	m1[6] = m2[6] * c
+	m1[7] = m2[7] * c
+	m1[8] = m2[8] * c
+	m1[9] = m2[9] * c
+	m1[10] = m2[10] * c
+	m1[11] = m2[11] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat4x3) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+	m1[4] *= c
+	m1[5] *= c
+	m1[6] *= c
+	m1[7] *= c
+	m1[8] *= c
+	m1[9] *= c
+	m1[10] *= c
+	m1[11] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat4x3) Row(row int) Vec3 {
+	return Vec3{m1[row+0], m1[row+4], m1[row+8]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat4x3) Rows() (row0, row1, row2, row3 Vec3) {
+	return m1.Row(0), m1.Row(1), m1.Row(2), m1.Row(3)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat4x3) Col(col int) Vec4 {
+	return Vec4{m1[col*4+0], m1[col*4+1], m1[col*4+2], m1[col*4+3]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat4x3) Cols() (col0, col1, col2 Vec4) {
+	return m1.Col(0), m1.Col(1), m1.Col(2)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat4x3) Abs() Mat4x3 {
+	return Mat4x3{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3]), Abs(m1[4]), Abs(m1[5]), Abs(m1[6]), Abs(m1[7]), Abs(m1[8]), Abs(m1[9]), Abs(m1[10]), Abs(m1[11])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat4x3) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+	m1[4] = Abs(m1[4])
+	m1[5] = Abs(m1[5])
+	m1[6] = Abs(m1[6])
+	m1[7] = Abs(m1[7])
+	m1[8] = Abs(m1[8])
+	m1[9] = Abs(m1[9])
+	m1[10] = Abs(m1[10])
+	m1[11] = Abs(m1[11])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat4x3) AbsOf(m2 *Mat4x3) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+	m1[4] = Abs(m2[4])
+	m1[5] = Abs(m2[5])
+	m1[6] = Abs(m2[6])
+	m1[7] = Abs(m2[7])
+	m1[8] = Abs(m2[8])
+	m1[9] = Abs(m2[9])
+	m1[10] = Abs(m2[10])
+	m1[11] = Abs(m2[11])
+}
+
+// Transposed produces the transpose of this matrix. For any MxN matrix the
+// transpose is an NxM matrix with the rows swapped with the columns.
+func (m1 *Mat4x3) Transposed() Mat3x4 {
+	return Mat3x4{m1[0], m1[4], m1[8], m1[1], m1[5], m1[9], m1[2], m1[6], m1[10], m1[3], m1[7], m1[11]}
+}
+
+// TransposeOf is a memory friendly version of Transposed.
+func (m1 *Mat4x3) TransposeOf(m2 *Mat3x4) {
+	*m1 = m2.Transposed()
+}
+
+// Mat2x4 represents a column major 2 row 4 column matrix.
+type Mat2x4 [8]float32
+
+// RowLen returns the length of a row for this matrix type.
+func (Mat2x4) RowLen() int { return 4 }
+
+// ColLen returns the length of a column for this matrix type.
+func (Mat2x4) ColLen() int { return 2 }
+
+// String pretty prints the matrix.
+func (m1 *Mat2x4) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 4, 4, 1, ' ', tabwriter.AlignRight)
+	for i := 0; i < m1.ColLen(); i++ {
+		for _, col := range m1.Row(i) {
+			fmt.Fprintf(w, "%f\t", col)
+		}
+
+		fmt.Fprintln(w, "")
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// At returns the matrix element at the given row and column.
+func (m1 *Mat2x4) At(row, col int) float32 { return m1[col*2+row] }
+
+// Set sets the corresponding matrix element at the given row and column.
+func (m1 *Mat2x4) Set(row, col int, value float32) { m1[col*2+row] = value }
+
+// Index returns the index of the given row and column. Used to directly access
+// the array.
+func (Mat2x4) Index(row, col int) int { return col*2 + row }
+
+// Equal performs an element-wise approximate equality test between two
+// matrices, as if FloatEqual had been used.
+func (m1 *Mat2x4) Equal(m2 *Mat2x4) bool {
+	return FloatEqual(m1[0], m2[0]) && FloatEqual(m1[1], m2[1]) && FloatEqual(m1[2], m2[2]) && FloatEqual(m1[3], m2[3]) && FloatEqual(m1[4], m2[4]) && FloatEqual(m1[5], m2[5]) && FloatEqual(m1[6], m2[6]) && FloatEqual(m1[7], m2[7])
+}
+
+// EqualThreshold performs an element-wise approximate equality test
+// between two matrices with a given epsilon threshold, as if
+// FloatEqualThreshold had been used.
+func (m1 *Mat2x4) EqualThreshold(m2 *Mat2x4, threshold float32) bool {
+	return FloatEqualThreshold(m1[0], m2[0], threshold) && FloatEqualThreshold(m1[1], m2[1], threshold) && FloatEqualThreshold(m1[2], m2[2], threshold) && FloatEqualThreshold(m1[3], m2[3], threshold) && FloatEqualThreshold(m1[4], m2[4], threshold) && FloatEqualThreshold(m1[5], m2[5], threshold) && FloatEqualThreshold(m1[6], m2[6], threshold) && FloatEqualThreshold(m1[7], m2[7], threshold)
+}
+
+// SetCol sets a column within the matrix, so it mutates the calling matrix.
+func (m1 *Mat2x4) SetCol(col int, v *Vec2) {
+	m1[col*2+0], m1[col*2+1] = v[0], v[1]
+}
+
+// SetRow sets a row within the matrix, so it mutates the calling matrix.
+func (m1 *Mat2x4) SetRow(row int, v *Vec4) {
+	m1[row+0], m1[row+2], m1[row+4], m1[row+6] = v[0], v[1], v[2], v[3]
+}
+
+// Mat2x4FromRows builds a new matrix from row vectors. The resulting matrix
+// will still be in column major order, but this can be good for hand-building
+// matrices.
+func Mat2x4FromRows(row0, row1 *Vec4) Mat2x4 {
+	return Mat2x4{row0[0], row1[0], row0[1], row1[1], row0[2], row1[2], row0[3], row1[3]}
+}
+
+// Mat2x4FromCols builds a new matrix from column vectors.
+func Mat2x4FromCols(col0, col1, col2, col3 *Vec2) Mat2x4 {
+	return Mat2x4{col0[0], col0[1], col1[0], col1[1], col2[0], col2[1], col3[0], col3[1]}
+}
+
+// Add performs an element-wise addition of two matrices, this is equivalent to
+// iterating over every element of m1 and adding the corresponding value of m2.
+func (m1 *Mat2x4) Add(m2 *Mat2x4) Mat2x4 {
+	return Mat2x4{m1[0] + m2[0], m1[1] + m2[1], m1[2] + m2[2], m1[3] + m2[3], m1[4] + m2[4], m1[5] + m2[5], m1[6] + m2[6], m1[7] + m2[7]}
+}
+
+// AddOf is a memory friendly version of Add.
+func (m1 *Mat2x4) AddOf(m2, m3 *Mat2x4) {
+	m1[0] = m2[0] + m3[0]
+	m1[1] = m2[1] + m3[1]
+	m1[2] = m2[2] + m3[2]
+	m1[3] = m2[3] + m3[3]
+	m1[4] = m2[4] + m3[4]
+	m1[5] = m2[5] + m3[5]
+	m1[6] = m2[6] + m3[6]
+	m1[7] = m2[7] + m3[7]
+}
+
+// AddWith is a memory friendly version of Add.
+func (m1 *Mat2x4) AddWith(m2 *Mat2x4) {
+	m1[0] += m2[0]
+	m1[1] += m2[1]
+	m1[2] += m2[2]
+	m1[3] += m2[3]
+	m1[4] += m2[4]
+	m1[5] += m2[5]
+	m1[6] += m2[6]
+	m1[7] += m2[7]
+}
+
+// Sub performs an element-wise subtraction of two matrices, this is equivalent
+// to iterating over every element of m1 and subtracting the corresponding value
+// of m2.
+func (m1 *Mat2x4) Sub(m2 *Mat2x4) Mat2x4 {
+	return Mat2x4{m1[0] - m2[0], m1[1] - m2[1], m1[2] - m2[2], m1[3] - m2[3], m1[4] - m2[4], m1[5] - m2[5], m1[6] - m2[6], m1[7] - m2[7]}
+}
+
+// SubOf is a memory friendly version of Sub.
+func (m1 *Mat2x4) SubOf(m2, m3 *Mat2x4) {
+	m1[0] = m2[0] - m3[0]
+	m1[1] = m2[1] - m3[1]
+	m1[2] = m2[2] - m3[2]
+	m1[3] = m2[3] - m3[3]
+	m1[4] = m2[4] - m3[4]
+	m1[5] = m2[5] - m3[5]
+	m1[6] = m2[6] - m3[6]
+	m1[7] = m2[7] - m3[7]
+}
+
+// SubWith is a memory friendly version of Sub.
+func (m1 *Mat2x4) SubWith(m2 *Mat2x4) {
+	m1[0] -= m2[0]
+	m1[1] -= m2[1]
+	m1[2] -= m2[2]
+	m1[3] -= m2[3]
+	m1[4] -= m2[4]
+	m1[5] -= m2[5]
+	m1[6] -= m2[6]
+	m1[7] -= m2[7]
+}
+
+// Mul performs a scalar multiplication of the matrix. This is equivalent to
+// iterating over every element of the matrix and multiply it by c.
+func (m1 *Mat2x4) Mul(c float32) Mat2x4 {
+	return Mat2x4{m1[0] * c, m1[1] * c, m1[2] * c, m1[3] * c, m1[4] * c, m1[5] * c, m1[6] * c, m1[7] * c}
+}
+
+// MulOf is a memory friendly version of Mul.
+func (m1 *Mat2x4) MulOf(m2 *Mat2x4, c float32) {
+	m1[0] = m2[0] * c
+	m1[1] = m2[1] * c
+	m1[2] = m2[2] * c
+	m1[3] = m2[3] * c
+	m1[4] = m2[4] * c
+	m1[5] = m2[5] * c
+	m1[6] = m2[6] * c
+	m1[7] = m2[7] * c
+}
+
+// MulWith is a memory friendly version of Mul.
+func (m1 *Mat2x4) MulWith(c float32) {
+	m1[0] *= c
+	m1[1] *= c
+	m1[2] *= c
+	m1[3] *= c
+	m1[4] *= c
+	m1[5] *= c
+	m1[6] *= c
+	m1[7] *= c
+}
+
+// Row returns a vector representing the corresponding row (starting at row 0).
+// This package makes no distinction between row and column vectors, so it will
+// be a normal VecM for a MxN matrix.
+func (m1 *Mat2x4) Row(row int) Vec4 {
+	return Vec4{m1[row+0], m1[row+2], m1[row+4], m1[row+6]}
+}
+
+// Rows decomposes a matrix into its corresponding row vectors. This is
+// equivalent to calling mat.Row for each row.
+func (m1 *Mat2x4) Rows() (row0, row1 Vec4) {
+	return m1.Row(0), m1.Row(1)
+}
+
+// Col returns a vector representing the corresponding column (starting at col
+// 0). This package makes no distinction between row and column vectors, so it
+// will be a normal VecN for a MxN matrix.
+func (m1 *Mat2x4) Col(col int) Vec2 {
+	return Vec2{m1[col*2+0], m1[col*2+1]}
+}
+
+// Cols decomposes a matrix into its corresponding column vectors.
+// This is equivalent to calling mat.Col for each column.
+func (m1 *Mat2x4) Cols() (col0, col1, col2, col3 Vec2) {
+	return m1.Col(0), m1.Col(1), m1.Col(2), m1.Col(3)
+}
+
+// Abs returns the element-wise absolute value of this matrix.
+func (m1 *Mat2x4) Abs() Mat2x4 {
+	return Mat2x4{Abs(m1[0]), Abs(m1[1]), Abs(m1[2]), Abs(m1[3]), Abs(m1[4]), Abs(m1[5]), Abs(m1[6]), Abs(m1[7])}
+}
+
+// AbsSelf is a memory friendly version of Abs.
+func (m1 *Mat2x4) AbsSelf() {
+	m1[0] = Abs(m1[0])
+	m1[1] = Abs(m1[1])
+	m1[2] = Abs(m1[2])
+	m1[3] = Abs(m1[3])
+	m1[4] = Abs(m1[4])
+	m1[5] = Abs(m1[5])
+	m1[6] = Abs(m1[6])
+	m1[7] = Abs(m1[7])
+}
+
+// AbsOf is a memory friendly version of Abs.
+func (m1 *Mat2x4) AbsOf(m2 *Mat2x4) {
+	m1[0] = Abs(m2[0])
+	m1[1] = Abs(m2[1])
+	m1[2] = Abs(m2[2])
+	m1[3] = Abs(m2[3])
+	m1[4] = Abs(m2[4])
+	m1[5] = Abs(m2[5])
+	m1[6] = Abs(m2[6])
+	m1[7] = Abs(m2[7])
+}
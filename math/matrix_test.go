@@ -120,6 +120,49 @@ func TestTransposeSquare(t *testing.T) {
 	}
 }
 
+func TestTransposeNonSquare(t *testing.T) {
+	t.Parallel()
+	v := [3]Vec4{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	m := Mat3x4FromRows(&v[0], &v[1], &v[2])
+
+	transpose := m.Transposed()
+
+	correct := Mat4x3FromCols(&v[0], &v[1], &v[2])
+	if correct != transpose {
+		t.Errorf("Transpose not correct. Got: %v, expected: %v", transpose, correct)
+	}
+
+	var back Mat3x4
+	back.TransposeOf(&transpose)
+	if back != m {
+		t.Errorf("Transpose of the transpose not correct. Got: %v, expected: %v", back, m)
+	}
+}
+
+func TestMat2x4RowsCols(t *testing.T) {
+	t.Parallel()
+	m := Mat2x4FromRows(&Vec4{1, 2, 3, 4}, &Vec4{5, 6, 7, 8})
+	if m.RowLen() != 4 || m.ColLen() != 2 {
+		t.Errorf("Unexpected Mat2x4 dimensions %dx%d", m.ColLen(), m.RowLen())
+	}
+	if col := m.Col(2); col != (Vec2{3, 7}) {
+		t.Errorf("Unexpected Mat2x4 column %v", col)
+	}
+	if m.At(1, 3) != 8 {
+		t.Errorf("Unexpected Mat2x4 element %f", m.At(1, 3))
+	}
+
+	m.SetRow(0, &Vec4{-1, -2, -3, -4})
+	abs := m.Abs()
+	if row := abs.Row(0); row != (Vec4{1, 2, 3, 4}) {
+		t.Errorf("Unexpected Mat2x4 row %v", row)
+	}
+}
+
 func TestAtSet(t *testing.T) {
 	t.Parallel()
 	m := Mat3{
@@ -0,0 +1,36 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestNoise1(t *testing.T) {
+	t.Parallel()
+
+	for i := -10; i < 10; i++ {
+		if n := Noise1(float32(i)); n != 0 {
+			t.Errorf("Noise1(%d) = %f, expected 0", i, n)
+		}
+	}
+
+	nonZero := false
+	for x := float32(-10); x < 10; x += .01 {
+		n := Noise1(x)
+		if n < -1 || n > 1 {
+			t.Errorf("Noise1(%f) = %f out of [-1, 1]", x, n)
+		}
+		if n != 0 {
+			nonZero = true
+		}
+		if d := Abs(Noise1(x+1e-3) - n); d > 1e-2 {
+			t.Errorf("Noise1 isn't smooth at %f: %f", x, d)
+		}
+	}
+	if !nonZero {
+		t.Error("Noise1 is always 0")
+	}
+
+	if Noise1(3.3) != Noise1(3.3) {
+		t.Error("Noise1 isn't deterministic")
+	}
+}
@@ -122,3 +122,89 @@ func UnProject(win *Vec3, modelview, projection *Mat4, initialX, initialY, width
 
 	return obj
 }
+
+// InfinitePerspective returns a Mat4 representing a perspective projection
+// with the far plane at infinity. Like Perspective, depth is mapped to [-1, 1].
+func InfinitePerspective(fovy, aspect, near float32) Mat4 {
+	f := 1. / Tan(fovy/2.0)
+
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, -1, -1,
+		0, 0, -2. * near, 0,
+	}
+}
+
+// ReversedPerspective returns a Mat4 representing a perspective projection
+// mapping the near plane to a depth of 1 and the far plane to a depth of 0.
+// Used with a [0, 1] clip space depth range and a floating point depth buffer,
+// it gives a much better depth precision than Perspective.
+func ReversedPerspective(fovy, aspect, near, far float32) Mat4 {
+	fmn, f := 1/(far-near), 1./Tan(fovy/2.0)
+
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, near * fmn, -1,
+		0, 0, near * far * fmn, 0,
+	}
+}
+
+// InfiniteReversedPerspective is ReversedPerspective with the far plane at
+// infinity.
+func InfiniteReversedPerspective(fovy, aspect, near float32) Mat4 {
+	f := 1. / Tan(fovy/2.0)
+
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, 0, -1,
+		0, 0, near, 0,
+	}
+}
+
+// PerspectiveParams returns the parameters of a symmetric perspective
+// projection built by Perspective, InfinitePerspective, ReversedPerspective or
+// InfiniteReversedPerspective. fovy is in radians. far is +Inf for infinite
+// projections.
+func (m1 *Mat4) PerspectiveParams() (fovy, aspect, near, far float32) {
+	fovy = 2 * Atan(1/m1[5])
+	aspect = m1[5] / m1[0]
+
+	a, b := m1[10], m1[14]
+	if a < 0 {
+		// Depth mapped to [-1, 1], a is -1 for infinite projections.
+		near = b / (a - 1)
+		if a == -1 {
+			far = Inf(1)
+		} else {
+			far = b / (a + 1)
+		}
+	} else {
+		// Reversed depth, a is 0 for infinite projections.
+		near = b / (a + 1)
+		if a == 0 {
+			far = Inf(1)
+		} else {
+			far = b / a
+		}
+	}
+
+	return
+}
+
+// LogDepthCoefficient returns the coefficient used to compute logarithmic
+// depth values for a far plane at distance far, see LogDepth.
+func LogDepthCoefficient(far float32) float32 {
+	return 2. / Log2(far+1.)
+}
+
+// LogDepth returns the logarithmic depth, between 0 and 1, of a point at
+// distance w in front of the camera, w being the clip space w coordinate.
+// Logarithmic depth spreads the depth precision over huge depth ranges, eg.
+// from a few centimeters to thousands of kilometers, at the cost of writing
+// the depth from the fragment shader.
+func LogDepth(w, far float32) float32 {
+	return Log2(1.+w) * LogDepthCoefficient(far) * .5
+}
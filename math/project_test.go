@@ -180,6 +180,125 @@ func TestPerspective(t *testing.T) {
 	}
 }
 
+func TestPerspectiveParams(t *testing.T) {
+	t.Parallel()
+
+	fovy, aspect, near, far := DegToRad(60), float32(16./9.), float32(.5), float32(200)
+	tests := []struct {
+		name string
+		m    Mat4
+		far  float32
+	}{
+		{"Perspective", Perspective(fovy, aspect, near, far), far},
+		{"InfinitePerspective", InfinitePerspective(fovy, aspect, near), Inf(1)},
+		{"ReversedPerspective", ReversedPerspective(fovy, aspect, near, far), far},
+		{"InfiniteReversedPerspective", InfiniteReversedPerspective(fovy, aspect, near), Inf(1)},
+	}
+
+	for _, c := range tests {
+		f, a, n, fa := c.m.PerspectiveParams()
+		if !FloatEqualThreshold(f, fovy, 1e-5) || !FloatEqualThreshold(a, aspect, 1e-5) {
+			t.Errorf("%s: expected fovy %v and aspect %v, got %v and %v", c.name, fovy, aspect, f, a)
+		}
+		if !FloatEqualThreshold(n, near, 1e-4) {
+			t.Errorf("%s: expected near %v, got %v", c.name, near, n)
+		}
+		if IsInf(c.far, 1) != IsInf(fa, 1) || (!IsInf(fa, 1) && !FloatEqualThreshold(fa, c.far, 1e-2)) {
+			t.Errorf("%s: expected far %v, got %v", c.name, c.far, fa)
+		}
+	}
+}
+
+// projectDepth returns the NDC depth of the point at distance d in front of
+// the camera.
+func projectDepth(m *Mat4, d float32) float32 {
+	v := m.Mul4x1(&Vec4{0, 0, -d, 1})
+	return v[2] / v[3]
+}
+
+func TestInfinitePerspective(t *testing.T) {
+	t.Parallel()
+
+	fovy, aspect, near := DegToRad(60), float32(16./9.), float32(.1)
+	m := InfinitePerspective(fovy, aspect, near)
+	p := Perspective(fovy, aspect, near, 1e6)
+
+	if !m.EqualThreshold(&p, 1e-4) {
+		t.Errorf("InfinitePerspective should be close to a Perspective with a far away far plane (got %v, %v)", m, p)
+	}
+	if d := projectDepth(&m, near); !FloatEqualThreshold(d, -1, 1e-5) {
+		t.Errorf("near plane depth: expected -1, got %v", d)
+	}
+	if d := projectDepth(&m, 1e7); !FloatEqualThreshold(d, 1, 1e-5) {
+		t.Errorf("far away depth: expected 1, got %v", d)
+	}
+}
+
+func TestReversedPerspective(t *testing.T) {
+	t.Parallel()
+
+	fovy, aspect, near, far := DegToRad(60), float32(4./3.), float32(.5), float32(100)
+	m := ReversedPerspective(fovy, aspect, near, far)
+
+	if d := projectDepth(&m, near); !FloatEqualThreshold(d, 1, 1e-5) {
+		t.Errorf("near plane depth: expected 1, got %v", d)
+	}
+	if d := projectDepth(&m, far); !FloatEqualThreshold(d, 0, 1e-5) {
+		t.Errorf("far plane depth: expected 0, got %v", d)
+	}
+	if a, b := projectDepth(&m, 10), projectDepth(&m, 20); a <= b {
+		t.Errorf("depth should decrease with distance (got %v, %v)", a, b)
+	}
+
+	// Same x and y as the regular projection.
+	p := Perspective(fovy, aspect, near, far)
+	for _, i := range []int{0, 5, 11} {
+		if m[i] != p[i] {
+			t.Errorf("m[%d]: expected %v, got %v", i, p[i], m[i])
+		}
+	}
+}
+
+func TestInfiniteReversedPerspective(t *testing.T) {
+	t.Parallel()
+
+	fovy, aspect, near := DegToRad(60), float32(4./3.), float32(.5)
+	m := InfiniteReversedPerspective(fovy, aspect, near)
+	r := ReversedPerspective(fovy, aspect, near, 1e7)
+
+	if !m.EqualThreshold(&r, 1e-3) {
+		t.Errorf("InfiniteReversedPerspective should be close to a ReversedPerspective with a far away far plane (got %v, %v)", m, r)
+	}
+	if d := projectDepth(&m, near); !FloatEqualThreshold(d, 1, 1e-5) {
+		t.Errorf("near plane depth: expected 1, got %v", d)
+	}
+	if d := projectDepth(&m, 1e7); !FloatEqualThreshold(d, 0, 1e-3) {
+		t.Errorf("far away depth: expected 0, got %v", d)
+	}
+}
+
+func TestLogDepth(t *testing.T) {
+	t.Parallel()
+
+	far := float32(1e7)
+	if d := LogDepth(0, far); d != 0 {
+		t.Errorf("LogDepth(0) != 0 (got %v)", d)
+	}
+	if d := LogDepth(far, far); !FloatEqualThreshold(d, 1, 1e-5) {
+		t.Errorf("LogDepth(far) != 1 (got %v)", d)
+	}
+
+	// Depth keeps increasing, even close to the far plane.
+	previous := float32(0)
+	for w := float32(1); w < far; w *= 10 {
+		d := LogDepth(w, far)
+		if d <= previous {
+			t.Errorf("LogDepth(%v) = %v should be more than %v", w, d, previous)
+		}
+		previous = d
+	}
+}
+
 func TestFrustum(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -24,6 +24,9 @@ const (
 	ZXY
 )
 
+// Below this, sin(angle) and angles are considered 0 by Log and Exp.
+const quatEpsilon = 1e-6
+
 // Quat is a Quaternion. A Quaternion is an extension of the imaginary numbers.
 // In 3D graphics we mostly use it as a cheap way of representing rotation since
 // quaternions are cheaper to multiply by, and easier to interpolate than
@@ -61,6 +64,30 @@ func QuatRotate(angle float32, axis *Vec3) Quaternion {
 	return Quaternion{c, axis.Mul(s)}
 }
 
+// QuatFromAxisAngle creates the rotation of angle radians around axis. Unlike
+// QuatRotate, axis doesn't need to be normalized. It's the inverse of Axis and
+// Angle.
+func QuatFromAxisAngle(axis *Vec3, angle float32) Quaternion {
+	n := axis.Normalized()
+	return QuatRotate(angle, &n)
+}
+
+// Axis returns the normalized axis of the rotation represented by q1. The
+// identity rotation has no axis, Axis returns the X axis in that case.
+func (q1 *Quaternion) Axis() Vec3 {
+	l := q1.V.Len()
+	if l < quatEpsilon {
+		return Vec3{1, 0, 0}
+	}
+	return q1.V.Mul(1 / l)
+}
+
+// Angle returns the angle, in radians and in [0, 2π], of the rotation
+// represented by q1 around Axis. q1 is expected to be normalized.
+func (q1 *Quaternion) Angle() float32 {
+	return 2 * Atan2(q1.V.Len(), q1.W)
+}
+
 // Iden sets this quaternion to the identity quaternion.
 func (q1 *Quaternion) Iden() {
 	q1.W = 1
@@ -296,6 +323,26 @@ func (q1 *Quaternion) Inverse() Quaternion {
 	return c.Scale(1 / q1.Dot(q1))
 }
 
+// Log returns the logarithm of a unit quaternion: a pure quaternion (W = 0)
+// whose vector part is the rotation axis scaled by half the rotation angle.
+func (q1 *Quaternion) Log() Quaternion {
+	theta := Acos(Clamp(q1.W, -1, 1))
+	sin := Sin(theta)
+	if Abs(sin) < quatEpsilon {
+		return Quaternion{0, q1.V}
+	}
+	return Quaternion{0, q1.V.Mul(theta / sin)}
+}
+
+// Exp returns the exponential of a pure quaternion, the inverse of Log.
+func (q1 *Quaternion) Exp() Quaternion {
+	theta := q1.V.Len()
+	if theta < quatEpsilon {
+		return Quaternion{Cos(theta), q1.V}
+	}
+	return Quaternion{Cos(theta), q1.V.Mul(Sin(theta) / theta)}
+}
+
 // InverseOf is a memory friendly version of Inverse.
 func (q1 *Quaternion) InverseOf(q2 *Quaternion) {
 	q1.ConjugateOf(q2)
@@ -340,6 +387,22 @@ func (q1 *Quaternion) Rotate(v *Vec3) Vec3 {
 	return out
 }
 
+// Forward returns the direction an object rotated by q1 faces. Like cameras,
+// objects face Z- when not rotated.
+func (q1 *Quaternion) Forward() Vec3 {
+	return q1.Rotate(&Vec3{0, 0, -1})
+}
+
+// Up returns the Y+ axis rotated by q1.
+func (q1 *Quaternion) Up() Vec3 {
+	return q1.Rotate(&Vec3{0, 1, 0})
+}
+
+// Right returns the X+ axis rotated by q1.
+func (q1 *Quaternion) Right() Vec3 {
+	return q1.Rotate(&Vec3{1, 0, 0})
+}
+
 // RotateByVector ... I'm actually not sure what this does. This isn't called by
 // tornago... so I'm not sure why it's here.
 //func (q1 *Quat) RotateByVector(v1 *Vec3) {
@@ -349,6 +412,9 @@ func (q1 *Quaternion) Rotate(v *Vec3) Vec3 {
 
 // AddScaledVec takes an input vector and scaled it by f then adds that rotation
 // to q1.
+//
+// This is a first order integration of the angular velocity v1 over f seconds,
+// see Derivative. Integrate is exact and keeps q1 normalized.
 func (q1 *Quaternion) AddScaledVec(f float32, v1 *Vec3) {
 	q2 := Quaternion{0, Vec3{v1[0] * f, v1[1] * f, v1[2] * f}}
 	q2.MulWith(q1)
@@ -358,6 +424,38 @@ func (q1 *Quaternion) AddScaledVec(f float32, v1 *Vec3) {
 	q1.V[2] += q2.V[2] * 0.5
 }
 
+// Derivative returns the time derivative of the orientation q1 rotating with
+// the world space angular velocity, in radians per second: 0.5 * ω * q1.
+func (q1 *Quaternion) Derivative(angularVelocity *Vec3) Quaternion {
+	w := Quaternion{0, *angularVelocity}
+	d := w.Mul(q1)
+	return d.Scale(.5)
+}
+
+// Integrate advances the orientation q1 by dt seconds of rotation with the
+// world space angular velocity, in radians per second. Unlike the first order
+// AddScaledVec, the rotation is exact for a constant angular velocity and the
+// result stays normalized.
+func (q1 *Quaternion) Integrate(angularVelocity *Vec3, dt float32) {
+	half := Quaternion{0, angularVelocity.Mul(.5 * dt)}
+	r := half.Exp()
+	r.MulWith(q1)
+	*q1 = r.Normalized()
+}
+
+// QuatAngularVelocity returns the constant world space angular velocity, in
+// radians per second, rotating from to to in dt seconds along the shortest
+// path. It's the inverse of Integrate.
+func QuatAngularVelocity(from, to *Quaternion, dt float32) Vec3 {
+	inv := from.Inverse()
+	delta := to.Mul(&inv)
+	if delta.W < 0 {
+		delta.ScaleWith(-1)
+	}
+	l := delta.Log()
+	return l.V.Mul(2 / dt)
+}
+
 // Mat4 returns the homogeneous 3D rotation matrix corresponding to the
 // quaternion. with last row and last column as [0 0 0 1]
 func (q1 *Quaternion) Mat4() Mat4 {
@@ -381,6 +479,14 @@ func (q1 *Quaternion) Mat3() Mat3 {
 	}
 }
 
+// Mat3x4 returns the 3x4 matrix corresponding to the rotation, with no
+// translation.
+func (q1 *Quaternion) Mat3x4() Mat3x4 {
+	var m Mat3x4
+	m.SetOrientation(q1)
+	return m
+}
+
 // Dot returns the dot product between two quaternions.
 func (q1 *Quaternion) Dot(q2 *Quaternion) float32 {
 	return q1.W*q2.W + q1.V[0]*q2.V[0] + q1.V[1]*q2.V[1] + q1.V[2]*q2.V[2]
@@ -651,6 +757,32 @@ func QuatLookAtV(eye, center, up *Vec3) Quaternion {
 	return rotUp.Mul(&rotDir) // remember, in reverse order.
 }
 
+// QuatFromAxes creates the rotation transforming the X, Y and Z axis into x, y
+// and z. The axes are expected to be an orthonormal, right-handed, basis.
+func QuatFromAxes(x, y, z *Vec3) Quaternion {
+	m := Mat4{
+		x[0], x[1], x[2], 0,
+		y[0], y[1], y[2], 0,
+		z[0], z[1], z[2], 0,
+		0, 0, 0, 1,
+	}
+	return Mat4ToQuat(&m)
+}
+
+// QuatFromForwardUp creates the rotation orienting an object towards forward,
+// keeping its up as close as possible to up. Like QuatLookAtV, it assumes the
+// front of the object at Z- and its up at Y+. forward and up don't need to be
+// normalized or orthogonal but they must not be colinear.
+func QuatFromForwardUp(forward, up *Vec3) Quaternion {
+	z := forward.Mul(-1)
+	z.Normalize()
+	x := up.Cross(&z)
+	x.Normalize()
+	y := z.Cross(&x)
+
+	return QuatFromAxes(&x, &y, &z)
+}
+
 // QuatBetweenVectors calculates the rotation between two vectors
 func QuatBetweenVectors(start, dest *Vec3) Quaternion {
 	const epsilon = 0.001
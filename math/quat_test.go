@@ -1116,3 +1116,201 @@ func TestQuat_Mat4(t *testing.T) {
 		}
 	}
 }
+
+func TestQuat_Mat3x4(t *testing.T) {
+	t.Parallel()
+	q := AnglesToQuat(.3, -1.2, .7, XYZ)
+	m4 := q.Mat4()
+	if m := q.Mat3x4(); m != m4.Mat3x4() {
+		t.Errorf("Mat3x4() = %v, want %v", m, m4.Mat3x4())
+	}
+
+	// SetOrientation keeps the position.
+	m := Mat3x4{9: 1, 10: 2, 11: 3}
+	m.SetOrientation(&q)
+	expected := m4.Mat3x4()
+	expected[9], expected[10], expected[11] = 1, 2, 3
+	if m != expected {
+		t.Errorf("SetOrientation() = %v, want %v", m, expected)
+	}
+}
+
+func TestQuatFromAxes(t *testing.T) {
+	t.Parallel()
+	q := AnglesToQuat(.3, -1.2, .7, XYZ)
+	m := q.Mat3()
+	x, y, z := m.Cols()
+	if r := QuatFromAxes(&x, &y, &z); !r.OrientationEqualThreshold(&q, 1e-4) {
+		t.Errorf("QuatFromAxes(%v, %v, %v) = %v, want %v", x, y, z, r, q)
+	}
+}
+
+func TestQuatFromForwardUp(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		Description string
+		Forward, Up *Vec3
+	}{
+		{"forward", &Vec3{0, 0, -1}, &Vec3{0, 1, 0}},
+		{"heading 90 degree", &Vec3{1, 0, 0}, &Vec3{0, 1, 0}},
+		{"heading 180 degree", &Vec3{0, 0, 1}, &Vec3{0, 1, 0}},
+		{"attitude 90 degree", &Vec3{0, 0, -1}, &Vec3{1, 0, 0}},
+		{"bank 90 degree", &Vec3{0, -1, 0}, &Vec3{0, 0, -1}},
+		{"non orthogonal up", &Vec3{1, 0, -1}, &Vec3{0, 1, 1}},
+	}
+
+	for _, c := range tests {
+		r := QuatFromForwardUp(c.Forward, c.Up)
+
+		// QuatLookAtV doesn't make up perpendicular to forward.
+		if c.Forward.Dot(c.Up) == 0 {
+			if expected := QuatLookAtV(&Vec3{}, c.Forward, c.Up); !r.OrientationEqualThreshold(&expected, 1e-3) {
+				t.Errorf("%v failed: QuatFromForwardUp(%v, %v) = %v, want %v", c.Description, c.Forward, c.Up, r, expected)
+			}
+		}
+
+		// The object front, Z-, is rotated towards forward.
+		front := r.Rotate(&Vec3{0, 0, -1})
+		expected := c.Forward.Normalized()
+		if !front.EqualThreshold(&expected, 1e-3) {
+			t.Errorf("%v failed: front %v, want %v", c.Description, front, expected)
+		}
+
+		// The object up, Y+, is in the (forward, up) plane, on the up side.
+		top := r.Rotate(&Vec3{0, 1, 0})
+		normal := c.Forward.Cross(c.Up)
+		if top.Dot(c.Up) <= 0 || Abs(top.Dot(&normal)) > 1e-3 {
+			t.Errorf("%v failed: up %v isn't towards %v", c.Description, top, c.Up)
+		}
+	}
+}
+
+func TestQuat_LogExp(t *testing.T) {
+	t.Parallel()
+	axis := Vec3{1, 2, 3}
+	axis.Normalize()
+	q := QuatRotate(DegToRad(70), &axis)
+
+	l := q.Log()
+	expected := axis.Mul(DegToRad(35))
+	if l.W != 0 || !l.V.EqualThreshold(&expected, 1e-4) {
+		t.Errorf("Log() = %v, want {0 %v}", l, expected)
+	}
+	if e := l.Exp(); !e.EqualThreshold(&q, 1e-4) {
+		t.Errorf("Log().Exp() = %v, want %v", e, q)
+	}
+
+	ident := QuatIdent()
+	if l := ident.Log(); l != (Quaternion{}) {
+		t.Errorf("identity Log() = %v", l)
+	}
+	if e := (&Quaternion{}).Exp(); e != ident {
+		t.Errorf("zero Exp() = %v", e)
+	}
+}
+
+func TestQuat_Integrate(t *testing.T) {
+	t.Parallel()
+	q := AnglesToQuat(.3, -1.2, .7, XYZ)
+	angularVelocity := Vec3{0, Pi / 2, 0}
+
+	// 1s at π/2 rad/s around Y, in 100 steps.
+	integrated := q
+	for i := 0; i < 100; i++ {
+		integrated.Integrate(&angularVelocity, .01)
+	}
+	r := QuatRotate(Pi/2, &Vec3{0, 1, 0})
+	expected := r.Mul(&q)
+	if !integrated.OrientationEqualThreshold(&expected, 1e-4) {
+		t.Errorf("Integrate() = %v, want %v", integrated, expected)
+	}
+	if l := integrated.Len(); !FloatEqualThreshold(l, 1, 1e-5) {
+		t.Errorf("integrated quaternion has length %f", l)
+	}
+
+	// Back to the angular velocity.
+	if w := QuatAngularVelocity(&q, &integrated, 1); !w.EqualThreshold(&angularVelocity, 1e-3) {
+		t.Errorf("QuatAngularVelocity() = %v, want %v", w, angularVelocity)
+	}
+
+	// The first order AddScaledVec follows the derivative.
+	d := q.Derivative(&angularVelocity)
+	step := q
+	step.AddScaledVec(1e-3, &angularVelocity)
+	d.ScaleWith(1e-3)
+	expected = q.Add(&d)
+	if !step.EqualThreshold(&expected, 1e-6) {
+		t.Errorf("AddScaledVec() = %v, want %v", step, expected)
+	}
+}
+
+func TestQuat_AxisAngle(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		axis  Vec3
+		angle float32
+	}{
+		{Vec3{0, 1, 0}, Pi / 2},
+		{Vec3{1, 2, 3}, .3},
+		{Vec3{-1, 0, 1}, 1.5 * Pi},
+	}
+
+	for _, test := range tests {
+		q := QuatFromAxisAngle(&test.axis, test.angle)
+		if l := q.Len(); !FloatEqualThreshold(l, 1, 1e-6) {
+			t.Errorf("QuatFromAxisAngle(%v, %f) has length %f", test.axis, test.angle, l)
+		}
+
+		axis, expected := q.Axis(), test.axis.Normalized()
+		if !axis.EqualThreshold(&expected, 1e-5) {
+			t.Errorf("Axis() = %v, want %v", axis, expected)
+		}
+		if angle := q.Angle(); !FloatEqualThreshold(angle, test.angle, 1e-5) {
+			t.Errorf("Angle() = %f, want %f", angle, test.angle)
+		}
+	}
+
+	ident := QuatIdent()
+	if angle := ident.Angle(); angle != 0 {
+		t.Errorf("identity Angle() = %f", angle)
+	}
+	if axis := ident.Axis(); axis != (Vec3{1, 0, 0}) {
+		t.Errorf("identity Axis() = %v", axis)
+	}
+}
+
+func TestQuat_Directions(t *testing.T) {
+	t.Parallel()
+	ident := QuatIdent()
+	if f := ident.Forward(); f != (Vec3{0, 0, -1}) {
+		t.Errorf("identity Forward() = %v", f)
+	}
+
+	// Turning left.
+	q := QuatRotate(Pi/2, &Vec3{0, 1, 0})
+	tests := []struct {
+		name          string
+		got, expected Vec3
+	}{
+		{"Forward", q.Forward(), Vec3{-1, 0, 0}},
+		{"Up", q.Up(), Vec3{0, 1, 0}},
+		{"Right", q.Right(), Vec3{0, 0, -1}},
+	}
+	for _, test := range tests {
+		if !test.got.EqualThreshold(&test.expected, 1e-3) {
+			t.Errorf("%s() = %v, want %v", test.name, test.got, test.expected)
+		}
+	}
+
+	// The directions are consistent with QuatFromForwardUp.
+	forward, up := Vec3{1, 2, -3}, Vec3{0, 1, 0}
+	q = QuatFromForwardUp(&forward, &up)
+	f, expected := q.Forward(), forward.Normalized()
+	if !f.EqualThreshold(&expected, 1e-5) {
+		t.Errorf("Forward() = %v, want %v", f, expected)
+	}
+	right := q.Right()
+	if Abs(right[1]) > 1e-6 {
+		t.Errorf("Right() = %v isn't horizontal", right)
+	}
+}
@@ -0,0 +1,175 @@
+package math
+
+// Ray is a half-line starting at Origin and going in the Direction direction.
+// Direction is expected to be normalized so distances along the ray are
+// actual distances.
+type Ray struct {
+	Origin    Vec3
+	Direction Vec3
+}
+
+// At returns the point at distance t along the ray.
+func (r *Ray) At(t float32) Vec3 {
+	d := r.Direction.Mul(t)
+	return r.Origin.Add(&d)
+}
+
+// Transform returns the ray transformed by m. The direction of the result is
+// normalized again, so distances are measured in the new space.
+func (r *Ray) Transform(m *Mat4) Ray {
+	o := m.Mul4x1(&Vec4{r.Origin[0], r.Origin[1], r.Origin[2], 1})
+	d := m.Mul4x1(&Vec4{r.Direction[0], r.Direction[1], r.Direction[2], 0})
+	d3 := d.Vec3()
+	return Ray{
+		Origin:    o.Vec3(),
+		Direction: d3.Normalized(),
+	}
+}
+
+// ScreenRay returns the ray going through the point (x, y) of a viewport of
+// size width x height, in world space. (x, y) are window coordinates, with the
+// origin at the top left corner. projection and view are the camera
+// projection and view matrices.
+//
+// For perspective projections, the ray starts at the camera position. For
+// orthographic ones, it starts on the plane of the camera. Both standard and
+// reversed depth ranges, with finite or infinite far planes, are supported.
+func ScreenRay(x, y float32, width, height int, projection, view *Mat4) Ray {
+	ndcX := 2*x/float32(width) - 1
+	ndcY := 1 - 2*y/float32(height)
+
+	// Unproject two points inside the view volume, whatever the depth
+	// convention, to view space.
+	inverse := projection.Inverse()
+	unproject := func(z float32) Vec3 {
+		p := inverse.Mul4x1(&Vec4{ndcX, ndcY, z, 1})
+		return Vec3{p[0] / p[3], p[1] / p[3], p[2] / p[3]}
+	}
+	p1, p2 := unproject(.25), unproject(.5)
+
+	// The camera looks down -z.
+	d := p2.Sub(&p1)
+	if d[2] > 0 {
+		d = d.Mul(-1)
+	}
+	d.Normalize()
+
+	// Start the ray on the z = 0 plane, ie. at the camera position for
+	// perspective projections.
+	r := Ray{Origin: p1, Direction: d}
+	r.Origin = r.At(-p1[2] / d[2])
+
+	viewInverse := view.Inverse()
+	return r.Transform(&viewInverse)
+}
+
+// Below rayEpsilon, rays are considered parallel to planes and triangles.
+const rayEpsilon = 1e-8
+
+// IntersectPlane intersects the ray with the plane of points p satisfying
+// dot(normal, p) = distance. It returns the distance along the ray and the
+// point of the intersection. ok is false when the ray is parallel to the plane
+// or points away from it.
+func (r *Ray) IntersectPlane(normal *Vec3, distance float32) (t float32, point Vec3, ok bool) {
+	denom := normal.Dot(&r.Direction)
+	if Abs(denom) < rayEpsilon {
+		return 0, Vec3{}, false
+	}
+
+	t = (distance - normal.Dot(&r.Origin)) / denom
+	if t < 0 {
+		return 0, Vec3{}, false
+	}
+	return t, r.At(t), true
+}
+
+// IntersectSphere intersects the ray with a sphere. It returns the distance
+// along the ray and the point of the first intersection. A ray starting inside
+// the sphere hits it on its way out.
+func (r *Ray) IntersectSphere(center *Vec3, radius float32) (t float32, point Vec3, ok bool) {
+	// Solve |o + t.d - c|² = r², with |d| = 1.
+	oc := r.Origin.Sub(center)
+	b := oc.Dot(&r.Direction)
+	c := oc.Dot(&oc) - radius*radius
+	delta := b*b - c
+	if delta < 0 {
+		return 0, Vec3{}, false
+	}
+
+	sqrtDelta := Sqrt(delta)
+	t = -b - sqrtDelta
+	if t < 0 {
+		t = -b + sqrtDelta
+	}
+	if t < 0 {
+		return 0, Vec3{}, false
+	}
+	return t, r.At(t), true
+}
+
+// IntersectAABB intersects the ray with the axis aligned box (min, max). It
+// returns the distance along the ray and the point of the first intersection. A
+// ray starting inside the box hits it at distance 0.
+func (r *Ray) IntersectAABB(min, max *Vec3) (t float32, point Vec3, ok bool) {
+	// Slab method: intersect the ray with the 3 pairs of planes bounding the
+	// box and keep the overlap of the 3 intervals.
+	tmin, tmax := float32(0), float32(MaxFloat32)
+
+	for i := 0; i < 3; i++ {
+		if r.Direction[i] == 0 {
+			// Parallel to the slab, the origin has to be inside.
+			if r.Origin[i] < min[i] || r.Origin[i] > max[i] {
+				return 0, Vec3{}, false
+			}
+			continue
+		}
+
+		inv := 1 / r.Direction[i]
+		t1 := (min[i] - r.Origin[i]) * inv
+		t2 := (max[i] - r.Origin[i]) * inv
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tmin = Max(tmin, t1)
+		tmax = Min(tmax, t2)
+		if tmin > tmax {
+			return 0, Vec3{}, false
+		}
+	}
+
+	return tmin, r.At(tmin), true
+}
+
+// IntersectTriangle intersects the ray with the triangle (a, b, c), whatever
+// its winding. It returns the distance along the ray and the point of the
+// intersection.
+func (r *Ray) IntersectTriangle(a, b, c *Vec3) (t float32, point Vec3, ok bool) {
+	// Möller–Trumbore.
+	e1 := b.Sub(a)
+	e2 := c.Sub(a)
+	p := r.Direction.Cross(&e2)
+	det := e1.Dot(&p)
+	if Abs(det) < rayEpsilon {
+		// Parallel to the triangle plane.
+		return 0, Vec3{}, false
+	}
+	invDet := 1 / det
+
+	s := r.Origin.Sub(a)
+	u := s.Dot(&p) * invDet
+	if u < 0 || u > 1 {
+		return 0, Vec3{}, false
+	}
+
+	q := s.Cross(&e1)
+	v := r.Direction.Dot(&q) * invDet
+	if v < 0 || u+v > 1 {
+		return 0, Vec3{}, false
+	}
+
+	t = e2.Dot(&q) * invDet
+	if t < 0 {
+		return 0, Vec3{}, false
+	}
+	return t, r.At(t), true
+}
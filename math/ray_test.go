@@ -0,0 +1,192 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestRayAt(t *testing.T) {
+	t.Parallel()
+
+	r := Ray{Origin: Vec3{1, 2, 3}, Direction: Vec3{0, 0, -1}}
+	if p := r.At(2); !p.EqualThreshold(&Vec3{1, 2, 1}, 1e-3) {
+		t.Errorf("At(2): expected (1, 2, 1), got %v", p)
+	}
+}
+
+func TestScreenRay(t *testing.T) {
+	t.Parallel()
+
+	// Camera at (0, 0, 10), looking at the origin.
+	view := Translate3D(0, 0, -10)
+	projections := []Mat4{
+		Perspective(DegToRad(90), 1, .1, 100),
+		InfinitePerspective(DegToRad(90), 1, .1),
+		ReversedPerspective(DegToRad(90), 1, .1, 100),
+		InfiniteReversedPerspective(DegToRad(90), 1, .1),
+	}
+
+	for i := range projections {
+		// Center of the screen.
+		r := ScreenRay(400, 300, 800, 600, &projections[i], &view)
+		if !r.Origin.EqualThreshold(&Vec3{0, 0, 10}, 1e-3) {
+			t.Errorf("projection %d: origin: expected (0, 0, 10), got %v", i, r.Origin)
+		}
+		if !r.Direction.EqualThreshold(&Vec3{0, 0, -1}, 1e-3) {
+			t.Errorf("projection %d: direction: expected (0, 0, -1), got %v", i, r.Direction)
+		}
+
+		// Top right corner, 45° up and right.
+		r = ScreenRay(800, 0, 800, 800, &projections[i], &view)
+		expected := Vec3{1, 1, -1}
+		expected.Normalize()
+		if !r.Direction.EqualThreshold(&expected, 1e-3) {
+			t.Errorf("projection %d: direction: expected %v, got %v", i, expected, r.Direction)
+		}
+	}
+
+	// Orthographic projections start the ray on the camera plane.
+	ortho := Ortho(-10, 10, -10, 10, .1, 100)
+	r := ScreenRay(150, 50, 200, 200, &ortho, &view)
+	if !r.Origin.EqualThreshold(&Vec3{5, 5, 10}, 1e-3) {
+		t.Errorf("ortho origin: expected (5, 5, 10), got %v", r.Origin)
+	}
+	if !r.Direction.EqualThreshold(&Vec3{0, 0, -1}, 1e-3) {
+		t.Errorf("ortho direction: expected (0, 0, -1), got %v", r.Direction)
+	}
+}
+
+func TestRayIntersectPlane(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ray      Ray
+		normal   Vec3
+		distance float32
+		hit      bool
+		t        float32
+	}{
+		{Ray{Vec3{0, 5, 0}, Vec3{0, -1, 0}}, Vec3{0, 1, 0}, 1, true, 4},
+		{Ray{Vec3{0, 5, 0}, Vec3{0, 1, 0}}, Vec3{0, 1, 0}, 1, false, 0},
+		{Ray{Vec3{0, 5, 0}, Vec3{1, 0, 0}}, Vec3{0, 1, 0}, 1, false, 0},
+		// From below.
+		{Ray{Vec3{0, -5, 0}, Vec3{0, 1, 0}}, Vec3{0, 1, 0}, 1, true, 6},
+	}
+
+	for _, test := range tests {
+		d, p, hit := test.ray.IntersectPlane(&test.normal, test.distance)
+		if hit != test.hit {
+			t.Errorf("%v: expected hit %v", test.ray, test.hit)
+			continue
+		}
+		if !hit {
+			continue
+		}
+		if !FloatEqualThreshold(d, test.t, 1e-5) {
+			t.Errorf("%v: expected distance %v, got %v", test.ray, test.t, d)
+		}
+		if n := test.normal.Dot(&p); !FloatEqualThreshold(n, test.distance, 1e-5) {
+			t.Errorf("%v: hit point %v isn't on the plane", test.ray, p)
+		}
+	}
+}
+
+func TestRayIntersectSphere(t *testing.T) {
+	t.Parallel()
+
+	center := Vec3{0, 0, -10}
+	tests := []struct {
+		ray   Ray
+		hit   bool
+		t     float32
+		point Vec3
+	}{
+		{Ray{Vec3{0, 0, 0}, Vec3{0, 0, -1}}, true, 8, Vec3{0, 0, -8}},
+		{Ray{Vec3{0, 0, 0}, Vec3{0, 0, 1}}, false, 0, Vec3{}},
+		{Ray{Vec3{0, 3, 0}, Vec3{0, 0, -1}}, false, 0, Vec3{}},
+		// Tangent.
+		{Ray{Vec3{0, 2, 0}, Vec3{0, 0, -1}}, true, 10, Vec3{0, 2, -10}},
+		// From the inside.
+		{Ray{Vec3{0, 0, -10}, Vec3{1, 0, 0}}, true, 2, Vec3{2, 0, -10}},
+	}
+
+	for _, test := range tests {
+		d, p, hit := test.ray.IntersectSphere(&center, 2)
+		if hit != test.hit {
+			t.Errorf("%v: expected hit %v", test.ray, test.hit)
+			continue
+		}
+		if !hit {
+			continue
+		}
+		if !FloatEqualThreshold(d, test.t, 1e-5) || !p.EqualThreshold(&test.point, 1e-3) {
+			t.Errorf("%v: expected (%v, %v), got (%v, %v)", test.ray, test.t, test.point, d, p)
+		}
+	}
+}
+
+func TestRayIntersectAABB(t *testing.T) {
+	t.Parallel()
+
+	min, max := Vec3{-1, -1, -1}, Vec3{1, 1, 1}
+	tests := []struct {
+		ray Ray
+		hit bool
+		t   float32
+	}{
+		{Ray{Vec3{0, 0, 5}, Vec3{0, 0, -1}}, true, 4},
+		{Ray{Vec3{0, 0, 5}, Vec3{0, 0, 1}}, false, 0},
+		{Ray{Vec3{2, 0, 5}, Vec3{0, 0, -1}}, false, 0},
+		{Ray{Vec3{0, 0, 0}, Vec3{1, 0, 0}}, true, 0},
+		{Ray{Vec3{-5, -5, 0}, Vec3{0.7071068, 0.7071068, 0}}, true, 5.656854},
+	}
+
+	for _, test := range tests {
+		d, p, hit := test.ray.IntersectAABB(&min, &max)
+		if hit != test.hit {
+			t.Errorf("%v: expected hit %v", test.ray, test.hit)
+			continue
+		}
+		if !hit {
+			continue
+		}
+		if !FloatEqualThreshold(d, test.t, 1e-4) {
+			t.Errorf("%v: expected distance %v, got %v", test.ray, test.t, d)
+		}
+		if expected := test.ray.At(test.t); !p.EqualThreshold(&expected, 1e-3) {
+			t.Errorf("%v: expected point %v, got %v", test.ray, expected, p)
+		}
+	}
+}
+
+func TestRayIntersectTriangle(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := Vec3{0, 0, -5}, Vec3{2, 0, -5}, Vec3{0, 2, -5}
+	tests := []struct {
+		ray   Ray
+		hit   bool
+		point Vec3
+	}{
+		{Ray{Vec3{.5, .5, 0}, Vec3{0, 0, -1}}, true, Vec3{.5, .5, -5}},
+		// Back face.
+		{Ray{Vec3{.5, .5, -10}, Vec3{0, 0, 1}}, true, Vec3{.5, .5, -5}},
+		// Outside, past the hypotenuse.
+		{Ray{Vec3{1.5, 1.5, 0}, Vec3{0, 0, -1}}, false, Vec3{}},
+		{Ray{Vec3{-.5, .5, 0}, Vec3{0, 0, -1}}, false, Vec3{}},
+		// Behind the ray origin.
+		{Ray{Vec3{.5, .5, 0}, Vec3{0, 0, 1}}, false, Vec3{}},
+		// Parallel.
+		{Ray{Vec3{.5, .5, -5}, Vec3{1, 0, 0}}, false, Vec3{}},
+	}
+
+	for _, test := range tests {
+		_, p, hit := test.ray.IntersectTriangle(&a, &b, &c)
+		if hit != test.hit {
+			t.Errorf("%v: expected hit %v", test.ray, test.hit)
+			continue
+		}
+		if hit && !p.EqualThreshold(&test.point, 1e-3) {
+			t.Errorf("%v: expected point %v, got %v", test.ray, test.point, p)
+		}
+	}
+}
@@ -0,0 +1,512 @@
+package math
+
+import (
+	"flag"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// The reference tests cross-check the float32, hand-unrolled, functions of
+// this package against a straightforward float64 implementation on randomized
+// inputs. They catch the sign and index errors that are easy to make when
+// unrolling matrix code.
+//
+// A quick run is part of the normal test suite. Use the flags below for a
+// longer run or to reproduce a failure, eg.:
+//
+//	go test -run Reference -reference.n 1000000 -reference.seed 42
+var (
+	referenceN    = flag.Int("reference.n", 1000, "number of random inputs checked by the reference tests")
+	referenceSeed = flag.Int64("reference.seed", 1, "seed of the random inputs of the reference tests")
+)
+
+// Matrices with a condition number above this are too ill-conditioned for a
+// float32 inverse to be compared with the reference.
+const referenceMaxCond = 1e4
+
+// refMatrix is a float64, column-major, matrix.
+type refMatrix struct {
+	rows, cols int
+	m          []float64
+}
+
+func newRefMatrix(rows, cols int, m []float32) refMatrix {
+	r := refMatrix{rows, cols, make([]float64, rows*cols)}
+	for i := range m {
+		r.m[i] = float64(m[i])
+	}
+	return r
+}
+
+func (r refMatrix) at(row, col int) float64 { return r.m[col*r.rows+row] }
+
+func (r refMatrix) set(row, col int, v float64) { r.m[col*r.rows+row] = v }
+
+func (r refMatrix) mul(o refMatrix) refMatrix {
+	res := refMatrix{r.rows, o.cols, make([]float64, r.rows*o.cols)}
+	for i := 0; i < r.rows; i++ {
+		for j := 0; j < o.cols; j++ {
+			var sum float64
+			for k := 0; k < r.cols; k++ {
+				sum += r.at(i, k) * o.at(k, j)
+			}
+			res.set(i, j, sum)
+		}
+	}
+	return res
+}
+
+// affine extends a 3x4 matrix to a 4x4 one with a [0 0 0 1] last row.
+func (r refMatrix) affine() refMatrix {
+	res := refMatrix{4, 4, make([]float64, 16)}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			res.set(i, j, r.at(i, j))
+		}
+	}
+	res.set(3, 3, 1)
+	return res
+}
+
+// affine2 extends a 2x3 matrix to a 3x3 one with a [0 0 1] last row.
+func (r refMatrix) affine2() refMatrix {
+	res := refMatrix{3, 3, make([]float64, 9)}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			res.set(i, j, r.at(i, j))
+		}
+	}
+	res.set(2, 2, 1)
+	return res
+}
+
+// top returns the first n rows of r.
+func (r refMatrix) top(n int) refMatrix {
+	res := refMatrix{n, r.cols, make([]float64, n*r.cols)}
+	for i := 0; i < n; i++ {
+		for j := 0; j < r.cols; j++ {
+			res.set(i, j, r.at(i, j))
+		}
+	}
+	return res
+}
+
+// lu computes the LU decomposition of a square matrix with partial pivoting.
+// It returns the decomposition, the row permutation and its sign.
+func (r refMatrix) lu() (refMatrix, []int, float64) {
+	n := r.rows
+	a := refMatrix{n, n, append([]float64(nil), r.m...)}
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := 1.0
+
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a.at(i, k)) > math.Abs(a.at(p, k)) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				tmp := a.at(k, j)
+				a.set(k, j, a.at(p, j))
+				a.set(p, j, tmp)
+			}
+			perm[k], perm[p] = perm[p], perm[k]
+			sign = -sign
+		}
+		if a.at(k, k) == 0 {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			f := a.at(i, k) / a.at(k, k)
+			a.set(i, k, f)
+			for j := k + 1; j < n; j++ {
+				a.set(i, j, a.at(i, j)-f*a.at(k, j))
+			}
+		}
+	}
+
+	return a, perm, sign
+}
+
+func (r refMatrix) det() float64 {
+	a, _, det := r.lu()
+	for i := 0; i < r.rows; i++ {
+		det *= a.at(i, i)
+	}
+	return det
+}
+
+func (r refMatrix) inverse() refMatrix {
+	n := r.rows
+	a, perm, _ := r.lu()
+	inv := refMatrix{n, n, make([]float64, n*n)}
+
+	for j := 0; j < n; j++ {
+		// Solve L.U.x = P.e_j
+		x := make([]float64, n)
+		for i := 0; i < n; i++ {
+			if perm[i] == j {
+				x[i] = 1
+			}
+			for k := 0; k < i; k++ {
+				x[i] -= a.at(i, k) * x[k]
+			}
+		}
+		for i := n - 1; i >= 0; i-- {
+			for k := i + 1; k < n; k++ {
+				x[i] -= a.at(i, k) * x[k]
+			}
+			x[i] /= a.at(i, i)
+		}
+		for i := 0; i < n; i++ {
+			inv.set(i, j, x[i])
+		}
+	}
+
+	return inv
+}
+
+// normInf returns the infinity norm of r, the maximum absolute row sum.
+func (r refMatrix) normInf() float64 {
+	var norm float64
+	for i := 0; i < r.rows; i++ {
+		var sum float64
+		for j := 0; j < r.cols; j++ {
+			sum += math.Abs(r.at(i, j))
+		}
+		norm = math.Max(norm, sum)
+	}
+	return norm
+}
+
+// maxAbs returns the largest absolute value of the elements of r.
+func (r refMatrix) maxAbs() float64 {
+	var max float64
+	for _, v := range r.m {
+		max = math.Max(max, math.Abs(v))
+	}
+	return max
+}
+
+// hadamard returns the product of the column norms of r, an upper bound of
+// the absolute value of its determinant.
+func (r refMatrix) hadamard() float64 {
+	bound := 1.0
+	for j := 0; j < r.cols; j++ {
+		var sum float64
+		for i := 0; i < r.rows; i++ {
+			sum += r.at(i, j) * r.at(i, j)
+		}
+		bound *= math.Sqrt(sum)
+	}
+	return bound
+}
+
+// cond returns the condition number of a square matrix.
+func (r refMatrix) cond() float64 {
+	return r.normInf() * r.inverse().normInf()
+}
+
+// referenceClose returns the index of the first element of got too far from
+// want, or -1 if all elements are within tolerance. The tolerance is relative
+// to scale, the magnitude of the values involved in the computation.
+func referenceClose(got []float32, want []float64, scale, tolerance float64) int {
+	for i := range got {
+		if math.Abs(float64(got[i])-want[i]) > tolerance*scale {
+			return i
+		}
+	}
+	return -1
+}
+
+// referenceCheck runs check on *referenceN random inputs.
+func referenceCheck(t *testing.T, check func(r *rand.Rand) bool) {
+	r := rand.New(rand.NewSource(*referenceSeed))
+	for i := 0; i < *referenceN; i++ {
+		if !check(r) {
+			t.Logf("seed %d, iteration %d", *referenceSeed, i)
+			return
+		}
+	}
+}
+
+func randomFloats(r *rand.Rand, m []float32) {
+	for i := range m {
+		m[i] = r.Float32()*20 - 10
+	}
+}
+
+func randomMat3(r *rand.Rand) Mat3 {
+	var m Mat3
+	randomFloats(r, m[:])
+	return m
+}
+
+func randomMat4(r *rand.Rand) Mat4 {
+	var m Mat4
+	randomFloats(r, m[:])
+	return m
+}
+
+func randomMat3x4(r *rand.Rand) Mat3x4 {
+	var m Mat3x4
+	randomFloats(r, m[:])
+	return m
+}
+
+func randomUnitQuat(r *rand.Rand) Quaternion {
+	q := Quaternion{r.Float32()*2 - 1, Vec3{r.Float32()*2 - 1, r.Float32()*2 - 1, r.Float32()*2 - 1}}
+	if q.Len() < .1 {
+		return QuatIdent()
+	}
+	return q.Normalized()
+}
+
+func TestReferenceMat3(t *testing.T) {
+	t.Parallel()
+	referenceCheck(t, func(r *rand.Rand) bool {
+		m1, m2 := randomMat3(r), randomMat3(r)
+		v := Vec3{r.Float32()*20 - 10, r.Float32()*20 - 10, r.Float32()*20 - 10}
+		ref1, ref2 := newRefMatrix(3, 3, m1[:]), newRefMatrix(3, 3, m2[:])
+		refV := newRefMatrix(3, 1, v[:])
+
+		mul := m1.Mul3(&m2)
+		if i := referenceClose(mul[:], ref1.mul(ref2).m, 3*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul3(%v)[%d] = %v, want %v", m1, m2, i, mul[i], ref1.mul(ref2).m[i])
+			return false
+		}
+		mv := m1.Mul3x1(&v)
+		if i := referenceClose(mv[:], ref1.mul(refV).m, 3*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul3x1(%v)[%d] = %v, want %v", m1, v, i, mv[i], ref1.mul(refV).m[i])
+			return false
+		}
+		if det, want := m1.Det(), ref1.det(); referenceClose([]float32{det}, []float64{want}, ref1.hadamard(), 1e-5) >= 0 {
+			t.Errorf("%v.Det() = %v, want %v", m1, det, want)
+			return false
+		}
+
+		cond := ref1.cond()
+		if cond > referenceMaxCond {
+			return true
+		}
+		want := ref1.inverse()
+		scale := want.maxAbs() * cond
+		inverse := m1.Inverse()
+		if i := referenceClose(inverse[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("%v.Inverse()[%d] = %v, want %v", m1, i, inverse[i], want.m[i])
+			return false
+		}
+		var inverseOf Mat3
+		inverseOf.InverseOf(&m1)
+		if i := referenceClose(inverseOf[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("InverseOf(%v)[%d] = %v, want %v", m1, i, inverseOf[i], want.m[i])
+			return false
+		}
+		invert := m1
+		invert.Invert()
+		if i := referenceClose(invert[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("%v.Invert()[%d] = %v, want %v", m1, i, invert[i], want.m[i])
+			return false
+		}
+		return true
+	})
+}
+
+func TestReferenceMat4(t *testing.T) {
+	t.Parallel()
+	referenceCheck(t, func(r *rand.Rand) bool {
+		m1, m2 := randomMat4(r), randomMat4(r)
+		v := Vec4{r.Float32()*20 - 10, r.Float32()*20 - 10, r.Float32()*20 - 10, r.Float32()*20 - 10}
+		ref1, ref2 := newRefMatrix(4, 4, m1[:]), newRefMatrix(4, 4, m2[:])
+		refV := newRefMatrix(4, 1, v[:])
+
+		mul := m1.Mul4(&m2)
+		if i := referenceClose(mul[:], ref1.mul(ref2).m, 4*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul4(%v)[%d] = %v, want %v", m1, m2, i, mul[i], ref1.mul(ref2).m[i])
+			return false
+		}
+		mv := m1.Mul4x1(&v)
+		if i := referenceClose(mv[:], ref1.mul(refV).m, 4*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul4x1(%v)[%d] = %v, want %v", m1, v, i, mv[i], ref1.mul(refV).m[i])
+			return false
+		}
+		transposed := m1.Transposed()
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				if transposed.At(i, j) != m1.At(j, i) {
+					t.Errorf("%v.Transposed() = %v", m1, transposed)
+					return false
+				}
+			}
+		}
+		if det, want := m1.Det(), ref1.det(); referenceClose([]float32{det}, []float64{want}, ref1.hadamard(), 1e-5) >= 0 {
+			t.Errorf("%v.Det() = %v, want %v", m1, det, want)
+			return false
+		}
+
+		cond := ref1.cond()
+		if cond > referenceMaxCond {
+			return true
+		}
+		want := ref1.inverse()
+		scale := want.maxAbs() * cond
+		inverse := m1.Inverse()
+		if i := referenceClose(inverse[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("%v.Inverse()[%d] = %v, want %v", m1, i, inverse[i], want.m[i])
+			return false
+		}
+		var inverseOf Mat4
+		inverseOf.InverseOf(&m1)
+		if i := referenceClose(inverseOf[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("InverseOf(%v)[%d] = %v, want %v", m1, i, inverseOf[i], want.m[i])
+			return false
+		}
+		invert := m1
+		invert.Invert()
+		if i := referenceClose(invert[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("%v.Invert()[%d] = %v, want %v", m1, i, invert[i], want.m[i])
+			return false
+		}
+		return true
+	})
+}
+
+func TestReferenceMat3x4(t *testing.T) {
+	t.Parallel()
+	referenceCheck(t, func(r *rand.Rand) bool {
+		m1, m2 := randomMat3x4(r), randomMat3x4(r)
+		v := Vec3{r.Float32()*20 - 10, r.Float32()*20 - 10, r.Float32()*20 - 10}
+		ref1, ref2 := newRefMatrix(3, 4, m1[:]).affine(), newRefMatrix(3, 4, m2[:]).affine()
+		refV := refMatrix{4, 1, []float64{float64(v[0]), float64(v[1]), float64(v[2]), 1}}
+
+		mul := m1.Mul3x4(&m2)
+		if i := referenceClose(mul[:], ref1.mul(ref2).top(3).m, 4*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul3x4(%v)[%d] = %v, want %v", m1, m2, i, mul[i], ref1.mul(ref2).top(3).m[i])
+			return false
+		}
+		transformed := m1.Transform(&v)
+		if i := referenceClose(transformed[:], ref1.mul(refV).m, 4*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Transform(%v)[%d] = %v, want %v", m1, v, i, transformed[i], ref1.mul(refV).m[i])
+			return false
+		}
+		if det, want := m1.Det(), ref1.det(); referenceClose([]float32{det}, []float64{want}, ref1.top(3).hadamard(), 1e-5) >= 0 {
+			t.Errorf("%v.Det() = %v, want %v", m1, det, want)
+			return false
+		}
+
+		cond := ref1.cond()
+		if cond > referenceMaxCond {
+			return true
+		}
+		want := ref1.inverse().top(3)
+		scale := want.maxAbs() * cond
+		inverse := m1.Inverse()
+		if i := referenceClose(inverse[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("%v.Inverse()[%d] = %v, want %v", m1, i, inverse[i], want.m[i])
+			return false
+		}
+		invert := m1
+		invert.Invert()
+		if i := referenceClose(invert[:], want.m, scale, 1e-5); i >= 0 {
+			t.Errorf("%v.Invert()[%d] = %v, want %v", m1, i, invert[i], want.m[i])
+			return false
+		}
+		return true
+	})
+}
+
+func TestReferenceMat2x3(t *testing.T) {
+	t.Parallel()
+	referenceCheck(t, func(r *rand.Rand) bool {
+		var m1, m2 Mat2x3
+		randomFloats(r, m1[:])
+		randomFloats(r, m2[:])
+		v := Vec2{r.Float32()*20 - 10, r.Float32()*20 - 10}
+		ref1, ref2 := newRefMatrix(2, 3, m1[:]).affine2(), newRefMatrix(2, 3, m2[:]).affine2()
+		refV := refMatrix{3, 1, []float64{float64(v[0]), float64(v[1]), 1}}
+
+		mul := m1.Mul2x3(&m2)
+		if i := referenceClose(mul[:], ref1.mul(ref2).top(2).m, 3*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul2x3(%v)[%d] = %v, want %v", m1, m2, i, mul[i], ref1.mul(ref2).top(2).m[i])
+			return false
+		}
+		transformed := m1.Mul2x1(&v)
+		if i := referenceClose(transformed[:], ref1.mul(refV).m, 3*100, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul2x1(%v)[%d] = %v, want %v", m1, v, i, transformed[i], ref1.mul(refV).m[i])
+			return false
+		}
+		if det, want := m1.Det(), ref1.det(); referenceClose([]float32{det}, []float64{want}, ref1.top(2).hadamard(), 1e-5) >= 0 {
+			t.Errorf("%v.Det() = %v, want %v", m1, det, want)
+			return false
+		}
+
+		cond := ref1.cond()
+		if cond > referenceMaxCond {
+			return true
+		}
+		want := ref1.inverse().top(2)
+		inverse := m1.Inverse()
+		if i := referenceClose(inverse[:], want.m, want.maxAbs()*cond, 1e-5); i >= 0 {
+			t.Errorf("%v.Inverse()[%d] = %v, want %v", m1, i, inverse[i], want.m[i])
+			return false
+		}
+		return true
+	})
+}
+
+func TestReferenceQuat(t *testing.T) {
+	t.Parallel()
+	referenceCheck(t, func(r *rand.Rand) bool {
+		q1, q2 := randomUnitQuat(r), randomUnitQuat(r)
+		v := Vec3{r.Float32()*20 - 10, r.Float32()*20 - 10, r.Float32()*20 - 10}
+
+		// Rotation matrix, from the float64 quaternion.
+		w, x, y, z := float64(q1.W), float64(q1.V[0]), float64(q1.V[1]), float64(q1.V[2])
+		rot := refMatrix{4, 4, []float64{
+			1 - 2*y*y - 2*z*z, 2*x*y + 2*w*z, 2*x*z - 2*w*y, 0,
+			2*x*y - 2*w*z, 1 - 2*x*x - 2*z*z, 2*y*z + 2*w*x, 0,
+			2*x*z + 2*w*y, 2*y*z - 2*w*x, 1 - 2*x*x - 2*y*y, 0,
+			0, 0, 0, 1,
+		}}
+		m := q1.Mat4()
+		if i := referenceClose(m[:], rot.m, 1, 1e-6); i >= 0 {
+			t.Errorf("%v.Mat4()[%d] = %v, want %v", q1, i, m[i], rot.m[i])
+			return false
+		}
+
+		// Rotating a vector is the same as multiplying by the rotation matrix.
+		refV := refMatrix{4, 1, []float64{float64(v[0]), float64(v[1]), float64(v[2]), 0}}
+		rotated := q1.Rotate(&v)
+		if i := referenceClose(rotated[:], rot.mul(refV).m, 10, 1e-5); i >= 0 {
+			t.Errorf("%v.Rotate(%v)[%d] = %v, want %v", q1, v, i, rotated[i], rot.mul(refV).m[i])
+			return false
+		}
+
+		// Hamilton product.
+		w2, x2, y2, z2 := float64(q2.W), float64(q2.V[0]), float64(q2.V[1]), float64(q2.V[2])
+		product := []float64{
+			w*w2 - x*x2 - y*y2 - z*z2,
+			w*x2 + x*w2 + y*z2 - z*y2,
+			w*y2 - x*z2 + y*w2 + z*x2,
+			w*z2 + x*y2 - y*x2 + z*w2,
+		}
+		mul := q1.Mul(&q2)
+		if i := referenceClose([]float32{mul.W, mul.V[0], mul.V[1], mul.V[2]}, product, 1, 1e-6); i >= 0 {
+			t.Errorf("%v.Mul(%v) = %v, want %v", q1, q2, mul, product)
+			return false
+		}
+		mulWith := q1
+		mulWith.MulWith(&q2)
+		if i := referenceClose([]float32{mulWith.W, mulWith.V[0], mulWith.V[1], mulWith.V[2]}, product, 1, 1e-6); i >= 0 {
+			t.Errorf("%v.MulWith(%v) = %v, want %v", q1, q2, mulWith, product)
+			return false
+		}
+		return true
+	})
+}
@@ -121,6 +121,44 @@ func (t *Transform) AsMat4() *Mat4 {
 	return (*Mat4)(t)
 }
 
+// Decompose extracts the translation, rotation and scale of this transform.
+// See Mat4.Decompose.
+func (t *Transform) Decompose() (translation Vec3, rotation Quaternion, scale Vec3) {
+	return (*Mat4)(t).Decompose()
+}
+
+// Lerp interpolates between t1, at amount 0, and t2, at amount 1. Both
+// transforms are decomposed, see Decompose: translations and scales are
+// interpolated linearly and rotations with QuatSlerp, along the shortest path.
+func (t1 *Transform) Lerp(t2 *Transform, amount float32) Transform {
+	translation1, rotation1, scale1 := t1.Decompose()
+	translation2, rotation2, scale2 := t2.Decompose()
+
+	// q and -q are the same rotation, pick the closest one.
+	if rotation1.Dot(&rotation2) < 0 {
+		rotation2 = rotation2.Scale(-1)
+	}
+
+	translation := lerpVec3(&translation1, &translation2, amount)
+	rotation := QuatSlerp(&rotation1, &rotation2, amount)
+	scale := lerpVec3(&scale1, &scale2, amount)
+
+	m := Translate3D(translation[0], translation[1], translation[2])
+	r := rotation.Mat4()
+	m.Mul4With(&r)
+	sm := Scale3D(scale[0], scale[1], scale[2])
+	m.Mul4With(&sm)
+	return Transform(m)
+}
+
+func lerpVec3(v1, v2 *Vec3, amount float32) Vec3 {
+	return Vec3{
+		v1[0] + (v2[0]-v1[0])*amount,
+		v1[1] + (v2[1]-v1[1])*amount,
+		v1[2] + (v2[2]-v1[2])*amount,
+	}
+}
+
 // Pointer returns the pointer to the first element of the underlying 4x4
 // matrix. This is can be passed directly to OpenGL function.
 func (t *Transform) Pointer() unsafe.Pointer {
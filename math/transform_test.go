@@ -73,6 +73,65 @@ func TestTransform_SetTranslate(t *testing.T) {
 	}
 }
 
+// transformNear compares transforms with an absolute tolerance. EqualThreshold
+// is relative, which fails on values close to 0.
+func transformNear(t1, t2 *Transform, epsilon float32) bool {
+	for i := range t1 {
+		if Abs(t1[i]-t2[i]) > epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTransform_Lerp(t *testing.T) {
+	t.Parallel()
+	var t1, t2 Transform
+	t1.Iden()
+	t1.Translate3f(1, 2, 3)
+	t2 = Transform(Translate3D(3, 2, -1))
+	r := QuatRotate(Pi/2, &Vec3{0, 1, 0})
+	rm := r.Mat4()
+	t2.AsMat4().Mul4With(&rm)
+	s := Scale3D(3, 3, 3)
+	t2.AsMat4().Mul4With(&s)
+
+	// The ends of the interpolation are the transforms themselves.
+	for _, test := range []struct {
+		amount   float32
+		expected *Transform
+	}{{0, &t1}, {1, &t2}} {
+		m := t1.Lerp(&t2, test.amount)
+		if !transformNear(&m, test.expected, 1e-5) {
+			t.Errorf("Lerp(%f)\n%snot equal to\n%s", test.amount, m.String(), test.expected.String())
+		}
+	}
+
+	half := t1.Lerp(&t2, .5)
+	translation, rotation, scale := half.Decompose()
+	if expected := (Vec3{2, 2, 1}); !translation.EqualThreshold(&expected, 1e-4) {
+		t.Errorf("translation %v, expected %v", translation, expected)
+	}
+	if expected := QuatRotate(Pi/4, &Vec3{0, 1, 0}); !rotation.OrientationEqualThreshold(&expected, 1e-4) {
+		t.Errorf("rotation %v, expected %v", rotation, expected)
+	}
+	if expected := (Vec3{2, 2, 2}); !scale.EqualThreshold(&expected, 1e-4) {
+		t.Errorf("scale %v, expected %v", scale, expected)
+	}
+
+	// Rotations take the shortest path, whatever the sign of the
+	// quaternions.
+	qa := QuatRotate(.2, &Vec3{0, 0, 1})
+	qb := QuatRotate(-.2, &Vec3{0, 0, 1})
+	qb = qb.Scale(-1)
+	a, b := Transform(qa.Mat4()), Transform(qb.Mat4())
+	half = a.Lerp(&b, .5)
+	_, rotation, _ = half.Decompose()
+	if ident := QuatIdent(); !rotation.OrientationEqualThreshold(&ident, 1e-4) {
+		t.Errorf("rotation %v, expected identity", rotation)
+	}
+}
+
 /*
 func TestSpecial(t *testing.T) {
 	var tr Transform
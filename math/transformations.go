@@ -11,6 +11,13 @@ func Rotate2D(angle float32) Mat2 {
 	}
 }
 
+// Rotate2DIn is the same as Rotate2D but with a destination matrix.
+func Rotate2DIn(angle float32, dst *Mat2) {
+	sin, cos := Sincos(angle)
+	dst[0], dst[1] = cos, sin
+	dst[2], dst[3] = -sin, cos
+}
+
 // Rotate3DX returns a 3x3 (non-homogeneous) Matrix that rotates by angle about
 // the X-axis.
 //
@@ -27,6 +34,14 @@ func Rotate3DX(angle float32) Mat3 {
 	}
 }
 
+// Rotate3DXIn is the same as Rotate3DX but with a destination matrix.
+func Rotate3DXIn(angle float32, dst *Mat3) {
+	sin, cos := Sincos(angle)
+	dst[0], dst[1], dst[2] = 1, 0, 0
+	dst[3], dst[4], dst[5] = 0, cos, sin
+	dst[6], dst[7], dst[8] = 0, -sin, cos
+}
+
 // Rotate3DY returns a 3x3 (non-homogeneous) Matrix that rotates by angle about
 // the Y-axis.
 //
@@ -43,6 +58,14 @@ func Rotate3DY(angle float32) Mat3 {
 	}
 }
 
+// Rotate3DYIn is the same as Rotate3DY but with a destination matrix.
+func Rotate3DYIn(angle float32, dst *Mat3) {
+	sin, cos := Sincos(angle)
+	dst[0], dst[1], dst[2] = cos, 0, -sin
+	dst[3], dst[4], dst[5] = 0, 1, 0
+	dst[6], dst[7], dst[8] = sin, 0, cos
+}
+
 // Rotate3DZ returns a 3x3 (non-homogeneous) Matrix that rotates by angle about
 // the Z-axis.
 //
@@ -59,6 +82,34 @@ func Rotate3DZ(angle float32) Mat3 {
 	}
 }
 
+// Rotate3DZIn is the same as Rotate3DZ but with a destination matrix.
+func Rotate3DZIn(angle float32, dst *Mat3) {
+	sin, cos := Sincos(angle)
+	dst[0], dst[1], dst[2] = cos, sin, 0
+	dst[3], dst[4], dst[5] = -sin, cos, 0
+	dst[6], dst[7], dst[8] = 0, 0, 1
+}
+
+// Rotate3D returns a 3x3 (non-homogeneous) Matrix that rotates by angle about
+// an arbitrary axis. axis must be normalized. See HomogRotate3D for the
+// homogeneous version.
+func Rotate3D(angle float32, axis *Vec3) Mat3 {
+	var m Mat3
+	Rotate3DIn(angle, axis, &m)
+	return m
+}
+
+// Rotate3DIn is the same as Rotate3D but with a destination matrix.
+func Rotate3DIn(angle float32, axis *Vec3, dst *Mat3) {
+	x, y, z := axis[0], axis[1], axis[2]
+	s, c := Sincos(angle)
+	k := 1 - c
+
+	dst[0], dst[1], dst[2] = x*x*k+c, x*y*k+z*s, x*z*k-y*s
+	dst[3], dst[4], dst[5] = x*y*k-z*s, y*y*k+c, y*z*k+x*s
+	dst[6], dst[7], dst[8] = x*z*k+y*s, y*z*k-x*s, z*z*k+c
+}
+
 // Translate2D returns a homogeneous (3x3 for 2D-space) Translation matrix that moves a point by Tx units in the x-direction and Ty units in the y-direction
 //
 //    [[1, 0, Tx]]
@@ -72,6 +123,12 @@ func Translate2D(Tx, Ty float32) Mat3 {
 	}
 }
 
+// Translate2DIn is the same as Translate2D but with a destination matrix.
+func Translate2DIn(Tx, Ty float32, dst *Mat3) {
+	*dst = Ident3()
+	dst[6], dst[7] = Tx, Ty
+}
+
 // Translate3D returns a homogeneous (4x4 for 3D-space) Translation matrix that
 // moves a point by Tx units in the x-direction, Ty units in the y-direction,
 // and Tz units in the z-direction.
@@ -89,6 +146,12 @@ func Translate3D(Tx, Ty, Tz float32) Mat4 {
 	}
 }
 
+// Translate3DIn is the same as Translate3D but with a destination matrix.
+func Translate3DIn(Tx, Ty, Tz float32, dst *Mat4) {
+	*dst = Ident4()
+	dst[12], dst[13], dst[14] = Tx, Ty, Tz
+}
+
 // HomogRotate2D is the same as Rotate2D, except homogeneous (3x3 with the extra
 // row/col being all zeroes with a one in the bottom right).
 func HomogRotate2D(angle float32) Mat3 {
@@ -100,6 +163,14 @@ func HomogRotate2D(angle float32) Mat3 {
 	}
 }
 
+// HomogRotate2DIn is the same as HomogRotate2D but with a destination matrix.
+func HomogRotate2DIn(angle float32, dst *Mat3) {
+	sin, cos := Sincos(angle)
+	dst[0], dst[1], dst[2] = cos, sin, 0
+	dst[3], dst[4], dst[5] = -sin, cos, 0
+	dst[6], dst[7], dst[8] = 0, 0, 1
+}
+
 // HomogRotate3DX is the same as Rotate3DX, except homogeneous (4x4 with the
 // extra row/col being all zeroes with a one in the bottom right).
 func HomogRotate3DX(angle float32) Mat4 {
@@ -112,6 +183,15 @@ func HomogRotate3DX(angle float32) Mat4 {
 	}
 }
 
+// HomogRotate3DXIn is the same as HomogRotate3DX but with a destination
+// matrix.
+func HomogRotate3DXIn(angle float32, dst *Mat4) {
+	sin, cos := Sincos(angle)
+	*dst = Ident4()
+	dst[5], dst[6] = cos, sin
+	dst[9], dst[10] = -sin, cos
+}
+
 // HomogRotate3DY is the same as Rotate3DY, except homogeneous (4x4 with the
 // extra row/col being all zeroes with a one in the bottom right).
 func HomogRotate3DY(angle float32) Mat4 {
@@ -124,6 +204,15 @@ func HomogRotate3DY(angle float32) Mat4 {
 	}
 }
 
+// HomogRotate3DYIn is the same as HomogRotate3DY but with a destination
+// matrix.
+func HomogRotate3DYIn(angle float32, dst *Mat4) {
+	sin, cos := Sincos(angle)
+	*dst = Ident4()
+	dst[0], dst[2] = cos, -sin
+	dst[8], dst[10] = sin, cos
+}
+
 // HomogRotate3DZ is the same as Rotate3DZ, except homogeneous (4x4 with the
 // extra row/col being all zeroes with a one in the bottom right).
 func HomogRotate3DZ(angle float32) Mat4 {
@@ -136,6 +225,15 @@ func HomogRotate3DZ(angle float32) Mat4 {
 	}
 }
 
+// HomogRotate3DZIn is the same as HomogRotate3DZ but with a destination
+// matrix.
+func HomogRotate3DZIn(angle float32, dst *Mat4) {
+	sin, cos := Sincos(angle)
+	*dst = Ident4()
+	dst[0], dst[1] = cos, sin
+	dst[4], dst[5] = -sin, cos
+}
+
 // Scale3D creates a homogeneous 3D scaling matrix.
 // [scaleX 0      0      0]
 // [0      scaleY 0      0]
@@ -152,6 +250,12 @@ func Scale3D(scaleX, scaleY, scaleZ float32) Mat4 {
 	}
 }
 
+// Scale3DIn is the same as Scale3D but with a destination matrix.
+func Scale3DIn(scaleX, scaleY, scaleZ float32, dst *Mat4) {
+	*dst = Ident4()
+	dst[0], dst[5], dst[10] = scaleX, scaleY, scaleZ
+}
+
 // Scale2D creates a homogeneous 2D scaling matrix.
 // [scaleX 0      0]
 // [0      scaleY 0]
@@ -166,6 +270,122 @@ func Scale2D(scaleX, scaleY float32) Mat3 {
 	}
 }
 
+// Scale2DIn is the same as Scale2D but with a destination matrix.
+func Scale2DIn(scaleX, scaleY float32, dst *Mat3) {
+	*dst = Ident3()
+	dst[0], dst[4] = scaleX, scaleY
+}
+
+// ShearX2D creates a homogeneous 2D shear matrix along the X-axis: y is
+// offset by shear * x.
+//
+//	[1     0 0]
+//	[shear 1 0]
+//	[0     0 1]
+func ShearX2D(shear float32) Mat3 {
+	return Mat3{
+		1, shear, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// ShearY2D creates a homogeneous 2D shear matrix along the Y-axis: x is
+// offset by shear * y.
+//
+//	[1 shear 0]
+//	[0 1     0]
+//	[0 0     1]
+func ShearY2D(shear float32) Mat3 {
+	return Mat3{
+		1, 0, 0,
+		shear, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// ShearX3D creates a homogeneous 3D shear matrix along the X-axis: y and z are
+// offset by shearY * x and shearZ * x respectively.
+//
+//	[1      0 0 0]
+//	[shearY 1 0 0]
+//	[shearZ 0 1 0]
+//	[0      0 0 1]
+func ShearX3D(shearY, shearZ float32) Mat4 {
+	return Mat4{
+		1, shearY, shearZ, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// ShearY3D creates a homogeneous 3D shear matrix along the Y-axis: x and z are
+// offset by shearX * y and shearZ * y respectively.
+//
+//	[1 shearX 0 0]
+//	[0 1      0 0]
+//	[0 shearZ 1 0]
+//	[0 0      0 1]
+func ShearY3D(shearX, shearZ float32) Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		shearX, 1, shearZ, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// ShearZ3D creates a homogeneous 3D shear matrix along the Z-axis: x and y are
+// offset by shearX * z and shearY * z respectively.
+//
+//	[1 0 shearX 0]
+//	[0 1 shearY 0]
+//	[0 0 1      0]
+//	[0 0 0      1]
+func ShearZ3D(shearX, shearY float32) Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		shearX, shearY, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Reflect2D creates a homogeneous 2D reflection matrix about the line of
+// points p satisfying dot(normal, p) = distance. normal must be normalized.
+func Reflect2D(normal *Vec2, distance float32) Mat3 {
+	x, y := normal[0], normal[1]
+	return Mat3{
+		1 - 2*x*x, -2 * x * y, 0,
+		-2 * x * y, 1 - 2*y*y, 0,
+		2 * distance * x, 2 * distance * y, 1,
+	}
+}
+
+// Reflect3D creates a homogeneous 3D reflection matrix about the plane of
+// points p satisfying dot(normal, p) = distance. normal must be normalized.
+//
+// Where x, y, and z are the first, second, and third elements of the normal
+// and d is distance:
+//
+//	[[ 1-2x^2, -2xy  , -2xz  , 2dx ]]
+//	[[ -2xy  , 1-2y^2, -2yz  , 2dy ]]
+//	[[ -2xz  , -2yz  , 1-2z^2, 2dz ]]
+//	[[ 0     , 0     , 0     , 1   ]]
+//
+// A reflection flips the winding of triangles: the determinant of the
+// resulting matrix is -1.
+func Reflect3D(normal *Vec3, distance float32) Mat4 {
+	x, y, z := normal[0], normal[1], normal[2]
+	return Mat4{
+		1 - 2*x*x, -2 * x * y, -2 * x * z, 0,
+		-2 * x * y, 1 - 2*y*y, -2 * y * z, 0,
+		-2 * x * z, -2 * y * z, 1 - 2*z*z, 0,
+		2 * distance * x, 2 * distance * y, 2 * distance * z, 1,
+	}
+}
+
 // HomogRotate3D creates a 3D rotation Matrix that rotates by (radian) angle
 // about some arbitrary axis given by a Vector. It produces a homogeneous
 // matrix.
@@ -184,6 +404,18 @@ func HomogRotate3D(angle float32, axis *Vec3) Mat4 {
 	return Mat4{x*x*k + c, x*y*k + z*s, x*z*k - y*s, 0, x*y*k - z*s, y*y*k + c, y*z*k + x*s, 0, x*z*k + y*s, y*z*k - x*s, z*z*k + c, 0, 0, 0, 0, 1}
 }
 
+// HomogRotate3DIn is the same as HomogRotate3D but with a destination matrix.
+func HomogRotate3DIn(angle float32, axis *Vec3, dst *Mat4) {
+	x, y, z := axis[0], axis[1], axis[2]
+	s, c := Sincos(angle)
+	k := 1 - c
+
+	dst[0], dst[1], dst[2], dst[3] = x*x*k+c, x*y*k+z*s, x*z*k-y*s, 0
+	dst[4], dst[5], dst[6], dst[7] = x*y*k-z*s, y*y*k+c, y*z*k+x*s, 0
+	dst[8], dst[9], dst[10], dst[11] = x*z*k+y*s, y*z*k-x*s, z*z*k+c, 0
+	dst[12], dst[13], dst[14], dst[15] = 0, 0, 0, 1
+}
+
 // Extract3DScale extracts the 3d scaling from a homogeneous matrix.
 func Extract3DScale(m *Mat4) (x, y, z float32) {
 	return Sqrt(m[0]*m[0] + m[1]*m[1] + m[2]*m[2]),
@@ -283,3 +515,78 @@ func WorldToLocalDirn(world *Vec3, transform *Mat3x4) Vec3 {
 func WorldToLocalDirnIn(world *Vec3, transform *Mat3x4, dst *Vec3) {
 	transform.TransformInverseDirectionIn(world, dst)
 }
+
+// Scale below which an axis is considered degenerate by Decompose.
+const decomposeEpsilon = 1e-6
+
+// perpendicular returns a unit vector perpendicular to the unit vector v.
+func perpendicular(v *Vec3) Vec3 {
+	axis := Vec3{1, 0, 0}
+	if Abs(v[0]) > 0.9 {
+		axis = Vec3{0, 1, 0}
+	}
+	p := v.Cross(&axis)
+	p.Normalize()
+	return p
+}
+
+// Decompose extracts the translation, rotation and scale of an affine
+// transformation matrix so that:
+//
+//	m1 = Translate3D(translation) * rotation.Mat4() * Scale3D(scale)
+//
+// A transformation flipping the handedness of the space (negative
+// determinant) is expressed with a negative X scale. Shear can't be
+// expressed with a rotation and a scale and is dropped: the rotation is the
+// orthonormal basis given by the Gram-Schmidt orthogonalization of the matrix
+// columns, in the X, Y, Z order. Axes scaled by 0 are given a scale of 0 and
+// an arbitrary direction orthogonal to the other ones.
+func (m1 *Mat4) Decompose() (translation Vec3, rotation Quaternion, scale Vec3) {
+	translation = Vec3{m1[12], m1[13], m1[14]}
+
+	x := Vec3{m1[0], m1[1], m1[2]}
+	y := Vec3{m1[4], m1[5], m1[6]}
+	z := Vec3{m1[8], m1[9], m1[10]}
+
+	scale[0] = x.Len()
+	if scale[0] > decomposeEpsilon {
+		x.MulWith(1 / scale[0])
+	} else {
+		x = Vec3{1, 0, 0}
+	}
+
+	y.AddScaledVec(-x.Dot(&y), &x)
+	scale[1] = y.Len()
+	if scale[1] > decomposeEpsilon {
+		y.MulWith(1 / scale[1])
+	} else {
+		y = perpendicular(&x)
+	}
+
+	handedness := x.Cross(&y)
+	z.AddScaledVec(-x.Dot(&z), &x)
+	z.AddScaledVec(-y.Dot(&z), &y)
+	scale[2] = z.Len()
+	if scale[2] > decomposeEpsilon {
+		z.MulWith(1 / scale[2])
+	} else {
+		z = handedness
+	}
+
+	// Mirroring: make the basis right-handed again by flipping X.
+	if handedness.Dot(&z) < 0 {
+		scale[0] = -scale[0]
+		x.MulWith(-1)
+	}
+
+	basis := Mat4{
+		x[0], x[1], x[2], 0,
+		y[0], y[1], y[2], 0,
+		z[0], z[1], z[2], 0,
+		0, 0, 0, 1,
+	}
+	rotation = Mat4ToQuat(&basis)
+	rotation.Normalize()
+
+	return
+}
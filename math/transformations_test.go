@@ -370,3 +370,241 @@ func TestScale2D(t *testing.T) {
 		}
 	}
 }
+
+func TestShear3D(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		Description string
+		Shear       Mat4
+		Point       Vec4
+		Expected    Vec4
+	}{
+		{"X", ShearX3D(2, 3), Vec4{1, 1, 1, 1}, Vec4{1, 3, 4, 1}},
+		{"Y", ShearY3D(2, 3), Vec4{1, 1, 1, 1}, Vec4{3, 1, 4, 1}},
+		{"Z", ShearZ3D(2, 3), Vec4{1, 1, 1, 1}, Vec4{3, 4, 1, 1}},
+		{"X origin", ShearX3D(2, 3), Vec4{0, 1, 1, 1}, Vec4{0, 1, 1, 1}},
+	}
+
+	for _, test := range tests {
+		if v := test.Shear.Mul4x1(&test.Point); !v.EqualThreshold(&test.Expected, 1e-3) {
+			t.Errorf("%s: shearing %v gave %v, expected %v", test.Description, test.Point, v, test.Expected)
+		}
+	}
+}
+
+func TestShear2D(t *testing.T) {
+	t.Parallel()
+	x := ShearX2D(2)
+	if v := x.Mul3x1(&Vec3{1, 1, 1}); !v.EqualThreshold(&Vec3{1, 3, 1}, 1e-3) {
+		t.Errorf("ShearX2D: got %v", v)
+	}
+	y := ShearY2D(2)
+	if v := y.Mul3x1(&Vec3{1, 1, 1}); !v.EqualThreshold(&Vec3{3, 1, 1}, 1e-3) {
+		t.Errorf("ShearY2D: got %v", v)
+	}
+}
+
+func TestReflect3D(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		Description string
+		Normal      Vec3
+		Distance    float32
+		Point       Vec4
+		Expected    Vec4
+	}{
+		{"YZ plane", Vec3{1, 0, 0}, 0, Vec4{1, 2, 3, 1}, Vec4{-1, 2, 3, 1}},
+		{"XZ plane", Vec3{0, 1, 0}, 0, Vec4{1, 2, 3, 1}, Vec4{1, -2, 3, 1}},
+		{"x = 2 plane", Vec3{1, 0, 0}, 2, Vec4{1, 2, 3, 1}, Vec4{3, 2, 3, 1}},
+		{"direction", Vec3{0, 0, 1}, 5, Vec4{1, 2, 3, 0}, Vec4{1, 2, -3, 0}},
+		{"diagonal", Vec3{1 / Sqrt2, 1 / Sqrt2, 0}, 0, Vec4{1, 0, 0, 1}, Vec4{0, -1, 0, 1}},
+	}
+
+	for _, test := range tests {
+		m := Reflect3D(&test.Normal, test.Distance)
+		if v := m.Mul4x1(&test.Point); !v.EqualThreshold(&test.Expected, 1e-3) {
+			t.Errorf("%s: reflecting %v gave %v, expected %v", test.Description, test.Point, v, test.Expected)
+		}
+		if det := m.Det(); !FloatEqualThreshold(det, -1, 1e-3) {
+			t.Errorf("%s: determinant is %f, expected -1", test.Description, det)
+		}
+	}
+}
+
+func TestReflect2D(t *testing.T) {
+	t.Parallel()
+	m := Reflect2D(&Vec2{0, 1}, 1)
+	if v := m.Mul3x1(&Vec3{1, 3, 1}); !v.EqualThreshold(&Vec3{1, -1, 1}, 1e-3) {
+		t.Errorf("Reflect2D: got %v", v)
+	}
+}
+
+func TestTransformationsIn(t *testing.T) {
+	t.Parallel()
+	axis := Vec3{1, 2, 3}
+	axis.Normalize()
+	angles := []float32{0, DegToRad(30), DegToRad(90), DegToRad(-135), DegToRad(360)}
+
+	for _, angle := range angles {
+		var m2 Mat2
+		Rotate2DIn(angle, &m2)
+		if e := Rotate2D(angle); !e.EqualThreshold(&m2, 1e-4) {
+			t.Errorf("Rotate2DIn(%f) differs from Rotate2D", angle)
+		}
+
+		var m3 Mat3
+		Rotate3DXIn(angle, &m3)
+		if e := Rotate3DX(angle); !e.EqualThreshold(&m3, 1e-4) {
+			t.Errorf("Rotate3DXIn(%f) differs from Rotate3DX", angle)
+		}
+		Rotate3DYIn(angle, &m3)
+		if e := Rotate3DY(angle); !e.EqualThreshold(&m3, 1e-4) {
+			t.Errorf("Rotate3DYIn(%f) differs from Rotate3DY", angle)
+		}
+		Rotate3DZIn(angle, &m3)
+		if e := Rotate3DZ(angle); !e.EqualThreshold(&m3, 1e-4) {
+			t.Errorf("Rotate3DZIn(%f) differs from Rotate3DZ", angle)
+		}
+		Rotate3DIn(angle, &axis, &m3)
+		homog := HomogRotate3D(angle, &axis)
+		if e := homog.Mat3(); !e.EqualThreshold(&m3, 1e-4) {
+			t.Errorf("Rotate3DIn(%f) differs from HomogRotate3D", angle)
+		}
+		if r := Rotate3D(angle, &axis); !r.EqualThreshold(&m3, 1e-4) {
+			t.Errorf("Rotate3D(%f) differs from Rotate3DIn", angle)
+		}
+		HomogRotate2DIn(angle, &m3)
+		if e := HomogRotate2D(angle); !e.EqualThreshold(&m3, 1e-4) {
+			t.Errorf("HomogRotate2DIn(%f) differs from HomogRotate2D", angle)
+		}
+
+		var m4 Mat4
+		HomogRotate3DXIn(angle, &m4)
+		if e := HomogRotate3DX(angle); !e.EqualThreshold(&m4, 1e-4) {
+			t.Errorf("HomogRotate3DXIn(%f) differs from HomogRotate3DX", angle)
+		}
+		HomogRotate3DYIn(angle, &m4)
+		if e := HomogRotate3DY(angle); !e.EqualThreshold(&m4, 1e-4) {
+			t.Errorf("HomogRotate3DYIn(%f) differs from HomogRotate3DY", angle)
+		}
+		HomogRotate3DZIn(angle, &m4)
+		if e := HomogRotate3DZ(angle); !e.EqualThreshold(&m4, 1e-4) {
+			t.Errorf("HomogRotate3DZIn(%f) differs from HomogRotate3DZ", angle)
+		}
+		HomogRotate3DIn(angle, &axis, &m4)
+		if !homog.EqualThreshold(&m4, 1e-4) {
+			t.Errorf("HomogRotate3DIn(%f) differs from HomogRotate3D", angle)
+		}
+	}
+
+	var m3 Mat3
+	Translate2DIn(1, 2, &m3)
+	if e := Translate2D(1, 2); e != m3 {
+		t.Errorf("Translate2DIn differs from Translate2D")
+	}
+	Scale2DIn(1, 2, &m3)
+	if e := Scale2D(1, 2); e != m3 {
+		t.Errorf("Scale2DIn differs from Scale2D")
+	}
+
+	var m4 Mat4
+	Translate3DIn(1, 2, 3, &m4)
+	if e := Translate3D(1, 2, 3); e != m4 {
+		t.Errorf("Translate3DIn differs from Translate3D")
+	}
+	Scale3DIn(1, 2, 3, &m4)
+	if e := Scale3D(1, 2, 3); e != m4 {
+		t.Errorf("Scale3DIn differs from Scale3D")
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	t.Parallel()
+	axis := Vec3{1, 2, 3}
+	axis.Normalize()
+
+	tests := []struct {
+		translation Vec3
+		rotation    Quaternion
+		scale       Vec3
+	}{
+		{Vec3{0, 0, 0}, QuatIdent(), Vec3{1, 1, 1}},
+		{Vec3{1, 2, 3}, QuatIdent(), Vec3{1, 1, 1}},
+		{Vec3{1, 2, 3}, QuatRotate(Pi/3, &axis), Vec3{1, 1, 1}},
+		{Vec3{-4, 0, 2}, QuatRotate(-Pi/5, &axis), Vec3{2, .5, 3}},
+		{Vec3{-4, 0, 2}, QuatRotate(2*Pi/3, &Vec3{0, 1, 0}), Vec3{-2, .5, 3}},
+	}
+
+	for i, test := range tests {
+		m := Translate3D(test.translation[0], test.translation[1], test.translation[2])
+		r := test.rotation.Mat4()
+		s := Scale3D(test.scale[0], test.scale[1], test.scale[2])
+		m.Mul4With(&r)
+		m.Mul4With(&s)
+
+		translation, rotation, scale := m.Decompose()
+		if !translation.EqualThreshold(&test.translation, 1e-4) {
+			t.Errorf("[%d] translation %v, expected %v", i, translation, test.translation)
+		}
+		if !rotation.OrientationEqualThreshold(&test.rotation, 1e-4) {
+			t.Errorf("[%d] rotation %v, expected %v", i, rotation, test.rotation)
+		}
+		if !scale.EqualThreshold(&test.scale, 1e-4) {
+			t.Errorf("[%d] scale %v, expected %v", i, scale, test.scale)
+		}
+
+		transform := Transform(m)
+		if tt, tr, ts := transform.Decompose(); tt != translation || tr != rotation || ts != scale {
+			t.Errorf("[%d] Transform.Decompose differs from Mat4.Decompose", i)
+		}
+	}
+}
+
+func TestDecomposeMirror(t *testing.T) {
+	t.Parallel()
+	// Negating Y is the same as negating X and rotating by Pi around Z.
+	m := Scale3D(1, -1, 1)
+	_, rotation, scale := m.Decompose()
+
+	expected := QuatRotate(Pi, &Vec3{0, 0, 1})
+	if !rotation.OrientationEqualThreshold(&expected, 1e-4) {
+		t.Errorf("rotation %v, expected %v", rotation, expected)
+	}
+	if expectedScale := (Vec3{-1, 1, 1}); !scale.EqualThreshold(&expectedScale, 1e-4) {
+		t.Errorf("scale %v, expected %v", scale, expectedScale)
+	}
+}
+
+func TestDecomposeDegenerate(t *testing.T) {
+	t.Parallel()
+	tests := []Mat4{
+		Scale3D(0, 1, 1),
+		Scale3D(1, 0, 1),
+		Scale3D(1, 1, 0),
+		Scale3D(0, 0, 0),
+		ShearX3D(1, 2),
+	}
+
+	for i, m := range tests {
+		_, rotation, scale := m.Decompose()
+		if l := rotation.Len(); !FloatEqualThreshold(l, 1, 1e-4) {
+			t.Errorf("[%d] rotation %v isn't a unit quaternion", i, rotation)
+		}
+		for j := 0; j < 3; j++ {
+			if IsNaN(scale[j]) {
+				t.Errorf("[%d] scale %v has NaN", i, scale)
+			}
+		}
+	}
+
+	// Shear is dropped, scale is the length of the orthogonalized axes.
+	m := ShearY3D(1, 0)
+	_, rotation, scale := m.Decompose()
+	identity := QuatIdent()
+	if !rotation.OrientationEqualThreshold(&identity, 1e-4) {
+		t.Errorf("sheared rotation %v, expected identity", rotation)
+	}
+	if expected := (Vec3{1, 1, 1}); !scale.EqualThreshold(&expected, 1e-4) {
+		t.Errorf("sheared scale %v, expected %v", scale, expected)
+	}
+}
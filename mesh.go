@@ -1,5 +1,9 @@
 package dax
 
+import (
+	"github.com/dlespiau/dax/math"
+)
+
 // AttributeBuffer holds per-vertex attribute. There is one AttributeBuffer per
 // kind of data we want to keep with each vertex.
 type AttributeBuffer struct {
@@ -135,6 +139,13 @@ type Mesh struct {
 	mode       VertexMode
 	attributes []AttributeBuffer
 	indices    IndexBuffer
+
+	// bounds caches the bounding box of the positions, when boundsValid.
+	bounds      math.AABB
+	boundsValid bool
+	// sphere caches the bounding sphere of the positions, when sphereValid.
+	sphere      math.BoundingSphere
+	sphereValid bool
 }
 
 func NewMesh() *Mesh {
@@ -145,6 +156,12 @@ func NewMesh() *Mesh {
 	return m
 }
 
+// GetMesh implements Mesher, making it possible to use a Mesh directly with a
+// MeshRenderer.
+func (m *Mesh) GetMesh() *Mesh {
+	return m
+}
+
 // GetVertexMode returns how vertices in the Mesh are interpreted. New meshes
 // default to VertexModeTriangles.
 func (m *Mesh) GetVertexMode() VertexMode {
@@ -157,15 +174,74 @@ func (m *Mesh) SetVertexMode(mode VertexMode) {
 }
 
 func (m *Mesh) GetAttribute(name string) *AttributeBuffer {
-	for _, ab := range m.attributes {
-		if ab.Name == name {
-			return &ab
+	for i := range m.attributes {
+		if m.attributes[i].Name == name {
+			return &m.attributes[i]
 		}
 	}
 
 	return nil
 }
 
+// Bounds implements Bounder. The box of the mesh positions is computed the
+// first time it's needed and cached: call InvalidateBounds after modifying
+// positions in place. A mesh without positions has an empty box.
+func (m *Mesh) Bounds() math.AABB {
+	if m.boundsValid {
+		return m.bounds
+	}
+
+	m.bounds = math.EmptyAABB()
+	m.boundsValid = true
+
+	positions := m.GetAttribute("position")
+	if positions == nil || positions.NumComponents < 3 {
+		return m.bounds
+	}
+	for i := 0; i < positions.Len(); i++ {
+		x, y, z := positions.GetXYZ(i)
+		m.bounds.ExtendPoint(&math.Vec3{x, y, z})
+	}
+
+	return m.bounds
+}
+
+// BoundingSphere implements SphereBounder. The sphere is centered on the
+// bounding box and cached like the box, see Bounds.
+func (m *Mesh) BoundingSphere() math.BoundingSphere {
+	if m.sphereValid {
+		return m.sphere
+	}
+
+	bounds := m.Bounds()
+	m.sphere = boundingSphereAround(bounds.Center(), m.GetAttribute("position"))
+	m.sphereValid = true
+
+	return m.sphere
+}
+
+// boundingSphereAround returns the sphere centered on center containing all
+// positions.
+func boundingSphereAround(center math.Vec3, positions *AttributeBuffer) math.BoundingSphere {
+	if positions == nil || positions.NumComponents < 3 || positions.Len() == 0 {
+		return math.BoundingSphere{Radius: -1}
+	}
+
+	var radius2 float32
+	for i := 0; i < positions.Len(); i++ {
+		x, y, z := positions.GetXYZ(i)
+		d := math.Vec3{x - center[0], y - center[1], z - center[2]}
+		radius2 = math.Max(radius2, d.Len2())
+	}
+	return math.BoundingSphere{Center: center, Radius: math.Sqrt(radius2)}
+}
+
+// InvalidateBounds discards the cached bounding volumes of the mesh.
+func (m *Mesh) InvalidateBounds() {
+	m.boundsValid = false
+	m.sphereValid = false
+}
+
 func (m *Mesh) getNewAttribute(name string) *AttributeBuffer {
 	ab := m.GetAttribute(name)
 	if ab != nil {
@@ -181,17 +257,24 @@ func (m *Mesh) getNewAttribute(name string) *AttributeBuffer {
 func (m *Mesh) AddAttribute(name string, data []float32, NumComponents int) {
 	ab := m.getNewAttribute(name)
 	ab.InitFromData(name, data, NumComponents)
+	m.InvalidateBounds()
 }
 
 func (m *Mesh) AddAttributeBuffer(buffer *AttributeBuffer) {
 	ab := m.getNewAttribute(buffer.Name)
 	*ab = *buffer
+	m.InvalidateBounds()
 }
 
 func (m *Mesh) HasIndices() bool {
 	return m.indices.data16 != nil || m.indices.data32 != nil
 }
 
+// GetIndices returns the index buffer of the mesh.
+func (m *Mesh) GetIndices() *IndexBuffer {
+	return &m.indices
+}
+
 func (m *Mesh) AddIndices(data []uint) {
 	m.indices.InitFromData(data)
 }
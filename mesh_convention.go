@@ -0,0 +1,79 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// Attributes holding directions, converted along with positions.
+var directionAttributes = []string{"normal", "tangent"}
+
+// ConvertConvention converts the mesh from the from coordinate system
+// convention to the to convention: positions, normals and tangents are
+// transformed and, when the conversion mirrors the geometry, the winding of
+// triangles is reversed to keep front faces front facing and the handedness of
+// the tangent frames is flipped.
+func (m *Mesh) ConvertConvention(from, to math.Convention) {
+	if from == to {
+		return
+	}
+
+	c := math.ConventionMatrix(from, to)
+	mirrors := math.ConventionMirrors(from, to)
+	for _, name := range append([]string{"position"}, directionAttributes...) {
+		ab := m.GetAttribute(name)
+		if ab == nil || ab.NumComponents < 3 {
+			continue
+		}
+		for i := 0; i < ab.Len(); i++ {
+			x, y, z := ab.GetXYZ(i)
+			v := c.Mul3x1(&math.Vec3{x, y, z})
+			ab.SetXYZ(i, v[0], v[1], v[2])
+		}
+		// Tangents have a 4th component, the handedness of the bitangent,
+		// cross(normal, tangent) * w. Mirroring normals and tangents turns
+		// their cross product the other way: w is flipped to keep the
+		// bitangent mirrored as well.
+		if name == "tangent" && ab.NumComponents == 4 && mirrors {
+			for i := 0; i < ab.Len(); i++ {
+				x, y, z, w := ab.GetXYZW(i)
+				ab.SetXYZW(i, x, y, z, -w)
+			}
+		}
+	}
+	m.InvalidateBounds()
+
+	if mirrors {
+		m.reverseWinding()
+	}
+}
+
+// reverseWinding swaps front and back faces.
+func (m *Mesh) reverseWinding() {
+	indices := m.indices.indices()
+	n := len(indices)
+	if n == 0 {
+		return
+	}
+
+	switch m.mode {
+	case VertexModeTriangles:
+		for i := 0; i+2 < n; i += 3 {
+			indices[i+1], indices[i+2] = indices[i+2], indices[i+1]
+		}
+	case VertexModeTriangleFan:
+		// Keep the center, reverse the rim.
+		for i, j := 1, n-1; i < j; i, j = i+1, j-1 {
+			indices[i], indices[j] = indices[j], indices[i]
+		}
+	case VertexModeTriangleStrip:
+		// Repeating the first vertex adds a degenerate triangle and flips
+		// the winding of all the following ones.
+		indices = append([]uint{indices[0]}, indices...)
+		m.indices.InitFromData(indices)
+		return
+	default:
+		return
+	}
+
+	m.indices.setIndices(indices)
+}
@@ -0,0 +1,85 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConventionTestMesh(mode VertexMode, indices []uint) *Mesh {
+	m := NewMesh()
+	m.SetVertexMode(mode)
+	m.AddAttribute("position", []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 0, 0}, 3)
+	m.AddAttribute("normal", []float32{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1}, 3)
+	m.AddAttribute("tangent", []float32{1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1}, 4)
+	m.AddIndices(indices)
+	return m
+}
+
+func TestMeshConvertConvention(t *testing.T) {
+	m := newConventionTestMesh(VertexModeTriangles, []uint{0, 1, 2, 1, 2, 3})
+	m.ConvertConvention(math.ZUpRightHanded, math.YUpRightHanded)
+
+	x, y, z := m.GetAttribute("position").GetXYZ(0)
+	assert.Equal(t, []float32{1, 3, -2}, []float32{x, y, z})
+	x, y, z = m.GetAttribute("normal").GetXYZ(0)
+	assert.Equal(t, []float32{0, 1, 0}, []float32{x, y, z})
+	x, y, z, w := m.GetAttribute("tangent").GetXYZW(0)
+	assert.Equal(t, []float32{1, 0, 0, 1}, []float32{x, y, z, w})
+	// Same handedness, the winding doesn't change.
+	assert.Equal(t, []uint{0, 1, 2, 1, 2, 3}, m.indices.indices())
+
+	m = newConventionTestMesh(VertexModeTriangles, []uint{0, 1, 2, 1, 2, 3})
+	m.ConvertConvention(math.YUpLeftHanded, math.YUpRightHanded)
+	x, y, z = m.GetAttribute("position").GetXYZ(1)
+	assert.Equal(t, []float32{4, 5, -6}, []float32{x, y, z})
+	assert.Equal(t, []uint{0, 2, 1, 1, 3, 2}, m.indices.indices())
+}
+
+func TestMeshConvertConventionTangents(t *testing.T) {
+	bitangent := func(m *Mesh) math.Vec3 {
+		nx, ny, nz := m.GetAttribute("normal").GetXYZ(0)
+		tx, ty, tz, w := m.GetAttribute("tangent").GetXYZW(0)
+		n, tangent := math.Vec3{nx, ny, nz}, math.Vec3{tx, ty, tz}
+		b := n.Cross(&tangent)
+		return b.Mul(w)
+	}
+
+	// The bitangent is converted like the other directions.
+	m := newConventionTestMesh(VertexModeTriangles, []uint{0, 1, 2})
+	b := bitangent(m)
+	m.ConvertConvention(math.YUpLeftHanded, math.YUpRightHanded)
+	_, _, _, w := m.GetAttribute("tangent").GetXYZW(0)
+	assert.Equal(t, float32(-1), w)
+	c := math.ConventionMatrix(math.YUpLeftHanded, math.YUpRightHanded)
+	expected, converted := c.Mul3x1(&b), bitangent(m)
+	assertVec3(t, &expected, &converted, 1e-6)
+
+	// Rotations keep the handedness.
+	m = newConventionTestMesh(VertexModeTriangles, []uint{0, 1, 2})
+	b = bitangent(m)
+	m.ConvertConvention(math.ZUpRightHanded, math.YUpRightHanded)
+	_, _, _, w = m.GetAttribute("tangent").GetXYZW(0)
+	assert.Equal(t, float32(1), w)
+	c = math.ConventionMatrix(math.ZUpRightHanded, math.YUpRightHanded)
+	expected, converted = c.Mul3x1(&b), bitangent(m)
+	assertVec3(t, &expected, &converted, 1e-6)
+}
+
+func TestMeshReverseWinding(t *testing.T) {
+	tests := []struct {
+		mode              VertexMode
+		indices, expected []uint
+	}{
+		{VertexModeTriangleFan, []uint{0, 1, 2, 3}, []uint{0, 3, 2, 1}},
+		{VertexModeTriangleStrip, []uint{0, 1, 2, 3}, []uint{0, 0, 1, 2, 3}},
+		{VertexModeLines, []uint{0, 1, 2, 3}, []uint{0, 1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		m := newConventionTestMesh(test.mode, test.indices)
+		m.reverseWinding()
+		assert.Equal(t, test.expected, m.indices.indices())
+	}
+}
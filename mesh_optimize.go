@@ -0,0 +1,409 @@
+package dax
+
+import (
+	"sort"
+
+	"github.com/dlespiau/dax/math"
+)
+
+// This file contains a few passes reordering the triangles and vertices of an
+// indexed triangle Mesh to make better use of the GPU. They don't change what
+// is drawn on the screen but can make drawing it significantly faster and are
+// best run once, after creating or loading a mesh:
+//
+//   - OptimizeVertexCache reorders triangles so vertices shared between
+//     triangles are likely to still be in the post-transform vertex cache.
+//   - OptimizeOverdraw reorders clusters of triangles so the ones likely to
+//     occlude others are drawn first, maximizing early z rejection.
+//   - OptimizeVertexFetch reorders vertices in the order they are used by the
+//     index buffer, improving the locality of vertex fetches.
+//
+// Optimize runs the three passes in the right order.
+
+const (
+	// Size of the vertex cache modeled by the optimizer.
+	optimizerCacheSize = 32
+
+	// Tom Forsyth's "Linear-Speed Vertex Cache Optimisation" parameters.
+	// https://tomforsyth1000.github.io/papers/fast_vert_cache_opt.html
+	forsythCacheDecayPower   = 1.5
+	forsythLastTriScore      = 0.75
+	forsythValenceBoostScale = 2.0
+	forsythValenceBoostPower = 0.5
+)
+
+// Get returns the nth index.
+func (ib *IndexBuffer) Get(nth int) uint {
+	if ib.data16 != nil {
+		return uint(ib.data16[nth])
+	}
+	return uint(ib.data32[nth])
+}
+
+func (ib *IndexBuffer) indices() []uint {
+	indices := make([]uint, ib.Len())
+	for i := range indices {
+		indices[i] = ib.Get(i)
+	}
+	return indices
+}
+
+func (ib *IndexBuffer) setIndices(indices []uint) {
+	for i, index := range indices {
+		ib.Set(i, index)
+	}
+}
+
+// ACMR returns the Average Cache Miss Ratio of the index buffer, ie. the
+// average number of vertices that need to be transformed per triangle, with a
+// FIFO vertex cache of cacheSize entries. It ranges from 3 (no vertex is ever
+// reused) to ~0.5 for regular grids and is a good measure of the efficiency of
+// an index buffer.
+func (ib *IndexBuffer) ACMR(cacheSize int) float32 {
+	nTriangles := ib.Len() / 3
+	if nTriangles == 0 {
+		return 0
+	}
+
+	misses := fifoCacheMisses(ib.indices(), cacheSize, nil)
+	return float32(misses) / float32(nTriangles)
+}
+
+// fifoCacheMisses simulates a FIFO vertex cache and returns the number of
+// cache misses. If boundaries isn't nil, it's filled with the index of the
+// triangles for which all three vertices missed the cache.
+func fifoCacheMisses(indices []uint, cacheSize int, boundaries *[]int) int {
+	var cache []uint
+	misses := 0
+
+	inCache := func(v uint) bool {
+		for _, c := range cache {
+			if c == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	for t := 0; t < len(indices)/3; t++ {
+		triangleMisses := 0
+		for _, v := range indices[t*3 : t*3+3] {
+			if inCache(v) {
+				continue
+			}
+			triangleMisses++
+			cache = append(cache, v)
+			if len(cache) > cacheSize {
+				cache = cache[1:]
+			}
+		}
+		if triangleMisses == 3 && boundaries != nil {
+			*boundaries = append(*boundaries, t)
+		}
+		misses += triangleMisses
+	}
+
+	return misses
+}
+
+func (m *Mesh) canOptimize() bool {
+	return m.mode == VertexModeTriangles && m.indices.Len() >= 3
+}
+
+func (m *Mesh) numVertices() int {
+	if len(m.attributes) > 0 {
+		return m.attributes[0].Len()
+	}
+	return 0
+}
+
+func forsythVertexScore(cachePosition, remainingValence int) float32 {
+	if remainingValence == 0 {
+		// No triangle needs this vertex anymore.
+		return -1
+	}
+
+	var score float32
+	if cachePosition >= 0 {
+		if cachePosition < 3 {
+			// The vertex was used in the last triangle: a fixed score
+			// avoids favoring strips going in one direction.
+			score = forsythLastTriScore
+		} else {
+			scaler := 1 / float32(optimizerCacheSize-3)
+			score = 1 - float32(cachePosition-3)*scaler
+			score = math.Pow(score, forsythCacheDecayPower)
+		}
+	}
+
+	// Favor vertices with few remaining triangles to get rid of lone
+	// triangles.
+	score += forsythValenceBoostScale *
+		math.Pow(float32(remainingValence), -forsythValenceBoostPower)
+
+	return score
+}
+
+// OptimizeVertexCache reorders the triangles of the mesh to maximize the
+// post-transform vertex cache hit rate. This only works on indexed triangle
+// meshes, other meshes are left untouched.
+func (m *Mesh) OptimizeVertexCache() {
+	if !m.canOptimize() {
+		return
+	}
+
+	indices := m.indices.indices()
+	nTriangles := len(indices) / 3
+	nVertices := 0
+	for _, v := range indices {
+		if int(v) >= nVertices {
+			nVertices = int(v) + 1
+		}
+	}
+
+	// Triangles adjacent to each vertex, the first valence[v] entries of
+	// adjacency[offsets[v]:] are the triangles not emitted yet.
+	valence := make([]int, nVertices)
+	for _, v := range indices {
+		valence[v]++
+	}
+	offsets := make([]int, nVertices+1)
+	for v := 0; v < nVertices; v++ {
+		offsets[v+1] = offsets[v] + valence[v]
+	}
+	adjacency := make([]int, len(indices))
+	fill := make([]int, nVertices)
+	for i, v := range indices {
+		adjacency[offsets[v]+fill[v]] = i / 3
+		fill[v]++
+	}
+
+	cachePosition := make([]int, nVertices)
+	vertexScore := make([]float32, nVertices)
+	for v := range cachePosition {
+		cachePosition[v] = -1
+		vertexScore[v] = forsythVertexScore(-1, valence[v])
+	}
+
+	triangleScore := make([]float32, nTriangles)
+	emitted := make([]bool, nTriangles)
+	for t := 0; t < nTriangles; t++ {
+		for _, v := range indices[t*3 : t*3+3] {
+			triangleScore[t] += vertexScore[v]
+		}
+	}
+
+	output := make([]uint, 0, len(indices))
+	cache := make([]uint, 0, optimizerCacheSize+3)
+	newCache := make([]uint, 0, optimizerCacheSize+3)
+	cursor := 0
+
+	for len(output) < len(indices) {
+		// Find the best triangle to emit next, looking at triangles using
+		// vertices in the cache.
+		best := -1
+		var bestScore float32
+		for _, v := range cache {
+			for _, t := range adjacency[offsets[v] : offsets[v]+valence[v]] {
+				if best == -1 || triangleScore[t] > bestScore {
+					best = t
+					bestScore = triangleScore[t]
+				}
+			}
+		}
+
+		// Nothing in the cache is useful, pick the next triangle not
+		// emitted yet.
+		if best == -1 {
+			for emitted[cursor] {
+				cursor++
+			}
+			best = cursor
+		}
+
+		emitted[best] = true
+		triangle := indices[best*3 : best*3+3]
+		output = append(output, triangle...)
+
+		// Update the cache: the triangle vertices go to the front.
+		newCache = newCache[:0]
+		newCache = append(newCache, triangle...)
+		for _, v := range cache {
+			if v != triangle[0] && v != triangle[1] && v != triangle[2] {
+				newCache = append(newCache, v)
+			}
+		}
+		cache, newCache = newCache, cache
+
+		// Remove the triangle from the adjacency lists.
+		for _, v := range triangle {
+			list := adjacency[offsets[v] : offsets[v]+valence[v]]
+			for i, t := range list {
+				if t == best {
+					list[i] = list[len(list)-1]
+					break
+				}
+			}
+			valence[v]--
+		}
+
+		// Update scores of vertices in the cache or evicted from it, and of
+		// their triangles.
+		for i, v := range cache {
+			position := i
+			if position >= optimizerCacheSize {
+				position = -1
+			}
+			cachePosition[v] = position
+
+			score := forsythVertexScore(position, valence[v])
+			delta := score - vertexScore[v]
+			vertexScore[v] = score
+			for _, t := range adjacency[offsets[v] : offsets[v]+valence[v]] {
+				triangleScore[t] += delta
+			}
+		}
+		if len(cache) > optimizerCacheSize {
+			cache = cache[:optimizerCacheSize]
+		}
+	}
+
+	m.indices.setIndices(output)
+}
+
+type triangleCluster struct {
+	start, end int
+	sortKey    float32
+}
+
+// OptimizeOverdraw reorders clusters of triangles to reduce overdraw: clusters
+// facing outwards are drawn first so they occlude the rest of the mesh and
+// let the GPU discard hidden fragments early. Clusters are delimited by the
+// triangles for which no vertex is in the vertex cache, so this pass doesn't
+// degrade the ordering done by OptimizeVertexCache and should run after it.
+//
+// This only works on indexed triangle meshes with a "position" attribute,
+// other meshes are left untouched.
+func (m *Mesh) OptimizeOverdraw() {
+	positions := m.GetAttribute("position")
+	if !m.canOptimize() || positions == nil || positions.NumComponents < 3 {
+		return
+	}
+
+	indices := m.indices.indices()
+	nTriangles := len(indices) / 3
+
+	var boundaries []int
+	fifoCacheMisses(indices, optimizerCacheSize, &boundaries)
+	boundaries = append(boundaries, nTriangles)
+
+	// Centroid of the whole mesh.
+	var meshCentroid math.Vec3
+	for _, v := range indices {
+		x, y, z := positions.GetXYZ(int(v))
+		meshCentroid.AddWith(&math.Vec3{x, y, z})
+	}
+	meshCentroid.MulWith(1 / float32(len(indices)))
+
+	clusters := make([]triangleCluster, 0, len(boundaries))
+	for i := 0; i < len(boundaries)-1; i++ {
+		c := triangleCluster{
+			start: boundaries[i],
+			end:   boundaries[i+1],
+		}
+
+		// Area weighted centroid and normal of the cluster.
+		var centroid, normal math.Vec3
+		var area float32
+		for t := c.start; t < c.end; t++ {
+			var p [3]math.Vec3
+			for j := range p {
+				x, y, z := positions.GetXYZ(int(indices[t*3+j]))
+				p[j] = math.Vec3{x, y, z}
+			}
+			e1 := p[1].Sub(&p[0])
+			e2 := p[2].Sub(&p[0])
+			n := e1.Cross(&e2)
+			a := n.Len()
+
+			center := p[0].Add(&p[1])
+			center.AddWith(&p[2])
+			centroid.AddScaledVec(a/3, &center)
+			normal.AddWith(&n)
+			area += a
+		}
+		if area > 0 {
+			centroid.MulWith(1 / area)
+		}
+		if normal.Len() > 0 {
+			normal.Normalize()
+		}
+
+		direction := centroid.Sub(&meshCentroid)
+		c.sortKey = direction.Dot(&normal)
+		clusters = append(clusters, c)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].sortKey > clusters[j].sortKey
+	})
+
+	output := make([]uint, 0, len(indices))
+	for _, c := range clusters {
+		output = append(output, indices[c.start*3:c.end*3]...)
+	}
+
+	m.indices.setIndices(output)
+}
+
+// OptimizeVertexFetch reorders the vertices of the mesh in the order they are
+// first referenced by the index buffer. Vertices not referenced by any index
+// are removed. This only works on indexed meshes, other meshes are left
+// untouched.
+func (m *Mesh) OptimizeVertexFetch() {
+	if !m.HasIndices() {
+		return
+	}
+
+	const unused = ^uint(0)
+
+	nVertices := m.numVertices()
+	remap := make([]uint, nVertices)
+	for i := range remap {
+		remap[i] = unused
+	}
+
+	indices := m.indices.indices()
+	next := uint(0)
+	for i, v := range indices {
+		if remap[v] == unused {
+			remap[v] = next
+			next++
+		}
+		indices[i] = remap[v]
+	}
+
+	for i := range m.attributes {
+		ab := &m.attributes[i]
+		n := ab.NumComponents
+		data := make([]float32, int(next)*n)
+		for v := 0; v < ab.Len(); v++ {
+			if remap[v] == unused {
+				continue
+			}
+			copy(data[int(remap[v])*n:], ab.Data[v*n:(v+1)*n])
+		}
+		ab.Data = data
+	}
+
+	m.indices.setIndices(indices)
+	m.InvalidateBounds()
+}
+
+// Optimize runs all the mesh optimization passes: OptimizeVertexCache,
+// OptimizeOverdraw and OptimizeVertexFetch.
+func (m *Mesh) Optimize() {
+	m.OptimizeVertexCache()
+	m.OptimizeOverdraw()
+	m.OptimizeVertexFetch()
+}
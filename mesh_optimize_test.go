@@ -0,0 +1,180 @@
+package dax
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestGridMesh creates a n x n grid of quads, with shuffled triangles.
+func newTestGridMesh(n int) *Mesh {
+	var positions []float32
+	var indices []uint
+
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			positions = append(positions, float32(x), float32(y), 0)
+		}
+	}
+
+	var triangles [][3]uint
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			a := uint(y*(n+1) + x)
+			b := a + 1
+			c := a + uint(n+1)
+			d := c + 1
+			triangles = append(triangles, [3]uint{a, b, d}, [3]uint{a, d, c})
+		}
+	}
+
+	r := rand.New(rand.NewSource(42))
+	for i := range triangles {
+		j := r.Intn(i + 1)
+		triangles[i], triangles[j] = triangles[j], triangles[i]
+	}
+	for _, t := range triangles {
+		indices = append(indices, t[0], t[1], t[2])
+	}
+
+	m := NewMesh()
+	m.AddAttribute("position", positions, 3)
+	m.AddIndices(indices)
+	return m
+}
+
+// meshTriangles returns the list of triangles of the mesh, as sorted vertex
+// positions, each triangle starting with its smallest vertex to preserve the
+// winding.
+func meshTriangles(m *Mesh) [][9]float32 {
+	positions := m.GetAttribute("position")
+	var triangles [][9]float32
+
+	for t := 0; t < m.indices.Len()/3; t++ {
+		var v [3][3]float32
+		for i := range v {
+			x, y, z := positions.GetXYZ(int(m.indices.Get(t*3 + i)))
+			v[i] = [3]float32{x, y, z}
+		}
+
+		less := func(a, b [3]float32) bool {
+			for i := range a {
+				if a[i] != b[i] {
+					return a[i] < b[i]
+				}
+			}
+			return false
+		}
+		first := 0
+		for i := 1; i < 3; i++ {
+			if less(v[i], v[first]) {
+				first = i
+			}
+		}
+
+		var triangle [9]float32
+		for i := 0; i < 3; i++ {
+			copy(triangle[i*3:], v[(first+i)%3][:])
+		}
+		triangles = append(triangles, triangle)
+	}
+
+	sort.Slice(triangles, func(i, j int) bool {
+		for k := range triangles[i] {
+			if triangles[i][k] != triangles[j][k] {
+				return triangles[i][k] < triangles[j][k]
+			}
+		}
+		return false
+	})
+
+	return triangles
+}
+
+func TestACMR(t *testing.T) {
+	m := NewMesh()
+	m.AddIndices([]uint{0, 1, 2, 0, 2, 3})
+	assertFloat(t, 2, m.indices.ACMR(16), 1e-6)
+
+	m.AddIndices([]uint{0, 1, 2, 3, 4, 5})
+	assertFloat(t, 3, m.indices.ACMR(16), 1e-6)
+
+	// With a cache of 3 entries, reusing 0 is a miss.
+	m.AddIndices([]uint{0, 1, 2, 3, 4, 5, 0, 1, 2})
+	assertFloat(t, 3, m.indices.ACMR(3), 1e-6)
+}
+
+func TestOptimizeVertexCache(t *testing.T) {
+	m := newTestGridMesh(20)
+	before := meshTriangles(m)
+	acmrBefore := m.indices.ACMR(optimizerCacheSize)
+
+	m.OptimizeVertexCache()
+
+	assert.Equal(t, before, meshTriangles(m))
+	acmr := m.indices.ACMR(optimizerCacheSize)
+	assert.True(t, acmr < acmrBefore, "ACMR %f not better than %f", acmr, acmrBefore)
+	assert.True(t, acmr < 1, "ACMR %f too high", acmr)
+}
+
+func TestOptimizeOverdraw(t *testing.T) {
+	m := newTestGridMesh(20)
+	before := meshTriangles(m)
+
+	m.OptimizeVertexCache()
+	acmr := m.indices.ACMR(optimizerCacheSize)
+	m.OptimizeOverdraw()
+
+	assert.Equal(t, before, meshTriangles(m))
+	assertFloat(t, acmr, m.indices.ACMR(optimizerCacheSize), 1e-6)
+}
+
+func TestOptimizeOverdrawDepth(t *testing.T) {
+	// Two quads facing +Z, one behind the other. The back quad comes first
+	// in the index buffer, the front one, occluding it, should be drawn
+	// first.
+	m := NewMesh()
+	m.AddAttribute("position", []float32{
+		0, 0, -1, 1, 0, -1, 1, 1, -1, 0, 1, -1,
+		0, 0, 1, 1, 0, 1, 1, 1, 1, 0, 1, 1,
+	}, 3)
+	m.AddIndices([]uint{
+		0, 1, 2, 0, 2, 3,
+		4, 5, 6, 4, 6, 7,
+	})
+
+	m.OptimizeOverdraw()
+
+	assert.Equal(t, []uint{4, 5, 6, 4, 6, 7, 0, 1, 2, 0, 2, 3}, m.indices.indices())
+}
+
+func TestOptimizeVertexFetch(t *testing.T) {
+	m := newTestGridMesh(4)
+	// Add an unused vertex at the end.
+	positions := m.GetAttribute("position")
+	m.AddAttribute("position", append(positions.Data, 100, 100, 100), 3)
+	before := meshTriangles(m)
+
+	m.OptimizeVertexFetch()
+
+	assert.Equal(t, before, meshTriangles(m))
+	assert.Equal(t, 25, m.GetAttribute("position").Len())
+
+	next := uint(0)
+	for i := 0; i < m.indices.Len(); i++ {
+		index := m.indices.Get(i)
+		assert.True(t, index <= next)
+		if index == next {
+			next++
+		}
+	}
+}
+
+func TestOptimizeNonIndexed(t *testing.T) {
+	m := NewMesh()
+	m.AddAttribute("position", []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}, 3)
+	m.Optimize()
+	assert.Equal(t, []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}, m.GetAttribute("position").Data)
+}
@@ -1,9 +1,37 @@
 package dax
 
+import (
+	"github.com/dlespiau/dax/math"
+)
+
 // MeshRenderer is a component rendering a Mesh with a Material.
 type MeshRenderer struct {
-	mesher   Mesher
-	material Material
+	mesher     Mesher
+	material   Material
+	properties *PropertyBlock
+	onDraw     func(c *DrawContext)
+}
+
+// DrawContext is given to the function registered with MeshRenderer.OnDraw.
+// It's only valid during the call: the renderer reuses it for the next draw.
+type DrawContext struct {
+	Camera Camera
+	Node   *Node
+	// Instance is, when Node is part of a subtree drawn by an instance, the
+	// instance being drawn, nil otherwise. See NewInstance.
+	Instance *Node
+	// Time is the number of seconds elapsed since the application main
+	// loop started, see Application.Time.
+	Time float64
+
+	properties *PropertyBlock
+}
+
+// SetUniform sets the value of a uniform for this draw only, overriding the
+// material and the property block. It accepts the same types as
+// Material.SetUniform.
+func (c *DrawContext) SetUniform(name string, value interface{}) {
+	c.properties.Set(name, value)
 }
 
 // NewMeshRenderer creates a new MeshRenderer.
@@ -14,8 +42,49 @@ func NewMeshRenderer(mesher Mesher, material Material) *MeshRenderer {
 	}
 }
 
+// SetPropertyBlock sets uniform values overriding the ones of the material
+// when drawing this mesh. nil removes the overrides.
+func (mr *MeshRenderer) SetPropertyBlock(properties *PropertyBlock) {
+	mr.properties = properties
+}
+
+// PropertyBlock returns the property block set with SetPropertyBlock.
+func (mr *MeshRenderer) PropertyBlock() *PropertyBlock {
+	return mr.properties
+}
+
+// OnDraw registers f to be called just before each draw of the mesh, to set
+// uniforms depending on the camera, the node or the time:
+//
+//	mr.OnDraw(func(c *dax.DrawContext) {
+//		c.SetUniform("pulse", math.Sin(float32(c.Time)))
+//	})
+//
+// nil removes the callback. Nodes with a draw callback are always drawn with
+// their own draw call.
+func (mr *MeshRenderer) OnDraw(f func(c *DrawContext)) {
+	mr.onDraw = f
+}
+
+// drawProperties returns the property block to draw with: the one set with
+// SetPropertyBlock, with the uniforms set by the OnDraw callback on top.
+// scratch holds the values and is reused between draws.
+func (mr *MeshRenderer) drawProperties(scratch *PropertyBlock, c *DrawContext) *PropertyBlock {
+	if mr.onDraw == nil {
+		return mr.properties
+	}
+
+	scratch.Clear()
+	if mr.properties != nil {
+		scratch.values.values = append(scratch.values.values, mr.properties.values.values...)
+	}
+	c.properties = scratch
+	mr.onDraw(c)
+	return scratch
+}
+
 // Update implements Updater for MeshRenderer.
-func (mr *MeshRenderer) Update(time float64) {
+func (mr *MeshRenderer) Update(dt float64) {
 
 }
 
@@ -23,3 +92,37 @@ func (mr *MeshRenderer) Update(time float64) {
 func (mr *MeshRenderer) Draw() {
 
 }
+
+// Bounds implements Bounder. It returns the bounding box of the rendered mesh,
+// without building the mesh when the Mesher is also a Bounder.
+func (mr *MeshRenderer) Bounds() math.AABB {
+	if b, ok := mr.mesher.(Bounder); ok {
+		return b.Bounds()
+	}
+	return mr.mesher.GetMesh().Bounds()
+}
+
+// BoundingSphere implements SphereBounder. Meshers that are neither
+// SphereBounders nor Bounders have their mesh built.
+func (mr *MeshRenderer) BoundingSphere() math.BoundingSphere {
+	switch b := mr.mesher.(type) {
+	case SphereBounder:
+		return b.BoundingSphere()
+	case Bounder:
+		bounds := b.Bounds()
+		return bounds.BoundingSphere()
+	}
+	return mr.mesher.GetMesh().BoundingSphere()
+}
+
+func getMeshRenderer(node *Node) *MeshRenderer {
+	var mr *MeshRenderer
+	var ok bool
+	for i := range node.components {
+		if mr, ok = node.components[i].(*MeshRenderer); ok {
+			break
+		}
+	}
+
+	return mr
+}
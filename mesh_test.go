@@ -0,0 +1,58 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeshBounds(t *testing.T) {
+	m := NewMesh()
+	bounds := m.Bounds()
+	assert.True(t, bounds.IsEmpty())
+
+	m.AddAttribute("position", []float32{
+		-1, 0, 2,
+		3, -4, 0,
+		0, 5, -6,
+	}, 3)
+	bounds = m.Bounds()
+	assert.Equal(t, math.Vec3{-1, -4, -6}, bounds.Min)
+	assert.Equal(t, math.Vec3{3, 5, 2}, bounds.Max)
+
+	// Modifying positions in place needs an explicit invalidation.
+	positions := m.GetAttribute("position")
+	positions.SetXYZ(0, -10, 0, 2)
+	bounds = m.Bounds()
+	assert.Equal(t, math.Vec3{-1, -4, -6}, bounds.Min)
+	m.InvalidateBounds()
+	bounds = m.Bounds()
+	assert.Equal(t, math.Vec3{-10, -4, -6}, bounds.Min)
+}
+
+func TestMeshBoundingSphere(t *testing.T) {
+	m := NewMesh()
+	sphere := m.BoundingSphere()
+	assert.True(t, sphere.IsEmpty())
+
+	m.AddAttribute("position", []float32{
+		-1, 0, 0,
+		1, 0, 0,
+		0, .5, 0,
+	}, 3)
+	sphere = m.BoundingSphere()
+	assert.Equal(t, math.Vec3{0, .25, 0}, sphere.Center)
+	assertFloat(t, math.Sqrt(1+.25*.25), sphere.Radius, 1e-6)
+
+	// Replacing the positions updates the bounding volumes.
+	m.AddAttribute("position", []float32{
+		0, 0, 0,
+		0, 0, 4,
+	}, 3)
+	sphere = m.BoundingSphere()
+	assert.Equal(t, math.Vec3{0, 0, 2}, sphere.Center)
+	assertFloat(t, 2, sphere.Radius, 1e-6)
+	bounds := m.Bounds()
+	assert.Equal(t, math.Vec3{0, 0, 4}, bounds.Max)
+}
@@ -0,0 +1,41 @@
+package dax
+
+import (
+	"sync"
+)
+
+// MutationQueue collects functions pushed from any goroutine to run them
+// later, in order, from the goroutine that owns the data they modify.
+type MutationQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	// running is kept around to reuse its storage between flushes.
+	running []func()
+}
+
+// Push queues f. Push can be called from any goroutine.
+func (q *MutationQueue) Push(f func()) {
+	q.mu.Lock()
+	q.pending = append(q.pending, f)
+	q.mu.Unlock()
+}
+
+// Len returns the number of functions waiting to be run.
+func (q *MutationQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Flush runs all the queued functions in the order they were pushed.
+// Functions pushed while flushing will run at the next Flush.
+func (q *MutationQueue) Flush() {
+	q.mu.Lock()
+	q.pending, q.running = q.running[:0], q.pending
+	q.mu.Unlock()
+
+	for i, f := range q.running {
+		f()
+		q.running[i] = nil
+	}
+}
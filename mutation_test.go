@@ -0,0 +1,48 @@
+package dax
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutationQueue(t *testing.T) {
+	var q MutationQueue
+	var values []int
+
+	q.Push(func() { values = append(values, 1) })
+	q.Push(func() {
+		values = append(values, 2)
+		// Pushed while flushing, runs at the next flush.
+		q.Push(func() { values = append(values, 3) })
+	})
+	assert.Equal(t, 2, q.Len())
+	assert.Empty(t, values)
+
+	q.Flush()
+	assert.Equal(t, []int{1, 2}, values)
+	assert.Equal(t, 1, q.Len())
+
+	q.Flush()
+	assert.Equal(t, []int{1, 2, 3}, values)
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestMutationQueueGoroutines(t *testing.T) {
+	var q MutationQueue
+	var wg sync.WaitGroup
+	n := NewNode()
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.Push(func() { n.TranslateX(1) })
+		}()
+	}
+	wg.Wait()
+
+	q.Flush()
+	assert.Equal(t, float32(10), n.GetPosition().X())
+}
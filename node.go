@@ -1,10 +1,16 @@
 package dax
 
 import (
+	"strings"
+
 	"github.com/dlespiau/dax/math"
 )
 
 type Node struct {
+	name string
+	// graph is the scene graph whose root is this node.
+	graph *SceneGraph
+
 	// Grapher
 	parent   Grapher
 	children []Grapher
@@ -22,9 +28,27 @@ type Node struct {
 	// manipulation. In other word, internal passes on the scene graph, like
 	// rendering passes.
 	worldTransform math.Transform
+	// previousWorldTransform is the world transform the last time the node
+	// was drawn.
+	previousWorldTransform      math.Transform
+	previousWorldTransformValid bool
 
 	// List of components.
 	components []interface{}
+
+	// Instances, see NewInstance. prototype is the root of the subtree
+	// drawn by an instance and instanceRoot the root of its mirror.
+	// Nodes of the mirror point to the prototype node they mirror with
+	// instanced.
+	prototype    *Node
+	instanceRoot *Node
+	instanced    *Node
+	// version is the last change of the subtree rooted at this node, see
+	// touch. Instances remember the version of their prototype they
+	// mirror in synced, and whether the prototype has nested instances.
+	version uint64
+	synced  uint64
+	nested  bool
 }
 
 func NewNode() *Node {
@@ -38,6 +62,17 @@ func (n *Node) Init() {
 	n.scale = math.Vec3{1, 1, 1}
 }
 
+// SetName sets the name of the node. Names don't need to be unique, see Find
+// and FindByPath.
+func (n *Node) SetName(name string) {
+	n.name = name
+}
+
+// Name returns the name of the node.
+func (n *Node) Name() string {
+	return n.name
+}
+
 func (n *Node) GetPosition() *math.Vec3 {
 	return &n.position
 }
@@ -46,41 +81,41 @@ func (n *Node) SetPosition(x, y, z float32) {
 	n.position[0] = x
 	n.position[1] = y
 	n.position[2] = z
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) SetPositionV(position *math.Vec3) {
 	n.position = *position
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) Translate(tx, ty, tz float32) {
 	n.position[0] += tx
 	n.position[1] += ty
 	n.position[2] += tz
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) TranslateV(t *math.Vec3) {
 	n.position[0] += t[0]
 	n.position[1] += t[1]
 	n.position[2] += t[2]
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) TranslateX(tx float32) {
 	n.position[0] += tx
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) TranslateY(ty float32) {
 	n.position[1] += ty
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) TranslateZ(tz float32) {
 	n.position[2] += tz
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) GetRotation() *math.Quaternion {
@@ -89,13 +124,13 @@ func (n *Node) GetRotation() *math.Quaternion {
 
 func (n *Node) SetRotation(q *math.Quaternion) {
 	n.rotation = *q
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) RotateAroundAxis(axis *math.Vec3, angle float32) {
 	q := math.QuatRotate(angle, axis)
 	n.rotation.MulWith(&q)
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) RotateX(angle float32) {
@@ -118,41 +153,119 @@ func (n *Node) SetScale(sx, sy, sz float32) {
 	n.scale[0] = sx
 	n.scale[1] = sy
 	n.scale[2] = sz
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) SetScaleV(s *math.Vec3) {
 	n.scale = *s
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) Scale(sx, sy, sz float32) {
 	n.scale[0] *= sx
 	n.scale[1] *= sy
 	n.scale[2] *= sz
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) ScaleV(s *math.Vec3) {
 	n.scale[0] *= s[0]
 	n.scale[1] *= s[1]
 	n.scale[2] *= s[2]
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) ScaleX(sx float32) {
 	n.scale[0] *= sx
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) ScaleY(sy float32) {
 	n.scale[1] *= sy
-	n.transformValid = false
+	n.invalidateTransform()
 }
 
 func (n *Node) ScaleZ(sz float32) {
 	n.scale[2] *= sz
-	n.transformValid = false
+	n.invalidateTransform()
+}
+
+// MirrorX mirrors the node along its local X axis, ie. negates its scale on
+// X. Mirroring flips the winding of the node triangles, something the renderer
+// compensates for when drawing.
+func (n *Node) MirrorX() {
+	n.scale[0] = -n.scale[0]
+	n.invalidateTransform()
+}
+
+// MirrorY mirrors the node along its local Y axis. See MirrorX.
+func (n *Node) MirrorY() {
+	n.scale[1] = -n.scale[1]
+	n.invalidateTransform()
+}
+
+// MirrorZ mirrors the node along its local Z axis. See MirrorX.
+func (n *Node) MirrorZ() {
+	n.scale[2] = -n.scale[2]
+	n.invalidateTransform()
+}
+
+// Mirror reflects the node about the plane of points p satisfying
+// dot(normal, p) = distance, expressed in the parent space. normal must be
+// normalized.
+func (n *Node) Mirror(normal *math.Vec3, distance float32) {
+	reflection := math.Reflect3D(normal, distance)
+
+	position := n.position.Vec4(1)
+	position = reflection.Mul4x1(&position)
+	n.position = position.Vec3()
+
+	// The reflection matrix R is an improper rotation. R * rotation * scale
+	// can be rewritten as (R * rotation * M) * (M * scale), with M a mirror
+	// along X: R * rotation * M is a proper rotation and M * scale is still
+	// a diagonal scale matrix.
+	rotation := n.rotation.Mat4()
+	mirror := math.Scale3D(-1, 1, 1)
+	proper := reflection.Mul4(&rotation)
+	proper.Mul4With(&mirror)
+	n.rotation = math.Mat4ToQuat(&proper)
+	n.scale[0] = -n.scale[0]
+
+	n.invalidateTransform()
+}
+
+// isMirrored returns true if the node world transform flips the winding of
+// triangles. The world transform must be up to date.
+func (n *Node) isMirrored() bool {
+	return n.worldTransform.AsMat4().Det() < 0
+}
+
+// PreviousWorldTransform returns the local space to world space transform the
+// last time the node was drawn. Before the node is first drawn, it's the current
+// world transform. Comparing it with the current world transform gives the
+// motion of the node between two frames.
+func (n *Node) PreviousWorldTransform() *math.Mat4 {
+	if !n.previousWorldTransformValid {
+		return n.worldTransform.AsMat4()
+	}
+	return n.previousWorldTransform.AsMat4()
+}
+
+// WorldBounds returns the world space bounding box of the mesh rendered by the
+// node, an empty box if the node doesn't render a mesh. The world transform is
+// expected to be up to date.
+func (n *Node) WorldBounds() math.AABB {
+	mr := getMeshRenderer(n)
+	if mr == nil {
+		return math.EmptyAABB()
+	}
+	bounds := mr.Bounds()
+	return bounds.Transform(n.worldTransform.AsMat4())
+}
+
+func (n *Node) savePreviousWorldTransform() {
+	n.previousWorldTransform = n.worldTransform
+	n.previousWorldTransformValid = true
 }
 
 func (n *Node) updateTransform() {
@@ -167,6 +280,13 @@ func (n *Node) updateTransform() {
 	n.worldTransformValid = false
 }
 
+// invalidateTransform marks the local transform as needing an update after a
+// change of the position, rotation or scale.
+func (n *Node) invalidateTransform() {
+	n.transformValid = false
+	n.touch()
+}
+
 func (n *Node) getTransform() *math.Transform {
 	n.updateTransform()
 	return &n.transform
@@ -208,12 +328,21 @@ func (n *Node) updateWorldTransform(force bool) {
 		node := child.(*Node)
 		node.updateWorldTransform(force)
 	}
+
+	if n.prototype != nil {
+		sync := n.instanceDirty()
+		if sync {
+			n.syncInstance()
+		}
+		n.instanceRoot.updateWorldTransform(force || sync)
+	}
 }
 
 // Components
 
 func (n *Node) AddComponent(c interface{}) *Node {
 	n.components = append(n.components, c)
+	n.touch()
 	return n
 }
 
@@ -229,11 +358,118 @@ func (n *Node) setParent(parent Grapher) {
 	n.parent = parent
 }
 
-// AddChild adds a child the node.
+// AddChild adds a child the node. A child already parented to another node is
+// removed from its previous parent first. AddChild panics if child is n or one
+// of its ancestors.
 func (n *Node) AddChild(child Grapher) {
 	childNode := child.(*Node)
+	for a := n; a != nil; a = a.parentNode() {
+		if a == childNode {
+			panic("scenegraph: node cycle")
+		}
+	}
+
+	childNode.RemoveFromParent()
 	childNode.setParent(n)
+	childNode.worldTransformValid = false
 	n.children = append(n.children, child)
+	n.touch()
+
+	if graph := n.sceneGraph(); graph != nil {
+		graph.events.emit(Event{Kind: EventNodeAdded, Node: childNode, Parent: n})
+	}
+}
+
+// insertChild adds child to n, at index in its children.
+func (n *Node) insertChild(child *Node, index int) {
+	n.AddChild(child)
+	last := len(n.children) - 1
+	if index < 0 || index >= last {
+		return
+	}
+
+	// Don't modify the slice in place, it may be being iterated over.
+	children := make([]Grapher, 0, len(n.children))
+	children = append(children, n.children[:index]...)
+	children = append(children, child)
+	n.children = append(children, n.children[index:last]...)
+}
+
+// RemoveChild removes child from the children of n. It does nothing if child
+// isn't a child of n. Removing children while iterating over the slice
+// returned by GetChildren is safe.
+func (n *Node) RemoveChild(child Grapher) {
+	for i := range n.children {
+		if n.children[i] != child {
+			continue
+		}
+
+		// Don't modify the slice in place, it may be being iterated
+		// over.
+		children := make([]Grapher, 0, len(n.children)-1)
+		children = append(children, n.children[:i]...)
+		n.children = append(children, n.children[i+1:]...)
+		n.touch()
+		childNode := child.(*Node)
+		childNode.setParent(nil)
+		childNode.worldTransformValid = false
+
+		if graph := n.sceneGraph(); graph != nil {
+			graph.events.emit(Event{Kind: EventNodeRemoved, Node: childNode, Parent: n})
+		}
+		return
+	}
+}
+
+// sceneGraph returns the scene graph n is part of, nil if it isn't part of
+// one.
+func (n *Node) sceneGraph() *SceneGraph {
+	a := n
+	for a.parentNode() != nil {
+		a = a.parentNode()
+	}
+	return a.graph
+}
+
+// RemoveFromParent removes n from the children of its parent. It does nothing
+// if n has no parent.
+func (n *Node) RemoveFromParent() {
+	if parent := n.parentNode(); parent != nil {
+		parent.RemoveChild(n)
+	}
+}
+
+// computeWorldTransform returns the local space to world space transform of
+// n from the local transforms of n and its ancestors, without relying on the
+// world transforms being up to date.
+func (n *Node) computeWorldTransform() math.Mat4 {
+	world := *n.GetTransform()
+	for a := n.parentNode(); a != nil; a = a.parentNode() {
+		world = a.GetTransform().Mul4(&world)
+	}
+	return world
+}
+
+// SetParentKeepWorldTransform re-parents n to parent, changing the position,
+// rotation and scale of n so it stays at the same place in world space. A nil
+// parent removes n from its parent. Shear, that rotations and scales can't
+// express, is lost, see math.Mat4.Decompose.
+func (n *Node) SetParentKeepWorldTransform(parent *Node) {
+	world := n.computeWorldTransform()
+	if parent != nil {
+		parentWorld := parent.computeWorldTransform()
+		parentInverse := parentWorld.Inverse()
+		world = parentInverse.Mul4(&world)
+	}
+
+	if parent != nil {
+		parent.AddChild(n)
+	} else {
+		n.RemoveFromParent()
+	}
+
+	n.position, n.rotation, n.scale = world.Decompose()
+	n.invalidateTransform()
 }
 
 // AddChildren adds a number of children to the node n.
@@ -247,3 +483,45 @@ func (n *Node) AddChildren(children ...Grapher) {
 func (n *Node) GetChildren() []Grapher {
 	return n.children
 }
+
+// child returns the first child of n named name.
+func (n *Node) child(name string) *Node {
+	for _, child := range n.children {
+		if node := child.(*Node); node.name == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// Find returns the first descendant of n named name, in depth-first
+// pre-order, nil if there's none.
+func (n *Node) Find(name string) *Node {
+	for _, child := range n.children {
+		node := child.(*Node)
+		if node.name == name {
+			return node
+		}
+		if found := node.Find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// FindByPath returns the descendant of n at path, a list of node names
+// separated by '/', eg. "arm/hand" for the child named "hand" of the child
+// named "arm" of n. When several children have the same name, the first one is
+// used. FindByPath returns nil if there's no node at path.
+func (n *Node) FindByPath(path string) *Node {
+	node := n
+	for _, name := range strings.Split(path, "/") {
+		if name == "" {
+			continue
+		}
+		if node = node.child(name); node == nil {
+			return nil
+		}
+	}
+	return node
+}
@@ -134,6 +134,78 @@ func TestAddChild(t *testing.T) {
 	assert.Equal(t, children[0], c)
 }
 
+func TestRemoveChild(t *testing.T) {
+	p := NewNode()
+	a, b, c := NewNode(), NewNode(), NewNode()
+	p.AddChildren(a, b, c)
+
+	// Removing while iterating.
+	for _, child := range p.GetChildren() {
+		if child != b {
+			p.RemoveChild(child)
+		}
+	}
+	assert.Equal(t, []Grapher{b}, p.GetChildren())
+	assert.Nil(t, a.GetParent())
+
+	b.RemoveFromParent()
+	assert.Equal(t, 0, len(p.GetChildren()))
+	assert.Nil(t, b.GetParent())
+
+	// Not a child, nor parented.
+	p.RemoveChild(b)
+	b.RemoveFromParent()
+}
+
+func TestReparent(t *testing.T) {
+	p, q, c := NewNode(), NewNode(), NewNode()
+	p.SetPosition(10, 0, 0)
+	p.AddChild(c)
+
+	// Moved from p to q.
+	q.AddChild(c)
+	assert.Equal(t, q, c.GetParent())
+	assert.Equal(t, 0, len(p.GetChildren()))
+
+	// The world transform follows the new parent.
+	p.AddChild(c)
+	p.updateWorldTransform(false)
+	q.AddChild(c)
+	q.updateWorldTransform(false)
+	assertFloat(t, 0, c.worldTransform[12], 1e-6)
+
+	// Cycles.
+	assert.Panics(t, func() { c.AddChild(c) })
+	assert.Panics(t, func() { c.AddChild(q) })
+	assert.Equal(t, q, c.GetParent())
+}
+
+func TestSetParentKeepWorldTransform(t *testing.T) {
+	p := NewNode()
+	p.SetPosition(10, 0, 0)
+	p.RotateY(math.Pi / 2)
+	p.SetScale(2, 2, 2)
+
+	c := NewNode()
+	c.SetPosition(1, 2, 3)
+	c.RotateX(math.Pi / 4)
+	world := *c.GetTransform()
+
+	c.SetParentKeepWorldTransform(p)
+	assert.Equal(t, p, c.GetParent())
+	p.updateWorldTransform(false)
+	for i := range world {
+		assert.InDelta(t, world[i], c.worldTransform[i], 1e-5)
+	}
+
+	c.SetParentKeepWorldTransform(nil)
+	assert.Nil(t, c.GetParent())
+	assert.Equal(t, 0, len(p.GetChildren()))
+	for i := range world {
+		assert.InDelta(t, world[i], c.GetTransform()[i], 1e-5)
+	}
+}
+
 func TestWorldTransform(t *testing.T) {
 	n := NewNode()
 	p := NewNode()
@@ -158,3 +230,83 @@ func TestWorldTransform(t *testing.T) {
 	w = q.worldTransform.LocalToWorld(&math.Vec3{0, 0, 0})
 	assertVec3(t, &math.Vec3{4, 0, 0}, &w, 1e-6)
 }
+
+func TestMirror(t *testing.T) {
+	n := NewNode()
+	n.MirrorX()
+	assertVec3(t, &math.Vec3{-1, 1, 1}, n.GetScale(), 1e-6)
+	n.updateWorldTransform(false)
+	assert.True(t, n.isMirrored())
+
+	n.MirrorY()
+	assertVec3(t, &math.Vec3{-1, -1, 1}, n.GetScale(), 1e-6)
+	n.updateWorldTransform(false)
+	assert.False(t, n.isMirrored())
+
+	n.MirrorZ()
+	assertVec3(t, &math.Vec3{-1, -1, -1}, n.GetScale(), 1e-6)
+}
+
+func TestMirrorPlane(t *testing.T) {
+	n := NewNode()
+	n.SetPosition(1, 2, 3)
+	n.RotateY(math.Pi / 3)
+	n.RotateX(math.Pi / 5)
+	n.SetScale(1, 2, 3)
+
+	normal := math.Vec3{1, 1, 0}
+	normal.Normalize()
+	reflection := math.Reflect3D(&normal, 1)
+	expected := reflection.Mul4(n.GetTransform())
+
+	n.Mirror(&normal, 1)
+	transform := n.GetTransform()
+	assert.True(t, expected.EqualThreshold(transform, 1e-3),
+		"expected\n%v\ngot\n%v", expected.String(), transform.String())
+
+	n.updateWorldTransform(false)
+	assert.True(t, n.isMirrored())
+}
+
+func TestPreviousWorldTransform(t *testing.T) {
+	n := NewNode()
+	n.Translate(1, 0, 0)
+	n.updateWorldTransform(false)
+
+	// Not drawn yet, the previous transform is the current one.
+	assert.Equal(t, n.worldTransform.AsMat4(), n.PreviousWorldTransform())
+
+	n.savePreviousWorldTransform()
+	n.Translate(1, 0, 0)
+	n.updateWorldTransform(false)
+
+	previous := n.PreviousWorldTransform()
+	assertVec3(t, &math.Vec3{1, 0, 0}, &math.Vec3{previous[12], previous[13], previous[14]}, 1e-6)
+	w := n.worldTransform.LocalToWorld(&math.Vec3{0, 0, 0})
+	assertVec3(t, &math.Vec3{2, 0, 0}, &w, 1e-6)
+}
+
+func TestFind(t *testing.T) {
+	root := NewNode()
+	arm := NewNode()
+	arm.SetName("arm")
+	hand := NewNode()
+	hand.SetName("hand")
+	other := NewNode()
+	other.SetName("hand")
+	root.AddChildren(arm, other)
+	arm.AddChild(hand)
+
+	assert.Equal(t, "arm", arm.Name())
+
+	// Depth-first.
+	assert.Equal(t, hand, root.Find("hand"))
+	assert.Equal(t, other, root.FindByPath("hand"))
+	assert.Equal(t, hand, root.FindByPath("arm/hand"))
+	assert.Equal(t, hand, root.FindByPath("/arm/hand/"))
+	assert.Equal(t, root, root.FindByPath(""))
+
+	assert.Nil(t, root.Find("finger"))
+	assert.Nil(t, root.FindByPath("arm/finger"))
+	assert.Nil(t, root.FindByPath("hand/arm"))
+}
@@ -0,0 +1,148 @@
+package dax
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// EngineOptions are engine options users and CI can override at startup,
+// without code changes, with command line flags or environment variables:
+//
+//	-dax.width, DAX_WIDTH             width of the windows
+//	-dax.height, DAX_HEIGHT           height of the windows
+//	-dax.fullscreen, DAX_FULLSCREEN   show the first window fullscreen
+//	-dax.vsync, DAX_VSYNC             sync to the vertical refresh
+//	-dax.gl-debug, DAX_GL_DEBUG       print the GL debug messages
+//	-dax.capture, DAX_CAPTURE         directory frames are saved into
+//	-dax.frames, DAX_FRAMES           number of frames to run
+//
+// Flags take precedence over environment variables. Application.ParseFlags
+// returns the arguments without the engine flags, so programs can parse their
+// own arguments as if they weren't there. See Application.Options.
+type EngineOptions struct {
+	// Width and Height override the size given to Application.NewWindow
+	// when not 0.
+	Width, Height int
+	// Fullscreen shows the first window fullscreen on the primary monitor,
+	// in its current video mode unless Width and Height are set.
+	Fullscreen bool
+	// VSync syncs the first window to the vertical refresh. It's true by
+	// default.
+	VSync bool
+	// GLDebug creates debug GL contexts and prints the debug messages of
+	// the GL driver.
+	GLDebug bool
+	// Capture is the directory each frame of each window is saved into, as
+	// "<window name> - <frame>.png", when not empty.
+	Capture string
+	// Frames is the number of frames drawn before the windows are closed
+	// and Application.Run returns, when not 0, eg. to capture a few frames
+	// in CI.
+	Frames int
+}
+
+// DefaultEngineOptions returns the options used when nothing overrides them.
+func DefaultEngineOptions() EngineOptions {
+	return EngineOptions{
+		VSync: true,
+	}
+}
+
+// engineOptionPrefix is the prefix of the engine flags.
+const engineOptionPrefix = "dax."
+
+// flagSet returns the engine flags, setting the fields of o.
+func (o *EngineOptions) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("dax", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	p := engineOptionPrefix
+	fs.IntVar(&o.Width, p+"width", o.Width, "width of the windows")
+	fs.IntVar(&o.Height, p+"height", o.Height, "height of the windows")
+	fs.BoolVar(&o.Fullscreen, p+"fullscreen", o.Fullscreen, "show the first window fullscreen")
+	fs.BoolVar(&o.VSync, p+"vsync", o.VSync, "sync to the vertical refresh")
+	fs.BoolVar(&o.GLDebug, p+"gl-debug", o.GLDebug, "print the GL debug messages")
+	fs.StringVar(&o.Capture, p+"capture", o.Capture, "directory frames are saved into")
+	fs.IntVar(&o.Frames, p+"frames", o.Frames, "number of frames to run")
+
+	return fs
+}
+
+// envName returns the environment variable of the flag name, eg. DAX_GL_DEBUG
+// for dax.gl-debug.
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+}
+
+// engineFlagName returns the name of the engine flag arg is, with its value if
+// given with "=", or "" when arg isn't an engine flag.
+func engineFlagName(arg string) string {
+	name := strings.TrimPrefix(arg, "-")
+	name = strings.TrimPrefix(name, "-")
+	if len(name) == len(arg) || !strings.HasPrefix(name, engineOptionPrefix) {
+		return ""
+	}
+	return name
+}
+
+// parseEngineOptions returns the options set by the environment, read with
+// getenv, and by the engine flags of args. The other arguments are returned,
+// in order. Arguments after a "--" terminator are left untouched.
+func parseEngineOptions(args []string, getenv func(key string) string) (EngineOptions, []string, error) {
+	o := DefaultEngineOptions()
+	fs := o.flagSet()
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		key := envName(f.Name)
+		value := getenv(key)
+		if value == "" || err != nil {
+			return
+		}
+		if e := fs.Set(f.Name, value); e != nil {
+			err = fmt.Errorf("dax: invalid value %q for %s: %v", value, key, e)
+		}
+	})
+	if err != nil {
+		return o, args, err
+	}
+
+	var engine, others []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			others = append(others, args[i:]...)
+			break
+		}
+
+		name := engineFlagName(arg)
+		if name == "" {
+			others = append(others, arg)
+			continue
+		}
+		engine = append(engine, arg)
+
+		// Non-boolean flags can have their value in the next argument.
+		if strings.Contains(name, "=") {
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if i+1 < len(args) {
+			i++
+			engine = append(engine, args[i])
+		}
+	}
+
+	if err := fs.Parse(engine); err != nil {
+		return o, args, fmt.Errorf("dax: %v", err)
+	}
+	return o, others, nil
+}
@@ -0,0 +1,74 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeEnv(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestParseEngineOptionsDefaults(t *testing.T) {
+	o, args, err := parseEngineOptions([]string{"run", "-v", "grid"}, fakeEnv(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultEngineOptions(), o)
+	assert.True(t, o.VSync)
+	assert.Equal(t, []string{"run", "-v", "grid"}, args)
+}
+
+func TestParseEngineOptionsFlags(t *testing.T) {
+	o, args, err := parseEngineOptions([]string{
+		"run",
+		"-dax.width=1280", "--dax.height", "720",
+		"-dax.fullscreen", "-dax.vsync=false",
+		"grid",
+		"-dax.capture", "frames", "-dax.frames=3",
+		"--", "-dax.gl-debug",
+	}, fakeEnv(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, EngineOptions{
+		Width:      1280,
+		Height:     720,
+		Fullscreen: true,
+		Capture:    "frames",
+		Frames:     3,
+	}, o)
+	// Engine flags are removed, except after "--".
+	assert.Equal(t, []string{"run", "grid", "--", "-dax.gl-debug"}, args)
+}
+
+func TestParseEngineOptionsEnv(t *testing.T) {
+	o, _, err := parseEngineOptions([]string{"-dax.width=640"}, fakeEnv(map[string]string{
+		"DAX_WIDTH":    "1024",
+		"DAX_HEIGHT":   "768",
+		"DAX_GL_DEBUG": "1",
+		"DAX_VSYNC":    "false",
+	}))
+	assert.Nil(t, err)
+	// Flags take precedence over the environment.
+	assert.Equal(t, 640, o.Width)
+	assert.Equal(t, 768, o.Height)
+	assert.True(t, o.GLDebug)
+	assert.False(t, o.VSync)
+}
+
+func TestParseEngineOptionsErrors(t *testing.T) {
+	_, _, err := parseEngineOptions(nil, fakeEnv(map[string]string{"DAX_FRAMES": "many"}))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "DAX_FRAMES")
+
+	_, _, err = parseEngineOptions([]string{"-dax.width=wide"}, fakeEnv(nil))
+	assert.NotNil(t, err)
+
+	_, _, err = parseEngineOptions([]string{"-dax.unknown"}, fakeEnv(nil))
+	assert.NotNil(t, err)
+
+	// Flags of other prefixes are left to the program.
+	_, args, err := parseEngineOptions([]string{"-daxtest.update"}, fakeEnv(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-daxtest.update"}, args)
+}
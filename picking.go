@@ -0,0 +1,210 @@
+package dax
+
+import (
+	"sort"
+
+	"github.com/dlespiau/dax/math"
+)
+
+// PickResult is a node hit by a picking ray.
+type PickResult struct {
+	Node *Node
+	// Distance is the distance from the ray origin to the hit point.
+	Distance float32
+	// Point is the hit point, in world space.
+	Point math.Vec3
+	// Instance is, when Node is part of a subtree drawn by an instance,
+	// the instance the node was hit through, nil otherwise. Node is then
+	// the node of the prototype subtree.
+	Instance *Node
+}
+
+// Pick returns the nodes under the point (x, y) of fb, as seen by the camera
+// of fb, sorted from the closest to the farthest. (x, y) are window
+// coordinates, as given to the mouse events. Nothing is picked outside of the
+// viewport of fb or, for letterboxing cameras, outside of the letterbox. Nodes
+// are tested against the triangles of their mesh, or its bounding box for
+// meshes without triangles, eg. lines.
+func (sg *SceneGraph) Pick(x, y float32, fb Framebuffer) []PickResult {
+	ray, inside := screenRay(x, y, fb)
+	if !inside {
+		return nil
+	}
+
+	sg.updateWorldTransform()
+	return sg.PickRay(&ray)
+}
+
+// ScreenRay returns the ray, in world space, going from the camera of fb
+// through the point (x, y) of fb. (x, y) are window coordinates, as given to
+// the mouse events, mapped through the viewport of fb. It's useful to drag
+// objects with the mouse, intersecting the ray with a plane.
+func ScreenRay(x, y float32, fb Framebuffer) math.Ray {
+	ray, _ := screenRay(x, y, fb)
+	return ray
+}
+
+// screenRay is ScreenRay also returning whether (x, y) is inside the viewport
+// of fb and the letterbox of its camera, if any.
+func screenRay(x, y float32, fb Framebuffer) (ray math.Ray, inside bool) {
+	c := fb.GetCamera()
+	width, height := fb.Size()
+	v := fb.Viewport()
+	if v.Width <= 0 || v.Height <= 0 {
+		v = Viewport{0, 0, width, height}
+	}
+
+	// Window coordinates have their origin at the top left corner, viewports
+	// at the bottom left one.
+	top := float32(height - v.Y - v.Height)
+	view := cameraView(c)
+	ray = math.ScreenRay(x-float32(v.X), y-top, v.Width, v.Height, c.GetProjection(), &view)
+
+	inside = windowContains(&v, x, y, height)
+	if l, ok := c.(letterboxer); ok {
+		box := l.letterbox()
+		inside = inside && windowContains(&box, x, y, height)
+	}
+	return ray, inside
+}
+
+// windowContains returns true if the window coordinates (x, y) are inside v,
+// in a framebuffer height pixels high.
+func windowContains(v *Viewport, x, y float32, height int) bool {
+	top := float32(height - v.Y - v.Height)
+	return x >= float32(v.X) && x < float32(v.X+v.Width) &&
+		y >= top && y < top+float32(v.Height)
+}
+
+// PickRay returns the nodes hit by ray, given in world space, sorted from the
+// closest to the farthest. World transforms are expected to be up to date.
+func (sg *SceneGraph) PickRay(ray *math.Ray) []PickResult {
+	var results []PickResult
+
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok {
+			continue
+		}
+		mr := getMeshRenderer(node)
+		if mr == nil {
+			continue
+		}
+		bounds := mr.Bounds()
+		if bounds.IsEmpty() {
+			continue
+		}
+
+		// Intersect in the node local space.
+		world := node.worldTransform.AsMat4()
+		worldInverse := world.Inverse()
+		local := ray.Transform(&worldInverse)
+		_, p, hit := local.IntersectAABB(&bounds.Min, &bounds.Max)
+		if !hit {
+			continue
+		}
+		// Refine the hit with the triangles of the mesh.
+		if mesh := mr.mesher.GetMesh(); mesh != nil {
+			point, hit, tested := intersectMesh(&local, mesh)
+			if tested && !hit {
+				continue
+			}
+			if tested {
+				p = point
+			}
+		}
+
+		point := world.Mul4x1(&math.Vec4{p[0], p[1], p[2], 1})
+		result := PickResult{
+			Node:  node.source(),
+			Point: point.Vec3(),
+		}
+		if node.isInstanced() {
+			result.Instance = node.outerInstance()
+		}
+		d := result.Point.Sub(&ray.Origin)
+		result.Distance = d.Len()
+		results = append(results, result)
+	}
+
+	sort.Sort(byDistance(results))
+
+	return results
+}
+
+// triangle returns the vertices of the ith triangle drawn in the given mode,
+// see numTriangles. index maps the nth drawn vertex to its index.
+func triangle(mode VertexMode, index func(n int) uint, i int) (a, b, c uint) {
+	switch mode {
+	case VertexModeTriangleStrip:
+		return index(i), index(i + 1), index(i + 2)
+	case VertexModeTriangleFan:
+		return index(0), index(i + 1), index(i + 2)
+	default:
+		return index(3 * i), index(3*i + 1), index(3*i + 2)
+	}
+}
+
+// intersectMesh intersects ray with the triangles of mesh, both in the same
+// space, and returns the closest hit point. tested is false for meshes
+// without triangles.
+func intersectMesh(ray *math.Ray, mesh *Mesh) (point math.Vec3, hit, tested bool) {
+	positions := mesh.GetAttribute("position")
+	if positions == nil || positions.NumComponents < 3 {
+		return point, false, false
+	}
+
+	// Meshes without indices draw their vertices in order.
+	index, count := mesh.indices.Get, mesh.indices.Len()
+	if count == 0 {
+		index = func(n int) uint { return uint(n) }
+		count = positions.Len()
+	}
+	n := numTriangles(mesh.GetVertexMode(), count)
+	if n == 0 {
+		return point, false, false
+	}
+
+	var closest float32
+	vertex := func(i uint) math.Vec3 {
+		x, y, z := positions.GetXYZ(int(i))
+		return math.Vec3{x, y, z}
+	}
+	for i := 0; i < n; i++ {
+		ia, ib, ic := triangle(mesh.GetVertexMode(), index, i)
+		if max := uint(positions.Len()); ia >= max || ib >= max || ic >= max {
+			continue
+		}
+		a, b, c := vertex(ia), vertex(ib), vertex(ic)
+		if t, p, ok := ray.IntersectTriangle(&a, &b, &c); ok && (!hit || t < closest) {
+			closest, point, hit = t, p, true
+		}
+	}
+	return point, hit, true
+}
+
+// FocusDistance returns the distance, along the view direction of the camera
+// of fb, of the closest node under the point (x, y) of fb, eg. to focus a
+// depth of field effect on what the user clicked. ok is false when there's no
+// node under the point.
+func (sg *SceneGraph) FocusDistance(x, y float32, fb Framebuffer) (distance float32, ok bool) {
+	results := sg.Pick(x, y, fb)
+	if len(results) == 0 {
+		return 0, false
+	}
+	return viewDepth(fb.GetCamera(), &results[0].Point), true
+}
+
+// viewDepth returns the distance between the camera c and the plane facing it
+// going through p, given in world space.
+func viewDepth(c Camera, p *math.Vec3) float32 {
+	view := cameraView(c)
+	v := view.Mul4x1(&math.Vec4{p[0], p[1], p[2], 1})
+	return -v[2]
+}
+
+type byDistance []PickResult
+
+func (s byDistance) Len() int           { return len(s) }
+func (s byDistance) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byDistance) Less(i, j int) bool { return s[i].Distance < s[j].Distance }
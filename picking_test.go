@@ -0,0 +1,149 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickRay(t *testing.T) {
+	sg := buildDepthTestScene()
+
+	// Scaled cube, further away but bigger.
+	big := NewNode().AddComponent(NewMeshRenderer(&cubeMesh{}, &dummyOpaqueMaterial{}))
+	big.SetPosition(0, 0, -30)
+	big.SetScale(10, 10, 10)
+	sg.AddChild(big)
+	sg.updateWorldTransform()
+
+	ray := math.Ray{Origin: math.Vec3{0, 0, 0}, Direction: math.Vec3{0, 0, -1}}
+	results := sg.PickRay(&ray)
+	assert.Equal(t, 3, len(results))
+	assertFloat(t, 9.5, results[0].Distance, 1e-3)
+	assertFloat(t, 25, results[1].Distance, 1e-3)
+	assert.Equal(t, big, results[1].Node)
+	assertVec3(t, &math.Vec3{0, 0, -25}, &results[1].Point, 1e-3)
+	assertFloat(t, 49.5, results[2].Distance, 1e-3)
+
+	// Only the big cube is wide enough.
+	ray.Origin = math.Vec3{3, 0, 0}
+	results = sg.PickRay(&ray)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, big, results[0].Node)
+}
+
+// triangleMesh is the triangle (-1, -1), (1, -1), (-1, 1) on the z = 0 plane
+// or, indexed, the triangle (1, -1), (1, 1), (-1, 1) of a square.
+type triangleMesh struct {
+	indexed bool
+}
+
+func (tm *triangleMesh) GetMesh() *Mesh {
+	m := NewMesh()
+	positions := []float32{-1, -1, 0, 1, -1, 0, -1, 1, 0, 1, 1, 0}
+	if !tm.indexed {
+		m.AddAttribute("position", positions[:9], 3)
+		return m
+	}
+	m.AddAttribute("position", positions, 3)
+	m.AddIndices([]uint{1, 3, 2})
+	return m
+}
+
+func TestPickRayTriangles(t *testing.T) {
+	sg := NewSceneGraph()
+	node := NewNode().AddComponent(NewMeshRenderer(&triangleMesh{}, &dummyOpaqueMaterial{}))
+	node.SetPosition(0, 0, -10)
+	sg.AddChild(node)
+	sg.updateWorldTransform()
+
+	ray := math.Ray{Origin: math.Vec3{-.5, -.5, 0}, Direction: math.Vec3{0, 0, -1}}
+	results := sg.PickRay(&ray)
+	assert.Equal(t, 1, len(results))
+	assertVec3(t, &math.Vec3{-.5, -.5, -10}, &results[0].Point, 1e-3)
+	assertFloat(t, 10, results[0].Distance, 1e-3)
+
+	// Inside the bounding box, but outside of the triangle.
+	ray.Origin = math.Vec3{.5, .5, 0}
+	assert.Equal(t, 0, len(sg.PickRay(&ray)))
+
+	// Indexed meshes only draw the triangles of their indices.
+	sg.RemoveChild(node)
+	indexed := NewNode().AddComponent(NewMeshRenderer(&triangleMesh{indexed: true}, &dummyOpaqueMaterial{}))
+	indexed.SetPosition(0, 0, -10)
+	sg.AddChild(indexed)
+	sg.updateWorldTransform()
+	assert.Equal(t, 1, len(sg.PickRay(&ray)))
+	ray.Origin = math.Vec3{-.5, -.5, 0}
+	assert.Equal(t, 0, len(sg.PickRay(&ray)))
+}
+
+// viewportFramebuffer is a framebuffer only implementing what picking needs.
+type viewportFramebuffer struct {
+	Framebuffer
+	width, height int
+	viewport      Viewport
+	camera        Camera
+}
+
+func (fb *viewportFramebuffer) Size() (int, int)           { return fb.width, fb.height }
+func (fb *viewportFramebuffer) Viewport() Viewport         { return fb.viewport }
+func (fb *viewportFramebuffer) SetViewport(x, y, w, h int) { fb.viewport = Viewport{x, y, w, h} }
+func (fb *viewportFramebuffer) GetCamera() Camera          { return fb.camera }
+
+func TestScreenRayViewport(t *testing.T) {
+	camera := NewPerspectiveCamera(90, 1, 1, 100)
+	fb := &viewportFramebuffer{width: 200, height: 100, camera: camera}
+
+	// Only the right half of the framebuffer.
+	fb.SetViewport(100, 0, 100, 100)
+	ray, inside := screenRay(150, 50, fb)
+	assert.True(t, inside)
+	assertVec3(t, &math.Vec3{0, 0, -1}, &ray.Direction, 1e-3)
+	ray, inside = screenRay(200, 0, fb)
+	assert.False(t, inside)
+	_, inside = screenRay(50, 50, fb)
+	assert.False(t, inside)
+
+	// The bottom left quarter: window coordinates start at the top.
+	fb.SetViewport(0, 0, 100, 50)
+	ray, inside = screenRay(50, 75, fb)
+	assert.True(t, inside)
+	assertVec3(t, &math.Vec3{0, 0, -1}, &ray.Direction, 1e-3)
+	_, inside = screenRay(50, 25, fb)
+	assert.False(t, inside)
+
+	// Letterboxing cameras only cover their letterbox, here the middle half.
+	fb.SetViewport(0, 0, 200, 100)
+	fb.camera = NewLetterboxCamera(camera, 1)
+	fb.camera.UpdateFBSize(200, 100)
+	ray, inside = screenRay(100, 50, fb)
+	assert.True(t, inside)
+	assertVec3(t, &math.Vec3{0, 0, -1}, &ray.Direction, 1e-3)
+	_, inside = screenRay(25, 50, fb)
+	assert.False(t, inside)
+	fb.camera = camera
+
+	// Nothing is picked outside of the viewport.
+	fb.SetViewport(0, 0, 100, 50)
+	sg := NewSceneGraph()
+	node := NewNode().AddComponent(NewMeshRenderer(&cubeMesh{}, &dummyOpaqueMaterial{}))
+	node.SetPosition(0, 0, -10)
+	sg.AddChild(node)
+	assert.Equal(t, 1, len(sg.Pick(50, 75, fb)))
+	assert.Equal(t, 0, len(sg.Pick(50, 25, fb)))
+}
+
+func TestViewDepth(t *testing.T) {
+	camera := NewPerspectiveCamera(90, 1, 1, 100)
+	camera.SetPosition(0, 0, 10)
+
+	// The depth is measured along the view direction, not along the ray.
+	assertFloat(t, 10, viewDepth(camera, &math.Vec3{0, 0, 0}), 1e-4)
+	assertFloat(t, 10, viewDepth(camera, &math.Vec3{5, 3, 0}), 1e-4)
+	assertFloat(t, 15, viewDepth(camera, &math.Vec3{0, 0, -5}), 1e-4)
+
+	camera.SetFocusDistance(15)
+	assertFloat(t, 15, camera.FocusDistance(), 1e-4)
+}
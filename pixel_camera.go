@@ -0,0 +1,140 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// PixelScale is how a pixel camera scales its virtual screen to the
+// framebuffer.
+type PixelScale int
+
+const (
+	// PixelScaleInteger uses the largest integer scale showing the whole
+	// virtual screen, at least 1. The rest of the framebuffer is
+	// letterboxed.
+	PixelScaleInteger PixelScale = iota
+	// PixelScaleNearestInteger uses the integer scale nearest to the one
+	// fitting the virtual screen in the framebuffer, at least 1. Depending
+	// on the framebuffer size, the virtual screen is letterboxed or its
+	// edges are cropped.
+	PixelScaleNearestInteger
+	// PixelScaleFit uses the largest scale showing the whole virtual
+	// screen, letterboxing the rest of the framebuffer. Fractional scales
+	// aren't pixel perfect.
+	PixelScaleFit
+)
+
+// pixelCamera maps a virtual screen of a fixed size, in pixels, to the
+// framebuffer.
+type pixelCamera struct {
+	BaseCamera
+	width, height int
+	policy        PixelScale
+	near, far     float32
+
+	// scale is the size of a virtual pixel, in framebuffer pixels.
+	scale float32
+	// screen is where the virtual screen is in the framebuffer.
+	screen Viewport
+	// top is the distance between the top of the framebuffer and the
+	// virtual screen.
+	top int
+}
+
+// NewPixelCamera creates a camera for pixel perfect 2D rendering. It shows a
+// virtual screen of width x height pixels, with its origin at the top left
+// corner, scaled to the framebuffer following policy. With integer scales, a
+// virtual pixel covers exactly scale x scale framebuffer pixels: textures
+// drawn at integer positions, with their size in virtual pixels and
+// TextureFilterNearest, map each texel to scale x scale pixels.
+//
+// Scenes using a pixel camera only draw on the virtual screen: the rest of
+// the framebuffer is left to the background color.
+func NewPixelCamera(width, height int, policy PixelScale) *pixelCamera {
+	c := new(pixelCamera)
+	c.Init()
+
+	c.width = width
+	c.height = height
+	c.policy = policy
+	c.near = -1
+	c.far = 1
+	c.UpdateFBSize(width, height)
+
+	return c
+}
+
+// pixelScale returns the scale of a width x height virtual screen shown in a
+// fbWidth x fbHeight framebuffer.
+func pixelScale(policy PixelScale, width, height, fbWidth, fbHeight int) float32 {
+	fit := math.Min(float32(fbWidth)/float32(width), float32(fbHeight)/float32(height))
+
+	switch policy {
+	case PixelScaleNearestInteger:
+		return math.Max(math.Floor(fit+.5), 1)
+	case PixelScaleFit:
+		return fit
+	default:
+		return math.Max(math.Floor(fit), 1)
+	}
+}
+
+// UpdateFBSize implements Camera.
+func (c *pixelCamera) UpdateFBSize(width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	c.scale = pixelScale(c.policy, c.width, c.height, width, height)
+
+	// Center the virtual screen on a whole pixel so virtual pixels stay
+	// aligned with the framebuffer ones.
+	w := float32(c.width) * c.scale
+	h := float32(c.height) * c.scale
+	x := math.Floor((float32(width) - w) / 2)
+	top := math.Floor((float32(height) - h) / 2)
+	c.top = int(top)
+	c.screen = Viewport{
+		X:      int(x),
+		Y:      int(float32(height) - top - math.Ceil(h)),
+		Width:  int(math.Ceil(w)),
+		Height: int(math.Ceil(h)),
+	}
+
+	// The projection covers the whole framebuffer, in virtual pixels.
+	c.projection = math.Ortho(
+		-x/c.scale, (float32(width)-x)/c.scale,
+		(float32(height)-top)/c.scale, -top/c.scale,
+		c.near, c.far)
+}
+
+// Scale returns the size of a virtual pixel, in framebuffer pixels.
+func (c *pixelCamera) Scale() float32 {
+	return c.scale
+}
+
+// Screen returns the rectangle of the framebuffer the virtual screen is drawn
+// into. It may be larger than the framebuffer when the virtual screen is
+// cropped.
+func (c *pixelCamera) Screen() Viewport {
+	return c.screen
+}
+
+// ToVirtual converts framebuffer coordinates, with their origin at the top
+// left corner like mouse coordinates, to virtual screen coordinates.
+func (c *pixelCamera) ToVirtual(x, y float32) math.Vec2 {
+	return math.Vec2{
+		(x - float32(c.screen.X)) / c.scale,
+		(y - float32(c.top)) / c.scale,
+	}
+}
+
+// letterbox implements letterboxer.
+func (c *pixelCamera) letterbox() Viewport {
+	return c.screen
+}
+
+// letterboxer is a camera only drawing on part of the framebuffer.
+type letterboxer interface {
+	letterbox() Viewport
+}
@@ -0,0 +1,54 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPixelCamera(t *testing.T) {
+	tests := []struct {
+		policy        PixelScale
+		width, height int
+		scale         float32
+		screen        Viewport
+	}{
+		// 3.125x fits: integer scales round down and letterbox.
+		{PixelScaleInteger, 1000, 700, 3, Viewport{20, 80, 960, 540}},
+		{PixelScaleNearestInteger, 1000, 700, 3, Viewport{20, 80, 960, 540}},
+		{PixelScaleFit, 1000, 700, 3.125, Viewport{0, 69, 1000, 563}},
+		// 2.8125x fits: the nearest integer scale crops the sides.
+		{PixelScaleInteger, 900, 540, 2, Viewport{130, 90, 640, 360}},
+		{PixelScaleNearestInteger, 900, 540, 3, Viewport{-30, 0, 960, 540}},
+		// Framebuffers smaller than the virtual screen still use 1x.
+		{PixelScaleInteger, 160, 90, 1, Viewport{-80, -45, 320, 180}},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: policy %d, %dx%d", test.policy, test.width, test.height)
+
+		c := NewPixelCamera(320, 180, test.policy)
+		c.UpdateFBSize(test.width, test.height)
+		assert.Equal(t, test.scale, c.Scale())
+		assert.Equal(t, test.screen, c.Screen())
+
+		// The corners of the virtual screen are projected on the corners
+		// of the letterbox.
+		projection := c.GetProjection()
+		identity := math.Ident4()
+		fb := Viewport{0, 0, test.width, test.height}
+		topLeft := fb.Project(&math.Vec3{0, 0, 0}, &identity, projection)
+		bottomRight := fb.Project(&math.Vec3{320, 180, 0}, &identity, projection)
+		screen := test.screen
+		assert.InDelta(t, float32(screen.X), topLeft[0], 1e-2)
+		assert.InDelta(t, float32(screen.Y+screen.Height), topLeft[1], 1e-2)
+		assert.InDelta(t, float32(screen.X)+320*test.scale, bottomRight[0], 1e-2)
+		assert.InDelta(t, float32(screen.Y+screen.Height)-180*test.scale, bottomRight[1], 1e-2)
+
+		// And back.
+		top := float32(test.height - screen.Y - screen.Height)
+		assert.Equal(t, math.Vec2{160, 90},
+			c.ToVirtual(float32(screen.X)+160*test.scale, top+90*test.scale))
+	}
+}
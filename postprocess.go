@@ -0,0 +1,563 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// postVertexShader draws a triangle covering the whole viewport, as
+// displayVertexShader does, giving effects the texture coordinates of their
+// fragments.
+const postVertexShader = `
+#version 330 core
+
+out vec2 uv;
+
+void main() {
+	vec2 p = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2);
+	uv = p;
+	gl_Position = vec4(p * 2.0 - 1.0, 0.0, 1.0);
+}`
+
+// PostEffect is a full-screen pass of a PostProcessor. Its fragment shader
+// reads the output of the previous pass, the frame drawn by the scene for the
+// first effect, and writes the input of the next one:
+//
+//	uniform sampler2D image; // output of the previous pass
+//	uniform sampler2D depth; // depth buffer of the scene
+//	uniform vec2 texelSize;  // size of a pixel of image, in uv units
+//	in vec2 uv;              // texture coordinates of the fragment
+//	out vec4 outputColor;
+//
+// Other uniforms are set with SetUniform. Colors are linear and aren't
+// clamped until the last pass, so effects can work on HDR frames.
+type PostEffect struct {
+	fs       *FragmentShader
+	uniforms uniformValues
+	disabled bool
+
+	// prepare, when set, updates the uniforms of the effect before each
+	// frame.
+	prepare func(f *postFrame)
+}
+
+// postFrame is what effects know of the frame drawn by the scene.
+type postFrame struct {
+	// camera is the camera of the scene, nil if it has none, and
+	// projection the projection it was drawn with.
+	camera     Camera
+	projection math.Mat4
+	// depthRange maps the values of the depth buffer to normalized device
+	// coordinates: z = depth * depthRange[0] + depthRange[1].
+	depthRange math.Vec2
+	height     int
+}
+
+// NewPostEffect creates an effect drawn with the fragment shader fs.
+func NewPostEffect(fs *FragmentShader) *PostEffect {
+	return &PostEffect{fs: fs}
+}
+
+// SetUniform sets the value of the uniform name of the effect. It accepts the
+// same types as Material.SetUniform.
+func (e *PostEffect) SetUniform(name string, value interface{}) {
+	e.uniforms.set(name, value)
+}
+
+// GetUniform returns the value of the uniform name, or nil if it hasn't been
+// set.
+func (e *PostEffect) GetUniform(name string) interface{} {
+	return e.uniforms.get(name)
+}
+
+// SetEnabled enables or disables the effect. Disabled effects are skipped
+// without having to be removed from their PostProcessor.
+func (e *PostEffect) SetEnabled(enabled bool) {
+	e.disabled = !enabled
+}
+
+// Enabled returns true when the effect is drawn.
+func (e *PostEffect) Enabled() bool {
+	return !e.disabled
+}
+
+const toneMappingFragmentShader = `
+#version 330
+
+uniform sampler2D image;
+uniform float exposure;
+
+in vec2 uv;
+out vec4 outputColor;
+
+vec3 aces(vec3 x) {
+	return clamp((x * (2.51 * x + 0.03)) / (x * (2.43 * x + 0.59) + 0.14), 0.0, 1.0);
+}
+
+void main() {
+	vec4 c = texture(image, uv);
+	outputColor = vec4(aces(c.rgb * exposure), c.a);
+}`
+
+// NewToneMappingEffect creates an effect mapping HDR colors to [0, 1] with an
+// approximation of the ACES filmic curve, after scaling them by exposure. It
+// usually comes after the effects working on HDR colors, eg. bloom. The
+// exposure uniform can be changed with SetUniform.
+func NewToneMappingEffect(exposure float32) *PostEffect {
+	e := NewPostEffect(NewFragmentShader(toneMappingFragmentShader))
+	e.SetUniform("exposure", exposure)
+	return e
+}
+
+const vignetteFragmentShader = `
+#version 330
+
+uniform sampler2D image;
+uniform float intensity;
+uniform float radius;
+uniform float softness;
+
+in vec2 uv;
+out vec4 outputColor;
+
+void main() {
+	vec4 c = texture(image, uv);
+	// 1 in the corners.
+	float d = distance(uv, vec2(0.5)) * sqrt(2.0);
+	float v = smoothstep(radius, radius + softness, d);
+	outputColor = vec4(c.rgb * (1.0 - intensity * v), c.a);
+}`
+
+// NewVignetteEffect creates an effect darkening the edges of the frame.
+// Colors are scaled by 1 - intensity in the corners. The radius and softness
+// uniforms, .5 and .5 by default, are where the darkening starts and the
+// distance over which it reaches its full intensity, as ratios of the
+// distance between the center and the corners.
+func NewVignetteEffect(intensity float32) *PostEffect {
+	e := NewPostEffect(NewFragmentShader(vignetteFragmentShader))
+	e.SetUniform("intensity", intensity)
+	e.SetUniform("radius", float32(.5))
+	e.SetUniform("softness", float32(.5))
+	return e
+}
+
+const fxaaFragmentShader = `
+#version 330
+
+uniform sampler2D image;
+uniform vec2 texelSize;
+
+in vec2 uv;
+out vec4 outputColor;
+
+const float reduceMin = 1.0 / 128.0;
+const float reduceMul = 1.0 / 8.0;
+const float spanMax = 8.0;
+
+void main() {
+	const vec3 toLuma = vec3(0.299, 0.587, 0.114);
+
+	vec4 m = texture(image, uv);
+	float lumaM = dot(m.rgb, toLuma);
+	float lumaNW = dot(texture(image, uv + vec2(-1.0, 1.0) * texelSize).rgb, toLuma);
+	float lumaNE = dot(texture(image, uv + vec2(1.0, 1.0) * texelSize).rgb, toLuma);
+	float lumaSW = dot(texture(image, uv + vec2(-1.0, -1.0) * texelSize).rgb, toLuma);
+	float lumaSE = dot(texture(image, uv + vec2(1.0, -1.0) * texelSize).rgb, toLuma);
+	float lumaMin = min(lumaM, min(min(lumaNW, lumaNE), min(lumaSW, lumaSE)));
+	float lumaMax = max(lumaM, max(max(lumaNW, lumaNE), max(lumaSW, lumaSE)));
+
+	// Blur along the edge, perpendicular to the luma gradient.
+	vec2 dir = vec2(-((lumaNW + lumaNE) - (lumaSW + lumaSE)),
+			(lumaNE + lumaSE) - (lumaNW + lumaSW));
+	float dirReduce = max((lumaNW + lumaNE + lumaSW + lumaSE) * 0.25 * reduceMul, reduceMin);
+	float rcpDirMin = 1.0 / (min(abs(dir.x), abs(dir.y)) + dirReduce);
+	dir = clamp(dir * rcpDirMin, -spanMax, spanMax) * texelSize;
+
+	vec3 a = 0.5 * (texture(image, uv + dir * (1.0 / 3.0 - 0.5)).rgb +
+			texture(image, uv + dir * (2.0 / 3.0 - 0.5)).rgb);
+	vec3 b = a * 0.5 + 0.25 * (texture(image, uv - dir * 0.5).rgb +
+			texture(image, uv + dir * 0.5).rgb);
+	float lumaB = dot(b, toLuma);
+	if (lumaB < lumaMin || lumaB > lumaMax) {
+		outputColor = vec4(a, m.a);
+	} else {
+		outputColor = vec4(b, m.a);
+	}
+}`
+
+// NewFXAAEffect creates an effect smoothing the edges of the frame with fast
+// approximate anti-aliasing, a much cheaper alternative to multisampling. It
+// expects colors in [0, 1] and usually comes after tone mapping.
+func NewFXAAEffect() *PostEffect {
+	return NewPostEffect(NewFragmentShader(fxaaFragmentShader))
+}
+
+const depthOfFieldFragmentShader = `
+#version 330
+
+uniform sampler2D image;
+uniform sampler2D depth;
+uniform vec2 texelSize;
+uniform mat4 inverseProjection;
+uniform vec2 depthRange;
+uniform float focusDistance;
+uniform float aperture;
+uniform float pixelScale;
+uniform float maxRadius;
+
+in vec2 uv;
+out vec4 outputColor;
+
+// radius returns the radius, in pixels, of the circle of confusion at p: the
+// blur of a lens of diameter aperture focused at focusDistance.
+float radius(vec2 p) {
+	float z = texture(depth, p).r * depthRange.x + depthRange.y;
+	vec4 view = inverseProjection * vec4(p * 2.0 - 1.0, z, 1.0);
+	float d = max(-view.z / view.w, 1e-4);
+	float coc = aperture * abs(d - focusDistance) / d;
+	return min(coc * pixelScale / focusDistance, maxRadius);
+}
+
+void main() {
+	vec4 c = texture(image, uv);
+	float r = radius(uv);
+	if (r < 0.5) {
+		outputColor = c;
+		return;
+	}
+
+	// Samples on a golden angle spiral covering the circle of confusion.
+	// Samples only contribute when their own circle of confusion reaches
+	// the fragment, so sharp objects don't bleed over blurred ones.
+	vec3 sum = c.rgb;
+	float total = 1.0;
+	for (int i = 1; i < 48; i++) {
+		float t = sqrt(float(i) / 48.0) * r;
+		float a = float(i) * 2.39996;
+		vec2 p = uv + vec2(cos(a), sin(a)) * t * texelSize;
+		float w = clamp(radius(p) - t + 1.0, 0.0, 1.0);
+		sum += texture(image, p).rgb * w;
+		total += w;
+	}
+	outputColor = vec4(sum / total, c.a);
+}`
+
+// focuser is implemented by cameras with focus settings.
+type focuser interface {
+	FocusDistance() float32
+	Aperture() float32
+}
+
+// NewDepthOfFieldEffect creates an effect blurring what's out of focus, as set
+// by the focus distance and aperture of perspective cameras, see
+// SetFocusDistance. Other cameras keep everything in focus. The maxRadius
+// uniform, 12 by default, limits the blur radius, in pixels. Materials writing
+// a logarithmic depth aren't supported.
+func NewDepthOfFieldEffect() *PostEffect {
+	e := NewPostEffect(NewFragmentShader(depthOfFieldFragmentShader))
+	e.SetUniform("maxRadius", float32(12))
+	e.prepare = e.setFocus
+	return e
+}
+
+// setFocus sets the uniforms of the depth of field effect for the frame f.
+func (e *PostEffect) setFocus(f *postFrame) {
+	c, ok := f.camera.(focuser)
+	if !ok || c.FocusDistance() <= 0 {
+		e.SetUniform("aperture", float32(0))
+		return
+	}
+
+	e.SetUniform("focusDistance", c.FocusDistance())
+	e.SetUniform("aperture", c.Aperture())
+	e.SetUniform("inverseProjection", f.projection.Inverse())
+	e.SetUniform("depthRange", f.depthRange)
+	// The size, in pixels, of a world unit at a distance of 1.
+	e.SetUniform("pixelScale", f.projection[5]*float32(f.height)/2)
+}
+
+const bloomFragmentShader = `
+#version 330
+
+uniform sampler2D image;
+uniform vec2 texelSize;
+uniform float threshold;
+uniform float intensity;
+uniform float radius;
+
+in vec2 uv;
+out vec4 outputColor;
+
+void main() {
+	vec4 c = texture(image, uv);
+
+	// Gaussian blur of the parts brighter than threshold, sampled on a
+	// 9x9 grid spread over radius pixels.
+	vec3 glow = vec3(0.0);
+	float total = 0.0;
+	for (int y = -4; y <= 4; y++) {
+		for (int x = -4; x <= 4; x++) {
+			vec2 o = vec2(x, y) / 4.0;
+			float w = exp(-2.0 * dot(o, o));
+			vec3 s = texture(image, uv + o * radius * texelSize).rgb;
+			glow += max(s - threshold, 0.0) * w;
+			total += w;
+		}
+	}
+	outputColor = vec4(c.rgb + glow / total * intensity, c.a);
+}`
+
+// NewBloomEffect creates an effect making the parts of the frame brighter
+// than threshold glow, adding them blurred, scaled by intensity, to the frame.
+// The radius uniform, 16 by default, is the size of the glow in pixels. It
+// works best on HDR frames, before tone mapping.
+func NewBloomEffect(threshold, intensity float32) *PostEffect {
+	e := NewPostEffect(NewFragmentShader(bloomFragmentShader))
+	e.SetUniform("threshold", threshold)
+	e.SetUniform("intensity", intensity)
+	e.SetUniform("radius", float32(16))
+	return e
+}
+
+// PostProcessor chains full-screen effects over the frame drawn by the scene
+// of a window, before it's presented:
+//
+//	post := dax.NewPostProcessor(
+//		dax.NewDepthOfFieldEffect(),
+//		dax.NewBloomEffect(1, .5),
+//		dax.NewToneMappingEffect(1),
+//		dax.NewFXAAEffect(),
+//		dax.NewVignetteEffect(.3),
+//	)
+//	window.SetPostProcessor(post)
+//
+// The scene is drawn into a floating point texture, which goes through the
+// enabled effects in order. The statistics and input overlays are drawn after
+// the effects. Reading the depth of the window returns the depth of the
+// scene.
+type PostProcessor struct {
+	effects []*PostEffect
+
+	// scene is drawn into, targets are the outputs of the passes in
+	// between, used in turn.
+	scene   *OffScreen
+	targets [2]*OffScreen
+	// previous is the framebuffer object the window was drawing into
+	// before the scene was redirected.
+	previous uint32
+
+	vs       *VertexShader
+	programs map[*PostEffect]*glProgram
+	// vao is an empty vertex array, core profiles needing one to draw.
+	vao uint32
+}
+
+// NewPostProcessor creates a PostProcessor applying effects in order.
+func NewPostProcessor(effects ...*PostEffect) *PostProcessor {
+	return &PostProcessor{
+		effects:  effects,
+		vs:       NewVertexShader(postVertexShader),
+		programs: make(map[*PostEffect]*glProgram),
+	}
+}
+
+// Add appends e to the effects.
+func (p *PostProcessor) Add(e *PostEffect) {
+	p.effects = append(p.effects, e)
+}
+
+// Remove removes e from the effects.
+func (p *PostProcessor) Remove(e *PostEffect) {
+	for i, effect := range p.effects {
+		if effect == e {
+			p.effects = append(p.effects[:i], p.effects[i+1:]...)
+			break
+		}
+	}
+}
+
+// Effects returns the effects, in the order they are applied.
+func (p *PostProcessor) Effects() []*PostEffect {
+	return p.effects
+}
+
+// active returns the enabled effects.
+func (p *PostProcessor) active() []*PostEffect {
+	var effects []*PostEffect
+	for _, e := range p.effects {
+		if e.Enabled() {
+			effects = append(effects, e)
+		}
+	}
+	return effects
+}
+
+// isActive returns true when p has effects to apply.
+func (p *PostProcessor) isActive() bool {
+	if p == nil {
+		return false
+	}
+	for _, e := range p.effects {
+		if e.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureTarget returns *t, (re)allocated with the size of fb and options.
+func ensureTarget(t **OffScreen, fb *onScreen, options OffScreenOptions) *OffScreen {
+	if *t != nil && (*t).options != options {
+		(*t).Destroy()
+		*t = nil
+	}
+	if *t == nil {
+		*t = NewOffScreen(fb, fb.width, fb.height, options)
+	} else {
+		(*t).SetSize(fb.width, fb.height)
+	}
+	return *t
+}
+
+// begin redirects the drawing into fb to the scene target.
+func (p *PostProcessor) begin(fb *onScreen, samples int) {
+	options := OffScreenOptions{
+		ColorFormat: TextureFormatRGBA16F,
+		DepthFormat: TextureFormatDepth24Stencil8,
+		Samples:     samples,
+		ReversedZ:   fb.reversedZ,
+	}
+	if fb.reversedZ {
+		options.DepthFormat = TextureFormatDepth32FStencil8
+	}
+	scene := ensureTarget(&p.scene, fb, options)
+
+	p.previous = fb.target
+	fb.target = scene.glID()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.target)
+}
+
+// end applies the effects to the scene drawn since begin, the last one
+// drawing into the framebuffer fb was drawing into before begin.
+func (p *PostProcessor) end(r *renderer, fb *onScreen) {
+	p.scene.resolve()
+
+	if gl.IsEnabled(gl.DEPTH_TEST) {
+		gl.Disable(gl.DEPTH_TEST)
+		defer gl.Enable(gl.DEPTH_TEST)
+	}
+	if gl.IsEnabled(gl.BLEND) {
+		gl.Disable(gl.BLEND)
+		defer gl.Enable(gl.BLEND)
+	}
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Viewport(0, 0, int32(fb.width), int32(fb.height))
+
+	if p.vao == 0 {
+		gl.GenVertexArrays(1, &p.vao)
+	}
+	gl.BindVertexArray(p.vao)
+
+	frame := postFrame{
+		camera:     fb.GetCamera(),
+		depthRange: math.Vec2{2, -1},
+		height:     fb.height,
+	}
+	if frame.camera != nil {
+		frame.projection = cameraProjection(frame.camera, fb.IsReversedZ())
+	}
+	if r.reversedZ && r.hasClipControl() {
+		frame.depthRange = math.Vec2{1, 0}
+	}
+
+	effects := p.active()
+	input := p.scene.color
+	for i, e := range effects {
+		if e.prepare != nil {
+			e.prepare(&frame)
+		}
+
+		var output *OffScreen
+		if i == len(effects)-1 {
+			fb.target = p.previous
+			gl.BindFramebuffer(gl.FRAMEBUFFER, fb.target)
+		} else {
+			output = ensureTarget(&p.targets[i%2], fb, OffScreenOptions{
+				ColorFormat: TextureFormatRGBA16F,
+			})
+			gl.BindFramebuffer(gl.FRAMEBUFFER, output.glID())
+		}
+
+		p.draw(e, input, p.scene.depth)
+
+		if output != nil {
+			input = output.color
+		}
+	}
+
+	applyViewport(&fb.viewport)
+}
+
+// program returns the program drawing e, compiling it the first time.
+func (p *PostProcessor) program(e *PostEffect) *glProgram {
+	if program, ok := p.programs[e]; ok {
+		return program
+	}
+
+	id, err := makeProgram(p.vs, e.fs)
+	if err != nil {
+		panic(err)
+	}
+	program := &glProgram{
+		id: id,
+		vs: p.vs,
+		fs: e.fs,
+	}
+	p.programs[e] = program
+	return program
+}
+
+// draw draws the effect e over the whole viewport with input as its image and
+// depth as the depth of the scene.
+func (p *PostProcessor) draw(e *PostEffect, input, depth *Texture) {
+	program := p.program(e)
+	gl.UseProgram(program.id)
+
+	input.Bind(0)
+	gl.Uniform1i(program.uniformLocation("image"), 0)
+	width, height := input.Size()
+	gl.Uniform2f(program.uniformLocation("texelSize"), 1/float32(width), 1/float32(height))
+	if location := program.uniformLocation("depth"); location != -1 && depth != nil {
+		depth.Bind(1)
+		gl.Uniform1i(location, 1)
+	}
+
+	// Textures of the effect come after the image and depth.
+	e.uniforms.forEach(2, func(v *uniformValue, unit int) {
+		if t, ok := v.value.(*Texture); ok {
+			t.Bind(unit)
+		}
+		if location := program.uniformLocation(v.name); location != -1 {
+			uploadUniform(location, v.value, unit)
+		}
+	})
+
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+}
+
+// release releases the GL objects of the post processor.
+func (p *PostProcessor) release(gc *glGarbageCollector) {
+	for _, t := range []**OffScreen{&p.scene, &p.targets[0], &p.targets[1]} {
+		if *t != nil {
+			(*t).Destroy()
+			*t = nil
+		}
+	}
+	for e, program := range p.programs {
+		gc.release(glObjectProgram, program.id)
+		delete(p.programs, e)
+	}
+	gc.release(glObjectVertexArray, p.vao)
+	p.vao = 0
+}
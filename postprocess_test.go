@@ -0,0 +1,74 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostProcessorEffects(t *testing.T) {
+	var p *PostProcessor
+	assert.False(t, p.isActive())
+
+	bloom := NewBloomEffect(1, .5)
+	tonemap := NewToneMappingEffect(1)
+	p = NewPostProcessor(bloom, tonemap)
+	assert.True(t, p.isActive())
+	assert.Equal(t, []*PostEffect{bloom, tonemap}, p.active())
+
+	// Disabled effects are skipped.
+	bloom.SetEnabled(false)
+	assert.False(t, bloom.Enabled())
+	assert.Equal(t, []*PostEffect{tonemap}, p.active())
+	tonemap.SetEnabled(false)
+	assert.False(t, p.isActive())
+	bloom.SetEnabled(true)
+
+	fxaa := NewFXAAEffect()
+	p.Add(fxaa)
+	p.Remove(tonemap)
+	assert.Equal(t, []*PostEffect{bloom, fxaa}, p.Effects())
+	assert.Equal(t, []*PostEffect{bloom, fxaa}, p.active())
+}
+
+func TestPostEffectUniforms(t *testing.T) {
+	e := NewVignetteEffect(.3)
+	assert.Equal(t, float32(.3), e.GetUniform("intensity"))
+	assert.Equal(t, float32(.5), e.GetUniform("radius"))
+
+	e.SetUniform("radius", .25)
+	assert.Equal(t, float32(.25), e.GetUniform("radius"))
+	assert.Nil(t, e.GetUniform("missing"))
+}
+
+func TestDepthOfFieldUniforms(t *testing.T) {
+	e := NewDepthOfFieldEffect()
+	camera := NewPerspectiveCamera(math.Pi/2, 1, 1, 100)
+	camera.SetAperture(.5)
+	frame := postFrame{
+		camera:     camera,
+		projection: *camera.GetProjection(),
+		depthRange: math.Vec2{2, -1},
+		height:     600,
+	}
+	e.prepare(&frame)
+	assert.Equal(t, float32(10), e.GetUniform("focusDistance"))
+	assert.Equal(t, float32(.5), e.GetUniform("aperture"))
+	// With a 90 degrees vertical field of view, a unit at a distance of 1 covers half
+	// of the frame height.
+	assertFloat(t, 300, e.GetUniform("pixelScale").(float32), 1e-3)
+
+	// The depth of the focus plane maps back to the focus distance.
+	focus := math.Vec4{0, 0, -10, 1}
+	clip := frame.projection.Mul4x1(&focus)
+	depth := (clip[2]/clip[3] - frame.depthRange[1]) / frame.depthRange[0]
+	inverse := e.GetUniform("inverseProjection").(math.Mat4)
+	view := inverse.Mul4x1(&math.Vec4{0, 0, depth*frame.depthRange[0] + frame.depthRange[1], 1})
+	assertFloat(t, 10, -view[2]/view[3], 1e-3)
+
+	// Cameras without focus settings keep everything sharp.
+	frame.camera = NewScreenSpaceCamera(800, 600, -1, 1)
+	e.prepare(&frame)
+	assert.Equal(t, float32(0), e.GetUniform("aperture"))
+}
@@ -0,0 +1,53 @@
+package dax
+
+// PropertyBlock is a set of uniform values applied over the ones of a material
+// when drawing a MeshRenderer. It lets many meshes share one material, and its
+// program, while having a few unique parameters:
+//
+//	material := material.NewPhong(&dax.Color{R: 1, G: 1, B: 1, A: 1})
+//	for i := range cubes {
+//		properties := dax.NewPropertyBlock()
+//		properties.Set("diffuse", &colors[i])
+//		mr := dax.NewMeshRenderer(cube, material)
+//		mr.SetPropertyBlock(properties)
+//		cubes[i].AddComponent(mr)
+//	}
+//
+// Mesh renderers sharing a material are drawn with a single draw call only if
+// they also share the same property block.
+type PropertyBlock struct {
+	values uniformValues
+}
+
+// NewPropertyBlock creates a new, empty, PropertyBlock.
+func NewPropertyBlock() *PropertyBlock {
+	return &PropertyBlock{}
+}
+
+// Set sets the value of the uniform name. It accepts the same types as
+// Material.SetUniform.
+func (b *PropertyBlock) Set(name string, value interface{}) {
+	b.values.set(name, value)
+}
+
+// Get returns the value of the uniform name, or nil if the block doesn't
+// override it.
+func (b *PropertyBlock) Get(name string) interface{} {
+	if b == nil {
+		return nil
+	}
+	return b.values.get(name)
+}
+
+// Len returns the number of uniforms overridden by the block.
+func (b *PropertyBlock) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.values.values)
+}
+
+// Clear removes all the overrides.
+func (b *PropertyBlock) Clear() {
+	b.values.values = b.values.values[:0]
+}
@@ -0,0 +1,53 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyBlock(t *testing.T) {
+	var nilBlock *PropertyBlock
+	assert.Nil(t, nilBlock.Get("color"))
+	assert.Equal(t, 0, nilBlock.Len())
+
+	b := NewPropertyBlock()
+	b.Set("color", &Color{0, 1, 0, 1})
+	b.Set("scale", 2.)
+	assert.Equal(t, math.Vec4{0, 1, 0, 1}, b.Get("color"))
+	assert.Equal(t, float32(2), b.Get("scale"))
+	assert.Equal(t, 2, b.Len())
+
+	b.Clear()
+	assert.Nil(t, b.Get("color"))
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestDrawCallbackProperties(t *testing.T) {
+	var scratch PropertyBlock
+	node := NewNode()
+	mr := NewMeshRenderer(&sharedMesher{NewMesh()}, &dummyOpaqueMaterial{})
+
+	// No callback: the property block is used as is.
+	assert.Nil(t, mr.drawProperties(&scratch, &DrawContext{Node: node}))
+	properties := NewPropertyBlock()
+	properties.Set("color", &Color{1, 0, 0, 1})
+	properties.Set("scale", 1.)
+	mr.SetPropertyBlock(properties)
+	assert.Equal(t, properties, mr.drawProperties(&scratch, &DrawContext{Node: node}))
+
+	// The callback overrides the property block, without modifying it.
+	mr.OnDraw(func(c *DrawContext) {
+		assert.Equal(t, node, c.Node)
+		c.SetUniform("scale", c.Time)
+	})
+	for _, time := range []float64{2, 3} {
+		p := mr.drawProperties(&scratch, &DrawContext{Node: node, Time: time})
+		assert.Equal(t, &scratch, p)
+		assert.Equal(t, 2, p.Len())
+		assert.Equal(t, math.Vec4{1, 0, 0, 1}, p.Get("color"))
+		assert.Equal(t, float32(time), p.Get("scale"))
+	}
+	assert.Equal(t, float32(1), properties.Get("scale"))
+}
@@ -0,0 +1,97 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// QuadBatch draws 2D quads sharing a texture with a single draw call. Each quad
+// samples its own part of the texture and is multiplied by its own color:
+//
+//	b := dax.NewQuadBatch(texture)
+//	b.AddQuad(&corners, &uvs, &dax.Color{R: 1, G: 1, B: 1, A: 1})
+//	fb.Draw(b)
+//
+// Quads lie in the z = 0 plane of world space and are seen through the camera
+// of the framebuffer they are drawn into. They are blended over the
+// framebuffer, without depth test, in the order they were added. The sprite
+// package builds on QuadBatch.
+type QuadBatch struct {
+	texture   *Texture
+	positions []float32
+	uvs       []float32
+	colors    []float32
+
+	// cache is the mesh drawn, kept between draws. Its indices are the ones
+	// of indexed quads.
+	cache   *Mesh
+	indexed int
+}
+
+// NewQuadBatch creates an empty QuadBatch sampling texture. A nil texture
+// draws quads of plain colors.
+func NewQuadBatch(texture *Texture) *QuadBatch {
+	return &QuadBatch{
+		texture: texture,
+	}
+}
+
+// SetTexture sets the texture sampled by the quads.
+func (b *QuadBatch) SetTexture(texture *Texture) {
+	b.texture = texture
+}
+
+// Texture returns the texture sampled by the quads.
+func (b *QuadBatch) Texture() *Texture {
+	return b.texture
+}
+
+// AddQuad adds a quad with the given corners, in counter-clockwise order, and
+// the texture coordinates of each corner. The texture color is multiplied by
+// color.
+func (b *QuadBatch) AddQuad(corners, uvs *[4]math.Vec2, color *Color) {
+	for i := 0; i < 4; i++ {
+		b.positions = append(b.positions, corners[i][0], corners[i][1])
+		b.uvs = append(b.uvs, uvs[i][0], uvs[i][1])
+		b.colors = append(b.colors, color.R, color.G, color.B, color.A)
+	}
+}
+
+// Len returns the number of quads.
+func (b *QuadBatch) Len() int {
+	return len(b.positions) / 8
+}
+
+// Clear removes all the quads, keeping the texture.
+func (b *QuadBatch) Clear() {
+	b.positions = b.positions[:0]
+	b.uvs = b.uvs[:0]
+	b.colors = b.colors[:0]
+}
+
+// mesh returns the triangles of the quads, two per quad.
+func (b *QuadBatch) mesh() *Mesh {
+	if b.cache == nil {
+		b.cache = NewMesh()
+	}
+	m := b.cache
+	m.AddAttribute("position", b.positions, 2)
+	m.AddAttribute("uv", b.uvs, 2)
+	m.AddAttribute("color", b.colors, 4)
+
+	// The indices only depend on the number of quads.
+	if n := b.Len(); n != b.indexed || !m.HasIndices() {
+		indices := make([]uint, 0, 6*n)
+		for i := 0; i < n; i++ {
+			v := uint(4 * i)
+			indices = append(indices, v, v+1, v+2, v, v+2, v+3)
+		}
+		m.AddIndices(indices)
+		b.indexed = n
+	}
+	return m
+}
+
+// Draw implements Drawer.
+func (b *QuadBatch) Draw(fb Framebuffer) {
+	fb.render().drawQuadBatch(fb, b)
+}
@@ -0,0 +1,35 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuadBatch(t *testing.T) {
+	b := NewQuadBatch(nil)
+	corners := [4]math.Vec2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	uvs := [4]math.Vec2{{0, 1}, {1, 1}, {1, 0}, {0, 0}}
+	b.AddQuad(&corners, &uvs, &Color{1, 0, 0, 1})
+	b.AddQuad(&corners, &uvs, &Color{0, 1, 0, .5})
+	assert.Equal(t, 2, b.Len())
+
+	// Two triangles per quad.
+	mesh := b.mesh()
+	assert.Equal(t, 8, mesh.GetAttribute("position").Len())
+	assert.Equal(t, 8, mesh.GetAttribute("uv").Len())
+	assert.Equal(t, 8, mesh.GetAttribute("color").Len())
+	assert.Equal(t, 12, mesh.GetIndices().Len())
+	assert.Equal(t, uint16(4), mesh.indices.data16[6])
+	assert.Equal(t, uint16(7), mesh.indices.data16[11])
+
+	b.Clear()
+	assert.Equal(t, 0, b.Len())
+
+	// The mesh is reused between draws.
+	b.AddQuad(&corners, &uvs, &Color{1, 0, 0, 1})
+	assert.Equal(t, mesh, b.mesh())
+	assert.Equal(t, 4, mesh.GetAttribute("position").Len())
+	assert.Equal(t, 6, mesh.GetIndices().Len())
+}
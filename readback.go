@@ -0,0 +1,76 @@
+package dax
+
+import (
+	"unsafe"
+)
+
+// glReadback is data copied from the GPU into a pixel pack buffer, waiting
+// for the GPU to be done with the copy.
+type glReadback struct {
+	buffer uint32
+	size   int
+	fence  unsafe.Pointer
+	done   func(data []byte)
+}
+
+// glReadbackQueue reads data back from the GPU without stalling: the data is
+// copied into a buffer by the GPU and only mapped once the fence inserted
+// after the copy is signaled, usually a frame or two later.
+type glReadbackQueue struct {
+	pending []glReadback
+
+	// GL operations, replaced in tests.
+	isSignaled    func(fence unsafe.Pointer) bool
+	deleteFence   func(fence unsafe.Pointer)
+	readBuffer    func(buffer uint32, size int) []byte
+	releaseBuffer func(buffer uint32)
+}
+
+// add queues the read back of the size bytes copied into buffer before fence
+// was inserted. done is called with the data by the poll seeing the fence
+// signaled.
+func (q *glReadbackQueue) add(buffer uint32, size int, fence unsafe.Pointer, done func(data []byte)) {
+	q.pending = append(q.pending, glReadback{buffer, size, fence, done})
+}
+
+// poll calls the callbacks of the read backs the GPU is done with. It's
+// called at the end of each frame.
+func (q *glReadbackQueue) poll() {
+	// Fences are signaled in order, stop at the first unsignaled one.
+	n := 0
+	for ; n < len(q.pending); n++ {
+		r := &q.pending[n]
+		if !q.isSignaled(r.fence) {
+			break
+		}
+		q.deleteFence(r.fence)
+		data := q.readBuffer(r.buffer, r.size)
+		q.releaseBuffer(r.buffer)
+		r.done(data)
+	}
+	q.pending = append(q.pending[:0], q.pending[n:]...)
+}
+
+// cancel drops the pending read backs without calling their callbacks.
+func (q *glReadbackQueue) cancel() {
+	for _, r := range q.pending {
+		q.deleteFence(r.fence)
+		q.releaseBuffer(r.buffer)
+	}
+	q.pending = nil
+}
+
+// len returns the number of read backs waiting for the GPU.
+func (q *glReadbackQueue) len() int {
+	return len(q.pending)
+}
+
+// bytesToFloat32s returns b as float32s, in the native byte order, without
+// copying it.
+func bytesToFloat32s(b []byte) []float32 {
+	n := len(b) / 4
+	if n == 0 {
+		return nil
+	}
+	return (*[1 << 28]float32)(unsafe.Pointer(&b[0]))[:n:n]
+}
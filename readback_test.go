@@ -0,0 +1,85 @@
+package dax
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (gpu *fakeGPU) readbacks(gc *glGarbageCollector) *glReadbackQueue {
+	return &glReadbackQueue{
+		isSignaled:  gc.isSignaled,
+		deleteFence: gc.deleteFence,
+		readBuffer: func(buffer uint32, size int) []byte {
+			data := make([]byte, size)
+			for i := range data {
+				data[i] = byte(buffer)
+			}
+			return data
+		},
+		releaseBuffer: func(buffer uint32) {
+			gc.release(glObjectBuffer, buffer)
+		},
+	}
+}
+
+func TestGLReadbackQueue(t *testing.T) {
+	gpu := &fakeGPU{}
+	gc := gpu.collector(0)
+	q := gpu.readbacks(gc)
+
+	var read [][]byte
+	done := func(data []byte) {
+		read = append(read, data)
+	}
+
+	q.add(1, 2, gc.fenceSync(), done)
+	q.add(2, 1, gc.fenceSync(), done)
+	assert.Equal(t, 2, q.len())
+
+	// The GPU may still be copying the data.
+	q.poll()
+	assert.Empty(t, read)
+
+	// Read backs are done in order, as their fences are signaled.
+	*gpu.fences[0] = true
+	q.poll()
+	assert.Equal(t, [][]byte{{1, 1}}, read)
+	assert.Equal(t, 1, q.len())
+
+	gpu.signal()
+	q.poll()
+	assert.Equal(t, [][]byte{{1, 1}, {2}}, read)
+	assert.Equal(t, 0, q.len())
+
+	// The buffers go through the garbage collector.
+	gc.endFrame()
+	gpu.signal()
+	gc.endFrame()
+	assert.Equal(t, []glObject{{glObjectBuffer, 1}, {glObjectBuffer, 2}}, gpu.deleted)
+}
+
+func TestGLReadbackQueueCancel(t *testing.T) {
+	gpu := &fakeGPU{}
+	gc := gpu.collector(0)
+	q := gpu.readbacks(gc)
+
+	called := false
+	q.add(1, 4, gc.fenceSync(), func([]byte) { called = true })
+	q.cancel()
+	assert.Equal(t, 0, q.len())
+	assert.Equal(t, 1, gc.len())
+
+	gpu.signal()
+	q.poll()
+	assert.False(t, called)
+}
+
+func TestBytesToFloat32s(t *testing.T) {
+	assert.Nil(t, bytesToFloat32s(nil))
+
+	f := []float32{1, .5}
+	b := (*[8]byte)(unsafe.Pointer(&f[0]))[:]
+	assert.Equal(t, f, bytesToFloat32s(b))
+}
@@ -3,7 +3,9 @@ package dax
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
+	"strings"
 	"unsafe"
 
 	"github.com/dlespiau/dax/math"
@@ -13,6 +15,11 @@ import (
 
 const (
 	polylineMaterial = "-dax-material-polyline"
+	gridMaterial     = "-dax-material-grid"
+	statsMaterial    = "-dax-material-stats"
+	displayMaterial  = "-dax-material-display"
+	quadMaterial     = "-dax-material-quad"
+	debugMaterial    = "-dax-material-debug"
 )
 
 type uploadInput struct {
@@ -39,7 +46,7 @@ func (u *glUniformUser) upload(input uploadInput) {
 type glUniformMVP glUniform
 
 func (u *glUniformMVP) upload(input uploadInput) {
-	cameraTransform := cameraTransform(input.fb.GetCamera())
+	cameraTransform := cameraTransform(input.fb.GetCamera(), input.fb.IsReversedZ())
 	gl.UniformMatrix4fv(u.location, 1, false, &cameraTransform[0])
 }
 
@@ -83,38 +90,79 @@ func (vbo *glIndexBuffer) upload() {
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, size, ptr, gl.STATIC_DRAW)
 }
 
+// glVAO is the VAO of a mesh, with a buffer per attribute and, if the mesh
+// has indices, an index buffer.
 type glVAO struct {
 	id      uint32
 	vbos    []glAttributeBuffer
 	indices glIndexBuffer
+	// instances is the per-instance buffer of instanced draws, created on
+	// the first one.
+	instances uint32
+	// enabled are the vertex attribute arrays enabled by the last draw.
+	enabled []uint32
+	// used is set when the VAO is drawn during the frame.
+	used bool
 }
 
-func newVAOFromMesh(mesh *Mesh) *glVAO {
-	vao := &glVAO{}
-
-	gl.GenVertexArrays(1, &vao.id)
-
+// setLayout makes vao hold a buffer per attribute of mesh and an index buffer
+// if mesh has indices. No GL object is created.
+func (vao *glVAO) setLayout(mesh *Mesh) {
 	for i := range mesh.attributes {
-		ab := &mesh.attributes[i]
+		vao.vbos = append(vao.vbos, glAttributeBuffer{
+			buffer: &mesh.attributes[i],
+		})
+	}
+
+	if mesh.indices.Len() > 0 {
+		vao.indices.buffer = &mesh.indices
+	}
+}
 
-		vbo := glAttributeBuffer{
-			buffer: ab,
+// matches returns true if the buffers of vao are the ones of mesh: the VAO can
+// be reused to draw mesh. Meshes can gain attributes or indices after being
+// drawn.
+func (vao *glVAO) matches(mesh *Mesh) bool {
+	if len(vao.vbos) != len(mesh.attributes) {
+		return false
+	}
+	for i := range vao.vbos {
+		if vao.vbos[i].buffer != &mesh.attributes[i] {
+			return false
 		}
-		gl.GenBuffers(1, &vbo.id)
+	}
+	return (vao.indices.buffer != nil) == (mesh.indices.Len() > 0)
+}
+
+// init creates the GL objects of the VAO, once its layout has been set.
+func (vao *glVAO) init() {
+	gl.GenVertexArrays(1, &vao.id)
 
-		vao.vbos = append(vao.vbos, vbo)
+	for i := range vao.vbos {
+		gl.GenBuffers(1, &vao.vbos[i].id)
 	}
 
-	if mesh.indices.Len() > 0 {
-		vao.indices.buffer = &mesh.indices
+	if vao.indices.buffer != nil {
 		gl.GenBuffers(1, &vao.indices.id)
 	}
-
-	return vao
 }
 
+// bind binds the VAO and disables the vertex attribute arrays the previous
+// draw enabled: each draw sets up the attributes of its own program.
 func (vao *glVAO) bind() {
 	gl.BindVertexArray(vao.id)
+
+	for _, index := range vao.enabled {
+		gl.DisableVertexAttribArray(index)
+		gl.VertexAttribDivisor(index, 0)
+	}
+	vao.enabled = vao.enabled[:0]
+}
+
+// enable enables the vertex attribute array index. The VAO has to be bound.
+func (vao *glVAO) enable(index uint32) {
+	gl.EnableVertexAttribArray(index)
+	vao.enabled = append(vao.enabled, index)
 }
 
 func (vao *glVAO) upload() {
@@ -124,15 +172,179 @@ func (vao *glVAO) upload() {
 	vao.indices.upload()
 }
 
-func (vao *glVAO) destroy() {
+// release queues the VAO and its buffers for deletion.
+func (vao *glVAO) release(gc *glGarbageCollector) {
 	for i := range vao.vbos {
-		vbo := &vao.vbos[i]
-		gl.DeleteBuffers(1, &vbo.id)
+		gc.release(glObjectBuffer, vao.vbos[i].id)
+	}
+	gc.release(glObjectBuffer, vao.indices.id)
+	gc.release(glObjectBuffer, vao.instances)
+	gc.release(glObjectVertexArray, vao.id)
+}
+
+// vaoCache keeps the VAOs of the meshes drawn so the next draws of a mesh
+// reuse its VAO and buffers instead of creating new ones. The mesh data is
+// still uploaded by each draw as meshes can be modified in place. The VAOs of
+// meshes not drawn during a frame are released at the end of the frame.
+type vaoCache struct {
+	vaos map[*Mesh]*glVAO
+	// free recycles the glVAO structures of released VAOs.
+	free []*glVAO
+}
+
+// get returns the VAO of mesh and true if it has been drawn before. Otherwise,
+// it returns a glVAO with the layout of mesh, false, and its GL objects have
+// to be created. A VAO not matching the mesh layout anymore is released into
+// gc. Cache hits and misses are recorded in counters.
+func (c *vaoCache) get(mesh *Mesh, gc *glGarbageCollector, counters *renderCounters) (*glVAO, bool) {
+	vao := c.vaos[mesh]
+	if vao != nil && vao.matches(mesh) {
+		counters.poolHits++
+		vao.used = true
+		return vao, true
+	}
+	if vao != nil {
+		c.release(vao, gc)
 	}
-	if vao.indices.id != 0 {
-		gl.DeleteBuffers(1, &vao.indices.id)
+
+	counters.poolMisses++
+	if n := len(c.free); n > 0 {
+		vao = c.free[n-1]
+		c.free = c.free[:n-1]
+	} else {
+		vao = &glVAO{}
+	}
+	vao.setLayout(mesh)
+	vao.used = true
+	if c.vaos == nil {
+		c.vaos = make(map[*Mesh]*glVAO)
+	}
+	c.vaos[mesh] = vao
+	return vao, false
+}
+
+// release queues the GL objects of vao for deletion and recycles it.
+func (c *vaoCache) release(vao *glVAO, gc *glGarbageCollector) {
+	vao.release(gc)
+	*vao = glVAO{
+		vbos:    vao.vbos[:0],
+		enabled: vao.enabled[:0],
+	}
+	c.free = append(c.free, vao)
+}
+
+// prune releases the VAOs of the meshes that haven't been drawn since the
+// last prune.
+func (c *vaoCache) prune(gc *glGarbageCollector) {
+	for mesh, vao := range c.vaos {
+		if !vao.used {
+			delete(c.vaos, mesh)
+			c.release(vao, gc)
+			continue
+		}
+		vao.used = false
+	}
+}
+
+// clear releases all the VAOs.
+func (c *vaoCache) clear(gc *glGarbageCollector) {
+	for mesh, vao := range c.vaos {
+		delete(c.vaos, mesh)
+		c.release(vao, gc)
+	}
+}
+
+// vao returns the VAO of mesh, bound. It's kept by the renderer for the next
+// draws of mesh.
+func (r *renderer) vao(mesh *Mesh) *glVAO {
+	vao, ok := r.vaos.get(mesh, &r.garbage, &r.counters)
+	if !ok {
+		vao.init()
+	}
+	vao.bind()
+	return vao
+}
+
+func newGLGarbageCollector() glGarbageCollector {
+	return glGarbageCollector{
+		budget: defaultGarbageBudget,
+		fenceSync: func() unsafe.Pointer {
+			return gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+		},
+		isSignaled: func(fence unsafe.Pointer) bool {
+			status := gl.ClientWaitSync(fence, 0, 0)
+			return status == gl.ALREADY_SIGNALED || status == gl.CONDITION_SATISFIED
+		},
+		deleteFence:  gl.DeleteSync,
+		deleteObject: deleteGLObject,
+	}
+}
+
+func newGLState() glState {
+	return glState{
+		glUseProgram: gl.UseProgram,
+		glFrontFace:  gl.FrontFace,
+		glBindTexture: func(unit int, texture uint32) {
+			gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+			gl.BindTexture(gl.TEXTURE_2D, texture)
+		},
+	}
+}
+
+func newGLReadbackQueue(gc *glGarbageCollector) glReadbackQueue {
+	return glReadbackQueue{
+		isSignaled:  gc.isSignaled,
+		deleteFence: gc.deleteFence,
+		readBuffer: func(buffer uint32, size int) []byte {
+			data := make([]byte, size)
+			if size == 0 {
+				return data
+			}
+			gl.BindBuffer(gl.PIXEL_PACK_BUFFER, buffer)
+			mapped := gl.MapBufferRange(gl.PIXEL_PACK_BUFFER, 0, size, gl.MAP_READ_BIT)
+			copy(data, (*[1 << 30]byte)(mapped)[:size:size])
+			gl.UnmapBuffer(gl.PIXEL_PACK_BUFFER)
+			gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+			return data
+		},
+		releaseBuffer: func(buffer uint32) {
+			gc.release(glObjectBuffer, buffer)
+		},
+	}
+}
+
+// readPixelsAsync starts copying rect from the framebuffer currently bound
+// into a pixel pack buffer. done is called with the pixels, pixelSize bytes
+// each, at the end of the first frame the GPU is done with the copy.
+func (r *renderer) readPixelsAsync(fb Framebuffer, rect *Viewport, format, xtype uint32, pixelSize int, done func(pixels []byte)) {
+	size := rect.Width * rect.Height * pixelSize
+
+	var buffer uint32
+	gl.GenBuffers(1, &buffer)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, buffer)
+	gl.BufferData(gl.PIXEL_PACK_BUFFER, size, nil, gl.STREAM_READ)
+	readPixels(fb, rect, format, xtype, gl.PtrOffset(0))
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+
+	fence := gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+	r.readbacks.add(buffer, size, fence, done)
+}
+
+func deleteGLObject(o glObject) {
+	switch o.kind {
+	case glObjectBuffer:
+		gl.DeleteBuffers(1, &o.id)
+	case glObjectVertexArray:
+		gl.DeleteVertexArrays(1, &o.id)
+	case glObjectProgram:
+		gl.DeleteProgram(o.id)
+	case glObjectTexture:
+		gl.DeleteTextures(1, &o.id)
+	case glObjectFramebuffer:
+		gl.DeleteFramebuffers(1, &o.id)
+	case glObjectRenderbuffer:
+		gl.DeleteRenderbuffers(1, &o.id)
 	}
-	gl.DeleteVertexArrays(1, &vao.id)
 }
 
 type glProgram struct {
@@ -140,6 +352,29 @@ type glProgram struct {
 	vs        *VertexShader
 	fs        *FragmentShader
 	uploaders []glUploader
+	// lights has the locations of the lights uniforms, nil until the
+	// first draw with lights.
+	lights *glLightLocations
+	// Material uniforms uploaded to the program and their locations.
+	uniforms  uniformCache
+	locations map[string]int32
+
+	// watched is the material shader when it comes from a file and can be
+	// reloaded.
+	watched  *FragmentShader
+	logDepth bool
+}
+
+func (p *glProgram) uniformLocation(name string) int32 {
+	if location, ok := p.locations[name]; ok {
+		return location
+	}
+	if p.locations == nil {
+		p.locations = make(map[string]int32)
+	}
+	location := gl.GetUniformLocation(p.id, gl.Str(name+"\x00"))
+	p.locations[name] = location
+	return location
 }
 
 func glVertexMode(mode VertexMode) uint32 {
@@ -175,27 +410,285 @@ type renderer struct {
 	programs map[string]*glProgram
 	// The only vs we currently have :/
 	vs *VertexShader
+	// Camera transforms of the previous frame, per framebuffer and camera.
+	previousCameraTransforms map[cameraPass]*cameraHistory
+
+	// Per-frame structures, kept around between frames to limit the
+	// pressure on the GC.
+	drawList   []zNode
+	batches    []drawBatch
+	batchIndex map[batchKey]int
+	instances  []float32
+	vaos       vaoCache
+	// Temporaries of drawNode.
+	drawContext  DrawContext
+	mvp          math.Mat4
+	previousMVP  math.Mat4
+	normalMatrix math.Mat3
+
+	// GL state cached while drawing scene graphs.
+	state glState
+
+	// GL objects waiting to be deleted.
+	garbage glGarbageCollector
+	// Data on its way back from the GPU.
+	readbacks glReadbackQueue
+
+	// Depth state: true when set up for reversed-Z cameras.
+	reversedZ bool
+	// clipControl is 1 when glClipControl is available, -1 when it's not
+	// and 0 when we haven't checked yet.
+	clipControl int
+
+	// Coefficient used by materials writing a logarithmic depth, computed
+	// from the far plane of the current camera.
+	logDepthCoef float32
+
+	// What has been submitted since the start of the frame.
+	counters renderCounters
+
+	// Lights of the scene graph being drawn, the world position of its
+	// camera and its view matrix.
+	lights         []lightData
+	cameraPosition math.Vec3
+	cameraView     math.Mat4
+	// materials are the uniform values of the materials of the snapshot
+	// being drawn, for buffered scene graphs.
+	materials map[Material]*uniformValues
+
+	// Given to draw callbacks: the camera of the scene graph being drawn
+	// and the application time.
+	camera Camera
+	time   float64
+	// Uniforms set by draw callbacks.
+	drawProperties PropertyBlock
 }
 
 const vertexShader = `
 #version 330 core
 
 in vec3 position;
+in vec3 normal;
+in vec2 uv;
 
 uniform mat4 mvp;
+uniform mat4 previousMvp;
+uniform mat4 model;
+uniform mat3 normalMatrix;
+
+// Clip space positions for this frame and the previous one. Fragment shaders
+// can use them to compute per-pixel velocities.
+out vec4 clipPosition;
+out vec4 previousClipPosition;
+
+// Used by fragment shaders writing a logarithmic depth.
+out float logDepthW;
+
+// World space position and normal, for lighting.
+out vec3 worldPosition;
+out vec3 worldNormal;
+
+// Texture coordinates, (0, 0) when the mesh has none.
+out vec2 texCoord;
 
 void main(){
-	gl_Position = mvp * vec4(position, 1.0f);
+	clipPosition = mvp * vec4(position, 1.0f);
+	previousClipPosition = previousMvp * vec4(position, 1.0f);
+	gl_Position = clipPosition;
+	logDepthW = 1.0 + clipPosition.w;
+	worldPosition = (model * vec4(position, 1.0f)).xyz;
+	worldNormal = normalMatrix * normal;
+	texCoord = uv;
+}`
+
+// instancedVertexShader is the vertex shader used when drawing several
+// instances of a mesh at once. The model matrices are per-instance attributes.
+const instancedVertexShader = `
+#version 330 core
+
+in vec3 position;
+in vec3 normal;
+in vec2 uv;
+in mat4 model;
+in mat4 previousModel;
+
+uniform mat4 viewProjection;
+uniform mat4 previousViewProjection;
+
+out vec4 clipPosition;
+out vec4 previousClipPosition;
+out float logDepthW;
+out vec3 worldPosition;
+out vec3 worldNormal;
+out vec2 texCoord;
+
+void main(){
+	vec4 world = model * vec4(position, 1.0f);
+	clipPosition = viewProjection * world;
+	previousClipPosition = previousViewProjection * previousModel * vec4(position, 1.0f);
+	gl_Position = clipPosition;
+	logDepthW = 1.0 + clipPosition.w;
+	worldPosition = world.xyz;
+	worldNormal = transpose(inverse(mat3(model))) * normal;
+	texCoord = uv;
 }`
 
 func newRenderer() *renderer {
 	vs := NewVertexShader(vertexShader)
 	vs.AddAttribute(VariableKindVec3, "position")
+	vs.AddAttribute(VariableKindVec3, "normal")
+	vs.AddAttribute(VariableKindVec2, "uv")
 	vs.AddUniform(VariableKindMat4, "mvp")
+	vs.AddUniform(VariableKindMat4, "previousMvp")
+
+	r := &renderer{
+		programs:                 make(map[string]*glProgram),
+		vs:                       vs,
+		previousCameraTransforms: make(map[cameraPass]*cameraHistory),
+		batchIndex:               make(map[batchKey]int),
+		garbage:                  newGLGarbageCollector(),
+		state:                    newGLState(),
+	}
+	r.readbacks = newGLReadbackQueue(&r.garbage)
+	return r
+}
 
-	return &renderer{
-		programs: make(map[string]*glProgram),
-		vs:       vs,
+func glHasExtension(name string) bool {
+	var n int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &n)
+	for i := uint32(0); i < uint32(n); i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i)) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *renderer) hasClipControl() bool {
+	if r.clipControl == 0 {
+		r.clipControl = -1
+		if glHasExtension("GL_ARB_clip_control") {
+			r.clipControl = 1
+		}
+	}
+	return r.clipControl > 0
+}
+
+// isDepthReversed returns true if drawing into fb uses a reversed depth range,
+// either because the framebuffer or its camera asked for it.
+func isDepthReversed(fb Framebuffer) bool {
+	if fb.IsReversedZ() {
+		return true
+	}
+	c := fb.GetCamera()
+	return c != nil && isReversedZ(c)
+}
+
+// setDepthState sets up the depth test for drawing into fb. Reversed-Z maps the
+// near plane to 1: it needs a GREATER depth test, a depth buffer cleared to 0
+// and a [0, 1] clip space depth range to get the full benefit of the reversed
+// projection.
+func (r *renderer) setDepthState(fb Framebuffer) {
+	r.setDepthRange(isDepthReversed(fb))
+}
+
+// setDepthRange sets up the depth test for a reversed, or standard, depth range,
+// see setDepthState.
+func (r *renderer) setDepthRange(reversed bool) {
+	if reversed == r.reversedZ {
+		return
+	}
+	r.reversedZ = reversed
+
+	if reversed {
+		if r.hasClipControl() {
+			gl.ClipControl(gl.LOWER_LEFT, gl.ZERO_TO_ONE)
+		}
+		gl.DepthFunc(gl.GREATER)
+		gl.ClearDepth(r.defaultClearDepth())
+	} else {
+		if r.hasClipControl() {
+			gl.ClipControl(gl.LOWER_LEFT, gl.NEGATIVE_ONE_TO_ONE)
+		}
+		gl.DepthFunc(gl.LESS)
+		gl.ClearDepth(r.defaultClearDepth())
+	}
+}
+
+// defaultClearDepth returns the depth of the far plane for the current depth
+// range.
+func (r *renderer) defaultClearDepth() float64 {
+	if r.reversedZ {
+		return 0
+	}
+	return 1
+}
+
+// release deletes the GL objects of the renderer, including the ones waiting
+// to be deleted. Pending read backs are dropped.
+func (r *renderer) release() {
+	r.readbacks.cancel()
+	for key, p := range r.programs {
+		r.garbage.release(glObjectProgram, p.id)
+		delete(r.programs, key)
+	}
+	r.vaos.clear(&r.garbage)
+	r.garbage.flush()
+}
+
+// endFrame is called once the frame has been submitted.
+func (r *renderer) endFrame() {
+	r.readbacks.poll()
+	r.vaos.prune(&r.garbage)
+	r.garbage.endFrame()
+	r.prunePreviousCameraTransforms()
+}
+
+// cameraPass is a camera drawing into a framebuffer. The same camera can draw
+// into several framebuffers, with different aspect ratios or depth ranges, so
+// previous camera transforms are tracked per pass.
+type cameraPass struct {
+	fb     Framebuffer
+	camera Camera
+}
+
+// cameraHistory is the camera transform of a pass in the previous frame and
+// in the current one.
+type cameraHistory struct {
+	previous math.Mat4
+	current  math.Mat4
+	// drawn is true when the pass has been drawn in the current frame.
+	drawn bool
+}
+
+// previousCameraTransform records the camera transform used by c to draw into
+// fb this frame and returns the one it used in the previous frame. A pass that
+// wasn't drawn in the previous frame has no motion: its previous transform is
+// the current one.
+func (r *renderer) previousCameraTransform(fb Framebuffer, c Camera, transform *math.Mat4) math.Mat4 {
+	key := cameraPass{fb: fb, camera: c}
+	h, ok := r.previousCameraTransforms[key]
+	switch {
+	case !ok:
+		h = &cameraHistory{previous: *transform}
+		r.previousCameraTransforms[key] = h
+	case !h.drawn:
+		h.previous = h.current
+	}
+	h.current = *transform
+	h.drawn = true
+	return h.previous
+}
+
+// prunePreviousCameraTransforms forgets the passes that haven't been drawn this
+// frame, eg. because their framebuffer or camera is gone.
+func (r *renderer) prunePreviousCameraTransforms() {
+	for key, h := range r.previousCameraTransforms {
+		if !h.drawn {
+			delete(r.previousCameraTransforms, key)
+			continue
+		}
+		h.drawn = false
 	}
 }
 
@@ -213,11 +706,15 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 		var logLength int32
 		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
 
-		//log := strings.Repeat('\x00', int(logLength+1))
-		//gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
 
-		log := "TODO"
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+		stage := ShaderStageVertex
+		if shaderType == gl.FRAGMENT_SHADER {
+			stage = ShaderStageFragment
+		}
+		return 0, newShaderError(stage, source, log)
 	}
 
 	return shader, nil
@@ -231,6 +728,7 @@ func makeProgram(v *VertexShader, f *FragmentShader) (uint32, error) {
 
 	fragmentShader, err := compileShader(f.source, gl.FRAGMENT_SHADER)
 	if err != nil {
+		gl.DeleteShader(vertexShader)
 		return 0, err
 	}
 
@@ -246,11 +744,14 @@ func makeProgram(v *VertexShader, f *FragmentShader) (uint32, error) {
 		var logLength int32
 		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
 
-		//log := strings.Repeat("\x00", int(logLength+1))
-		log := "TODO"
+		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
 
-		return 0, fmt.Errorf("failed to link program: %v", log)
+		gl.DeleteShader(vertexShader)
+		gl.DeleteShader(fragmentShader)
+		gl.DeleteProgram(program)
+
+		return 0, newShaderError(ShaderStageLink, "", log)
 	}
 
 	gl.DeleteShader(vertexShader)
@@ -298,17 +799,13 @@ func (r *renderer) drawPolyline(fb Framebuffer, p *Polyline) {
 
 	mesh := NewMesh()
 	mesh.AddAttribute("position", p.vertices, 3)
-	vao := newVAOFromMesh(mesh)
-
-	defer vao.destroy()
-
-	vao.bind()
+	vao := r.vao(mesh)
 	vao.upload()
 
 	gl.UseProgram(program.id)
 
 	position := uint32(gl.GetAttribLocation(program.id, gl.Str("position\x00")))
-	gl.EnableVertexAttribArray(position)
+	vao.enable(position)
 	gl.VertexAttribPointer(position, 3, gl.FLOAT, false, 0, gl.PtrOffset(0))
 
 	mvp := gl.GetUniformLocation(program.id, gl.Str("mvp\x00"))
@@ -318,130 +815,83 @@ func (r *renderer) drawPolyline(fb Framebuffer, p *Polyline) {
 	whiteish := (&Color{.8, .8, .8, 1}).Vec4()
 	gl.Uniform4fv(color, 1, &whiteish[0])
 
+	r.counters.draw(VertexModeLineStrip, p.Size(), 1)
 	gl.DrawArrays(gl.LINE_STRIP, 0, int32(p.Size()))
 }
 
-type zNode struct {
-	node *Node
-	mr   *MeshRenderer
-	z    float32
-}
-
-type frontToBack []zNode
+const gridVertexShader = `
+#version 330 core
 
-func (a frontToBack) Len() int           { return len(a) }
-func (a frontToBack) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a frontToBack) Less(i, j int) bool { return a[i].z > a[j].z }
+in vec3 position;
 
-type backToFront []zNode
+uniform mat4 mvp;
 
-func (a backToFront) Len() int           { return len(a) }
-func (a backToFront) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a backToFront) Less(i, j int) bool { return a[i].z < a[j].z }
+out vec3 worldPosition;
 
-func getMeshRenderer(node *Node) *MeshRenderer {
-	var mr *MeshRenderer
-	var ok bool
-	for i := range node.components {
-		if mr, ok = node.components[i].(*MeshRenderer); ok {
-			break
-		}
-	}
+void main(){
+	worldPosition = position;
+	gl_Position = mvp * vec4(position, 1.0f);
+}`
 
-	return mr
-}
+const gridFragmentShader = `
+#version 330
 
-func opaqueFrontToBack(sg *SceneGraph, cameraTransform *math.Mat4) []zNode {
-	var nodes []zNode
-	for g := range sg.Traverse() {
-		node, ok := g.(*Node)
-		if !ok {
-			continue
-		}
+in vec3 worldPosition;
 
-		// If the material needs blending, we can't draw it in this pass. We'll have to
-		// draw it back to front
-		mr := getMeshRenderer(node)
-		if mr == nil {
-			continue
-		}
-		if mr.material.GetBlending().Enabled {
-			continue
-		}
+uniform vec3 cameraPosition;
+uniform float minorSpacing;
+uniform float majorSpacing;
+uniform float fadeDistance;
+uniform vec4 minorColor;
+uniform vec4 majorColor;
+uniform vec4 xAxisColor;
+uniform vec4 zAxisColor;
 
-		// Compute world position of the node.
-		origin := math.Vec4{0, 0, 0, 1}
-		position := node.worldTransform.AsMat4().Mul4x1(&origin)
+out vec4 outputColor;
 
-		// And get the the node position from the camera pov.
-		transformed := cameraTransform.Mul4x1(&position)
+// Returns 1.0 on a line, 0.0 away from lines, with a one pixel wide
+// anti-aliased transition.
+float gridLine(vec2 p, float spacing) {
+	vec2 coord = p / spacing;
+	vec2 d = fwidth(coord);
+	vec2 g = abs(fract(coord - 0.5) - 0.5) / d;
+	return 1.0 - min(min(g.x, g.y), 1.0);
+}
 
-		nodes = append(nodes, zNode{
-			node: node,
-			mr:   mr,
-			z:    transformed.Z(),
-		})
-	}
+float axisLine(float p) {
+	return 1.0 - min(abs(p) / fwidth(p), 1.0);
+}
 
-	// Sort the nodes by z
-	sort.Sort(frontToBack(nodes))
+void main() {
+	vec2 p = worldPosition.xz;
 
-	return nodes
-}
+	vec4 color = minorColor;
+	color.a *= gridLine(p, minorSpacing);
 
-// Compute the camera transform: projection . worldTransform^-1.
-func cameraTransform(c Camera) *math.Mat4 {
-	cameraTransform := *c.GetProjection()
+	float major = gridLine(p, majorSpacing);
+	color = mix(color, majorColor, major);
 
-	// The camera may either be part of the scene (part of the scene graph) or not.
-	// XXX: we don't check that the root of the tree the Camera is part of is
-	// indeed the scenegraph we are drawing.
-	cameraNode := c.AsNode()
-	var cameraWorldInverse math.Mat4
-	if cameraNode.parent == nil {
-		cameraWorldInverse = cameraNode.GetTransform().Inverse()
-	} else {
-		cameraWorldInverse = cameraNode.worldTransform.AsMat4().Inverse()
-	}
-	cameraTransform.Mul4With(&cameraWorldInverse)
-	return &cameraTransform
-}
+	// The X axis is the z = 0 line, the Z axis is the x = 0 line.
+	color = mix(color, xAxisColor, axisLine(p.y));
+	color = mix(color, zAxisColor, axisLine(p.x));
 
-func createUniformUploader(program *glProgram, uniform Uniform) glUploader {
-	name := uniform.Name()
-	location := gl.GetUniformLocation(program.id, gl.Str(name+"\x00"))
-	if location == -1 {
-		panic("no uniform called " + name)
-	}
+	float distance = length(worldPosition - cameraPosition);
+	color.a *= 1.0 - smoothstep(0.0, fadeDistance, distance);
 
-	switch name {
-	case "mvp":
-		return &glUniformMVP{
-			uniform:  uniform,
-			location: location,
-		}
-	default:
-		return &glUniformUser{
-			uniform:  uniform,
-			location: location,
-		}
+	if (color.a <= 0.0) {
+		discard;
 	}
-}
 
-func collectUniforms(program *glProgram, uniforms []Uniform) {
-	for _, u := range uniforms {
-		uploader := createUniformUploader(program, u)
-		program.uploaders = append(program.uploaders, uploader)
-	}
-}
+	outputColor = color;
+}`
 
-func (r *renderer) programForMaterial(m Material) *glProgram {
-	if p, ok := r.programs[m.ID()]; ok {
+func (r *renderer) makeGridProgram() *glProgram {
+	if p, ok := r.programs[gridMaterial]; ok {
 		return p
 	}
 
-	vs := NewVertexShader(vertexShader)
-	fs := m.GetFragmentShader()
+	vs := NewVertexShader(gridVertexShader)
+	fs := NewFragmentShader(gridFragmentShader)
 	p, err := makeProgram(vs, fs)
 	if err != nil {
 		panic(err)
@@ -451,80 +901,1280 @@ func (r *renderer) programForMaterial(m Material) *glProgram {
 		vs: vs,
 		fs: fs,
 	}
+	r.programs[gridMaterial] = program
+	return program
+}
 
-	gl.UseProgram(p)
+// cameraPosition returns the position of the camera in world space.
+func cameraPosition(c Camera) math.Vec3 {
+	cameraNode := c.AsNode()
+	if cameraNode.parent == nil {
+		return cameraNode.position
+	}
+	return math.Vec3{
+		cameraNode.worldTransform[12],
+		cameraNode.worldTransform[13],
+		cameraNode.worldTransform[14],
+	}
+}
 
-	// Cache uniform locations
-	collectUniforms(program, vs.uniforms)
-	collectUniforms(program, fs.uniforms)
+func uniformColor(program *glProgram, name string, c *Color) {
+	location := gl.GetUniformLocation(program.id, gl.Str(name+"\x00"))
+	v := c.Vec4()
+	gl.Uniform4fv(location, 1, &v[0])
+}
 
-	r.programs[m.ID()] = program
-	return program
+func uniformFloat(program *glProgram, name string, f float32) {
+	location := gl.GetUniformLocation(program.id, gl.Str(name+"\x00"))
+	gl.Uniform1f(location, f)
 }
 
-func (r *renderer) drawSceneGraph(fb Framebuffer, sg *SceneGraph) {
+func (r *renderer) drawGrid(fb Framebuffer, g *Grid) {
+	program := r.makeGridProgram()
 	c := fb.GetCamera()
 
-	// Update all world transform matrices.
-	sg.updateWorldTransform()
+	mesh := g.GetMesh()
+	vao := r.vao(mesh)
+	vao.upload()
 
-	// Render opaque geometry, front to back to limit overdraw thanks to early z
-	// discard.
-	cameraTransform := cameraTransform(c)
-	nodes := opaqueFrontToBack(sg, cameraTransform)
-	for i := range nodes {
-		node := &nodes[i]
+	gl.UseProgram(program.id)
 
-		// cameraTransform * node.worldTransform
-		mvp := &math.Mat4{}
+	position := uint32(gl.GetAttribLocation(program.id, gl.Str("position\x00")))
+	vao.enable(position)
+	gl.VertexAttribPointer(position, 3, gl.FLOAT, false, 0, gl.PtrOffset(0))
 
-		mesh := node.mr.mesher.GetMesh()
-		vao := newVAOFromMesh(mesh)
-		defer vao.destroy()
-		vao.bind()
+	mvp := gl.GetUniformLocation(program.id, gl.Str("mvp\x00"))
+	gl.UniformMatrix4fv(mvp, 1, false, &cameraTransform(c, fb.IsReversedZ())[0])
+
+	eye := cameraPosition(c)
+	location := gl.GetUniformLocation(program.id, gl.Str("cameraPosition\x00"))
+	gl.Uniform3fv(location, 1, &eye[0])
+
+	uniformFloat(program, "minorSpacing", g.MinorSpacing)
+	uniformFloat(program, "majorSpacing", g.MajorSpacing)
+	uniformFloat(program, "fadeDistance", g.FadeDistance)
+	uniformColor(program, "minorColor", &g.MinorColor)
+	uniformColor(program, "majorColor", &g.MajorColor)
+	uniformColor(program, "xAxisColor", &g.XAxisColor)
+	uniformColor(program, "zAxisColor", &g.ZAxisColor)
+
+	// The grid is mostly transparent.
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	defer gl.Disable(gl.BLEND)
+
+	r.counters.draw(mesh.GetVertexMode(), mesh.indices.Len(), 1)
+	gl.DrawElements(
+		glVertexMode(mesh.GetVertexMode()),
+		int32(mesh.indices.Len()),
+		glIndexType(&mesh.indices),
+		gl.PtrOffset(0))
+}
 
-		program := r.programForMaterial(node.mr.material)
-		gl.UseProgram(program.id)
+const statsVertexShader = `
+#version 330 core
 
-		// Upload each attribute buffer and link them to the vertex shader.
-		vsAttr := &r.vs.attributes[0]
-		for i := range vao.vbos {
-			vbo := vao.vbos[i]
-			ab := vbo.buffer
-			if ab.Name != vsAttr.name {
-				continue
-			}
+in vec2 position;
+in vec4 color;
 
-			vbo.upload()
+uniform vec2 viewportSize;
 
-			location := gl.GetAttribLocation(program.id, gl.Str(vsAttr.name+"\x00"))
-			if location == -1 {
-				// XXX: reports errors better
-				fmt.Fprintf(os.Stderr, "couldn't find attribute %s", vsAttr.name)
-			}
+out vec4 vertexColor;
 
-			index := uint32(location)
-			gl.EnableVertexAttribArray(index)
-			gl.VertexAttribPointer(index, 3, gl.FLOAT, false, 0, gl.PtrOffset(0))
-		}
+void main(){
+	// Window coordinates have their origin at the top left corner.
+	vec2 p = position / viewportSize * 2.0 - 1.0;
+	gl_Position = vec4(p.x, -p.y, 0.0, 1.0);
+	vertexColor = color;
+}`
 
-		// Upload indices.
-		vao.indices.upload()
+const statsFragmentShader = `
+#version 330
 
-		// Upload uniforms
-		mvp.Mul4Of(cameraTransform, node.node.worldTransform.AsMat4())
-		location := gl.GetUniformLocation(program.id, gl.Str("mvp\x00"))
-		gl.UniformMatrix4fv(location, 1, false, &mvp[0])
+in vec4 vertexColor;
 
-		color := gl.GetUniformLocation(program.id, gl.Str("color\x00"))
-		whiteish := (&Color{.8, .8, .8, 1}).Vec4()
-		gl.Uniform4fv(color, 1, &whiteish[0])
-
-		// Draw. The index array is already bound above.
-		gl.DrawElements(
-			glVertexMode(mesh.GetVertexMode()),
-			int32(mesh.indices.Len()),
-			glIndexType(&mesh.indices),
-			gl.PtrOffset(0))
+out vec4 outputColor;
+
+void main() {
+	outputColor = vertexColor;
+}`
+
+func (r *renderer) makeStatsProgram() *glProgram {
+	if p, ok := r.programs[statsMaterial]; ok {
+		return p
+	}
+
+	vs := NewVertexShader(statsVertexShader)
+	fs := NewFragmentShader(statsFragmentShader)
+	p, err := makeProgram(vs, fs)
+	if err != nil {
+		panic(err)
+	}
+	program := &glProgram{
+		id: p,
+		vs: vs,
+		fs: fs,
+	}
+	r.programs[statsMaterial] = program
+	return program
+}
+
+// drawStats draws the stats overlay at the top left corner of a width x height
+// viewport. The overlay itself isn't counted in the stats.
+func (r *renderer) drawStats(width, height int, s *Stats) {
+	r.drawOverlay(width, height, statsOverlay(s.lines()))
+}
+
+// drawOverlay draws a mesh built by an overlayBuilder on top of a width x
+// height viewport.
+func (r *renderer) drawOverlay(width, height int, mesh *Mesh) {
+	program := r.makeStatsProgram()
+
+	vao := r.vao(mesh)
+	gl.UseProgram(program.id)
+
+	for i := range vao.vbos {
+		vbo := &vao.vbos[i]
+		vbo.upload()
+
+		ab := vbo.buffer
+		location := uint32(gl.GetAttribLocation(program.id, gl.Str(ab.Name+"\x00")))
+		vao.enable(location)
+		gl.VertexAttribPointer(location, int32(ab.NumComponents), gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+
+	location := gl.GetUniformLocation(program.id, gl.Str("viewportSize\x00"))
+	gl.Uniform2f(location, float32(width), float32(height))
+
+	if gl.IsEnabled(gl.DEPTH_TEST) {
+		gl.Disable(gl.DEPTH_TEST)
+		defer gl.Enable(gl.DEPTH_TEST)
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	defer gl.Disable(gl.BLEND)
+
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(mesh.GetAttribute("position").Len()))
+}
+
+const quadVertexShader = `
+#version 330 core
+
+in vec2 position;
+in vec2 uv;
+in vec4 color;
+
+uniform mat4 mvp;
+
+out vec2 texCoord;
+out vec4 vertexColor;
+
+void main(){
+	gl_Position = mvp * vec4(position, 0.0, 1.0);
+	texCoord = uv;
+	vertexColor = color;
+}`
+
+const quadFragmentShader = `
+#version 330
+
+uniform sampler2D map;
+uniform bool textured;
+
+in vec2 texCoord;
+in vec4 vertexColor;
+
+out vec4 outputColor;
+
+void main() {
+	outputColor = vertexColor;
+	if (textured) {
+		outputColor *= texture(map, texCoord);
+	}
+}`
+
+func (r *renderer) makeQuadProgram() *glProgram {
+	if p, ok := r.programs[quadMaterial]; ok {
+		return p
+	}
+
+	vs := NewVertexShader(quadVertexShader)
+	fs := NewFragmentShader(quadFragmentShader)
+	p, err := makeProgram(vs, fs)
+	if err != nil {
+		panic(err)
+	}
+	program := &glProgram{
+		id: p,
+		vs: vs,
+		fs: fs,
+	}
+	r.programs[quadMaterial] = program
+	return program
+}
+
+func (r *renderer) drawQuadBatch(fb Framebuffer, b *QuadBatch) {
+	if b.Len() == 0 {
+		return
+	}
+
+	program := r.makeQuadProgram()
+	mesh := b.mesh()
+	vao := r.vao(mesh)
+	vao.upload()
+	gl.UseProgram(program.id)
+
+	for i := range vao.vbos {
+		ab := vao.vbos[i].buffer
+		location := uint32(gl.GetAttribLocation(program.id, gl.Str(ab.Name+"\x00")))
+		vao.enable(location)
+		gl.BindBuffer(gl.ARRAY_BUFFER, vao.vbos[i].id)
+		gl.VertexAttribPointer(location, int32(ab.NumComponents), gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+
+	uniformMat4(program, "mvp", cameraTransform(fb.GetCamera(), fb.IsReversedZ()))
+	textured := gl.GetUniformLocation(program.id, gl.Str("textured\x00"))
+	if b.texture != nil {
+		b.texture.Bind(0)
+		gl.Uniform1i(gl.GetUniformLocation(program.id, gl.Str("map\x00")), 0)
+		gl.Uniform1i(textured, 1)
+	} else {
+		gl.Uniform1i(textured, 0)
+	}
+
+	if gl.IsEnabled(gl.DEPTH_TEST) {
+		gl.Disable(gl.DEPTH_TEST)
+		defer gl.Enable(gl.DEPTH_TEST)
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	defer gl.Disable(gl.BLEND)
+
+	r.counters.draw(VertexModeTriangles, mesh.indices.Len(), 1)
+	gl.DrawElements(gl.TRIANGLES, int32(mesh.indices.Len()), glIndexType(&mesh.indices), gl.PtrOffset(0))
+}
+
+const debugVertexShader = `
+#version 330 core
+
+in vec3 position;
+in vec4 color;
+
+uniform mat4 mvp;
+
+out vec4 vertexColor;
+
+void main(){
+	gl_Position = mvp * vec4(position, 1.0);
+	vertexColor = color;
+}`
+
+func (r *renderer) makeDebugProgram() *glProgram {
+	if p, ok := r.programs[debugMaterial]; ok {
+		return p
+	}
+
+	vs := NewVertexShader(debugVertexShader)
+	fs := NewFragmentShader(statsFragmentShader)
+	p, err := makeProgram(vs, fs)
+	if err != nil {
+		panic(err)
+	}
+	program := &glProgram{
+		id: p,
+		vs: vs,
+		fs: fs,
+	}
+	r.programs[debugMaterial] = program
+	return program
+}
+
+// drawDebug draws the lines buffered by d, seen through the camera of fb.
+func (r *renderer) drawDebug(fb Framebuffer, d *DebugDraw) {
+	c := fb.GetCamera()
+	if d.Len() == 0 || c == nil {
+		return
+	}
+
+	program := r.makeDebugProgram()
+	mesh := d.mesh()
+	vao := r.vao(mesh)
+	gl.UseProgram(program.id)
+
+	for i := range vao.vbos {
+		vbo := &vao.vbos[i]
+		vbo.upload()
+
+		ab := vbo.buffer
+		location := uint32(gl.GetAttribLocation(program.id, gl.Str(ab.Name+"\x00")))
+		vao.enable(location)
+		gl.VertexAttribPointer(location, int32(ab.NumComponents), gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+
+	uniformMat4(program, "mvp", cameraTransform(c, fb.IsReversedZ()))
+
+	if gl.IsEnabled(gl.DEPTH_TEST) {
+		gl.Disable(gl.DEPTH_TEST)
+		defer gl.Enable(gl.DEPTH_TEST)
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	defer gl.Disable(gl.BLEND)
+
+	gl.DrawArrays(gl.LINES, 0, int32(2*d.Len()))
+}
+
+// displayVertexShader draws a triangle covering the whole viewport, without
+// vertex attributes.
+const displayVertexShader = `
+#version 330 core
+
+void main() {
+	vec2 p = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2);
+	gl_Position = vec4(p * 2.0 - 1.0, 0.0, 1.0);
+}`
+
+// displayFragmentShader converts colors to the output color space, see
+// ColorSpace.convert, then applies a DisplayAdjustment, see
+// DisplayAdjustment.apply.
+const displayFragmentShader = `
+#version 330
+
+uniform sampler2D image;
+// gamut converts linear sRGB colors to the output color space when
+// convertGamut is true. encoded is true when colors are encoded with the sRGB
+// transfer function.
+uniform mat3 gamut;
+uniform bool convertGamut;
+uniform bool encoded;
+uniform float gamma;
+uniform float brightness;
+uniform float contrast;
+uniform float saturation;
+
+out vec4 outputColor;
+
+vec3 srgbToLinear(vec3 c) {
+	return mix(c / 12.92, pow((c + 0.055) / 1.055, vec3(2.4)), step(0.04045, c));
+}
+
+vec3 linearToSRGB(vec3 c) {
+	return mix(c * 12.92, 1.055 * pow(c, vec3(1.0 / 2.4)) - 0.055, step(0.0031308, c));
+}
+
+void main() {
+	vec4 c = texelFetch(image, ivec2(gl_FragCoord.xy), 0);
+	vec3 rgb = c.rgb;
+	if (convertGamut) {
+		if (encoded) {
+			rgb = srgbToLinear(rgb);
+		}
+		rgb = clamp(gamut * rgb, 0.0, 1.0);
+		if (encoded) {
+			rgb = linearToSRGB(rgb);
+		}
+	}
+
+	rgb = (rgb - 0.5) * contrast + 0.5 + brightness;
+	float luma = dot(rgb, vec3(0.2126, 0.7152, 0.0722));
+	rgb = clamp(mix(vec3(luma), rgb, saturation), 0.0, 1.0);
+	if (gamma > 0.0) {
+		rgb = pow(rgb, vec3(1.0 / gamma));
+	}
+	outputColor = vec4(rgb, c.a);
+}`
+
+func (r *renderer) makeDisplayProgram() *glProgram {
+	if p, ok := r.programs[displayMaterial]; ok {
+		return p
+	}
+
+	vs := NewVertexShader(displayVertexShader)
+	fs := NewFragmentShader(displayFragmentShader)
+	p, err := makeProgram(vs, fs)
+	if err != nil {
+		panic(err)
+	}
+	program := &glProgram{
+		id: p,
+		vs: vs,
+		fs: fs,
+	}
+	r.programs[displayMaterial] = program
+	return program
+}
+
+// displayPass draws the frame of a window offscreen, to then draw it in the
+// window with a final adjustment.
+type displayPass struct {
+	target *OffScreen
+	// vao is an empty vertex array, core profiles needing one to draw.
+	vao uint32
+}
+
+// begin redirects the drawing into fb to the offscreen target, allocating it
+// with the size and depth range of fb.
+func (p *displayPass) begin(fb *onScreen, samples int) {
+	options := OffScreenOptions{
+		// Keep the precision of the colors until they are adjusted.
+		ColorFormat: TextureFormatRGBA16F,
+		DepthFormat: TextureFormatDepth24Stencil8,
+		Samples:     samples,
+		ReversedZ:   fb.reversedZ,
+	}
+	if fb.reversedZ {
+		options.DepthFormat = TextureFormatDepth32FStencil8
+	}
+
+	if p.target != nil && p.target.options != options {
+		p.target.Destroy()
+		p.target = nil
+	}
+	if p.target == nil {
+		p.target = NewOffScreen(fb, fb.width, fb.height, options)
+	} else {
+		p.target.SetSize(fb.width, fb.height)
+	}
+
+	fb.target = p.target.glID()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.target)
+}
+
+// release releases the GL objects of the pass.
+func (p *displayPass) release(gc *glGarbageCollector) {
+	if p.target != nil {
+		p.target.Destroy()
+		p.target = nil
+	}
+	gc.release(glObjectVertexArray, p.vao)
+	p.vao = 0
+}
+
+// drawDisplay ends a display pass started with displayPass.begin: the frame
+// drawn offscreen is drawn into the window, converted to the color space cs
+// and adjusted by a. encoded is true when the window framebuffer isn't sRGB,
+// colors being then drawn encoded with the sRGB transfer function.
+func (r *renderer) drawDisplay(p *displayPass, fb *onScreen, cs ColorSpace, encoded bool, a *DisplayAdjustment) {
+	p.target.resolve()
+	fb.target = 0
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	program := r.makeDisplayProgram()
+	if p.vao == 0 {
+		gl.GenVertexArrays(1, &p.vao)
+	}
+	gl.BindVertexArray(p.vao)
+	gl.UseProgram(program.id)
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, p.target.color.id)
+	gl.Uniform1i(program.uniformLocation("image"), 0)
+	gamut := cs.fromSRGB()
+	gl.UniformMatrix3fv(program.uniformLocation("gamut"), 1, false, &gamut[0])
+	gl.Uniform1i(program.uniformLocation("convertGamut"), glBool(cs != ColorSpaceSRGB))
+	gl.Uniform1i(program.uniformLocation("encoded"), glBool(encoded))
+	uniformFloat(program, "gamma", a.Gamma)
+	uniformFloat(program, "brightness", a.Brightness)
+	uniformFloat(program, "contrast", a.Contrast)
+	uniformFloat(program, "saturation", a.Saturation)
+
+	if gl.IsEnabled(gl.DEPTH_TEST) {
+		gl.Disable(gl.DEPTH_TEST)
+		defer gl.Enable(gl.DEPTH_TEST)
+	}
+	if gl.IsEnabled(gl.BLEND) {
+		gl.Disable(gl.BLEND)
+		defer gl.Enable(gl.BLEND)
+	}
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Viewport(0, 0, int32(fb.width), int32(fb.height))
+
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+
+	applyViewport(&fb.viewport)
+}
+
+func glBool(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type zNode struct {
+	node     *Node
+	mr       *MeshRenderer
+	z        float32
+	mirrored bool
+	// state is, for buffered scene graphs, the snapshot of the node.
+	state *nodeState
+}
+
+// transforms returns the world and previous world transforms of the node.
+func (n *zNode) transforms() (world, previous *math.Mat4) {
+	if n.state != nil {
+		return &n.state.world, &n.state.previous
+	}
+	return n.node.worldTransform.AsMat4(), n.node.PreviousWorldTransform()
+}
+
+// contextNodes returns the Node and Instance of the DrawContext of the node.
+func (n *zNode) contextNodes() (node, instance *Node) {
+	if n.state != nil {
+		return n.state.source, n.state.instance
+	}
+	if n.node.isInstanced() {
+		instance = n.node.outerInstance()
+	}
+	return n.node.source(), instance
+}
+
+type frontToBack []zNode
+
+func (a frontToBack) Len() int           { return len(a) }
+func (a frontToBack) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a frontToBack) Less(i, j int) bool { return a[i].z > a[j].z }
+
+type backToFront []zNode
+
+func (a backToFront) Len() int           { return len(a) }
+func (a backToFront) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a backToFront) Less(i, j int) bool { return a[i].z < a[j].z }
+
+func opaqueFrontToBack(sg *SceneGraph, cameraTransform *math.Mat4) []zNode {
+	return appendOpaqueFrontToBack(nil, sg, nil, cameraTransform)
+}
+
+// appendOpaqueFrontToBack is opaqueFrontToBack reusing the nodes slice. Nodes
+// outside of the camera frustum are skipped. The nodes of the snapshot s are
+// used instead of the ones of sg when s isn't nil.
+func appendOpaqueFrontToBack(nodes []zNode, sg *SceneGraph, s *sceneState, cameraTransform *math.Mat4) []zNode {
+	frustum := math.ViewFrustumFromMat4(cameraTransform)
+
+	nodes = nodes[:0]
+	if s != nil {
+		for i := range s.nodes {
+			state := &s.nodes[i]
+			nodes = appendVisible(nodes, zNode{
+				node:     state.node,
+				mr:       &state.mr,
+				mirrored: state.mirrored,
+				state:    state,
+			}, &state.bounds, &state.world, &frustum, cameraTransform)
+		}
+		sort.Sort(frontToBack(nodes))
+		return nodes
+	}
+
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok {
+			continue
+		}
+
+		// If the material needs blending, we can't draw it in this pass. We'll have to
+		// draw it back to front
+		mr := getMeshRenderer(node)
+		if mr == nil {
+			continue
+		}
+		if mr.material.GetBlending().Enabled {
+			continue
+		}
+
+		bounds := mr.Bounds()
+		nodes = appendVisible(nodes, zNode{
+			node:     node,
+			mr:       mr,
+			mirrored: node.isMirrored(),
+		}, &bounds, node.worldTransform.AsMat4(), &frustum, cameraTransform)
+	}
+
+	// Sort the nodes by z
+	sort.Sort(frontToBack(nodes))
+
+	return nodes
+}
+
+// appendVisible appends n, transformed by world, to nodes with its depth if
+// its bounds aren't outside of the frustum.
+func appendVisible(nodes []zNode, n zNode, bounds *math.AABB, world *math.Mat4, frustum *math.ViewFrustum, cameraTransform *math.Mat4) []zNode {
+	if isCulled(frustum, bounds, world) {
+		return nodes
+	}
+
+	// Compute world position of the node.
+	origin := math.Vec4{0, 0, 0, 1}
+	position := world.Mul4x1(&origin)
+
+	// And get the the node position from the camera pov.
+	transformed := cameraTransform.Mul4x1(&position)
+	n.z = transformed.Z()
+
+	return append(nodes, n)
+}
+
+// isCulled returns true if bounds, transformed by world, are outside the
+// frustum. Meshes without bounds are never culled.
+func isCulled(frustum *math.ViewFrustum, bounds *math.AABB, world *math.Mat4) bool {
+	if bounds.IsEmpty() {
+		return false
+	}
+	transformed := bounds.Transform(world)
+	return !frustum.IntersectsAABB(&transformed)
+}
+
+// Compute the camera transform: projection . worldTransform^-1. reversed is
+// true when drawing into a reversed-Z framebuffer, see cameraProjection.
+func cameraTransform(c Camera, reversed bool) *math.Mat4 {
+	cameraTransform := cameraProjection(c, reversed)
+	view := cameraView(c)
+	cameraTransform.Mul4With(&view)
+	return &cameraTransform
+}
+
+func createUniformUploader(program *glProgram, uniform Uniform) glUploader {
+	name := uniform.Name()
+	location := gl.GetUniformLocation(program.id, gl.Str(name+"\x00"))
+	if location == -1 {
+		panic("no uniform called " + name)
+	}
+
+	switch name {
+	case "mvp":
+		return &glUniformMVP{
+			uniform:  uniform,
+			location: location,
+		}
+	default:
+		return &glUniformUser{
+			uniform:  uniform,
+			location: location,
+		}
+	}
+}
+
+func collectUniforms(program *glProgram, uniforms []Uniform) {
+	for _, u := range uniforms {
+		uploader := createUniformUploader(program, u)
+		program.uploaders = append(program.uploaders, uploader)
+	}
+}
+
+func (r *renderer) programForMaterial(m Material) *glProgram {
+	return r.materialProgram(m.ID(), vertexShader, m)
+}
+
+// instancedProgramForMaterial returns the program used to draw many instances
+// of a mesh with the material m in a single draw call.
+func (r *renderer) instancedProgramForMaterial(m Material) *glProgram {
+	return r.materialProgram(m.ID()+"-instanced", instancedVertexShader, m)
+}
+
+func (r *renderer) materialProgram(key, vsSource string, m Material) *glProgram {
+	logDepth := m.GetDepthTest().Logarithmic
+	if logDepth {
+		key += "-logdepth"
+	}
+	if p, ok := r.programs[key]; ok {
+		return p
+	}
+
+	program, err := newMaterialProgram(NewVertexShader(vsSource), m.GetFragmentShader(), logDepth)
+	if err != nil {
+		panic(err)
+	}
+	r.state.programChanged(program.id)
+
+	r.programs[key] = program
+	return program
+}
+
+func newMaterialProgram(vs *VertexShader, fs *FragmentShader, logDepth bool) (*glProgram, error) {
+	program := &glProgram{
+		vs:       vs,
+		logDepth: logDepth,
+	}
+	if fs.filename != "" {
+		program.watched = fs
+	}
+
+	if logDepth {
+		// Materials may hand out the same shader each time: don't modify
+		// it.
+		defined := *fs
+		defined.source = shaderDefine(fs.source, "DAX_LOG_DEPTH")
+		fs = &defined
+	}
+	p, err := makeProgram(vs, fs)
+	if err != nil {
+		return nil, err
+	}
+	program.id = p
+	program.fs = fs
+
+	gl.UseProgram(p)
+
+	// Cache uniform locations
+	collectUniforms(program, vs.uniforms)
+	collectUniforms(program, fs.uniforms)
+
+	return program, nil
+}
+
+// reloadShaders rebuilds the programs of the material shaders whose file has
+// changed. Errors are reported and the previous source and programs are kept,
+// so a typo doesn't bring the application down.
+func (r *renderer) reloadShaders() {
+	checked := make(map[*FragmentShader]bool)
+	sources := make(map[*FragmentShader]string)
+	for _, p := range r.programs {
+		fs := p.watched
+		if fs == nil || checked[fs] {
+			continue
+		}
+		checked[fs] = true
+
+		source, changed, err := fs.reload()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", fs.filename, err)
+		}
+		if changed {
+			sources[fs] = source
+		}
+	}
+
+	for fs, source := range sources {
+		if err := r.rebuildPrograms(fs, source); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", fs.filename, err)
+		}
+	}
+}
+
+// rebuildPrograms rebuilds the programs using fs with its new source. The
+// source is only swapped in once all of them compile: programs created later,
+// eg. for new materials, never see a broken source.
+func (r *renderer) rebuildPrograms(fs *FragmentShader, source string) error {
+	defer r.state.invalidate()
+
+	previous := fs.source
+	fs.source = source
+	rebuilt := make(map[string]*glProgram)
+	for key, p := range r.programs {
+		if p.watched != fs {
+			continue
+		}
+		program, err := newMaterialProgram(p.vs, fs, p.logDepth)
+		if err != nil {
+			fs.source = previous
+			for _, program := range rebuilt {
+				r.garbage.release(glObjectProgram, program.id)
+			}
+			return err
+		}
+		rebuilt[key] = program
+	}
+
+	for key, program := range rebuilt {
+		r.garbage.release(glObjectProgram, r.programs[key].id)
+		r.programs[key] = program
+	}
+	return nil
+}
+
+// drawBatch is a list of nodes that can be drawn with a single instanced draw
+// call: they share the same mesher, material and property block.
+type drawBatch struct {
+	mesher     Mesher
+	material   Material
+	properties *PropertyBlock
+	mirrored   bool
+	nodes      []*zNode
+}
+
+type batchKey struct {
+	mesher     Mesher
+	material   Material
+	properties *PropertyBlock
+	mirrored   bool
+	// node is set, instead of mesher and material, when the node has a draw
+	// callback or a mesher or material that can't be a map key, so it gets
+	// its own batch.
+	node *Node
+}
+
+// isComparable returns true if v can be part of a map key. Meshers and
+// materials can be values of types that can't, eg. structs with a slice.
+func isComparable(v interface{}) bool {
+	return v == nil || reflect.TypeOf(v).Comparable()
+}
+
+// batchNodes groups nodes sharing the same mesher and material. Meshers are
+// compared rather than meshes as they usually generate a new mesh each time
+// they're asked for one. Batches are ordered by their first node so a front to
+// back list of nodes stays roughly front to back.
+func batchNodes(nodes []zNode) []drawBatch {
+	return appendBatches(nil, make(map[batchKey]int), nodes)
+}
+
+// appendBatches is batchNodes reusing the batches slice, as well as the node
+// lists of the batches, and the index map.
+func appendBatches(batches []drawBatch, index map[batchKey]int, nodes []zNode) []drawBatch {
+	batches = batches[:0]
+	for key := range index {
+		delete(index, key)
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		key := batchKey{
+			mesher:     node.mr.mesher,
+			material:   node.mr.material,
+			properties: node.mr.properties,
+			mirrored:   node.mirrored,
+		}
+		if node.mr.onDraw != nil || !isComparable(key.mesher) || !isComparable(key.material) {
+			key.mesher, key.material = nil, nil
+			key.node = node.node
+		}
+
+		if b, ok := index[key]; ok {
+			batches[b].nodes = append(batches[b].nodes, node)
+			continue
+		}
+
+		index[key] = len(batches)
+		if len(batches) < cap(batches) {
+			batches = batches[:len(batches)+1]
+		} else {
+			batches = append(batches, drawBatch{})
+		}
+		b := &batches[len(batches)-1]
+		b.mesher = node.mr.mesher
+		b.material = node.mr.material
+		b.properties = key.properties
+		b.mirrored = key.mirrored
+		b.nodes = append(b.nodes[:0], node)
+	}
+
+	return batches
+}
+
+// setupVAO binds the VAO of mesh and uploads the mesh vertices and indices.
+func (r *renderer) setupVAO(program *glProgram, mesh *Mesh) *glVAO {
+	vao := r.vao(mesh)
+
+	// Upload each attribute buffer and link them to the vertex shader.
+	for i := range vao.vbos {
+		vbo := vao.vbos[i]
+		ab := vbo.buffer
+		if !r.vs.hasAttribute(ab.Name) {
+			continue
+		}
+
+		location := gl.GetAttribLocation(program.id, gl.Str(ab.Name+"\x00"))
+		if location == -1 {
+			if ab.Name == "position" {
+				// XXX: reports errors better
+				fmt.Fprintf(os.Stderr, "couldn't find attribute %s", ab.Name)
+			}
+			// Other attributes may not be used by the material.
+			continue
+		}
+
+		vbo.upload()
+
+		index := uint32(location)
+		vao.enable(index)
+		gl.VertexAttribPointer(index, int32(ab.NumComponents), gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+
+	// Upload indices.
+	vao.indices.upload()
+
+	return vao
+}
+
+// frontFace returns the winding of front faces. Mirrored nodes have their
+// triangle winding flipped.
+func frontFace(mirrored bool) uint32 {
+	if mirrored {
+		return gl.CW
+	}
+	return gl.CCW
+}
+
+func uniformMat4(program *glProgram, name string, m *math.Mat4) {
+	location := gl.GetUniformLocation(program.id, gl.Str(name+"\x00"))
+	gl.UniformMatrix4fv(location, 1, false, &m[0])
+}
+
+func (r *renderer) drawNode(node *zNode, cameraTransform, previousCameraTransform *math.Mat4) {
+	mesh := node.mr.mesher.GetMesh()
+	program := r.programForMaterial(node.mr.material)
+	r.state.useProgram(program.id)
+
+	r.setupVAO(program, mesh)
+
+	// Upload uniforms
+	world, previous := node.transforms()
+	r.mvp.Mul4Of(cameraTransform, world)
+	uniformMat4(program, "mvp", &r.mvp)
+
+	r.previousMVP.Mul4Of(previousCameraTransform, previous)
+	uniformMat4(program, "previousMvp", &r.previousMVP)
+
+	uniformMat4(program, "model", world)
+	r.normalMatrix = world.Mat3()
+	r.normalMatrix = r.normalMatrix.Inverse()
+	r.normalMatrix.Transpose()
+	location := gl.GetUniformLocation(program.id, gl.Str("normalMatrix\x00"))
+	gl.UniformMatrix3fv(location, 1, false, &r.normalMatrix[0])
+
+	uniformFloat(program, "logDepthCoef", r.logDepthCoef)
+	r.uploadLights(program)
+	c := &r.drawContext
+	*c = DrawContext{
+		Camera: r.camera,
+		Time:   r.time,
+	}
+	c.Node, c.Instance = node.contextNodes()
+	properties := node.mr.drawProperties(&r.drawProperties, c)
+	r.uploadMaterialUniforms(program, node.mr.material, properties)
+
+	r.state.setFrontFace(frontFace(node.mirrored))
+
+	// Draw. The index array is already bound above.
+	r.counters.draw(mesh.GetVertexMode(), mesh.indices.Len(), 1)
+	gl.DrawElements(
+		glVertexMode(mesh.GetVertexMode()),
+		int32(mesh.indices.Len()),
+		glIndexType(&mesh.indices),
+		gl.PtrOffset(0))
+}
+
+// glLightLocations are the locations of the uniforms declared by
+// LightsShaderChunk, and of the view matrix.
+type glLightLocations struct {
+	count          int32
+	cameraPosition int32
+	view           int32
+	lights         [MaxLights]struct {
+		kind, color, position, direction, lightRange, cosInner, cosOuter int32
+	}
+}
+
+func newGLLightLocations(program *glProgram) *glLightLocations {
+	get := func(name string) int32 {
+		return gl.GetUniformLocation(program.id, gl.Str(name+"\x00"))
+	}
+
+	l := &glLightLocations{
+		count:          get("lightCount"),
+		cameraPosition: get("cameraPosition"),
+		view:           get("view"),
+	}
+	for i := range l.lights {
+		prefix := fmt.Sprintf("lights[%d].", i)
+		light := &l.lights[i]
+		light.kind = get(prefix + "kind")
+		light.color = get(prefix + "color")
+		light.position = get(prefix + "position")
+		light.direction = get(prefix + "direction")
+		light.lightRange = get(prefix + "range")
+		light.cosInner = get(prefix + "cosInner")
+		light.cosOuter = get(prefix + "cosOuter")
+	}
+	return l
+}
+
+// uploadLights uploads the lights of the scene to programs using them.
+func (r *renderer) uploadLights(program *glProgram) {
+	if program.lights == nil {
+		program.lights = newGLLightLocations(program)
+	}
+	locations := program.lights
+	// Unlit materials can use the camera position and view matrix too, eg.
+	// for view dependent effects.
+	gl.Uniform3fv(locations.cameraPosition, 1, &r.cameraPosition[0])
+	gl.UniformMatrix4fv(locations.view, 1, false, &r.cameraView[0])
+	if locations.count == -1 {
+		// The material isn't lit.
+		return
+	}
+
+	gl.Uniform1i(locations.count, int32(len(r.lights)))
+	for i := range r.lights {
+		light := &r.lights[i]
+		l := &locations.lights[i]
+		gl.Uniform1i(l.kind, light.kind)
+		gl.Uniform3fv(l.color, 1, &light.color[0])
+		gl.Uniform3fv(l.position, 1, &light.position[0])
+		gl.Uniform3fv(l.direction, 1, &light.direction[0])
+		gl.Uniform1f(l.lightRange, light.lightRange)
+		gl.Uniform1f(l.cosInner, light.cosInner)
+		gl.Uniform1f(l.cosOuter, light.cosOuter)
+	}
+}
+
+// uploadUniform uploads a value set with SetUniform. unit is the texture unit
+// of textures.
+func uploadUniform(location int32, value interface{}, unit int) {
+	switch v := value.(type) {
+	case float32:
+		gl.Uniform1f(location, v)
+	case int32:
+		gl.Uniform1i(location, v)
+	case math.Vec2:
+		gl.Uniform2fv(location, 1, &v[0])
+	case math.Vec3:
+		gl.Uniform3fv(location, 1, &v[0])
+	case math.Vec4:
+		gl.Uniform4fv(location, 1, &v[0])
+	case math.Mat3:
+		gl.UniformMatrix3fv(location, 1, false, &v[0])
+	case math.Mat4:
+		gl.UniformMatrix4fv(location, 1, false, &v[0])
+	case *Texture:
+		gl.Uniform1i(location, int32(unit))
+	}
+}
+
+// uploadMaterialUniforms uploads the uniform values of the material that
+// changed since the last draw with program and binds its textures, then
+// applies the property block, if any. Property blocks are uploaded at each
+// draw.
+func (r *renderer) uploadMaterialUniforms(program *glProgram, m Material, properties *PropertyBlock) {
+	values := r.materialValues(m)
+	if values == nil {
+		values = &uniformValues{}
+	}
+
+	// Materials that don't set a color get a default one.
+	if values.get("color") == nil && properties.Get("color") == nil {
+		whiteish := (&Color{.8, .8, .8, 1}).Vec4()
+		gl.Uniform4fv(program.uniformLocation("color"), 1, &whiteish[0])
+	}
+
+	program.uniforms.update(values, func(v *uniformValue, unit int) {
+		if location := program.uniformLocation(v.name); location != -1 {
+			uploadUniform(location, v.value, unit)
+		}
+	})
+
+	// Textures need to be bound again as other draws may have used the
+	// same units.
+	units := values.forEach(0, func(v *uniformValue, unit int) {
+		if t, ok := v.value.(*Texture); ok {
+			r.state.bindTexture(unit, t.id)
+		}
+	})
+
+	if properties == nil {
+		return
+	}
+	properties.values.forEach(units, func(v *uniformValue, unit int) {
+		if t, ok := v.value.(*Texture); ok {
+			r.state.bindTexture(unit, t.id)
+		}
+		if location := program.uniformLocation(v.name); location != -1 {
+			uploadUniform(location, v.value, unit)
+		}
+		// Restore the material value at the next draw.
+		program.uniforms.invalidate(v.name)
+	})
+}
+
+// materialValues returns the uniform values of m, the copy of the snapshot
+// being drawn for buffered scene graphs, nil if m doesn't have any.
+func (r *renderer) materialValues(m Material) *uniformValues {
+	if !isComparable(m) {
+		return materialUniforms(m)
+	}
+	if values, ok := r.materials[m]; ok {
+		return values
+	}
+	return materialUniforms(m)
+}
+
+// Size of the per-instance data: the model and previous model matrices.
+const instanceStride = 2 * 16 * 4
+
+func instanceMat4Attribute(vao *glVAO, program *glProgram, name string, offset int) {
+	location := gl.GetAttribLocation(program.id, gl.Str(name+"\x00"))
+	if location == -1 {
+		return
+	}
+
+	// A mat4 attribute takes 4 consecutive locations, one per column.
+	for i := 0; i < 4; i++ {
+		index := uint32(location) + uint32(i)
+		vao.enable(index)
+		gl.VertexAttribPointer(index, 4, gl.FLOAT, false, instanceStride,
+			gl.PtrOffset(offset+i*4*4))
+		gl.VertexAttribDivisor(index, 1)
+	}
+}
+
+func (r *renderer) drawInstanced(b *drawBatch, cameraTransform, previousCameraTransform *math.Mat4) {
+	instances := r.instances[:0]
+	for _, node := range b.nodes {
+		world, previous := node.transforms()
+		instances = append(instances, world[:]...)
+		instances = append(instances, previous[:]...)
+	}
+	r.instances = instances
+
+	r.drawInstances(b.mesher.GetMesh(), b.material, b.properties, b.mirrored, instances,
+		cameraTransform, previousCameraTransform)
+}
+
+// drawInstancedMesh draws the copies of an InstancedMesh.
+func (r *renderer) drawInstancedMesh(fb Framebuffer, m *InstancedMesh) {
+	if m.Len() == 0 {
+		return
+	}
+
+	c := fb.GetCamera()
+	r.setDepthState(fb)
+	r.logDepthCoef = math.LogDepthCoefficient(cameraFar(c))
+	r.cameraPosition = cameraPosition(c)
+	r.cameraView = cameraView(c)
+	r.camera = c
+
+	// The GL state is only cached while drawing.
+	r.state.invalidate()
+	defer r.state.invalidate()
+
+	r.instances = m.appendInstances(r.instances[:0])
+	cameraTransform := cameraTransform(c, fb.IsReversedZ())
+	r.drawInstances(m.mesher.GetMesh(), m.material, m.properties, false, r.instances,
+		cameraTransform, cameraTransform)
+}
+
+// drawInstances draws a copy of mesh per instance. instances has the model
+// and previous model matrices of each copy, see instanceStride.
+func (r *renderer) drawInstances(mesh *Mesh, material Material, properties *PropertyBlock, mirrored bool,
+	instances []float32, cameraTransform, previousCameraTransform *math.Mat4) {
+	program := r.instancedProgramForMaterial(material)
+	r.state.useProgram(program.id)
+
+	vao := r.setupVAO(program, mesh)
+
+	// Upload the per-instance matrices.
+	if vao.instances == 0 {
+		gl.GenBuffers(1, &vao.instances)
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, vao.instances)
+	gl.BufferData(gl.ARRAY_BUFFER, len(instances)*4, gl.Ptr(instances), gl.STREAM_DRAW)
+	instanceMat4Attribute(vao, program, "model", 0)
+	instanceMat4Attribute(vao, program, "previousModel", 16*4)
+
+	// Upload uniforms
+	uniformMat4(program, "viewProjection", cameraTransform)
+	uniformMat4(program, "previousViewProjection", previousCameraTransform)
+
+	uniformFloat(program, "logDepthCoef", r.logDepthCoef)
+	r.uploadLights(program)
+	r.uploadMaterialUniforms(program, material, properties)
+
+	r.state.setFrontFace(frontFace(mirrored))
+
+	count := len(instances) * 4 / instanceStride
+	r.counters.draw(mesh.GetVertexMode(), mesh.indices.Len(), count)
+	gl.DrawElementsInstanced(
+		glVertexMode(mesh.GetVertexMode()),
+		int32(mesh.indices.Len()),
+		glIndexType(&mesh.indices),
+		gl.PtrOffset(0),
+		int32(count))
+}
+
+// prepareSceneGraph reads what drawing sg with the camera c needs: the state of
+// the camera, the lights and the batches of visible opaque nodes, front to
+// back. Buffered scene graphs give their last snapshot, returned to be given
+// back with endDraw, and never read sg or c otherwise. ok is false when there's
+// nothing to draw yet.
+func (r *renderer) prepareSceneGraph(sg *SceneGraph, c Camera, reversed bool) (camera cameraState, state *sceneState, ok bool) {
+	var drawOrder DrawOrder
+	if sg.Buffered() {
+		state = sg.beginDraw(c)
+		if state == nil {
+			return camera, nil, false
+		}
+		// Cameras are captured by the updates following their first draw.
+		if camera, ok = state.cameras[c.AsNode()]; !ok {
+			return camera, state, false
+		}
+		r.materials = state.materials
+		r.lights = append(r.lights[:0], state.lights...)
+		drawOrder = state.drawOrder
+	} else {
+		sg.updateWorldTransform()
+		fitNearFar(c, sg, nil)
+		camera.capture(c)
+		r.lights = appendLights(r.lights, sg)
+		drawOrder = sg.drawOrder
+	}
+
+	// Nodes sharing the same mesh and material are drawn in one go.
+	cameraTransform := camera.transform(reversed)
+	r.drawList = appendOpaqueFrontToBack(r.drawList, sg, state, &cameraTransform)
+	r.batches = appendBatches(r.batches, r.batchIndex, r.drawList)
+	if drawOrder == DrawOrderState {
+		sortBatchesByState(r.batches, r.materialValues)
+	}
+	return camera, state, true
+}
+
+func (r *renderer) drawSceneGraph(fb Framebuffer, sg *SceneGraph) {
+	c := fb.GetCamera()
+
+	// Buffered scene graphs draw their last snapshot, others have all their
+	// world transform matrices updated.
+	camera, state, ok := r.prepareSceneGraph(sg, c, fb.IsReversedZ())
+	if state != nil {
+		defer sg.endDraw()
+		defer func() { r.materials = nil }()
+	}
+	if !ok {
+		return
+	}
+
+	r.setDepthRange(fb.IsReversedZ() || camera.reversedZ)
+	r.logDepthCoef = math.LogDepthCoefficient(camera.far)
+	r.cameraPosition = camera.position
+	r.cameraView = camera.view
+	r.camera = c
+	if appInstance != nil {
+		r.time = appInstance.Time()
+	}
+
+	// Render opaque geometry, front to back to limit overdraw thanks to early z
+	// discard.
+	cameraTransform := camera.transform(fb.IsReversedZ())
+	previousCameraTransform := r.previousCameraTransform(fb, c, &cameraTransform)
+
+	// The GL state is only cached while drawing: anything may change it
+	// between two scene graphs.
+	r.state.invalidate()
+	defer r.state.invalidate()
+
+	nodes, batches := r.drawList, r.batches
+	for i := range batches {
+		b := &batches[i]
+		if len(b.nodes) == 1 {
+			r.drawNode(b.nodes[0], &cameraTransform, &previousCameraTransform)
+		} else {
+			r.drawInstanced(b, &cameraTransform, &previousCameraTransform)
+		}
+	}
+
+	// Remember this frame transforms for the next one. Snapshots already did.
+	if state != nil {
+		return
+	}
+	for i := range nodes {
+		nodes[i].node.savePreviousWorldTransform()
 	}
 }
@@ -0,0 +1,182 @@
+package dax
+
+import (
+	"sort"
+)
+
+// maxCachedTextureUnits is the number of texture units whose bound texture is
+// cached by glState.
+const maxCachedTextureUnits = 16
+
+// glState caches the GL state set while drawing a scene graph to skip the
+// redundant state changes between draws: the bound program, the winding of
+// front faces and the textures bound to the first texture units. The cache is
+// invalidated at the start of each scene graph draw as anything, eg. user code
+// in an OnAfterDraw hook, may have changed the state since.
+type glState struct {
+	valid     bool
+	program   uint32
+	frontFace uint32
+	textures  [maxCachedTextureUnits]uint32
+
+	// GL operations, replaced in tests.
+	glUseProgram  func(program uint32)
+	glFrontFace   func(mode uint32)
+	glBindTexture func(unit int, texture uint32)
+}
+
+// invalidate forgets the cached state.
+func (s *glState) invalidate() {
+	s.valid = false
+}
+
+// reset makes the cache valid again, with nothing known of the GL state.
+func (s *glState) reset() {
+	if s.valid {
+		return
+	}
+	s.valid = true
+	s.program = 0
+	s.frontFace = 0
+	s.textures = [maxCachedTextureUnits]uint32{}
+}
+
+func (s *glState) useProgram(program uint32) {
+	s.reset()
+	if s.program == program && program != 0 {
+		return
+	}
+	s.program = program
+	s.glUseProgram(program)
+}
+
+// programChanged records that program was bound behind the cache's back.
+func (s *glState) programChanged(program uint32) {
+	if s.valid {
+		s.program = program
+	}
+}
+
+func (s *glState) setFrontFace(mode uint32) {
+	s.reset()
+	if s.frontFace == mode {
+		return
+	}
+	s.frontFace = mode
+	s.glFrontFace(mode)
+}
+
+func (s *glState) bindTexture(unit int, texture uint32) {
+	s.reset()
+	if unit >= maxCachedTextureUnits {
+		s.glBindTexture(unit, texture)
+		return
+	}
+	if s.textures[unit] == texture && texture != 0 {
+		return
+	}
+	s.textures[unit] = texture
+	s.glBindTexture(unit, texture)
+}
+
+// DrawOrder is the order the opaque nodes of a scene graph are drawn in, see
+// SceneGraph.SetDrawOrder.
+type DrawOrder int
+
+const (
+	// DrawOrderFrontToBack draws the nodes closest to the camera first, so
+	// the depth test discards the hidden fragments of the nodes drawn
+	// after them before shading them. It's the default.
+	DrawOrderFrontToBack DrawOrder = iota
+	// DrawOrderState groups the draws sharing a shader, then a texture
+	// and a material, to limit the GL state changes between draws. Draws
+	// sharing the same state are still drawn front to back. It's faster
+	// than DrawOrderFrontToBack for scenes with many draws and cheap
+	// shaders.
+	DrawOrderState
+)
+
+// batchSortKey is the GL state a batch is drawn with, from the most to the
+// least expensive to change.
+type batchSortKey struct {
+	program string
+	texture uint32
+	// material is the rank of the first appearance of the material in the
+	// batches, materials not being ordered.
+	material int
+}
+
+func (k *batchSortKey) less(k2 *batchSortKey) bool {
+	if k.program != k2.program {
+		return k.program < k2.program
+	}
+	if k.texture != k2.texture {
+		return k.texture < k2.texture
+	}
+	return k.material < k2.material
+}
+
+// materialTexture returns the id of the first texture of the uniform values of
+// a material, 0 if it has none.
+func materialTexture(values *uniformValues) uint32 {
+	if values == nil {
+		return 0
+	}
+	for _, v := range values.values {
+		if t, ok := v.value.(*Texture); ok && t != nil {
+			return t.id
+		}
+	}
+	return 0
+}
+
+// sortBatchesByState sorts batches so the ones sharing a program, then a
+// texture and a material, are drawn one after the other. The sort is stable:
+// batches sharing the same state keep their order. values returns the uniform
+// values of the materials.
+func sortBatchesByState(batches []drawBatch, values func(m Material) *uniformValues) {
+	byState := batchesByState{
+		batches: batches,
+		keys:    make([]*batchSortKey, len(batches)),
+	}
+	materials := make(map[Material]*batchSortKey)
+	for i := range batches {
+		m := batches[i].material
+
+		// Materials that can't be map keys have a key per batch.
+		var key *batchSortKey
+		if isComparable(m) {
+			key = materials[m]
+		}
+		if key == nil {
+			program := m.ID()
+			if m.GetDepthTest().Logarithmic {
+				program += "-logdepth"
+			}
+			key = &batchSortKey{
+				program:  program,
+				texture:  materialTexture(values(m)),
+				material: i,
+			}
+			if isComparable(m) {
+				materials[m] = key
+			}
+		}
+		byState.keys[i] = key
+	}
+
+	sort.Stable(byState)
+}
+
+// batchesByState sorts batches by their sort keys.
+type batchesByState struct {
+	batches []drawBatch
+	keys    []*batchSortKey
+}
+
+func (a batchesByState) Len() int           { return len(a.batches) }
+func (a batchesByState) Less(i, j int) bool { return a.keys[i].less(a.keys[j]) }
+func (a batchesByState) Swap(i, j int) {
+	a.batches[i], a.batches[j] = a.batches[j], a.batches[i]
+	a.keys[i], a.keys[j] = a.keys[j], a.keys[i]
+}
@@ -0,0 +1,116 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedGL records the GL state changes going through a glState.
+type recordedGL struct {
+	calls []string
+}
+
+func (r *recordedGL) state() *glState {
+	return &glState{
+		glUseProgram: func(program uint32) {
+			r.calls = append(r.calls, "program")
+		},
+		glFrontFace: func(mode uint32) {
+			r.calls = append(r.calls, "frontFace")
+		},
+		glBindTexture: func(unit int, texture uint32) {
+			r.calls = append(r.calls, "texture")
+		},
+	}
+}
+
+func TestGLState(t *testing.T) {
+	gl := &recordedGL{}
+	s := gl.state()
+
+	// Redundant state changes are skipped.
+	s.useProgram(1)
+	s.useProgram(1)
+	s.setFrontFace(2)
+	s.setFrontFace(2)
+	s.bindTexture(0, 3)
+	s.bindTexture(0, 3)
+	s.bindTexture(1, 3)
+	assert.Equal(t, []string{"program", "frontFace", "texture", "texture"}, gl.calls)
+
+	// Programs bound by others are taken into account.
+	gl.calls = nil
+	s.programChanged(4)
+	s.useProgram(4)
+	s.useProgram(1)
+	assert.Equal(t, []string{"program"}, gl.calls)
+
+	// Nothing is known of the state once invalidated.
+	gl.calls = nil
+	s.invalidate()
+	s.programChanged(1)
+	s.useProgram(1)
+	s.setFrontFace(2)
+	s.bindTexture(0, 3)
+	assert.Equal(t, []string{"program", "frontFace", "texture"}, gl.calls)
+}
+
+type otherOpaqueMaterial struct {
+	BaseMaterial
+}
+
+func (m *otherOpaqueMaterial) ID() string {
+	return "-dax-material-other"
+}
+
+func TestSortBatchesByState(t *testing.T) {
+	base1 := &dummyOpaqueMaterial{}
+	base2 := &dummyOpaqueMaterial{}
+	other := &otherOpaqueMaterial{}
+	textured := &dummyOpaqueMaterial{}
+	textured.SetUniform("map", &Texture{id: 1})
+
+	batches := []drawBatch{
+		{material: base1},
+		{material: other},
+		{material: textured},
+		{material: base2},
+		{material: base1, mirrored: true},
+	}
+	sortBatchesByState(batches, materialUniforms)
+
+	var order []Material
+	for _, b := range batches {
+		order = append(order, b.material)
+	}
+	// Grouped by program, then texture and material, keeping the order
+	// of batches sharing the same state.
+	assert.Equal(t, []Material{base1, base1, base2, textured, other}, order)
+	assert.False(t, batches[0].mirrored)
+	assert.True(t, batches[1].mirrored)
+}
+
+// valueMaterial is a Material that can't be a map key.
+type valueMaterial struct {
+	*dummyOpaqueMaterial
+	tags []string
+}
+
+func TestSortBatchesByStateNotComparable(t *testing.T) {
+	material := &dummyOpaqueMaterial{}
+	value := valueMaterial{dummyOpaqueMaterial: &dummyOpaqueMaterial{}}
+
+	batches := []drawBatch{
+		{material: value},
+		{material: material},
+		{material: value, mirrored: true},
+	}
+	sortBatchesByState(batches, materialUniforms)
+
+	// Batches of materials that can't be compared are ordered as distinct
+	// materials.
+	assert.Equal(t, material, batches[1].material)
+	assert.False(t, batches[0].mirrored)
+	assert.True(t, batches[2].mirrored)
+}
@@ -68,7 +68,7 @@ func buildTestSceneGraph() *testCtx {
 
 func TestOpaqueFrontToBack(t *testing.T) {
 	ctx := buildTestSceneGraph()
-	cameraTransform := cameraTransform(ctx.c)
+	cameraTransform := cameraTransform(ctx.c, false)
 	nodes := opaqueFrontToBack(ctx.sg, cameraTransform)
 
 	assert.Equal(t, 2, len(nodes))
@@ -79,3 +79,210 @@ func TestOpaqueFrontToBack(t *testing.T) {
 	assert.Equal(t, ctx.a, nodes[1].node)
 	assertFloat(t, -0.2, nodes[1].z, 1e-6)
 }
+
+func TestOpaqueFrontToBackCulling(t *testing.T) {
+	sg := buildDepthTestScene()
+	// Nodes without bounds are never culled.
+	empty := createDummyNode()
+	empty.SetPosition(0, 0, 100)
+	sg.AddChild(empty)
+	sg.updateWorldTransform()
+
+	// Camera at the origin, looking down -z. Only the cube at z = -10 is
+	// inside the frustum: the cube at z = -50 is further than the far plane
+	// and the one at z = 20 is behind the camera.
+	c := NewPerspectiveCamera(math.DegToRad(60), 1, .1, 30)
+	nodes := opaqueFrontToBack(sg, cameraTransform(c, false))
+
+	assert.Equal(t, 2, len(nodes))
+	assertFloat(t, -10, nodes[0].node.position[2], 1e-6)
+	assert.Equal(t, empty, nodes[1].node)
+}
+
+type sharedMesher struct {
+	mesh *Mesh
+}
+
+func (m *sharedMesher) GetMesh() *Mesh { return m.mesh }
+
+func TestBatchNodes(t *testing.T) {
+	mesher := &sharedMesher{NewMesh()}
+	material := &dummyOpaqueMaterial{}
+
+	a := NewMeshRenderer(mesher, material)
+	b := NewMeshRenderer(mesher, &dummyOpaqueMaterial{})
+	c := NewMeshRenderer(&sharedMesher{NewMesh()}, material)
+
+	nodes := []zNode{
+		{mr: a},
+		{mr: b},
+		{mr: a},
+		{mr: c},
+		{mr: a, mirrored: true},
+		{mr: b},
+	}
+
+	batches := batchNodes(nodes)
+	assert.Equal(t, 4, len(batches))
+	assert.Equal(t, []*zNode{&nodes[0], &nodes[2]}, batches[0].nodes)
+	assert.Equal(t, []*zNode{&nodes[1], &nodes[5]}, batches[1].nodes)
+	assert.Equal(t, []*zNode{&nodes[3]}, batches[2].nodes)
+	assert.Equal(t, []*zNode{&nodes[4]}, batches[3].nodes)
+	assert.True(t, batches[3].mirrored)
+}
+
+func TestBatchNodesPropertyBlocks(t *testing.T) {
+	mesher := &sharedMesher{NewMesh()}
+	material := &dummyOpaqueMaterial{}
+	red, green := NewPropertyBlock(), NewPropertyBlock()
+
+	newNode := func(properties *PropertyBlock) zNode {
+		mr := NewMeshRenderer(mesher, material)
+		mr.SetPropertyBlock(properties)
+		return zNode{mr: mr}
+	}
+	nodes := []zNode{
+		newNode(red),
+		newNode(nil),
+		newNode(green),
+		newNode(red),
+	}
+
+	// Only nodes sharing a property block are drawn together.
+	batches := batchNodes(nodes)
+	assert.Equal(t, 3, len(batches))
+	assert.Equal(t, red, batches[0].properties)
+	assert.Equal(t, []*zNode{&nodes[0], &nodes[3]}, batches[0].nodes)
+	assert.Nil(t, batches[1].properties)
+	assert.Equal(t, green, batches[2].properties)
+}
+
+func TestBatchNodesDrawCallback(t *testing.T) {
+	mesher := &sharedMesher{NewMesh()}
+	material := &dummyOpaqueMaterial{}
+
+	a := NewMeshRenderer(mesher, material)
+	b := NewMeshRenderer(mesher, material)
+	b.OnDraw(func(c *DrawContext) {})
+
+	// Nodes with a draw callback are never instanced, even when they share
+	// the same MeshRenderer.
+	nodes := []zNode{
+		{node: NewNode(), mr: a},
+		{node: NewNode(), mr: b},
+		{node: NewNode(), mr: a},
+		{node: NewNode(), mr: b},
+	}
+
+	batches := batchNodes(nodes)
+	assert.Equal(t, 3, len(batches))
+	assert.Equal(t, []*zNode{&nodes[0], &nodes[2]}, batches[0].nodes)
+	assert.Equal(t, []*zNode{&nodes[1]}, batches[1].nodes)
+	assert.Equal(t, []*zNode{&nodes[3]}, batches[2].nodes)
+}
+
+// valueMesher is a Mesher that can't be a map key.
+type valueMesher struct {
+	meshes []*Mesh
+}
+
+func (m valueMesher) GetMesh() *Mesh { return m.meshes[0] }
+
+func TestBatchNodesNotComparable(t *testing.T) {
+	mesher := valueMesher{[]*Mesh{NewMesh()}}
+	mr := NewMeshRenderer(mesher, &dummyOpaqueMaterial{})
+
+	// Nodes with meshers that can't be compared get their own batch.
+	nodes := []zNode{
+		{node: NewNode(), mr: mr},
+		{node: NewNode(), mr: mr},
+	}
+
+	batches := batchNodes(nodes)
+	assert.Equal(t, 2, len(batches))
+	assert.Equal(t, []*zNode{&nodes[0]}, batches[0].nodes)
+	assert.Equal(t, mesher.meshes[0], batches[0].mesher.GetMesh())
+	assert.Equal(t, []*zNode{&nodes[1]}, batches[1].nodes)
+}
+
+func TestAppendBatchesReuse(t *testing.T) {
+	mesher := &sharedMesher{NewMesh()}
+	a := NewMeshRenderer(mesher, &dummyOpaqueMaterial{})
+	b := NewMeshRenderer(mesher, &dummyOpaqueMaterial{})
+	nodes := []zNode{{mr: a}, {mr: b}, {mr: a}}
+
+	index := make(map[batchKey]int)
+	batches := appendBatches(nil, index, nodes)
+
+	// Once warmed up, building the batches doesn't allocate.
+	allocs := testing.AllocsPerRun(10, func() {
+		batches = appendBatches(batches, index, nodes)
+	})
+	assert.Equal(t, float64(0), allocs)
+	assert.Equal(t, 2, len(batches))
+	assert.Equal(t, []*zNode{&nodes[0], &nodes[2]}, batches[0].nodes)
+}
+
+func TestPreviousCameraTransform(t *testing.T) {
+	r := &renderer{previousCameraTransforms: make(map[cameraPass]*cameraHistory)}
+	fb1, fb2 := &OffScreen{}, &OffScreen{}
+	c := NewPerspectiveCamera(45, 1, 1, 100)
+	t1, t2, t3 := math.Translate3D(1, 0, 0), math.Translate3D(2, 0, 0), math.Translate3D(3, 0, 0)
+
+	// First frame: no motion.
+	assert.Equal(t, t1, r.previousCameraTransform(fb1, c, &t1))
+	r.prunePreviousCameraTransforms()
+
+	// Drawing several times in a frame keeps the transform of the previous
+	// frame.
+	assert.Equal(t, t1, r.previousCameraTransform(fb1, c, &t2))
+	assert.Equal(t, t1, r.previousCameraTransform(fb1, c, &t2))
+	// Same camera, other framebuffer.
+	assert.Equal(t, t2, r.previousCameraTransform(fb2, c, &t2))
+	r.prunePreviousCameraTransforms()
+	assert.Equal(t, 2, len(r.previousCameraTransforms))
+
+	// fb2 isn't drawn anymore and is forgotten.
+	assert.Equal(t, t2, r.previousCameraTransform(fb1, c, &t3))
+	r.prunePreviousCameraTransforms()
+	assert.Equal(t, 1, len(r.previousCameraTransforms))
+	assert.Equal(t, t3, r.previousCameraTransform(fb2, c, &t3))
+}
+
+func TestVAOCache(t *testing.T) {
+	var c vaoCache
+	var counters renderCounters
+	gc := (&fakeGPU{}).collector(0)
+
+	mesh := NewMesh()
+	mesh.AddAttribute("position", []float32{0, 0, 0}, 3)
+
+	vao, ok := c.get(mesh, gc, &counters)
+	assert.False(t, ok)
+	assert.Equal(t, 1, len(vao.vbos))
+	assert.Equal(t, 1, counters.poolMisses)
+	vao.id = 1
+
+	// The next draws of mesh reuse its VAO.
+	c.prune(gc)
+	cached, ok := c.get(mesh, gc, &counters)
+	assert.True(t, ok)
+	assert.Equal(t, vao, cached)
+	assert.Equal(t, 1, counters.poolHits)
+
+	// The VAO doesn't match the mesh once it gains an attribute.
+	mesh.AddAttribute("normal", []float32{0, 0, 1}, 3)
+	vao, ok = c.get(mesh, gc, &counters)
+	assert.False(t, ok)
+	assert.Equal(t, 2, len(vao.vbos))
+	assert.Equal(t, 2, counters.poolMisses)
+	assert.Equal(t, 1, gc.len())
+	vao.id = 2
+
+	// VAOs of meshes not drawn during a frame are released.
+	c.prune(gc)
+	assert.Equal(t, 1, len(c.vaos))
+	c.prune(gc)
+	assert.Empty(t, c.vaos)
+	assert.Equal(t, 2, gc.len())
+}
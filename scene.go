@@ -18,8 +18,8 @@ type Scener interface {
 
 	// events
 	OnResize(fb Framebuffer, width, height int)
-	OnKeyPressed()
-	OnKeyReleased()
+	OnKeyPressed(key Key, scancode int, mods ModifierKey)
+	OnKeyReleased(key Key, scancode int, mods ModifierKey)
 	OnMouseMoved(x, y float32)
 	OnMouseButtonPressed(button MouseButton, x, y float32)
 	OnMouseButtonReleased(button MouseButton, x, y float32)
@@ -32,11 +32,51 @@ const (
 	sceneDirtyCamera sceneDirtyFlags = 1 << iota
 )
 
+// ClearFlags select the buffers cleared before drawing a scene.
+type ClearFlags uint
+
+const (
+	// ClearColor clears the color buffer to the scene background color.
+	ClearColor ClearFlags = 1 << iota
+	// ClearDepth clears the depth buffer to the far plane, or to the value
+	// given to Scene.SetClearDepth.
+	ClearDepth
+	// ClearStencil clears the stencil buffer to the value given to
+	// Scene.SetClearStencil, 0 by default.
+	ClearStencil
+
+	// ClearNone doesn't clear anything: the scene draws on top of what the
+	// framebuffer already holds. Only OffScreen framebuffers keep their
+	// contents between frames, eg. the canvas of a painting application.
+	// Windows are double buffered and the contents of their back buffer are
+	// undefined after a swap.
+	ClearNone ClearFlags = 0
+	// ClearDefault is what's cleared when the scene doesn't say otherwise.
+	ClearDefault = ClearColor | ClearDepth
+)
+
+// sceneClear is the clear configuration of a scene. The zero value clears
+// with the defaults.
+type sceneClear struct {
+	flags    ClearFlags
+	flagsSet bool
+	depth    float32
+	depthSet bool
+	stencil  int
+}
+
 type Scene struct {
 	camera          Camera
 	name            string
 	backgroundColor Color
+	clear           sceneClear
 	dirty           sceneDirtyFlags
+	events          EventDispatcher
+
+	// floatingOrigin rebases floatingGraph every frame, see
+	// SetFloatingOrigin.
+	floatingOrigin *FloatingOrigin
+	floatingGraph  *SceneGraph
 }
 
 func (s *Scene) isDirty(flag sceneDirtyFlags) bool {
@@ -54,6 +94,29 @@ func (s *Scene) clearDirty(flag sceneDirtyFlags) {
 func (s *Scene) Setup() {
 }
 
+// Events returns the event dispatcher of the scene.
+func (s *Scene) Events() *EventDispatcher {
+	return &s.events
+}
+
+// On registers f to be called when an event of the given kind is sent by the
+// scene. See EventDispatcher.On.
+func (s *Scene) On(kind EventKind, f func(e Event)) HookID {
+	return s.events.On(kind, f)
+}
+
+// Off unregisters a function registered with On.
+func (s *Scene) Off(id HookID) {
+	s.events.Off(id)
+}
+
+// AddSceneGraph sends the node events of sg to the scene too, see
+// EventDispatcher.Forward. Scenes add the scene graphs they want to see the
+// events of, usually in Setup, once they're created.
+func (s *Scene) AddSceneGraph(sg *SceneGraph) {
+	sg.events.Forward(&s.events)
+}
+
 func toScene(s Scener) *Scene {
 	if scene, ok := s.(*Scene); ok {
 		return scene
@@ -89,6 +152,17 @@ func sceneSetup(s Scener, fb Framebuffer) {
 		width, height := fb.Size()
 		scene.SetCamera(NewScreenSpaceCamera(width, height, -1, 1))
 	}
+
+	if scene := toScene(s); scene != nil {
+		scene.events.emit(Event{Kind: EventSceneSetup})
+	}
+}
+
+func sceneTearDown(s Scener) {
+	if scene := toScene(s); scene != nil {
+		scene.events.emit(Event{Kind: EventSceneTearDown})
+	}
+	s.TearDown()
 }
 
 func (s *Scene) TearDown() {
@@ -105,6 +179,44 @@ func (s *Scene) SetBackgroundColor(r, g, b, a float32) {
 	s.backgroundColor.A = a
 }
 
+// SetClear selects the buffers cleared before drawing the scene. It defaults
+// to ClearDefault.
+func (s *Scene) SetClear(flags ClearFlags) {
+	s.clear.flags = flags
+	s.clear.flagsSet = true
+}
+
+// ClearFlags returns the buffers cleared before drawing the scene.
+func (s *Scene) ClearFlags() ClearFlags {
+	if !s.clear.flagsSet {
+		return ClearDefault
+	}
+	return s.clear.flags
+}
+
+// SetClearDepth sets the value the depth buffer is cleared to, overriding the
+// far plane of the depth range.
+func (s *Scene) SetClearDepth(depth float32) {
+	s.clear.depth = depth
+	s.clear.depthSet = true
+}
+
+// ClearDepth returns the value the depth buffer is cleared to and whether it
+// has been set with SetClearDepth.
+func (s *Scene) ClearDepth() (depth float32, ok bool) {
+	return s.clear.depth, s.clear.depthSet
+}
+
+// SetClearStencil sets the value the stencil buffer is cleared to.
+func (s *Scene) SetClearStencil(stencil int) {
+	s.clear.stencil = stencil
+}
+
+// ClearStencil returns the value the stencil buffer is cleared to.
+func (s *Scene) ClearStencil() int {
+	return s.clear.stencil
+}
+
 func (s *Scene) SetCamera(camera Camera) {
 	if camera == nil {
 		return
@@ -114,36 +226,82 @@ func (s *Scene) SetCamera(camera Camera) {
 	s.setDirty(sceneDirtyCamera)
 }
 
-func sceneUpdate(s Scener, time float64) {
-	s.Update(time)
+// SetFloatingOrigin makes the windows drawing the scene rebase sg with o, every
+// frame after Update, when the camera strays further than o.Threshold from the
+// origin. A nil o stops the rebasing. See FloatingOrigin.
+func (s *Scene) SetFloatingOrigin(o *FloatingOrigin, sg *SceneGraph) {
+	s.floatingOrigin = o
+	s.floatingGraph = sg
+}
+
+// updateFloatingOrigin rebases the scene graph given to SetFloatingOrigin if
+// camera is too far from the origin.
+func (s *Scene) updateFloatingOrigin(camera Camera) {
+	if s.floatingOrigin == nil || s.floatingGraph == nil || camera == nil {
+		return
+	}
+	s.floatingOrigin.Update(s.floatingGraph, camera)
+}
+
+func sceneUpdate(s Scener, dt float64) {
+	s.Update(dt)
 }
 
-func (s *Scene) Update(time float64) {
+func (s *Scene) Update(dt float64) {
 }
 
 func sceneDraw(s Scener, fb Framebuffer) {
 	scene := toScene(s)
 	if scene != nil && scene.isDirty(sceneDirtyCamera) {
+		// The camera may have been created for a different size.
+		scene.camera.UpdateFBSize(fb.Size())
 		fb.SetCamera(scene.camera)
 		scene.clearDirty(sceneDirtyCamera)
 	}
+
+	// Cameras showing only part of the framebuffer, eg. pixel cameras, leave
+	// the rest of it to the background color.
+	if l, ok := fb.GetCamera().(letterboxer); ok {
+		r := l.letterbox()
+		fb.SetScissor(r.X, r.Y, r.Width, r.Height)
+		defer fb.DisableScissor()
+	}
+
 	s.Draw(fb)
 }
 
 func (s *Scene) Draw(fb Framebuffer) {
 }
 
+// sceneResize lets the scene handle a resize then updates the cameras drawing
+// into fb with its new size, so scenes overriding OnResize don't have to.
+func sceneResize(s Scener, fb Framebuffer, width, height int) {
+	s.OnResize(fb, width, height)
+
+	scene := toScene(s)
+	if scene != nil && scene.camera != nil {
+		scene.camera.UpdateFBSize(width, height)
+	}
+	if camera := fb.GetCamera(); camera != nil && (scene == nil || camera != scene.camera) {
+		camera.UpdateFBSize(width, height)
+	}
+
+	if scene != nil {
+		scene.events.emit(Event{Kind: EventResize, Width: width, Height: height})
+	}
+}
+
+// OnResize resizes fb and its viewport. The active camera is updated after
+// OnResize returns.
 func (s *Scene) OnResize(fb Framebuffer, width, height int) {
 	fb.SetSize(width, height)
 	fb.SetViewport(0, 0, width, height)
-
-	s.camera.UpdateFBSize(width, height)
 }
 
-func (s *Scene) OnKeyPressed() {
+func (s *Scene) OnKeyPressed(key Key, scancode int, mods ModifierKey) {
 }
 
-func (s *Scene) OnKeyReleased() {
+func (s *Scene) OnKeyReleased(key Key, scancode int, mods ModifierKey) {
 }
 
 func (s *Scene) OnMouseMoved(x, y float32) {
@@ -158,6 +316,21 @@ func (s *Scene) OnMouseButtonReleased(button MouseButton, x, y float32) {
 func (s *Scene) OnRuneEntered(r rune) {
 }
 
+// defaultFrameMargin is the room left around the scene by FrameAll.
+const defaultFrameMargin = 0.1
+
+// FrameAll moves the scene camera so the whole scene graph sg is in view, see
+// Framer. It does nothing if the camera isn't a Framer or sg doesn't render
+// anything.
+func (s *Scene) FrameAll(sg *SceneGraph) {
+	f, ok := s.camera.(Framer)
+	if !ok {
+		return
+	}
+	bounds := sg.Bounds()
+	f.Frame(&bounds, defaultFrameMargin)
+}
+
 // CreateActor creates a new node that renders a mesh with a material. This
 // function is a convenience function that creates a Node and adds a
 // MeshRenderer component to it.
@@ -0,0 +1,275 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+)
+
+// nodeState is the renderable state of a node, copied by the snapshots of
+// buffered scene graphs.
+type nodeState struct {
+	node *Node
+	// mr is a copy of the mesh renderer of the node, with its property block
+	// replaced by the copy of the snapshot.
+	mr              MeshRenderer
+	world, previous math.Mat4
+	mirrored        bool
+	// bounds are the bounds of the mesh, in the node space.
+	bounds math.AABB
+	// source and instance are the Node and Instance of the DrawContext.
+	source, instance *Node
+}
+
+// cameraState is what drawing a scene graph reads from a camera.
+type cameraState struct {
+	position math.Vec3
+	view     math.Mat4
+	// projection and reversed are the projections of the camera drawing
+	// into framebuffers with a standard or a reversed depth range, see
+	// cameraProjection.
+	projection, reversed math.Mat4
+	reversedZ            bool
+	far                  float32
+}
+
+func (s *cameraState) capture(c Camera) {
+	s.position = cameraPosition(c)
+	s.view = cameraView(c)
+	s.projection = cameraProjection(c, false)
+	s.reversed = cameraProjection(c, true)
+	s.reversedZ = isReversedZ(c)
+	s.far = cameraFar(c)
+}
+
+// transform returns the camera transform, see cameraTransform.
+func (s *cameraState) transform(reversed bool) math.Mat4 {
+	t := s.projection
+	if reversed {
+		t = s.reversed
+	}
+	t.Mul4With(&s.view)
+	return t
+}
+
+// sceneState is a snapshot of the renderable state of a scene graph: the
+// opaque nodes, the lights, the uniform values of the materials and the
+// cameras drawing it.
+type sceneState struct {
+	nodes      []nodeState
+	lights     []lightData
+	materials  map[Material]*uniformValues
+	properties map[*PropertyBlock]*PropertyBlock
+	drawOrder  DrawOrder
+	// cameras are keyed by their node, Camera values may not be comparable.
+	cameras map[*Node]cameraState
+
+	// Copies of the previous snapshot, reused.
+	freeValues     []*uniformValues
+	freeProperties []*PropertyBlock
+}
+
+func (s *sceneState) reset() {
+	if s.materials == nil {
+		s.materials = make(map[Material]*uniformValues)
+		s.properties = make(map[*PropertyBlock]*PropertyBlock)
+		s.cameras = make(map[*Node]cameraState)
+	}
+
+	for i := range s.nodes {
+		s.nodes[i] = nodeState{}
+	}
+	s.nodes = s.nodes[:0]
+	for m, values := range s.materials {
+		s.freeValues = append(s.freeValues, values)
+		delete(s.materials, m)
+	}
+	for pb, block := range s.properties {
+		s.freeProperties = append(s.freeProperties, block)
+		delete(s.properties, pb)
+	}
+	for n := range s.cameras {
+		delete(s.cameras, n)
+	}
+}
+
+// copyMaterial copies the uniform values of m, once per snapshot.
+func (s *sceneState) copyMaterial(m Material) {
+	values := materialUniforms(m)
+	if values == nil || !isComparable(m) {
+		return
+	}
+	if _, ok := s.materials[m]; ok {
+		return
+	}
+
+	var dup *uniformValues
+	if n := len(s.freeValues); n > 0 {
+		dup = s.freeValues[n-1]
+		s.freeValues = s.freeValues[:n-1]
+	} else {
+		dup = &uniformValues{}
+	}
+	dup.copyFrom(values)
+	s.materials[m] = dup
+}
+
+// copyProperties returns the copy of pb, made once per snapshot so nodes
+// sharing a property block still share the copy.
+func (s *sceneState) copyProperties(pb *PropertyBlock) *PropertyBlock {
+	if pb == nil {
+		return nil
+	}
+	if dup, ok := s.properties[pb]; ok {
+		return dup
+	}
+
+	var dup *PropertyBlock
+	if n := len(s.freeProperties); n > 0 {
+		dup = s.freeProperties[n-1]
+		s.freeProperties = s.freeProperties[:n-1]
+	} else {
+		dup = &PropertyBlock{}
+	}
+	dup.values.copyFrom(&pb.values)
+	s.properties[pb] = dup
+	return dup
+}
+
+// capture snapshots sg, whose world transforms are up to date, and the
+// cameras drawing it. The current world transforms become the previous ones of
+// the next snapshot.
+func (s *sceneState) capture(sg *SceneGraph, cameras []Camera) {
+	s.reset()
+	s.lights = appendLights(s.lights, sg)
+	s.drawOrder = sg.drawOrder
+
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok {
+			continue
+		}
+
+		// Blended materials aren't drawn by the opaque pass.
+		mr := getMeshRenderer(node)
+		if mr == nil || mr.material.GetBlending().Enabled {
+			continue
+		}
+
+		s.nodes = append(s.nodes, nodeState{
+			node:     node,
+			mr:       *mr,
+			world:    *node.worldTransform.AsMat4(),
+			previous: *node.PreviousWorldTransform(),
+			mirrored: node.isMirrored(),
+			bounds:   mr.Bounds(),
+			source:   node.source(),
+		})
+		state := &s.nodes[len(s.nodes)-1]
+		if node.isInstanced() {
+			state.instance = node.outerInstance()
+		}
+		state.mr.properties = s.copyProperties(mr.properties)
+		s.copyMaterial(mr.material)
+
+		node.savePreviousWorldTransform()
+	}
+
+	// Cameras fitting their near and far planes do it to the snapshot.
+	for _, c := range cameras {
+		fitNearFar(c, sg, s)
+		camera := cameraState{}
+		camera.capture(c)
+		s.cameras[c.AsNode()] = camera
+	}
+}
+
+// SetBuffered makes the scene graph buffered: Update ends with a snapshot of
+// the renderable state, the world transforms and bounds of the opaque nodes,
+// the lights, the uniform values of the materials and property blocks, the
+// draw order and the cameras drawing the scene graph, and draws only read the
+// last snapshot. The next Update can then run concurrently with the
+// drawing of the previous frame, eg. in its own goroutine:
+//
+//	sg.SetBuffered(true)
+//	sg.Update(0)
+//
+//	go func() {
+//		for dt := range ticks {
+//			mutations.Flush()
+//			sg.Update(dt)
+//		}
+//	}()
+//
+// Nothing is drawn until the first Update, and a camera drawing the scene
+// graph for the first time only draws once an Update captured it. Cameras are
+// then owned by the update goroutine. Meshes and the blending and depth
+// settings of materials aren't part of the snapshot and must not change while
+// drawing. OnDraw functions run when drawing, and changes made from the main
+// goroutine, eg. by input handlers, should go through a MutationQueue flushed
+// by the update goroutine. Previous world transforms are the ones of the
+// previous snapshot.
+//
+// Snapshots are triple buffered: Update never waits for a draw to finish.
+func (sg *SceneGraph) SetBuffered(buffered bool) {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	sg.buffered = buffered
+	sg.front = nil
+	sg.cameras = nil
+}
+
+// Buffered returns true if the scene graph is buffered, see SetBuffered.
+func (sg *SceneGraph) Buffered() bool {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	return sg.buffered
+}
+
+// snapshot captures the renderable state in a buffer that is neither the
+// last snapshot nor the one being drawn, then makes it the last snapshot.
+func (sg *SceneGraph) snapshot() {
+	sg.mu.Lock()
+	var s *sceneState
+	for i := range sg.states {
+		if &sg.states[i] != sg.front && &sg.states[i] != sg.drawing {
+			s = &sg.states[i]
+			break
+		}
+	}
+	// Draws only append cameras, past the end of this slice.
+	cameras := sg.cameras[:len(sg.cameras):len(sg.cameras)]
+	sg.mu.Unlock()
+
+	s.capture(sg, cameras)
+
+	sg.mu.Lock()
+	sg.front = s
+	sg.mu.Unlock()
+}
+
+// beginDraw returns the last snapshot, nil if there's none yet, and keeps it
+// until endDraw. c, when not nil, is the camera drawing: it's captured by the
+// next snapshots.
+func (sg *SceneGraph) beginDraw(c Camera) *sceneState {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if c != nil && !sg.hasCamera(c) {
+		sg.cameras = append(sg.cameras, c)
+	}
+	sg.drawing = sg.front
+	return sg.drawing
+}
+
+func (sg *SceneGraph) hasCamera(c Camera) bool {
+	for _, camera := range sg.cameras {
+		if camera.AsNode() == c.AsNode() {
+			return true
+		}
+	}
+	return false
+}
+
+func (sg *SceneGraph) endDraw() {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	sg.drawing = nil
+}
@@ -0,0 +1,26 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneClear(t *testing.T) {
+	s := new(Scene)
+	assert.Equal(t, ClearDefault, s.ClearFlags())
+	_, ok := s.ClearDepth()
+	assert.False(t, ok)
+
+	s.SetClear(ClearNone)
+	assert.Equal(t, ClearNone, s.ClearFlags())
+
+	s.SetClear(ClearDepth | ClearStencil)
+	s.SetClearDepth(.5)
+	s.SetClearStencil(3)
+	assert.Equal(t, ClearDepth|ClearStencil, s.ClearFlags())
+	depth, ok := s.ClearDepth()
+	assert.True(t, ok)
+	assert.Equal(t, float32(.5), depth)
+	assert.Equal(t, 3, s.ClearStencil())
+}
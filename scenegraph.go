@@ -1,5 +1,11 @@
 package dax
 
+import (
+	"sync"
+
+	"github.com/dlespiau/dax/math"
+)
+
 // Grapher is an interface for objects that can be put into a graph.
 type Grapher interface {
 	GetParent() Grapher
@@ -33,6 +39,19 @@ func (s *nodeStack) Pop() Grapher {
 
 type SceneGraph struct {
 	Node
+	events    EventDispatcher
+	drawOrder DrawOrder
+
+	// Snapshots of buffered scene graphs, see SetBuffered. front is the last
+	// snapshot and drawing the one being drawn. cameras are the cameras
+	// drawing the scene graph, captured by the snapshots. mu guards
+	// buffered, front, drawing and cameras.
+	mu       sync.Mutex
+	buffered bool
+	states   [3]sceneState
+	front    *sceneState
+	drawing  *sceneState
+	cameras  []Camera
 }
 
 func NewSceneGraph() *SceneGraph {
@@ -43,17 +62,129 @@ func NewSceneGraph() *SceneGraph {
 
 func (sg *SceneGraph) Init() {
 	sg.Node.Init()
+	sg.Node.graph = sg
+}
+
+// SetDrawOrder sets the order the opaque nodes are drawn in. It defaults to
+// DrawOrderFrontToBack.
+func (sg *SceneGraph) SetDrawOrder(order DrawOrder) {
+	sg.drawOrder = order
+}
+
+// DrawOrder returns the order the opaque nodes are drawn in.
+func (sg *SceneGraph) DrawOrder() DrawOrder {
+	return sg.drawOrder
+}
+
+// Events returns the event dispatcher of the scene graph, sending the node
+// events.
+func (sg *SceneGraph) Events() *EventDispatcher {
+	return &sg.events
+}
+
+// On registers f to be called when an event of the given kind is sent by the
+// scene graph, eg. when a node is added to it. See EventDispatcher.On.
+func (sg *SceneGraph) On(kind EventKind, f func(e Event)) HookID {
+	return sg.events.On(kind, f)
+}
+
+// Off unregisters a function registered with On.
+func (sg *SceneGraph) Off(id HookID) {
+	sg.events.Off(id)
 }
 
 func (sg *SceneGraph) updateWorldTransform() {
 	sg.Node.updateWorldTransform(false)
 }
 
-func (sg *SceneGraph) Update(time float64) {
+func (n *Node) updateComponents(dt float64) {
+	for _, c := range n.components {
+		if u, ok := c.(Updater); ok {
+			u.Update(dt)
+		}
+	}
+}
+
+// Update updates the node components implementing Updater, eg. animation
+// players, then the world transforms of the nodes. Components of instanced
+// subtrees are updated once, however many instances draw them. Buffered scene
+// graphs then snapshot their renderable state, see SetBuffered.
+func (sg *SceneGraph) Update(dt float64) {
+	// Prototypes outside of the scene graph, updated after the nodes of the
+	// scene graph.
+	var detached []*Node
+	seen := make(map[*Node]bool)
+
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok || node.isInstanced() {
+			continue
+		}
+		node.updateComponents(dt)
+		if node.prototype != nil {
+			detached = appendDetached(detached, seen, node.prototype, &sg.Node)
+		}
+	}
+
+	for i := 0; i < len(detached); i++ {
+		stack := []*Node{detached[i]}
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			node.updateComponents(dt)
+			if node.prototype != nil {
+				detached = appendDetached(detached, seen, node.prototype, &sg.Node)
+			}
+			for _, child := range node.children {
+				stack = append(stack, child.(*Node))
+			}
+		}
+	}
+
 	sg.updateWorldTransform()
+
+	if sg.Buffered() {
+		sg.snapshot()
+	}
 }
 
-// Depth-first pre-order traversal of the SceneGraph
+// appendDetached appends prototype to prototypes if it isn't part of the tree
+// rooted at root. seen holds the prototypes already looked at.
+func appendDetached(prototypes []*Node, seen map[*Node]bool, prototype, root *Node) []*Node {
+	if seen[prototype] {
+		return prototypes
+	}
+	seen[prototype] = true
+
+	a := prototype
+	for a.parentNode() != nil {
+		a = a.parentNode()
+	}
+	if a == root {
+		return prototypes
+	}
+	return append(prototypes, prototype)
+}
+
+// Bounds returns the world space bounding box of the meshes of the scene
+// graph. World transforms are updated first.
+func (sg *SceneGraph) Bounds() math.AABB {
+	sg.updateWorldTransform()
+
+	bounds := math.EmptyAABB()
+	for g := range sg.Traverse() {
+		node, ok := g.(*Node)
+		if !ok {
+			continue
+		}
+		nodeBounds := node.WorldBounds()
+		bounds = bounds.Merge(&nodeBounds)
+	}
+	return bounds
+}
+
+// Depth-first pre-order traversal of the SceneGraph. The mirrors of instanced
+// subtrees, see NewInstance, are traversed after the children of instances.
 func (sg *SceneGraph) Traverse() <-chan Grapher {
 	ch := make(chan Grapher)
 
@@ -66,6 +197,9 @@ func (sg *SceneGraph) Traverse() <-chan Grapher {
 		for !stack.Empty() {
 			n := stack.Pop()
 			ch <- n
+			if node, ok := n.(*Node); ok && node.instanceRoot != nil {
+				stack.Push(node.instanceRoot)
+			}
 			children := n.GetChildren()
 			for i := len(children) - 1; i >= 0; i-- {
 				stack.Push(children[i])
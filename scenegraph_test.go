@@ -3,6 +3,7 @@ package dax
 import (
 	"testing"
 
+	"github.com/dlespiau/dax/math"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,3 +29,126 @@ func TestTraverse(t *testing.T) {
 	}
 	assert.Equal(t, len(preOrder), idx)
 }
+
+func TestSceneGraphBuffered(t *testing.T) {
+	sg := NewSceneGraph()
+	sg.SetBuffered(true)
+
+	material := &dummyOpaqueMaterial{}
+	material.SetUniform("a", float32(1))
+	properties := NewPropertyBlock()
+	properties.Set("b", float32(2))
+	mr := NewMeshRenderer(&dummerMesher{}, material)
+	mr.SetPropertyBlock(properties)
+	node := NewNode().AddComponent(mr)
+	sg.AddChild(node)
+
+	// Nothing to draw before the first update.
+	assert.Nil(t, sg.beginDraw(nil))
+	sg.endDraw()
+
+	node.SetPosition(1, 0, 0)
+	sg.Update(0)
+	node.SetPosition(2, 0, 0)
+	material.SetUniform("a", float32(3))
+	properties.Set("b", float32(4))
+
+	// Draws see the state at the end of the update.
+	s := sg.beginDraw(nil)
+	assert.Equal(t, 1, len(s.nodes))
+	state := &s.nodes[0]
+	assert.Equal(t, node, state.node)
+	assert.Equal(t, node, state.source)
+	assertFloat(t, 1, state.world[12], 1e-6)
+	assertFloat(t, 1, state.previous[12], 1e-6)
+	assert.Equal(t, float32(1), s.materials[material].get("a"))
+	assert.Equal(t, &material.uniforms, s.materials[material].source)
+	assert.Equal(t, float32(2), state.mr.properties.Get("b"))
+
+	// Updates don't touch the snapshot being drawn, and previous world
+	// transforms are the ones of the previous snapshot.
+	sg.Update(0)
+	assertFloat(t, 1, state.world[12], 1e-6)
+	sg.endDraw()
+
+	s = sg.beginDraw(nil)
+	assertFloat(t, 2, s.nodes[0].world[12], 1e-6)
+	assertFloat(t, 1, s.nodes[0].previous[12], 1e-6)
+	assert.Equal(t, float32(3), s.materials[material].get("a"))
+	assert.Equal(t, float32(4), s.nodes[0].mr.properties.Get("b"))
+	sg.endDraw()
+}
+
+func TestSceneGraphBufferRotation(t *testing.T) {
+	sg := NewSceneGraph()
+	sg.SetBuffered(true)
+	sg.AddChild(createDummyNode())
+
+	sg.Update(0)
+	drawing := sg.beginDraw(nil)
+	for i := 0; i < 5; i++ {
+		sg.Update(0)
+		assert.True(t, sg.front != drawing)
+	}
+	sg.endDraw()
+
+	// The next draw picks the last snapshot.
+	front := sg.front
+	assert.Equal(t, front, sg.beginDraw(nil))
+	sg.endDraw()
+}
+
+// TestSceneGraphBufferedConcurrentDraw draws while updating from another
+// goroutine, moving the camera, and is meant to be run with -race.
+func TestSceneGraphBufferedConcurrentDraw(t *testing.T) {
+	sg := NewSceneGraph()
+	sg.SetBuffered(true)
+	node := NewNode().AddComponent(NewMeshRenderer(&cubeMesh{}, &dummyOpaqueMaterial{}))
+	sg.AddChild(node)
+
+	camera := NewPerspectiveCamera(math.Pi/2, 1, .1, 100)
+	camera.SetAutoNearFar(true)
+	camera.SetPosition(0, 0, 10)
+	r := &renderer{batchIndex: make(map[batchKey]int)}
+
+	// Cameras are captured by the updates following their first draw.
+	sg.Update(0)
+	_, state, ok := r.prepareSceneGraph(sg, camera, false)
+	assert.NotNil(t, state)
+	assert.False(t, ok)
+	sg.endDraw()
+
+	const updates = 100
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < updates; i++ {
+			camera.SetPosition(0, 0, 10+float32(i))
+			node.SetPosition(float32(i)/updates, 0, 0)
+			sg.SetDrawOrder(DrawOrder(i % 2))
+			sg.Update(1. / 60)
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		_, state, ok := r.prepareSceneGraph(sg, camera, false)
+		if state != nil {
+			sg.endDraw()
+		}
+		if ok {
+			assert.Equal(t, 1, len(r.drawList))
+		}
+	}
+
+	// Draws see the camera of the last update.
+	s, _, ok := r.prepareSceneGraph(sg, camera, false)
+	sg.endDraw()
+	assert.True(t, ok)
+	assertFloat(t, 10+updates-1, s.position[2], 1e-6)
+	assertFloat(t, -(10 + updates - 1), s.view[14], 1e-4)
+}
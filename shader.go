@@ -1,6 +1,14 @@
 package dax
 
-import "github.com/dlespiau/dax/math"
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dlespiau/dax/math"
+)
 
 // VariableKind defines the type of a variable in a shader.
 type VariableKind int
@@ -16,6 +24,12 @@ const (
 	VariableKindVec4
 	// VariableKindMat4 is a 4x4 matrix uniform.
 	VariableKindMat4
+	// VariableKindInt is an int uniform.
+	VariableKindInt
+	// VariableKindMat3 is a 3x3 matrix uniform.
+	VariableKindMat3
+	// VariableKindTexture is a sampler2D uniform.
+	VariableKindTexture
 	variableKindMax
 )
 
@@ -52,6 +66,7 @@ type Uniform interface {
 
 var builtinUniforms = [...]string{
 	"mvp",
+	"previousMvp",
 }
 
 type builtinUniform struct {
@@ -136,6 +151,45 @@ func (u *mat4Uniform) Set(v interface{}) {
 	u.val = v.(math.Mat4)
 }
 
+type intUniform struct {
+	baseVariable
+	val int
+}
+
+func (u *intUniform) Get() interface{} {
+	return u.val
+}
+
+func (u *intUniform) Set(v interface{}) {
+	u.val = v.(int)
+}
+
+type mat3Uniform struct {
+	baseVariable
+	val math.Mat3
+}
+
+func (u *mat3Uniform) Get() interface{} {
+	return u.val
+}
+
+func (u *mat3Uniform) Set(v interface{}) {
+	u.val = v.(math.Mat3)
+}
+
+type textureUniform struct {
+	baseVariable
+	val *Texture
+}
+
+func (u *textureUniform) Get() interface{} {
+	return u.val
+}
+
+func (u *textureUniform) Set(v interface{}) {
+	u.val = v.(*Texture)
+}
+
 func createUniform(kind VariableKind, name string) Uniform {
 	var u Uniform
 
@@ -187,6 +241,27 @@ func createUniform(kind VariableKind, name string) Uniform {
 				name: name,
 			},
 		}
+	case VariableKindInt:
+		u = &intUniform{
+			baseVariable: baseVariable{
+				kind: VariableKindInt,
+				name: name,
+			},
+		}
+	case VariableKindMat3:
+		u = &mat3Uniform{
+			baseVariable: baseVariable{
+				kind: VariableKindMat3,
+				name: name,
+			},
+		}
+	case VariableKindTexture:
+		u = &textureUniform{
+			baseVariable: baseVariable{
+				kind: VariableKindTexture,
+				name: name,
+			},
+		}
 	}
 
 	return u
@@ -195,6 +270,43 @@ func createUniform(kind VariableKind, name string) Uniform {
 type baseShader struct {
 	source   string
 	uniforms []Uniform
+
+	// Shaders created from a file remember where they come from to be
+	// reloaded when the file changes.
+	filename string
+	modTime  time.Time
+}
+
+// reload reads the shader source again if its file has been modified since it
+// was last read. It returns the new source and true if it differs from the
+// current one. The shader source itself isn't changed: the new source may not
+// compile, see renderer.reloadShaders. A missing file isn't an error: editors
+// may replace files by deleting them first.
+func (s *baseShader) reload() (string, bool, error) {
+	if s.filename == "" {
+		return "", false, nil
+	}
+
+	info, err := os.Stat(s.filename)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return "", false, nil
+	}
+
+	source, err := ioutil.ReadFile(s.filename)
+	if err != nil {
+		return "", false, err
+	}
+	s.modTime = info.ModTime()
+	if string(source) == s.source {
+		return "", false, nil
+	}
+	return string(source), true, nil
 }
 
 // Uniform returns the uniform named name.
@@ -241,6 +353,16 @@ func (vs *VertexShader) AddAttribute(kind VariableKind, name string) *Attribute
 	return &a
 }
 
+// hasAttribute returns true if the vertex shader has an attribute named name.
+func (vs *VertexShader) hasAttribute(name string) bool {
+	for i := range vs.attributes {
+		if vs.attributes[i].name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // FragmentShader is a program that runs for each fragment.
 type FragmentShader struct {
 	baseShader
@@ -255,3 +377,56 @@ func NewFragmentShader(source string) *FragmentShader {
 		},
 	}
 }
+
+// NewFragmentShaderFromFile creates a fragment shader with the source found in
+// filename. When shader hot reloading is enabled, see
+// Window.SetShaderHotReload, the shader is rebuilt each time the file changes.
+// There's no vertex shader counterpart: materials only provide a fragment
+// shader, the vertex shaders are the renderer's.
+func NewFragmentShaderFromFile(filename string) (*FragmentShader, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	source, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FragmentShader{
+		baseShader: baseShader{
+			source:   string(source),
+			filename: filename,
+			modTime:  info.ModTime(),
+		},
+	}, nil
+}
+
+// NewFragmentShaderVariant creates a fragment shader from source with the
+// preprocessor macros defines defined. Several materials can then share one
+// source, each selecting its features with #ifdef blocks. Variants need
+// distinct material IDs as programs are cached by ID.
+func NewFragmentShaderVariant(source string, defines ...string) *FragmentShader {
+	for _, name := range defines {
+		source = shaderDefine(source, name)
+	}
+	return NewFragmentShader(source)
+}
+
+// shaderDefine defines the preprocessor macro name in source, right after the
+// #version directive. A #line directive keeps line numbers in compilation
+// errors matching the original source.
+func shaderDefine(source, name string) string {
+	lines := strings.SplitAfter(source, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#version") {
+			continue
+		}
+		if !strings.HasSuffix(line, "\n") {
+			lines[i] += "\n"
+		}
+		define := fmt.Sprintf("#define %s\n#line %d\n", name, i+2)
+		return strings.Join(lines[:i+1], "") + define + strings.Join(lines[i+1:], "")
+	}
+	return fmt.Sprintf("#define %s\n#line 1\n", name) + source
+}
@@ -0,0 +1,146 @@
+package dax
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ShaderStage identifies the step of the shader pipeline that failed.
+type ShaderStage int
+
+const (
+	// ShaderStageVertex is the compilation of a vertex shader.
+	ShaderStageVertex ShaderStage = iota
+	// ShaderStageFragment is the compilation of a fragment shader.
+	ShaderStageFragment
+	// ShaderStageLink is the link of a program.
+	ShaderStageLink
+)
+
+func (s ShaderStage) String() string {
+	switch s {
+	case ShaderStageVertex:
+		return "vertex shader"
+	case ShaderStageFragment:
+		return "fragment shader"
+	case ShaderStageLink:
+		return "program"
+	}
+	return "unknown stage"
+}
+
+// ShaderLogEntry is a single message from the driver's info log.
+type ShaderLogEntry struct {
+	// Line is the source line the message refers to, starting at 1. It's 0
+	// when the driver didn't give a line number.
+	Line    int
+	Message string
+}
+
+// ShaderError is the error returned when a shader fails to compile or a
+// program fails to link. Its message includes the offending lines of source.
+type ShaderError struct {
+	Stage ShaderStage
+	// Log is the raw info log from the driver.
+	Log     string
+	Entries []ShaderLogEntry
+	source  string
+}
+
+// Number of source lines shown before and after the line of an error.
+const shaderErrorContext = 2
+
+// Drivers don't agree on a log format. Recognize the common ones:
+//
+//	0:12(5): error: ...       Mesa
+//	0(12) : error C0000: ...  NVIDIA
+//	ERROR: 0:12: ...          AMD, Apple and the reference compiler
+var shaderLogLineRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`^\d+:(\d+)\(\d+\): (.*)$`),
+	regexp.MustCompile(`^\d+\((\d+)\) ?: (.*)$`),
+	regexp.MustCompile(`^(?:ERROR|WARNING): \d+:(\d+): (.*)$`),
+}
+
+func parseShaderLog(log string) []ShaderLogEntry {
+	var entries []ShaderLogEntry
+
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(strings.TrimRight(line, "\x00"))
+		if line == "" {
+			continue
+		}
+
+		entry := ShaderLogEntry{Message: line}
+		for _, re := range shaderLogLineRegexps {
+			m := re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			entry.Line, _ = strconv.Atoi(m[1])
+			entry.Message = m[2]
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func newShaderError(stage ShaderStage, source, log string) *ShaderError {
+	return &ShaderError{
+		Stage:   stage,
+		Log:     strings.TrimRight(log, "\x00"),
+		Entries: parseShaderLog(log),
+		source:  source,
+	}
+}
+
+func (e *ShaderError) Error() string {
+	var buf bytes.Buffer
+
+	if e.Stage == ShaderStageLink {
+		buf.WriteString("failed to link program")
+	} else {
+		fmt.Fprintf(&buf, "failed to compile %s", e.Stage)
+	}
+
+	if len(e.Entries) == 0 {
+		return buf.String()
+	}
+
+	lines := strings.Split(e.source, "\n")
+	width := len(strconv.Itoa(len(lines)))
+
+	for _, entry := range e.Entries {
+		buf.WriteString("\n")
+		if entry.Line > 0 {
+			fmt.Fprintf(&buf, "%d: ", entry.Line)
+		}
+		buf.WriteString(entry.Message)
+
+		if entry.Line < 1 || entry.Line > len(lines) {
+			continue
+		}
+
+		first := entry.Line - shaderErrorContext
+		if first < 1 {
+			first = 1
+		}
+		last := entry.Line + shaderErrorContext
+		if last > len(lines) {
+			last = len(lines)
+		}
+		for i := first; i <= last; i++ {
+			marker := " "
+			if i == entry.Line {
+				marker = ">"
+			}
+			fmt.Fprintf(&buf, "\n %s %*d | %s", marker, width, i, lines[i-1])
+		}
+	}
+
+	return buf.String()
+}
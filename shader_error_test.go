@@ -0,0 +1,52 @@
+package dax
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShaderLog(t *testing.T) {
+	tests := []struct {
+		log     string
+		entries []ShaderLogEntry
+	}{
+		{"0:5(19): error: `colr' undeclared\n", []ShaderLogEntry{
+			{5, "error: `colr' undeclared"},
+		}},
+		{"0(5) : error C1008: undefined variable \"colr\"\n", []ShaderLogEntry{
+			{5, "error C1008: undefined variable \"colr\""},
+		}},
+		{"ERROR: 0:5: 'colr' : undeclared identifier\nERROR: 1 compilation errors.\x00", []ShaderLogEntry{
+			{5, "'colr' : undeclared identifier"},
+			{0, "ERROR: 1 compilation errors."},
+		}},
+		{"error: vertex shader output `foo' not written\n", []ShaderLogEntry{
+			{0, "error: vertex shader output `foo' not written"},
+		}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.entries, parseShaderLog(test.log))
+	}
+}
+
+func TestShaderError(t *testing.T) {
+	err := newShaderError(ShaderStageFragment, testFragmentShaderSource,
+		"0:5(19): error: `colr' undeclared\n\x00")
+
+	assert.Equal(t, ShaderStageFragment, err.Stage)
+	assert.Equal(t, "0:5(19): error: `colr' undeclared\n", err.Log)
+	assert.Equal(t, strings.Join([]string{
+		"failed to compile fragment shader",
+		"5: error: `colr' undeclared",
+		"   3 | out vec4 outputColor;",
+		"   4 | void main() {",
+		" > 5 |     outputColor = vec4(.8, .8, .8, 1);",
+		"   6 | }",
+	}, "\n"), err.Error())
+
+	err = newShaderError(ShaderStageLink, "", "error: no main\n")
+	assert.Equal(t, "failed to link program\nerror: no main", err.Error())
+}
@@ -2,7 +2,11 @@ package dax
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,3 +33,76 @@ func TestFragmentShaderCreation(t *testing.T) {
 		assert.Equal(t, name, s.uniforms[i].Name())
 	}
 }
+
+func TestShaderDefine(t *testing.T) {
+	tests := []struct {
+		source, expected string
+	}{
+		{
+			"\n#version 330\nvoid main() {}",
+			"\n#version 330\n#define FOO\n#line 3\nvoid main() {}",
+		}, {
+			"#version 330 core",
+			"#version 330 core\n#define FOO\n#line 2\n",
+		}, {
+			"void main() {}",
+			"#define FOO\n#line 1\nvoid main() {}",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, shaderDefine(test.source, "FOO"))
+	}
+}
+
+func TestFragmentShaderVariant(t *testing.T) {
+	fs := NewFragmentShaderVariant("#version 330\nvoid main() {}", "FOO", "BAR")
+	assert.Equal(t, "#version 330\n#define BAR\n#line 2\n#define FOO\n#line 2\nvoid main() {}", fs.source)
+}
+
+func TestFragmentShaderReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dax-shader")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "test.frag")
+	write := func(source string, modTime time.Time) {
+		assert.Nil(t, ioutil.WriteFile(filename, []byte(source), 0644))
+		assert.Nil(t, os.Chtimes(filename, modTime, modTime))
+	}
+	now := time.Now()
+
+	_, err = NewFragmentShaderFromFile(filepath.Join(dir, "missing.frag"))
+	assert.NotNil(t, err)
+
+	write("v1", now)
+	fs, err := NewFragmentShaderFromFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", fs.source)
+
+	_, changed, err := fs.reload()
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	// The new source is only swapped in by the renderer, once it compiles.
+	write("v2", now.Add(time.Second))
+	source, changed, err := fs.reload()
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "v2", source)
+	assert.Equal(t, "v1", fs.source)
+	fs.source = source
+
+	// Touching the file doesn't change the source.
+	write("v2", now.Add(2*time.Second))
+	_, changed, err = fs.reload()
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	// The file may briefly disappear while being saved.
+	assert.Nil(t, os.Remove(filename))
+	_, changed, err = fs.reload()
+	assert.Nil(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "v2", fs.source)
+}
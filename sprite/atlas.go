@@ -0,0 +1,238 @@
+package sprite
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	// Atlas images are usually PNG files.
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// TextureRegion is a named part of a texture, eg. a frame of a TextureAtlas.
+type TextureRegion struct {
+	Name    string
+	Texture *dax.Texture
+	// Rect is the region in texels, with its origin at the top left corner
+	// of the texture.
+	Rect Rect
+}
+
+// UVRect returns the texture coordinates of the top left and bottom right
+// corners of the region.
+func (r *TextureRegion) UVRect() (topLeft, bottomRight math.Vec2) {
+	so := r.ScaleOffset()
+	return math.Vec2{so[2], so[3]}, math.Vec2{so[2] + so[0], so[3] + so[1]}
+}
+
+// ScaleOffset returns the scale, in x and y, and the offset, in z and w,
+// mapping texture coordinates covering a whole texture to the region:
+// uv * scale + offset. It's meant to be given to materials sampling a region of
+// an atlas:
+//
+//	texCoord = uv * region.xy + region.zw;
+func (r *TextureRegion) ScaleOffset() math.Vec4 {
+	width, height := r.Texture.Size()
+	return scaleOffset(r.Rect, width, height)
+}
+
+// scaleOffset returns the scale and offset mapping texture coordinates to the
+// region r of a width x height texture, see TextureRegion.ScaleOffset.
+func scaleOffset(r Rect, width, height int) math.Vec4 {
+	w, h := float32(width), float32(height)
+	return math.Vec4{r.Width / w, r.Height / h, r.X / w, r.Y / h}
+}
+
+// remap applies the scale and offset so to uv.
+func remap(so *math.Vec4, uv math.Vec2) math.Vec2 {
+	return math.Vec2{uv[0]*so[0] + so[2], uv[1]*so[1] + so[3]}
+}
+
+// Remap maps uv, texture coordinates covering a whole texture, to the region.
+func (r *TextureRegion) Remap(uv math.Vec2) math.Vec2 {
+	so := r.ScaleOffset()
+	return remap(&so, uv)
+}
+
+// RemapMesh remaps the uv attribute of mesh, covering a whole texture, to the
+// region. Meshes without texture coordinates are left untouched.
+func (r *TextureRegion) RemapMesh(mesh *dax.Mesh) {
+	uvs := mesh.GetAttribute("uv")
+	if uvs == nil {
+		return
+	}
+	so := r.ScaleOffset()
+	for i := 0; i < uvs.Len(); i++ {
+		u, v := uvs.GetXY(i)
+		uv := remap(&so, math.Vec2{u, v})
+		uvs.SetXY(i, uv[0], uv[1])
+	}
+}
+
+// NewSpriteFromRegion creates a sprite showing the region r.
+func NewSpriteFromRegion(r *TextureRegion) *Sprite {
+	s := NewSprite(r.Texture)
+	s.Region = r.Rect
+	return s
+}
+
+// SetRegion makes the sprite show the region r, eg. to change the frame of an
+// animated sprite.
+func (s *Sprite) SetRegion(r *TextureRegion) {
+	s.Texture = r.Texture
+	s.Region = r.Rect
+}
+
+// TextureAtlas is a texture packing many images, the regions of the atlas,
+// looked up by name. Atlases are described by the JSON files of packing tools
+// such as TexturePacker, in their "hash" or "array" flavors:
+//
+//	{
+//		"frames": {
+//			"hero-idle.png": {"frame": {"x": 0, "y": 0, "w": 32, "h": 32}},
+//			"hero-run.png": {"frame": {"x": 32, "y": 0, "w": 32, "h": 32}}
+//		},
+//		"meta": {"image": "hero.png"}
+//	}
+//
+// Rotated regions aren't supported.
+type TextureAtlas struct {
+	texture *dax.Texture
+	regions map[string]*TextureRegion
+}
+
+// LoadTextureAtlas loads the atlas described by the JSON file at path. The
+// atlas image is the file named by meta.image, relative to the JSON file.
+func LoadTextureAtlas(path string) (*TextureAtlas, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	atlas, err := parseAtlas(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if atlas.Meta.Image == "" {
+		return nil, fmt.Errorf("%s: no atlas image", path)
+	}
+
+	imagePath := filepath.Join(filepath.Dir(path), atlas.Meta.Image)
+	imageFile, err := os.Open(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer imageFile.Close()
+
+	img, _, err := image.Decode(imageFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", imagePath, err)
+	}
+
+	return newTextureAtlas(dax.NewTextureFromImage(img), atlas), nil
+}
+
+// NewTextureAtlas creates an atlas of texture, with the regions described by
+// the JSON read from r. The atlas image named in the JSON is ignored.
+func NewTextureAtlas(texture *dax.Texture, r io.Reader) (*TextureAtlas, error) {
+	atlas, err := parseAtlas(r)
+	if err != nil {
+		return nil, err
+	}
+	return newTextureAtlas(texture, atlas), nil
+}
+
+func newTextureAtlas(texture *dax.Texture, atlas *atlasFile) *TextureAtlas {
+	a := &TextureAtlas{
+		texture: texture,
+		regions: make(map[string]*TextureRegion),
+	}
+	for _, f := range atlas.frames {
+		a.regions[f.Filename] = &TextureRegion{
+			Name:    f.Filename,
+			Texture: texture,
+			Rect: Rect{
+				X:      f.Frame.X,
+				Y:      f.Frame.Y,
+				Width:  f.Frame.W,
+				Height: f.Frame.H,
+			},
+		}
+	}
+	return a
+}
+
+// Texture returns the atlas texture.
+func (a *TextureAtlas) Texture() *dax.Texture {
+	return a.texture
+}
+
+// Region returns the region called name, nil if the atlas doesn't have it.
+func (a *TextureAtlas) Region(name string) *TextureRegion {
+	return a.regions[name]
+}
+
+// Names returns the names of the regions, sorted.
+func (a *TextureAtlas) Names() []string {
+	names := make([]string, 0, len(a.regions))
+	for name := range a.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// atlasFrame is a region of an atlas JSON file.
+type atlasFrame struct {
+	Filename string `json:"filename"`
+	Frame    struct {
+		X, Y, W, H float32
+	} `json:"frame"`
+	Rotated bool `json:"rotated"`
+}
+
+// atlasFile is an atlas JSON file. Frames are either an object, keyed by the
+// frame names, or an array of frames with a filename.
+type atlasFile struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image string `json:"image"`
+	} `json:"meta"`
+
+	frames []atlasFrame
+}
+
+func parseAtlas(r io.Reader) (*atlasFile, error) {
+	atlas := &atlasFile{}
+	if err := json.NewDecoder(r).Decode(atlas); err != nil {
+		return nil, err
+	}
+
+	var hash map[string]atlasFrame
+	if err := json.Unmarshal(atlas.Frames, &hash); err == nil {
+		for name, f := range hash {
+			f.Filename = name
+			atlas.frames = append(atlas.frames, f)
+		}
+	} else if err := json.Unmarshal(atlas.Frames, &atlas.frames); err != nil {
+		return nil, fmt.Errorf("invalid frames: %v", err)
+	}
+
+	for _, f := range atlas.frames {
+		if f.Filename == "" {
+			return nil, fmt.Errorf("frame without a name")
+		}
+		if f.Rotated {
+			return nil, fmt.Errorf("%s: rotated frames aren't supported", f.Filename)
+		}
+	}
+
+	return atlas, nil
+}
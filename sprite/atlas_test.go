@@ -0,0 +1,64 @@
+package sprite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextureAtlas(t *testing.T) {
+	texture := &dax.Texture{}
+	hash := `{
+		"frames": {
+			"run-1.png": {"frame": {"x": 32, "y": 0, "w": 32, "h": 16}},
+			"idle.png": {"frame": {"x": 0, "y": 0, "w": 32, "h": 16}, "rotated": false}
+		},
+		"meta": {"image": "hero.png", "size": {"w": 128, "h": 64}}
+	}`
+	array := `{
+		"frames": [
+			{"filename": "run-1.png", "frame": {"x": 32, "y": 0, "w": 32, "h": 16}},
+			{"filename": "idle.png", "frame": {"x": 0, "y": 0, "w": 32, "h": 16}}
+		]
+	}`
+
+	for _, metadata := range []string{hash, array} {
+		atlas, err := NewTextureAtlas(texture, strings.NewReader(metadata))
+		assert.Nil(t, err)
+		assert.Equal(t, texture, atlas.Texture())
+		assert.Equal(t, []string{"idle.png", "run-1.png"}, atlas.Names())
+
+		run := atlas.Region("run-1.png")
+		assert.Equal(t, "run-1.png", run.Name)
+		assert.Equal(t, texture, run.Texture)
+		assert.Equal(t, Rect{X: 32, Y: 0, Width: 32, Height: 16}, run.Rect)
+		assert.Nil(t, atlas.Region("jump.png"))
+
+		s := NewSpriteFromRegion(run)
+		assert.Equal(t, run.Rect, s.Region)
+		s.SetRegion(atlas.Region("idle.png"))
+		assert.Equal(t, float32(0), s.Region.X)
+	}
+}
+
+func TestTextureAtlasErrors(t *testing.T) {
+	for _, metadata := range []string{
+		`{`,
+		`{"frames": 1}`,
+		`{"frames": [{"frame": {"x": 0, "y": 0, "w": 1, "h": 1}}]}`,
+		`{"frames": {"a": {"frame": {"x": 0, "y": 0, "w": 1, "h": 1}, "rotated": true}}}`,
+	} {
+		_, err := NewTextureAtlas(nil, strings.NewReader(metadata))
+		assert.NotNil(t, err, metadata)
+	}
+}
+
+func TestRegionRemap(t *testing.T) {
+	so := scaleOffset(Rect{X: 32, Y: 16, Width: 32, Height: 16}, 128, 64)
+	assert.Equal(t, math.Vec4{.25, .25, .25, .25}, so)
+	assert.Equal(t, math.Vec2{.25, .25}, remap(&so, math.Vec2{0, 0}))
+	assert.Equal(t, math.Vec2{.5, .5}, remap(&so, math.Vec2{1, 1}))
+}
@@ -0,0 +1,89 @@
+package sprite
+
+import (
+	"github.com/dlespiau/dax"
+)
+
+// Batch draws sprites, with one draw call per texture: the sprites sharing a
+// texture are merged into a single dax.QuadBatch.
+//
+// Sprites sharing a texture are drawn in the order they were added, and the
+// textures in the order of their first sprite. Sprites of different textures
+// don't keep their relative order: to draw sprites over the ones of another
+// texture, eg. a character over the background tiles, add them to a batch
+// drawn after.
+type Batch struct {
+	sprites []*Sprite
+	// quads are the quad batches of the last draw, one per texture. They
+	// are reused from draw to draw.
+	quads []*dax.QuadBatch
+}
+
+// NewBatch creates an empty Batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Add adds sprites to the batch.
+func (b *Batch) Add(sprites ...*Sprite) {
+	b.sprites = append(b.sprites, sprites...)
+}
+
+// Remove removes a sprite from the batch. It does nothing if s isn't part of
+// the batch.
+func (b *Batch) Remove(s *Sprite) {
+	for i := range b.sprites {
+		if b.sprites[i] == s {
+			b.sprites = append(b.sprites[:i], b.sprites[i+1:]...)
+			return
+		}
+	}
+}
+
+// Sprites returns the sprites of the batch.
+func (b *Batch) Sprites() []*Sprite {
+	return b.sprites
+}
+
+// Len returns the number of sprites in the batch.
+func (b *Batch) Len() int {
+	return len(b.sprites)
+}
+
+// Clear removes all the sprites.
+func (b *Batch) Clear() {
+	b.sprites = b.sprites[:0]
+}
+
+// build fills the quad batches with the sprites and returns them, one per
+// texture.
+func (b *Batch) build() []*dax.QuadBatch {
+	n := 0
+	index := make(map[*dax.Texture]int)
+
+	for _, s := range b.sprites {
+		i, ok := index[s.Texture]
+		if !ok {
+			i = n
+			index[s.Texture] = i
+			if i == len(b.quads) {
+				b.quads = append(b.quads, dax.NewQuadBatch(s.Texture))
+			}
+			b.quads[i].SetTexture(s.Texture)
+			b.quads[i].Clear()
+			n++
+		}
+
+		corners, uvs := s.Corners(), s.uvs()
+		b.quads[i].AddQuad(&corners, &uvs, &s.Color)
+	}
+
+	return b.quads[:n]
+}
+
+// Draw implements dax.Drawer.
+func (b *Batch) Draw(fb dax.Framebuffer) {
+	for _, q := range b.build() {
+		q.Draw(fb)
+	}
+}
@@ -0,0 +1,38 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchMergesTextures(t *testing.T) {
+	t1, t2 := &dax.Texture{}, &dax.Texture{}
+	s1, s2, s3, s4 := NewSprite(t1), NewSprite(t2), NewSprite(t1), NewSprite(nil)
+
+	b := NewBatch()
+	b.Add(s1, s2, s3, s4)
+	assert.Equal(t, 4, b.Len())
+
+	// One quad batch per texture, in the order of their first sprite.
+	quads := b.build()
+	assert.Equal(t, 3, len(quads))
+	assert.Equal(t, t1, quads[0].Texture())
+	assert.Equal(t, 2, quads[0].Len())
+	assert.Equal(t, t2, quads[1].Texture())
+	assert.Equal(t, 1, quads[1].Len())
+	assert.Nil(t, quads[2].Texture())
+
+	// Quad batches are reused from draw to draw.
+	b.Remove(s2)
+	b.Remove(s4)
+	again := b.build()
+	assert.Equal(t, 1, len(again))
+	assert.True(t, quads[0] == again[0])
+	assert.Equal(t, 2, again[0].Len())
+
+	b.Clear()
+	assert.Equal(t, 0, b.Len())
+	assert.Equal(t, 0, len(b.build()))
+}
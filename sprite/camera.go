@@ -0,0 +1,88 @@
+package sprite
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Camera is an orthographic camera for 2D scenes. It looks at the z = 0 plane
+// sprites lie in, with Y going up and, at zoom 1, a world unit per framebuffer
+// pixel. The position of the camera is the point of the world shown at the
+// center of the framebuffer: move the camera to scroll the scene.
+//
+// The camera only pans and zooms, it isn't expected to be rotated.
+type Camera struct {
+	dax.BaseCamera
+	width, height int
+	zoom          float32
+}
+
+var _ dax.Camera = &Camera{}
+
+// NewCamera creates a 2D camera at the origin, with a zoom of 1.
+func NewCamera() *Camera {
+	c := &Camera{
+		zoom: 1,
+	}
+	c.Init()
+	return c
+}
+
+// SetZoom sets the number of framebuffer pixels per world unit. Zooms above 1
+// magnify the scene.
+func (c *Camera) SetZoom(zoom float32) {
+	c.zoom = zoom
+	c.update()
+}
+
+// Zoom returns the zoom set with SetZoom.
+func (c *Camera) Zoom() float32 {
+	return c.zoom
+}
+
+// UpdateFBSize implements dax.Camera.
+func (c *Camera) UpdateFBSize(width, height int) {
+	c.width = width
+	c.height = height
+	c.update()
+}
+
+// center returns the framebuffer pixel, from the bottom left corner, the
+// camera position is shown at. It's on a pixel boundary so sprites drawn at
+// integer positions with a zoom of 1 map each texel to a pixel.
+func (c *Camera) center() (x, y float32) {
+	return math.Floor(float32(c.width) / 2), math.Floor(float32(c.height) / 2)
+}
+
+func (c *Camera) update() {
+	if c.width <= 0 || c.height <= 0 {
+		return
+	}
+
+	x, y := c.center()
+	*c.GetProjection() = math.Ortho(
+		-x/c.zoom, (float32(c.width)-x)/c.zoom,
+		-y/c.zoom, (float32(c.height)-y)/c.zoom,
+		-1, 1)
+}
+
+// ScreenToWorld converts framebuffer coordinates, with their origin at the top
+// left corner like mouse coordinates, to world coordinates.
+func (c *Camera) ScreenToWorld(x, y float32) math.Vec2 {
+	cx, cy := c.center()
+	p := c.GetPosition()
+	return math.Vec2{
+		p[0] + (x-cx)/c.zoom,
+		p[1] + (float32(c.height)-y-cy)/c.zoom,
+	}
+}
+
+// WorldToScreen converts world coordinates to framebuffer coordinates, with
+// their origin at the top left corner.
+func (c *Camera) WorldToScreen(p math.Vec2) (x, y float32) {
+	cx, cy := c.center()
+	position := c.GetPosition()
+	x = cx + (p[0]-position[0])*c.zoom
+	y = float32(c.height) - cy - (p[1]-position[1])*c.zoom
+	return
+}
@@ -0,0 +1,33 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCamera(t *testing.T) {
+	c := NewCamera()
+	c.UpdateFBSize(801, 600)
+	c.SetPosition(100, 50, 0)
+
+	// The position of the camera is at the center of the framebuffer, on
+	// a pixel boundary.
+	assert.Equal(t, math.Vec2{100, 50}, c.ScreenToWorld(400, 300))
+	assert.Equal(t, math.Vec2{-300, 350}, c.ScreenToWorld(0, 0))
+
+	// Y goes up in world space.
+	c.SetZoom(2)
+	assert.Equal(t, math.Vec2{101, 49}, c.ScreenToWorld(402, 302))
+
+	x, y := c.WorldToScreen(math.Vec2{101, 49})
+	assert.Equal(t, float32(402), x)
+	assert.Equal(t, float32(302), y)
+
+	// The projection maps the framebuffer edges to clip space ones.
+	p := c.GetProjection()
+	bottomLeft := p.Mul4x1(&math.Vec4{-200, -150, 0, 1})
+	assert.InDelta(t, -1, bottomLeft[0], 1e-5)
+	assert.InDelta(t, -1, bottomLeft[1], 1e-5)
+}
@@ -0,0 +1,148 @@
+// Package sprite draws 2D scenes made of sprites, textured rectangles.
+//
+// Sprites are drawn by a Batch, merging the sprites sharing a texture into a
+// single draw call, usually seen through a 2D Camera:
+//
+//	sheet := dax.NewTextureFromImage(img)
+//	hero := sprite.NewSprite(sheet)
+//	hero.Region = sprite.Rect{X: 0, Y: 0, Width: 32, Height: 32}
+//	hero.Position = math.Vec2{100, 50}
+//
+//	batch := sprite.NewBatch()
+//	batch.Add(hero)
+//
+//	fb.SetCamera(sprite.NewCamera())
+//	fb.Draw(batch)
+//
+// Sprites lie in the z = 0 plane of world space, with Y going up.
+package sprite
+
+import (
+	"github.com/dlespiau/dax"
+	"github.com/dlespiau/dax/math"
+)
+
+// Rect is a rectangle of a texture, in texels, with its origin at the top left
+// corner of the image.
+type Rect struct {
+	X, Y, Width, Height float32
+}
+
+// IsEmpty returns true if the rectangle has no area.
+func (r *Rect) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Sprite is a textured rectangle of a 2D scene. Its fields can be changed at
+// any time, the batches drawing it pick up the changes at their next draw.
+type Sprite struct {
+	// Texture is the image the sprite shows part of. A nil texture draws a
+	// rectangle of Color.
+	Texture *dax.Texture
+	// Region is the part of the texture shown by the sprite. An empty
+	// region shows the whole texture.
+	Region Rect
+	// Position is where the anchor of the sprite is, in world units.
+	Position math.Vec2
+	// Size is the size of the sprite, in world units. A zero size uses the
+	// size of the region, a texel per world unit.
+	Size math.Vec2
+	// Anchor is the point of the sprite placed at Position, and the center
+	// of its rotation, in fractions of its size: (0, 0) is the bottom left
+	// corner and (1, 1) the top right one.
+	Anchor math.Vec2
+	// Rotation is the counter-clockwise rotation of the sprite around its
+	// anchor, in radians.
+	Rotation float32
+	// FlipX and FlipY mirror the region horizontally and vertically.
+	FlipX, FlipY bool
+	// Color multiplies the texture color, eg. to tint the sprite or fade it
+	// out.
+	Color dax.Color
+}
+
+// NewSprite creates a sprite showing the whole texture, anchored at its center
+// and not tinted.
+func NewSprite(texture *dax.Texture) *Sprite {
+	return &Sprite{
+		Texture: texture,
+		Anchor:  math.Vec2{.5, .5},
+		Color:   dax.Color{R: 1, G: 1, B: 1, A: 1},
+	}
+}
+
+// region returns the part of the texture shown by the sprite, in texels.
+func (s *Sprite) region() Rect {
+	if !s.Region.IsEmpty() || s.Texture == nil {
+		return s.Region
+	}
+	width, height := s.Texture.Size()
+	return Rect{Width: float32(width), Height: float32(height)}
+}
+
+// size returns the size of the sprite, in world units.
+func (s *Sprite) size() math.Vec2 {
+	if s.Size[0] != 0 || s.Size[1] != 0 {
+		return s.Size
+	}
+	r := s.region()
+	return math.Vec2{r.Width, r.Height}
+}
+
+// Corners returns the corners of the sprite in world space, in
+// counter-clockwise order from the bottom left one before rotation.
+func (s *Sprite) Corners() [4]math.Vec2 {
+	size := s.size()
+	left := -s.Anchor[0] * size[0]
+	bottom := -s.Anchor[1] * size[1]
+	right := left + size[0]
+	top := bottom + size[1]
+
+	corners := [4]math.Vec2{
+		{left, bottom},
+		{right, bottom},
+		{right, top},
+		{left, top},
+	}
+
+	sin, cos := math.Sincos(s.Rotation)
+	for i := range corners {
+		x, y := corners[i][0], corners[i][1]
+		if s.Rotation != 0 {
+			x, y = x*cos-y*sin, x*sin+y*cos
+		}
+		corners[i] = math.Vec2{s.Position[0] + x, s.Position[1] + y}
+	}
+	return corners
+}
+
+// uvs returns the texture coordinates of the corners returned by Corners.
+func (s *Sprite) uvs() [4]math.Vec2 {
+	if s.Texture == nil {
+		return [4]math.Vec2{}
+	}
+	width, height := s.Texture.Size()
+	return texCoords(s.region(), width, height, s.FlipX, s.FlipY)
+}
+
+// texCoords returns the texture coordinates of the corners of the region r of
+// a width x height texture, in counter-clockwise order from the bottom left
+// one.
+func texCoords(r Rect, width, height int, flipX, flipY bool) [4]math.Vec2 {
+	u0, u1 := r.X/float32(width), (r.X+r.Width)/float32(width)
+	// Textures have their top row at v = 0.
+	top, bottom := r.Y/float32(height), (r.Y+r.Height)/float32(height)
+	if flipX {
+		u0, u1 = u1, u0
+	}
+	if flipY {
+		top, bottom = bottom, top
+	}
+
+	return [4]math.Vec2{
+		{u0, bottom},
+		{u1, bottom},
+		{u1, top},
+		{u0, top},
+	}
+}
@@ -0,0 +1,50 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertCorners(t *testing.T, expected, corners [4]math.Vec2) {
+	for i := range corners {
+		assert.InDelta(t, expected[i][0], corners[i][0], 1e-5, "corner %d", i)
+		assert.InDelta(t, expected[i][1], corners[i][1], 1e-5, "corner %d", i)
+	}
+}
+
+func TestSpriteCorners(t *testing.T) {
+	s := NewSprite(nil)
+	s.Region = Rect{X: 16, Y: 0, Width: 32, Height: 16}
+	s.Position = math.Vec2{100, 50}
+
+	// The size defaults to the size of the region, centered on the
+	// position.
+	assertCorners(t, [4]math.Vec2{
+		{84, 42}, {116, 42}, {116, 58}, {84, 58},
+	}, s.Corners())
+
+	s.Size = math.Vec2{10, 20}
+	s.Anchor = math.Vec2{0, 0}
+	assertCorners(t, [4]math.Vec2{
+		{100, 50}, {110, 50}, {110, 70}, {100, 70},
+	}, s.Corners())
+
+	// Sprites rotate around their anchor.
+	s.Rotation = math.Pi / 2
+	assertCorners(t, [4]math.Vec2{
+		{100, 50}, {100, 60}, {80, 60}, {80, 50},
+	}, s.Corners())
+}
+
+func TestTexCoords(t *testing.T) {
+	r := Rect{X: 32, Y: 0, Width: 32, Height: 16}
+	assert.Equal(t, [4]math.Vec2{
+		{.25, .25}, {.5, .25}, {.5, 0}, {.25, 0},
+	}, texCoords(r, 128, 64, false, false))
+
+	assert.Equal(t, [4]math.Vec2{
+		{.5, 0}, {.25, 0}, {.25, .25}, {.5, .25},
+	}, texCoords(r, 128, 64, true, true))
+}
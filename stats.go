@@ -0,0 +1,249 @@
+package dax
+
+import (
+	"fmt"
+)
+
+// Stats are rendering statistics. Times are averaged over the last
+// statsInterval seconds, counters are the ones of the last frame.
+type Stats struct {
+	// FrameTime is the average time between two frames, in seconds.
+	FrameTime float64
+	// FPS is the number of frames per second.
+	FPS float64
+	// DrawCalls is the number of draw calls issued.
+	DrawCalls int
+	// Triangles is the number of triangles submitted.
+	Triangles int
+	// PoolHits is the number of meshes drawn with the GL vertex array and
+	// buffers the renderer kept from a previous draw.
+	PoolHits int
+	// PoolMisses is the number of meshes the renderer had to create a GL
+	// vertex array and buffers for. It drops to 0 when the same meshes are
+	// drawn frame after frame.
+	PoolMisses int
+}
+
+// renderCounters count what the renderer submits during a frame.
+type renderCounters struct {
+	drawCalls  int
+	triangles  int
+	poolHits   int
+	poolMisses int
+}
+
+// draw records a draw call of count vertices in the given mode, repeated
+// instances times.
+func (c *renderCounters) draw(mode VertexMode, count, instances int) {
+	c.drawCalls++
+	c.triangles += numTriangles(mode, count) * instances
+}
+
+// reset zeroes the counters, at the start of a frame.
+func (c *renderCounters) reset() {
+	*c = renderCounters{}
+}
+
+// numTriangles returns the number of triangles drawn by count vertices in the
+// given mode.
+func numTriangles(mode VertexMode, count int) int {
+	switch mode {
+	case VertexModeTriangles:
+		return count / 3
+	case VertexModeTriangleStrip, VertexModeTriangleFan:
+		if count < 3 {
+			return 0
+		}
+		return count - 2
+	}
+	return 0
+}
+
+// The statistics are refreshed every statsInterval seconds, so the numbers
+// stay readable.
+const statsInterval = 0.5
+
+// statsCounter accumulates frame statistics.
+type statsCounter struct {
+	current Stats
+
+	frames  int
+	elapsed float64
+}
+
+// addFrame records a frame that took dt seconds.
+func (s *statsCounter) addFrame(dt float64, counters *renderCounters) {
+	s.current.DrawCalls = counters.drawCalls
+	s.current.Triangles = counters.triangles
+	s.current.PoolHits = counters.poolHits
+	s.current.PoolMisses = counters.poolMisses
+
+	s.frames++
+	s.elapsed += dt
+	if s.elapsed < statsInterval {
+		return
+	}
+
+	s.current.FrameTime = s.elapsed / float64(s.frames)
+	s.current.FPS = float64(s.frames) / s.elapsed
+	s.frames = 0
+	s.elapsed = 0
+}
+
+// lines returns the text of the stats overlay.
+func (s *Stats) lines() []string {
+	return []string{
+		fmt.Sprintf("FPS   %.1f", s.FPS),
+		fmt.Sprintf("MS    %.2f", s.FrameTime*1000),
+		fmt.Sprintf("DRAWS %d", s.DrawCalls),
+		fmt.Sprintf("TRIS  %d", s.Triangles),
+		fmt.Sprintf("POOL  %d/%d", s.PoolHits, s.PoolHits+s.PoolMisses),
+	}
+}
+
+// statsFont is a tiny 3x5 bitmap font covering the characters of the
+// overlays: the stats, the names of keys, ... Unknown characters are drawn as
+// spaces.
+var statsFont = map[rune][5]string{
+	'0':  {"###", "#.#", "#.#", "#.#", "###"},
+	'1':  {".#.", "##.", ".#.", ".#.", "###"},
+	'2':  {"###", "..#", "###", "#..", "###"},
+	'3':  {"###", "..#", "###", "..#", "###"},
+	'4':  {"#.#", "#.#", "###", "..#", "..#"},
+	'5':  {"###", "#..", "###", "..#", "###"},
+	'6':  {"###", "#..", "###", "#.#", "###"},
+	'7':  {"###", "..#", "..#", "..#", "..#"},
+	'8':  {"###", "#.#", "###", "#.#", "###"},
+	'9':  {"###", "#.#", "###", "..#", "###"},
+	'.':  {"...", "...", "...", "...", ".#."},
+	',':  {"...", "...", "...", ".#.", "#.."},
+	';':  {"...", ".#.", "...", ".#.", "#.."},
+	'\'': {".#.", ".#.", "...", "...", "..."},
+	'`':  {"#..", ".#.", "...", "...", "..."},
+	'+':  {"...", ".#.", "###", ".#.", "..."},
+	'-':  {"...", "...", "###", "...", "..."},
+	'=':  {"...", "###", "...", "###", "..."},
+	'/':  {"..#", "..#", ".#.", "#..", "#.."},
+	'\\': {"#..", "#..", ".#.", "..#", "..#"},
+	'[':  {"##.", "#..", "#..", "#..", "##."},
+	']':  {".##", "..#", "..#", "..#", ".##"},
+	'A':  {".#.", "#.#", "###", "#.#", "#.#"},
+	'B':  {"##.", "#.#", "##.", "#.#", "##."},
+	'C':  {".##", "#..", "#..", "#..", ".##"},
+	'D':  {"##.", "#.#", "#.#", "#.#", "##."},
+	'E':  {"###", "#..", "##.", "#..", "###"},
+	'F':  {"###", "#..", "##.", "#..", "#.."},
+	'G':  {".##", "#..", "#.#", "#.#", ".##"},
+	'H':  {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I':  {"###", ".#.", ".#.", ".#.", "###"},
+	'J':  {"..#", "..#", "..#", "#.#", ".#."},
+	'K':  {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L':  {"#..", "#..", "#..", "#..", "###"},
+	'M':  {"#.#", "###", "###", "#.#", "#.#"},
+	'N':  {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O':  {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P':  {"##.", "#.#", "##.", "#..", "#.."},
+	'Q':  {".#.", "#.#", "#.#", "##.", ".##"},
+	'R':  {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S':  {".##", "#..", ".#.", "..#", "##."},
+	'T':  {"###", ".#.", ".#.", ".#.", ".#."},
+	'U':  {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V':  {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W':  {"#.#", "#.#", "###", "###", "#.#"},
+	'X':  {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y':  {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z':  {"###", "..#", ".#.", "#..", "###"},
+}
+
+// Layout of the stats overlay, in font pixels.
+const (
+	statsPixelSize = 2
+	statsMargin    = 4
+	statsAdvance   = 4
+	statsLineSpace = 7
+)
+
+var (
+	statsBackground = Color{0, 0, 0, .6}
+	statsForeground = Color{1, 1, 1, 1}
+)
+
+// overlayBuilder builds the triangles of overlays drawn on top of a window, in
+// window coordinates with the origin at the top left corner: a "position"
+// attribute with 2 components and a "color" one with 4.
+type overlayBuilder struct {
+	positions, colors []float32
+}
+
+func (b *overlayBuilder) quad(x0, y0, x1, y1 float32, c *Color) {
+	b.positions = append(b.positions,
+		x0, y0, x0, y1, x1, y1,
+		x0, y0, x1, y1, x1, y0)
+	for i := 0; i < 6; i++ {
+		b.colors = append(b.colors, c.R, c.G, c.B, c.A)
+	}
+}
+
+// bitmap draws the pixels of rows set to set, scaled by pixelSize, with the
+// top left corner of the bitmap at (x, y).
+func (b *overlayBuilder) bitmap(x, y, pixelSize float32, rows []string, set rune, c *Color) {
+	for j, row := range rows {
+		for i, pixel := range row {
+			if pixel != set {
+				continue
+			}
+			px := x + float32(i)*pixelSize
+			py := y + float32(j)*pixelSize
+			b.quad(px, py, px+pixelSize, py+pixelSize, c)
+		}
+	}
+}
+
+// textBoxSize returns the size of the box textBox draws lines in.
+func textBoxSize(lines []string) (width, height float32) {
+	columns := 0
+	for _, line := range lines {
+		if n := len([]rune(line)); n > columns {
+			columns = n
+		}
+	}
+	const p = statsPixelSize
+	width = float32(2*statsMargin+columns*statsAdvance-1) * p
+	height = float32(2*statsMargin+len(lines)*statsLineSpace-2) * p
+	return
+}
+
+// textBox draws lines on a translucent box, with the top left corner of the
+// box at (x, y).
+func (b *overlayBuilder) textBox(x, y float32, lines []string) {
+	width, height := textBoxSize(lines)
+	b.quad(x, y, x+width, y+height, &statsBackground)
+
+	const p = statsPixelSize
+	for l, line := range lines {
+		for c, r := range []rune(line) {
+			glyph, ok := statsFont[r]
+			if !ok {
+				continue
+			}
+			px := x + float32(statsMargin+c*statsAdvance)*p
+			py := y + float32(statsMargin+l*statsLineSpace)*p
+			b.bitmap(px, py, p, glyph[:], '#', &statsForeground)
+		}
+	}
+}
+
+func (b *overlayBuilder) mesh() *Mesh {
+	m := NewMesh()
+	m.AddAttribute("position", b.positions, 2)
+	m.AddAttribute("color", b.colors, 4)
+	return m
+}
+
+// statsOverlay builds the triangles of the stats overlay, at the top left
+// corner of the window. See overlayBuilder.
+func statsOverlay(lines []string) *Mesh {
+	var b overlayBuilder
+	b.textBox(0, 0, lines)
+	return b.mesh()
+}
@@ -0,0 +1,98 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumTriangles(t *testing.T) {
+	tests := []struct {
+		mode     VertexMode
+		count    int
+		expected int
+	}{
+		{VertexModeTriangles, 36, 12},
+		{VertexModeTriangles, 2, 0},
+		{VertexModeTriangleStrip, 6, 4},
+		{VertexModeTriangleFan, 5, 3},
+		{VertexModeTriangleFan, 2, 0},
+		{VertexModeLines, 10, 0},
+		{VertexModePoints, 10, 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, numTriangles(test.mode, test.count))
+	}
+}
+
+func TestRenderCounters(t *testing.T) {
+	var c renderCounters
+	c.draw(VertexModeTriangles, 36, 1)
+	c.draw(VertexModeTriangles, 6, 10)
+	c.draw(VertexModeLineStrip, 4, 1)
+	assert.Equal(t, 3, c.drawCalls)
+	assert.Equal(t, 32, c.triangles)
+
+	c.reset()
+	assert.Equal(t, renderCounters{}, c)
+}
+
+func TestStatsCounter(t *testing.T) {
+	var s statsCounter
+	counters := renderCounters{drawCalls: 2, triangles: 24, poolHits: 5, poolMisses: 1}
+
+	// Times are only refreshed once statsInterval has elapsed.
+	for i := 0; i < 3; i++ {
+		s.addFrame(.125, &counters)
+	}
+	assert.Equal(t, 0., s.current.FPS)
+	assert.Equal(t, 2, s.current.DrawCalls)
+	assert.Equal(t, 24, s.current.Triangles)
+	assert.Equal(t, 5, s.current.PoolHits)
+	assert.Equal(t, 1, s.current.PoolMisses)
+
+	s.addFrame(.125, &counters)
+	assertFloat(t, 8, float32(s.current.FPS), 1e-3)
+	assertFloat(t, .125, float32(s.current.FrameTime), 1e-3)
+
+	// The next refresh only accounts for the new frames.
+	s.addFrame(.25, &counters)
+	s.addFrame(.25, &counters)
+	assertFloat(t, 4, float32(s.current.FPS), 1e-3)
+}
+
+func TestStatsOverlay(t *testing.T) {
+	// "1." is lit by 9 font pixels: 8 for the '1' and 1 for the '.'. Each
+	// pixel, as well as the background, is a quad made of 6 vertices.
+	m := statsOverlay([]string{"1.", "?"})
+	positions := m.GetAttribute("position")
+	colors := m.GetAttribute("color")
+	assert.Equal(t, 2, positions.NumComponents)
+	assert.Equal(t, 4, colors.NumComponents)
+	assert.Equal(t, (1+9)*6, positions.Len())
+	assert.Equal(t, positions.Len(), colors.Len())
+
+	// The background covers the text.
+	x, y := positions.GetXY(2)
+	assertFloat(t, (2*statsMargin+2*statsAdvance-1)*statsPixelSize, x, 1e-3)
+	assertFloat(t, (2*statsMargin+2*statsLineSpace-2)*statsPixelSize, y, 1e-3)
+	for i := 6; i < positions.Len(); i++ {
+		px, py := positions.GetXY(i)
+		assert.True(t, px > 0 && px < x && py > 0 && py < y)
+	}
+}
+
+func TestStatsFont(t *testing.T) {
+	// All the characters of the overlay have a glyph.
+	s := Stats{FPS: 1234.5, FrameTime: 0.0067, DrawCalls: 89, Triangles: 10}
+	for _, line := range s.lines() {
+		for _, r := range line {
+			if r == ' ' {
+				continue
+			}
+			_, ok := statsFont[r]
+			assert.True(t, ok, "missing glyph for %q", r)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package dax
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// TextureFormat is the format of the texels of a Texture.
+type TextureFormat int
+
+const (
+	textureFormatDefault TextureFormat = iota
+	// TextureFormatRGBA8 has 8 bits per color channel.
+	TextureFormatRGBA8
+	// TextureFormatRGBA16F has a half float per color channel, eg. to
+	// render HDR images.
+	TextureFormatRGBA16F
+	// TextureFormatDepth24 is a 24 bits fixed point depth format.
+	TextureFormatDepth24
+	// TextureFormatDepth32F is a floating point depth format.
+	TextureFormatDepth32F
+	// TextureFormatSRGB8A8 has 8 bits per color channel, with the color
+	// stored in the sRGB color space. Colors written to such textures are
+	// converted from linear to sRGB and converted back when sampled.
+	TextureFormatSRGB8A8
+	// TextureFormatDepth24Stencil8 is a 24 bits fixed point depth format
+	// with an 8 bits stencil.
+	TextureFormatDepth24Stencil8
+	// TextureFormatDepth32FStencil8 is a floating point depth format with an
+	// 8 bits stencil.
+	TextureFormatDepth32FStencil8
+)
+
+// IsDepth returns true if f is a depth format, with or without stencil.
+func (f TextureFormat) IsDepth() bool {
+	return f == TextureFormatDepth24 || f == TextureFormatDepth32F || f.HasStencil()
+}
+
+// HasStencil returns true if f is a depth format with a stencil.
+func (f TextureFormat) HasStencil() bool {
+	return f == TextureFormatDepth24Stencil8 || f == TextureFormatDepth32FStencil8
+}
+
+// IsSRGB returns true if the color of f is in the sRGB color space.
+func (f TextureFormat) IsSRGB() bool {
+	return f == TextureFormatSRGB8A8
+}
+
+// glFormat returns the internal format, format and type used to allocate a
+// texture with the format f.
+func (f TextureFormat) glFormat() (internalFormat int32, format, xtype uint32) {
+	switch f {
+	case TextureFormatRGBA16F:
+		return gl.RGBA16F, gl.RGBA, gl.HALF_FLOAT
+	case TextureFormatDepth24:
+		return gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.UNSIGNED_INT
+	case TextureFormatDepth32F:
+		return gl.DEPTH_COMPONENT32F, gl.DEPTH_COMPONENT, gl.FLOAT
+	case TextureFormatSRGB8A8:
+		return gl.SRGB8_ALPHA8, gl.RGBA, gl.UNSIGNED_BYTE
+	case TextureFormatDepth24Stencil8:
+		return gl.DEPTH24_STENCIL8, gl.DEPTH_STENCIL, gl.UNSIGNED_INT_24_8
+	case TextureFormatDepth32FStencil8:
+		return gl.DEPTH32F_STENCIL8, gl.DEPTH_STENCIL, gl.FLOAT_32_UNSIGNED_INT_24_8_REV
+	default:
+		return gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE
+	}
+}
+
+// TextureFilter is how a Texture is sampled between texels.
+type TextureFilter int
+
+const (
+	// TextureFilterLinear interpolates the nearest texels.
+	TextureFilterLinear TextureFilter = iota
+	// TextureFilterNearest returns the nearest texel, eg. to keep pixel art
+	// crisp when scaled up.
+	TextureFilterNearest
+)
+
+// glFilter returns the GL filter for f.
+func (f TextureFilter) glFilter() int32 {
+	if f == TextureFilterNearest {
+		return gl.NEAREST
+	}
+	return gl.LINEAR
+}
+
+// Texture is a 2D image living in GPU memory.
+type Texture struct {
+	id            uint32
+	width, height int
+	format        TextureFormat
+}
+
+// newTexture allocates an uninitialized texture.
+func newTexture(width, height int, format TextureFormat) *Texture {
+	t := &Texture{
+		width:  width,
+		height: height,
+		format: format,
+	}
+
+	internalFormat, glFormat, xtype := format.glFormat()
+	gl.GenTextures(1, &t.id)
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, int32(width), int32(height),
+		0, glFormat, xtype, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	return t
+}
+
+// NewTextureFromImage creates a TextureFormatSRGB8A8 texture with the pixels
+// of img, eg. a PNG decoded with image.Decode. The top row of img is at the v
+// = 0 texture coordinate.
+func NewTextureFromImage(img image.Image) *Texture {
+	bounds := img.Bounds()
+	rgba, ok := img.(*image.NRGBA)
+	if !ok || rgba.Stride != 4*bounds.Dx() {
+		rgba = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+
+	t := newTexture(bounds.Dx(), bounds.Dy(), TextureFormatSRGB8A8)
+	if len(rgba.Pix) == 0 {
+		return t
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, int32(t.width), int32(t.height),
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	return t
+}
+
+// SetFilter sets how the texture is sampled when minified and magnified.
+// Textures are created with TextureFilterLinear.
+func (t *Texture) SetFilter(filter TextureFilter) {
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter.glFilter())
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter.glFilter())
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// Size returns the size of the texture in texels.
+func (t *Texture) Size() (width, height int) {
+	return t.width, t.height
+}
+
+// Format returns the format of the texture.
+func (t *Texture) Format() TextureFormat {
+	return t.format
+}
+
+// ID returns the GL name of the texture, for use in custom GL code.
+func (t *Texture) ID() uint32 {
+	return t.id
+}
+
+// Bind binds the texture to the texture unit unit.
+func (t *Texture) Bind(unit int) {
+	gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+}
+
+func (t *Texture) release(gc *glGarbageCollector) {
+	gc.release(glObjectTexture, t.id)
+	t.id = 0
+}
@@ -0,0 +1,152 @@
+package dax
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dlespiau/dax/math"
+)
+
+// uniformValue is a uniform value set with Material.SetUniform.
+type uniformValue struct {
+	name string
+	// value is one of float32, int32, math.Vec2, math.Vec3, math.Vec4,
+	// math.Mat3, math.Mat4 or *Texture.
+	value interface{}
+	// version changes each time the value is set.
+	version uint64
+}
+
+// uniformVersion is the last version given to a uniform value. Versions are
+// unique across all materials. It's updated atomically as materials may be
+// changed by the update of buffered scene graphs while drawing.
+var uniformVersion uint64
+
+// uniformValues is a list of uniform values. Values are kept in the order
+// they were first set in, so texture units stay the same from one draw to the
+// next.
+type uniformValues struct {
+	values []uniformValue
+	// source is, for copies, the values copied. Copies share the upload
+	// cache of their source.
+	source *uniformValues
+}
+
+// copyFrom makes u a copy of src.
+func (u *uniformValues) copyFrom(src *uniformValues) {
+	u.values = append(u.values[:0], src.values...)
+	u.source = src
+}
+
+// normalizeUniformValue converts value to one of the types stored in
+// uniformValue.
+func normalizeUniformValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float32, int32, math.Vec2, math.Vec3, math.Vec4, math.Mat3, math.Mat4, *Texture:
+		return v
+	case float64:
+		return float32(v)
+	case int:
+		return int32(v)
+	case bool:
+		if v {
+			return int32(1)
+		}
+		return int32(0)
+	case Color:
+		return v.Vec4()
+	case *Color:
+		return v.Vec4()
+	case *math.Vec2:
+		return *v
+	case *math.Vec3:
+		return *v
+	case *math.Vec4:
+		return *v
+	case *math.Mat3:
+		return *v
+	case *math.Mat4:
+		return *v
+	default:
+		panic(fmt.Sprintf("material: unsupported uniform type %T", value))
+	}
+}
+
+func (u *uniformValues) find(name string) *uniformValue {
+	for i := range u.values {
+		if u.values[i].name == name {
+			return &u.values[i]
+		}
+	}
+	return nil
+}
+
+func (u *uniformValues) set(name string, value interface{}) {
+	value = normalizeUniformValue(value)
+
+	v := u.find(name)
+	if v == nil {
+		u.values = append(u.values, uniformValue{name: name})
+		v = &u.values[len(u.values)-1]
+	} else if v.value == value {
+		return
+	}
+
+	v.value = value
+	v.version = atomic.AddUint64(&uniformVersion, 1)
+}
+
+func (u *uniformValues) get(name string) interface{} {
+	if v := u.find(name); v != nil {
+		return v.value
+	}
+	return nil
+}
+
+// uniformCache remembers, for a program, which uniform values have been
+// uploaded.
+type uniformCache struct {
+	owner    *uniformValues
+	versions map[string]uint64
+}
+
+// update calls upload for the values that changed since the last update.
+// Switching to different values uploads all of them, copies of the same values
+// aren't different. unit is the texture unit of texture values.
+func (c *uniformCache) update(values *uniformValues, upload func(v *uniformValue, unit int)) {
+	owner := values
+	if values.source != nil {
+		owner = values.source
+	}
+	if c.owner != owner || c.versions == nil {
+		c.owner = owner
+		c.versions = make(map[string]uint64)
+	}
+
+	values.forEach(0, func(v *uniformValue, unit int) {
+		if c.versions[v.name] != v.version {
+			upload(v, unit)
+			c.versions[v.name] = v.version
+		}
+	})
+}
+
+// invalidate makes the next update upload name again, for when something
+// else has been uploaded in its place.
+func (c *uniformCache) invalidate(name string) {
+	delete(c.versions, name)
+}
+
+// forEach calls f for each value. unit is the texture unit of texture values,
+// numbered from firstUnit. forEach returns the first unit left unused.
+func (u *uniformValues) forEach(firstUnit int, f func(v *uniformValue, unit int)) int {
+	unit := firstUnit
+	for i := range u.values {
+		v := &u.values[i]
+		f(v, unit)
+		if _, ok := v.value.(*Texture); ok {
+			unit++
+		}
+	}
+	return unit
+}
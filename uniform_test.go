@@ -0,0 +1,99 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterialSetUniform(t *testing.T) {
+	m := &BaseMaterial{}
+
+	m.SetUniform("f", 1.5)
+	m.SetUniform("i", 3)
+	m.SetUniform("color", &Color{1, 0, 0, 1})
+	m.SetUniform("v3", math.Vec3{1, 2, 3})
+	m.SetUniform("m3", math.Ident3())
+
+	assert.Equal(t, float32(1.5), m.GetUniform("f"))
+	assert.Equal(t, int32(3), m.GetUniform("i"))
+	assert.Equal(t, math.Vec4{1, 0, 0, 1}, m.GetUniform("color"))
+	assert.Equal(t, math.Vec3{1, 2, 3}, m.GetUniform("v3"))
+	assert.Equal(t, math.Ident3(), m.GetUniform("m3"))
+	assert.Nil(t, m.GetUniform("none"))
+
+	assert.Panics(t, func() { m.SetUniform("s", "foo") })
+}
+
+func TestUniformDirtyTracking(t *testing.T) {
+	var values uniformValues
+	var cache uniformCache
+
+	uploaded := func() []string {
+		var names []string
+		cache.update(&values, func(v *uniformValue, unit int) {
+			names = append(names, v.name)
+		})
+		return names
+	}
+
+	values.set("a", float32(1))
+	values.set("b", math.Vec2{1, 2})
+	assert.Equal(t, []string{"a", "b"}, uploaded())
+	assert.Nil(t, uploaded())
+
+	// Only changed values are uploaded again.
+	values.set("b", math.Vec2{3, 4})
+	values.set("a", float32(1))
+	assert.Equal(t, []string{"b"}, uploaded())
+
+	// Switching to other values uploads everything.
+	var other uniformValues
+	other.set("a", float32(2))
+	cache.update(&other, func(v *uniformValue, unit int) {})
+	assert.Equal(t, []string{"a", "b"}, uploaded())
+
+	// Copies share the cache of the values they copy.
+	var copied uniformValues
+	copied.copyFrom(&values)
+	names := []string{}
+	cache.update(&copied, func(v *uniformValue, unit int) {
+		names = append(names, v.name)
+	})
+	assert.Equal(t, []string{}, names)
+	values.set("a", float32(3))
+	copied.copyFrom(&values)
+	cache.update(&copied, func(v *uniformValue, unit int) {
+		names = append(names, v.name)
+	})
+	assert.Equal(t, []string{"a"}, names)
+}
+
+func TestUniformTextureUnits(t *testing.T) {
+	var values uniformValues
+	var cache uniformCache
+
+	t0, t1 := &Texture{}, &Texture{}
+	values.set("diffuseMap", t0)
+	values.set("scale", float32(1))
+	values.set("normalMap", t1)
+
+	units := make(map[string]int)
+	cache.update(&values, func(v *uniformValue, unit int) {
+		if _, ok := v.value.(*Texture); ok {
+			units[v.name] = unit
+		}
+	})
+	assert.Equal(t, map[string]int{"diffuseMap": 0, "normalMap": 1}, units)
+
+	var bound []*Texture
+	next := values.forEach(2, func(v *uniformValue, unit int) {
+		if texture, ok := v.value.(*Texture); ok {
+			assert.Equal(t, 2+len(bound), unit)
+			bound = append(bound, texture)
+		}
+	})
+	assert.Equal(t, []*Texture{t0, t1}, bound)
+	assert.Equal(t, 4, next)
+}
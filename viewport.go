@@ -0,0 +1,85 @@
+package dax
+
+import (
+	"github.com/dlespiau/dax/math"
+	"github.com/dlespiau/dax/math/imath"
+)
+
+// Viewport is a rectangle of a framebuffer, in pixels, with its origin at the
+// bottom left corner.
+type Viewport struct {
+	X, Y, Width, Height int
+}
+
+// Project transforms obj, in object space, to window coordinates in the
+// viewport, with a depth in [0, 1].
+func (v *Viewport) Project(obj *math.Vec3, modelview, projection *math.Mat4) math.Vec3 {
+	return math.Project(obj, modelview, projection, v.X, v.Y, v.Width, v.Height)
+}
+
+// UnProject transforms win, window coordinates in the viewport with a depth in
+// [0, 1], back to object space.
+func (v *Viewport) UnProject(win *math.Vec3, modelview, projection *math.Mat4) math.Vec3 {
+	return math.UnProject(win, modelview, projection, v.X, v.Y, v.Width, v.Height)
+}
+
+// Clamp returns the part of v inside a framebuffer of size width x height. The
+// rectangle returned is empty, with a zero width and height, when v is fully
+// outside.
+func (v *Viewport) Clamp(width, height int) Viewport {
+	x0, y0 := imath.Max(v.X, 0), imath.Max(v.Y, 0)
+	x1, y1 := imath.Min(v.X+v.Width, width), imath.Min(v.Y+v.Height, height)
+	if x1 <= x0 || y1 <= y0 {
+		return Viewport{}
+	}
+	return Viewport{x0, y0, x1 - x0, y1 - y0}
+}
+
+// inside returns true if v is a non-empty rectangle fully inside a framebuffer
+// of size width x height.
+func (v *Viewport) inside(width, height int) bool {
+	return v.Width > 0 && v.Height > 0 &&
+		v.X >= 0 && v.Y >= 0 &&
+		v.X+v.Width <= width && v.Y+v.Height <= height
+}
+
+// viewportState tracks the viewport and scissor of a framebuffer.
+type viewportState struct {
+	viewport       Viewport
+	scissor        Viewport
+	scissorEnabled bool
+
+	// Saved states, see push and pop.
+	stack []viewportState
+}
+
+func (s *viewportState) setViewport(x, y, width, height int) {
+	s.viewport = Viewport{x, y, width, height}
+}
+
+func (s *viewportState) setScissor(x, y, width, height int) {
+	s.scissor = Viewport{x, y, width, height}
+	s.scissorEnabled = true
+}
+
+func (s *viewportState) disableScissor() {
+	s.scissorEnabled = false
+}
+
+// push saves the current state.
+func (s *viewportState) push() {
+	saved := *s
+	saved.stack = nil
+	s.stack = append(s.stack, saved)
+}
+
+// pop restores the last saved state. It panics if there's no saved state.
+func (s *viewportState) pop() {
+	n := len(s.stack)
+	if n == 0 {
+		panic("framebuffer: unbalanced PopViewport")
+	}
+	stack := s.stack[:n-1]
+	*s = s.stack[n-1]
+	s.stack = stack
+}
@@ -0,0 +1,75 @@
+package dax
+
+import (
+	"testing"
+
+	"github.com/dlespiau/dax/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewportStack(t *testing.T) {
+	var s viewportState
+	s.setViewport(0, 0, 800, 600)
+
+	s.push()
+	s.setViewport(10, 20, 100, 50)
+	s.setScissor(10, 20, 50, 50)
+	assert.Equal(t, Viewport{10, 20, 100, 50}, s.viewport)
+	assert.True(t, s.scissorEnabled)
+
+	// Nested pass.
+	s.push()
+	s.setViewport(0, 0, 64, 64)
+	s.disableScissor()
+	s.pop()
+	assert.Equal(t, Viewport{10, 20, 100, 50}, s.viewport)
+	assert.Equal(t, Viewport{10, 20, 50, 50}, s.scissor)
+	assert.True(t, s.scissorEnabled)
+
+	s.pop()
+	assert.Equal(t, Viewport{0, 0, 800, 600}, s.viewport)
+	assert.False(t, s.scissorEnabled)
+	assert.Equal(t, 0, len(s.stack))
+
+	assert.Panics(t, func() { s.pop() })
+}
+
+func TestViewportClamp(t *testing.T) {
+	for _, test := range []struct {
+		rect, clamped Viewport
+	}{
+		{Viewport{10, 20, 30, 20}, Viewport{10, 20, 30, 20}},
+		{Viewport{0, 0, 100, 50}, Viewport{0, 0, 100, 50}},
+		{Viewport{-10, -5, 20, 20}, Viewport{0, 0, 10, 15}},
+		{Viewport{90, 40, 20, 20}, Viewport{90, 40, 10, 10}},
+		{Viewport{-10, -10, 200, 200}, Viewport{0, 0, 100, 50}},
+		// Fully outside or empty.
+		{Viewport{100, 0, 10, 10}, Viewport{}},
+		{Viewport{0, -10, 10, 10}, Viewport{}},
+		{Viewport{10, 10, 0, 10}, Viewport{}},
+		{Viewport{10, 10, -5, 10}, Viewport{}},
+	} {
+		assert.Equal(t, test.clamped, test.rect.Clamp(100, 50), "%v", test.rect)
+	}
+}
+
+func TestViewportProject(t *testing.T) {
+	v := Viewport{X: 100, Y: 50, Width: 200, Height: 100}
+	modelview := math.Ident4()
+	projection := math.Ortho(-1, 1, -1, 1, -1, 1)
+
+	// The center of the clip space is the center of the viewport.
+	win := v.Project(&math.Vec3{0, 0, 0}, &modelview, &projection)
+	assertVec3(t, &math.Vec3{200, 100, .5}, &win, 1e-3)
+
+	obj := v.UnProject(&math.Vec3{300, 150, .5}, &modelview, &projection)
+	assertVec3(t, &math.Vec3{1, 1, 0}, &obj, 1e-3)
+}
+
+func TestViewportInside(t *testing.T) {
+	assert.True(t, (&Viewport{0, 0, 800, 600}).inside(800, 600))
+	assert.True(t, (&Viewport{799, 599, 1, 1}).inside(800, 600))
+	assert.False(t, (&Viewport{799, 599, 2, 1}).inside(800, 600))
+	assert.False(t, (&Viewport{-1, 0, 10, 10}).inside(800, 600))
+	assert.False(t, (&Viewport{10, 10, 0, 10}).inside(800, 600))
+}
@@ -5,11 +5,15 @@ import (
 	"image"
 	"image/png"
 	"os"
+	"path/filepath"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.1/glfw"
 )
 
+// Window is a window drawing a scene. Sizes and mouse coordinates given to
+// scenes are in framebuffer pixels, which are smaller than screen coordinates
+// on high DPI displays, see PixelRatio.
 type Window struct {
 	app           *Application
 	name          string
@@ -17,35 +21,68 @@ type Window struct {
 	fb            Framebuffer
 	scene         Scener
 	glfwWindow    *glfw.Window
+	isolated      bool
+
+	// Frame statistics.
+	dt           float64
+	stats        statsCounter
+	statsVisible bool
+
+	hooks frameHooks
+
+	shaderHotReload bool
+
+	// Number of samples per pixel of the window framebuffer and whether
+	// it's an sRGB framebuffer.
+	samples int
+	srgb    bool
+	// Color space of the display, final adjustment of the frames and the
+	// pass applying them.
+	colorSpace ColorSpace
+	adjustment DisplayAdjustment
+	display    displayPass
+	// Effects applied to the frame drawn by the scene.
+	post *PostProcessor
+
+	// Input recording and replay.
+	recorder *InputRecorder
+	player   *InputPlayer
+
+	// Input given to the scene and whether it's shown on top of the
+	// window.
+	held         inputState
+	inputVisible bool
 }
 
-func newWindow(app *Application, name string, width, height int) *Window {
+// newWindow creates a window, sharing GL objects with share if not nil.
+func newWindow(app *Application, name string, width, height int, monitor *glfw.Monitor, share *glfw.Window) *Window {
 	window := new(Window)
 	window.app = app
 	window.name = name
 	window.width = width
 	window.height = height
+	window.adjustment = DefaultDisplayAdjustment()
 
 	glfw.WindowHint(glfw.ContextVersionMajor, 3)
 	glfw.WindowHint(glfw.ContextVersionMinor, 3)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 
-	glfwWindow, err := glfw.CreateWindow(width, height, name, nil, nil)
+	glfwWindow, err := glfw.CreateWindow(width, height, name, monitor, share)
 	if err != nil {
 		panic(err)
 	}
 	window.glfwWindow = glfwWindow
 	glfwWindow.MakeContextCurrent()
 
-	glfw.SwapInterval(1)
+	// The framebuffer is larger than the window on high DPI displays.
+	window.width, window.height = glfwWindow.GetFramebufferSize()
 
 	// create OnScreen object
-	window.fb = newOnScreen(width, height)
+	window.fb = newOnScreen(window.width, window.height)
 
 	// window events
-	glfwWindow.SetCloseCallback(onClose)
-	glfwWindow.SetSizeCallback(onResize)
+	glfwWindow.SetFramebufferSizeCallback(onResize)
 
 	// key events
 	glfwWindow.SetKeyCallback(onKeyEvent)
@@ -61,32 +98,290 @@ func newWindow(app *Application, name string, width, height int) *Window {
 	return window
 }
 
-func (w *Window) Update() {
-	sceneUpdate(w.scene, 0)
+// Update updates the window scene, dt seconds after the previous update.
+func (w *Window) Update(dt float64) {
+	w.dt = dt
+	w.hooks.beforeUpdate.call()
+	if w.recorder != nil {
+		w.recorder.advance(dt)
+	}
+	if w.player != nil {
+		w.player.update(dt, w.scene)
+	}
+	sceneUpdate(w.scene, dt)
+
+	// The scene camera only makes it to the framebuffer when first drawn.
+	camera := toScene(w.scene).camera
+	if camera == nil {
+		camera = w.fb.GetCamera()
+	}
+	updateCameraModifiers(camera, dt)
+	toScene(w.scene).updateFloatingOrigin(camera)
+}
+
+// clearScene clears the buffers of fb selected by the scene. The bars of
+// letterboxing cameras with their own bar color are cleared to that color and
+// the buffers only inside the letterbox.
+func clearScene(s Scener, fb Framebuffer, r *renderer) {
+	scene := toScene(s)
+	flags := scene.ClearFlags()
+
+	if l, ok := fb.GetCamera().(letterboxBars); ok {
+		if c, ok := l.bars(); ok {
+			gl.ClearColor(c.R, c.G, c.B, c.A)
+			gl.Clear(gl.COLOR_BUFFER_BIT)
+			box := l.letterbox()
+			fb.SetScissor(box.X, box.Y, box.Width, box.Height)
+			defer fb.DisableScissor()
+		}
+	}
+
+	var mask uint32
+	if flags&ClearColor != 0 {
+		c := s.BackgroundColor()
+		gl.ClearColor(c.R, c.G, c.B, c.A)
+		mask |= gl.COLOR_BUFFER_BIT
+	}
+	if flags&ClearDepth != 0 {
+		if depth, ok := scene.ClearDepth(); ok {
+			gl.ClearDepth(float64(depth))
+			// Restore the clear value of the depth range.
+			defer gl.ClearDepth(r.defaultClearDepth())
+		}
+		mask |= gl.DEPTH_BUFFER_BIT
+	}
+	if flags&ClearStencil != 0 {
+		gl.ClearStencil(int32(scene.ClearStencil()))
+		mask |= gl.STENCIL_BUFFER_BIT
+	}
+
+	if mask != 0 {
+		gl.Clear(mask)
+	}
 }
 
 func (w *Window) Draw() {
-	c := w.scene.BackgroundColor()
+	r := w.fb.render()
+	if w.shaderHotReload {
+		r.reloadShaders()
+	}
 
-	gl.ClearColor(c.R, c.G, c.B, c.A)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	// Minimized windows have an empty framebuffer.
+	adjusted := (w.colorSpace != ColorSpaceSRGB || !w.adjustment.isIdentity()) &&
+		w.width > 0 && w.height > 0
+	if adjusted {
+		w.display.begin(w.fb.(*onScreen), w.samples)
+	}
+	postProcessed := w.post.isActive() && w.width > 0 && w.height > 0
+	if postProcessed {
+		w.post.begin(w.fb.(*onScreen), w.samples)
+	}
+
+	// The depth clear value depends on the depth range.
+	r.setDepthState(w.fb)
+	clearScene(w.scene, w.fb, r)
+
+	r.counters.reset()
 	sceneDraw(w.scene, w.fb)
+	w.stats.addFrame(w.dt, &r.counters)
+
+	if postProcessed {
+		w.post.end(r, w.fb.(*onScreen))
+	}
+
+	// The depth of the scene stays in the target it was drawn into.
+	switch fb := w.fb.(*onScreen); {
+	case postProcessed:
+		fb.depth = w.post.scene
+	case adjusted:
+		fb.depth = w.display.target
+	default:
+		fb.depth = nil
+	}
+	r.drawDebug(w.fb, Debug)
+	if w.statsVisible {
+		r.drawStats(w.width, w.height, &w.stats.current)
+	}
+	if p := w.player; p != nil && p.playing && p.visible {
+		r.drawOverlay(w.width, w.height, p.overlay(w.height))
+	} else if w.inputVisible {
+		r.drawOverlay(w.width, w.height, inputOverlay(&w.held, w.height))
+	}
+	w.hooks.afterDraw.call()
+
+	if adjusted {
+		r.drawDisplay(&w.display, w.fb.(*onScreen), w.colorSpace, !w.srgb, &w.adjustment)
+	}
+	r.endFrame()
+}
+
+// swap presents the frame drawn by Draw.
+func (w *Window) swap() {
+	w.glfwWindow.SwapBuffers()
+	w.hooks.swap.call()
+}
+
+// OnBeforeUpdate registers f to be called at the start of each frame, before
+// the scene is updated.
+func (w *Window) OnBeforeUpdate(f func()) HookID {
+	return w.hooks.beforeUpdate.add(f)
+}
+
+// OnAfterDraw registers f to be called once the scene and the statistics
+// overlay have been drawn, before the frame is presented. f can draw on top of
+// the scene, eg. an external UI library.
+func (w *Window) OnAfterDraw(f func()) HookID {
+	return w.hooks.afterDraw.add(f)
+}
+
+// OnSwap registers f to be called right after the frame has been presented.
+func (w *Window) OnSwap(f func()) HookID {
+	return w.hooks.swap.add(f)
+}
+
+// OnClose registers f to be called when the window is closed, before its scene
+// is torn down, with the GL context of the window current. It's the place to
+// release GL objects created outside of the scene.
+func (w *Window) OnClose(f func()) HookID {
+	return w.hooks.close.add(f)
+}
+
+// RemoveHook removes a function registered with OnBeforeUpdate, OnAfterDraw,
+// OnSwap or OnClose.
+func (w *Window) RemoveHook(id HookID) {
+	w.hooks.remove(id)
+}
+
+// SetStatsVisible shows or hides an overlay with the frame statistics in the
+// top left corner of the window.
+func (w *Window) SetStatsVisible(visible bool) {
+	w.statsVisible = visible
+}
+
+// SetInputOverlayVisible shows or hides an overlay listing the keys and mouse
+// buttons held down, eg. "CTRL+A" or "SHIFT+LMB", in the bottom left corner of
+// the window. It's meant for recording demo videos or streaming. While an
+// InputPlayer is playing, its own overlay is shown instead.
+func (w *Window) SetInputOverlayVisible(visible bool) {
+	w.inputVisible = visible
+}
+
+// SetPostProcessor sets the effects applied to the frames drawn by the scene,
+// before the overlays are drawn and the frame is presented. nil, the default,
+// draws the scene directly in the window.
+func (w *Window) SetPostProcessor(p *PostProcessor) {
+	if w.post != nil && w.post != p {
+		w.makeCurrent()
+		w.post.release(&w.fb.render().garbage)
+	}
+	w.post = p
+}
+
+// PostProcessor returns the effects applied to the frames drawn by the scene.
+func (w *Window) PostProcessor() *PostProcessor {
+	return w.post
+}
+
+// SetShaderHotReload enables or disables shader hot reloading, a development
+// mode where material shaders created with NewFragmentShaderFromFile are
+// rebuilt at the start of the next frame when their file changes. Compilation
+// errors are printed and the last version of the shader that compiled is kept.
+func (w *Window) SetShaderHotReload(enabled bool) {
+	w.shaderHotReload = enabled
+}
+
+// SetDisplayAdjustment sets the final adjustment of the frames drawn in the
+// window. It can be changed at any time, taking effect on the next frame. The
+// adjustment is applied to the whole window, statistics overlay and drawing
+// done by OnAfterDraw functions included. With DefaultDisplayAdjustment, the
+// default, frames are drawn directly in the window.
+func (w *Window) SetDisplayAdjustment(a DisplayAdjustment) {
+	w.adjustment = a
+}
+
+// DisplayAdjustment returns the final adjustment of the frames drawn in the
+// window.
+func (w *Window) DisplayAdjustment() DisplayAdjustment {
+	return w.adjustment
+}
+
+// SetOutputColorSpace tags the display showing the window with its color
+// space. Frames are converted from sRGB to cs by the final pass, so colors
+// look the same on wide gamut displays as on sRGB ones. GLFW doesn't expose
+// the color profile of monitors: applications know it from their
+// configuration, eg. a table of the monitors of an installation, and tag each
+// window. The default is ColorSpaceSRGB.
+func (w *Window) SetOutputColorSpace(cs ColorSpace) {
+	w.colorSpace = cs
+}
+
+// OutputColorSpace returns the color space of the display showing the window.
+func (w *Window) OutputColorSpace() ColorSpace {
+	return w.colorSpace
+}
+
+// Stats returns the latest frame statistics. They are collected whether the
+// overlay is visible or not.
+func (w *Window) Stats() Stats {
+	return w.stats.current
 }
 
+// PixelRatio returns the number of framebuffer pixels per screen coordinate,
+// eg. 2 on most high DPI displays.
+func (w *Window) PixelRatio() float32 {
+	width, _ := w.glfwWindow.GetSize()
+	if width == 0 {
+		return 1
+	}
+	return float32(w.width) / float32(width)
+}
+
+// toPixels converts screen coordinates to framebuffer pixels.
+func (w *Window) toPixels(x, y float64) (float32, float32) {
+	ratio := w.PixelRatio()
+	return float32(x) * ratio, float32(y) * ratio
+}
+
+// Close closes the window at the end of the current frame.
 func (w *Window) Close() {
 	w.glfwWindow.SetShouldClose(true)
 }
 
+// makeCurrent makes the GL context of the window current.
+func (w *Window) makeCurrent() {
+	if glfw.GetCurrentContext() != w.glfwWindow {
+		w.glfwWindow.MakeContextCurrent()
+	}
+}
+
+// destroy releases the window, in this order: the OnClose hooks are called,
+// the scene is torn down, the GL objects of the window are deleted and the
+// GLFW window is destroyed.
+func (w *Window) destroy() {
+	w.makeCurrent()
+	w.hooks.close.call()
+	sceneTearDown(w.scene)
+
+	r := w.fb.render()
+	w.display.release(&r.garbage)
+	if w.post != nil {
+		w.post.release(&r.garbage)
+	}
+	r.release()
+
+	w.glfwWindow.Destroy()
+}
+
 func onResize(w *glfw.Window, width, height int) {
 	window := getWindow(w)
 	window.width = width
 	window.height = height
-	window.scene.OnResize(window.fb, width, height)
+	sceneResize(window.scene, window.fb, width, height)
 }
 
-func onClose(w *glfw.Window) {
-	window := getWindow(w)
-	window.scene.TearDown()
+// capture saves the frame drawn by Draw into dir, see EngineOptions.Capture.
+func (w *Window) capture(dir string, frame int) error {
+	return w.saveScreenshot(filepath.Join(dir, fmt.Sprintf("%s - %04d.png", w.name, frame)))
 }
 
 func (w *Window) doScreenshot() {
@@ -122,36 +417,91 @@ func onKeyEvent(w *glfw.Window, key glfw.Key, scancode int,
 			window.doScreenshot()
 		}
 
-		window.scene.OnKeyPressed()
+		window.input(InputEvent{
+			Kind:     InputKeyPressed,
+			Key:      Key(key),
+			Scancode: scancode,
+			Mods:     ModifierKey(mods),
+		})
 	} else if action == glfw.Release {
-		window.scene.OnKeyReleased()
+		window.input(InputEvent{
+			Kind:     InputKeyReleased,
+			Key:      Key(key),
+			Scancode: scancode,
+			Mods:     ModifierKey(mods),
+		})
 	}
 }
 
+// IsKeyPressed returns true if key is currently held down.
+func (w *Window) IsKeyPressed(key Key) bool {
+	return w.glfwWindow.GetKey(glfw.Key(key)) == glfw.Press
+}
+
 func onMouseMoved(w *glfw.Window, x, y float64) {
 	window := getWindow(w)
-	window.scene.OnMouseMoved(float32(x), float32(y))
+	px, py := window.toPixels(x, y)
+	window.input(InputEvent{Kind: InputMouseMoved, X: px, Y: py})
 }
 
 func onMouseButton(w *glfw.Window, button glfw.MouseButton,
 	action glfw.Action, mod glfw.ModifierKey) {
 	window := getWindow(w)
-	x, y := w.GetCursorPos()
+	x, y := window.toPixels(w.GetCursorPos())
 	if action == glfw.Press {
-		window.scene.OnMouseButtonPressed(MouseButton(button), float32(x), float32(y))
+		window.input(InputEvent{Kind: InputMouseButtonPressed, Button: MouseButton(button), X: x, Y: y})
 	} else if action == glfw.Release {
-		window.scene.OnMouseButtonReleased(MouseButton(button), float32(x), float32(y))
+		window.input(InputEvent{Kind: InputMouseButtonReleased, Button: MouseButton(button), X: x, Y: y})
 	}
 }
 
 func onRuneEvent(w *glfw.Window, r rune) {
 	window := getWindow(w)
-	window.scene.OnRuneEntered(r)
+	window.input(InputEvent{Kind: InputRuneEntered, Rune: r})
+}
+
+// input gives e to the scene, recording it if an InputRecorder is recording.
+// The user input is ignored while an InputPlayer is playing.
+func (w *Window) input(e InputEvent) {
+	if w.player != nil && w.player.playing {
+		return
+	}
+	if w.recorder != nil {
+		w.recorder.record(e)
+	}
+	w.held.track(&e)
+	e.dispatch(w.scene)
 }
 
+// RecordInput records the input events of the window with r, while r is
+// recording. A nil r removes the recorder of the window.
+//
+//	recorder := dax.NewInputRecorder()
+//	window.RecordInput(recorder)
+//	recorder.Start()
+//	...
+//	recording := recorder.Stop()
+func (w *Window) RecordInput(r *InputRecorder) {
+	w.recorder = r
+}
+
+// PlayInput replays the recording of p in the window while p is playing, eg.
+// for built-in tutorials or automated demos. The user input is ignored during
+// the replay. A nil p removes the player of the window.
+//
+//	player := dax.NewInputPlayer(recording)
+//	window.PlayInput(player)
+//	player.Play()
+func (w *Window) PlayInput(p *InputPlayer) {
+	w.player = p
+}
+
+// SetScene sets the scene drawn in the window, tearing down the previous one.
 func (w *Window) SetScene(s Scener) {
+	w.makeCurrent()
+
 	if w.scene != nil {
-		w.scene.TearDown()
+		sceneTearDown(w.scene)
 	}
 
 	if s != nil {
@@ -162,24 +512,96 @@ func (w *Window) SetScene(s Scener) {
 		w.scene = new(Scene)
 	}
 	sceneSetup(w.scene, w.fb)
-	w.scene.OnResize(w.fb, w.width, w.height)
+	sceneResize(w.scene, w.fb, w.width, w.height)
+}
+
+// sceneFrame draws a frame of a scene, clearing the framebuffer first.
+type sceneFrame struct {
+	scene Scener
+}
+
+func (f *sceneFrame) Draw(fb Framebuffer) {
+	r := fb.render()
+	r.setDepthState(fb)
+	clearScene(f.scene, fb, r)
+	sceneDraw(f.scene, fb)
+}
+
+// RenderScene sets s up in an OffScreen framebuffer of size width x height,
+// updates it once, draws a frame of it and tears it down. It returns the frame
+// with its top row first, as images are usually stored. The window GL context
+// is used but nothing is drawn in the window. It's meant for tests, see the
+// daxtest package.
+func (w *Window) RenderScene(s Scener, width, height int) *image.RGBA {
+	w.makeCurrent()
+
+	fb := NewOffScreen(w.fb, width, height)
+	defer fb.Destroy()
+
+	sceneSetup(s, fb)
+	defer sceneTearDown(s)
+	sceneResize(s, fb, width, height)
+	sceneUpdate(s, 0)
+
+	fb.Draw(&sceneFrame{scene: s})
+	fb.render().endFrame()
+
+	img := fb.Screenshot()
+	flipRows(img)
+	return img
+}
+
+// flipRows flips img upside down, GL framebuffers having their first row at
+// the bottom.
+func flipRows(img *image.RGBA) {
+	height := img.Rect.Dy()
+	row := make([]byte, img.Stride)
+	for y := 0; y < height/2; y++ {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(height-1-y)*img.Stride : (height-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
 }
 
 func (w *Window) Screenshot() *image.RGBA {
+	w.makeCurrent()
 	return w.fb.Screenshot()
 }
 
+// ScreenshotAsync is Screenshot without stalling the rendering loop waiting
+// for the GPU: done is called with the image a frame or two later.
+func (w *Window) ScreenshotAsync(done func(img *image.RGBA)) {
+	w.makeCurrent()
+	width, height := w.fb.Size()
+	w.fb.ReadPixelsAsync(Viewport{0, 0, width, height}, func(pixels []byte) {
+		done(&image.RGBA{
+			Pix:    pixels,
+			Stride: width * 4,
+			Rect:   image.Rect(0, 0, width, height),
+		})
+	})
+}
+
 func (w *Window) ScreenshotToFile(filename string) {
-	img := w.fb.Screenshot()
+	if err := w.saveScreenshot(filename); err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// saveScreenshot saves the content of the window as a PNG file.
+func (w *Window) saveScreenshot(filename string) error {
+	img := w.Screenshot()
 
 	file, err := os.Create(filename)
 	if err != nil {
-		fmt.Println(err.Error())
+		return err
 	}
-	defer file.Close()
 
-	err = png.Encode(file, img)
-	if err != nil {
-		fmt.Println(err.Error())
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
 	}
+	return file.Close()
 }